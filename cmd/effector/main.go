@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/bluesky-social/go-util/pkg/telemetry"
 	"github.com/bluesky-social/osprey-atproto/effector"
@@ -33,24 +35,88 @@ func main() {
 				Value:   "osprey-effector-consumers",
 			},
 			&cli.StringFlag{
-				Name:     "ozone-proxy-did",
-				Required: true,
-				EnvVars:  []string{"OSPREY_OZONE_PROXY_DID"},
+				Name:    "ozone-proxy-did",
+				EnvVars: []string{"OSPREY_OZONE_PROXY_DID"},
 			},
 			&cli.StringFlag{
-				Name:     "ozone-pds-host",
-				Required: true,
-				EnvVars:  []string{"OSPREY_OZONE_PDS_HOST"},
+				Name:    "ozone-pds-host",
+				EnvVars: []string{"OSPREY_OZONE_PDS_HOST"},
 			},
 			&cli.StringFlag{
-				Name:     "ozone-identifier",
-				Required: true,
-				EnvVars:  []string{"OSPREY_OZONE_IDENTIFIER"},
+				Name:    "ozone-identifier",
+				EnvVars: []string{"OSPREY_OZONE_IDENTIFIER"},
 			},
 			&cli.StringFlag{
-				Name:     "ozone-password",
-				Required: true,
-				EnvVars:  []string{"OSPREY_OZONE_PASSWORD"},
+				Name:    "ozone-password",
+				EnvVars: []string{"OSPREY_OZONE_PASSWORD"},
+			},
+			&cli.Float64Flag{
+				Name:    "ozone-rate-limit",
+				Usage:   "Events sent to Ozone per second. Events beyond that wait, takedowns first and comments last. 0 disables the limit.",
+				EnvVars: []string{"OSPREY_OZONE_RATE_LIMIT"},
+				Value:   10,
+			},
+			&cli.IntFlag{
+				Name:    "ozone-rate-burst",
+				EnvVars: []string{"OSPREY_OZONE_RATE_BURST"},
+				Value:   20,
+			},
+			&cli.StringFlag{
+				Name:    "ozone-session-store",
+				Usage:   "Where to persist the Ozone session across restarts, so deploys don't log in again: file, memcache, or postgres (using --memcached-servers or --postgres-url). Disabled if unset.",
+				EnvVars: []string{"OSPREY_OZONE_SESSION_STORE"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-session-path",
+				Usage:   "File to persist the Ozone session in, for --ozone-session-store=file.",
+				EnvVars: []string{"OSPREY_OZONE_SESSION_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-auth",
+				Usage:   "How to authenticate to the labeler's PDS: password, or oauth (authorize first with the ozone-oauth-login command, and requires --ozone-session-store).",
+				EnvVars: []string{"OSPREY_OZONE_AUTH"},
+				Value:   effector.OzoneAuthPassword,
+			},
+			&cli.StringFlag{
+				Name:    "ozone-oauth-client-id",
+				Usage:   "URL of the OAuth client metadata document. A localhost development client is used if unset.",
+				EnvVars: []string{"OSPREY_OZONE_OAUTH_CLIENT_ID"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-oauth-callback-url",
+				Usage:   "OAuth redirect URL, served by the ozone-oauth-login command.",
+				EnvVars: []string{"OSPREY_OZONE_OAUTH_CALLBACK_URL"},
+				Value:   "http://127.0.0.1:8091/oauth/callback",
+			},
+			&cli.StringSliceFlag{
+				Name:    "ozone-oauth-scopes",
+				Usage:   "OAuth scopes to request for the Ozone service account.",
+				EnvVars: []string{"OSPREY_OZONE_OAUTH_SCOPES"},
+				Value:   cli.NewStringSlice("atproto", "transition:generic"),
+			},
+			&cli.StringFlag{
+				Name:    "ozone-oauth-client-secret-key",
+				Usage:   "Multibase private key for a confidential OAuth client, whose public key is in the client metadata.",
+				EnvVars: []string{"OSPREY_OZONE_OAUTH_CLIENT_SECRET_KEY"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-oauth-client-key-id",
+				Usage:   "Key ID of --ozone-oauth-client-secret-key in the client metadata.",
+				EnvVars: []string{"OSPREY_OZONE_OAUTH_CLIENT_KEY_ID"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-standby-identifier",
+				Usage:   "Second account on the labeler that events fail over to when the primary account is rate limited or can't log in. Disabled if unset.",
+				EnvVars: []string{"OSPREY_OZONE_STANDBY_IDENTIFIER"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-standby-password",
+				EnvVars: []string{"OSPREY_OZONE_STANDBY_PASSWORD"},
+			},
+			&cli.StringFlag{
+				Name:    "ozone-standby-pds-host",
+				Usage:   "PDS of the standby account. Defaults to --ozone-pds-host.",
+				EnvVars: []string{"OSPREY_OZONE_STANDBY_PDS_HOST"},
 			},
 			&cli.StringFlag{
 				Name:    "bigquery-credentials-json",
@@ -64,6 +130,24 @@ func main() {
 				Name:    "bigquery-dataset-id",
 				EnvVars: []string{"OSPREY_BIGQUERY_DATASET_ID"},
 			},
+			&cli.StringFlag{
+				Name:    "bigquery-flag-record-table",
+				Usage:   "BigQuery table that flags on records are inserted into.",
+				EnvVars: []string{"OSPREY_BIGQUERY_FLAG_RECORD_TABLE"},
+				Value:   "tagged_posts",
+			},
+			&cli.StringFlag{
+				Name:    "bigquery-flag-actor-table",
+				Usage:   "BigQuery table that flags on accounts are inserted into.",
+				EnvVars: []string{"OSPREY_BIGQUERY_FLAG_ACTOR_TABLE"},
+				Value:   "tagged_actors",
+			},
+			&cli.IntFlag{
+				Name:    "bigquery-flag-batch-size",
+				Usage:   "How many BigQuery flags are inserted at once. Partial batches are sent every ten seconds.",
+				EnvVars: []string{"OSPREY_BIGQUERY_FLAG_BATCH_SIZE"},
+				Value:   25,
+			},
 			&cli.StringFlag{
 				Name:    "environment",
 				Usage:   "Values other than `production` do not take actions in Ozone.",
@@ -74,33 +158,312 @@ func main() {
 				Name:    "slack-webhook-url",
 				EnvVars: []string{"OSPREY_SLACK_WEBHOOK_URL"},
 			},
+			&cli.StringFlag{
+				Name:    "slack-routes-path",
+				Usage:   "JSON file routing the effects of some rules or kinds to other Slack webhooks, as a list of {\"rules\", \"kinds\", \"webhook_url\", \"digest\"}.",
+				EnvVars: []string{"OSPREY_SLACK_ROUTES_PATH"},
+			},
+			&cli.DurationFlag{
+				Name:    "slack-batch-window",
+				Usage:   "After an effect is posted to Slack, further effects of the same rules are summarized in one message after this long.",
+				EnvVars: []string{"OSPREY_SLACK_BATCH_WINDOW"},
+				Value:   time.Minute,
+			},
+			&cli.DurationFlag{
+				Name:    "slack-digest-interval",
+				Usage:   "How often Slack routes in digest mode are sent a summary.",
+				EnvVars: []string{"OSPREY_SLACK_DIGEST_INTERVAL"},
+				Value:   15 * time.Minute,
+			},
+			&cli.StringFlag{
+				Name:    "discord-webhook-url",
+				EnvVars: []string{"OSPREY_DISCORD_WEBHOOK_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "comment-templates-path",
+				Usage:   "JSON file of Go templates for the comments on Ozone events, by effect kind, with \"default\" for kinds not listed.",
+				EnvVars: []string{"OSPREY_COMMENT_TEMPLATES_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "sentry-dsn",
+				Usage:   "Sentry DSN that effects which failed to apply are reported to, tagged with --environment.",
+				EnvVars: []string{"OSPREY_SENTRY_DSN"},
+			},
+			&cli.StringFlag{
+				Name:    "webhook-url",
+				Usage:   "HTTPS endpoint that signed batches of effects are posted to.",
+				EnvVars: []string{"OSPREY_WEBHOOK_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "webhook-secret",
+				Usage:   "Shared secret the webhook requests are signed with.",
+				EnvVars: []string{"OSPREY_WEBHOOK_SECRET"},
+			},
+			&cli.BoolFlag{
+				Name:    "webhook-include-events",
+				Usage:   "Also post every event to the webhook, not just the effects taken on them.",
+				EnvVars: []string{"OSPREY_WEBHOOK_INCLUDE_EVENTS"},
+			},
+			&cli.StringFlag{
+				Name:    "log-postgres-url",
+				Usage:   "Logs events and effects to Postgres, for deployments without BigQuery.",
+				EnvVars: []string{"OSPREY_LOG_POSTGRES_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "opensearch-url",
+				Usage:   "OpenSearch or Elasticsearch cluster that effects are indexed into.",
+				EnvVars: []string{"OSPREY_OPENSEARCH_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "opensearch-username",
+				EnvVars: []string{"OSPREY_OPENSEARCH_USERNAME"},
+			},
+			&cli.StringFlag{
+				Name:    "opensearch-password",
+				EnvVars: []string{"OSPREY_OPENSEARCH_PASSWORD"},
+			},
+			&cli.StringFlag{
+				Name:    "opensearch-index-prefix",
+				Usage:   "Prefix of the daily effect and event indices.",
+				EnvVars: []string{"OSPREY_OPENSEARCH_INDEX_PREFIX"},
+				Value:   "osprey",
+			},
+			&cli.BoolFlag{
+				Name:    "opensearch-include-events",
+				Usage:   "Also index every event, not just the effects taken on them.",
+				EnvVars: []string{"OSPREY_OPENSEARCH_INCLUDE_EVENTS"},
+			},
+			&cli.StringFlag{
+				Name:    "outcomes-topic",
+				Usage:   "Kafka topic that the outcome of each event sent to Ozone is produced to.",
+				EnvVars: []string{"OSPREY_OUTCOMES_TOPIC"},
+			},
+			&cli.StringFlag{
+				Name:    "label-expiry-topic",
+				Usage:   "Kafka topic that temporary labels are produced to as Osprey input when they expire, so rules can step up. Usually Osprey's input topic.",
+				EnvVars: []string{"OSPREY_LABEL_EXPIRY_TOPIC"},
+			},
+			&cli.StringFlag{
+				Name:    "label-expiry-snapshot-path",
+				Usage:   "File that temporary labels waiting to expire are kept in across restarts.",
+				EnvVars: []string{"OSPREY_LABEL_EXPIRY_SNAPSHOT_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "delayed-effects-snapshot-path",
+				Usage:   "File that delayed effects waiting to be applied, including the removal of expiring tags, are kept in across restarts.",
+				EnvVars: []string{"OSPREY_DELAYED_EFFECTS_SNAPSHOT_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "action-store",
+				Usage:   "Where taken actions are recorded to avoid repeating them: `memcache` or `postgres`.",
+				EnvVars: []string{"OSPREY_ACTION_STORE"},
+				Value:   effector.ActionStoreMemcache,
+			},
 			&cli.StringSliceFlag{
-				Name:     "memcached-servers",
-				EnvVars:  []string{"OSPREY_MEMCACHED_SERVERS"},
-				Required: true,
+				Name:    "memcached-servers",
+				Usage:   "Required for the memcache action store.",
+				EnvVars: []string{"OSPREY_MEMCACHED_SERVERS"},
+			},
+			&cli.BoolFlag{
+				Name:    "memcache-migrate-legacy-keys",
+				Usage:   "Honor actions recorded in memcache before keys included the effect kind and value, so upgrading doesn't repeat them.",
+				EnvVars: []string{"OSPREY_MEMCACHE_MIGRATE_LEGACY_KEYS"},
+				Value:   true,
+			},
+			&cli.DurationFlag{
+				Name:    "action-default-ttl",
+				Usage:   "How long actions without an expiration are remembered, after which rules may take them again. 0 remembers them forever.",
+				EnvVars: []string{"OSPREY_ACTION_DEFAULT_TTL"},
+			},
+			&cli.StringFlag{
+				Name:    "postgres-url",
+				Usage:   "Required for the postgres action store.",
+				EnvVars: []string{"OSPREY_POSTGRES_URL"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "allowed-effects",
+				Usage:   "Restricts the effect kinds that are executed. Everything is allowed when unset.",
+				EnvVars: []string{"OSPREY_ALLOWED_EFFECTS"},
 			},
+			&cli.StringFlag{
+				Name:    "rule-modes-path",
+				Usage:   "JSON file mapping rule names to \"enforce\", \"shadow\", or a rollout percentage like \"10%\". Shadowed effects are logged but not applied.",
+				EnvVars: []string{"OSPREY_RULE_MODES_PATH"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "test-subject-dids",
+				Usage:   "Accounts whose effects are applied even outside of production, for testing rules end to end.",
+				EnvVars: []string{"OSPREY_TEST_SUBJECT_DIDS"},
+			},
+			&cli.BoolFlag{
+				Name:    "check-subject-status",
+				Usage:   "Look up subjects in Ozone before labeling or taking them down, and skip actions that are already in place.",
+				EnvVars: []string{"OSPREY_CHECK_SUBJECT_STATUS"},
+			},
+			&cli.StringFlag{
+				Name:    "admin-listen-addr",
+				Usage:   "Address to serve the admin API on, e.g. :8081, for pausing consumption and killing effects at runtime. The API is disabled if unset.",
+				EnvVars: []string{"OSPREY_ADMIN_LISTEN_ADDR"},
+			},
+			&cli.StringFlag{
+				Name:    "admin-token",
+				Usage:   "Bearer token required by the admin API.",
+				EnvVars: []string{"OSPREY_ADMIN_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:    "history-token",
+				Usage:   "Bearer token accepted by the action history API, which is served with the admin API, in addition to the admin token.",
+				EnvVars: []string{"OSPREY_HISTORY_TOKEN"},
+			},
+			&cli.IntFlag{
+				Name:    "recent-actions-size",
+				Usage:   "How many of the last events handled, and their effects, are kept for the admin API's /admin/recent. 0 keeps none.",
+				EnvVars: []string{"OSPREY_RECENT_ACTIONS_SIZE"},
+				Value:   1000,
+			},
+			&cli.IntFlag{
+				Name:    "workers",
+				Usage:   "Number of events handled concurrently.",
+				EnvVars: []string{"OSPREY_WORKERS"},
+				Value:   100,
+			},
+			&cli.IntFlag{
+				Name:    "worker-queue-size",
+				Usage:   "Events that can wait on each worker before consuming is held up.",
+				EnvVars: []string{"OSPREY_WORKER_QUEUE_SIZE"},
+				Value:   100,
+			},
+			&cli.IntFlag{
+				Name:    "retry-max-attempts",
+				Usage:   "Attempts at a failed effect before it's dead-lettered. 0 disables retries.",
+				EnvVars: []string{"OSPREY_RETRY_MAX_ATTEMPTS"},
+				Value:   5,
+			},
+			&cli.DurationFlag{
+				Name:    "retry-base-delay",
+				Usage:   "Delay before the first retry of a failed effect, doubling with each attempt.",
+				EnvVars: []string{"OSPREY_RETRY_BASE_DELAY"},
+				Value:   30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:    "retry-max-delay",
+				EnvVars: []string{"OSPREY_RETRY_MAX_DELAY"},
+				Value:   10 * time.Minute,
+			},
+			&cli.DurationFlag{
+				Name:    "shutdown-timeout",
+				Usage:   "How long events being handled are given to finish when shutting down.",
+				EnvVars: []string{"OSPREY_SHUTDOWN_TIMEOUT"},
+				Value:   30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:    "backlog-alert-threshold",
+				Usage:   "Alert when events are handled longer than this after Osprey sent them. 0 disables the alert.",
+				EnvVars: []string{"OSPREY_BACKLOG_ALERT_THRESHOLD"},
+				Value:   15 * time.Minute,
+			},
+			&cli.Float64Flag{
+				Name:    "rule-guard-multiple",
+				Usage:   "Pause a rule's effects and alert when it acts at more than this many times its rate over the last hour. 0 disables the guard.",
+				EnvVars: []string{"OSPREY_RULE_GUARD_MULTIPLE"},
+			},
+			&cli.IntFlag{
+				Name:    "rule-guard-min-effects",
+				Usage:   "Fewest effects a rule has to cause in five minutes for the rule guard to pause it.",
+				EnvVars: []string{"OSPREY_RULE_GUARD_MIN_EFFECTS"},
+				Value:   100,
+			},
+		},
+		Commands: []*cli.Command{
+			replayCommand,
+			reverseCommand,
+			ozoneOAuthLoginCommand,
 		},
 		Action: func(cmd *cli.Context) error {
 			ctx := context.Background()
 
+			// Checked here rather than marked as required, since most subcommands don't talk to Ozone.
+			required := []string{"ozone-proxy-did", "ozone-pds-host", "ozone-identifier"}
+			if cmd.String("ozone-auth") == effector.OzoneAuthPassword {
+				required = append(required, "ozone-password")
+			}
+			for _, name := range required {
+				if cmd.String(name) == "" {
+					return fmt.Errorf("required flag %q not set", name)
+				}
+			}
+
 			logger := telemetry.StartLogger(cmd)
 			telemetry.StartMetrics(cmd)
 
 			effector, err := effector.New(&effector.Args{
-				BootstrapServers:        cmd.StringSlice("bootstrap-servers"),
-				InputTopic:              cmd.String("input-topic"),
-				ConsumerGroup:           cmd.String("consumer-group"),
-				BigQueryCredentialsJson: []byte(cmd.String("bigquery-credentials-json")),
-				BigQueryProjectID:       cmd.String("bigquery-project-id"),
-				BigQueryDatasetID:       cmd.String("bigquery-dataset-id"),
-				OzonePdsHost:            cmd.String("ozone-pds-host"),
-				OzoneIdentifier:         cmd.String("ozone-identifier"),
-				OzonePassword:           cmd.String("ozone-password"),
-				OzoneProxyDid:           cmd.String("ozone-proxy-did"),
-				IsProduction:            cmd.String("environment") == "production",
-				SlackWebhookURL:         cmd.String("slack-webhook-url"),
-				MemcacheServers:         cmd.StringSlice("memcached-servers"),
-				Logger:                  logger,
+				BootstrapServers:           cmd.StringSlice("bootstrap-servers"),
+				InputTopic:                 cmd.String("input-topic"),
+				ConsumerGroup:              cmd.String("consumer-group"),
+				BigQueryCredentialsJson:    []byte(cmd.String("bigquery-credentials-json")),
+				BigQueryProjectID:          cmd.String("bigquery-project-id"),
+				BigQueryDatasetID:          cmd.String("bigquery-dataset-id"),
+				BigQueryFlagRecordTable:    cmd.String("bigquery-flag-record-table"),
+				BigQueryFlagActorTable:     cmd.String("bigquery-flag-actor-table"),
+				BigQueryFlagBatchSize:      cmd.Int("bigquery-flag-batch-size"),
+				OzonePdsHost:               cmd.String("ozone-pds-host"),
+				OzoneIdentifier:            cmd.String("ozone-identifier"),
+				OzonePassword:              cmd.String("ozone-password"),
+				OzoneProxyDid:              cmd.String("ozone-proxy-did"),
+				OzoneRateLimit:             cmd.Float64("ozone-rate-limit"),
+				OzoneRateBurst:             cmd.Int("ozone-rate-burst"),
+				OzoneSessionStore:          cmd.String("ozone-session-store"),
+				OzoneSessionPath:           cmd.String("ozone-session-path"),
+				OzoneAuth:                  cmd.String("ozone-auth"),
+				OzoneOAuth:                 ozoneOAuthArgs(cmd),
+				OzoneStandbyPdsHost:        cmd.String("ozone-standby-pds-host"),
+				OzoneStandbyIdentifier:     cmd.String("ozone-standby-identifier"),
+				OzoneStandbyPassword:       cmd.String("ozone-standby-password"),
+				IsProduction:               cmd.String("environment") == "production",
+				SlackWebhookURL:            cmd.String("slack-webhook-url"),
+				SlackRoutesPath:            cmd.String("slack-routes-path"),
+				SlackBatchWindow:           cmd.Duration("slack-batch-window"),
+				SlackDigestInterval:        cmd.Duration("slack-digest-interval"),
+				DiscordWebhookURL:          cmd.String("discord-webhook-url"),
+				SentryDSN:                  cmd.String("sentry-dsn"),
+				Environment:                cmd.String("environment"),
+				CommentTemplatesPath:       cmd.String("comment-templates-path"),
+				WebhookURL:                 cmd.String("webhook-url"),
+				WebhookSecret:              cmd.String("webhook-secret"),
+				WebhookIncludeEvents:       cmd.Bool("webhook-include-events"),
+				LogPostgresURL:             cmd.String("log-postgres-url"),
+				OpenSearchURL:              cmd.String("opensearch-url"),
+				OpenSearchUsername:         cmd.String("opensearch-username"),
+				OpenSearchPassword:         cmd.String("opensearch-password"),
+				OpenSearchIndexPrefix:      cmd.String("opensearch-index-prefix"),
+				OpenSearchIncludeEvents:    cmd.Bool("opensearch-include-events"),
+				OutcomesTopic:              cmd.String("outcomes-topic"),
+				LabelExpiryTopic:           cmd.String("label-expiry-topic"),
+				LabelExpirySnapshotPath:    cmd.String("label-expiry-snapshot-path"),
+				DelayedEffectsSnapshotPath: cmd.String("delayed-effects-snapshot-path"),
+				ActionStore:                cmd.String("action-store"),
+				MemcacheServers:            cmd.StringSlice("memcached-servers"),
+				MemcacheMigrateLegacyKeys:  cmd.Bool("memcache-migrate-legacy-keys"),
+				PostgresURL:                cmd.String("postgres-url"),
+				ActionDefaultTTL:           cmd.Duration("action-default-ttl"),
+				AllowedEffects:             cmd.StringSlice("allowed-effects"),
+				RuleModesPath:              cmd.String("rule-modes-path"),
+				TestSubjectDids:            cmd.StringSlice("test-subject-dids"),
+				CheckSubjectStatus:         cmd.Bool("check-subject-status"),
+				AdminListenAddr:            cmd.String("admin-listen-addr"),
+				AdminToken:                 cmd.String("admin-token"),
+				HistoryToken:               cmd.String("history-token"),
+				RecentActionsSize:          cmd.Int("recent-actions-size"),
+				Workers:                    cmd.Int("workers"),
+				WorkerQueueSize:            cmd.Int("worker-queue-size"),
+				RetryMaxAttempts:           cmd.Int("retry-max-attempts"),
+				RetryBaseDelay:             cmd.Duration("retry-base-delay"),
+				RetryMaxDelay:              cmd.Duration("retry-max-delay"),
+				ShutdownTimeout:            cmd.Duration("shutdown-timeout"),
+				BacklogAlertThreshold:      cmd.Duration("backlog-alert-threshold"),
+				RuleGuardMultiple:          cmd.Float64("rule-guard-multiple"),
+				RuleGuardMinEffects:        cmd.Int("rule-guard-min-effects"),
+				Logger:                     logger,
 			})
 			if err != nil {
 				return err
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/bluesky-social/go-util/pkg/telemetry"
+	"github.com/bluesky-social/osprey-atproto/effector"
+	"github.com/urfave/cli/v2"
+)
+
+var ozoneOAuthLoginCommand = &cli.Command{
+	Name:  "ozone-oauth-login",
+	Usage: "Authorize the effector to act as the Ozone service account with OAuth",
+	Description: "Prints a URL to approve the authorization at while signed in as the service account, and " +
+		"serves --ozone-oauth-callback-url until it's approved. The session is stored in --ozone-session-store, " +
+		"where the effector resumes it from when run with --ozone-auth=oauth.",
+	Action: func(cmd *cli.Context) error {
+		logger := telemetry.StartLogger(cmd)
+
+		if cmd.String("ozone-identifier") == "" {
+			return errors.New("required flag \"ozone-identifier\" not set")
+		}
+
+		sessions, err := effector.NewSessionStore(cmd.Context, cmd.String("ozone-session-store"), cmd.String("ozone-session-path"),
+			cmd.StringSlice("memcached-servers"), cmd.String("postgres-url"))
+		if err != nil {
+			return err
+		}
+		if sessions == nil {
+			return errors.New("must supply --ozone-session-store to keep the oauth session in")
+		}
+		defer sessions.Close()
+
+		return effector.OzoneOAuthLogin(cmd.Context, &effector.OzoneOAuthLoginArgs{
+			OAuth:        ozoneOAuthArgs(cmd),
+			SessionStore: sessions,
+			Identifier:   cmd.String("ozone-identifier"),
+			Logger:       logger,
+		})
+	},
+}
+
+func ozoneOAuthArgs(cmd *cli.Context) *effector.OzoneOAuthArgs {
+	return &effector.OzoneOAuthArgs{
+		ClientID:        cmd.String("ozone-oauth-client-id"),
+		CallbackURL:     cmd.String("ozone-oauth-callback-url"),
+		Scopes:          cmd.StringSlice("ozone-oauth-scopes"),
+		ClientSecretKey: cmd.String("ozone-oauth-client-secret-key"),
+		ClientKeyID:     cmd.String("ozone-oauth-client-key-id"),
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/telemetry"
+	"github.com/bluesky-social/osprey-atproto/effector"
+	"github.com/urfave/cli/v2"
+)
+
+var replayCommand = &cli.Command{
+	Name:  "replay",
+	Usage: "Produce events from the BigQuery event log back onto the input topic",
+	Description: "Events are replayed in the order they were logged. Effects that were already taken are " +
+		"still skipped by the action store, so replaying is safe after an outage but won't repeat effects " +
+		"that succeeded.",
+	Flags: []cli.Flag{
+		&cli.TimestampFlag{
+			Name:     "start",
+			Usage:    "Replay events logged at or after this time, e.g. 2025-10-01T12:00:00Z",
+			Layout:   time.RFC3339,
+			Required: true,
+		},
+		&cli.TimestampFlag{
+			Name:   "end",
+			Usage:  "Replay events logged before this time. Defaults to now",
+			Layout: time.RFC3339,
+		},
+		&cli.StringFlag{
+			Name:  "rule",
+			Usage: "Only replay events with an effect caused by this rule",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Maximum number of events to replay. 0 replays everything in the range",
+			Value: 10000,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Log the events that would be replayed without producing them",
+		},
+	},
+	Action: func(cmd *cli.Context) error {
+		logger := telemetry.StartLogger(cmd)
+
+		end := time.Now()
+		if t := cmd.Timestamp("end"); t != nil {
+			end = *t
+		}
+
+		replayed, err := effector.Replay(cmd.Context, &effector.ReplayArgs{
+			BigQueryCredentialsJson: []byte(cmd.String("bigquery-credentials-json")),
+			BigQueryProjectID:       cmd.String("bigquery-project-id"),
+			BigQueryDatasetID:       cmd.String("bigquery-dataset-id"),
+			BootstrapServers:        cmd.StringSlice("bootstrap-servers"),
+			InputTopic:              cmd.String("input-topic"),
+			Start:                   *cmd.Timestamp("start"),
+			End:                     end,
+			Rule:                    cmd.String("rule"),
+			Limit:                   cmd.Int("limit"),
+			DryRun:                  cmd.Bool("dry-run"),
+			Logger:                  logger,
+		})
+		if err != nil {
+			return fmt.Errorf("replayed %d events before failing: %w", replayed, err)
+		}
+
+		if cmd.Bool("dry-run") {
+			fmt.Fprintf(os.Stderr, "would have replayed %d events to %s\n", replayed, cmd.String("input-topic"))
+		} else {
+			fmt.Fprintf(os.Stderr, "replayed %d events to %s\n", replayed, cmd.String("input-topic"))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/telemetry"
+	"github.com/bluesky-social/osprey-atproto/effector"
+	"github.com/urfave/cli/v2"
+)
+
+var reverseCommand = &cli.Command{
+	Name:  "reverse",
+	Usage: "Undo the takedowns, labels and tags a rule applied, from the BigQuery effect log",
+	Description: "Each subject is looked up in Ozone and only what's still in place is reversed, including " +
+		"effects that other rules or moderators applied as well, so check a dry run first. The action store " +
+		"isn't cleared, so the rule won't re-apply reversed effects until their keys expire.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "rule",
+			Usage:    "Rule whose effects are reversed",
+			Required: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start",
+			Usage:    "Reverse effects logged at or after this time, e.g. 2025-10-01T12:00:00Z",
+			Layout:   time.RFC3339,
+			Required: true,
+		},
+		&cli.TimestampFlag{
+			Name:   "end",
+			Usage:  "Reverse effects logged before this time. Defaults to now",
+			Layout: time.RFC3339,
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Maximum number of effects to reverse. 0 reverses everything in the range",
+			Value: 10000,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Log the effects that would be reversed without reversing them",
+		},
+	},
+	Action: func(cmd *cli.Context) error {
+		logger := telemetry.StartLogger(cmd)
+
+		required := []string{"ozone-proxy-did", "ozone-pds-host", "ozone-identifier"}
+		if cmd.String("ozone-auth") == effector.OzoneAuthPassword {
+			required = append(required, "ozone-password")
+		}
+		for _, name := range required {
+			if cmd.String(name) == "" {
+				return fmt.Errorf("required flag %q not set", name)
+			}
+		}
+
+		sessions, err := effector.NewSessionStore(cmd.Context, cmd.String("ozone-session-store"), cmd.String("ozone-session-path"),
+			cmd.StringSlice("memcached-servers"), cmd.String("postgres-url"))
+		if err != nil {
+			return err
+		}
+
+		var oauthArgs *effector.OzoneOAuthArgs
+		if cmd.String("ozone-auth") == effector.OzoneAuthOAuth {
+			oauthArgs = ozoneOAuthArgs(cmd)
+		}
+
+		end := time.Now()
+		if t := cmd.Timestamp("end"); t != nil {
+			end = *t
+		}
+
+		reversed, err := effector.Reverse(cmd.Context, &effector.ReverseArgs{
+			BigQueryCredentialsJson: []byte(cmd.String("bigquery-credentials-json")),
+			BigQueryProjectID:       cmd.String("bigquery-project-id"),
+			BigQueryDatasetID:       cmd.String("bigquery-dataset-id"),
+			Ozone: &effector.OzoneClientArgs{
+				PdsHost:         cmd.String("ozone-pds-host"),
+				Identifier:      cmd.String("ozone-identifier"),
+				Password:        cmd.String("ozone-password"),
+				ProxyDid:        cmd.String("ozone-proxy-did"),
+				IsProduction:    cmd.String("environment") == "production",
+				TestSubjectDids: cmd.StringSlice("test-subject-dids"),
+				RateLimit:       cmd.Float64("ozone-rate-limit"),
+				RateBurst:       cmd.Int("ozone-rate-burst"),
+				SessionStore:    sessions,
+				OAuth:           oauthArgs,
+			},
+			Rule:   cmd.String("rule"),
+			Start:  *cmd.Timestamp("start"),
+			End:    end,
+			Limit:  cmd.Int("limit"),
+			DryRun: cmd.Bool("dry-run"),
+			Logger: logger,
+		})
+		if err != nil {
+			return fmt.Errorf("reversed %d effects before failing: %w", reversed, err)
+		}
+
+		if cmd.Bool("dry-run") {
+			fmt.Fprintf(os.Stderr, "would have reversed %d effects of %s\n", reversed, cmd.String("rule"))
+		} else {
+			fmt.Fprintf(os.Stderr, "reversed %d effects of %s\n", reversed, cmd.String("rule"))
+		}
+		return nil
+	},
+}
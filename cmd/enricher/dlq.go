@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bluesky-social/osprey-atproto/enricher/dlq"
+	enricher "github.com/bluesky-social/osprey-atproto/enricher/server"
+	"github.com/urfave/cli/v2"
+)
+
+var dlqCommand = &cli.Command{
+	Name:  "dlq",
+	Usage: "Inspect and requeue events in the enricher's dead letter queue",
+	Description: "Events land in the dead letter queue when the enricher fails to handle them. The queue " +
+		"holds the original event only, so look up the handler error in the logs around the entry's timestamp.",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List the events in the dead letter queue, one JSON object per line",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:  "limit",
+					Usage: "Maximum number of entries to list. 0 lists everything",
+					Value: 100,
+				},
+			},
+			Action: func(cmd *cli.Context) error {
+				client, err := newDLQClient(cmd)
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+
+				entries, err := client.List(cmd.Context, cmd.Int("limit"))
+				if err != nil {
+					return fmt.Errorf("failed to list %s: %w", client.Topic(), err)
+				}
+
+				enc := json.NewEncoder(os.Stdout)
+				for _, e := range entries {
+					if err := enc.Encode(summarizeEntry(e)); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "show",
+			Usage: "Show a single event from the dead letter queue, including its record",
+			Flags: entryFlags(),
+			Action: func(cmd *cli.Context) error {
+				client, err := newDLQClient(cmd)
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+
+				entry, err := client.Get(cmd.Context, int32(cmd.Int("partition")), cmd.Int64("offset"))
+				if err != nil {
+					return err
+				}
+
+				out := struct {
+					entrySummary
+					Record json.RawMessage `json:"record,omitempty"`
+				}{entrySummary: summarizeEntry(entry)}
+				if entry.Event != nil && entry.Event.Commit != nil && json.Valid(entry.Event.Commit.Record) {
+					out.Record = entry.Event.Commit.Record
+				}
+
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			},
+		},
+		{
+			Name:  "requeue",
+			Usage: "Produce events from the dead letter queue back onto the input topic",
+			Flags: append(entryFlags(),
+				&cli.BoolFlag{
+					Name:  "all",
+					Usage: "Requeue every entry currently in the queue instead of a single one",
+				},
+			),
+			Action: func(cmd *cli.Context) error {
+				client, err := newDLQClient(cmd)
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+
+				var entries []*dlq.Entry
+				if cmd.Bool("all") {
+					entries, err = client.List(cmd.Context, 0)
+					if err != nil {
+						return fmt.Errorf("failed to list %s: %w", client.Topic(), err)
+					}
+				} else {
+					if !cmd.IsSet("partition") || !cmd.IsSet("offset") {
+						return fmt.Errorf("either --partition and --offset or --all is required")
+					}
+					entry, err := client.Get(cmd.Context, int32(cmd.Int("partition")), cmd.Int64("offset"))
+					if err != nil {
+						return err
+					}
+					entries = []*dlq.Entry{entry}
+				}
+
+				if err := client.Requeue(cmd.Context, entries); err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "requeued %d entries to %s\n", len(entries), cmd.String("input-topic"))
+				return nil
+			},
+		},
+	},
+}
+
+func entryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "partition",
+			Usage: "Partition of the entry",
+		},
+		&cli.Int64Flag{
+			Name:  "offset",
+			Usage: "Offset of the entry",
+		},
+	}
+}
+
+func newDLQClient(cmd *cli.Context) (*dlq.Client, error) {
+	client, err := dlq.NewClient(&dlq.ClientArgs{
+		BootstrapServers: cmd.StringSlice("bootstrap-servers"),
+		SASLUsername:     cmd.String("sasl-username"),
+		SASLPassword:     cmd.String("sasl-password"),
+		InputTopic:       cmd.String("input-topic"),
+		ConsumerGroup:    enricher.ConsumerGroup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DLQ client: %w", err)
+	}
+	return client, nil
+}
+
+type entrySummary struct {
+	Partition   int32     `json:"partition"`
+	Offset      int64     `json:"offset"`
+	Timestamp   time.Time `json:"timestamp"`
+	Key         string    `json:"key"`
+	Did         string    `json:"did,omitempty"`
+	Kind        string    `json:"kind,omitempty"`
+	Collection  string    `json:"collection,omitempty"`
+	Rkey        string    `json:"rkey,omitempty"`
+	Operation   string    `json:"operation,omitempty"`
+	Cid         string    `json:"cid,omitempty"`
+	DecodeError string    `json:"decode_error,omitempty"`
+}
+
+func summarizeEntry(e *dlq.Entry) entrySummary {
+	s := entrySummary{
+		Partition: e.Partition,
+		Offset:    e.Offset,
+		Timestamp: e.Timestamp,
+		Key:       e.Key,
+	}
+	if e.DecodeErr != nil {
+		s.DecodeError = e.DecodeErr.Error()
+	}
+	if e.Event != nil {
+		s.Did = e.Event.Did
+		s.Kind = e.Event.Kind.String()
+		if e.Event.Commit != nil {
+			s.Collection = e.Event.Commit.Collection
+			s.Rkey = e.Event.Commit.Rkey
+			s.Operation = e.Event.Commit.Operation.String()
+			s.Cid = e.Event.Commit.Cid
+		}
+	}
+	return s
+}
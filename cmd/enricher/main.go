@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/bluesky-social/go-util/pkg/telemetry"
 	enricher "github.com/bluesky-social/osprey-atproto/enricher/server"
@@ -43,9 +45,9 @@ func main() {
 				Usage:   "SASL password for Kafka authentication",
 				EnvVars: []string{"SASL_PASSWORD"},
 			},
-			&cli.StringFlag{
+			&cli.StringSliceFlag{
 				Name:    "image-cdn-url",
-				Usage:   "URL for the CDN",
+				Usage:   "URLs for the CDN, tried in order. If all of them fail, images are fetched from the owning PDS when a PLC host is set",
 				EnvVars: []string{"IMAGE_CDN_URL"},
 			},
 			&cli.StringFlag{
@@ -128,11 +130,196 @@ func main() {
 				Usage:   "Minimum hamming distance for flagged image matches",
 				EnvVars: []string{"FLAGGED_IMAGE_MIN_DISTANCE"},
 			},
+			&cli.DurationFlag{
+				Name:    "dedupe-window",
+				Usage:   "How long to remember handled events so duplicates (same DID, CID and operation) are dropped. 0 disables deduplication",
+				Value:   5 * time.Minute,
+				EnvVars: []string{"DEDUPE_WINDOW"},
+			},
+			&cli.DurationFlag{
+				Name:    "drain-timeout",
+				Usage:   "How long to wait on shutdown for in-flight events to finish and be produced before closing the producer",
+				Value:   30 * time.Second,
+				EnvVars: []string{"DRAIN_TIMEOUT"},
+			},
+			&cli.DurationFlag{
+				Name:    "stall-timeout",
+				Usage:   "Alert if no events have been produced for this long, which usually means the Kafka client has wedged. 0 disables the watchdog",
+				Value:   10 * time.Minute,
+				EnvVars: []string{"STALL_TIMEOUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "stall-exit",
+				Usage:   "Shut down with an error once stalled, so the process can be restarted",
+				EnvVars: []string{"STALL_EXIT"},
+			},
+			&cli.Float64Flag{
+				Name:    "event-log-sample-rate",
+				Usage:   "Fraction of events that log in full (e.g. 0.01 for 1%). The rest only log at --unsampled-log-level and above",
+				Value:   1,
+				EnvVars: []string{"EVENT_LOG_SAMPLE_RATE"},
+			},
+			&cli.StringFlag{
+				Name:    "unsampled-log-level",
+				Usage:   "Minimum level logged for events that aren't sampled by --event-log-sample-rate",
+				Value:   "warn",
+				EnvVars: []string{"UNSAMPLED_LOG_LEVEL"},
+			},
+			&cli.IntFlag{
+				Name:    "max-blob-size",
+				Usage:   "Maximum image size in bytes to send to third parties. Larger images are downscaled or skipped. 0 disables the limit",
+				Value:   0,
+				EnvVars: []string{"MAX_BLOB_SIZE"},
+			},
+			&cli.IntFlag{
+				Name:    "animated-frame-samples",
+				Usage:   "Number of frames to sample from animated GIFs. Each sampled frame is sent to every image processor, so paid API usage scales with this. 0 processes only the first frame",
+				Value:   5,
+				EnvVars: []string{"ANIMATED_FRAME_SAMPLES"},
+			},
+			&cli.BoolFlag{
+				Name:    "sniff-blobs",
+				Usage:   "Sniff the leading bytes of every blob from its PDS and route it by its actual content type rather than its declared mimetype. Adds a rate limited PDS request per blob and requires --plc-host",
+				EnvVars: []string{"SNIFF_BLOBS"},
+			},
+			&cli.IntFlag{
+				Name:    "max-output-size",
+				Usage:   "Maximum serialized output event size in bytes. Raw third-party responses are trimmed from larger events",
+				Value:   enricher.DefaultMaxOutputSize,
+				EnvVars: []string{"MAX_OUTPUT_SIZE"},
+			},
+			&cli.Float64Flag{
+				Name:    "hive-price-per-call",
+				Usage:   "Price of a single Hive call, used for estimated cost metrics",
+				EnvVars: []string{"HIVE_PRICE_PER_CALL"},
+			},
+			&cli.Float64Flag{
+				Name:    "abyss-price-per-call",
+				Usage:   "Price of a single Abyss (PhotoDNA) call, used for estimated cost metrics",
+				EnvVars: []string{"ABYSS_PRICE_PER_CALL"},
+			},
+			&cli.IntFlag{
+				Name:    "hive-daily-budget",
+				Usage:   "Maximum Hive calls per day, spread evenly across the day with up to an hour's worth available at once. 0 is unlimited",
+				EnvVars: []string{"HIVE_DAILY_BUDGET"},
+			},
+			&cli.StringFlag{
+				Name:    "hive-budget-policy",
+				Usage:   "What to do with an image once the Hive budget is exhausted: \"queue\" waits for budget up to the dispatch timeout, \"skip\" skips Hive, \"sample\" sends --hive-budget-sample-rate of images anyway",
+				Value:   enricher.BudgetSkip,
+				EnvVars: []string{"HIVE_BUDGET_POLICY"},
+			},
+			&cli.Float64Flag{
+				Name:    "hive-budget-sample-rate",
+				Usage:   "Fraction of images sent to Hive over budget with the \"sample\" policy (e.g. 0.01 for 1%)",
+				EnvVars: []string{"HIVE_BUDGET_SAMPLE_RATE"},
+			},
+			&cli.Float64Flag{
+				Name:    "prescreen-qa-sample-rate",
+				Usage:   "Fraction of images marked sfw by prescreen that are still sent to Hive for QA (e.g. 0.005 for 0.5%)",
+				EnvVars: []string{"PRESCREEN_QA_SAMPLE_RATE"},
+			},
+			&cli.StringFlag{
+				Name:    "prescreen-failure-policy",
+				Usage:   "What to do with an image when the prescreen call fails: \"closed\" skips Hive, \"open\" sends it to Hive anyway",
+				Value:   enricher.PrescreenFailClosed,
+				EnvVars: []string{"PRESCREEN_FAILURE_POLICY"},
+			},
+			&cli.BoolFlag{
+				Name:    "velocity-enabled",
+				Usage:   "Track per-DID sliding window velocity features and attach them to enriched events",
+				EnvVars: []string{"VELOCITY_ENABLED"},
+			},
+			&cli.StringFlag{
+				Name:    "velocity-snapshot-path",
+				Usage:   "File to periodically snapshot velocity state to and restore it from on startup",
+				EnvVars: []string{"VELOCITY_SNAPSHOT_PATH"},
+			},
+			&cli.BoolFlag{
+				Name:    "campaign-index-enabled",
+				Usage:   "Track how often each image's PDQ hash has been seen recently and by how many accounts, and attach it to image results. Requires --retina-hash-url",
+				EnvVars: []string{"CAMPAIGN_INDEX_ENABLED"},
+			},
+			&cli.DurationFlag{
+				Name:    "campaign-window",
+				Usage:   "How far back the campaign index counts image sightings",
+				Value:   24 * time.Hour,
+				EnvVars: []string{"CAMPAIGN_WINDOW"},
+			},
+			&cli.StringFlag{
+				Name:    "campaign-snapshot-path",
+				Usage:   "File to periodically snapshot the campaign index to and restore it from on startup",
+				EnvVars: []string{"CAMPAIGN_SNAPSHOT_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "term-list-source",
+				Usage:   "File path or http(s) URL of the JSON keyword/regex term lists, reloaded on change",
+				EnvVars: []string{"TERM_LIST_SOURCE"},
+			},
+			&cli.StringFlag{
+				Name:    "protected-accounts-path",
+				Usage:   "JSON file of protected accounts to check handles and display names for impersonation against",
+				EnvVars: []string{"PROTECTED_ACCOUNTS_PATH"},
+			},
+			&cli.Float64Flag{
+				Name:    "impersonation-min-score",
+				Usage:   "Minimum similarity, from 0 to 1, for an impersonation match to be reported",
+				Value:   0.85,
+				EnvVars: []string{"IMPERSONATION_MIN_SCORE"},
+			},
+			&cli.StringFlag{
+				Name:    "suspicious-hosts-path",
+				Usage:   "File of newline separated PDS hosts to flag as suspicious, including their subdomains",
+				EnvVars: []string{"SUSPICIOUS_HOSTS_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "pds-snapshot-path",
+				Usage:   "File to periodically snapshot PDS first-seen times to and restore them from on startup",
+				EnvVars: []string{"PDS_SNAPSHOT_PATH"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "watchlist-uris",
+				Usage:   "AT-URIs of moderation lists to check authors for membership in. Requires an Appview host",
+				EnvVars: []string{"WATCHLIST_URIS"},
+			},
+			&cli.StringFlag{
+				Name:    "labeler-host",
+				Usage:   "Labeler whose label stream to follow so events can include labels already applied to their subject, e.g. https://mod.bsky.app",
+				EnvVars: []string{"LABELER_HOST"},
+			},
+			&cli.StringFlag{
+				Name:    "api-listen-addr",
+				Usage:   "Address to serve the enricher's HTTP API on, e.g. :8080. The API is disabled if unset",
+				EnvVars: []string{"API_LISTEN_ADDR"},
+			},
+			&cli.StringFlag{
+				Name:    "report-webhook-secret",
+				Usage:   "Bearer token required on report intake webhooks. The webhook is disabled if unset",
+				EnvVars: []string{"REPORT_WEBHOOK_SECRET"},
+			},
+			&cli.StringFlag{
+				Name:    "scan-api-token",
+				Usage:   "Bearer token required on on-demand scan requests. The scan endpoint is disabled if unset",
+				EnvVars: []string{"SCAN_API_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:    "admin-api-token",
+				Usage:   "Bearer token required on admin API requests, such as toggling processors. The admin API is disabled if unset",
+				EnvVars: []string{"ADMIN_API_TOKEN"},
+			},
+		},
+		Commands: []*cli.Command{
+			dlqCommand,
 		},
 		Action: func(cmd *cli.Context) error {
 			ctx := context.Background()
 
 			logger := telemetry.StartLogger(cmd)
+
+			var unsampledLogLevel slog.Level
+			if err := unsampledLogLevel.UnmarshalText([]byte(cmd.String("unsampled-log-level"))); err != nil {
+				return fmt.Errorf("invalid unsampled log level: %w", err)
+			}
 			telemetry.StartMetrics(cmd)
 
 			args := enricher.Args{
@@ -141,7 +328,7 @@ func main() {
 				SASLPassword:            cmd.String("sasl-password"),
 				InputTopic:              cmd.String("input-topic"),
 				OutputTopic:             cmd.String("output-topic"),
-				ImageCdnURL:             cmd.String("image-cdn-url"),
+				ImageCdnURLs:            cmd.StringSlice("image-cdn-url"),
 				AbyssURL:                cmd.String("abyss-url"),
 				AbyssAdminPassword:      cmd.String("abyss-admin-password"),
 				HiveAPIToken:            cmd.String("hive-api-token"),
@@ -158,6 +345,39 @@ func main() {
 				NciiMinDistance:         cmd.Float64("ncii-min-distance"),
 				FlaggedImageCollection:  cmd.String("flagged-image-collection"),
 				FlaggedImageMinDistance: cmd.Float64("flagged-image-min-distance"),
+				DedupeWindow:            cmd.Duration("dedupe-window"),
+				DrainTimeout:            cmd.Duration("drain-timeout"),
+				StallTimeout:            cmd.Duration("stall-timeout"),
+				StallExit:               cmd.Bool("stall-exit"),
+				EventLogSampleRate:      cmd.Float64("event-log-sample-rate"),
+				UnsampledLogLevel:       unsampledLogLevel,
+				MaxBlobSize:             cmd.Int("max-blob-size"),
+				AnimatedFrameSamples:    cmd.Int("animated-frame-samples"),
+				SniffBlobs:              cmd.Bool("sniff-blobs"),
+				MaxOutputSize:           cmd.Int("max-output-size"),
+				HivePricePerCall:        cmd.Float64("hive-price-per-call"),
+				HiveDailyBudget:         cmd.Int("hive-daily-budget"),
+				HiveBudgetPolicy:        cmd.String("hive-budget-policy"),
+				HiveBudgetSampleRate:    cmd.Float64("hive-budget-sample-rate"),
+				AbyssPricePerCall:       cmd.Float64("abyss-price-per-call"),
+				PrescreenQASampleRate:   cmd.Float64("prescreen-qa-sample-rate"),
+				PrescreenFailurePolicy:  cmd.String("prescreen-failure-policy"),
+				VelocityEnabled:         cmd.Bool("velocity-enabled"),
+				VelocitySnapshotPath:    cmd.String("velocity-snapshot-path"),
+				CampaignIndexEnabled:    cmd.Bool("campaign-index-enabled"),
+				CampaignWindow:          cmd.Duration("campaign-window"),
+				CampaignSnapshotPath:    cmd.String("campaign-snapshot-path"),
+				TermListSource:          cmd.String("term-list-source"),
+				ProtectedAccountsPath:   cmd.String("protected-accounts-path"),
+				ImpersonationMinScore:   cmd.Float64("impersonation-min-score"),
+				SuspiciousHostsPath:     cmd.String("suspicious-hosts-path"),
+				PDSSnapshotPath:         cmd.String("pds-snapshot-path"),
+				WatchlistURIs:           cmd.StringSlice("watchlist-uris"),
+				LabelerHost:             cmd.String("labeler-host"),
+				APIListenAddr:           cmd.String("api-listen-addr"),
+				ReportWebhookSecret:     cmd.String("report-webhook-secret"),
+				ScanAPIToken:            cmd.String("scan-api-token"),
+				AdminAPIToken:           cmd.String("admin-api-token"),
 				Logger:                  logger,
 			}
 
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/bluesky-social/osprey-atproto/retina"
 	_ "github.com/joho/godotenv/autoload"
@@ -19,6 +20,11 @@ func main() {
 				EnvVars: []string{"RETINA_API_LISTEN_ADDR"},
 				Value:   ":8080",
 			},
+			&cli.StringFlag{
+				Name:    "grpc-listen-addr",
+				Usage:   "Listen address for the gRPC API, which is disabled when empty",
+				EnvVars: []string{"RETINA_GRPC_LISTEN_ADDR"},
+			},
 			&cli.StringFlag{
 				Name:    "metrics-addr",
 				EnvVars: []string{"RETINA_METRICS_ADDR"},
@@ -39,14 +45,220 @@ func main() {
 				EnvVars: []string{"RETINA_PDQ_PATH"},
 				Value:   "/usr/bin/pdq-photo-hasher",
 			},
+			&cli.BoolFlag{
+				Name:    "pdq-exec",
+				Usage:   "Hash images with the pdq-photo-hasher binary at pdq-path instead of in process",
+				EnvVars: []string{"RETINA_PDQ_EXEC"},
+				Value:   false,
+			},
+			&cli.StringSliceFlag{
+				Name:    "api-keys",
+				Usage:   "Keys the API accepts as bearer tokens, as name:key so that metrics can tell them apart. The API is open without any",
+				EnvVars: []string{"RETINA_API_KEYS"},
+			},
+			&cli.StringFlag{
+				Name:    "ffmpeg-path",
+				Usage:   "Path to the ffmpeg binary video frames are extracted with",
+				EnvVars: []string{"RETINA_FFMPEG_PATH"},
+				Value:   "ffmpeg",
+			},
+			&cli.DurationFlag{
+				Name:    "video-frame-interval",
+				Usage:   "How far apart the video frames that are hashed are",
+				EnvVars: []string{"RETINA_VIDEO_FRAME_INTERVAL"},
+				Value:   time.Second,
+			},
+			&cli.IntFlag{
+				Name:    "video-max-frames",
+				Usage:   "Most frames hashed per video",
+				EnvVars: []string{"RETINA_VIDEO_MAX_FRAMES"},
+				Value:   60,
+			},
+			&cli.Int64Flag{
+				Name:    "max-concurrent-video-hashes",
+				Usage:   "Number of videos that can be hashed in parallel",
+				EnvVars: []string{"RETINA_MAX_CONCURRENT_VIDEO_HASHES"},
+				Value:   2,
+			},
+			&cli.BoolFlag{
+				Name:    "ocr-in-process",
+				Usage:   "Run tesseract through libtesseract instead of exec'ing it per image. Needs a build with the tesseract build tag",
+				EnvVars: []string{"RETINA_OCR_IN_PROCESS"},
+				Value:   false,
+			},
+			&cli.StringSliceFlag{
+				Name:    "ocr-languages",
+				Usage:   "Tesseract languages to recognize when a request doesn't ask for any, e.g. eng",
+				EnvVars: []string{"RETINA_OCR_LANGUAGES"},
+			},
+			&cli.IntFlag{
+				Name:    "ocr-page-seg-mode",
+				Usage:   "Tesseract page segmentation mode, or tesseract's default when 0",
+				EnvVars: []string{"RETINA_OCR_PAGE_SEG_MODE"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "ocr-preprocess",
+				Usage:   "Preprocessing steps run on images before OCR when a request doesn't ask for any: upscale, grayscale, deskew, threshold",
+				EnvVars: []string{"RETINA_OCR_PREPROCESS"},
+			},
+			&cli.IntFlag{
+				Name:    "ocr-dpi",
+				Usage:   "Resolution tesseract assumes images are at, or tesseract's guess when 0",
+				EnvVars: []string{"RETINA_OCR_DPI"},
+			},
+			&cli.StringFlag{
+				Name:    "cdn-host",
+				Usage:   "CDN images are fetched from by DID and CID",
+				EnvVars: []string{"RETINA_CDN_HOST"},
+				Value:   retina.DefaultCdnHost,
+			},
+			&cli.StringSliceFlag{
+				Name:    "cdn-allowed-hosts",
+				Usage:   "Other CDN hosts requests can ask for images to be fetched from, e.g. mirrors or staging CDNs",
+				EnvVars: []string{"RETINA_CDN_ALLOWED_HOSTS"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "job-callback-allowed-hosts",
+				Usage:   "Hosts, with their port if it isn't the default, that jobs can send their results to with callbackUrl. Jobs can't ask for callbacks when unset.",
+				EnvVars: []string{"RETINA_JOB_CALLBACK_ALLOWED_HOSTS"},
+			},
+			&cli.StringFlag{
+				Name:    "classifier-model-path",
+				Usage:   "ONNX image classifier model, e.g. an NSFW model, served at /api/classify. Needs a build with the onnx build tag. Disabled if unset.",
+				EnvVars: []string{"RETINA_CLASSIFIER_MODEL_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    "onnxruntime-library-path",
+				Usage:   "Path to the onnxruntime shared library",
+				EnvVars: []string{"RETINA_ONNXRUNTIME_LIBRARY_PATH"},
+				Value:   "libonnxruntime.so",
+			},
+			&cli.StringSliceFlag{
+				Name:    "classifier-labels",
+				Usage:   "Labels of the classifier model's classes, in the order of its outputs",
+				EnvVars: []string{"RETINA_CLASSIFIER_LABELS"},
+			},
+			&cli.IntFlag{
+				Name:    "classifier-input-size",
+				Usage:   "Width and height images are resized to for the classifier model",
+				EnvVars: []string{"RETINA_CLASSIFIER_INPUT_SIZE"},
+				Value:   224,
+			},
+			&cli.StringFlag{
+				Name:    "classifier-input-layout",
+				Usage:   "How the classifier model takes its input: nchw or nhwc",
+				EnvVars: []string{"RETINA_CLASSIFIER_INPUT_LAYOUT"},
+				Value:   retina.ClassifierLayoutNCHW,
+			},
+			&cli.Float64SliceFlag{
+				Name:    "classifier-mean",
+				Usage:   "Mean of each RGB channel subtracted for the classifier model, after scaling to [0, 1]",
+				EnvVars: []string{"RETINA_CLASSIFIER_MEAN"},
+			},
+			&cli.Float64SliceFlag{
+				Name:    "classifier-std",
+				Usage:   "Std each RGB channel is divided by for the classifier model, after subtracting the mean",
+				EnvVars: []string{"RETINA_CLASSIFIER_STD"},
+			},
+			&cli.BoolFlag{
+				Name:    "classifier-softmax",
+				Usage:   "Apply softmax to the classifier model's outputs, for models that output logits",
+				EnvVars: []string{"RETINA_CLASSIFIER_SOFTMAX"},
+			},
+			&cli.Int64Flag{
+				Name:    "max-concurrent-classifications",
+				Usage:   "Number of images the classifier model can run on in parallel",
+				EnvVars: []string{"RETINA_MAX_CONCURRENT_CLASSIFICATIONS"},
+				Value:   2,
+			},
+			&cli.IntFlag{
+				Name:    "cache-size",
+				Usage:   "Number of OCR and PDQ results kept in memory by image CID, or 0 to not cache them in memory",
+				EnvVars: []string{"RETINA_CACHE_SIZE"},
+				Value:   100_000,
+			},
+			&cli.DurationFlag{
+				Name:    "cache-ttl",
+				Usage:   "How long OCR and PDQ results are cached for",
+				EnvVars: []string{"RETINA_CACHE_TTL"},
+				Value:   24 * time.Hour,
+			},
+			&cli.StringSliceFlag{
+				Name:    "cache-memcached-servers",
+				Usage:   "Memcached servers to share cached results between replicas through, if set",
+				EnvVars: []string{"RETINA_CACHE_MEMCACHED_SERVERS"},
+			},
+			&cli.Int64Flag{
+				Name:    "max-upload-size",
+				Usage:   "Most bytes a request body, or an image or blob downloaded for one, can be",
+				EnvVars: []string{"RETINA_MAX_UPLOAD_SIZE"},
+				Value:   retina.DefaultMaxUploadSize,
+			},
+			&cli.IntFlag{
+				Name:    "max-image-dimension",
+				Usage:   "Most pixels wide or tall an image can be",
+				EnvVars: []string{"RETINA_MAX_IMAGE_DIMENSION"},
+				Value:   retina.DefaultMaxImageDimension,
+			},
+			&cli.Int64Flag{
+				Name:    "max-image-pixels",
+				Usage:   "Most pixels an image can have in all",
+				EnvVars: []string{"RETINA_MAX_IMAGE_PIXELS"},
+				Value:   retina.DefaultMaxImagePixels,
+			},
 		},
 		Action: func(cmd *cli.Context) error {
+			apiKeys, err := retina.ParseAPIKeys(cmd.StringSlice("api-keys"))
+			if err != nil {
+				return err
+			}
+
 			r, err := retina.New(&retina.Args{
 				APIListenAddr:         cmd.String("api-listen-addr"),
 				MetricsAddr:           cmd.String("metrics-addr"),
+				GRPCListenAddr:        cmd.String("grpc-listen-addr"),
 				Debug:                 cmd.Bool("debug"),
 				MaxConcurrentOCRExecs: cmd.Int64("max-concurrent-ocr-execs"),
 				PdqPath:               cmd.String("pdq-path"),
+				PdqExec:               cmd.Bool("pdq-exec"),
+				APIKeys:               apiKeys,
+				CdnHost:               cmd.String("cdn-host"),
+				AllowedCdnHosts:       cmd.StringSlice("cdn-allowed-hosts"),
+				AllowedCallbackHosts:  cmd.StringSlice("job-callback-allowed-hosts"),
+				Video: retina.VideoArgs{
+					FfmpegPath:    cmd.String("ffmpeg-path"),
+					FrameInterval: cmd.Duration("video-frame-interval"),
+					MaxFrames:     cmd.Int("video-max-frames"),
+					MaxConcurrent: cmd.Int64("max-concurrent-video-hashes"),
+				},
+				OCR: retina.OCRArgs{
+					InProcess:   cmd.Bool("ocr-in-process"),
+					Languages:   cmd.StringSlice("ocr-languages"),
+					PageSegMode: cmd.Int("ocr-page-seg-mode"),
+					DPI:         cmd.Int("ocr-dpi"),
+					Preprocess:  cmd.StringSlice("ocr-preprocess"),
+				},
+				Classifier: retina.ClassifierArgs{
+					ModelPath:     cmd.String("classifier-model-path"),
+					LibraryPath:   cmd.String("onnxruntime-library-path"),
+					Labels:        cmd.StringSlice("classifier-labels"),
+					InputSize:     cmd.Int("classifier-input-size"),
+					Layout:        cmd.String("classifier-input-layout"),
+					Mean:          cmd.Float64Slice("classifier-mean"),
+					Std:           cmd.Float64Slice("classifier-std"),
+					Softmax:       cmd.Bool("classifier-softmax"),
+					MaxConcurrent: cmd.Int64("max-concurrent-classifications"),
+				},
+				Cache: retina.CacheArgs{
+					Size:            cmd.Int("cache-size"),
+					TTL:             cmd.Duration("cache-ttl"),
+					MemcacheServers: cmd.StringSlice("cache-memcached-servers"),
+				},
+				Limits: retina.LimitArgs{
+					MaxUploadSize:     cmd.Int64("max-upload-size"),
+					MaxImageDimension: cmd.Int("max-image-dimension"),
+					MaxImagePixels:    cmd.Int64("max-image-pixels"),
+				},
 			})
 			if err != nil {
 				return err
@@ -0,0 +1,329 @@
+package effector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Backends for recording which actions have been taken.
+const (
+	ActionStoreMemcache = "memcache"
+	ActionStorePostgres = "postgres"
+)
+
+// ActionKey identifies an action taken on a subject by a set of rules. Actions with the same key
+// are only taken once.
+type ActionKey struct {
+	Subject string
+	// Action is what was done, e.g. "label:spam" or "-label:spam" for its negation. See actionName.
+	Action string
+	Rules  string
+	// ExpirationInHours is part of the key so that rules can step up durations for behavior that
+	// persists.
+	ExpirationInHours *int64
+}
+
+func (k ActionKey) String() string {
+	key := fmt.Sprintf("%s-%s-%s", k.Subject, k.Action, k.Rules)
+	if k.ExpirationInHours != nil {
+		key = fmt.Sprintf("%s-dur-%d", key, *k.ExpirationInHours)
+	}
+	return key
+}
+
+// actionName names an effect for deduplication. Reversals are prefixed with "-", so that taking an
+// action can invalidate its reversal and vice versa.
+func actionName(kind, value string, reverse bool) string {
+	name := kind
+	if value != "" {
+		name = fmt.Sprintf("%s:%s", kind, value)
+	}
+	if reverse {
+		name = "-" + name
+	}
+	return name
+}
+
+// oppositeAction returns the action that undoes the given one.
+func oppositeAction(action string) string {
+	if action != "" && action[0] == '-' {
+		return action[1:]
+	}
+	return "-" + action
+}
+
+// ActionStore remembers which actions have already been taken.
+type ActionStore interface {
+	// MarkActioned records the action and reports whether it was newly recorded. A previously
+	// recorded action that has expired counts as new. A ttl of zero never expires.
+	MarkActioned(ctx context.Context, key ActionKey, ttl time.Duration) (bool, error)
+	// Forget removes a single recorded action, e.g. when taking it failed.
+	Forget(ctx context.Context, key ActionKey) error
+	// Invalidate forgets every recorded instance of the action on the subject, whichever rules
+	// took it, so it can be taken again.
+	Invalidate(ctx context.Context, subject, action string) error
+	Close()
+}
+
+// MemcacheActionStore keeps actions in memcached. Entries are lost if memcached restarts or
+// evicts them, so prefer PostgresActionStore where that matters.
+type MemcacheActionStore struct {
+	client *memcache.Client
+	// migrateLegacyKeys honors actions recorded before keys included what was done, see
+	// legacyMemcacheKey.
+	migrateLegacyKeys bool
+}
+
+func NewMemcacheActionStore(servers []string, migrateLegacyKeys bool) (*MemcacheActionStore, error) {
+	client := memcache.New(servers...)
+	if err := client.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping memcache servers: %w", err)
+	}
+	return &MemcacheActionStore{client: client, migrateLegacyKeys: migrateLegacyKeys}, nil
+}
+
+// memcacheMaxRelativeExpiration is the longest expiration memcached treats as relative. Anything
+// longer has to be given as a unix timestamp.
+const memcacheMaxRelativeExpiration = 30 * 24 * time.Hour
+
+func (s *MemcacheActionStore) MarkActioned(ctx context.Context, key ActionKey, ttl time.Duration) (bool, error) {
+	gen, err := s.generation(key.Subject, key.Action)
+	if err != nil {
+		return false, err
+	}
+
+	var expiration int32
+	switch {
+	case ttl <= 0:
+	case ttl > memcacheMaxRelativeExpiration:
+		expiration = int32(time.Now().Add(ttl).Unix())
+	default:
+		expiration = int32(max(ttl, time.Second).Seconds())
+	}
+
+	if err := s.client.Add(&memcache.Item{
+		Key:        memcacheKey(key, gen),
+		Value:      []byte("1"),
+		Expiration: expiration,
+	}); err != nil {
+		if errors.Is(err, memcache.ErrNotStored) {
+			return false, nil
+		}
+		return false, fmt.Errorf("memcache insert error: %w", err)
+	}
+
+	if s.migrateLegacyKeys {
+		if legacyKey, ok := legacyMemcacheKey(key); ok {
+			// Deleting the legacy key consumes it, so it counts for only one action. That's the
+			// one it was most likely recorded for, since whatever repeats after upgrading comes
+			// first, and any other action the rules take on the subject goes ahead as it should.
+			err := s.client.Delete(legacyKey)
+			if err == nil {
+				return false, nil
+			}
+			if !errors.Is(err, memcache.ErrCacheMiss) {
+				return true, fmt.Errorf("memcache delete error: %w", err)
+			}
+		}
+	}
+	return true, nil
+}
+
+func (s *MemcacheActionStore) Forget(ctx context.Context, key ActionKey) error {
+	gen, err := s.generation(key.Subject, key.Action)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Delete(memcacheKey(key, gen)); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+		return fmt.Errorf("memcache delete error: %w", err)
+	}
+	return nil
+}
+
+// Invalidate bumps the generation for the subject and action. memcached can't find every key for
+// them, so instead the generation is part of each key and bumping it orphans the old ones.
+func (s *MemcacheActionStore) Invalidate(ctx context.Context, subject, action string) error {
+	genKey := generationKey(subject, action)
+	if _, err := s.client.Increment(genKey, 1); err != nil {
+		if !errors.Is(err, memcache.ErrCacheMiss) {
+			return fmt.Errorf("memcache increment error: %w", err)
+		}
+		if err := s.client.Set(&memcache.Item{Key: genKey, Value: []byte("1")}); err != nil {
+			return fmt.Errorf("memcache insert error: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *MemcacheActionStore) generation(subject, action string) (uint64, error) {
+	item, err := s.client.Get(generationKey(subject, action))
+	if err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("memcache lookup error: %w", err)
+	}
+	gen, err := strconv.ParseUint(string(item.Value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid generation %q: %w", item.Value, err)
+	}
+	return gen, nil
+}
+
+func memcacheKey(key ActionKey, gen uint64) string {
+	return fmt.Sprintf("%s-gen-%d", key, gen)
+}
+
+// legacyMemcacheKey returns the key the action would've been recorded under before keys included
+// what was done, when only the subject, the rules, and a label's expiration were. Those keys never
+// expire, and without honoring them every subject actioned before upgrading would be actioned again.
+// Only labels, tags, takedowns, and comments were recorded, and reversals shared their keys.
+func legacyMemcacheKey(key ActionKey) (string, bool) {
+	kind, _, _ := strings.Cut(key.Action, ":")
+	switch kind {
+	case "label":
+		legacy := fmt.Sprintf("%s-%s", key.Subject, key.Rules)
+		if key.ExpirationInHours != nil {
+			legacy = fmt.Sprintf("%s-dur-%d", legacy, *key.ExpirationInHours)
+		}
+		return legacy, true
+	case "tag", "takedown", "comment":
+		return fmt.Sprintf("%s-%s", key.Subject, key.Rules), true
+	}
+	return "", false
+}
+
+func generationKey(subject, action string) string {
+	return fmt.Sprintf("gen-%s-%s", subject, action)
+}
+
+func (s *MemcacheActionStore) Close() {
+	s.client.Close()
+}
+
+// PostgresActionStore keeps actions in Postgres, so they survive restarts and expire when they're
+// meant to.
+type PostgresActionStore struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+
+	cancelPrune context.CancelFunc
+	pruneDone   chan struct{}
+}
+
+const createActionsTable = `
+CREATE TABLE IF NOT EXISTS effector_actions (
+	key TEXT PRIMARY KEY,
+	subject TEXT NOT NULL,
+	action TEXT NOT NULL,
+	expires_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS effector_actions_subject_action ON effector_actions (subject, action);
+CREATE INDEX IF NOT EXISTS effector_actions_expires_at ON effector_actions (expires_at);
+`
+
+// actionsPruneInterval is how often expired actions are deleted. Expired rows are already treated
+// as absent, so this only keeps the table from growing.
+const actionsPruneInterval = time.Hour
+
+func NewPostgresActionStore(ctx context.Context, url string, logger *slog.Logger) (*PostgresActionStore, error) {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+	if _, err := pool.Exec(ctx, createActionsTable); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to create actions table: %w", err)
+	}
+
+	pruneCtx, cancel := context.WithCancel(context.Background())
+	s := &PostgresActionStore{
+		pool:        pool,
+		logger:      logger.With("component", "postgres_action_store"),
+		cancelPrune: cancel,
+		pruneDone:   make(chan struct{}),
+	}
+	go s.runPrune(pruneCtx)
+
+	return s, nil
+}
+
+func (s *PostgresActionStore) MarkActioned(ctx context.Context, key ActionKey, ttl time.Duration) (bool, error) {
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+
+	var inserted string
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO effector_actions (key, subject, action, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET expires_at = EXCLUDED.expires_at, created_at = now()
+		WHERE effector_actions.expires_at IS NOT NULL AND effector_actions.expires_at <= now()
+		RETURNING key`,
+		key.String(), key.Subject, key.Action, expiresAt,
+	).Scan(&inserted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to insert action: %w", err)
+	}
+	return true, nil
+}
+
+func (s *PostgresActionStore) Forget(ctx context.Context, key ActionKey) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM effector_actions WHERE key = $1`, key.String()); err != nil {
+		return fmt.Errorf("failed to delete action: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresActionStore) Invalidate(ctx context.Context, subject, action string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM effector_actions WHERE subject = $1 AND action = $2`, subject, action); err != nil {
+		return fmt.Errorf("failed to delete actions: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresActionStore) runPrune(ctx context.Context) {
+	defer close(s.pruneDone)
+
+	ticker := time.NewTicker(actionsPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := s.pool.Exec(ctx, `DELETE FROM effector_actions WHERE expires_at <= now()`)
+			if err != nil {
+				s.logger.Error("failed to prune expired actions", "error", err)
+				continue
+			}
+			s.logger.Info("pruned expired actions", "count", res.RowsAffected())
+		}
+	}
+}
+
+func (s *PostgresActionStore) Close() {
+	s.cancelPrune()
+	<-s.pruneDone
+	s.pool.Close()
+}
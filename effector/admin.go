@@ -0,0 +1,412 @@
+package effector
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo-contrib/echoprometheus"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	slogecho "github.com/samber/slog-echo"
+)
+
+var (
+	consumptionPaused = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "consumption_paused",
+		Namespace: NAMESPACE,
+		Help:      "whether consuming events has been paused through the admin API",
+	})
+
+	killSwitchesActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "kill_switches_active",
+		Namespace: NAMESPACE,
+		Help:      "number of kill switches turned on, by whether they're for effect kinds or rules",
+	}, []string{"type"})
+)
+
+// newAdminServer creates the HTTP server for the effector's admin API, which lets operators pause
+// consumption and kill effects at runtime instead of killing the pod. Routes are registered in
+// addAdminRoutes.
+func newAdminServer(logger *slog.Logger, addr string) (*http.Server, *echo.Echo) {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	e.Use(middleware.Recover())
+	e.Use(middleware.RemoveTrailingSlash())
+	e.Use(echoprometheus.NewMiddleware("effector_admin"))
+
+	slogEchoCfg := slogecho.Config{
+		DefaultLevel:     slog.LevelInfo,
+		ServerErrorLevel: slog.LevelError,
+		Filters: []slogecho.Filter{
+			func(ctx echo.Context) bool {
+				return ctx.Request().URL.Path != "/_health"
+			},
+		},
+	}
+	e.Use(slogecho.NewWithConfig(logger, slogEchoCfg))
+
+	httpd := &http.Server{
+		Addr:    addr,
+		Handler: e,
+	}
+
+	return httpd, e
+}
+
+// addAdminRoutes registers the admin API, and the action history API, which also takes the history
+// token so that rules and reviewers can look up history without being able to pause or kill effects.
+func (or *OspreyEffector) addAdminRoutes(e *echo.Echo, token, historyToken string) {
+	e.GET("/_health", func(e echo.Context) error {
+		return e.String(http.StatusOK, "healthy")
+	})
+
+	admin := e.Group("/admin", bearerAuth(token))
+	admin.GET("/status", or.handleAdminStatus)
+	admin.POST("/pause", or.handlePause)
+	admin.POST("/resume", or.handleResume)
+	admin.GET("/rules", or.handleRuleStats)
+	admin.GET("/recent", or.handleRecentActions)
+	admin.PUT("/kill-switches/kinds/:kind", or.handleSetKindKillSwitch(true))
+	admin.DELETE("/kill-switches/kinds/:kind", or.handleSetKindKillSwitch(false))
+	admin.PUT("/kill-switches/rules/:rule", or.handleSetRuleKillSwitch(true))
+	admin.DELETE("/kill-switches/rules/:rule", or.handleSetRuleKillSwitch(false))
+	admin.GET("/suppressions", or.handleListSuppressions)
+	admin.PUT("/suppressions", or.handleSetSuppression)
+	admin.DELETE("/suppressions", or.handleRemoveSuppression)
+
+	history := e.Group("/history", bearerAuth(token, historyToken))
+	history.GET("/actions", or.handleActionHistory)
+}
+
+// runAdminServer serves the admin API until the context is cancelled, then shuts the server down.
+func (or *OspreyEffector) runAdminServer(ctx context.Context) {
+	logger := or.logger.With("component", "admin_api")
+
+	go func() {
+		logger.Info("admin server listening", "addr", or.adminHttpd.Addr)
+		if err := or.adminHttpd.ListenAndServe(); err != http.ErrServerClosed {
+			logger.Error("failed to start admin server", "err", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := or.adminHttpd.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to shut down admin server", "err", err)
+	}
+}
+
+// bearerAuth rejects requests that don't carry one of the given tokens as a bearer token. Empty
+// tokens are never accepted.
+func bearerAuth(tokens ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			auth := e.Request().Header.Get(echo.HeaderAuthorization)
+			if provided, ok := strings.CutPrefix(auth, "Bearer "); ok {
+				for _, token := range tokens {
+					if token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+						return next(e)
+					}
+				}
+			}
+			return e.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		}
+	}
+}
+
+type AdminStatus struct {
+	Paused bool `json:"paused"`
+	// InFlight is the number of events waiting for or being handled by a worker.
+	InFlight    int64    `json:"inFlight"`
+	KilledKinds []string `json:"killedKinds"`
+	KilledRules []string `json:"killedRules"`
+}
+
+func (or *OspreyEffector) handleAdminStatus(e echo.Context) error {
+	kinds, rules := or.killSwitches.list()
+	return e.JSON(http.StatusOK, AdminStatus{
+		Paused:      or.pauseGate.isPaused(),
+		InFlight:    or.workers.inFlight.Load(),
+		KilledKinds: kinds,
+		KilledRules: rules,
+	})
+}
+
+func (or *OspreyEffector) handlePause(e echo.Context) error {
+	if or.pauseGate.pause() {
+		or.logger.Warn("consumption paused through the admin api")
+	}
+	return or.handleAdminStatus(e)
+}
+
+func (or *OspreyEffector) handleResume(e echo.Context) error {
+	if or.pauseGate.resume() {
+		or.logger.Warn("consumption resumed through the admin api")
+	}
+	return or.handleAdminStatus(e)
+}
+
+func (or *OspreyEffector) handleRuleStats(e echo.Context) error {
+	return e.JSON(http.StatusOK, or.ruleCounts.lastHour())
+}
+
+func (or *OspreyEffector) handleSetKindKillSwitch(killed bool) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		kind := e.Param("kind")
+		if !slices.Contains(EffectKinds, kind) {
+			return e.JSON(http.StatusNotFound, map[string]string{"error": "unknown effect kind"})
+		}
+		if or.killSwitches.setKind(kind, killed) {
+			or.logger.Warn("effect kind kill switch toggled", "kind", kind, "killed", killed)
+		}
+		return or.handleAdminStatus(e)
+	}
+}
+
+func (or *OspreyEffector) handleSetRuleKillSwitch(killed bool) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		rule := e.Param("rule")
+		if or.killSwitches.setRule(rule, killed) {
+			or.logger.Warn("rule kill switch toggled", "rule", rule, "killed", killed)
+		}
+		return or.handleAdminStatus(e)
+	}
+}
+
+// pauseGate holds up event handling while consumption is paused.
+type pauseGate struct {
+	mu      sync.Mutex
+	resumed chan struct{}
+}
+
+func newPauseGate() *pauseGate {
+	resumed := make(chan struct{})
+	close(resumed)
+	return &pauseGate{resumed: resumed}
+}
+
+// wait blocks while paused, or until the context is done.
+func (g *pauseGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause reports whether it wasn't already paused.
+func (g *pauseGate) pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	select {
+	case <-g.resumed:
+		g.resumed = make(chan struct{})
+		consumptionPaused.Set(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// resume reports whether it was paused.
+func (g *pauseGate) resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	select {
+	case <-g.resumed:
+		return false
+	default:
+		close(g.resumed)
+		consumptionPaused.Set(0)
+		return true
+	}
+}
+
+func (g *pauseGate) isPaused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	select {
+	case <-g.resumed:
+		return false
+	default:
+		return true
+	}
+}
+
+// killSwitches are effect kinds and rules whose effects are dropped until they're turned off again.
+// Unlike the allowlist and rule modes they're set at runtime, and don't survive a restart.
+type killSwitches struct {
+	mu    sync.RWMutex
+	kinds map[string]bool
+	rules map[string]bool
+}
+
+func newKillSwitches() *killSwitches {
+	return &killSwitches{kinds: map[string]bool{}, rules: map[string]bool{}}
+}
+
+func (k *killSwitches) empty() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.kinds) == 0 && len(k.rules) == 0
+}
+
+func (k *killSwitches) kindKilled(kind string) bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.kinds[kind]
+}
+
+// ruleKilled returns the first of the rules that's been killed, if any.
+func (k *killSwitches) ruleKilled(rules []string) (string, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, rule := range rules {
+		if k.rules[rule] {
+			return rule, true
+		}
+	}
+	return "", false
+}
+
+// setKind reports whether the switch changed.
+func (k *killSwitches) setKind(kind string, killed bool) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return setSwitch(k.kinds, kind, killed, "kind")
+}
+
+// setRule reports whether the switch changed.
+func (k *killSwitches) setRule(rule string, killed bool) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return setSwitch(k.rules, rule, killed, "rule")
+}
+
+func setSwitch(switches map[string]bool, name string, killed bool, typ string) bool {
+	if switches[name] == killed {
+		return false
+	}
+	if killed {
+		switches[name] = true
+	} else {
+		delete(switches, name)
+	}
+	killSwitchesActive.WithLabelValues(typ).Set(float64(len(switches)))
+	return true
+}
+
+func (k *killSwitches) list() ([]string, []string) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return slices.Sorted(maps.Keys(k.kinds)), slices.Sorted(maps.Keys(k.rules))
+}
+
+// ruleCountWindow is how far back the admin API reports effects per rule.
+const ruleCountWindow = time.Hour
+
+// ruleCounts keeps how many effects each rule caused in the last hour, in one bucket per minute.
+type ruleCounts struct {
+	mu      sync.Mutex
+	buckets [60]ruleCountBucket
+}
+
+type ruleCountBucket struct {
+	minute int64
+	counts map[ruleCountKey]int
+}
+
+type ruleCountKey struct {
+	rule string
+	mode string
+}
+
+func newRuleCounts() *ruleCounts {
+	return &ruleCounts{}
+}
+
+func (c *ruleCounts) add(rule, mode string) {
+	minute := time.Now().Unix() / 60
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := &c.buckets[minute%int64(len(c.buckets))]
+	if b.minute != minute || b.counts == nil {
+		b.minute = minute
+		b.counts = map[ruleCountKey]int{}
+	}
+	b.counts[ruleCountKey{rule: rule, mode: mode}]++
+}
+
+type RuleCount struct {
+	Rule    string `json:"rule"`
+	Enforce int    `json:"enforce"`
+	Shadow  int    `json:"shadow"`
+	DryRun  int    `json:"dryRun"`
+}
+
+func (rc RuleCount) total() int {
+	return rc.Enforce + rc.Shadow + rc.DryRun
+}
+
+// lastHour returns the effects each rule caused in the last hour, busiest rule first.
+func (c *ruleCounts) lastHour() []RuleCount {
+	oldest := time.Now().Add(-ruleCountWindow).Unix()/60 + 1
+
+	c.mu.Lock()
+	byRule := map[string]*RuleCount{}
+	for _, b := range c.buckets {
+		if b.minute < oldest {
+			continue
+		}
+		for key, n := range b.counts {
+			rc, ok := byRule[key.rule]
+			if !ok {
+				rc = &RuleCount{Rule: key.rule}
+				byRule[key.rule] = rc
+			}
+			switch key.mode {
+			case "enforce":
+				rc.Enforce += n
+			case "shadow":
+				rc.Shadow += n
+			case "dry-run":
+				rc.DryRun += n
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	counts := make([]RuleCount, 0, len(byRule))
+	for _, rc := range byRule {
+		counts = append(counts, *rc)
+	}
+	slices.SortFunc(counts, func(a, b RuleCount) int {
+		return cmp.Or(cmp.Compare(b.total(), a.total()), cmp.Compare(a.Rule, b.Rule))
+	})
+	return counts
+}
@@ -0,0 +1,139 @@
+package effector
+
+import (
+	"fmt"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Effect kinds, as named in Args.AllowedEffects.
+const (
+	EffectLabel           = "label"
+	EffectTag             = "tag"
+	EffectTakedown        = "takedown"
+	EffectMute            = "mute"
+	EffectDivert          = "divert"
+	EffectReport          = "report"
+	EffectComment         = "comment"
+	EffectEscalation      = "escalation"
+	EffectAcknowledgement = "acknowledgement"
+	EffectResolveAppeal   = "resolve-appeal"
+	EffectSet             = "set"
+	EffectEmail           = "email"
+	EffectBigQueryFlag    = "bigquery-flag"
+)
+
+var EffectKinds = []string{
+	EffectLabel,
+	EffectTag,
+	EffectTakedown,
+	EffectMute,
+	EffectDivert,
+	EffectReport,
+	EffectComment,
+	EffectEscalation,
+	EffectAcknowledgement,
+	EffectResolveAppeal,
+	EffectSet,
+	EffectEmail,
+	EffectBigQueryFlag,
+}
+
+var (
+	effectsBlocked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "effects_blocked",
+		Namespace: NAMESPACE,
+		Help:      "number of effects dropped because their kind isn't allowed, by type and action name",
+	}, []string{"type", "action_name"})
+
+	effectsKilled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "effects_killed",
+		Namespace: NAMESPACE,
+		Help:      "number of effects dropped by a kill switch, by type and action name",
+	}, []string{"type", "action_name"})
+)
+
+func newEffectAllowlist(kinds []string) (map[string]bool, error) {
+	known := map[string]bool{}
+	for _, kind := range EffectKinds {
+		known[kind] = true
+	}
+
+	allowed := map[string]bool{}
+	for _, kind := range kinds {
+		if !known[kind] {
+			return nil, fmt.Errorf("unknown effect kind %q, must be one of %v", kind, EffectKinds)
+		}
+		allowed[kind] = true
+	}
+	return allowed, nil
+}
+
+// dropBlockedEffects removes effects whose kinds aren't allowed from the event, so they're never
+// executed, along with those whose kind or any of whose rules have been killed through the admin
+// API. Every effect is allowed when there's no allowlist and no kill switches are on.
+func (or *OspreyEffector) dropBlockedEffects(evt *osprey.ResultEvent) {
+	if or.allowedEffects == nil && or.killSwitches.empty() {
+		return
+	}
+
+	evt.Labels = blockEffects(or, evt, EffectLabel, evt.Labels)
+	evt.Tags = blockEffects(or, evt, EffectTag, evt.Tags)
+	evt.Takedowns = blockEffects(or, evt, EffectTakedown, evt.Takedowns)
+	evt.Mutes = blockEffects(or, evt, EffectMute, evt.Mutes)
+	evt.Diverts = blockEffects(or, evt, EffectDivert, evt.Diverts)
+	evt.Reports = blockEffects(or, evt, EffectReport, evt.Reports)
+	evt.Comments = blockEffects(or, evt, EffectComment, evt.Comments)
+	evt.Escalations = blockEffects(or, evt, EffectEscalation, evt.Escalations)
+	evt.Acknowledgements = blockEffects(or, evt, EffectAcknowledgement, evt.Acknowledgements)
+	evt.ResolveAppeals = blockEffects(or, evt, EffectResolveAppeal, evt.ResolveAppeals)
+	evt.Sets = blockEffects(or, evt, EffectSet, evt.Sets)
+	evt.Emails = blockEffects(or, evt, EffectEmail, evt.Emails)
+	evt.BigqueryFlags = blockEffects(or, evt, EffectBigQueryFlag, evt.BigqueryFlags)
+}
+
+func blockEffects[T ruledEffect](or *OspreyEffector, evt *osprey.ResultEvent, kind string, effects []T) []T {
+	if len(effects) == 0 {
+		return effects
+	}
+
+	if or.allowedEffects != nil && !or.allowedEffects[kind] {
+		or.logger.Warn("dropping effects that aren't allowed",
+			"kind", kind,
+			"count", len(effects),
+			"actionId", evt.ActionId,
+			"actionName", evt.ActionName,
+		)
+		effectsBlocked.WithLabelValues(kind, evt.ActionName).Add(float64(len(effects)))
+		return nil
+	}
+
+	if or.killSwitches.kindKilled(kind) {
+		or.logger.Warn("dropping effects whose kind has been killed",
+			"kind", kind,
+			"count", len(effects),
+			"actionId", evt.ActionId,
+			"actionName", evt.ActionName,
+		)
+		effectsKilled.WithLabelValues(kind, evt.ActionName).Add(float64(len(effects)))
+		return nil
+	}
+
+	kept := effects[:0]
+	for _, e := range effects {
+		if rule, killed := or.killSwitches.ruleKilled(e.GetRules()); killed {
+			or.logger.Warn("dropping effect whose rule has been killed",
+				"kind", kind,
+				"rule", rule,
+				"actionId", evt.ActionId,
+				"actionName", evt.ActionName,
+			)
+			effectsKilled.WithLabelValues(kind, evt.ActionName).Inc()
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
@@ -2,16 +2,24 @@ package effector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
+	"cloud.google.com/go/bigquery"
 	bigqueryinserter "github.com/bluesky-social/osprey-atproto/pkg/bigquery_inserter"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
 )
 
 type BigQueryLogger struct {
 	eventInserter  *bigqueryinserter.BigQueryInserter
 	effectInserter *bigqueryinserter.BigQueryInserter
-	logger         *slog.Logger
+	// client queries the effect log for action history.
+	client    *bigquery.Client
+	projectID string
+	datasetID string
+	logger    *slog.Logger
 }
 
 type BigQueryLoggerArgs struct {
@@ -25,7 +33,9 @@ func NewBigQueryLogger(args *BigQueryLoggerArgs) (*BigQueryLogger, error) {
 	slog := args.Logger.With("component", "bigquery_logger")
 
 	logger := &BigQueryLogger{
-		logger: slog,
+		projectID: args.ProjectID,
+		datasetID: args.DatasetID,
+		logger:    slog,
 	}
 
 	evtInserter, err := bigqueryinserter.New(context.Background(), &bigqueryinserter.Args{
@@ -62,6 +72,12 @@ func NewBigQueryLogger(args *BigQueryLoggerArgs) (*BigQueryLogger, error) {
 	}
 	logger.effectInserter = effectInserter
 
+	client, err := bigquery.NewClient(context.Background(), args.ProjectID, option.WithCredentialsJSON(args.CredentialsJson))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bigquery client: %w", err)
+	}
+	logger.client = client
+
 	return logger, nil
 }
 
@@ -77,7 +93,44 @@ func (l *BigQueryLogger) LogEffect(ctx context.Context, log *OspreyEffectLog) er
 	return l.effectInserter.Insert(context.Background(), log)
 }
 
+// CountActions counts the events that applied effects to the subject. Effects are only as recent
+// as the effect inserter's last batch.
+func (l *BigQueryLogger) CountActions(ctx context.Context, q *ActionHistoryQuery) (int64, error) {
+	sql := fmt.Sprintf("SELECT COUNT(DISTINCT action_id) AS count FROM `%s.%s.osprey-effects`"+
+		" WHERE subject = @subject AND created_at >= @since AND NOT shadow AND error IS NULL AND kind != 'dry-run'",
+		l.projectID, l.datasetID)
+	params := []bigquery.QueryParameter{
+		{Name: "subject", Value: q.Subject},
+		{Name: "since", Value: q.Since},
+	}
+	if q.Rule != "" {
+		sql += " AND @rule IN UNNEST(SPLIT(rules, ','))"
+		params = append(params, bigquery.QueryParameter{Name: "rule", Value: q.Rule})
+	}
+	if q.Kind != "" {
+		sql += " AND kind = @kind"
+		params = append(params, bigquery.QueryParameter{Name: "kind", Value: q.Kind})
+	}
+
+	query := l.client.Query(sql)
+	query.Parameters = params
+
+	it, err := query.Read(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query effect log: %w", err)
+	}
+
+	var row struct {
+		Count int64 `bigquery:"count"`
+	}
+	if err := it.Next(&row); err != nil && !errors.Is(err, iterator.Done) {
+		return 0, fmt.Errorf("failed to read effect log: %w", err)
+	}
+	return row.Count, nil
+}
+
 func (l *BigQueryLogger) Close() {
 	l.eventInserter.Close(context.Background())
 	l.effectInserter.Close(context.Background())
+	l.client.Close()
 }
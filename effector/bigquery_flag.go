@@ -8,6 +8,16 @@ import (
 
 	"github.com/bluesky-social/indigo/atproto/syntax"
 	bigqueryinserter "github.com/bluesky-social/osprey-atproto/pkg/bigquery_inserter"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+const (
+	defaultBigQueryFlagRecordTable = "tagged_posts"
+	defaultBigQueryFlagActorTable  = "tagged_actors"
+
+	// bigQueryFlagFlushInterval is how often partial batches of flags are sent, so that flags from
+	// rules that rarely fire don't wait on the batch filling up.
+	bigQueryFlagFlushInterval = 10 * time.Second
 )
 
 type BigQueryFlag struct {
@@ -19,73 +29,175 @@ type BigQueryFlag struct {
 	CreatedAt  time.Time `bigquery:"created_at"`
 }
 
+// BigQueryActorFlag is a flag on an account, which goes in its own table since there's no record.
+type BigQueryActorFlag struct {
+	Did       string    `bigquery:"did"`
+	Tag       string    `bigquery:"tag"`
+	CreatedAt time.Time `bigquery:"created_at"`
+}
+
 type BigQueryFlagClient struct {
-	inserter *bigqueryinserter.BigQueryInserter
-	logger   *slog.Logger
+	recordInserter *bigqueryinserter.BigQueryInserter
+	actorInserter  *bigqueryinserter.BigQueryInserter
+	logger         *slog.Logger
+
+	stopFlush chan struct{}
+	flushDone chan struct{}
 }
 
 type BigQueryFlagClientArgs struct {
 	CredentialsJson []byte
 	ProjectID       string
 	DatasetID       string
-	Logger          *slog.Logger
+	// RecordTable and ActorTable are the tables flags on records and accounts are inserted into.
+	// They default to tagged_posts and tagged_actors.
+	RecordTable string
+	ActorTable  string
+	// BatchSize is how many flags are inserted at once. Partial batches are sent every ten
+	// seconds. Defaults to 1.
+	BatchSize int
+	Logger    *slog.Logger
 }
 
 func NewBigQueryFlagClient(args *BigQueryFlagClientArgs) (*BigQueryFlagClient, error) {
 	logger := args.Logger.With("component", "bigquery-flags")
 
-	inserter, err := bigqueryinserter.New(context.Background(), &bigqueryinserter.Args{
-		BaseArgs: bigqueryinserter.BaseArgs{
-			CredentialsJson: args.CredentialsJson,
-			ProjectID:       args.ProjectID,
-			DatasetID:       args.DatasetID,
-			TableID:         "tagged_posts",
-			MaxPendingSends: 100,
-		},
-		BatchSize: 1,
-		Logger:    logger,
-	})
+	if args.RecordTable == "" {
+		args.RecordTable = defaultBigQueryFlagRecordTable
+	}
+	if args.ActorTable == "" {
+		args.ActorTable = defaultBigQueryFlagActorTable
+	}
+	if args.BatchSize <= 0 {
+		args.BatchSize = 1
+	}
+
+	newInserter := func(table string) (*bigqueryinserter.BigQueryInserter, error) {
+		return bigqueryinserter.New(context.Background(), &bigqueryinserter.Args{
+			BaseArgs: bigqueryinserter.BaseArgs{
+				CredentialsJson: args.CredentialsJson,
+				ProjectID:       args.ProjectID,
+				DatasetID:       args.DatasetID,
+				TableID:         table,
+				MaxPendingSends: 100,
+			},
+			BatchSize: args.BatchSize,
+			Logger:    logger.With("table", table),
+		})
+	}
+
+	recordInserter, err := newInserter(args.RecordTable)
+	if err != nil {
+		return nil, err
+	}
+	actorInserter, err := newInserter(args.ActorTable)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BigQueryFlagClient{
-		inserter: inserter,
-		logger:   logger,
-	}, nil
+	bfc := &BigQueryFlagClient{
+		recordInserter: recordInserter,
+		actorInserter:  actorInserter,
+		logger:         logger,
+		stopFlush:      make(chan struct{}),
+		flushDone:      make(chan struct{}),
+	}
+	if args.BatchSize > 1 {
+		go bfc.runFlush()
+	} else {
+		close(bfc.flushDone)
+	}
+
+	return bfc, nil
+}
+
+func (bfc *BigQueryFlagClient) runFlush() {
+	defer close(bfc.flushDone)
+
+	ticker := time.NewTicker(bigQueryFlagFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bfc.stopFlush:
+			return
+		case <-ticker.C:
+			bfc.recordInserter.Flush(context.Background())
+			bfc.actorInserter.Flush(context.Background())
+		}
+	}
+}
+
+// Close sends any flags still waiting on a batch.
+func (bfc *BigQueryFlagClient) Close() {
+	close(bfc.stopFlush)
+	<-bfc.flushDone
+	bfc.recordInserter.Close(context.Background())
+	bfc.actorInserter.Close(context.Background())
 }
 
-func (bfc *BigQueryFlagClient) addFlag(ctx context.Context, uri string, tag string) error {
+// addFlag flags the event's record or account, depending on the subject kind.
+func (bfc *BigQueryFlagClient) addFlag(ctx context.Context, kind osprey.AtprotoSubjectKind, did string, uri string, tag string) error {
 	status := "error"
 
 	defer func() {
 		effectsProcessed.WithLabelValues("bigquery-flag", status).Inc()
 	}()
 
+	var err error
+	switch kind {
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
+		err = bfc.addRecordFlag(ctx, uri, tag)
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
+		err = bfc.addActorFlag(ctx, did, tag)
+	default:
+		err = fmt.Errorf("unsupported subject kind for bigquery flag: %s", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	status = "ok"
+
+	return nil
+}
+
+func (bfc *BigQueryFlagClient) addRecordFlag(ctx context.Context, uri string, tag string) error {
 	aturi, err := syntax.ParseATURI(uri)
 	if err != nil {
 		return fmt.Errorf("could not parse uri passed to bigquery flag: %w", err)
 	}
 
-	did := aturi.Authority().String()
-	collection := aturi.Collection().String()
-	rkey := aturi.RecordKey().String()
-
 	flag := BigQueryFlag{
 		Uri:        uri,
-		Did:        did,
-		Collection: collection,
-		Rkey:       rkey,
+		Did:        aturi.Authority().String(),
+		Collection: aturi.Collection().String(),
+		Rkey:       aturi.RecordKey().String(),
 		Tag:        tag,
 		CreatedAt:  time.Now(),
 	}
 
-	if err := bfc.inserter.Insert(ctx, flag); err != nil {
+	if err := bfc.recordInserter.Insert(ctx, flag); err != nil {
 		bfc.logger.Error("error adding insert for bigquery flag", "error", err)
 		return err
 	}
+	return nil
+}
 
-	status = "ok"
+func (bfc *BigQueryFlagClient) addActorFlag(ctx context.Context, did string, tag string) error {
+	if _, err := syntax.ParseDID(did); err != nil {
+		return fmt.Errorf("could not parse did passed to bigquery flag: %w", err)
+	}
+
+	flag := BigQueryActorFlag{
+		Did:       did,
+		Tag:       tag,
+		CreatedAt: time.Now(),
+	}
 
+	if err := bfc.actorInserter.Insert(ctx, flag); err != nil {
+		bfc.logger.Error("error adding insert for bigquery actor flag", "error", err)
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,118 @@
+package effector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// commentTemplateDefault is the key of the template used for effect kinds without one of their
+// own.
+const commentTemplateDefault = "default"
+
+// defaultCommentTemplate is how comments read when no templates are configured.
+var defaultCommentTemplate = template.Must(template.New(commentTemplateDefault).Parse(
+	"Actioned by rules {{.Rules}}{{if .Comment}}\n\n{{.Comment}}{{end}}"))
+
+// CommentData is what comment templates are executed with.
+type CommentData struct {
+	// Kind is the effect kind, as named in EffectKinds.
+	Kind string
+	// Rules are the rules behind the effect, comma separated, and RuleList the same as a list.
+	Rules    string
+	RuleList []string
+	// Comment is the rule's own comment on the effect, if it gave one.
+	Comment     string
+	ActionID    int64
+	ActionName  string
+	Environment string
+}
+
+// commentTemplates renders the comments on Ozone events, so that deployments can match their
+// moderators' conventions.
+type commentTemplates struct {
+	byKind      map[string]*template.Template
+	environment string
+}
+
+// loadCommentTemplates reads a JSON object of Go templates by effect kind, with "default" for any
+// kind that isn't listed, e.g.
+//
+//	{"default": "Rules: {{.Rules}}\n{{.Comment}}", "takedown": "Taken down by {{.Rules}} ({{.ActionID}})"}
+//
+// Without a path, comments say which rules the effect was actioned by, followed by the rule's own
+// comment.
+func loadCommentTemplates(path, environment string) (*commentTemplates, error) {
+	ct := &commentTemplates{
+		byKind:      map[string]*template.Template{commentTemplateDefault: defaultCommentTemplate},
+		environment: environment,
+	}
+	if path == "" {
+		return ct, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read comment templates: %w", err)
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal comment templates: %w", err)
+	}
+
+	for kind, text := range raw {
+		if kind != commentTemplateDefault && !slices.Contains(EffectKinds, kind) {
+			return nil, fmt.Errorf("comment template for unknown effect kind %q", kind)
+		}
+		tmpl, err := template.New(kind).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse comment template for %s: %w", kind, err)
+		}
+		ct.byKind[kind] = tmpl
+	}
+	return ct, nil
+}
+
+// render executes the template for the kind, falling back to the built in one if it fails, since
+// an effect shouldn't be held up by its comment.
+func (ct *commentTemplates) render(data *CommentData) (string, error) {
+	tmpl, ok := ct.byKind[data.Kind]
+	if !ok {
+		tmpl = ct.byKind[commentTemplateDefault]
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		sb.Reset()
+		_ = defaultCommentTemplate.Execute(&sb, data)
+		return sb.String(), fmt.Errorf("failed to execute comment template for %s: %w", data.Kind, err)
+	}
+	return sb.String(), nil
+}
+
+// effectComment renders the comment for one of the event's effects, followed by its evidence.
+func (or *OspreyEffector) effectComment(evt *osprey.ResultEvent, kind string, rules []string, comment string) string {
+	return withEvidence(or.renderComment(evt, kind, rules, comment), evt, rules)
+}
+
+// renderComment renders the comment for one of the event's effects, without its evidence.
+func (or *OspreyEffector) renderComment(evt *osprey.ResultEvent, kind string, rules []string, comment string) string {
+	text, err := or.commentTemplates.render(&CommentData{
+		Kind:        kind,
+		Rules:       strings.Join(rules, ","),
+		RuleList:    rules,
+		Comment:     comment,
+		ActionID:    evt.ActionId,
+		ActionName:  evt.ActionName,
+		Environment: or.commentTemplates.environment,
+	})
+	if err != nil {
+		or.logger.Error("falling back to the default comment", "kind", kind, "actionId", evt.ActionId, "error", err)
+	}
+	return text
+}
@@ -0,0 +1,233 @@
+package effector
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var effectsConsolidated = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "effects_consolidated",
+	Namespace: NAMESPACE,
+	Help:      "number of tag and comment effects folded into another effect's Ozone event instead of getting their own",
+}, []string{"kind"})
+
+// consolidatedEffect is a tag or comment effect that's been checked against the actions already
+// taken and is waiting to go out in its subject's consolidated event.
+type consolidatedEffect struct {
+	key     ActionKey
+	action  string
+	comment string
+	tag     *osprey.AtprotoTagEffect
+	note    *osprey.AtprotoCommentEffect
+}
+
+// applyTagsAndComments applies the event's tag and comment effects with at most one Ozone event per
+// subject, rather than one per effect: all of the tags go in a single tag event, and the comments
+// ride along as that event's comment, or go in a single comment event when there are no tags.
+// Otherwise a rule that tags and comments shows up as a pile of separate events in the subject's
+// history. Every effect is still deduplicated, logged and retried on its own.
+//
+// Labels aren't folded in: an Ozone event is either a label or a tag event, never both, and a label
+// event carries its own expiration and email, so comments riding along on it would be retried and
+// reversed along with the label.
+func (or *OspreyEffector) applyTagsAndComments(ctx context.Context, evt, failed *osprey.ResultEvent) []error {
+	var errs []error
+	for _, kind := range []osprey.AtprotoSubjectKind{
+		osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR,
+		osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD,
+		osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE,
+	} {
+		if err := or.applyConsolidated(ctx, evt, failed, kind); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (or *OspreyEffector) applyConsolidated(ctx context.Context, evt, failed *osprey.ResultEvent, kind osprey.AtprotoSubjectKind) error {
+	var subject string
+	switch kind {
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
+		subject = evt.Did
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
+		subject = evt.Uri
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE:
+		if evt.Message == nil {
+			// Message effects without a message were already dropped.
+			return nil
+		}
+		subject = MessageSubject(evt.Message)
+	}
+
+	var pending []*consolidatedEffect
+	for _, e := range evt.Tags {
+		if e.SubjectKind != kind {
+			continue
+		}
+		rules := strings.Join(e.Rules, ",")
+		action := actionName("tag", e.Tag, e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)
+		key := ActionKey{Subject: subject, Action: action, Rules: rules, ExpirationInHours: e.ExpirationInHours}
+		if or.checkHasActioned(ctx, key) {
+			or.logger.Info("skipping ozone tag effect", "actionId", evt.ActionId)
+			ozoneRequests.WithLabelValues("tag", kind.String(), "skipped").Inc()
+			continue
+		}
+
+		comment := or.renderComment(evt, EffectTag, e.Rules, e.GetComment())
+		pending = append(pending, &consolidatedEffect{key: key, action: action, comment: comment, tag: e})
+	}
+	for _, e := range evt.Comments {
+		if e.SubjectKind != kind {
+			continue
+		}
+		rules := strings.Join(e.Rules, ",")
+		key := ActionKey{Subject: subject, Action: actionName("comment", "", false), Rules: rules}
+		if or.checkHasActioned(ctx, key) {
+			or.logger.Info("skipping ozone comment effect", "actionId", evt.ActionId)
+			ozoneRequests.WithLabelValues("comment", kind.String(), "skipped").Inc()
+			continue
+		}
+
+		comment := or.renderComment(evt, EffectComment, e.Rules, e.Comment)
+		pending = append(pending, &consolidatedEffect{key: key, comment: comment, note: e})
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Tags without a comment of their own only need the rules, which are in the mod tool metadata,
+	// so only comments that say something are combined.
+	var add, remove, rules, comments []string
+	for _, p := range pending {
+		if p.tag != nil {
+			if p.tag.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE {
+				remove = appendUnique(remove, p.tag.Tag)
+			} else {
+				add = appendUnique(add, p.tag.Tag)
+			}
+		}
+		if p.note != nil || p.tag.Comment != nil {
+			comments = appendUnique(comments, p.comment)
+		}
+		for _, rule := range p.rules() {
+			rules = appendUnique(rules, rule)
+		}
+	}
+	meta := ModToolMeta{
+		Rules:    strings.Join(rules, ","),
+		ActionID: evt.ActionId,
+	}
+
+	// Evidence is shown once for all of the effects, rather than on each of their comments.
+	var comment *string
+	if combined := withEvidence(strings.Join(comments, "\n\n"), evt, rules); combined != "" {
+		comment = &combined
+	}
+
+	tagged := len(add) > 0 || len(remove) > 0
+	if !tagged && comment == nil {
+		// Comments whose templates rendered nothing have nothing to send.
+		or.logger.Info("skipping empty ozone comment effects", "actionId", evt.ActionId, "subject", subject)
+		for _, p := range pending {
+			or.forgetAction(p.key)
+			ozoneRequests.WithLabelValues(p.kind(), kind.String(), "skipped-empty").Inc()
+		}
+		return nil
+	}
+
+	callCtx, sent := withSentEvent(ctx)
+	var err error
+	switch kind {
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
+		if tagged {
+			err = or.ozoneClient.TagActor(callCtx, evt.Did, meta, add, remove, comment)
+		} else {
+			err = or.ozoneClient.CommentActor(callCtx, evt.Did, meta, *comment)
+		}
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
+		if tagged {
+			err = or.ozoneClient.TagRecord(callCtx, evt.Uri, evt.Cid, meta, add, remove, comment)
+		} else {
+			err = or.ozoneClient.CommentRecord(callCtx, evt.Uri, evt.Cid, meta, *comment)
+		}
+	case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE:
+		if tagged {
+			err = or.ozoneClient.TagMessage(callCtx, evt.Message, meta, add, remove, comment)
+		} else {
+			err = or.ozoneClient.CommentMessage(callCtx, evt.Message, meta, *comment)
+		}
+	}
+
+	if err != nil {
+		or.logger.Error("error processing tag and comment effects", "subject", subject, "error", err)
+	} else if len(pending) > 1 {
+		for _, p := range pending[1:] {
+			effectsConsolidated.WithLabelValues(p.kind()).Inc()
+		}
+	}
+
+	for _, p := range pending {
+		if err != nil {
+			or.forgetAction(p.key)
+			if p.tag != nil {
+				failed.Tags = append(failed.Tags, p.tag)
+			} else {
+				failed.Comments = append(failed.Comments, p.note)
+			}
+			ozoneRequests.WithLabelValues(p.kind(), kind.String(), "error").Inc()
+			continue
+		}
+
+		ozoneRequests.WithLabelValues(p.kind(), kind.String(), "ok").Inc()
+		log := &OspreyEffectLog{
+			ActionName:   evt.ActionName,
+			ActionID:     evt.ActionId,
+			Subject:      subject,
+			Kind:         p.kind(),
+			Comment:      p.comment,
+			CreatedAt:    time.Now(),
+			Rules:        strings.Join(p.rules(), ","),
+			OzoneEventID: sent.eventID(),
+			dryRun:       sent.dryRun,
+		}
+		if p.tag != nil {
+			or.invalidateAction(ctx, subject, oppositeAction(p.action))
+			or.trackTagExpiry(evt, subject, p.tag)
+			log.Tag = bigquery.NullString{
+				StringVal: p.tag.Tag,
+				Valid:     true,
+			}
+		}
+		or.logEffect(log)
+	}
+
+	return err
+}
+
+func (p *consolidatedEffect) kind() string {
+	if p.tag != nil {
+		return "tag"
+	}
+	return "comment"
+}
+
+func (p *consolidatedEffect) rules() []string {
+	if p.tag != nil {
+		return p.tag.Rules
+	}
+	return p.note.Rules
+}
+
+func appendUnique(s []string, v string) []string {
+	if slices.Contains(s, v) {
+		return s
+	}
+	return append(s, v)
+}
@@ -0,0 +1,282 @@
+package effector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// delayedEffectsInterval is how often delayed effects are checked for being due, and the snapshot
+// written.
+const delayedEffectsInterval = 10 * time.Second
+
+var (
+	delayedEffectsPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "delayed_effects_pending",
+		Namespace: NAMESPACE,
+		Help:      "number of delayed effects waiting to be applied",
+	})
+
+	delayedEffectsHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "delayed_effects",
+		Namespace: NAMESPACE,
+		Help:      "number of delayed effects by what happened to them: scheduled, superseded, cancelled, or applied",
+	}, []string{"outcome"})
+)
+
+// delayedEffects holds effects that rules asked to be applied after a delay, such as taking a post
+// down unless its author deletes it within ten minutes. When they're due the event is handed to the
+// workers like any other, unless a later event cancelled them by key first. The effects are
+// snapshotted to disk, so that a restart doesn't forget them.
+//
+// Effects are held by the effector that consumed them. Osprey keys events by DID, so cancellations
+// keyed by the same account's subjects arrive at the same effector, unless partitions were
+// reassigned in between.
+type delayedEffects struct {
+	logger       *slog.Logger
+	snapshotPath string
+
+	mu      sync.Mutex
+	entries map[string]*delayedEntry
+}
+
+type delayedEntry struct {
+	Key       string    `json:"key"`
+	ExecuteAt time.Time `json:"execute_at"`
+	// Event is the marshalled ResultEvent with the effects to apply.
+	Event []byte `json:"event"`
+}
+
+func newDelayedEffects(logger *slog.Logger, snapshotPath string) (*delayedEffects, error) {
+	de := &delayedEffects{
+		logger:       logger.With("component", "delayed_effects"),
+		snapshotPath: snapshotPath,
+		entries:      map[string]*delayedEntry{},
+	}
+
+	if de.snapshotPath != "" {
+		if err := de.loadSnapshot(); err != nil {
+			return nil, fmt.Errorf("failed to load delayed effects snapshot: %w", err)
+		}
+	}
+
+	return de, nil
+}
+
+// schedule holds the event's delayed effects, replacing any still waiting under the same key. The
+// key defaults to the event's subject, the record if there is one and otherwise the account.
+func (de *delayedEffects) schedule(evt *osprey.ResultEvent, d *osprey.DelayedEffects) error {
+	if d.Event == nil {
+		return errors.New("delayed effects without an event")
+	}
+	key := d.Key
+	if key == "" {
+		key = d.Event.Uri
+	}
+	if key == "" {
+		key = d.Event.Did
+	}
+	if key == "" {
+		key = evt.Did
+	}
+
+	b, err := proto.Marshal(d.Event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delayed event: %w", err)
+	}
+
+	de.mu.Lock()
+	defer de.mu.Unlock()
+	if _, ok := de.entries[key]; ok {
+		delayedEffectsHandled.WithLabelValues("superseded").Inc()
+	}
+	de.entries[key] = &delayedEntry{
+		Key:       key,
+		ExecuteAt: time.Now().Add(time.Duration(d.ExecuteAfterSeconds) * time.Second),
+		Event:     b,
+	}
+	delayedEffectsHandled.WithLabelValues("scheduled").Inc()
+	delayedEffectsPending.Set(float64(len(de.entries)))
+	return nil
+}
+
+// cancel drops the effects waiting under the key, reporting whether there were any.
+func (de *delayedEffects) cancel(key string) bool {
+	de.mu.Lock()
+	defer de.mu.Unlock()
+	if _, ok := de.entries[key]; !ok {
+		return false
+	}
+	delete(de.entries, key)
+	delayedEffectsHandled.WithLabelValues("cancelled").Inc()
+	delayedEffectsPending.Set(float64(len(de.entries)))
+	return true
+}
+
+// due removes and returns the entries due by now.
+func (de *delayedEffects) due(now time.Time) []*delayedEntry {
+	de.mu.Lock()
+	defer de.mu.Unlock()
+
+	var due []*delayedEntry
+	for key, e := range de.entries {
+		if !e.ExecuteAt.After(now) {
+			due = append(due, e)
+			delete(de.entries, key)
+		}
+	}
+	delayedEffectsPending.Set(float64(len(de.entries)))
+	return due
+}
+
+// requeue puts back an entry that couldn't be handed off, unless it's been superseded or
+// cancelled since it was taken.
+func (de *delayedEffects) requeue(e *delayedEntry) {
+	de.mu.Lock()
+	defer de.mu.Unlock()
+	if _, ok := de.entries[e.Key]; !ok {
+		de.entries[e.Key] = e
+		delayedEffectsPending.Set(float64(len(de.entries)))
+	}
+}
+
+// handleDelayedEffects cancels the delayed effects the event asks to, then schedules its own. It's
+// done before anything else about the event, so that a cancellation isn't held up by the event's
+// other effects failing.
+func (or *OspreyEffector) handleDelayedEffects(evt *osprey.ResultEvent) {
+	for _, key := range evt.CancelDelayed {
+		if or.delayedEffects.cancel(key) {
+			or.logger.Info("cancelled delayed effects", "key", key, "actionId", evt.ActionId)
+		}
+	}
+	for _, d := range evt.Delayed {
+		if err := or.delayedEffects.schedule(evt, d); err != nil {
+			or.logger.Error("failed to schedule delayed effects", "actionId", evt.ActionId, "error", err)
+		}
+	}
+}
+
+// runDelayedEffects hands delayed effects to the workers as they come due until the context is
+// cancelled. The final snapshot is written by the caller once events in flight have been handled,
+// since they can still schedule or cancel effects.
+func (or *OspreyEffector) runDelayedEffects(ctx context.Context) {
+	ticker := time.NewTicker(delayedEffectsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			or.applyDueEffects(ctx, now)
+			if err := or.delayedEffects.writeSnapshot(); err != nil {
+				or.delayedEffects.logger.Error("failed to write delayed effects snapshot", "err", err)
+			}
+		}
+	}
+}
+
+// applyDueEffects submits the effects that are due to the workers without waiting on them, so that
+// one slow event doesn't hold up the rest. Effects that can't be submitted are kept for the next
+// tick.
+func (or *OspreyEffector) applyDueEffects(ctx context.Context, now time.Time) {
+	logger := or.delayedEffects.logger
+
+	for _, e := range or.delayedEffects.due(now) {
+		var evt osprey.ResultEvent
+		if err := proto.Unmarshal(e.Event, &evt); err != nil {
+			logger.Error("failed to unmarshal delayed event, dropping it", "key", e.Key, "err", err)
+			continue
+		}
+		// Sent as of when it came due, so that the delay doesn't count towards latency or backlog.
+		evt.SendTime = timestamppb.New(e.ExecuteAt)
+
+		if err := or.pauseGate.wait(ctx); err != nil {
+			or.delayedEffects.requeue(e)
+			continue
+		}
+		if _, err := or.workers.submit(ctx, eventSubject(&evt), func() { or.handleEventWorker(&evt) }); err != nil {
+			logger.Error("failed to submit delayed event", "key", e.Key, "err", err)
+			or.delayedEffects.requeue(e)
+			continue
+		}
+		logger.Info("applying delayed effects", "key", e.Key, "actionId", evt.ActionId)
+		delayedEffectsHandled.WithLabelValues("applied").Inc()
+	}
+}
+
+func (de *delayedEffects) writeSnapshot() error {
+	if de.snapshotPath == "" {
+		return nil
+	}
+
+	de.mu.Lock()
+	snapshot := make([]*delayedEntry, 0, len(de.entries))
+	for _, e := range de.entries {
+		snapshot = append(snapshot, e)
+	}
+	b, err := json.Marshal(snapshot)
+	de.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// Write to a temp file and rename so we never leave a partial snapshot behind.
+	tmp, err := os.CreateTemp(filepath.Dir(de.snapshotPath), filepath.Base(de.snapshotPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), de.snapshotPath); err != nil {
+		return fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+
+	de.logger.Debug("wrote delayed effects snapshot", "entries", len(snapshot))
+	return nil
+}
+
+func (de *delayedEffects) loadSnapshot() error {
+	b, err := os.ReadFile(de.snapshotPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			de.logger.Info("no delayed effects snapshot found, starting empty", "path", de.snapshotPath)
+			return nil
+		}
+		return err
+	}
+
+	var snapshot []*delayedEntry
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	// Effects that came due while we were down are kept, and applied on the first tick.
+	for _, e := range snapshot {
+		de.entries[e.Key] = e
+	}
+	delayedEffectsPending.Set(float64(len(de.entries)))
+
+	de.logger.Info("loaded delayed effects snapshot", "path", de.snapshotPath, "entries", len(de.entries))
+	return nil
+}
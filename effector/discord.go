@@ -0,0 +1,161 @@
+package effector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Embed colors, by whether the effect was applied.
+const (
+	discordColorApplied  = 0x1185fe
+	discordColorShadowed = 0x8b8b8b
+)
+
+// Discord rejects embeds with fields or descriptions longer than these.
+const (
+	discordMaxContentLength     = 2000
+	discordMaxFieldLength       = 1024
+	discordMaxDescriptionLength = 4096
+)
+
+type DiscordLogger struct {
+	webhookUrl string
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color"`
+	Timestamp   string              `json:"timestamp"`
+	Fields      []discordEmbedField `json:"fields"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+func NewDiscordLogger(webhookUrl string) *DiscordLogger {
+	return &DiscordLogger{
+		webhookUrl: webhookUrl,
+	}
+}
+
+func (l *DiscordLogger) Name() string {
+	return "discord"
+}
+
+// No-op, we don't log events to Discord
+func (l *DiscordLogger) LogEvent(ctx context.Context, log *OspreyEventLog) error {
+	return nil
+}
+
+func (l *DiscordLogger) LogEffect(ctx context.Context, log *OspreyEffectLog) error {
+	// Failures are alerted on once they're dead-lettered, rather than on every attempt.
+	if log.Error.Valid {
+		return nil
+	}
+	bskyUrl, ozoneUrl, err := subjectUrls(log.Subject)
+	if err != nil {
+		return err
+	}
+
+	embed := discordEmbed{
+		Title:       fmt.Sprintf("%s: %s", log.Kind, log.ActionName),
+		Description: truncate(log.Comment, discordMaxDescriptionLength),
+		URL:         ozoneUrl,
+		Color:       discordColorApplied,
+		Timestamp:   log.CreatedAt.Format(time.RFC3339Nano),
+		Fields: []discordEmbedField{
+			{Name: "Action ID", Value: strconv.FormatInt(log.ActionID, 10), Inline: true},
+			{Name: "Rules", Value: discordFieldValue(log.Rules), Inline: true},
+			{Name: "Subject", Value: discordFieldValue(log.Subject)},
+		},
+	}
+
+	if log.Shadow {
+		embed.Title = "[shadow] " + embed.Title
+		embed.Color = discordColorShadowed
+	}
+	if log.Handle != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Handle", Value: "@" + log.Handle, Inline: true})
+	}
+	if log.Label.Valid {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Label", Value: log.Label.StringVal, Inline: true})
+	}
+	if log.Tag.Valid {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Tag", Value: log.Tag.StringVal, Inline: true})
+	}
+	if log.OzoneSet.Valid {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Set", Value: log.OzoneSet.StringVal, Inline: true})
+	}
+	if bskyUrl != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Bsky URL", Value: bskyUrl})
+	}
+	embed.Fields = append(embed.Fields, discordEmbedField{Name: "Ozone URL", Value: ozoneUrl})
+
+	return l.send(ctx, discordMessage{Embeds: []discordEmbed{embed}})
+}
+
+// Alert posts a message that isn't about any one effect, e.g. effects that failed for good.
+func (l *DiscordLogger) Alert(ctx context.Context, msg string) error {
+	// wrap in backticks so it looks nice
+	msg = truncate(msg, discordMaxContentLength-len("```\n\n```"))
+	return l.send(ctx, discordMessage{Content: fmt.Sprintf("```\n%s\n```", msg)})
+}
+
+func (l *DiscordLogger) send(ctx context.Context, payload discordMessage) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", l.webhookUrl, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// discordFieldValue fits the value into an embed field, which can't be empty either.
+func discordFieldValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return truncate(s, discordMaxFieldLength)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -1,21 +1,27 @@
 package effector
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/bluesky-social/go-util/pkg/bus/consumer"
+	"github.com/bluesky-social/go-util/pkg/bus/producer"
+	"github.com/bluesky-social/indigo/api/ozone"
+	"github.com/bluesky-social/indigo/atproto/syntax"
 	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
-	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -48,6 +54,19 @@ var (
 		Namespace: NAMESPACE,
 		Help:      "number of requests to Ozone",
 	}, []string{"type", "kind", "status"})
+
+	ruleEffects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "rule_effects",
+		Namespace: NAMESPACE,
+		Help:      "number of effects applied, by rule, kind, and whether they were enforced, shadowed, or a dry run",
+	}, []string{"rule", "kind", "mode"})
+
+	ozoneRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "ozone_request_duration_seconds",
+		Namespace: NAMESPACE,
+		Help:      "latency of events sent to Ozone, by kind and status",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"kind", "status"})
 )
 
 type OspreyEffector struct {
@@ -57,13 +76,68 @@ type OspreyEffector struct {
 
 	ozoneClient *OzoneClient
 
-	memClient *memcache.Client
-
-	logManager     *OspreyLogManager
-	bigQueryLogger *BigQueryLogger
+	actionStore ActionStore
+	// actionDefaultTTL is how long actions that don't expire on their own are recorded for.
+	actionDefaultTTL time.Duration
+
+	logManager       *OspreyLogManager
+	bigQueryLogger   *BigQueryLogger
+	webhookLogger    *WebhookLogger
+	postgresLogger   *PostgresLogger
+	openSearchLogger *OpenSearchLogger
+	slackLogger      *SlackLogger
+	// alerters are the chat loggers that failures needing attention are posted to.
+	alerters []Alerter
+	// errorSinks are told about effects Ozone failed to apply.
+	errorSinks []ErrorSink
+	// commentTemplates render the comments on Ozone events.
+	commentTemplates *commentTemplates
+
+	// allowedEffects is the set of effect kinds that may be executed, or nil to allow all of them.
+	allowedEffects map[string]bool
+	// ruleModes shadows or partially rolls out rules' effects, or is nil to enforce all of them.
+	ruleModes ruleModes
+
+	workers *workerPool
+	retries *retryQueue
+
+	// outcomeProducer publishes the outcome of each Ozone event, when there's an outcomes topic.
+	outcomeProducer *producer.Producer[*osprey.EffectOutcome]
 
 	bigqueryFlagClient *BigQueryFlagClient
 
+	// checkSubjectStatus skips labels and takedowns that Ozone says are already in place.
+	checkSubjectStatus bool
+
+	// pauseGate, killSwitches, suppressions, and ruleCounts are controlled and reported on through
+	// the admin API.
+	pauseGate    *pauseGate
+	killSwitches *killSwitches
+	suppressions *suppressions
+	ruleCounts   *ruleCounts
+	adminHttpd   *http.Server
+	// history is the effect log the action history API looks up, if there's one that can be.
+	history ActionHistory
+	// recentActions keeps the last events handled for the admin API, if enabled.
+	recentActions *recentActions
+
+	// shutdownTimeout is how long in-flight effects are waited on when shutting down.
+	shutdownTimeout time.Duration
+
+	// backlogThreshold is how old events can get before alerting that the effector is behind, and
+	// backlogAlerting is set while it is.
+	backlogThreshold time.Duration
+	backlogAlerting  atomic.Bool
+
+	// ruleGuard pauses rules acting far above their baseline, if enabled.
+	ruleGuard *ruleGuard
+
+	// labelExpiries produces temporary labels back to Osprey when they expire, when set.
+	labelExpiries *labelExpiries
+
+	// delayedEffects holds effects until they're due, or cancelled.
+	delayedEffects *delayedEffects
+
 	isProduction bool
 }
 
@@ -76,17 +150,149 @@ type Args struct {
 	BigQueryCredentialsJson []byte
 	BigQueryProjectID       string
 	BigQueryDatasetID       string
+	// BigQueryFlagRecordTable and BigQueryFlagActorTable are the tables BigQuery flags on records
+	// and accounts go in, tagged_posts and tagged_actors by default. BigQueryFlagBatchSize is how
+	// many flags are inserted at once.
+	BigQueryFlagRecordTable string
+	BigQueryFlagActorTable  string
+	BigQueryFlagBatchSize   int
 
 	OzonePdsHost    string
 	OzoneIdentifier string
 	OzonePassword   string
 	OzoneProxyDid   string
-
+	// OzoneRateLimit is how many events per second are sent to Ozone, in bursts of up to
+	// OzoneRateBurst, so that bursty rules can't exhaust the labeler's rate limits. Zero disables it.
+	OzoneRateLimit float64
+	OzoneRateBurst int
+	// OzoneSessionStore persists the Ozone session across restarts, in OzoneSessionPath for
+	// SessionStoreFile, or in the MemcacheServers or PostgresURL of the action store. Every start
+	// logs in again when unset.
+	OzoneSessionStore string
+	OzoneSessionPath  string
+	// OzoneAuth is how the effector authenticates to the labeler's PDS, either OzoneAuthPassword
+	// with OzonePassword or OzoneAuthOAuth with OzoneOAuth. OAuth requires OzoneSessionStore.
+	OzoneAuth  string
+	OzoneOAuth *OzoneOAuthArgs
+	// OzoneStandbyIdentifier and OzoneStandbyPassword are a second account on the labeler, on
+	// OzoneStandbyPdsHost or OzonePdsHost, that events fail over to when the primary account is
+	// rate limited or locked out. Disabled when unset.
+	OzoneStandbyPdsHost    string
+	OzoneStandbyIdentifier string
+	OzoneStandbyPassword   string
+
+	// ActionStore is where taken actions are recorded, either ActionStoreMemcache or
+	// ActionStorePostgres. Defaults to memcache.
+	ActionStore     string
 	MemcacheServers []string
+	// MemcacheMigrateLegacyKeys counts actions recorded in memcache before their keys included the
+	// effect kind and value, so that upgrading doesn't repeat them.
+	MemcacheMigrateLegacyKeys bool
+	PostgresURL               string
+	// ActionDefaultTTL is how long actions without an expiration are recorded for, after which a
+	// rule may take them again. Zero records them forever.
+	ActionDefaultTTL time.Duration
 
 	IsProduction bool
 
 	SlackWebhookURL string
+	// SlackRoutesPath is a JSON file containing a list of SlackRoutes, which send effects of some
+	// rules or kinds to other channels than SlackWebhookURL.
+	SlackRoutesPath string
+	// SlackBatchWindow is how long further effects of the same rules are summarized for after one
+	// is posted, and SlackDigestInterval is how often digest routes get their summaries.
+	SlackBatchWindow    time.Duration
+	SlackDigestInterval time.Duration
+	DiscordWebhookURL   string
+
+	// WebhookURL receives signed batches of effect logs, and event logs with WebhookIncludeEvents.
+	WebhookURL           string
+	WebhookSecret        string
+	WebhookIncludeEvents bool
+
+	// LogPostgresURL logs events and effects to Postgres, for deployments without BigQuery.
+	LogPostgresURL string
+
+	// OpenSearchURL indexes effects, and events with OpenSearchIncludeEvents, into OpenSearch.
+	OpenSearchURL           string
+	OpenSearchUsername      string
+	OpenSearchPassword      string
+	OpenSearchIndexPrefix   string
+	OpenSearchIncludeEvents bool
+
+	// SentryDSN reports effects that failed to apply to Sentry, tagged with Environment.
+	SentryDSN string
+
+	// Environment is the name of the deployment, e.g. production, which comment templates and error
+	// reports can tell deployments apart by.
+	Environment string
+	// CommentTemplatesPath is a JSON file of Go templates for the comments on Ozone events, by effect
+	// kind. See loadCommentTemplates.
+	CommentTemplatesPath string
+
+	// OutcomesTopic receives an EffectOutcome for each event sent to Ozone, when set.
+	OutcomesTopic string
+
+	// LabelExpiryTopic receives a LabelExpiredEvent, as Osprey input, for each temporary label that
+	// expires, when set. It should be the topic Osprey consumes. The labels waiting to expire are
+	// kept in LabelExpirySnapshotPath across restarts.
+	LabelExpiryTopic        string
+	LabelExpirySnapshotPath string
+
+	// DelayedEffectsSnapshotPath keeps delayed effects that are still waiting across restarts,
+	// including the removals of tags applied with an expiration. They're lost on restart when unset.
+	DelayedEffectsSnapshotPath string
+
+	// RetryMaxAttempts is how many times an effect is attempted before it's dead-lettered. Zero
+	// disables retries, so failed effects are only logged.
+	RetryMaxAttempts int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+
+	// Workers is how many events are handled at once, and WorkerQueueSize how many can wait per
+	// worker before the consumer is held up. Events for the same account are handled in order.
+	Workers         int
+	WorkerQueueSize int
+
+	// AllowedEffects restricts the effect kinds that are executed to these, see EffectKinds.
+	// Everything is allowed when empty.
+	AllowedEffects []string
+
+	// RuleModesPath is a JSON file mapping rule names to "enforce", "shadow", or a rollout
+	// percentage. Shadowed effects are logged but not applied.
+	RuleModesPath string
+
+	// TestSubjectDids are accounts whose effects are applied even outside of production.
+	TestSubjectDids []string
+
+	// CheckSubjectStatus looks up the subject in Ozone before labeling or taking it down, and skips
+	// the action when it's already in place. This avoids duplicate mod events when the action store
+	// has lost its state, at the cost of an extra request per action.
+	CheckSubjectStatus bool
+
+	// ShutdownTimeout is how long events being handled are given to finish when shutting down,
+	// before the loggers are closed regardless. Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+	// BacklogAlertThreshold is how long after Osprey sent them events can be handled before
+	// alerting that the effector has fallen behind. Zero disables the alert.
+	BacklogAlertThreshold time.Duration
+	// RuleGuardMultiple pauses a rule's effects when it acts at more than this many times its
+	// baseline rate over the last hour, alerting that it did. Zero disables the guard.
+	RuleGuardMultiple float64
+	// RuleGuardMinEffects is the fewest effects a rule has to cause in five minutes before the guard
+	// trips for it.
+	RuleGuardMinEffects int
+
+	// AdminListenAddr serves the admin API, which requires AdminToken as a bearer token. The admin
+	// API is disabled when unset.
+	AdminListenAddr string
+	AdminToken      string
+	// HistoryToken is accepted by the action history API, served alongside the admin API, in
+	// addition to AdminToken.
+	HistoryToken string
+	// RecentActionsSize is how many of the last events handled, and their effects, are kept for the
+	// admin API. Zero keeps none.
+	RecentActionsSize int
 }
 
 func New(args *Args) (*OspreyEffector, error) {
@@ -100,12 +306,45 @@ func New(args *Args) (*OspreyEffector, error) {
 
 	loginCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+
+	sessionStore, err := NewSessionStore(loginCtx, args.OzoneSessionStore, args.OzoneSessionPath, args.MemcacheServers, args.PostgresURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var oauthArgs *OzoneOAuthArgs
+	switch args.OzoneAuth {
+	case "", OzoneAuthPassword:
+	case OzoneAuthOAuth:
+		oauthArgs = args.OzoneOAuth
+	default:
+		return nil, fmt.Errorf("unknown ozone auth %q", args.OzoneAuth)
+	}
+
+	var standbyArgs *OzoneStandbyArgs
+	if args.OzoneStandbyIdentifier != "" {
+		if args.OzoneStandbyPassword == "" {
+			return nil, errors.New("must supply a password for the standby ozone account")
+		}
+		standbyArgs = &OzoneStandbyArgs{
+			PdsHost:    args.OzoneStandbyPdsHost,
+			Identifier: args.OzoneStandbyIdentifier,
+			Password:   args.OzoneStandbyPassword,
+		}
+	}
+
 	oc, err := NewOzoneClient(loginCtx, &OzoneClientArgs{
-		PdsHost:      args.OzonePdsHost,
-		Identifier:   args.OzoneIdentifier,
-		Password:     args.OzonePassword,
-		ProxyDid:     args.OzoneProxyDid,
-		IsProduction: args.IsProduction,
+		PdsHost:         args.OzonePdsHost,
+		Identifier:      args.OzoneIdentifier,
+		Password:        args.OzonePassword,
+		ProxyDid:        args.OzoneProxyDid,
+		IsProduction:    args.IsProduction,
+		TestSubjectDids: args.TestSubjectDids,
+		RateLimit:       args.OzoneRateLimit,
+		RateBurst:       args.OzoneRateBurst,
+		SessionStore:    sessionStore,
+		OAuth:           oauthArgs,
+		Standby:         standbyArgs,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not create ozone client: %w", err)
@@ -120,20 +359,95 @@ func New(args *Args) (*OspreyEffector, error) {
 		args.ConsumerGroup = "osprey-effector-staging-consumers"
 	}
 
-	memcli := memcache.New(args.MemcacheServers...)
-
-	if err := memcli.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping memcache servers: %w", err)
+	var actionStore ActionStore
+	switch args.ActionStore {
+	case "", ActionStoreMemcache:
+		if len(args.MemcacheServers) == 0 {
+			return nil, errors.New("must supply memcache servers to use the memcache action store")
+		}
+		actionStore, err = NewMemcacheActionStore(args.MemcacheServers, args.MemcacheMigrateLegacyKeys)
+		if err != nil {
+			return nil, err
+		}
+	case ActionStorePostgres:
+		if args.PostgresURL == "" {
+			return nil, errors.New("must supply a postgres url to use the postgres action store")
+		}
+		pgCtx, pgCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer pgCancel()
+		actionStore, err = NewPostgresActionStore(pgCtx, args.PostgresURL, logger)
+		if err != nil {
+			return nil, fmt.Errorf("could not create postgres action store: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown action store %q", args.ActionStore)
 	}
+	logger.Info("initialized action store", "store", cmp.Or(args.ActionStore, ActionStoreMemcache))
 
 	or := &OspreyEffector{
 		logger: args.Logger,
 
-		ozoneClient: oc,
-		memClient:   memcli,
+		ozoneClient:      oc,
+		actionStore:      actionStore,
+		actionDefaultTTL: args.ActionDefaultTTL,
+
+		pauseGate:    newPauseGate(),
+		killSwitches: newKillSwitches(),
+		suppressions: newSuppressions(),
+		ruleCounts:   newRuleCounts(),
+
+		checkSubjectStatus: args.CheckSubjectStatus,
+		shutdownTimeout:    cmp.Or(args.ShutdownTimeout, 30*time.Second),
+		backlogThreshold:   args.BacklogAlertThreshold,
 
 		isProduction: args.IsProduction,
 	}
+	oc.dryRun = or.logDryRun
+	oc.sent = or.recordSent
+	oc.failover = or.alertOzoneFailover
+
+	if args.RuleGuardMultiple != 0 {
+		if args.RuleGuardMultiple <= 1 {
+			return nil, errors.New("rule guard multiple must be more than 1")
+		}
+		or.ruleGuard = newRuleGuard(args.RuleGuardMultiple, args.RuleGuardMinEffects)
+	}
+
+	or.commentTemplates, err = loadCommentTemplates(args.CommentTemplatesPath, args.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args.AllowedEffects) > 0 {
+		allowed, err := newEffectAllowlist(args.AllowedEffects)
+		if err != nil {
+			return nil, err
+		}
+		or.allowedEffects = allowed
+		logger.Info("restricting effects to allowlist", "allowed_effects", args.AllowedEffects)
+	}
+
+	if args.RuleModesPath != "" {
+		modes, err := loadRuleModes(args.RuleModesPath)
+		if err != nil {
+			return nil, err
+		}
+		or.ruleModes = modes
+		logger.Info("loaded rule modes", "path", args.RuleModesPath, "rules", len(modes))
+	}
+
+	if args.RecentActionsSize > 0 {
+		or.recentActions = newRecentActions(args.RecentActionsSize)
+	}
+
+	if args.AdminListenAddr != "" {
+		if args.AdminToken == "" {
+			return nil, errors.New("admin token is required to serve the admin api")
+		}
+		httpd, e := newAdminServer(logger, args.AdminListenAddr)
+		or.addAdminRoutes(e, args.AdminToken, args.HistoryToken)
+		or.adminHttpd = httpd
+	}
 
 	lm := NewOspreyLogManager()
 
@@ -150,11 +464,95 @@ func New(args *Args) (*OspreyEffector, error) {
 		}
 		lm.AddLogger(bql)
 		or.bigQueryLogger = bql
+		or.history = bql
 	}
 
 	// Add a Slack channel logger
-	if args.SlackWebhookURL != "" {
-		lm.AddLogger(NewSlackLogger(args.SlackWebhookURL))
+	if args.SlackWebhookURL != "" || args.SlackRoutesPath != "" {
+		var routes []SlackRoute
+		if args.SlackRoutesPath != "" {
+			routes, err = LoadSlackRoutes(args.SlackRoutesPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		sl := NewSlackLogger(&SlackLoggerArgs{
+			WebhookURL:     args.SlackWebhookURL,
+			Routes:         routes,
+			BatchWindow:    args.SlackBatchWindow,
+			DigestInterval: args.SlackDigestInterval,
+			Logger:         logger,
+		})
+		lm.AddLogger(sl)
+		or.alerters = append(or.alerters, sl)
+		or.slackLogger = sl
+	}
+
+	// Add a Discord channel logger
+	if args.DiscordWebhookURL != "" {
+		dl := NewDiscordLogger(args.DiscordWebhookURL)
+		lm.AddLogger(dl)
+		or.alerters = append(or.alerters, dl)
+	}
+
+	// Report failed effects to Sentry
+	if args.SentryDSN != "" {
+		ss, err := NewSentryErrorSink(&SentryErrorSinkArgs{
+			Dsn:         args.SentryDSN,
+			Environment: args.Environment,
+		})
+		if err != nil {
+			return nil, err
+		}
+		or.errorSinks = append(or.errorSinks, ss)
+	}
+
+	// Add a Postgres logger
+	if args.LogPostgresURL != "" {
+		pgCtx, pgCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer pgCancel()
+		pl, err := NewPostgresLogger(pgCtx, args.LogPostgresURL, logger)
+		if err != nil {
+			return nil, fmt.Errorf("could not create postgres logger: %w", err)
+		}
+		lm.AddLogger(pl)
+		or.postgresLogger = pl
+		// Postgres is preferred for action history, since its lookups are indexed and free.
+		or.history = pl
+	}
+
+	// Add an OpenSearch logger
+	if args.OpenSearchURL != "" {
+		osCtx, osCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer osCancel()
+		osl, err := NewOpenSearchLogger(osCtx, &OpenSearchLoggerArgs{
+			URL:           args.OpenSearchURL,
+			Username:      args.OpenSearchUsername,
+			Password:      args.OpenSearchPassword,
+			IndexPrefix:   args.OpenSearchIndexPrefix,
+			IncludeEvents: args.OpenSearchIncludeEvents,
+			Logger:        logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create opensearch logger: %w", err)
+		}
+		lm.AddLogger(osl)
+		or.openSearchLogger = osl
+	}
+
+	// Add a webhook logger for external systems
+	if args.WebhookURL != "" {
+		wl, err := NewWebhookLogger(&WebhookLoggerArgs{
+			URL:           args.WebhookURL,
+			Secret:        args.WebhookSecret,
+			IncludeEvents: args.WebhookIncludeEvents,
+			Logger:        logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create webhook logger: %w", err)
+		}
+		lm.AddLogger(wl)
+		or.webhookLogger = wl
 	}
 
 	// Add a slog logger for stdout
@@ -162,20 +560,83 @@ func New(args *Args) (*OspreyEffector, error) {
 
 	or.logManager = lm
 
+	if args.Workers <= 0 {
+		args.Workers = 100
+	}
+	if args.WorkerQueueSize <= 0 {
+		args.WorkerQueueSize = 100
+	}
+	or.workers = newWorkerPool(args.Workers, args.WorkerQueueSize)
+
 	busConsumer, err := consumer.New(args.Logger, args.BootstrapServers, args.InputTopic, args.ConsumerGroup,
 		consumer.WithOffset[*osprey.ResultEvent](consumer.OffsetEnd),
-		consumer.WithMessageHandler(or.handleEventAsync),
+		// Each partition is handled in order, one event at a time, so that offsets are only
+		// committed for events that have been handled.
+		consumer.WithInOrderConsumption[*osprey.ResultEvent](),
+		consumer.WithMessageHandler(or.handleMessage),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating bus consumer: %w", err)
 	}
 	or.consumer = busConsumer
 
+	if args.RetryMaxAttempts > 0 {
+		if args.RetryBaseDelay <= 0 || args.RetryMaxDelay < args.RetryBaseDelay {
+			return nil, fmt.Errorf("invalid retry delays: base %s, max %s", args.RetryBaseDelay, args.RetryMaxDelay)
+		}
+		rq, err := or.newRetryQueue(context.Background(), args)
+		if err != nil {
+			return nil, err
+		}
+		or.retries = rq
+		logger.Info("initialized retry queue",
+			"max_attempts", args.RetryMaxAttempts,
+			"base_delay", args.RetryBaseDelay,
+			"max_delay", args.RetryMaxDelay,
+		)
+	}
+
+	if args.OutcomesTopic != "" {
+		p, err := producer.New(context.Background(), args.Logger, args.BootstrapServers, args.OutcomesTopic,
+			producer.WithEnsureTopic[*osprey.EffectOutcome](true),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating outcomes producer: %w", err)
+		}
+		or.outcomeProducer = p
+		logger.Info("producing effect outcomes", "topic", args.OutcomesTopic)
+	}
+
+	de, err := newDelayedEffects(logger, args.DelayedEffectsSnapshotPath)
+	if err != nil {
+		return nil, err
+	}
+	or.delayedEffects = de
+
+	if args.LabelExpiryTopic != "" {
+		p, err := producer.New(context.Background(), args.Logger, args.BootstrapServers, args.LabelExpiryTopic,
+			producer.WithEnsureTopic[*osprey.OspreyInputEvent](true),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating label expiry producer: %w", err)
+		}
+		le, err := newLabelExpiries(logger, p, args.LabelExpirySnapshotPath)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		or.labelExpiries = le
+		logger.Info("producing expired labels", "topic", args.LabelExpiryTopic)
+	}
+
 	if args.IsProduction {
 		bfc, err := NewBigQueryFlagClient(&BigQueryFlagClientArgs{
 			CredentialsJson: args.BigQueryCredentialsJson,
 			ProjectID:       args.BigQueryProjectID,
 			DatasetID:       args.BigQueryDatasetID,
+			RecordTable:     args.BigQueryFlagRecordTable,
+			ActorTable:      args.BigQueryFlagActorTable,
+			BatchSize:       args.BigQueryFlagBatchSize,
 			Logger:          logger,
 		})
 		if err != nil {
@@ -212,31 +673,175 @@ func (or *OspreyEffector) Run(ctx context.Context) error {
 		cancel()
 	}()
 
+	if or.retries != nil {
+		go func() {
+			logger := or.logger.With("component", "retry_consumer")
+			for {
+				err := or.retries.consumer.Consume(context.Background())
+				if err != nil {
+					if errors.Is(err, consumer.ErrClientClosed) {
+						logger.Info("retry consumer client closed, stopping")
+						return
+					}
+					logger.Error("failed to consume retries", "err", err)
+				}
+			}
+		}()
+	}
+
+	expiryCtx, cancelExpiries := context.WithCancel(context.Background())
+	expiriesDone := make(chan struct{})
+	if or.labelExpiries != nil {
+		go func() {
+			or.labelExpiries.run(expiryCtx)
+			close(expiriesDone)
+		}()
+	} else {
+		close(expiriesDone)
+	}
+
+	guardCtx, cancelGuard := context.WithCancel(context.Background())
+	if or.ruleGuard != nil {
+		go or.runRuleGuard(guardCtx)
+	}
+
+	delayedCtx, cancelDelayed := context.WithCancel(context.Background())
+	delayedDone := make(chan struct{})
+	go func() {
+		or.runDelayedEffects(delayedCtx)
+		close(delayedDone)
+	}()
+
+	adminCtx, cancelAdmin := context.WithCancel(context.Background())
+	adminDone := make(chan struct{})
+	if or.adminHttpd != nil {
+		go func() {
+			or.runAdminServer(adminCtx)
+			close(adminDone)
+		}()
+	} else {
+		close(adminDone)
+	}
+
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
 	<-signals
 
+	cancelAdmin()
+	<-adminDone
+	cancelGuard()
+	// Let anything held up by a pause finish, so that shutting down doesn't wait on it.
+	or.pauseGate.resume()
+	cancelDelayed()
+	<-delayedDone
+
 	close(shutdownConsumer)
+	if or.retries != nil {
+		or.retries.stop()
+	}
+	or.drain(consumerShutdown)
+	if or.retries != nil {
+		or.retries.close()
+	}
+	// Stopped after draining, so that labels applied by in-flight events make the final snapshot.
+	cancelExpiries()
+	<-expiriesDone
+	if err := or.delayedEffects.writeSnapshot(); err != nil {
+		or.logger.Error("failed to write final delayed effects snapshot", "err", err)
+	}
+	if or.labelExpiries != nil {
+		or.labelExpiries.producer.Close()
+	}
+	if or.outcomeProducer != nil {
+		or.outcomeProducer.Close()
+	}
 	if or.bigQueryLogger != nil {
 		or.bigQueryLogger.Close()
 	}
+	if or.bigqueryFlagClient != nil {
+		or.bigqueryFlagClient.Close()
+	}
+	if or.webhookLogger != nil {
+		or.webhookLogger.Close()
+	}
+	if or.postgresLogger != nil {
+		or.postgresLogger.Close()
+	}
+	if or.openSearchLogger != nil {
+		or.openSearchLogger.Close()
+	}
+	if or.slackLogger != nil {
+		or.slackLogger.Close()
+	}
+	for _, s := range or.errorSinks {
+		s.Close()
+	}
+	or.actionStore.Close()
+	or.ozoneClient.Close()
 
 	return nil
 }
 
-func (or *OspreyEffector) handleEventAsync(ctx context.Context, evt *osprey.ResultEvent) error {
+// drain waits for the events and retries being handled to finish, so that their effects are
+// logged before the loggers are closed. It gives up after the shutdown timeout, since effects that
+// haven't finished by then will be replayed from the last committed offset anyway.
+func (or *OspreyEffector) drain(consumerShutdown <-chan struct{}) {
+	done := make(chan struct{})
 	go func() {
-		ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
-		defer cancel()
-
-		if err := or.handleEvent(ctx, evt); err != nil {
-			or.logger.Error("error handling event async", "error", err)
+		<-consumerShutdown
+		or.workers.close()
+		if or.retries != nil {
+			or.retries.handlers.Wait()
 		}
+		close(done)
 	}()
+
+	select {
+	case <-done:
+		or.logger.Info("drained in-flight effects")
+	case <-time.After(or.shutdownTimeout):
+		or.logger.Warn("timed out waiting for in-flight effects, shutting down anyway",
+			"in_flight", or.workers.inFlight.Load(),
+			"timeout", or.shutdownTimeout,
+		)
+	}
+}
+
+// handleMessage hands the event to the worker pool and waits for it to be handled. The consumer
+// commits the offset once this returns, so waiting means an event is only acknowledged after its
+// effects have been applied or the ones that failed have been queued for retry, and a crash
+// replays whatever was in flight instead of losing it. Replayed effects are skipped by the action
+// store if they were already taken. Partitions are consumed in parallel, so events on different
+// partitions share the workers.
+func (or *OspreyEffector) handleMessage(ctx context.Context, evt *osprey.ResultEvent) error {
+	if evt == nil {
+		or.logger.Warn("attempted to handle nil event")
+		return nil
+	}
+
+	if err := or.pauseGate.wait(ctx); err != nil {
+		return err
+	}
+
+	done, err := or.workers.submit(ctx, eventSubject(evt), func() { or.handleEventWorker(evt) })
+	if err != nil {
+		return err
+	}
+	<-done
+
 	return nil
 }
 
+func (or *OspreyEffector) handleEventWorker(evt *osprey.ResultEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := or.handleEvent(ctx, evt); err != nil {
+		or.logger.Error("error handling event", "error", err)
+	}
+}
+
 func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEvent) error {
 	if evt == nil {
 		or.logger.Warn("attempted to handle nil event")
@@ -244,6 +849,7 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 	}
 
 	eventsReceived.WithLabelValues(evt.ActionName).Inc()
+	or.checkBacklog(ctx, evt)
 
 	status := "error"
 	defer func() {
@@ -269,6 +875,45 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 		}
 	}
 
+	or.recentActions.add(evt)
+	or.handleDelayedEffects(evt)
+
+	or.dropInvalidMessageEffects(evt)
+	or.dropBlockedEffects(evt)
+	or.dropSuppressedEffects(evt)
+
+	if shadow := or.splitShadowEffects(evt); shadow != nil {
+		or.logShadowEffects(shadow)
+	}
+
+	failed, err := or.applyEffects(ctx, evt)
+	if err != nil {
+		or.scheduleRetry(failed, 1, err)
+	}
+	or.recentActions.finish(evt.ActionId, err)
+
+	status = "ok"
+
+	return nil
+}
+
+// applyEffects applies each of the event's effects. Effects that fail are returned in an event of
+// their own along with the joined errors, so that they can be retried.
+func (or *OspreyEffector) applyEffects(ctx context.Context, evt *osprey.ResultEvent) (*osprey.ResultEvent, error) {
+	failed := &osprey.ResultEvent{
+		SendTime:   evt.SendTime,
+		ActionName: evt.ActionName,
+		ActionId:   evt.ActionId,
+		Did:        evt.Did,
+		Uri:        evt.Uri,
+		Cid:        evt.Cid,
+		Message:    evt.Message,
+		Evidence:   evt.Evidence,
+	}
+	var errs []error
+
+	ctx = context.WithValue(ctx, actionNameKey{}, evt.ActionName)
+
 	for _, e := range evt.Labels {
 		ozoneStatus := "error"
 		defer func() {
@@ -276,59 +921,81 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 		}()
 
 		rules := strings.Join(e.Rules, ",")
+		action := actionName("label", AtprotoLabelToString(e.Label), e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)
 
-		e.Comment = fmt.Sprintf("Actioned by rules %s\n\n%s", rules, e.Comment)
+		comment := or.effectComment(evt, EffectLabel, e.Rules, e.Comment)
 
 		switch e.SubjectKind {
 		// Label actors
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
-			if or.checkHasActioned(evt.Did, rules, e.ExpirationInHours) {
+			key := ActionKey{Subject: evt.Did, Action: action, Rules: rules, ExpirationInHours: e.ExpirationInHours}
+			if or.checkHasActioned(ctx, key) {
 				or.logger.Info("skipping ozone label effect", "actionId", evt.ActionId)
 				ozoneStatus = "skipped"
 				continue
 			}
+			if or.alreadyInPlace(ctx, evt.Did, labelInPlace(AtprotoLabelToString(e.Label), e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)) {
+				or.logger.Info("skipping ozone label effect that's already in place", "actionId", evt.ActionId)
+				ozoneStatus = "skipped-existing"
+				continue
+			}
 
+			callCtx, sent := withSentEvent(ctx)
 			if err := or.ozoneClient.LabelActor(
-				ctx,
+				callCtx,
 				evt.Did,
 				ModToolMeta{
 					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
 				e.Label,
-				e.Comment,
+				comment,
 				e.Email,
 				e.ExpirationInHours,
 				e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
 			); err != nil {
 				or.logger.Error("error processing actor label effects", "error", err)
+				or.forgetAction(key)
+				failed.Labels = append(failed.Labels, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
-				or.logManager.LogEffect(context.Background(), &OspreyEffectLog{
+				or.invalidateAction(ctx, evt.Did, oppositeAction(action))
+				or.trackLabelExpiry(evt, e)
+				or.logEffect(&OspreyEffectLog{
 					ActionName: evt.ActionName,
 					ActionID:   evt.ActionId,
 					Subject:    evt.Did,
 					Kind:       "label",
-					Comment:    e.Comment,
+					Comment:    comment,
 					Label: bigquery.NullString{
 						StringVal: AtprotoLabelToString(e.Label),
 						Valid:     true,
 					},
-					CreatedAt: time.Now(),
-					Rules:     strings.Join(e.Rules, ","),
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 
 		// Label records
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
-			if or.checkHasActioned(evt.Uri, rules, e.ExpirationInHours) {
+			key := ActionKey{Subject: evt.Uri, Action: action, Rules: rules, ExpirationInHours: e.ExpirationInHours}
+			if or.checkHasActioned(ctx, key) {
 				or.logger.Info("skipping ozone label effect", "actionId", evt.ActionId)
 				ozoneStatus = "skipped"
 				continue
 			}
+			if or.alreadyInPlace(ctx, evt.Uri, labelInPlace(AtprotoLabelToString(e.Label), e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)) {
+				or.logger.Info("skipping ozone label effect that's already in place", "actionId", evt.ActionId)
+				ozoneStatus = "skipped-existing"
+				continue
+			}
 
+			callCtx, sent := withSentEvent(ctx)
 			if err := or.ozoneClient.LabelRecord(
-				ctx,
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
@@ -336,199 +1003,309 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 					ActionID: evt.ActionId,
 				},
 				e.Label,
-				e.Comment,
+				comment,
 				e.Email,
 				e.ExpirationInHours,
 				e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
 			); err != nil {
 				or.logger.Error("error processing record label effects", "error", err)
+				or.forgetAction(key)
+				failed.Labels = append(failed.Labels, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
+				or.invalidateAction(ctx, evt.Uri, oppositeAction(action))
+				or.trackLabelExpiry(evt, e)
 				or.logEffect(&OspreyEffectLog{
 					ActionName: evt.ActionName,
 					ActionID:   evt.ActionId,
 					Subject:    evt.Uri,
 					Kind:       "label",
-					Comment:    e.Comment,
+					Comment:    comment,
 					Label: bigquery.NullString{
 						StringVal: AtprotoLabelToString(e.Label),
 						Valid:     true,
 					},
-					CreatedAt: time.Now(),
-					Rules:     strings.Join(e.Rules, ","),
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
-	for _, e := range evt.Tags {
+	errs = append(errs, or.applyTagsAndComments(ctx, evt, failed)...)
+
+	for _, e := range evt.Takedowns {
 		ozoneStatus := "error"
 		defer func() {
-			ozoneRequests.WithLabelValues("tag", e.SubjectKind.String(), ozoneStatus).Inc()
+			ozoneRequests.WithLabelValues("takedown", e.SubjectKind.String(), ozoneStatus).Inc()
 		}()
 
 		rules := strings.Join(e.Rules, ",")
+		action := actionName("takedown", "", e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)
 
-		comment := fmt.Sprintf("Actioned by rules %s", rules)
-		if e.Comment != nil {
-			comment = fmt.Sprintf("%s\n\n%s", comment, *e.Comment)
-		}
+		comment := or.effectComment(evt, EffectTakedown, e.Rules, e.Comment)
 
 		switch e.SubjectKind {
-		// Tag actors
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
-			if or.checkHasActioned(evt.Did, rules, nil) {
-				or.logger.Info("skipping ozone tag effect", "actionId", evt.ActionId)
+			key := ActionKey{Subject: evt.Did, Action: action, Rules: rules, ExpirationInHours: e.DurationInHours}
+			if or.checkHasActioned(ctx, key) {
+				or.logger.Info("skipping ozone takedown effect", "actionId", evt.ActionId)
 				ozoneStatus = "skipped"
 				continue
 			}
+			if or.alreadyInPlace(ctx, evt.Did, takedownInPlace(e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)) {
+				or.logger.Info("skipping ozone takedown effect that's already in place", "actionId", evt.ActionId)
+				ozoneStatus = "skipped-existing"
+				continue
+			}
 
-			if err := or.ozoneClient.TagActor(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.TakedownActor(
+				callCtx,
 				evt.Did,
 				ModToolMeta{
 					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
-				e.Tag,
-				e.Comment,
+				comment,
+				e.Email,
+				e.Policies,
+				e.DurationInHours,
 				e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
 			); err != nil {
-				or.logger.Error("error processing actor tag effects", "error", err)
+				or.logger.Error("error processing actor takedown effects", "error", err)
+				or.forgetAction(key)
+				failed.Takedowns = append(failed.Takedowns, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
+				or.invalidateAction(ctx, evt.Did, oppositeAction(action))
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Did,
-					Kind:       "tag",
-					Comment:    comment,
-					Tag: bigquery.NullString{
-						StringVal: e.Tag,
-						Valid:     true,
-					},
-					CreatedAt: time.Now(),
-					Rules:     strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Did,
+					Kind:         "takedown",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
-
-		// Tag records
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
-			if or.checkHasActioned(evt.Uri, rules, nil) {
-				or.logger.Info("skipping ozone tag effect", "actionId", evt.ActionId)
+			key := ActionKey{Subject: evt.Uri, Action: action, Rules: rules, ExpirationInHours: e.DurationInHours}
+			if or.checkHasActioned(ctx, key) {
+				or.logger.Info("skipping ozone takedown effect", "actionId", evt.ActionId)
 				ozoneStatus = "skipped"
 				continue
 			}
+			if or.alreadyInPlace(ctx, evt.Uri, takedownInPlace(e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE)) {
+				or.logger.Info("skipping ozone takedown effect that's already in place", "actionId", evt.ActionId)
+				ozoneStatus = "skipped-existing"
+				continue
+			}
 
-			if err := or.ozoneClient.TagRecord(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.TakedownRecord(
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
 					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
-				e.Tag,
-				e.Comment,
+				comment,
+				e.Email,
+				e.Policies,
+				e.DurationInHours,
 				e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
 			); err != nil {
-				or.logger.Error("error processing actor tag effects", "error", err)
+				or.logger.Error("error processing record takedown effects", "error", err)
+				or.forgetAction(key)
+				failed.Takedowns = append(failed.Takedowns, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
+				or.invalidateAction(ctx, evt.Uri, oppositeAction(action))
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Uri,
-					Kind:       "tag",
-					Comment:    comment,
-					Tag: bigquery.NullString{
-						StringVal: e.Tag,
-						Valid:     true,
-					},
-					CreatedAt: time.Now(),
-					Rules:     strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Uri,
+					Kind:         "takedown",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
-	for _, e := range evt.Takedowns {
+	for _, e := range evt.Mutes {
 		ozoneStatus := "error"
 		defer func() {
-			ozoneRequests.WithLabelValues("takedown", e.SubjectKind.String(), ozoneStatus).Inc()
+			ozoneRequests.WithLabelValues("mute", e.SubjectKind.String(), ozoneStatus).Inc()
 		}()
 
 		rules := strings.Join(e.Rules, ",")
+		unmute := e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE
+		action := actionName("mute", "", unmute)
 
-		e.Comment = fmt.Sprintf("Actioned by rules %s\n\n%s", rules, e.Comment)
+		// Mutes are recorded for as long as they last, so that an expired mute can be applied again.
+		var expirationInHours *int64
+		if !unmute {
+			expirationInHours = &e.DurationInHours
+		}
+
+		comment := or.effectComment(evt, EffectMute, e.Rules, e.GetComment())
 
 		switch e.SubjectKind {
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
-			if or.checkHasActioned(evt.Did, rules, nil) {
-				or.logger.Info("skipping ozone takedown effect", "actionId", evt.ActionId)
+			key := ActionKey{Subject: evt.Did, Action: action, Rules: rules, ExpirationInHours: expirationInHours}
+			if or.checkHasActioned(ctx, key) {
+				or.logger.Info("skipping ozone mute effect", "actionId", evt.ActionId)
 				ozoneStatus = "skipped"
 				continue
 			}
 
-			if err := or.ozoneClient.TakedownActor(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.MuteActor(
+				callCtx,
 				evt.Did,
 				ModToolMeta{
 					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
-				e.Email,
-				e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
+				&comment,
+				e.DurationInHours,
+				unmute,
 			); err != nil {
-				or.logger.Error("error processing actor takedown effects", "error", err)
+				or.logger.Error("error processing actor mute effects", "error", err)
+				or.forgetAction(key)
+				failed.Mutes = append(failed.Mutes, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
+				or.invalidateAction(ctx, evt.Did, oppositeAction(action))
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Did,
-					Kind:       "takedown",
-					Comment:    e.Comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Did,
+					Kind:         "mute",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        rules,
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
-			if or.checkHasActioned(evt.Uri, rules, nil) {
-				or.logger.Info("skipping ozone takedown effect", "actionId", evt.ActionId)
+			key := ActionKey{Subject: evt.Uri, Action: action, Rules: rules, ExpirationInHours: expirationInHours}
+			if or.checkHasActioned(ctx, key) {
+				or.logger.Info("skipping ozone mute effect", "actionId", evt.ActionId)
 				ozoneStatus = "skipped"
 				continue
 			}
 
-			if err := or.ozoneClient.TakedownRecord(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.MuteRecord(
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
 					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
-				e.Email,
-				e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
+				&comment,
+				e.DurationInHours,
+				unmute,
 			); err != nil {
-				or.logger.Error("error processing record takedown effects", "error", err)
+				or.logger.Error("error processing record mute effects", "error", err)
+				or.forgetAction(key)
+				failed.Mutes = append(failed.Mutes, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
+				or.invalidateAction(ctx, evt.Uri, oppositeAction(action))
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Uri,
-					Kind:       "takedown",
-					Comment:    e.Comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Uri,
+					Kind:         "mute",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        rules,
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
+	for _, e := range evt.Diverts {
+		ozoneStatus := "error"
+		defer func() {
+			ozoneRequests.WithLabelValues("divert", e.SubjectKind.String(), ozoneStatus).Inc()
+		}()
+
+		rules := strings.Join(e.Rules, ",")
+
+		if e.SubjectKind != osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD {
+			or.logger.Warn("skipping divert effect on a subject that isn't a record", "actionId", evt.ActionId, "subjectKind", e.SubjectKind.String())
+			ozoneStatus = "invalid"
+			continue
+		}
+
+		comment := or.effectComment(evt, EffectDivert, e.Rules, e.GetComment())
+
+		blobCids := slices.Sorted(slices.Values(e.BlobCids))
+		key := ActionKey{Subject: evt.Uri, Action: actionName("divert", strings.Join(blobCids, ","), false), Rules: rules}
+		if or.checkHasActioned(ctx, key) {
+			or.logger.Info("skipping ozone divert effect", "actionId", evt.ActionId)
+			ozoneStatus = "skipped"
+			continue
+		}
+
+		callCtx, sent := withSentEvent(ctx)
+		if err := or.ozoneClient.DivertRecord(
+			callCtx,
+			evt.Uri,
+			evt.Cid,
+			blobCids,
+			ModToolMeta{
+				Rules:    rules,
+				ActionID: evt.ActionId,
+			},
+			&comment,
+		); err != nil {
+			or.logger.Error("error processing record divert effects", "error", err)
+			or.forgetAction(key)
+			if errors.Is(err, errInvalidDivert) {
+				ozoneStatus = "invalid"
+				continue
+			}
+			failed.Diverts = append(failed.Diverts, e)
+			errs = append(errs, err)
+		} else {
+			ozoneStatus = "ok"
+			or.logEffect(&OspreyEffectLog{
+				ActionName:   evt.ActionName,
+				ActionID:     evt.ActionId,
+				Subject:      evt.Uri,
+				Kind:         "divert",
+				Comment:      comment,
+				CreatedAt:    time.Now(),
+				Rules:        rules,
+				OzoneEventID: sent.eventID(),
+				dryRun:       sent.dryRun,
+			})
+		}
+	}
+
 	for _, e := range evt.Reports {
 		ozoneStatus := "error"
 		defer func() {
@@ -537,38 +1314,44 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 
 		rules := strings.Join(e.Rules, ",")
 
-		e.Comment = fmt.Sprintf("Actioned by rules %s\n\n%s", rules, e.Comment)
+		comment := or.effectComment(evt, EffectReport, e.Rules, e.Comment)
 
 		// NOTE: Purposefully do not ignore duplicate actions for reports
 		switch e.SubjectKind {
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
+			callCtx, sent := withSentEvent(ctx)
 			if err := or.ozoneClient.ReportActor(
-				ctx,
+				callCtx,
 				evt.Did,
 				ModToolMeta{
 					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
 				e.ReportKind,
-				e.Comment,
+				comment,
 				e.PriorityScore,
 			); err != nil {
 				or.logger.Error("error processing actor report effects", "error", err)
+				failed.Reports = append(failed.Reports, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Did,
-					Kind:       "report",
-					Comment:    e.Comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Did,
+					Kind:         "report",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
+			callCtx, sent := withSentEvent(ctx)
 			if err := or.ozoneClient.ReportRecord(
-				ctx,
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
@@ -576,248 +1359,425 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 					ActionID: evt.ActionId,
 				},
 				e.ReportKind,
-				e.Comment,
+				comment,
 				e.PriorityScore,
 			); err != nil {
 				or.logger.Error("error processing record report effects", "error", err)
+				failed.Reports = append(failed.Reports, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Uri,
-					Kind:       "report",
-					Comment:    e.Comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Uri,
+					Kind:         "report",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
+				})
+			}
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE:
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.ReportMessage(
+				callCtx,
+				evt.Message,
+				ModToolMeta{
+					Rules:    rules,
+					ActionID: evt.ActionId,
+				},
+				e.ReportKind,
+				comment,
+				e.PriorityScore,
+			); err != nil {
+				or.logger.Error("error processing message report effects", "error", err)
+				failed.Reports = append(failed.Reports, e)
+				errs = append(errs, err)
+			} else {
+				ozoneStatus = "ok"
+				or.logEffect(&OspreyEffectLog{
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      MessageSubject(evt.Message),
+					Kind:         "report",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
-	for _, e := range evt.Comments {
+	for _, e := range evt.Escalations {
 		ozoneStatus := "error"
 		defer func() {
 			ozoneRequests.WithLabelValues("comment", e.SubjectKind.String(), ozoneStatus).Inc()
 		}()
 
-		rules := strings.Join(e.Rules, ",")
+		comment := or.effectComment(evt, EffectEscalation, e.Rules, e.GetComment())
 
-		e.Comment = fmt.Sprintf("Actioned by rules %s\n\n%s", rules, e.Comment)
+		var queueTag bigquery.NullString
+		if e.GetQueue() != "" {
+			queueTag = bigquery.NullString{StringVal: escalationQueueTagPrefix + e.GetQueue(), Valid: true}
+		}
 
+		// NOTE: Purposefully do not prevent duplicate actions for escalations
 		switch e.SubjectKind {
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
-			if or.checkHasActioned(evt.Did, rules, nil) {
-				or.logger.Info("skipping ozone comment effect", "actionId", evt.ActionId)
-				ozoneStatus = "skipped"
-				continue
-			}
-
-			if err := or.ozoneClient.CommentActor(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.EscalateActor(
+				callCtx,
 				evt.Did,
 				ModToolMeta{
-					Rules:    rules,
+					Rules:    strings.Join(e.Rules, ","),
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
+				&comment,
+				e.GetQueue(),
 			); err != nil {
-				or.logger.Error("error processing actor comment effects", "error", err)
+				or.logger.Error("error processing actor escalation effects", "error", err)
+				failed.Escalations = append(failed.Escalations, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Did,
-					Kind:       "comment",
-					Comment:    e.Comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Did,
+					Kind:         "escalation",
+					Comment:      comment,
+					Tag:          queueTag,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
-			if or.checkHasActioned(evt.Uri, rules, nil) {
-				or.logger.Info("skipping ozone comment effect", "actionId", evt.ActionId)
-				ozoneStatus = "skipped"
-				continue
-			}
-
-			if err := or.ozoneClient.CommentRecord(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.EscalateRecord(
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
-					Rules:    rules,
+					Rules:    strings.Join(e.Rules, ","),
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
+				&comment,
+				e.GetQueue(),
 			); err != nil {
-				or.logger.Error("error processing record comment effects", "error", err)
+				or.logger.Error("error processing record escalation effects", "error", err)
+				failed.Escalations = append(failed.Escalations, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Uri,
-					Kind:       "comment",
-					Comment:    e.Comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Uri,
+					Kind:         "escalation",
+					Comment:      comment,
+					Tag:          queueTag,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
+				})
+			}
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE:
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.EscalateMessage(
+				callCtx,
+				evt.Message,
+				ModToolMeta{
+					Rules:    strings.Join(e.Rules, ","),
+					ActionID: evt.ActionId,
+				},
+				&comment,
+				e.GetQueue(),
+			); err != nil {
+				or.logger.Error("error processing message escalation effects", "error", err)
+				failed.Escalations = append(failed.Escalations, e)
+				errs = append(errs, err)
+			} else {
+				ozoneStatus = "ok"
+				or.logEffect(&OspreyEffectLog{
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      MessageSubject(evt.Message),
+					Kind:         "escalation",
+					Comment:      comment,
+					Tag:          queueTag,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
-	for _, e := range evt.Escalations {
+	for _, e := range evt.Acknowledgements {
 		ozoneStatus := "error"
 		defer func() {
 			ozoneRequests.WithLabelValues("comment", e.SubjectKind.String(), ozoneStatus).Inc()
 		}()
 
-		comment := fmt.Sprintf("Actioned by rules %s", strings.Join(e.Rules, ","))
-		if e.Comment != nil {
-			comment = fmt.Sprintf("%s\n\n%s", comment, *e.Comment)
-		}
+		comment := or.effectComment(evt, EffectAcknowledgement, e.Rules, e.GetComment())
 
-		// NOTE: Purposefully do not prevent duplicate actions for escalations
+		// NOTE: Purposefully do not ignore duplicate actions for acks
 		switch e.SubjectKind {
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
-			if err := or.ozoneClient.EscalateActor(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.AcknowledgeActor(
+				callCtx,
 				evt.Did,
 				ModToolMeta{
 					Rules:    strings.Join(e.Rules, ","),
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
+				&comment,
 			); err != nil {
-				or.logger.Error("error processing actor escalation effects", "error", err)
+				or.logger.Error("error processing actor acknowledgement effects", "error", err)
+				failed.Acknowledgements = append(failed.Acknowledgements, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Did,
-					Kind:       "escalation",
-					Comment:    comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Did,
+					Kind:         "acknowledgement",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
-			if err := or.ozoneClient.EscalateRecord(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.AcknowledgeRecord(
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
 					Rules:    strings.Join(e.Rules, ","),
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
+				&comment,
 			); err != nil {
-				or.logger.Error("error processing record escalation effects", "error", err)
+				or.logger.Error("error processing record acknowledgement effects", "error", err)
+				failed.Acknowledgements = append(failed.Acknowledgements, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Uri,
-					Kind:       "escalation",
-					Comment:    comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Uri,
+					Kind:         "acknowledgement",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
+				})
+			}
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE:
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.AcknowledgeMessage(
+				callCtx,
+				evt.Message,
+				ModToolMeta{
+					Rules:    strings.Join(e.Rules, ","),
+					ActionID: evt.ActionId,
+				},
+				&comment,
+			); err != nil {
+				or.logger.Error("error processing message acknowledgement effects", "error", err)
+				failed.Acknowledgements = append(failed.Acknowledgements, e)
+				errs = append(errs, err)
+			} else {
+				ozoneStatus = "ok"
+				or.logEffect(&OspreyEffectLog{
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      MessageSubject(evt.Message),
+					Kind:         "acknowledgement",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        strings.Join(e.Rules, ","),
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
-	for _, e := range evt.Acknowledgements {
+	for _, e := range evt.ResolveAppeals {
 		ozoneStatus := "error"
 		defer func() {
-			ozoneRequests.WithLabelValues("comment", e.SubjectKind.String(), ozoneStatus).Inc()
+			ozoneRequests.WithLabelValues("resolve-appeal", e.SubjectKind.String(), ozoneStatus).Inc()
 		}()
 
-		comment := fmt.Sprintf("Actioned by rules %s", strings.Join(e.Rules, ","))
-		if e.Comment != nil {
-			comment = fmt.Sprintf("%s\n\n%s", comment, *e.Comment)
-		}
+		rules := strings.Join(e.Rules, ",")
 
-		// NOTE: Purposefully do not ignore duplicate actions for acks
+		comment := or.effectComment(evt, EffectResolveAppeal, e.Rules, e.GetComment())
+
+		// NOTE: Purposefully do not ignore duplicate actions for appeal resolutions, since a subject
+		// can be appealed again after one is resolved
 		switch e.SubjectKind {
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
-			if err := or.ozoneClient.AcknowledgeActor(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.ResolveAppealActor(
+				callCtx,
 				evt.Did,
 				ModToolMeta{
-					Rules:    strings.Join(e.Rules, ","),
+					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
+				&comment,
 			); err != nil {
-				or.logger.Error("error processing actor acknowledgement effects", "error", err)
+				or.logger.Error("error processing actor appeal resolution effects", "error", err)
+				failed.ResolveAppeals = append(failed.ResolveAppeals, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Did,
-					Kind:       "acknowledgement",
-					Comment:    comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Did,
+					Kind:         "resolve-appeal",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        rules,
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
-			if err := or.ozoneClient.EscalateRecord(
-				ctx,
+			callCtx, sent := withSentEvent(ctx)
+			if err := or.ozoneClient.ResolveAppealRecord(
+				callCtx,
 				evt.Uri,
 				evt.Cid,
 				ModToolMeta{
-					Rules:    strings.Join(e.Rules, ","),
+					Rules:    rules,
 					ActionID: evt.ActionId,
 				},
-				e.Comment,
+				&comment,
 			); err != nil {
-				or.logger.Error("error processing record acknowledgement effects", "error", err)
+				or.logger.Error("error processing record appeal resolution effects", "error", err)
+				failed.ResolveAppeals = append(failed.ResolveAppeals, e)
+				errs = append(errs, err)
 			} else {
 				ozoneStatus = "ok"
 				or.logEffect(&OspreyEffectLog{
-					ActionName: evt.ActionName,
-					ActionID:   evt.ActionId,
-					Subject:    evt.Uri,
-					Kind:       "acknowledgement",
-					Comment:    comment,
-					CreatedAt:  time.Now(),
-					Rules:      strings.Join(e.Rules, ","),
+					ActionName:   evt.ActionName,
+					ActionID:     evt.ActionId,
+					Subject:      evt.Uri,
+					Kind:         "resolve-appeal",
+					Comment:      comment,
+					CreatedAt:    time.Now(),
+					Rules:        rules,
+					OzoneEventID: sent.eventID(),
+					dryRun:       sent.dryRun,
 				})
 			}
 		}
 	}
 
-	for _, e := range evt.Emails {
+	for _, e := range evt.Sets {
 		ozoneStatus := "error"
 		defer func() {
-			ozoneRequests.WithLabelValues("comment", "actor", ozoneStatus).Inc()
+			ozoneRequests.WithLabelValues("set", "actor", ozoneStatus).Inc()
 		}()
 
-		comment := fmt.Sprintf("Actioned by rules %s", strings.Join(e.Rules, ","))
-		if e.Comment != nil {
-			comment = fmt.Sprintf("%s\n\n%s", comment, *e.Comment)
+		rules := strings.Join(e.Rules, ",")
+		remove := e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE
+		action := actionName("set", e.Set, remove)
+
+		// Sets don't take comments, so it's only logged.
+		comment := or.effectComment(evt, EffectSet, e.Rules, e.GetComment())
+
+		key := ActionKey{Subject: evt.Did, Action: action, Rules: rules}
+		if or.checkHasActioned(ctx, key) {
+			or.logger.Info("skipping ozone set effect", "actionId", evt.ActionId)
+			ozoneStatus = "skipped"
+			continue
 		}
 
-		// NOTE: Purposefully do not ignore duplicate actions for emails
-		if err := or.ozoneClient.SendEmail(ctx, evt.Did, e.Email); err != nil {
-			or.logger.Error("error processing email effects", "error", err)
+		callCtx, sent := withSentEvent(ctx)
+		if err := or.ozoneClient.UpdateSet(callCtx, evt.Did, e.Set, remove); err != nil {
+			or.logger.Error("error processing set effects", "error", err)
+			or.forgetAction(key)
+			failed.Sets = append(failed.Sets, e)
+			errs = append(errs, err)
 		} else {
 			ozoneStatus = "ok"
+			or.invalidateAction(ctx, evt.Did, oppositeAction(action))
+			if sent.dryRun {
+				or.logSetDryRun(evt, e, rules)
+			}
 			or.logEffect(&OspreyEffectLog{
 				ActionName: evt.ActionName,
 				ActionID:   evt.ActionId,
-				Subject:    evt.Uri,
-				Kind:       "email",
+				Subject:    evt.Did,
+				Kind:       EffectSet,
 				Comment:    comment,
-				CreatedAt:  time.Now(),
-				Rules:      strings.Join(e.Rules, ","),
+				OzoneSet: bigquery.NullString{
+					StringVal: e.Set,
+					Valid:     true,
+				},
+				CreatedAt: time.Now(),
+				Rules:     rules,
+				dryRun:    sent.dryRun,
+			})
+		}
+	}
+
+	for _, e := range evt.Emails {
+		ozoneStatus := "error"
+		defer func() {
+			ozoneRequests.WithLabelValues("comment", "actor", ozoneStatus).Inc()
+		}()
+
+		comment := or.effectComment(evt, EffectEmail, e.Rules, e.GetComment())
+
+		// NOTE: Purposefully do not ignore duplicate actions for emails
+		callCtx, sent := withSentEvent(ctx)
+		lang, err := or.ozoneClient.SendEmail(
+			callCtx,
+			evt.Did,
+			ModToolMeta{
+				Rules:    strings.Join(e.Rules, ","),
+				ActionID: evt.ActionId,
+			},
+			e.Email,
+		)
+		if err != nil {
+			or.logger.Error("error processing email effects", "error", err)
+			failed.Emails = append(failed.Emails, e)
+			errs = append(errs, err)
+		} else {
+			ozoneStatus = "ok"
+			or.logEffect(&OspreyEffectLog{
+				ActionName:   evt.ActionName,
+				ActionID:     evt.ActionId,
+				Subject:      evt.Uri,
+				Kind:         "email",
+				Comment:      comment,
+				Email:        bigquery.NullString{StringVal: e.Email.String(), Valid: true},
+				CreatedAt:    time.Now(),
+				Rules:        strings.Join(e.Rules, ","),
+				OzoneEventID: sent.eventID(),
+				dryRun:       sent.dryRun,
+				EmailLang:    bigquery.NullString{StringVal: lang, Valid: true},
 			})
 		}
 	}
@@ -828,19 +1788,25 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 			continue
 		}
 
-		// skip over anything that isn't a record, we shouldn't be sending those
-		if e.SubjectKind != osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD {
+		// skip over anything that isn't a record or an account, we shouldn't be sending those
+		var subject string
+		switch e.SubjectKind {
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
+			subject = evt.Uri
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR:
+			subject = evt.Did
+		default:
 			continue
 		}
 
-		if err := or.bigqueryFlagClient.addFlag(context.Background(), evt.Uri, e.Tag); err != nil {
+		if err := or.bigqueryFlagClient.addFlag(context.Background(), e.SubjectKind, evt.Did, evt.Uri, e.Tag); err != nil {
 			or.logger.Error("error procesing bigquery flag effect", "error", err)
 		}
 
 		or.logEffect(&OspreyEffectLog{
 			ActionName: evt.ActionName,
 			ActionID:   evt.ActionId,
-			Subject:    evt.Uri,
+			Subject:    subject,
 			Kind:       "bigquery-flag",
 			Tag: bigquery.NullString{
 				StringVal: e.Tag,
@@ -851,51 +1817,198 @@ func (or *OspreyEffector) handleEvent(ctx context.Context, evt *osprey.ResultEve
 		})
 	}
 
-	status = "ok"
+	// failed is empty when every effect was applied, so this observes all of them.
+	observeEffectLatency(evt, failed)
 
-	return nil
+	if len(errs) == 0 {
+		return nil, nil
+	}
+
+	return failed, errors.Join(errs...)
+}
+
+// actionNameKey carries the name of the action whose effects are being applied, for dry run logs
+// and effect outcomes.
+type actionNameKey struct{}
+
+// logDryRun writes a moderation event that wasn't sent to Ozone to the effect log, with the full
+// event as the comment.
+func (or *OspreyEffector) logDryRun(ctx context.Context, input *ozone.ModerationEmitEvent_Input) {
+	or.produceOutcome(ctx, input, nil, true, nil)
+
+	b, err := json.Marshal(input)
+	if err != nil {
+		or.logger.Error("failed to marshal dry run event", "error", err)
+		return
+	}
+
+	meta := emitEventMeta(input)
+	actionName, _ := ctx.Value(actionNameKey{}).(string)
+
+	or.countRuleEffects(meta.Rules, ozoneEventKind(input.Event), "dry-run")
+
+	or.logEffect(&OspreyEffectLog{
+		ActionName: actionName,
+		ActionID:   meta.ActionID,
+		Subject:    emitEventSubject(input),
+		Kind:       "dry-run",
+		Comment:    string(b),
+		CreatedAt:  time.Now(),
+		Rules:      meta.Rules,
+	})
+}
+
+// logSetDryRun writes a set change that wasn't made in Ozone to the effect log, the same way as
+// logDryRun does for moderation events, with the request that would've been sent as the comment.
+func (or *OspreyEffector) logSetDryRun(evt *osprey.ResultEvent, e *osprey.AtprotoSetEffect, rules string) {
+	var input any = &ozone.SetAddValues_Input{Name: e.Set, Values: []string{evt.Did}}
+	if e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE {
+		input = &ozone.SetDeleteValues_Input{Name: e.Set, Values: []string{evt.Did}}
+	}
+	b, err := json.Marshal(input)
+	if err != nil {
+		or.logger.Error("failed to marshal dry run set change", "error", err)
+		return
+	}
+
+	or.countRuleEffects(rules, EffectSet, "dry-run")
+
+	or.logEffect(&OspreyEffectLog{
+		ActionName: evt.ActionName,
+		ActionID:   evt.ActionId,
+		Subject:    evt.Did,
+		Kind:       "dry-run",
+		Comment:    string(b),
+		OzoneSet: bigquery.NullString{
+			StringVal: e.Set,
+			Valid:     true,
+		},
+		CreatedAt: time.Now(),
+		Rules:     rules,
+	})
 }
 
 func (or *OspreyEffector) logEffect(log *OspreyEffectLog) {
+	if log.dryRun {
+		return
+	}
+
+	switch {
+	case log.Error.Valid:
+		// Failed effects are logged for auditing, but didn't do anything.
+	case log.Kind == "dry-run":
+		// Counted by logDryRun, which knows the kind of the event.
+	case log.Shadow:
+		or.countRuleEffects(log.Rules, log.Kind, "shadow")
+	default:
+		or.countRuleEffects(log.Rules, log.Kind, "enforce")
+	}
+
+	or.recentActions.addEffect(log)
+
+	if log.Handle == "" {
+		log.Handle = or.subjectHandle(log.Subject)
+	}
 	if err := or.logManager.LogEffect(context.Background(), log); err != nil {
 		or.logger.Error("failed to log effect", "error", err)
 	}
 }
 
-func createActionKey(subject string, ruleName string, expirationInHours *int64) string {
-	// create the base key for the action
-	key := fmt.Sprintf("%s-%s", subject, ruleName)
-	// if there is an expiration in hours set, append it to the string. this allows us to have "incremental step ups"
-	// for behavior that persists
-	if expirationInHours != nil {
-		key = fmt.Sprintf("%s-dur-%d", key, *expirationInHours)
+// countRuleEffects counts an effect towards each of the comma separated rules that caused it, so
+// that a rule suddenly actioning far more than usual stands out.
+func (or *OspreyEffector) countRuleEffects(rules, kind, mode string) {
+	for rule := range strings.SplitSeq(rules, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			ruleEffects.WithLabelValues(rule, kind, mode).Inc()
+			or.ruleCounts.add(rule, mode)
+		}
 	}
-	return key
 }
 
-func (or *OspreyEffector) checkHasActioned(subject string, ruleName string, expirationInHours *int64) bool {
-	key := createActionKey(subject, ruleName, expirationInHours)
+// subjectHandle resolves the handle of the account a DID or AT-URI subject belongs to. Failures
+// are logged and return an empty string, since the handle is only there to make logs readable.
+func (or *OspreyEffector) subjectHandle(subject string) string {
+	did := subject
+	if !strings.HasPrefix(subject, "did:") {
+		aturi, err := syntax.ParseATURI(subject)
+		if err != nil {
+			return ""
+		}
+		did = aturi.Authority().String()
+	}
 
-	_, err := or.memClient.Get(key)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	handle, err := or.ozoneClient.ResolveHandle(ctx, did)
 	if err != nil {
-		if err != memcache.ErrCacheMiss {
-			or.logger.Error("memcache lookup error", "err", err)
-		}
-	} else {
-		// if this didn't error, then it's set in the cache
-		return true
+		or.logger.Warn("failed to resolve handle for effect subject", "subject", subject, "error", err)
+		return ""
 	}
+	return handle
+}
 
-	if err := or.memClient.Add(&memcache.Item{
-		Key:        key,
-		Value:      []byte("1"),
-		Expiration: 0,
-	}); err != nil {
-		if err != memcache.ErrNotStored {
-			or.logger.Error("memcache insert error", "err", err)
-		}
+// checkHasActioned reports whether the action has already been taken, recording it if not. Labels
+// are recorded for as long as they last, so that an expired label can be applied again, and other
+// actions for actionDefaultTTL. Errors from the store are logged and treated as not actioned, so
+// that we'd rather act twice than never.
+func (or *OspreyEffector) checkHasActioned(ctx context.Context, key ActionKey) bool {
+	ttl := or.actionDefaultTTL
+	if key.ExpirationInHours != nil && *key.ExpirationInHours > 0 {
+		ttl = time.Duration(*key.ExpirationInHours) * time.Hour
+	}
+
+	recorded, err := or.actionStore.MarkActioned(ctx, key, ttl)
+	if err != nil {
+		or.logger.Error("failed to record action", "key", key.String(), "err", err)
+		return false
+	}
+	return !recorded
+}
+
+// alreadyInPlace reports whether, according to Ozone, the subject is already in the state an action
+// would put it in, so that the action would only add a duplicate mod event. This catches actions
+// the action store has lost track of. It's only checked when enabled, and lookup failures report
+// false so that the action goes ahead.
+func (or *OspreyEffector) alreadyInPlace(ctx context.Context, subject string, inPlace func(*SubjectStatus) bool) bool {
+	if !or.checkSubjectStatus {
+		return false
 	}
 
-	// after storing, go ahead and return false so that we actually apply the action
-	return false
+	status, err := or.ozoneClient.GetSubjectStatus(ctx, subject)
+	if err != nil {
+		or.logger.Warn("failed to check subject status, applying effect anyway", "subject", subject, "err", err)
+		return false
+	}
+	return inPlace(status)
+}
+
+func labelInPlace(label string, remove bool) func(*SubjectStatus) bool {
+	return func(s *SubjectStatus) bool {
+		return s.Labels[label] != remove
+	}
+}
+
+func takedownInPlace(remove bool) func(*SubjectStatus) bool {
+	return func(s *SubjectStatus) bool {
+		return s.Takendown != remove
+	}
+}
+
+// forgetAction removes the record of an action that failed, so that it isn't skipped when retried.
+func (or *OspreyEffector) forgetAction(key ActionKey) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := or.actionStore.Forget(ctx, key); err != nil {
+		or.logger.Error("failed to forget action", "key", key.String(), "err", err)
+	}
+}
+
+// invalidateAction forgets that the action was taken on the subject, so that once an action is
+// reversed it can be taken again, and vice versa.
+func (or *OspreyEffector) invalidateAction(ctx context.Context, subject, action string) {
+	if err := or.actionStore.Invalidate(ctx, subject, action); err != nil {
+		or.logger.Error("failed to invalidate action", "subject", subject, "action", action, "err", err)
+	}
 }
@@ -0,0 +1,82 @@
+package effector
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/bluesky-social/indigo/api/ozone"
+	"github.com/bluesky-social/indigo/atproto/identity"
+	"github.com/bluesky-social/indigo/xrpc"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// newDryRunEffector returns an effector that isn't in production, along with the events it would
+// have sent to Ozone.
+func newDryRunEffector(t *testing.T) (*OspreyEffector, *[]*ozone.ModerationEmitEvent_Input) {
+	t.Helper()
+
+	templates, err := loadCommentTemplates("", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := identity.NewMockDirectory()
+	oc := &OzoneClient{
+		logger:    slog.Default(),
+		directory: &dir,
+	}
+	oc.client.Store(&xrpc.Client{Auth: &xrpc.AuthInfo{Did: "did:plc:labeler"}})
+
+	var sent []*ozone.ModerationEmitEvent_Input
+	oc.dryRun = func(ctx context.Context, input *ozone.ModerationEmitEvent_Input) {
+		sent = append(sent, input)
+	}
+
+	return &OspreyEffector{
+		logger:           slog.Default(),
+		ozoneClient:      oc,
+		logManager:       NewOspreyLogManager(),
+		commentTemplates: templates,
+		ruleCounts:       newRuleCounts(),
+	}, &sent
+}
+
+func TestEscalationComment(t *testing.T) {
+	or, sent := newDryRunEffector(t)
+
+	comment := "looks like spam"
+	score := 0.97
+	evt := &osprey.ResultEvent{
+		ActionName: "create_post",
+		ActionId:   1,
+		Did:        "did:plc:subject",
+		Escalations: []*osprey.AtprotoEscalateEffect{{
+			SubjectKind: osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR,
+			Comment:     &comment,
+			Rules:       []string{"spam_rule"},
+		}},
+		Evidence: []*osprey.Evidence{{
+			Kind:  "hive",
+			Name:  "spam",
+			Score: &score,
+		}},
+	}
+
+	if _, err := or.applyEffects(context.Background(), evt); err != nil {
+		t.Fatalf("applyEffects: %v", err)
+	}
+
+	if len(*sent) != 1 {
+		t.Fatalf("expected 1 event sent to Ozone, got %d", len(*sent))
+	}
+	escalate := (*sent)[0].Event.ModerationDefs_ModEventEscalate
+	if escalate == nil || escalate.Comment == nil {
+		t.Fatalf("expected an escalation with a comment, got %+v", (*sent)[0].Event)
+	}
+
+	want := "Actioned by rules spam_rule\n\nlooks like spam\n\nEvidence:\n- hive spam: 0.97"
+	if *escalate.Comment != want {
+		t.Errorf("unexpected escalation comment\n got: %q\nwant: %q", *escalate.Comment, want)
+	}
+}
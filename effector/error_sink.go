@@ -0,0 +1,59 @@
+package effector
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/xrpc"
+)
+
+// ErrorSink is told about effects that failed to apply, so that failures can be aggregated and
+// alerted on somewhere other than the logs.
+type ErrorSink interface {
+	Name() string
+	CaptureEffectFailure(ctx context.Context, f *EffectFailure)
+	// Close sends any failures that haven't been yet.
+	Close()
+}
+
+// EffectFailure is an effect that Ozone didn't apply.
+type EffectFailure struct {
+	ActionName string
+	ActionID   int64
+	Kind       string
+	Subject    string
+	Rules      string
+	Err        error
+}
+
+// captureEffectFailure hands the failure to each error sink.
+func (or *OspreyEffector) captureEffectFailure(ctx context.Context, f *EffectFailure) {
+	for _, s := range or.errorSinks {
+		s.CaptureEffectFailure(ctx, f)
+	}
+}
+
+// ozoneErrorName returns the status code and XRPC error name Ozone responded with, e.g.
+// "400 InvalidRequest", or an empty string if the request didn't get a response.
+func ozoneErrorName(err error) string {
+	var xerr *xrpc.Error
+	if errors.As(err, &xerr) {
+		name := strconv.Itoa(xerr.StatusCode)
+		var xrpcErr *xrpc.XRPCError
+		if errors.As(xerr.Wrapped, &xrpcErr) && xrpcErr.ErrStr != "" {
+			name += " " + xrpcErr.ErrStr
+		}
+		return name
+	}
+	var apiErr *atclient.APIError
+	if errors.As(err, &apiErr) {
+		name := strconv.Itoa(apiErr.StatusCode)
+		if apiErr.Name != "" {
+			name += " " + apiErr.Name
+		}
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package effector
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// evidenceMaxValueLength is how much of an evidence value is shown, so that a long OCR snippet
+// doesn't bury the rest of the comment.
+const evidenceMaxValueLength = 300
+
+// withEvidence appends the event's evidence for the rules to the comment.
+func withEvidence(comment string, evt *osprey.ResultEvent, rules []string) string {
+	block := evidenceBlock(evt, rules)
+	if block == "" {
+		return comment
+	}
+	if comment == "" {
+		return block
+	}
+	return strings.TrimRight(comment, "\n") + "\n\n" + block
+}
+
+// evidenceBlock renders the event's evidence for any of the rules as a list, one piece per line,
+// e.g. "- hive sexual: 0.97", or returns an empty string if there's none.
+func evidenceBlock(evt *osprey.ResultEvent, rules []string) string {
+	var lines []string
+	for _, ev := range evt.Evidence {
+		if len(ev.Rules) > 0 && !slices.ContainsFunc(ev.Rules, func(r string) bool { return slices.Contains(rules, r) }) {
+			continue
+		}
+
+		line := "- " + ev.Kind
+		if ev.Name != "" {
+			line += " " + ev.Name
+		}
+		var details []string
+		if ev.Score != nil {
+			details = append(details, strconv.FormatFloat(*ev.Score, 'g', 3, 64))
+		}
+		if ev.Value != "" {
+			details = append(details, truncateEvidence(ev.Value))
+		}
+		if len(details) > 0 {
+			line += ": " + strings.Join(details, " ")
+		}
+		lines = appendUnique(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Evidence:\n" + strings.Join(lines, "\n")
+}
+
+// truncateEvidence shortens the value to evidenceMaxValueLength runes and puts it on one line.
+func truncateEvidence(v string) string {
+	v = strings.Join(strings.Fields(v), " ")
+	if r := []rune(v); len(r) > evidenceMaxValueLength {
+		v = string(r[:evidenceMaxValueLength]) + "…"
+	}
+	return fmt.Sprintf("%q", v)
+}
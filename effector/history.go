@@ -0,0 +1,115 @@
+package effector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// historyDefaultDays is how far back the action history API looks when the request doesn't say,
+	// and historyMaxDays is as far back as it'll go, since BigQuery scans get pricey.
+	historyDefaultDays = 30
+	historyMaxDays     = 365
+
+	historyQueryTimeout = 10 * time.Second
+)
+
+var historyQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "history_queries",
+	Namespace: NAMESPACE,
+	Help:      "number of action history lookups, by the effect log they went to and whether they succeeded",
+}, []string{"source", "status"})
+
+// ActionHistory is implemented by effect logs that can be queried for the actions already taken on
+// a subject.
+type ActionHistory interface {
+	Name() string
+	CountActions(ctx context.Context, q *ActionHistoryQuery) (int64, error)
+}
+
+// ActionHistoryQuery selects the applied effects on a subject since a point in time. Shadowed and
+// failed effects are never counted, since nothing was done.
+type ActionHistoryQuery struct {
+	Subject string
+	// Rule, when set, only counts effects the rule caused.
+	Rule string
+	// Kind, when set, only counts effects of the kind, e.g. "label" or "takedown".
+	Kind  string
+	Since time.Time
+}
+
+// ActionHistoryCount is the action history API's response. Count is the number of events that
+// actioned the subject, rather than of effects, so a rule that labels and tags counts once.
+type ActionHistoryCount struct {
+	Subject string `json:"subject"`
+	Rule    string `json:"rule,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Days    int    `json:"days"`
+	Count   int64  `json:"count"`
+	Source  string `json:"source"`
+}
+
+// handleActionHistory answers how many times the subject was actioned in the last N days, optionally
+// by a given rule or with a given kind of effect, for rules and reviewers that take prior
+// enforcement into account.
+func (or *OspreyEffector) handleActionHistory(e echo.Context) error {
+	if or.history == nil {
+		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "no effect log to look up action history in"})
+	}
+
+	subject := e.QueryParam("subject")
+	if subject == "" {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "subject is required"})
+	}
+
+	kind := e.QueryParam("kind")
+	if kind != "" && !slices.Contains(EffectKinds, kind) {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "unknown effect kind"})
+	}
+
+	days := historyDefaultDays
+	if v := e.QueryParam("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > historyMaxDays {
+			return e.JSON(http.StatusBadRequest, map[string]string{"error": "days must be between 1 and " + strconv.Itoa(historyMaxDays)})
+		}
+		days = n
+	}
+
+	ctx, cancel := context.WithTimeout(e.Request().Context(), historyQueryTimeout)
+	defer cancel()
+
+	rule := e.QueryParam("rule")
+	count, err := or.history.CountActions(ctx, &ActionHistoryQuery{
+		Subject: subject,
+		Rule:    rule,
+		Kind:    kind,
+		Since:   time.Now().AddDate(0, 0, -days),
+	})
+	if err != nil {
+		historyQueries.WithLabelValues(or.history.Name(), "error").Inc()
+		or.logger.Error("failed to look up action history", "subject", subject, "rule", rule, "error", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return e.JSON(http.StatusGatewayTimeout, map[string]string{"error": "action history lookup timed out"})
+		}
+		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to look up action history"})
+	}
+	historyQueries.WithLabelValues(or.history.Name(), "ok").Inc()
+
+	return e.JSON(http.StatusOK, ActionHistoryCount{
+		Subject: subject,
+		Rule:    rule,
+		Kind:    kind,
+		Days:    days,
+		Count:   count,
+		Source:  or.history.Name(),
+	})
+}
@@ -0,0 +1,308 @@
+package effector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/bus/producer"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// labelExpiredActionName is the action name expired labels are produced to Osprey with.
+const labelExpiredActionName = "atproto.label#expired"
+
+// labelExpiryInterval is how often expired labels are looked for, and the snapshot written.
+const labelExpiryInterval = time.Minute
+
+var (
+	labelExpiriesTracked = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "label_expiries_tracked",
+		Namespace: NAMESPACE,
+		Help:      "number of temporary labels waiting to expire",
+	})
+
+	labelExpiriesProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "label_expiries_produced",
+		Namespace: NAMESPACE,
+		Help:      "number of expired labels produced back to Osprey, by label and status",
+	}, []string{"label", "status"})
+)
+
+// labelExpiries keeps the temporary labels the effector applied until they expire, then produces
+// a LabelExpiredEvent for each to Osprey's input topic. Rules can then check whether the behavior
+// continued after the label expired and step up, without a cron job outside of Osprey. The labels
+// are snapshotted to disk, so that a restart doesn't forget them.
+type labelExpiries struct {
+	logger       *slog.Logger
+	producer     *producer.Producer[*osprey.OspreyInputEvent]
+	snapshotPath string
+
+	mu      sync.Mutex
+	entries map[labelExpiryKey]*labelExpiry
+}
+
+type labelExpiryKey struct {
+	subject string
+	label   string
+}
+
+type labelExpiry struct {
+	SubjectDid      string    `json:"subject_did"`
+	SubjectUri      string    `json:"subject_uri,omitempty"`
+	Label           string    `json:"label"`
+	Rules           []string  `json:"rules"`
+	ActionName      string    `json:"action_name"`
+	ActionID        int64     `json:"action_id"`
+	DurationInHours int64     `json:"duration_in_hours"`
+	AppliedAt       time.Time `json:"applied_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func (e *labelExpiry) key() labelExpiryKey {
+	subject := e.SubjectDid
+	if e.SubjectUri != "" {
+		subject = e.SubjectUri
+	}
+	return labelExpiryKey{subject: subject, label: e.Label}
+}
+
+func newLabelExpiries(logger *slog.Logger, p *producer.Producer[*osprey.OspreyInputEvent], snapshotPath string) (*labelExpiries, error) {
+	le := &labelExpiries{
+		logger:       logger.With("component", "label_expiries"),
+		producer:     p,
+		snapshotPath: snapshotPath,
+		entries:      map[labelExpiryKey]*labelExpiry{},
+	}
+
+	if le.snapshotPath != "" {
+		if err := le.loadSnapshot(); err != nil {
+			return nil, fmt.Errorf("failed to load label expiry snapshot: %w", err)
+		}
+	}
+
+	return le, nil
+}
+
+// track records a temporary label that was just applied. Applying the same label to the subject
+// again replaces the earlier one, since Ozone's expiry is replaced as well.
+func (le *labelExpiries) track(e *labelExpiry) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	le.entries[e.key()] = e
+	labelExpiriesTracked.Set(float64(len(le.entries)))
+}
+
+// forget stops tracking the label on the subject, for when it's removed before it expires.
+func (le *labelExpiries) forget(subject, label string) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	delete(le.entries, labelExpiryKey{subject: subject, label: label})
+	labelExpiriesTracked.Set(float64(len(le.entries)))
+}
+
+// run produces labels as they expire and writes snapshots until the context is cancelled, at which
+// point a final snapshot is written.
+func (le *labelExpiries) run(ctx context.Context) {
+	ticker := time.NewTicker(labelExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := le.writeSnapshot(); err != nil {
+				le.logger.Error("failed to write final label expiry snapshot", "err", err)
+			}
+			return
+		case <-ticker.C:
+			le.produceExpired(ctx, time.Now())
+			if err := le.writeSnapshot(); err != nil {
+				le.logger.Error("failed to write label expiry snapshot", "err", err)
+			}
+		}
+	}
+}
+
+// produceExpired produces the labels that have expired by now. Labels that fail to produce are
+// kept, and tried again on the next tick.
+func (le *labelExpiries) produceExpired(ctx context.Context, now time.Time) {
+	le.mu.Lock()
+	var expired []*labelExpiry
+	for _, e := range le.entries {
+		if !e.ExpiresAt.After(now) {
+			expired = append(expired, e)
+		}
+	}
+	le.mu.Unlock()
+
+	for _, e := range expired {
+		evt, err := labelExpiryToOspreyEvent(e)
+		if err != nil {
+			le.logger.Error("failed to build expired label event, dropping it", "subject", e.key().subject, "label", e.Label, "err", err)
+			le.remove(e)
+			continue
+		}
+		if err := le.producer.ProduceSync(ctx, e.SubjectDid, evt); err != nil {
+			le.logger.Error("failed to produce expired label", "subject", e.key().subject, "label", e.Label, "err", err)
+			labelExpiriesProduced.WithLabelValues(e.Label, "error").Inc()
+			continue
+		}
+		labelExpiriesProduced.WithLabelValues(e.Label, "ok").Inc()
+		le.remove(e)
+	}
+}
+
+// remove stops tracking the entry, unless it's been replaced by a newer application of the label
+// in the meantime.
+func (le *labelExpiries) remove(e *labelExpiry) {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+	if le.entries[e.key()] == e {
+		delete(le.entries, e.key())
+		labelExpiriesTracked.Set(float64(len(le.entries)))
+	}
+}
+
+func labelExpiryToOspreyEvent(e *labelExpiry) (*osprey.OspreyInputEvent, error) {
+	event := &osprey.LabelExpiredEvent{
+		SubjectDid:      e.SubjectDid,
+		Label:           e.Label,
+		Rules:           e.Rules,
+		ActionName:      e.ActionName,
+		ActionId:        e.ActionID,
+		DurationInHours: e.DurationInHours,
+		AppliedAt:       timestamppb.New(e.AppliedAt),
+		ExpiredAt:       timestamppb.New(e.ExpiresAt),
+	}
+	if e.SubjectUri != "" {
+		event.SubjectUri = &e.SubjectUri
+	}
+
+	dataBytes, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal LabelExpiredEvent: %w", err)
+	}
+
+	return &osprey.OspreyInputEvent{
+		SendTime: timestamppb.Now(),
+		Data: &osprey.OspreyInputEventData{
+			ActionName: labelExpiredActionName,
+			ActionId:   time.Now().UnixMicro(),
+			Timestamp:  event.ExpiredAt,
+			SecretData: map[string]string{},
+			Encoding:   "UTF8",
+			Data:       dataBytes,
+		},
+	}, nil
+}
+
+func (le *labelExpiries) writeSnapshot() error {
+	if le.snapshotPath == "" {
+		return nil
+	}
+
+	le.mu.Lock()
+	snapshot := make([]*labelExpiry, 0, len(le.entries))
+	for _, e := range le.entries {
+		snapshot = append(snapshot, e)
+	}
+	b, err := json.Marshal(snapshot)
+	le.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// Write to a temp file and rename so we never leave a partial snapshot behind.
+	tmp, err := os.CreateTemp(filepath.Dir(le.snapshotPath), filepath.Base(le.snapshotPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), le.snapshotPath); err != nil {
+		return fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+
+	le.logger.Debug("wrote label expiry snapshot", "labels", len(snapshot))
+	return nil
+}
+
+func (le *labelExpiries) loadSnapshot() error {
+	b, err := os.ReadFile(le.snapshotPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			le.logger.Info("no label expiry snapshot found, starting empty", "path", le.snapshotPath)
+			return nil
+		}
+		return err
+	}
+
+	var snapshot []*labelExpiry
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	// Labels that expired while we were down are kept, and produced on the first tick.
+	for _, e := range snapshot {
+		le.entries[e.key()] = e
+	}
+	labelExpiriesTracked.Set(float64(len(le.entries)))
+
+	le.logger.Info("loaded label expiry snapshot", "path", le.snapshotPath, "labels", len(le.entries))
+	return nil
+}
+
+// trackLabelExpiry keeps track of a temporary label that was applied, or stops tracking a label
+// that was removed, when expired labels are being produced.
+func (or *OspreyEffector) trackLabelExpiry(evt *osprey.ResultEvent, e *osprey.AtprotoLabelEffect) {
+	if or.labelExpiries == nil {
+		return
+	}
+
+	subject := evt.Did
+	if e.SubjectKind == osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD {
+		subject = evt.Uri
+	}
+	label := AtprotoLabelToString(e.Label)
+
+	if e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE {
+		or.labelExpiries.forget(subject, label)
+		return
+	}
+	if e.ExpirationInHours == nil || *e.ExpirationInHours <= 0 || !or.ozoneClient.live(evt.Did) {
+		return
+	}
+
+	now := time.Now()
+	le := &labelExpiry{
+		SubjectDid:      evt.Did,
+		Label:           label,
+		Rules:           e.Rules,
+		ActionName:      evt.ActionName,
+		ActionID:        evt.ActionId,
+		DurationInHours: *e.ExpirationInHours,
+		AppliedAt:       now,
+		ExpiresAt:       now.Add(time.Duration(*e.ExpirationInHours) * time.Hour),
+	}
+	if e.SubjectKind == osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD {
+		le.SubjectUri = evt.Uri
+	}
+	or.labelExpiries.track(le)
+}
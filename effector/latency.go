@@ -0,0 +1,92 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	effectLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "effect_latency_seconds",
+		Namespace: NAMESPACE,
+		Help:      "time from Osprey deciding on effects to the effector applying them, by effect kind, including retries",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 16),
+	}, []string{"kind"})
+
+	eventAge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "event_age_seconds",
+		Namespace: NAMESPACE,
+		Help:      "how long ago Osprey sent the event the effector most recently started handling",
+	})
+)
+
+// observeEffectLatency records how long after Osprey sent the event its effects were applied, for
+// each kind of effect that was. Effects that failed are observed when a retry applies them.
+func observeEffectLatency(evt, failed *osprey.ResultEvent) {
+	if evt.SendTime == nil {
+		return
+	}
+	latency := time.Since(evt.SendTime.AsTime()).Seconds()
+
+	applied := map[string]int{
+		EffectLabel:           len(evt.Labels) - len(failed.Labels),
+		EffectTag:             len(evt.Tags) - len(failed.Tags),
+		EffectTakedown:        len(evt.Takedowns) - len(failed.Takedowns),
+		EffectMute:            len(evt.Mutes) - len(failed.Mutes),
+		EffectDivert:          len(evt.Diverts) - len(failed.Diverts),
+		EffectReport:          len(evt.Reports) - len(failed.Reports),
+		EffectComment:         len(evt.Comments) - len(failed.Comments),
+		EffectEscalation:      len(evt.Escalations) - len(failed.Escalations),
+		EffectAcknowledgement: len(evt.Acknowledgements) - len(failed.Acknowledgements),
+		EffectResolveAppeal:   len(evt.ResolveAppeals) - len(failed.ResolveAppeals),
+		EffectSet:             len(evt.Sets) - len(failed.Sets),
+		EffectEmail:           len(evt.Emails) - len(failed.Emails),
+		EffectBigQueryFlag:    len(evt.BigqueryFlags) - len(failed.BigqueryFlags),
+	}
+	for kind, n := range applied {
+		if n > 0 {
+			effectLatency.WithLabelValues(kind).Observe(latency)
+		}
+	}
+}
+
+// checkBacklog tracks how old the events being handled are, and alerts when they're older than
+// backlogThreshold, meaning the effector has fallen behind and enforcement is stale. It alerts
+// again once events are back under half the threshold, so a backlog hovering around it doesn't
+// alert on every event.
+func (or *OspreyEffector) checkBacklog(ctx context.Context, evt *osprey.ResultEvent) {
+	if evt.SendTime == nil {
+		return
+	}
+	age := time.Since(evt.SendTime.AsTime())
+	eventAge.Set(age.Seconds())
+
+	if or.backlogThreshold <= 0 {
+		return
+	}
+
+	var msg string
+	switch {
+	case age > or.backlogThreshold && or.backlogAlerting.CompareAndSwap(false, true):
+		or.logger.Warn("handling events older than the backlog threshold", "age", age, "threshold", or.backlogThreshold)
+		msg = fmt.Sprintf(`Effector is behind
+Handling events sent %s ago, over the %s threshold
+Action Name: %s`, age.Round(time.Second), or.backlogThreshold, evt.ActionName)
+	case age < or.backlogThreshold/2 && or.backlogAlerting.CompareAndSwap(true, false):
+		or.logger.Info("caught up on the backlog", "age", age)
+		msg = fmt.Sprintf("Effector caught up, handling events sent %s ago", age.Round(time.Second))
+	default:
+		return
+	}
+
+	for _, a := range or.alerters {
+		if err := a.Alert(ctx, msg); err != nil {
+			or.logger.Error("failed to send backlog alert", "error", err)
+		}
+	}
+}
@@ -31,6 +31,25 @@ type OspreyEffectLog struct {
 	Tag        bigquery.NullString `bigquery:"tag" json:"tag"`
 	Email      bigquery.NullString `bigquery:"email" json:"email"`
 	CreatedAt  time.Time           `bigquery:"created_at" json:"createdAt"`
+	// Shadow is set when the effect's rules are shadowed, so it was logged but never applied.
+	Shadow bool `bigquery:"shadow" json:"shadow,omitempty"`
+	// OzoneEventID is the moderation event Ozone created for the effect, for joining against Ozone's
+	// own event history.
+	OzoneEventID bigquery.NullInt64 `bigquery:"ozone_event_id" json:"ozoneEventId"`
+	// Error is set when the effect failed to apply, in which case nothing was done.
+	Error bigquery.NullString `bigquery:"error" json:"error"`
+	// EmailLang is the language of the email template that was sent, which is the recipient's when
+	// there's a template in it.
+	EmailLang bigquery.NullString `bigquery:"email_lang" json:"emailLang"`
+	// OzoneSet is the Ozone set the subject was added to or removed from.
+	OzoneSet bigquery.NullString `bigquery:"ozone_set" json:"ozoneSet"`
+	// Handle is the subject's handle at the time of the effect, for human readers. It isn't written
+	// to BigQuery, where the DID is what matters.
+	Handle string `bigquery:"-" json:"handle,omitempty"`
+
+	// dryRun is set on effects that were only a dry run, which are logged as such when the Ozone
+	// client skips sending them, so they aren't logged again.
+	dryRun bool
 }
 
 type OspreyLogger interface {
@@ -39,6 +58,11 @@ type OspreyLogger interface {
 	LogEffect(ctx context.Context, log *OspreyEffectLog) error
 }
 
+// Alerter is implemented by loggers that can also post messages that aren't about any one effect.
+type Alerter interface {
+	Alert(ctx context.Context, msg string) error
+}
+
 type OspreyLogManager struct {
 	loggers []OspreyLogger
 }
@@ -0,0 +1,58 @@
+package effector
+
+import (
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var messageEffectsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "message_effects_dropped",
+	Namespace: NAMESPACE,
+	Help:      "number of effects on chat messages dropped because they can't apply to messages or the event had no message, by kind",
+}, []string{"kind"})
+
+type subjectEffect interface {
+	GetSubjectKind() osprey.AtprotoSubjectKind
+}
+
+// dropInvalidMessageEffects removes effects on chat messages that can't be applied. Ozone only
+// keeps a case history for messages, so reports, comments, tags, escalations and acknowledgements
+// work, while labels, takedowns and the like apply to accounts and records and have to be aimed at
+// the sender's account instead. Every message effect is dropped when the event has no message.
+func (or *OspreyEffector) dropInvalidMessageEffects(evt *osprey.ResultEvent) {
+	supported := evt.Message != nil && evt.Message.Did != "" && evt.Message.ConvoId != "" && evt.Message.MessageId != ""
+
+	evt.Labels = dropMessageEffects(or, evt, EffectLabel, evt.Labels, false)
+	evt.Takedowns = dropMessageEffects(or, evt, EffectTakedown, evt.Takedowns, false)
+	evt.Mutes = dropMessageEffects(or, evt, EffectMute, evt.Mutes, false)
+	evt.Diverts = dropMessageEffects(or, evt, EffectDivert, evt.Diverts, false)
+	evt.ResolveAppeals = dropMessageEffects(or, evt, EffectResolveAppeal, evt.ResolveAppeals, false)
+	evt.Tags = dropMessageEffects(or, evt, EffectTag, evt.Tags, supported)
+	evt.Reports = dropMessageEffects(or, evt, EffectReport, evt.Reports, supported)
+	evt.Comments = dropMessageEffects(or, evt, EffectComment, evt.Comments, supported)
+	evt.Escalations = dropMessageEffects(or, evt, EffectEscalation, evt.Escalations, supported)
+	evt.Acknowledgements = dropMessageEffects(or, evt, EffectAcknowledgement, evt.Acknowledgements, supported)
+}
+
+func dropMessageEffects[T subjectEffect](or *OspreyEffector, evt *osprey.ResultEvent, kind string, effects []T, supported bool) []T {
+	if supported {
+		return effects
+	}
+
+	kept := effects[:0]
+	for _, e := range effects {
+		if e.GetSubjectKind() != osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE {
+			kept = append(kept, e)
+			continue
+		}
+		or.logger.Error("dropping effect that can't be applied to a chat message",
+			"kind", kind,
+			"hasMessage", evt.Message != nil,
+			"actionId", evt.ActionId,
+			"actionName", evt.ActionName,
+		)
+		messageEffectsDropped.WithLabelValues(kind).Inc()
+	}
+	return kept
+}
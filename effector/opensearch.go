@@ -0,0 +1,315 @@
+package effector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	opensearchIndexed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "opensearch_indexed",
+		Namespace: NAMESPACE,
+		Help:      "number of documents bulk indexed into OpenSearch, by status",
+	}, []string{"status"})
+
+	opensearchDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "opensearch_dropped",
+		Namespace: NAMESPACE,
+		Help:      "number of logs dropped because the OpenSearch queue was full",
+	})
+)
+
+// OpenSearchLogger bulk indexes effect logs, and optionally event logs, into daily OpenSearch (or
+// Elasticsearch) indices, so that action history is searchable without BigQuery. Index templates
+// for the indices are installed on startup.
+type OpenSearchLogger struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+	logger   *slog.Logger
+
+	indexPrefix   string
+	includeEvents bool
+	batchSize     int
+	flushInterval time.Duration
+
+	queue chan opensearchDoc
+	wg    sync.WaitGroup
+}
+
+type OpenSearchLoggerArgs struct {
+	URL      string
+	Username string
+	Password string
+	Logger   *slog.Logger
+
+	// IndexPrefix names the indices, as <prefix>-effects-YYYY.MM.DD and <prefix>-events-YYYY.MM.DD.
+	IndexPrefix string
+	// IncludeEvents also indexes every event, not just the effects taken on them.
+	IncludeEvents bool
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+type opensearchDoc struct {
+	index string
+	doc   any
+}
+
+// Mappings for the fields of OspreyEffectLog and OspreyEventLog, by their JSON names. Comments and
+// rules are full-text searchable, with keyword subfields for exact matches and aggregations.
+const (
+	opensearchEffectsMappings = `{
+	"properties": {
+		"actionName": {"type": "keyword"},
+		"actionId": {"type": "long"},
+		"subject": {"type": "keyword"},
+		"handle": {"type": "keyword"},
+		"kind": {"type": "keyword"},
+		"rules": {"type": "text", "fields": {"keyword": {"type": "keyword", "ignore_above": 1024}}},
+		"comment": {"type": "text"},
+		"label": {"type": "keyword"},
+		"tag": {"type": "keyword"},
+		"email": {"type": "keyword"},
+		"shadow": {"type": "boolean"},
+		"ozoneEventId": {"type": "long"},
+		"error": {"type": "text"},
+		"emailLang": {"type": "keyword"},
+		"ozoneSet": {"type": "keyword"},
+		"createdAt": {"type": "date"}
+	}
+}`
+
+	opensearchEventsMappings = `{
+	"properties": {
+		"actionName": {"type": "keyword"},
+		"actionId": {"type": "long"},
+		"did": {"type": "keyword"},
+		"uri": {"type": "keyword"},
+		"cid": {"type": "keyword"},
+		"raw": {"type": "text"},
+		"sendTime": {"type": "date"},
+		"createdAt": {"type": "date"}
+	}
+}`
+)
+
+func NewOpenSearchLogger(ctx context.Context, args *OpenSearchLoggerArgs) (*OpenSearchLogger, error) {
+	if args.IndexPrefix == "" {
+		args.IndexPrefix = "osprey"
+	}
+	if args.BatchSize == 0 {
+		args.BatchSize = 200
+	}
+	if args.FlushInterval == 0 {
+		args.FlushInterval = 5 * time.Second
+	}
+
+	l := &OpenSearchLogger{
+		url:      strings.TrimSuffix(args.URL, "/"),
+		username: args.Username,
+		password: args.Password,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger:        args.Logger.With("component", "opensearch_logger"),
+		indexPrefix:   args.IndexPrefix,
+		includeEvents: args.IncludeEvents,
+		batchSize:     args.BatchSize,
+		flushInterval: args.FlushInterval,
+		queue:         make(chan opensearchDoc, args.BatchSize*10),
+	}
+
+	if err := l.putIndexTemplate(ctx, "effects", opensearchEffectsMappings); err != nil {
+		return nil, err
+	}
+	if err := l.putIndexTemplate(ctx, "events", opensearchEventsMappings); err != nil {
+		return nil, err
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return l, nil
+}
+
+// putIndexTemplate installs the template for the kind of index, replacing any existing one so
+// that mapping changes apply to the next day's index.
+func (l *OpenSearchLogger) putIndexTemplate(ctx context.Context, kind string, mappings string) error {
+	name := fmt.Sprintf("%s-%s", l.indexPrefix, kind)
+	template := fmt.Sprintf(`{
+	"index_patterns": [%q],
+	"template": {
+		"mappings": %s
+	}
+}`, name+"-*", mappings)
+
+	resp, err := l.do(ctx, "PUT", "/_index_template/"+name, "application/json", []byte(template))
+	if err != nil {
+		return fmt.Errorf("failed to put %s index template: %w", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to put %s index template: status %d: %s", kind, resp.StatusCode, b)
+	}
+	return nil
+}
+
+func (l *OpenSearchLogger) Name() string {
+	return "opensearch"
+}
+
+func (l *OpenSearchLogger) LogEvent(ctx context.Context, log *OspreyEventLog) error {
+	if !l.includeEvents {
+		return nil
+	}
+	l.enqueue(opensearchDoc{index: l.indexName("events", log.CreatedAt), doc: log})
+	return nil
+}
+
+func (l *OpenSearchLogger) LogEffect(ctx context.Context, log *OspreyEffectLog) error {
+	l.enqueue(opensearchDoc{index: l.indexName("effects", log.CreatedAt), doc: log})
+	return nil
+}
+
+func (l *OpenSearchLogger) indexName(kind string, t time.Time) string {
+	return fmt.Sprintf("%s-%s-%s", l.indexPrefix, kind, t.UTC().Format("2006.01.02"))
+}
+
+// enqueue drops the log rather than holding up effects while OpenSearch is backed up.
+func (l *OpenSearchLogger) enqueue(doc opensearchDoc) {
+	select {
+	case l.queue <- doc:
+	default:
+		opensearchDropped.Inc()
+		l.logger.Warn("dropped opensearch log because the queue is full")
+	}
+}
+
+func (l *OpenSearchLogger) run() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	var batch []opensearchDoc
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		l.bulkIndex(batch)
+		batch = nil
+	}
+
+	for {
+		select {
+		case doc, ok := <-l.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, doc)
+			if len(batch) >= l.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+type opensearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+func (l *OpenSearchLogger) bulkIndex(batch []opensearchDoc) {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, d := range batch {
+		enc.Encode(map[string]any{"index": map[string]string{"_index": d.index}})
+		if err := enc.Encode(d.doc); err != nil {
+			l.logger.Error("failed to marshal opensearch document", "error", err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := l.do(ctx, "POST", "/_bulk", "application/x-ndjson", body.Bytes())
+	if err != nil {
+		opensearchIndexed.WithLabelValues("error").Add(float64(len(batch)))
+		l.logger.Error("failed to bulk index into opensearch", "error", err, "docs", len(batch))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		opensearchIndexed.WithLabelValues("error").Add(float64(len(batch)))
+		b, _ := io.ReadAll(resp.Body)
+		l.logger.Error("failed to bulk index into opensearch", "status", resp.StatusCode, "body", string(b), "docs", len(batch))
+		return
+	}
+
+	var res opensearchBulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		l.logger.Error("failed to decode opensearch bulk response", "error", err)
+		return
+	}
+
+	failed := 0
+	if res.Errors {
+		for _, item := range res.Items {
+			for _, r := range item {
+				if r.Error == nil {
+					continue
+				}
+				if failed == 0 {
+					l.logger.Error("failed to index opensearch document", "type", r.Error.Type, "reason", r.Error.Reason)
+				}
+				failed++
+			}
+		}
+	}
+	opensearchIndexed.WithLabelValues("error").Add(float64(failed))
+	opensearchIndexed.WithLabelValues("ok").Add(float64(len(batch) - failed))
+}
+
+func (l *OpenSearchLogger) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, l.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("content-type", contentType)
+	if l.username != "" {
+		req.SetBasicAuth(l.username, l.password)
+	}
+	return l.client.Do(req)
+}
+
+// Close indexes whatever is queued. Logs mustn't be written after it's called.
+func (l *OpenSearchLogger) Close() {
+	close(l.queue)
+	l.wg.Wait()
+}
@@ -0,0 +1,239 @@
+package effector
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/bluesky-social/indigo/api/ozone"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordSent is called with each event sent to Ozone.
+func (or *OspreyEffector) recordSent(ctx context.Context, input *ozone.ModerationEmitEvent_Input, view *ozone.ModerationDefs_ModEventView, err error) {
+	if sent, ok := ctx.Value(sentEventKey{}).(*sentEvent); ok && view != nil && !sent.id.Valid {
+		sent.id = bigquery.NullInt64{Int64: view.Id, Valid: true}
+	}
+	if err != nil {
+		or.logFailedEffect(ctx, input, err)
+	}
+	or.produceOutcome(ctx, input, view, false, err)
+}
+
+type sentEventKey struct{}
+
+// sentEvent captures the ID of the Ozone event an effect created, for its effect log. Some effects
+// send more than one event, e.g. a takedown and its email, in which case it's the first.
+type sentEvent struct {
+	id bigquery.NullInt64
+	// dryRun is set when the effect wasn't sent because this isn't production.
+	dryRun bool
+}
+
+// withSentEvent returns a context for applying a single effect, which records the Ozone event it
+// creates in the returned sentEvent.
+func withSentEvent(ctx context.Context) (context.Context, *sentEvent) {
+	sent := &sentEvent{}
+	return context.WithValue(ctx, sentEventKey{}, sent), sent
+}
+
+func (s *sentEvent) eventID() bigquery.NullInt64 {
+	return s.id
+}
+
+// markDryRun records that the effect being applied with the context was only a dry run.
+func markDryRun(ctx context.Context) {
+	if sent, ok := ctx.Value(sentEventKey{}).(*sentEvent); ok {
+		sent.dryRun = true
+	}
+}
+
+// logFailedEffect writes an Ozone event that failed to the effect log along with the error, so
+// that failures can be audited next to the effects that were applied, and reports it to the error
+// sinks.
+func (or *OspreyEffector) logFailedEffect(ctx context.Context, input *ozone.ModerationEmitEvent_Input, err error) {
+	meta := emitEventMeta(input)
+	actionName, _ := ctx.Value(actionNameKey{}).(string)
+
+	log := &OspreyEffectLog{
+		ActionName: actionName,
+		ActionID:   meta.ActionID,
+		Subject:    emitEventSubject(input),
+		Kind:       effectKind(ozoneEventKind(input.Event)),
+		Rules:      meta.Rules,
+		CreatedAt:  time.Now(),
+		Error:      bigquery.NullString{StringVal: err.Error(), Valid: true},
+	}
+
+	ev := input.Event
+	switch {
+	case ev == nil:
+	case ev.ModerationDefs_ModEventLabel != nil:
+		labels := slices.Concat(ev.ModerationDefs_ModEventLabel.CreateLabelVals, ev.ModerationDefs_ModEventLabel.NegateLabelVals)
+		log.Label = bigquery.NullString{StringVal: strings.Join(labels, ","), Valid: len(labels) > 0}
+	case ev.ModerationDefs_ModEventTag != nil:
+		tags := slices.Concat(ev.ModerationDefs_ModEventTag.Add, ev.ModerationDefs_ModEventTag.Remove)
+		log.Tag = bigquery.NullString{StringVal: strings.Join(tags, ","), Valid: len(tags) > 0}
+	}
+	if comment := emitEventComment(ev); comment != nil {
+		log.Comment = *comment
+	}
+
+	or.logEffect(log)
+	or.captureEffectFailure(ctx, &EffectFailure{
+		ActionName: log.ActionName,
+		ActionID:   log.ActionID,
+		Kind:       log.Kind,
+		Subject:    log.Subject,
+		Rules:      log.Rules,
+		Err:        err,
+	})
+}
+
+// produceOutcome publishes the outcome of an Ozone event to the outcomes topic, if there is one.
+// Outcomes are produced asynchronously, so a slow topic doesn't hold up effects.
+func (or *OspreyEffector) produceOutcome(ctx context.Context, input *ozone.ModerationEmitEvent_Input, view *ozone.ModerationDefs_ModEventView, dryRun bool, err error) {
+	if or.outcomeProducer == nil {
+		return
+	}
+
+	meta := emitEventMeta(input)
+	actionName, _ := ctx.Value(actionNameKey{}).(string)
+
+	outcome := &osprey.EffectOutcome{
+		Timestamp:  timestamppb.Now(),
+		ActionName: actionName,
+		ActionId:   meta.ActionID,
+		Kind:       ozoneEventKind(input.Event),
+		Subject:    emitEventSubject(input),
+		Success:    err == nil,
+		DryRun:     dryRun,
+	}
+	if meta.Rules != "" {
+		outcome.Rules = strings.Split(meta.Rules, ",")
+	}
+	if err != nil {
+		msg := err.Error()
+		outcome.Error = &msg
+	}
+	if view != nil {
+		outcome.OzoneEventId = &view.Id
+	}
+
+	if err := or.outcomeProducer.ProduceAsync(context.Background(), outcome.Subject, outcome, func(_ *kgo.Record, err error) {
+		if err != nil {
+			or.logger.Error("failed to produce effect outcome", "actionId", outcome.ActionId, "error", err)
+		}
+	}); err != nil {
+		or.logger.Error("failed to produce effect outcome", "actionId", outcome.ActionId, "error", err)
+	}
+}
+
+// emitEventSubject returns the DID or AT-URI the event is about.
+func emitEventSubject(input *ozone.ModerationEmitEvent_Input) string {
+	switch {
+	case input.Subject.AdminDefs_RepoRef != nil:
+		return input.Subject.AdminDefs_RepoRef.Did
+	case input.Subject.RepoStrongRef != nil:
+		return input.Subject.RepoStrongRef.Uri
+	}
+	return ""
+}
+
+// emitEventMeta returns the metadata the effector attached to the event.
+func emitEventMeta(input *ozone.ModerationEmitEvent_Input) ModToolMeta {
+	var meta ModToolMeta
+	if input.ModTool != nil && input.ModTool.Meta != nil {
+		meta, _ = (*input.ModTool.Meta).(ModToolMeta)
+	}
+	return meta
+}
+
+// emitEventComment returns the comment the effector attached to the event.
+func emitEventComment(ev *ozone.ModerationEmitEvent_Input_Event) *string {
+	switch {
+	case ev == nil:
+		return nil
+	case ev.ModerationDefs_ModEventTakedown != nil:
+		return ev.ModerationDefs_ModEventTakedown.Comment
+	case ev.ModerationDefs_ModEventReverseTakedown != nil:
+		return ev.ModerationDefs_ModEventReverseTakedown.Comment
+	case ev.ModerationDefs_ModEventLabel != nil:
+		return ev.ModerationDefs_ModEventLabel.Comment
+	case ev.ModerationDefs_ModEventTag != nil:
+		return ev.ModerationDefs_ModEventTag.Comment
+	case ev.ModerationDefs_ModEventMute != nil:
+		return ev.ModerationDefs_ModEventMute.Comment
+	case ev.ModerationDefs_ModEventUnmute != nil:
+		return ev.ModerationDefs_ModEventUnmute.Comment
+	case ev.ModerationDefs_ModEventDivert != nil:
+		return ev.ModerationDefs_ModEventDivert.Comment
+	case ev.ModerationDefs_ModEventComment != nil:
+		return ev.ModerationDefs_ModEventComment.Comment
+	case ev.ModerationDefs_ModEventReport != nil:
+		return ev.ModerationDefs_ModEventReport.Comment
+	case ev.ModerationDefs_ModEventEscalate != nil:
+		return ev.ModerationDefs_ModEventEscalate.Comment
+	case ev.ModerationDefs_ModEventResolveAppeal != nil:
+		return ev.ModerationDefs_ModEventResolveAppeal.Comment
+	case ev.ModerationDefs_ModEventAcknowledge != nil:
+		return ev.ModerationDefs_ModEventAcknowledge.Comment
+	}
+	return nil
+}
+
+// ozoneEventKind names the kinds of Ozone events the effector sends.
+func ozoneEventKind(ev *ozone.ModerationEmitEvent_Input_Event) string {
+	switch {
+	case ev == nil:
+		return ""
+	case ev.ModerationDefs_ModEventTakedown != nil:
+		return "takedown"
+	case ev.ModerationDefs_ModEventReverseTakedown != nil:
+		return "reverse-takedown"
+	case ev.ModerationDefs_ModEventLabel != nil:
+		return "label"
+	case ev.ModerationDefs_ModEventTag != nil:
+		return "tag"
+	case ev.ModerationDefs_ModEventMute != nil:
+		return "mute"
+	case ev.ModerationDefs_ModEventUnmute != nil:
+		return "unmute"
+	case ev.ModerationDefs_ModEventDivert != nil:
+		return "divert"
+	case ev.ModerationDefs_ModEventComment != nil:
+		return "comment"
+	case ev.ModerationDefs_ModEventReport != nil:
+		return "report"
+	case ev.ModerationDefs_ModEventPriorityScore != nil:
+		return "priority-score"
+	case ev.ModerationDefs_ModEventEscalate != nil:
+		return "escalate"
+	case ev.ModerationDefs_ModEventResolveAppeal != nil:
+		return "resolve-appeal"
+	case ev.ModerationDefs_ModEventAcknowledge != nil:
+		return "acknowledge"
+	case ev.ModerationDefs_ModEventEmail != nil:
+		return "email"
+	}
+	return "other"
+}
+
+// effectKind returns the effect kind, as named in effect logs, that sends the kind of Ozone event.
+func effectKind(ozoneKind string) string {
+	switch ozoneKind {
+	case "takedown", "reverse-takedown":
+		return EffectTakedown
+	case "mute", "unmute":
+		return EffectMute
+	case "escalate":
+		return EffectEscalation
+	case "acknowledge":
+		return EffectAcknowledgement
+	}
+	return ozoneKind
+}
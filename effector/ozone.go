@@ -2,18 +2,26 @@ package effector
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/bluesky-social/indigo/api/atproto"
 	"github.com/bluesky-social/indigo/api/ozone"
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/atproto/identity"
 	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
 	"github.com/bluesky-social/indigo/xrpc"
 	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
 	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -29,9 +37,45 @@ type OzoneClient struct {
 	refreshMu sync.Mutex
 	logger    *slog.Logger
 
-	templates []CommunicationTemplate
+	identifier string
+	password   string
+	// sessions, when set, persists the session so that restarts resume it instead of logging in.
+	sessions SessionStore
+	// oauthClient, when set, is the OAuth session used instead of the password session in client.
+	oauthClient *atclient.APIClient
+	// sessionRevoked is set when Ozone rejects the session, so that it's refreshed even though its
+	// access token hasn't expired yet.
+	sessionRevoked atomic.Bool
+	// reloginFailures and reloginAfter back off starting the session over, under refreshMu.
+	reloginFailures int
+	reloginAfter    time.Time
+
+	directory identity.Directory
+
+	// templates are Ozone's communication templates, cached for templatesTTL.
+	templatesMu        sync.Mutex
+	templates          []CommunicationTemplate
+	templatesFetchedAt time.Time
+
+	// labelerDid is the DID of the labeler the effector acts as, whose labels GetSubjectStatus reports.
+	labelerDid string
 
 	isProduction bool
+	// testSubjects are DIDs whose effects are applied even outside of production.
+	testSubjects map[string]bool
+	// dryRun, when set, is handed the events that aren't sent because this isn't production.
+	dryRun func(ctx context.Context, input *ozone.ModerationEmitEvent_Input)
+	// sent, when set, is handed each event sent to Ozone along with the result.
+	sent func(ctx context.Context, input *ozone.ModerationEmitEvent_Input, view *ozone.ModerationDefs_ModEventView, err error)
+	// limiter, when set, paces the events sent to Ozone by priority.
+	limiter *ozoneLimiter
+
+	// standby, when set, is the account events are sent as while the primary is failed over, until
+	// failoverUntil in unix nanos. failover, when set, is handed alerts about failing over and back.
+	standby        *OzoneClient
+	failoverUntil  atomic.Int64
+	failoverActive atomic.Bool
+	failover       func(ctx context.Context, msg string)
 }
 
 type OzoneClientArgs struct {
@@ -42,6 +86,24 @@ type OzoneClientArgs struct {
 	Logger *slog.Logger
 
 	IsProduction bool
+	// TestSubjectDids are accounts whose effects are applied even outside of production, for
+	// trying out rules end to end in staging.
+	TestSubjectDids []string
+
+	// RateLimit is how many events per second are sent to Ozone, with bursts of up to RateBurst.
+	// Events beyond that wait their turn, takedowns first and comments last. Zero disables it.
+	RateLimit float64
+	RateBurst int
+
+	// SessionStore, when set, persists the session across restarts. Logins are rate limited by
+	// the PDS, so frequent deploys would otherwise trip them.
+	SessionStore SessionStore
+	// OAuth, when set, authenticates with the OAuth session stored for Identifier instead of
+	// Password. It requires a SessionStore.
+	OAuth *OzoneOAuthArgs
+	// Standby, when set, is an account events are failed over to when the primary account is rate
+	// limited or its session can't be established.
+	Standby *OzoneStandbyArgs
 
 	ProxyDid string
 }
@@ -58,9 +120,44 @@ func NewOzoneClient(ctx context.Context, args *OzoneClientArgs) (*OzoneClient, e
 
 	args.Logger = args.Logger.With("component", "ozone_client")
 
+	baseDirectory := identity.BaseDirectory{
+		PLCURL:    identity.DefaultPLCURL,
+		UserAgent: ClientName,
+	}
+	directory := identity.NewCacheDirectory(&baseDirectory, 100_000, 6*time.Hour, 2*time.Minute, 10*time.Minute)
+
 	oc := &OzoneClient{
 		logger:       args.Logger,
+		directory:    &directory,
+		labelerDid:   args.ProxyDid,
 		isProduction: args.IsProduction,
+		testSubjects: map[string]bool{},
+		identifier:   args.Identifier,
+		password:     args.Password,
+		sessions:     args.SessionStore,
+	}
+	for _, did := range args.TestSubjectDids {
+		oc.testSubjects[did] = true
+	}
+	if args.RateLimit > 0 {
+		oc.limiter = newOzoneLimiter(rate.Limit(args.RateLimit), args.RateBurst)
+	}
+	if args.Standby != nil {
+		oc.standby = newStandbyClient(oc, args)
+	}
+
+	if args.OAuth != nil {
+		if args.SessionStore == nil {
+			return nil, errors.New("must supply a session store to authenticate to ozone with oauth")
+		}
+		app, err := newOAuthApp(args.OAuth, args.SessionStore)
+		if err != nil {
+			return nil, err
+		}
+		if err := oc.startOAuthSession(ctx, app, args.ProxyDid); err != nil {
+			return nil, err
+		}
+		return oc, nil
 	}
 
 	cli := &xrpc.Client{
@@ -70,9 +167,73 @@ func NewOzoneClient(ctx context.Context, args *OzoneClientArgs) (*OzoneClient, e
 		},
 	}
 
+	if err := oc.startSession(ctx, cli); err != nil {
+		if oc.standby == nil {
+			return nil, err
+		}
+		// Starting up is what fails when the account is locked out, so the first event fails over.
+		oc.logger.Error("failed to start ozone session, starting with the standby account", "error", err)
+		cli.Auth = &xrpc.AuthInfo{}
+		oc.client.Store(cli)
+	}
+
+	return oc, nil
+}
+
+// startSession resumes the stored session if there is one, refreshing it if its access token is
+// about to expire, and only logs in when there's no session to resume or it can't be refreshed.
+func (oc *OzoneClient) startSession(ctx context.Context, base *xrpc.Client) error {
+	if oc.sessions != nil {
+		stored, err := oc.sessions.Load(ctx, oc.identifier)
+		if err != nil {
+			oc.logger.Warn("failed to load stored ozone session, logging in", "error", err)
+		}
+		// The session being started over is skipped, in case it was stored before it was revoked.
+		stale := base.Auth != nil && stored != nil && stored.RefreshJwt == base.Auth.RefreshJwt
+		if stored != nil && stored.Host == base.Host && !stale {
+			resumed := &xrpc.Client{
+				Host:    base.Host,
+				Headers: base.Headers,
+				Auth: &xrpc.AuthInfo{
+					AccessJwt:  stored.AccessJwt,
+					RefreshJwt: stored.RefreshJwt,
+					Handle:     stored.Handle,
+					Did:        stored.Did,
+				},
+			}
+			if accessTokenValid(resumed.Auth) {
+				oc.client.Store(resumed)
+				oc.logger.Info("resumed stored ozone session")
+				return nil
+			}
+			refreshed, err := oc.refresh(ctx, resumed)
+			if err == nil {
+				oc.client.Store(refreshed)
+				oc.logger.Info("resumed and refreshed stored ozone session")
+				return nil
+			}
+			oc.logger.Warn("failed to refresh stored ozone session, logging in", "error", err)
+		}
+	}
+
+	cli, err := oc.login(ctx, base)
+	if err != nil {
+		return err
+	}
+	oc.client.Store(cli)
+	return nil
+}
+
+// login creates a new session, which is rate limited by the PDS.
+func (oc *OzoneClient) login(ctx context.Context, base *xrpc.Client) (*xrpc.Client, error) {
+	cli := &xrpc.Client{
+		Host:    base.Host,
+		Headers: base.Headers,
+	}
+
 	resp, err := atproto.ServerCreateSession(ctx, cli, &atproto.ServerCreateSession_Input{
-		Identifier: args.Identifier,
-		Password:   args.Password,
+		Identifier: oc.identifier,
+		Password:   oc.password,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not create auth session: %w", err)
@@ -84,41 +245,14 @@ func NewOzoneClient(ctx context.Context, args *OzoneClientArgs) (*OzoneClient, e
 		Handle:     resp.Handle,
 		Did:        resp.Did,
 	}
+	oc.logger.Info("created ozone session")
+	oc.saveSession(ctx, cli)
 
-	oc.client.Store(cli)
-
-	return oc, nil
+	return cli, nil
 }
 
-func (oc *OzoneClient) GetClient(ctx context.Context) (*xrpc.Client, error) {
-	client := oc.client.Load().(*xrpc.Client)
-
-	token, _, _ := new(jwt.Parser).ParseUnverified(client.Auth.AccessJwt, jwt.MapClaims{})
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		if exp, ok := claims["exp"].(float64); ok {
-			expiration := time.Unix(int64(exp), 0)
-			if time.Until(expiration) > 5*time.Minute {
-				return client, nil
-			}
-		}
-	}
-
-	oc.refreshMu.Lock()
-	defer oc.refreshMu.Unlock()
-
-	client = oc.client.Load().(*xrpc.Client)
-	token, _, _ = new(jwt.Parser).ParseUnverified(client.Auth.AccessJwt, jwt.MapClaims{})
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		if exp, ok := claims["exp"].(float64); ok {
-			expiration := time.Unix(int64(exp), 0)
-			if time.Until(expiration) > 5*time.Minute {
-				return client, nil
-			}
-		}
-	}
-
-	oc.logger.Info("refreshing auth token...")
-
+// refresh exchanges the client's refresh token for a new session.
+func (oc *OzoneClient) refresh(ctx context.Context, client *xrpc.Client) (*xrpc.Client, error) {
 	tempClient := &xrpc.Client{
 		Host:    client.Host,
 		Headers: client.Headers,
@@ -132,8 +266,7 @@ func (oc *OzoneClient) GetClient(ctx context.Context) (*xrpc.Client, error) {
 
 	res, err := atproto.ServerRefreshSession(ctx, tempClient)
 	if err != nil {
-		oc.logger.Error("error refreshing session", "error", err)
-		return client, fmt.Errorf("failed to refresh token: %w", err)
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
 
 	newClient := &xrpc.Client{
@@ -146,62 +279,310 @@ func (oc *OzoneClient) GetClient(ctx context.Context) (*xrpc.Client, error) {
 			Did:        client.Auth.Did,
 		},
 	}
+	oc.saveSession(ctx, newClient)
+
+	return newClient, nil
+}
+
+// saveSession persists the client's session, if there's a session store. Failing to is only
+// logged, since it costs a login on the next start at worst.
+func (oc *OzoneClient) saveSession(ctx context.Context, cli *xrpc.Client) {
+	if oc.sessions == nil {
+		return
+	}
+	if err := oc.sessions.Save(ctx, &StoredSession{
+		Host:       cli.Host,
+		Identifier: oc.identifier,
+		AccessJwt:  cli.Auth.AccessJwt,
+		RefreshJwt: cli.Auth.RefreshJwt,
+		Handle:     cli.Auth.Handle,
+		Did:        cli.Auth.Did,
+	}); err != nil {
+		oc.logger.Error("failed to save ozone session", "error", err)
+	}
+}
+
+// accessTokenValid reports whether the access token is good for at least another five minutes.
+func accessTokenValid(auth *xrpc.AuthInfo) bool {
+	token, _, _ := new(jwt.Parser).ParseUnverified(auth.AccessJwt, jwt.MapClaims{})
+	if token == nil {
+		return false
+	}
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if exp, ok := claims["exp"].(float64); ok {
+			expiration := time.Unix(int64(exp), 0)
+			return time.Until(expiration) > 5*time.Minute
+		}
+	}
+	return false
+}
+
+// checkSessionRevoked marks the session to be refreshed if Ozone rejected it, e.g. because it was
+// revoked, since its access token can't tell.
+func (oc *OzoneClient) checkSessionRevoked(err error) {
+	var xerr *xrpc.Error
+	if errors.As(err, &xerr) && xerr.StatusCode == http.StatusUnauthorized {
+		oc.sessionRevoked.Store(true)
+	}
+}
+
+// Close closes the session store, if there is one.
+func (oc *OzoneClient) Close() {
+	if oc.sessions != nil {
+		oc.sessions.Close()
+	}
+	if oc.standby != nil {
+		oc.standby.Close()
+	}
+}
+
+// lexClient returns the client to call Ozone with and the DID of the account it's authenticated
+// as, which is the standby account's while failed over to it.
+func (oc *OzoneClient) lexClient(ctx context.Context) (util.LexClient, string, error) {
+	if oc.failedOver() {
+		return oc.standby.sessionClient(ctx)
+	}
+	cli, did, err := oc.sessionClient(ctx)
+	if err != nil && oc.standby != nil {
+		until, reason, _ := shouldFailOver(err)
+		oc.failOver(ctx, err, until, reason)
+		return oc.standby.sessionClient(ctx)
+	}
+	return cli, did, err
+}
+
+// sessionClient returns the client for the account's own session, which is the OAuth session when
+// there is one and the password session otherwise.
+func (oc *OzoneClient) sessionClient(ctx context.Context) (util.LexClient, string, error) {
+	if oc.oauthClient != nil {
+		return oc.oauthClient, oc.oauthClient.AccountDID.String(), nil
+	}
+	cli, err := oc.GetClient(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", errOzoneSession, err)
+	}
+	return cli, cli.Auth.Did, nil
+}
+
+// accountDid is the DID of the account events are sent to Ozone as.
+func (oc *OzoneClient) accountDid() string {
+	if oc.failedOver() {
+		return oc.standby.accountDid()
+	}
+	if oc.oauthClient != nil {
+		return oc.oauthClient.AccountDID.String()
+	}
+	return oc.client.Load().(*xrpc.Client).Auth.Did
+}
+
+func (oc *OzoneClient) GetClient(ctx context.Context) (*xrpc.Client, error) {
+	client := oc.client.Load().(*xrpc.Client)
+	if accessTokenValid(client.Auth) && !oc.sessionRevoked.Load() {
+		return client, nil
+	}
+
+	oc.refreshMu.Lock()
+	defer oc.refreshMu.Unlock()
+
+	client = oc.client.Load().(*xrpc.Client)
+	if accessTokenValid(client.Auth) && !oc.sessionRevoked.Load() {
+		return client, nil
+	}
+
+	if client.Auth.RefreshJwt == "" {
+		// Never had a session, e.g. the standby account before it's first used.
+		if err := oc.relogin(ctx, client); err != nil {
+			return client, fmt.Errorf("failed to start session: %w", err)
+		}
+		return oc.client.Load().(*xrpc.Client), nil
+	}
+
+	oc.logger.Info("refreshing auth token...")
+
+	newClient, err := oc.refresh(ctx, client)
+	if err != nil {
+		// The refresh token may have been revoked, or rotated by another replica sharing the
+		// stored session, in which case the stored session or a new login takes over.
+		oc.logger.Error("error refreshing session, starting a new one", "error", err)
+		if err := oc.relogin(ctx, client); err != nil {
+			return client, fmt.Errorf("failed to refresh token: %w", err)
+		}
+		return oc.client.Load().(*xrpc.Client), nil
+	}
 
 	oc.client.Store(newClient)
+	oc.sessionRevoked.Store(false)
 	oc.logger.Info("ozone session refreshed")
 
 	return newClient, nil
 }
 
-func (oc *OzoneClient) TakedownActor(ctx context.Context, did string, meta ModToolMeta, comment string, emailTemplate *osprey.AtprotoEmail, reverse bool) error {
+// relogin starts the session over after it couldn't be refreshed, backing off exponentially while
+// that keeps failing. It's called with refreshMu held.
+func (oc *OzoneClient) relogin(ctx context.Context, client *xrpc.Client) error {
+	if wait := time.Until(oc.reloginAfter); wait > 0 {
+		return fmt.Errorf("backing off logging in again for %s", wait.Round(time.Second))
+	}
+
+	if err := oc.startSession(ctx, client); err != nil {
+		oc.reloginFailures++
+		backoff := min(reloginBaseBackoff<<min(oc.reloginFailures-1, 16), reloginMaxBackoff)
+		oc.reloginAfter = time.Now().Add(backoff)
+		ozoneRelogins.WithLabelValues("error").Inc()
+		oc.logger.Error("failed to log in to ozone again", "error", err, "failures", oc.reloginFailures, "backoff", backoff)
+		return err
+	}
+
+	oc.reloginFailures = 0
+	oc.reloginAfter = time.Time{}
+	oc.sessionRevoked.Store(false)
+	ozoneRelogins.WithLabelValues("ok").Inc()
+	oc.logger.Info("logged in to ozone again")
+	return nil
+}
+
+// live reports whether effects on the account should actually be applied, which is always the case
+// in production and otherwise only for test subjects.
+func (oc *OzoneClient) live(did string) bool {
+	return oc.isProduction || oc.testSubjects[did]
+}
+
+// emit sends the moderation event to Ozone. Outside of production the event is built all the same,
+// but only logged and handed to the dry run handler, so that staging shows what would've been sent.
+func (oc *OzoneClient) emit(ctx context.Context, input *ozone.ModerationEmitEvent_Input) error {
+	var did string
+	switch {
+	case input.Subject.AdminDefs_RepoRef != nil:
+		did = input.Subject.AdminDefs_RepoRef.Did
+	case input.Subject.RepoStrongRef != nil:
+		if aturi, err := syntax.ParseATURI(input.Subject.RepoStrongRef.Uri); err == nil {
+			did = aturi.Authority().String()
+		}
+	}
+	if oc.live(did) {
+		view, err := oc.send(ctx, input)
+		if oc.sent != nil {
+			oc.sent(ctx, input, view, err)
+		}
+		return err
+	}
+
+	input.CreatedBy = oc.accountDid()
+
+	b, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dry run event: %w", err)
+	}
+	oc.logger.Info("dry run, not sending moderation event", "event", string(b))
+	markDryRun(ctx)
+
+	if oc.dryRun != nil {
+		oc.dryRun(ctx, input)
+	}
+	return nil
+}
+
+// send sends the event as the primary account, failing over to the standby account if there is one
+// and the primary can't send it.
+func (oc *OzoneClient) send(ctx context.Context, input *ozone.ModerationEmitEvent_Input) (*ozone.ModerationDefs_ModEventView, error) {
+	if oc.failedOver() {
+		return oc.standby.sendAs(ctx, input)
+	}
+
+	view, err := oc.sendAs(ctx, input)
+	if oc.standby == nil {
+		return view, err
+	}
+	if err == nil {
+		oc.failBack(ctx)
+		return view, nil
+	}
+	until, reason, ok := shouldFailOver(err)
+	if !ok {
+		return view, err
+	}
+	oc.failOver(ctx, err, until, reason)
+	return oc.standby.sendAs(ctx, input)
+}
+
+// sendAs sends the event as the account's own session.
+func (oc *OzoneClient) sendAs(ctx context.Context, input *ozone.ModerationEmitEvent_Input) (*ozone.ModerationDefs_ModEventView, error) {
+	cli, did, err := oc.sessionClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if oc.limiter != nil {
+		if err := oc.limiter.wait(ctx, ozoneEventKind(input.Event)); err != nil {
+			return nil, fmt.Errorf("failed waiting for ozone rate limiter: %w", err)
+		}
+	}
+
+	input.CreatedBy = did
+
+	start := time.Now()
+	var view *ozone.ModerationDefs_ModEventView
+	if ref, ok := ctx.Value(messageSubjectKey{}).(*osprey.ChatMessageRef); ok {
+		view, err = emitMessageEvent(ctx, cli, input, ref)
+	} else {
+		view, err = ozone.ModerationEmitEvent(ctx, cli, input)
+	}
+	status := "ok"
+	if err != nil {
+		status = "error"
+		if oc.limiter != nil {
+			oc.limiter.rateLimited(err)
+		}
+		oc.checkSessionRevoked(err)
+	}
+	ozoneRequestDuration.WithLabelValues(ozoneEventKind(input.Event), status).Observe(time.Since(start).Seconds())
+
+	return view, err
+}
+
+func (oc *OzoneClient) TakedownActor(ctx context.Context, did string, meta ModToolMeta, comment string, emailTemplate *osprey.AtprotoEmail, policies []string, durationInHours *int64, reverse bool) error {
 	status := "error"
 	defer func() {
 		effectsProcessed.WithLabelValues("takedown-actor", status).Inc()
 	}()
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
+	t := true
+	var met *ozone.ModerationDefs_ModEventTakedown
+	var mert *ozone.ModerationDefs_ModEventReverseTakedown
+	if !reverse {
+		met = &ozone.ModerationDefs_ModEventTakedown{
+			Comment:                    &comment,
+			AcknowledgeAccountSubjects: &t,
+			Policies:                   policies,
+			DurationInHours:            durationInHours,
 		}
-
-		t := true
-		var met *ozone.ModerationDefs_ModEventTakedown
-		var mert *ozone.ModerationDefs_ModEventReverseTakedown
-		if !reverse {
-			met = &ozone.ModerationDefs_ModEventTakedown{
-				Comment:                    &comment,
-				AcknowledgeAccountSubjects: &t,
-			}
-		} else {
-			mert = &ozone.ModerationDefs_ModEventReverseTakedown{
-				Comment: &comment,
-			}
+	} else {
+		mert = &ozone.ModerationDefs_ModEventReverseTakedown{
+			Comment: &comment,
 		}
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventTakedown:        met,
-				ModerationDefs_ModEventReverseTakedown: mert,
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-					Did: did,
-				},
-			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventTakedown:        met,
+			ModerationDefs_ModEventReverseTakedown: mert,
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
 
-		if emailTemplate != nil {
-			if err := oc.SendEmail(ctx, did, *emailTemplate); err != nil {
-				return err
-			}
+	if oc.live(did) && emailTemplate != nil {
+		if _, err := oc.SendEmail(ctx, did, meta, *emailTemplate); err != nil {
+			return err
 		}
 	}
 
@@ -209,7 +590,7 @@ func (oc *OzoneClient) TakedownActor(ctx context.Context, did string, meta ModTo
 	return nil
 }
 
-func (oc *OzoneClient) TakedownRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment string, emailTemplate *osprey.AtprotoEmail, reverse bool) error {
+func (oc *OzoneClient) TakedownRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment string, emailTemplate *osprey.AtprotoEmail, policies []string, durationInHours *int64, reverse bool) error {
 	status := "error"
 	defer func() {
 		effectsProcessed.WithLabelValues("takedown-record", status).Inc()
@@ -220,50 +601,44 @@ func (oc *OzoneClient) TakedownRecord(ctx context.Context, uri string, cid strin
 		return fmt.Errorf("failed to parse aturi paseed to TakedownRecord: %w", err)
 	}
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
+	t := true
+	var met *ozone.ModerationDefs_ModEventTakedown
+	var mert *ozone.ModerationDefs_ModEventReverseTakedown
+	if !reverse {
+		met = &ozone.ModerationDefs_ModEventTakedown{
+			Comment:                    &comment,
+			AcknowledgeAccountSubjects: &t,
+			Policies:                   policies,
+			DurationInHours:            durationInHours,
 		}
-
-		t := true
-		var met *ozone.ModerationDefs_ModEventTakedown
-		var mert *ozone.ModerationDefs_ModEventReverseTakedown
-		if !reverse {
-			met = &ozone.ModerationDefs_ModEventTakedown{
-				Comment:                    &comment,
-				AcknowledgeAccountSubjects: &t,
-			}
-		} else {
-			mert = &ozone.ModerationDefs_ModEventReverseTakedown{
-				Comment: &comment,
-			}
+	} else {
+		mert = &ozone.ModerationDefs_ModEventReverseTakedown{
+			Comment: &comment,
 		}
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventTakedown:        met,
-				ModerationDefs_ModEventReverseTakedown: mert,
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				RepoStrongRef: &atproto.RepoStrongRef{
-					Uri: uri,
-					Cid: cid,
-				},
-			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventTakedown:        met,
+			ModerationDefs_ModEventReverseTakedown: mert,
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
 
-		if emailTemplate != nil {
-			if err := oc.SendEmail(ctx, aturi.Authority().String(), *emailTemplate); err != nil {
-				return err
-			}
+	if oc.live(aturi.Authority().String()) && emailTemplate != nil {
+		if _, err := oc.SendEmail(ctx, aturi.Authority().String(), meta, *emailTemplate); err != nil {
+			return err
 		}
 	}
 
@@ -277,55 +652,47 @@ func (oc *OzoneClient) LabelActor(ctx context.Context, did string, meta ModToolM
 		effectsProcessed.WithLabelValues("label-actor", status).Inc()
 	}()
 
-	if oc.isProduction {
-		if label == osprey.AtprotoLabel_ATPROTO_LABEL_NEEDS_REVIEW {
-			if durationInHours == nil || *durationInHours > NeedsReviewMaxTime {
-				durationInHours = &NeedsReviewMaxTime
-			}
+	if label == osprey.AtprotoLabel_ATPROTO_LABEL_NEEDS_REVIEW {
+		if durationInHours == nil || *durationInHours > NeedsReviewMaxTime {
+			durationInHours = &NeedsReviewMaxTime
 		}
+	}
 
-		labelStr := AtprotoLabelToString(label)
-
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
+	labelStr := AtprotoLabelToString(label)
 
-		cvals := []string{}
-		nvals := []string{}
-		if neg {
-			nvals = append(nvals, labelStr)
-		} else {
-			cvals = append(cvals, labelStr)
-		}
+	cvals := []string{}
+	nvals := []string{}
+	if neg {
+		nvals = append(nvals, labelStr)
+	} else {
+		cvals = append(cvals, labelStr)
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventLabel: &ozone.ModerationDefs_ModEventLabel{
-					CreateLabelVals: cvals,
-					NegateLabelVals: nvals,
-					Comment:         &comment,
-					DurationInHours: durationInHours,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-					Did: did,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventLabel: &ozone.ModerationDefs_ModEventLabel{
+				CreateLabelVals: cvals,
+				NegateLabelVals: nvals,
+				Comment:         &comment,
+				DurationInHours: durationInHours,
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
 
-		if email != nil {
-			if err := oc.SendEmail(ctx, did, *email); err != nil {
-				return err
-			}
+	if oc.live(did) && email != nil {
+		if _, err := oc.SendEmail(ctx, did, meta, *email); err != nil {
+			return err
 		}
 	}
 
@@ -344,56 +711,48 @@ func (oc *OzoneClient) LabelRecord(ctx context.Context, uri string, cid string,
 		return fmt.Errorf("failed to parse aturi paseed to LabelRecord: %w", err)
 	}
 
-	if oc.isProduction {
-		if label == osprey.AtprotoLabel_ATPROTO_LABEL_NEEDS_REVIEW {
-			if durationInHours == nil || *durationInHours > NeedsReviewMaxTime {
-				durationInHours = &NeedsReviewMaxTime
-			}
+	if label == osprey.AtprotoLabel_ATPROTO_LABEL_NEEDS_REVIEW {
+		if durationInHours == nil || *durationInHours > NeedsReviewMaxTime {
+			durationInHours = &NeedsReviewMaxTime
 		}
+	}
 
-		labelStr := AtprotoLabelToString(label)
-
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
+	labelStr := AtprotoLabelToString(label)
 
-		cvals := []string{}
-		nvals := []string{}
-		if neg {
-			nvals = append(nvals, labelStr)
-		} else {
-			cvals = append(cvals, labelStr)
-		}
+	cvals := []string{}
+	nvals := []string{}
+	if neg {
+		nvals = append(nvals, labelStr)
+	} else {
+		cvals = append(cvals, labelStr)
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventLabel: &ozone.ModerationDefs_ModEventLabel{
-					CreateLabelVals: cvals,
-					NegateLabelVals: nvals,
-					Comment:         &comment,
-					DurationInHours: durationInHours,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventLabel: &ozone.ModerationDefs_ModEventLabel{
+				CreateLabelVals: cvals,
+				NegateLabelVals: nvals,
+				Comment:         &comment,
+				DurationInHours: durationInHours,
 			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				RepoStrongRef: &atproto.RepoStrongRef{
-					Uri: uri,
-					Cid: cid,
-				},
-			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
 
-		if email != nil {
-			if err := oc.SendEmail(ctx, aturi.Authority().String(), *email); err != nil {
-				return err
-			}
+	if oc.live(aturi.Authority().String()) && email != nil {
+		if _, err := oc.SendEmail(ctx, aturi.Authority().String(), meta, *email); err != nil {
+			return err
 		}
 	}
 
@@ -401,54 +760,47 @@ func (oc *OzoneClient) LabelRecord(ctx context.Context, uri string, cid string,
 	return nil
 }
 
-func (oc *OzoneClient) TagActor(ctx context.Context, did string, meta ModToolMeta, tag string, comment *string, neg bool) error {
+// TagActor adds and removes any number of tags on the account in one tag event.
+func (oc *OzoneClient) TagActor(ctx context.Context, did string, meta ModToolMeta, add []string, remove []string, comment *string) error {
 	status := "error"
 	defer func() {
 		effectsProcessed.WithLabelValues("tag-actor", status).Inc()
 	}()
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
-
-		add := []string{}
-		remove := []string{}
-		if neg {
-			remove = append(remove, tag)
-		} else {
-			add = append(add, tag)
-		}
+	if add == nil {
+		add = []string{}
+	}
+	if remove == nil {
+		remove = []string{}
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventTag: &ozone.ModerationDefs_ModEventTag{
-					Add:     add,
-					Remove:  remove,
-					Comment: comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-					Did: did,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventTag: &ozone.ModerationDefs_ModEventTag{
+				Add:     add,
+				Remove:  remove,
+				Comment: comment,
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
 	}
 
 	status = "ok"
 	return nil
 }
 
-func (oc *OzoneClient) TagRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, tag string, comment *string, neg bool) error {
+// TagRecord adds and removes any number of tags on the record in one tag event.
+func (oc *OzoneClient) TagRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, add []string, remove []string, comment *string) error {
 	status := "error"
 	defer func() {
 		effectsProcessed.WithLabelValues("tag-record", status).Inc()
@@ -459,65 +811,260 @@ func (oc *OzoneClient) TagRecord(ctx context.Context, uri string, cid string, me
 		return fmt.Errorf("failed to parse aturi paseed to TagRecord: %w", err)
 	}
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
+	if add == nil {
+		add = []string{}
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventTag: &ozone.ModerationDefs_ModEventTag{
+				Add:     add,
+				Remove:  remove,
+				Comment: comment,
+			},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+func (oc *OzoneClient) MuteActor(ctx context.Context, did string, meta ModToolMeta, comment *string, durationInHours int64, unmute bool) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("mute-actor", status).Inc()
+	}()
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: muteEvent(comment, durationInHours, unmute),
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+func (oc *OzoneClient) MuteRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment *string, durationInHours int64, unmute bool) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("mute-record", status).Inc()
+	}()
+
+	_, err := syntax.ParseATURI(uri)
+	if err != nil {
+		return fmt.Errorf("failed to parse aturi paseed to MuteRecord: %w", err)
+	}
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: muteEvent(comment, durationInHours, unmute),
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+func muteEvent(comment *string, durationInHours int64, unmute bool) *ozone.ModerationEmitEvent_Input_Event {
+	if unmute {
+		return &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventUnmute: &ozone.ModerationDefs_ModEventUnmute{
+				Comment: comment,
+			},
 		}
+	}
+	return &ozone.ModerationEmitEvent_Input_Event{
+		ModerationDefs_ModEventMute: &ozone.ModerationDefs_ModEventMute{
+			Comment:         comment,
+			DurationInHours: durationInHours,
+		},
+	}
+}
+
+// errInvalidDivert is returned for divert effects that can never succeed, so they aren't retried.
+var errInvalidDivert = errors.New("invalid divert")
+
+// DivertRecord sends the record's blobs to abuse processing. Ozone only diverts blobs, so at least
+// one blob CID is required.
+func (oc *OzoneClient) DivertRecord(ctx context.Context, uri string, cid string, blobCids []string, meta ModToolMeta, comment *string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("divert-record", status).Inc()
+	}()
 
-		add := []string{}
-		remove := []string{}
-		if neg {
-			remove = append(remove, tag)
-		} else {
-			add = append(add, tag)
+	if _, err := syntax.ParseATURI(uri); err != nil {
+		status = "invalid"
+		return fmt.Errorf("%w: failed to parse aturi passed to DivertRecord: %w", errInvalidDivert, err)
+	}
+	if len(blobCids) == 0 {
+		status = "invalid"
+		return fmt.Errorf("%w: no blob cids to divert for %s", errInvalidDivert, uri)
+	}
+	for _, blobCid := range blobCids {
+		if _, err := syntax.ParseCID(blobCid); err != nil {
+			status = "invalid"
+			return fmt.Errorf("%w: invalid blob cid %q: %w", errInvalidDivert, blobCid, err)
 		}
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventTag: &ozone.ModerationDefs_ModEventTag{
-					Add:     add,
-					Remove:  remove,
-					Comment: comment,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventDivert: &ozone.ModerationDefs_ModEventDivert{
+				Comment: comment,
 			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				RepoStrongRef: &atproto.RepoStrongRef{
-					Uri: uri,
-					Cid: cid,
-				},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
+			},
+		},
+		SubjectBlobCids: blobCids,
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+func (oc *OzoneClient) CommentActor(ctx context.Context, did string, meta ModToolMeta, comment string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("comment-actor", status).Inc()
+	}()
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventComment: &ozone.ModerationDefs_ModEventComment{
+				Comment: &comment,
+			},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+func (oc *OzoneClient) CommentRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("comment-record", status).Inc()
+	}()
+
+	_, err := syntax.ParseATURI(uri)
+	if err != nil {
+		return fmt.Errorf("failed to parse aturi paseed to CommentRecord: %w", err)
+	}
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventComment: &ozone.ModerationDefs_ModEventComment{
+				Comment: &comment,
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
 	}
 
 	status = "ok"
 	return nil
 }
 
-func (oc *OzoneClient) CommentActor(ctx context.Context, did string, meta ModToolMeta, comment string) error {
+func (oc *OzoneClient) ReportActor(ctx context.Context, did string, meta ModToolMeta, reportType osprey.AtprotoReportKind, comment string, priorityScore *int64) error {
 	status := "error"
 	defer func() {
-		effectsProcessed.WithLabelValues("comment-actor", status).Inc()
+		effectsProcessed.WithLabelValues("report-actor", status).Inc()
 	}()
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
+	reportTypeStr := AtprotoReportKindToString(reportType)
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventReport: &ozone.ModerationDefs_ModEventReport{
+				ReportType: &reportTypeStr,
+				Comment:    &comment,
+			},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
+	if priorityScore != nil {
+		err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
 			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventComment: &ozone.ModerationDefs_ModEventComment{
+				ModerationDefs_ModEventPriorityScore: &ozone.ModerationDefs_ModEventPriorityScore{
 					Comment: &comment,
+					Score:   *priorityScore,
 				},
 			},
 			Subject: &ozone.ModerationEmitEvent_Input_Subject{
@@ -529,40 +1076,58 @@ func (oc *OzoneClient) CommentActor(ctx context.Context, did string, meta ModToo
 				Name: ClientName,
 				Meta: metaToInterface(meta),
 			},
-		}); err != nil {
+		})
+
+		if err != nil {
 			return err
 		}
 	}
 
 	status = "ok"
+
 	return nil
 }
 
-func (oc *OzoneClient) CommentRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment string) error {
+func (oc *OzoneClient) ReportRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, reportType osprey.AtprotoReportKind, comment string, priorityScore *int64) error {
 	status := "error"
 	defer func() {
-		effectsProcessed.WithLabelValues("comment-record", status).Inc()
+		effectsProcessed.WithLabelValues("report-record", status).Inc()
 	}()
 
-	aturi, err := syntax.ParseATURI(uri)
+	_, err := syntax.ParseATURI(uri)
 	if err != nil {
-		return fmt.Errorf("failed to parse aturi paseed to CommentRecord: %w", err)
+		return fmt.Errorf("failed to parse aturi paseed to ReportRecord: %w", err)
 	}
 
-	// TODO: remove this debug comment
-	isHailey := aturi.Authority().String() == "did:plc:oisofpd7lj26yvgiivf3lxsi"
+	reportTypeStr := AtprotoReportKindToString(reportType)
 
-	if oc.isProduction || isHailey {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventReport: &ozone.ModerationDefs_ModEventReport{
+				ReportType: &reportTypeStr,
+				Comment:    &comment,
+			},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
+	if priorityScore != nil {
+		err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
 			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventComment: &ozone.ModerationDefs_ModEventComment{
+				ModerationDefs_ModEventPriorityScore: &ozone.ModerationDefs_ModEventPriorityScore{
 					Comment: &comment,
+					Score:   *priorityScore,
 				},
 			},
 			Subject: &ozone.ModerationEmitEvent_Input_Subject{
@@ -575,7 +1140,9 @@ func (oc *OzoneClient) CommentRecord(ctx context.Context, uri string, cid string
 				Name: ClientName,
 				Meta: metaToInterface(meta),
 			},
-		}); err != nil {
+		})
+
+		if err != nil {
 			return err
 		}
 	}
@@ -584,218 +1151,197 @@ func (oc *OzoneClient) CommentRecord(ctx context.Context, uri string, cid string
 	return nil
 }
 
-func (oc *OzoneClient) ReportActor(ctx context.Context, did string, meta ModToolMeta, reportType osprey.AtprotoReportKind, comment string, priorityScore *int64) error {
+// escalationQueueTagPrefix prefixes the tag that routes an escalation to a reviewer queue, since
+// escalate events can't say which queue they're for and Ozone's queues are filtered by tag.
+const escalationQueueTagPrefix = "queue:"
+
+// EscalateActor escalates the account, first tagging it for the reviewer queue when one's given so
+// that it never shows up in the general escalation pool.
+func (oc *OzoneClient) EscalateActor(ctx context.Context, did string, meta ModToolMeta, comment *string, queue string) error {
 	status := "error"
 	defer func() {
-		effectsProcessed.WithLabelValues("report-actor", status).Inc()
+		effectsProcessed.WithLabelValues("escalate-actor", status).Inc()
 	}()
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
-
-		reportTypeStr := AtprotoReportKindToString(reportType)
-
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventReport: &ozone.ModerationDefs_ModEventReport{
-					ReportType: &reportTypeStr,
-					Comment:    &comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-					Did: did,
-				},
-			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
-			},
-		}); err != nil {
-			return err
-		}
-
-		if priorityScore != nil {
-			_, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-				CreatedBy: cli.Auth.Did,
-				Event: &ozone.ModerationEmitEvent_Input_Event{
-					ModerationDefs_ModEventPriorityScore: &ozone.ModerationDefs_ModEventPriorityScore{
-						Comment: &comment,
-						Score:   *priorityScore,
-					},
-				},
-				Subject: &ozone.ModerationEmitEvent_Input_Subject{
-					AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-						Did: did,
-					},
-				},
-				ModTool: &ozone.ModerationDefs_ModTool{
-					Name: ClientName,
-					Meta: metaToInterface(meta),
-				},
-			})
-
-			if err != nil {
-				return err
-			}
-		}
+	subject := &ozone.ModerationEmitEvent_Input_Subject{
+		AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+			Did: did,
+		},
+	}
+	if err := oc.escalate(ctx, subject, meta, comment, queue); err != nil {
+		return err
 	}
 
 	status = "ok"
-
 	return nil
 }
 
-func (oc *OzoneClient) ReportRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, reportType osprey.AtprotoReportKind, comment string, priorityScore *int64) error {
+// EscalateRecord escalates the record, first tagging it for the reviewer queue when one's given.
+func (oc *OzoneClient) EscalateRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment *string, queue string) error {
 	status := "error"
 	defer func() {
-		effectsProcessed.WithLabelValues("report-record", status).Inc()
+		effectsProcessed.WithLabelValues("escalate-record", status).Inc()
 	}()
 
 	_, err := syntax.ParseATURI(uri)
 	if err != nil {
-		return fmt.Errorf("failed to parse aturi paseed to ReportRecord: %w", err)
+		return fmt.Errorf("failed to parse aturi paseed to EscalateRecord: %w", err)
 	}
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
+	subject := &ozone.ModerationEmitEvent_Input_Subject{
+		RepoStrongRef: &atproto.RepoStrongRef{
+			Uri: uri,
+			Cid: cid,
+		},
+	}
+	if err := oc.escalate(ctx, subject, meta, comment, queue); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
 
-		reportTypeStr := AtprotoReportKindToString(reportType)
+// escalate sends the queue tag, if there's a queue, and then the escalation, so that the escalation
+// is the event recorded for the effect.
+func (oc *OzoneClient) escalate(ctx context.Context, subject *ozone.ModerationEmitEvent_Input_Subject, meta ModToolMeta, comment *string, queue string) error {
+	modTool := &ozone.ModerationDefs_ModTool{
+		Name: ClientName,
+		Meta: metaToInterface(meta),
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
+	if queue != "" {
+		if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
 			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventReport: &ozone.ModerationDefs_ModEventReport{
-					ReportType: &reportTypeStr,
-					Comment:    &comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				RepoStrongRef: &atproto.RepoStrongRef{
-					Uri: uri,
-					Cid: cid,
+				ModerationDefs_ModEventTag: &ozone.ModerationDefs_ModEventTag{
+					Add:     []string{escalationQueueTagPrefix + queue},
+					Remove:  []string{},
+					Comment: comment,
 				},
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
-			},
+			Subject: subject,
+			ModTool: modTool,
 		}); err != nil {
-			return err
+			return fmt.Errorf("failed to tag escalation for queue %q: %w", queue, err)
 		}
+	}
 
-		if priorityScore != nil {
-			_, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-				CreatedBy: cli.Auth.Did,
-				Event: &ozone.ModerationEmitEvent_Input_Event{
-					ModerationDefs_ModEventPriorityScore: &ozone.ModerationDefs_ModEventPriorityScore{
-						Comment: &comment,
-						Score:   *priorityScore,
-					},
-				},
-				Subject: &ozone.ModerationEmitEvent_Input_Subject{
-					RepoStrongRef: &atproto.RepoStrongRef{
-						Uri: uri,
-						Cid: cid,
-					},
-				},
-				ModTool: &ozone.ModerationDefs_ModTool{
-					Name: ClientName,
-					Meta: metaToInterface(meta),
-				},
-			})
+	return oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventEscalate: &ozone.ModerationDefs_ModEventEscalate{
+				Comment: comment,
+			},
+		},
+		Subject: subject,
+		ModTool: modTool,
+	})
+}
 
-			if err != nil {
-				return err
-			}
-		}
+func (oc *OzoneClient) ResolveAppealActor(ctx context.Context, did string, meta ModToolMeta, comment *string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("resolve-appeal-actor", status).Inc()
+	}()
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventResolveAppeal: &ozone.ModerationDefs_ModEventResolveAppeal{
+				Comment: comment,
+			},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
 	}
 
 	status = "ok"
 	return nil
 }
 
-func (oc *OzoneClient) EscalateActor(ctx context.Context, did string, meta ModToolMeta, comment *string) error {
+func (oc *OzoneClient) ResolveAppealRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment *string) error {
 	status := "error"
 	defer func() {
-		effectsProcessed.WithLabelValues("escalate-actor", status).Inc()
+		effectsProcessed.WithLabelValues("resolve-appeal-record", status).Inc()
 	}()
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
+	_, err := syntax.ParseATURI(uri)
+	if err != nil {
+		return fmt.Errorf("failed to parse aturi paseed to ResolveAppealRecord: %w", err)
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventEscalate: &ozone.ModerationDefs_ModEventEscalate{
-					Comment: comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-					Did: did,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventResolveAppeal: &ozone.ModerationDefs_ModEventResolveAppeal{
+				Comment: comment,
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
 	}
 
 	status = "ok"
 	return nil
 }
 
-func (oc *OzoneClient) EscalateRecord(ctx context.Context, uri string, cid string, meta ModToolMeta, comment *string) error {
+// UpdateSet adds the account to the named Ozone set, or removes it from the set. The set has to
+// exist in Ozone already. Set membership isn't a moderation event, so nothing shows up in the
+// subject's history, and outside of production the change is only logged.
+func (oc *OzoneClient) UpdateSet(ctx context.Context, did string, set string, remove bool) error {
+	kind := "set-add"
+	if remove {
+		kind = "set-remove"
+	}
 	status := "error"
 	defer func() {
-		effectsProcessed.WithLabelValues("escalate-", status).Inc()
+		effectsProcessed.WithLabelValues(kind, status).Inc()
 	}()
 
-	_, err := syntax.ParseATURI(uri)
+	if !oc.live(did) {
+		oc.logger.Info("dry run, not updating ozone set", "set", set, "did", did, "remove", remove)
+		markDryRun(ctx)
+		status = "ok"
+		return nil
+	}
+
+	cli, _, err := oc.lexClient(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to parse aturi paseed to EscalateRecord: %w", err)
+		return err
 	}
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
+	if oc.limiter != nil {
+		if err := oc.limiter.wait(ctx, kind); err != nil {
+			return fmt.Errorf("failed waiting for ozone rate limiter: %w", err)
 		}
+	}
 
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventEscalate: &ozone.ModerationDefs_ModEventEscalate{
-					Comment: comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				RepoStrongRef: &atproto.RepoStrongRef{
-					Uri: uri,
-					Cid: cid,
-				},
-			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
-			},
-		}); err != nil {
-			return err
+	if remove {
+		err = ozone.SetDeleteValues(ctx, cli, &ozone.SetDeleteValues_Input{Name: set, Values: []string{did}})
+	} else {
+		err = ozone.SetAddValues(ctx, cli, &ozone.SetAddValues_Input{Name: set, Values: []string{did}})
+	}
+	if err != nil {
+		if oc.limiter != nil {
+			oc.limiter.rateLimited(err)
 		}
+		return fmt.Errorf("failed to update ozone set %q: %w", set, err)
 	}
 
 	status = "ok"
@@ -808,31 +1354,23 @@ func (oc *OzoneClient) AcknowledgeActor(ctx context.Context, did string, meta Mo
 		effectsProcessed.WithLabelValues("acknowledge-actor", status).Inc()
 	}()
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
-
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventAcknowledge: &ozone.ModerationDefs_ModEventAcknowledge{
-					Comment: comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
-					Did: did,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventAcknowledge: &ozone.ModerationDefs_ModEventAcknowledge{
+				Comment: comment,
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
 	}
 
 	status = "ok"
@@ -850,46 +1388,106 @@ func (oc *OzoneClient) AcknowledgeRecord(ctx context.Context, uri string, cid st
 		return fmt.Errorf("failed to parse aturi paseed to AcknowledgeRecord: %w", err)
 	}
 
-	if oc.isProduction {
-		cli, err := oc.GetClient(ctx)
-		if err != nil {
-			return err
-		}
-
-		if _, err := ozone.ModerationEmitEvent(ctx, cli, &ozone.ModerationEmitEvent_Input{
-			CreatedBy: cli.Auth.Did,
-			Event: &ozone.ModerationEmitEvent_Input_Event{
-				ModerationDefs_ModEventAcknowledge: &ozone.ModerationDefs_ModEventAcknowledge{
-					Comment: comment,
-				},
-			},
-			Subject: &ozone.ModerationEmitEvent_Input_Subject{
-				RepoStrongRef: &atproto.RepoStrongRef{
-					Uri: uri,
-					Cid: cid,
-				},
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventAcknowledge: &ozone.ModerationDefs_ModEventAcknowledge{
+				Comment: comment,
 			},
-			ModTool: &ozone.ModerationDefs_ModTool{
-				Name: ClientName,
-				Meta: metaToInterface(meta),
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &atproto.RepoStrongRef{
+				Uri: uri,
+				Cid: cid,
 			},
-		}); err != nil {
-			return err
-		}
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return err
 	}
 
 	status = "ok"
 	return nil
 }
 
-func (oc *OzoneClient) ResolveHandle(ctx context.Context, did string) (string, error) {
-	// TODO: actually return a handle
-	return "", nil
+// SubjectStatus is what Ozone currently has in place for a subject.
+type SubjectStatus struct {
+	Takendown bool
+	// Labels are the labeler's unexpired labels on the subject.
+	Labels map[string]bool
+	// Tags are the moderation tags on the subject.
+	Tags map[string]bool
+	// Cid is the record's current CID, empty for accounts.
+	Cid string
 }
 
-// TODO: send an email
-func (oc *OzoneClient) SendEmail(ctx context.Context, did string, emailTemplate osprey.AtprotoEmail) error {
-	return nil
+// GetSubjectStatus looks up whether the account or record is taken down, and which of the
+// labeler's labels and moderation tags it carries.
+func (oc *OzoneClient) GetSubjectStatus(ctx context.Context, subject string) (*SubjectStatus, error) {
+	cli, _, err := oc.lexClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []*atproto.LabelDefs_Label
+	var moderation *ozone.ModerationDefs_ModerationDetail
+	var cid string
+	if strings.HasPrefix(subject, "did:") {
+		repo, err := ozone.ModerationGetRepo(ctx, cli, subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repo status: %w", err)
+		}
+		labels, moderation = repo.Labels, repo.Moderation
+	} else {
+		record, err := ozone.ModerationGetRecord(ctx, cli, "", subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get record status: %w", err)
+		}
+		labels, moderation, cid = record.Labels, record.Moderation, record.Cid
+	}
+
+	status := &SubjectStatus{Labels: map[string]bool{}, Tags: map[string]bool{}, Cid: cid}
+	if moderation != nil && moderation.SubjectStatus != nil {
+		if moderation.SubjectStatus.Takendown != nil {
+			status.Takendown = *moderation.SubjectStatus.Takendown
+		}
+		for _, t := range moderation.SubjectStatus.Tags {
+			status.Tags[t] = true
+		}
+	}
+	for _, l := range labels {
+		if l.Src != oc.labelerDid || l.Uri != subject || (l.Neg != nil && *l.Neg) {
+			continue
+		}
+		if l.Exp != nil {
+			if exp, err := time.Parse(time.RFC3339, *l.Exp); err == nil && exp.Before(time.Now()) {
+				continue
+			}
+		}
+		status.Labels[l.Val] = true
+	}
+	return status, nil
+}
+
+// ResolveHandle returns the verified handle for the DID, or an empty string if the handle doesn't
+// resolve back to the DID. Lookups are cached.
+func (oc *OzoneClient) ResolveHandle(ctx context.Context, did string) (string, error) {
+	atid, err := syntax.ParseDID(did)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse did passed to ResolveHandle: %w", err)
+	}
+
+	ident, err := oc.directory.LookupDID(ctx, atid)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up identity: %w", err)
+	}
+
+	if ident.Handle.IsInvalidHandle() {
+		return "", nil
+	}
+	return ident.Handle.String(), nil
 }
 
 func AtprotoReportKindToString(kind osprey.AtprotoReportKind) string {
@@ -934,6 +1532,18 @@ func AtprotoLabelToString(label osprey.AtprotoLabel) string {
 	}
 }
 
+// AtprotoLabelFromString is the reverse of AtprotoLabelToString, reporting false for labels the
+// effector doesn't apply.
+func AtprotoLabelFromString(s string) (osprey.AtprotoLabel, bool) {
+	for _, v := range osprey.AtprotoLabel_value {
+		label := osprey.AtprotoLabel(v)
+		if label != osprey.AtprotoLabel_ATPROTO_LABEL_NONE && AtprotoLabelToString(label) == s {
+			return label, true
+		}
+	}
+	return osprey.AtprotoLabel_ATPROTO_LABEL_NONE, false
+}
+
 type ListTemplatesResponse struct {
 	CommunicationTemplates []CommunicationTemplate `json:"communicationTemplates"`
 }
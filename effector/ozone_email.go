@@ -0,0 +1,210 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/api/ozone"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/lex/util"
+	"github.com/bluesky-social/indigo/xrpc"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+const (
+	// emailFallbackLang is the language of the templates the email enum refers to, and what's sent
+	// when there's no template in the recipient's language.
+	emailFallbackLang = "en"
+
+	// templatesTTL is how long communication templates are cached before they're listed again.
+	templatesTTL = 10 * time.Minute
+
+	// emailLangPosts is how many of the recipient's latest posts their language is guessed from.
+	emailLangPosts = 10
+)
+
+// SendEmail sends the communication template to the account in their language, if Ozone has a
+// template of the same name in it, and otherwise in English. It returns the language that was sent.
+func (oc *OzoneClient) SendEmail(ctx context.Context, did string, meta ModToolMeta, emailTemplate osprey.AtprotoEmail) (string, error) {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("email-actor", status).Inc()
+	}()
+
+	templates, err := oc.communicationTemplates(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	lang, err := oc.recipientLang(ctx, did)
+	if err != nil {
+		// Not knowing their language shouldn't keep them from getting the email at all.
+		oc.logger.Warn("failed to look up email recipient language, falling back", "did", did, "error", err)
+	}
+
+	tmpl, err := selectTemplate(templates, emailTemplate, lang)
+	if err != nil {
+		return "", err
+	}
+
+	content := tmpl.ContentMarkdown
+	if handle, err := oc.ResolveHandle(ctx, did); err == nil && handle != "" {
+		content = strings.ReplaceAll(content, "{{handle}}", handle)
+	}
+	comment := fmt.Sprintf("Sent %s email template %q", templateLang(tmpl), tmpl.Name)
+
+	if err := oc.emit(ctx, &ozone.ModerationEmitEvent_Input{
+		Event: &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventEmail: &ozone.ModerationDefs_ModEventEmail{
+				SubjectLine: tmpl.Subject,
+				Content:     &content,
+				Comment:     &comment,
+			},
+		},
+		Subject: &ozone.ModerationEmitEvent_Input_Subject{
+			AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+				Did: did,
+			},
+		},
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	}); err != nil {
+		return "", err
+	}
+
+	status = "ok"
+	return templateLang(tmpl), nil
+}
+
+// communicationTemplates returns Ozone's communication templates, listing them again once the
+// cached ones are older than templatesTTL.
+func (oc *OzoneClient) communicationTemplates(ctx context.Context) ([]CommunicationTemplate, error) {
+	oc.templatesMu.Lock()
+	defer oc.templatesMu.Unlock()
+
+	if oc.templates != nil && time.Since(oc.templatesFetchedAt) < templatesTTL {
+		return oc.templates, nil
+	}
+
+	cli, _, err := oc.lexClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ListTemplatesResponse
+	if err := cli.LexDo(ctx, util.Query, "", "tools.ozone.communication.listTemplates", nil, nil, &resp); err != nil {
+		if oc.templates != nil {
+			oc.logger.Warn("failed to list communication templates, using cached ones", "error", err)
+			return oc.templates, nil
+		}
+		return nil, fmt.Errorf("failed to list communication templates: %w", err)
+	}
+
+	oc.templates = resp.CommunicationTemplates
+	oc.templatesFetchedAt = time.Now()
+	return oc.templates, nil
+}
+
+// recipientLang guesses the account's primary language from the langs of their latest posts, as the
+// most common one among them. It returns an empty string if none of the posts have langs.
+func (oc *OzoneClient) recipientLang(ctx context.Context, did string) (string, error) {
+	atid, err := syntax.ParseDID(did)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse did passed to recipientLang: %w", err)
+	}
+
+	ident, err := oc.directory.LookupDID(ctx, atid)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up identity: %w", err)
+	}
+	pds := ident.PDSEndpoint()
+	if pds == "" {
+		return "", fmt.Errorf("identity has no pds")
+	}
+
+	out, err := atproto.RepoListRecords(ctx, &xrpc.Client{Host: pds}, "app.bsky.feed.post", "", emailLangPosts, did, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	counts := map[string]int{}
+	var lang string
+	for _, rec := range out.Records {
+		if rec.Value == nil {
+			continue
+		}
+		post, ok := rec.Value.Val.(*bsky.FeedPost)
+		if !ok {
+			continue
+		}
+		for _, l := range post.Langs {
+			l = primaryLang(l)
+			if l == "" {
+				continue
+			}
+			counts[l]++
+			// Ties go to the language of the more recent post.
+			if counts[l] > counts[lang] {
+				lang = l
+			}
+		}
+	}
+	return lang, nil
+}
+
+// selectTemplate picks the enabled template with the same name as the one the email refers to, in
+// the given language if there's one, otherwise in English, otherwise the referred to one itself.
+func selectTemplate(templates []CommunicationTemplate, emailTemplate osprey.AtprotoEmail, lang string) (*CommunicationTemplate, error) {
+	id := strconv.Itoa(int(emailTemplate))
+
+	var base *CommunicationTemplate
+	for i := range templates {
+		if templates[i].Id == id {
+			base = &templates[i]
+			break
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("no communication template for email %s (id %s)", emailTemplate, id)
+	}
+
+	var fallback *CommunicationTemplate
+	for i := range templates {
+		t := &templates[i]
+		if t.Disabled || t.Name != base.Name {
+			continue
+		}
+		tl := templateLang(t)
+		if lang != "" && tl == lang {
+			return t, nil
+		}
+		if tl == emailFallbackLang && fallback == nil {
+			fallback = t
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	return base, nil
+}
+
+// templateLang returns the template's primary language, taking templates without one as English.
+func templateLang(t *CommunicationTemplate) string {
+	if l := primaryLang(t.Lang); l != "" {
+		return l
+	}
+	return emailFallbackLang
+}
+
+// primaryLang returns the primary subtag of the BCP 47 language tag, lowercased, so "pt-BR" is "pt".
+func primaryLang(tag string) string {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	return strings.ToLower(primary)
+}
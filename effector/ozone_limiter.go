@@ -0,0 +1,213 @@
+package effector
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/xrpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ozoneLimiterWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "ozone_limiter_wait_seconds",
+		Namespace: NAMESPACE,
+		Help:      "time events waited for the Ozone rate limiter, by kind",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"kind"})
+
+	ozoneLimiterQueued = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "ozone_limiter_queued",
+		Namespace: NAMESPACE,
+		Help:      "number of events waiting for the Ozone rate limiter",
+	})
+
+	ozoneRateLimited = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "ozone_rate_limited",
+		Namespace: NAMESPACE,
+		Help:      "number of events Ozone rejected for exceeding its rate limit",
+	})
+)
+
+// ozoneRateLimitBackoff is how long sending is paused after a 429 that didn't say when to retry.
+const ozoneRateLimitBackoff = 10 * time.Second
+
+// ozoneEventPriority orders the events waiting on the limiter, so that takedowns aren't starved by
+// a burst of labels, and labels aren't starved by a burst of comments.
+func ozoneEventPriority(kind string) int {
+	switch kind {
+	case "takedown", "reverse-takedown":
+		return 3
+	case "label", "mute", "unmute", "divert":
+		return 2
+	case "comment":
+		return 0
+	}
+	return 1
+}
+
+// ozoneLimiter paces the events sent to Ozone, so that bursty rules can't exhaust the labeler's
+// rate limits. Whenever a send is allowed, it goes to the highest priority event waiting, in the
+// order they started waiting within a priority.
+type ozoneLimiter struct {
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	waiting     ozoneWaiters
+	seq         uint64
+	pausedUntil time.Time
+
+	wake chan struct{}
+}
+
+type ozoneWaiter struct {
+	priority int
+	seq      uint64
+	index    int
+	granted  bool
+	ready    chan struct{}
+}
+
+func newOzoneLimiter(limit rate.Limit, burst int) *ozoneLimiter {
+	l := &ozoneLimiter{
+		limiter: rate.NewLimiter(limit, max(burst, 1)),
+		wake:    make(chan struct{}, 1),
+	}
+	go l.run()
+	return l
+}
+
+// wait blocks until the event may be sent, or the context is done.
+func (l *ozoneLimiter) wait(ctx context.Context, kind string) error {
+	start := time.Now()
+	w := &ozoneWaiter{priority: ozoneEventPriority(kind), ready: make(chan struct{})}
+
+	l.mu.Lock()
+	l.seq++
+	w.seq = l.seq
+	heap.Push(&l.waiting, w)
+	ozoneLimiterQueued.Set(float64(len(l.waiting)))
+	l.mu.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-w.ready:
+		ozoneLimiterWait.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if !w.granted {
+			heap.Remove(&l.waiting, w.index)
+			ozoneLimiterQueued.Set(float64(len(l.waiting)))
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// rateLimited pauses sending when Ozone says it's over its limit, until its limit resets.
+func (l *ozoneLimiter) rateLimited(err error) {
+	until, ok := ozoneRateLimitReset(err)
+	if !ok {
+		return
+	}
+	ozoneRateLimited.Inc()
+
+	l.mu.Lock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.mu.Unlock()
+}
+
+// ozoneRateLimitReset reports whether the error is Ozone saying it's over its limit, and when the
+// limit resets. Password sessions return xrpc errors, which say when, and OAuth sessions return
+// atclient errors, which don't, so those wait out ozoneRateLimitBackoff.
+func ozoneRateLimitReset(err error) (time.Time, bool) {
+	var xerr *xrpc.Error
+	if errors.As(err, &xerr) && xerr.StatusCode == 429 {
+		if xerr.Ratelimit != nil && xerr.Ratelimit.Reset.After(time.Now()) {
+			return xerr.Ratelimit.Reset, true
+		}
+		return time.Now().Add(ozoneRateLimitBackoff), true
+	}
+	var apiErr *atclient.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 429 {
+		return time.Now().Add(ozoneRateLimitBackoff), true
+	}
+	return time.Time{}, false
+}
+
+func (l *ozoneLimiter) run() {
+	for {
+		l.mu.Lock()
+		empty := len(l.waiting) == 0
+		pause := time.Until(l.pausedUntil)
+		l.mu.Unlock()
+
+		if empty {
+			<-l.wake
+			continue
+		}
+		if pause > 0 {
+			time.Sleep(pause)
+			continue
+		}
+
+		l.limiter.Wait(context.Background())
+
+		// Whoever is first in line now gets the send, which may not be who was when we started
+		// waiting. If everyone gave up in the meantime, the token is spent anyway.
+		l.mu.Lock()
+		if len(l.waiting) > 0 {
+			w := heap.Pop(&l.waiting).(*ozoneWaiter)
+			w.granted = true
+			close(w.ready)
+			ozoneLimiterQueued.Set(float64(len(l.waiting)))
+		}
+		l.mu.Unlock()
+	}
+}
+
+// ozoneWaiters is a heap of waiters, highest priority first.
+type ozoneWaiters []*ozoneWaiter
+
+func (h ozoneWaiters) Len() int { return len(h) }
+
+func (h ozoneWaiters) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h ozoneWaiters) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *ozoneWaiters) Push(x any) {
+	w := x.(*ozoneWaiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *ozoneWaiters) Pop() any {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return w
+}
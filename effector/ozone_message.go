@@ -0,0 +1,180 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/chat"
+	"github.com/bluesky-social/indigo/api/ozone"
+	"github.com/bluesky-social/indigo/lex/util"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// Events about chat messages are built on the sender's account, which decides whether they're live
+// and is the subject their logs and outcomes show, and the message is swapped in as the subject
+// when they're sent. indigo's emitEvent subject union can't hold a message reference, though Ozone
+// takes one.
+
+type messageSubjectKey struct{}
+
+// messageEmitEventInput is an emitEvent input with a message reference as its subject, which
+// shadows the embedded input's subject when marshalled.
+type messageEmitEventInput struct {
+	*ozone.ModerationEmitEvent_Input
+	Subject *chat.ConvoDefs_MessageRef `json:"subject"`
+}
+
+func emitMessageEvent(ctx context.Context, cli util.LexClient, input *ozone.ModerationEmitEvent_Input, ref *osprey.ChatMessageRef) (*ozone.ModerationDefs_ModEventView, error) {
+	body := &messageEmitEventInput{
+		ModerationEmitEvent_Input: input,
+		Subject: &chat.ConvoDefs_MessageRef{
+			LexiconTypeID: "chat.bsky.convo.defs#messageRef",
+			Did:           ref.Did,
+			ConvoId:       ref.ConvoId,
+			MessageId:     ref.MessageId,
+		},
+	}
+
+	var out ozone.ModerationDefs_ModEventView
+	if err := cli.LexDo(ctx, util.Procedure, "application/json", "tools.ozone.moderation.emitEvent", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MessageSubject is how a chat message is identified in effect logs and action keys, since it has
+// no AT-URI.
+func MessageSubject(ref *osprey.ChatMessageRef) string {
+	return fmt.Sprintf("chat://%s/%s/%s", ref.Did, ref.ConvoId, ref.MessageId)
+}
+
+// emitOnMessage sends the event with the message as its subject.
+func (oc *OzoneClient) emitOnMessage(ctx context.Context, ref *osprey.ChatMessageRef, meta ModToolMeta, event *ozone.ModerationEmitEvent_Input_Event) error {
+	return oc.emit(context.WithValue(ctx, messageSubjectKey{}, ref), &ozone.ModerationEmitEvent_Input{
+		Event:   event,
+		Subject: messageSenderSubject(ref),
+		ModTool: &ozone.ModerationDefs_ModTool{
+			Name: ClientName,
+			Meta: metaToInterface(meta),
+		},
+	})
+}
+
+func messageSenderSubject(ref *osprey.ChatMessageRef) *ozone.ModerationEmitEvent_Input_Subject {
+	return &ozone.ModerationEmitEvent_Input_Subject{
+		AdminDefs_RepoRef: &atproto.AdminDefs_RepoRef{
+			Did: ref.Did,
+		},
+	}
+}
+
+func (oc *OzoneClient) ReportMessage(ctx context.Context, ref *osprey.ChatMessageRef, meta ModToolMeta, reportType osprey.AtprotoReportKind, comment string, priorityScore *int64) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("report-message", status).Inc()
+	}()
+
+	reportTypeStr := AtprotoReportKindToString(reportType)
+	if err := oc.emitOnMessage(ctx, ref, meta, &ozone.ModerationEmitEvent_Input_Event{
+		ModerationDefs_ModEventReport: &ozone.ModerationDefs_ModEventReport{
+			ReportType: &reportTypeStr,
+			Comment:    &comment,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if priorityScore != nil {
+		if err := oc.emitOnMessage(ctx, ref, meta, &ozone.ModerationEmitEvent_Input_Event{
+			ModerationDefs_ModEventPriorityScore: &ozone.ModerationDefs_ModEventPriorityScore{
+				Comment: &comment,
+				Score:   *priorityScore,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	status = "ok"
+	return nil
+}
+
+func (oc *OzoneClient) CommentMessage(ctx context.Context, ref *osprey.ChatMessageRef, meta ModToolMeta, comment string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("comment-message", status).Inc()
+	}()
+
+	if err := oc.emitOnMessage(ctx, ref, meta, &ozone.ModerationEmitEvent_Input_Event{
+		ModerationDefs_ModEventComment: &ozone.ModerationDefs_ModEventComment{
+			Comment: &comment,
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+// TagMessage adds and removes any number of tags on the message in one tag event.
+func (oc *OzoneClient) TagMessage(ctx context.Context, ref *osprey.ChatMessageRef, meta ModToolMeta, add []string, remove []string, comment *string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("tag-message", status).Inc()
+	}()
+
+	if add == nil {
+		add = []string{}
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+
+	if err := oc.emitOnMessage(ctx, ref, meta, &ozone.ModerationEmitEvent_Input_Event{
+		ModerationDefs_ModEventTag: &ozone.ModerationDefs_ModEventTag{
+			Add:     add,
+			Remove:  remove,
+			Comment: comment,
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+// EscalateMessage escalates the message, first tagging it for the reviewer queue when one's given.
+func (oc *OzoneClient) EscalateMessage(ctx context.Context, ref *osprey.ChatMessageRef, meta ModToolMeta, comment *string, queue string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("escalate-message", status).Inc()
+	}()
+
+	if err := oc.escalate(context.WithValue(ctx, messageSubjectKey{}, ref), messageSenderSubject(ref), meta, comment, queue); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
+
+func (oc *OzoneClient) AcknowledgeMessage(ctx context.Context, ref *osprey.ChatMessageRef, meta ModToolMeta, comment *string) error {
+	status := "error"
+	defer func() {
+		effectsProcessed.WithLabelValues("acknowledge-message", status).Inc()
+	}()
+
+	if err := oc.emitOnMessage(ctx, ref, meta, &ozone.ModerationEmitEvent_Input_Event{
+		ModerationDefs_ModEventAcknowledge: &ozone.ModerationDefs_ModEventAcknowledge{
+			Comment: comment,
+		},
+	}); err != nil {
+		return err
+	}
+
+	status = "ok"
+	return nil
+}
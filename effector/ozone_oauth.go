@@ -0,0 +1,193 @@
+package effector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/atcrypto"
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// Ways of authenticating to the labeler's PDS.
+const (
+	OzoneAuthPassword = "password"
+	OzoneAuthOAuth    = "oauth"
+)
+
+// OzoneOAuthArgs configure authenticating to the labeler's PDS with OAuth instead of an app
+// password. atproto OAuth has no client credentials grant, so the service account authorizes the
+// effector once with OzoneOAuthLogin, and the session is resumed and refreshed from the session
+// store after that. Requests are bound to the session's key with DPoP.
+type OzoneOAuthArgs struct {
+	// ClientID is the URL of the client metadata document. A localhost development client is used
+	// when empty, whose sessions don't last long.
+	ClientID    string
+	CallbackURL string
+	Scopes      []string
+	// ClientSecretKey is a multibase private key, which makes the effector a confidential client
+	// whose sessions last longer. Its public key has to be in the client metadata under ClientKeyID.
+	ClientSecretKey string
+	ClientKeyID     string
+}
+
+func newOAuthApp(args *OzoneOAuthArgs, sessions SessionStore) (*oauth.ClientApp, error) {
+	var config oauth.ClientConfig
+	if args.ClientID == "" {
+		config = oauth.NewLocalhostConfig(args.CallbackURL, args.Scopes)
+	} else {
+		config = oauth.NewPublicConfig(args.ClientID, args.CallbackURL, args.Scopes)
+	}
+	config.UserAgent = ClientName
+
+	if args.ClientSecretKey != "" {
+		priv, err := atcrypto.ParsePrivateMultibase(args.ClientSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse oauth client secret key: %w", err)
+		}
+		if err := config.SetClientSecret(priv, args.ClientKeyID); err != nil {
+			return nil, fmt.Errorf("failed to set oauth client secret: %w", err)
+		}
+	}
+
+	return oauth.NewClientApp(&config, &oauthStore{MemStore: oauth.NewMemStore(), sessions: sessions}), nil
+}
+
+// oauthStore keeps OAuth sessions in the session store, one per account, so that they're resumed
+// across restarts. Auth requests only live as long as OzoneOAuthLogin, so they're kept in memory.
+type oauthStore struct {
+	*oauth.MemStore
+	sessions SessionStore
+}
+
+func oauthSessionIdentifier(did syntax.DID) string {
+	return "oauth:" + did.String()
+}
+
+func (s *oauthStore) GetSession(ctx context.Context, did syntax.DID, sessionID string) (*oauth.ClientSessionData, error) {
+	stored, err := s.sessions.Load(ctx, oauthSessionIdentifier(did))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.OAuth == nil {
+		return nil, fmt.Errorf("no oauth session stored for %s", did)
+	}
+	return stored.OAuth, nil
+}
+
+func (s *oauthStore) SaveSession(ctx context.Context, sess oauth.ClientSessionData) error {
+	return s.sessions.Save(ctx, &StoredSession{
+		Host:       sess.HostURL,
+		Identifier: oauthSessionIdentifier(sess.AccountDID),
+		Did:        sess.AccountDID.String(),
+		OAuth:      &sess,
+	})
+}
+
+func (s *oauthStore) DeleteSession(ctx context.Context, did syntax.DID, sessionID string) error {
+	return s.sessions.Save(ctx, &StoredSession{Identifier: oauthSessionIdentifier(did)})
+}
+
+// startOAuthSession resumes the session stored by OzoneOAuthLogin for the identifier. The session
+// refreshes its own tokens, and saves them to the session store as they rotate.
+func (oc *OzoneClient) startOAuthSession(ctx context.Context, app *oauth.ClientApp, proxyDid string) error {
+	atid, err := syntax.ParseAtIdentifier(oc.identifier)
+	if err != nil {
+		return fmt.Errorf("failed to parse ozone identifier: %w", err)
+	}
+	ident, err := oc.directory.Lookup(ctx, *atid)
+	if err != nil {
+		return fmt.Errorf("failed to look up ozone identity: %w", err)
+	}
+
+	sess, err := app.ResumeSession(ctx, ident.DID, "")
+	if err != nil {
+		return fmt.Errorf("could not resume oauth session, authorize the effector with the ozone-oauth-login command first: %w", err)
+	}
+
+	client := sess.APIClient()
+	client.Headers.Set("atproto-proxy", fmt.Sprintf("%s#atproto_labeler", proxyDid))
+	oc.oauthClient = client
+	oc.logger.Info("resumed ozone oauth session", "did", ident.DID)
+	return nil
+}
+
+// OzoneOAuthLoginArgs configure authorizing the effector's OAuth client as the service account.
+type OzoneOAuthLoginArgs struct {
+	OAuth        *OzoneOAuthArgs
+	SessionStore SessionStore
+	// Identifier is the handle or DID of the service account.
+	Identifier string
+
+	Logger *slog.Logger
+}
+
+// OzoneOAuthLogin authorizes the effector as the service account. It prints the URL to approve the
+// authorization at, and serves the callback on the callback URL's address until it's approved,
+// storing the resulting session for the effector to resume.
+func OzoneOAuthLogin(ctx context.Context, args *OzoneOAuthLoginArgs) error {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	logger := args.Logger.With("component", "ozone_oauth_login")
+
+	app, err := newOAuthApp(args.OAuth, args.SessionStore)
+	if err != nil {
+		return err
+	}
+
+	callback, err := url.Parse(args.OAuth.CallbackURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse oauth callback url: %w", err)
+	}
+
+	redirectURL, err := app.StartAuthFlow(ctx, args.Identifier)
+	if err != nil {
+		return fmt.Errorf("failed to start oauth flow: %w", err)
+	}
+
+	done := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callback.Path, func(w http.ResponseWriter, r *http.Request) {
+		sess, err := app.ProcessCallback(r.Context(), r.URL.Query())
+		if err != nil {
+			http.Error(w, "authorization failed: "+err.Error(), http.StatusBadRequest)
+			done <- fmt.Errorf("failed to process oauth callback: %w", err)
+			return
+		}
+		fmt.Fprintf(w, "Authorized %s, you can close this window.\n", sess.AccountDID)
+		logger.Info("stored ozone oauth session", "did", sess.AccountDID, "scopes", sess.Scopes)
+		done <- nil
+	})
+
+	ln, err := net.Listen("tcp", callback.Host)
+	if err != nil {
+		return fmt.Errorf("failed to listen for oauth callback: %w", err)
+	}
+	httpd := &http.Server{Handler: mux}
+	go func() {
+		if err := httpd.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			done <- fmt.Errorf("failed to serve oauth callback: %w", err)
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpd.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Approve the effector's access as %s at:\n\n%s\n\n", args.Identifier, redirectURL)
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
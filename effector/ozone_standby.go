@@ -0,0 +1,136 @@
+package effector
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bluesky-social/indigo/xrpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+// ozoneFailoverDuration is how long events go to the standby account after the primary account's
+// session couldn't be established, before the primary is tried again. A rate limited primary is
+// tried again once its limit resets, but no sooner than ozoneFailoverMinDuration.
+const (
+	ozoneFailoverDuration    = 5 * time.Minute
+	ozoneFailoverMinDuration = time.Minute
+)
+
+// errOzoneSession marks errors starting or refreshing the session, as opposed to Ozone rejecting
+// the request.
+var errOzoneSession = errors.New("no ozone session")
+
+var (
+	ozoneFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "ozone_failovers",
+		Namespace: NAMESPACE,
+		Help:      "number of times events failed over to the standby Ozone account, by reason",
+	}, []string{"reason"})
+
+	ozoneFailedOver = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "ozone_failed_over",
+		Namespace: NAMESPACE,
+		Help:      "1 while events are being sent as the standby Ozone account",
+	})
+)
+
+// OzoneStandbyArgs configure a second account on the labeler, which events are sent as when the
+// primary account is rate limited or its session can't be established, so that one account being
+// locked out doesn't halt moderation.
+type OzoneStandbyArgs struct {
+	// PdsHost defaults to the primary account's.
+	PdsHost    string
+	Identifier string
+	Password   string
+}
+
+// newStandbyClient creates the client for the standby account. It doesn't log in until it's first
+// failed over to, nor persist its session, so that deploys don't spend its logins.
+func newStandbyClient(primary *OzoneClient, args *OzoneClientArgs) *OzoneClient {
+	standby := &OzoneClient{
+		logger:       primary.logger.With("account", "standby"),
+		directory:    primary.directory,
+		labelerDid:   primary.labelerDid,
+		isProduction: primary.isProduction,
+		testSubjects: primary.testSubjects,
+		identifier:   args.Standby.Identifier,
+		password:     args.Standby.Password,
+	}
+	if args.RateLimit > 0 {
+		standby.limiter = newOzoneLimiter(rate.Limit(args.RateLimit), args.RateBurst)
+	}
+	standby.client.Store(&xrpc.Client{
+		Host: cmp.Or(args.Standby.PdsHost, args.PdsHost),
+		Headers: map[string]string{
+			"atproto-proxy": fmt.Sprintf("%s#atproto_labeler", args.ProxyDid),
+		},
+		Auth: &xrpc.AuthInfo{},
+	})
+	return standby
+}
+
+// failedOver reports whether events are going to the standby account.
+func (oc *OzoneClient) failedOver() bool {
+	return oc.standby != nil && time.Now().UnixNano() < oc.failoverUntil.Load()
+}
+
+// shouldFailOver reports whether the primary account's error is one the standby account might not
+// have, and when to try the primary again if so.
+func shouldFailOver(err error) (time.Time, string, bool) {
+	if errors.Is(err, errOzoneSession) {
+		return time.Now().Add(ozoneFailoverDuration), "session", true
+	}
+	if reset, ok := ozoneRateLimitReset(err); ok {
+		if earliest := time.Now().Add(ozoneFailoverMinDuration); reset.Before(earliest) {
+			reset = earliest
+		}
+		return reset, "rate_limited", true
+	}
+	return time.Time{}, "", false
+}
+
+// failOver sends events as the standby account until the given time, alerting when the failover
+// starts rather than each time it's extended.
+func (oc *OzoneClient) failOver(ctx context.Context, err error, until time.Time, reason string) {
+	oc.failoverUntil.Store(until.UnixNano())
+	if !oc.failoverActive.CompareAndSwap(false, true) {
+		return
+	}
+
+	ozoneFailovers.WithLabelValues(reason).Inc()
+	ozoneFailedOver.Set(1)
+	oc.logger.Error("failing over to the standby ozone account", "reason", reason, "error", err, "until", until)
+	if oc.failover != nil {
+		oc.failover(ctx, fmt.Sprintf(`Failed over to the standby Ozone account %s
+Reason: %s
+Error: %s
+Retrying %s at: %s`, oc.standby.identifier, reason, err, oc.identifier, until.UTC().Format(time.RFC3339)))
+	}
+}
+
+// failBack goes back to the primary account once it works again.
+func (oc *OzoneClient) failBack(ctx context.Context) {
+	if !oc.failoverActive.CompareAndSwap(true, false) {
+		return
+	}
+
+	ozoneFailedOver.Set(0)
+	oc.logger.Info("failed back to the primary ozone account")
+	if oc.failover != nil {
+		oc.failover(ctx, fmt.Sprintf("Failed back to the primary Ozone account %s", oc.identifier))
+	}
+}
+
+// alertOzoneFailover posts failovers between Ozone accounts to the alerters.
+func (or *OspreyEffector) alertOzoneFailover(ctx context.Context, msg string) {
+	for _, a := range or.alerters {
+		if err := a.Alert(ctx, msg); err != nil {
+			or.logger.Error("failed to send ozone failover alert", "error", err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresLogger writes event and effect logs to Postgres, with the same fields as the BigQuery
+// logger, for deployments without BigQuery.
+type PostgresLogger struct {
+	pool   *pgxpool.Pool
+	logger *slog.Logger
+}
+
+// postgresLogMigrations are applied in order, each once. Only ever append to this.
+var postgresLogMigrations = []string{
+	`
+CREATE TABLE osprey_events (
+	id BIGSERIAL PRIMARY KEY,
+	action_name TEXT NOT NULL,
+	action_id BIGINT NOT NULL,
+	did TEXT NOT NULL,
+	uri TEXT NOT NULL,
+	cid TEXT NOT NULL,
+	raw TEXT NOT NULL,
+	send_time TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX osprey_events_action_id ON osprey_events (action_id);
+CREATE INDEX osprey_events_did ON osprey_events (did);
+CREATE INDEX osprey_events_created_at ON osprey_events (created_at);
+
+CREATE TABLE osprey_effects (
+	id BIGSERIAL PRIMARY KEY,
+	action_name TEXT NOT NULL,
+	action_id BIGINT NOT NULL,
+	subject TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	rules TEXT NOT NULL,
+	comment TEXT NOT NULL,
+	label TEXT,
+	tag TEXT,
+	email TEXT,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX osprey_effects_action_id ON osprey_effects (action_id);
+CREATE INDEX osprey_effects_subject ON osprey_effects (subject);
+CREATE INDEX osprey_effects_created_at ON osprey_effects (created_at);
+`,
+	`
+ALTER TABLE osprey_effects ADD COLUMN shadow BOOLEAN NOT NULL DEFAULT false;
+`,
+	`
+ALTER TABLE osprey_effects ADD COLUMN ozone_event_id BIGINT;
+ALTER TABLE osprey_effects ADD COLUMN error TEXT;
+`,
+	`
+ALTER TABLE osprey_effects ADD COLUMN email_lang TEXT;
+`,
+	`
+ALTER TABLE osprey_effects ADD COLUMN ozone_set TEXT;
+`,
+}
+
+func NewPostgresLogger(ctx context.Context, url string, logger *slog.Logger) (*PostgresLogger, error) {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	l := &PostgresLogger{
+		pool:   pool,
+		logger: logger.With("component", "postgres_logger"),
+	}
+	if err := l.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// migrate applies the migrations that haven't been yet, tracking the version in its own table. The
+// migrations run in a transaction that holds a lock on that table, so concurrently starting
+// effectors don't apply them twice.
+func (l *PostgresLogger) migrate(ctx context.Context) error {
+	if _, err := l.pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS osprey_log_migrations (version INT NOT NULL)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	return pgx.BeginFunc(ctx, l.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `LOCK TABLE osprey_log_migrations IN EXCLUSIVE MODE`); err != nil {
+			return fmt.Errorf("failed to lock migrations table: %w", err)
+		}
+
+		var version int
+		if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM osprey_log_migrations`).Scan(&version); err != nil {
+			return fmt.Errorf("failed to get migration version: %w", err)
+		}
+
+		for i := version; i < len(postgresLogMigrations); i++ {
+			if _, err := tx.Exec(ctx, postgresLogMigrations[i]); err != nil {
+				return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+			}
+			if _, err := tx.Exec(ctx, `INSERT INTO osprey_log_migrations (version) VALUES ($1)`, i+1); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+			}
+			l.logger.Info("applied migration", "version", i+1)
+		}
+		return nil
+	})
+}
+
+func (l *PostgresLogger) Name() string {
+	return "postgres"
+}
+
+func (l *PostgresLogger) LogEvent(ctx context.Context, log *OspreyEventLog) error {
+	if _, err := l.pool.Exec(ctx, `
+		INSERT INTO osprey_events (action_name, action_id, did, uri, cid, raw, send_time, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		log.ActionName, log.ActionID, log.Did, log.Uri, log.Cid, log.Raw, log.SendTime, log.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+	return nil
+}
+
+func (l *PostgresLogger) LogEffect(ctx context.Context, log *OspreyEffectLog) error {
+	if _, err := l.pool.Exec(ctx, `
+		INSERT INTO osprey_effects (action_name, action_id, subject, kind, rules, comment, label, tag, email, created_at, shadow, ozone_event_id, error, email_lang, ozone_set)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		log.ActionName, log.ActionID, log.Subject, log.Kind, log.Rules, log.Comment,
+		nullString(log.Label.Valid, log.Label.StringVal),
+		nullString(log.Tag.Valid, log.Tag.StringVal),
+		nullString(log.Email.Valid, log.Email.StringVal),
+		log.CreatedAt, log.Shadow,
+		nullInt64(log.OzoneEventID.Valid, log.OzoneEventID.Int64),
+		nullString(log.Error.Valid, log.Error.StringVal),
+		nullString(log.EmailLang.Valid, log.EmailLang.StringVal),
+		nullString(log.OzoneSet.Valid, log.OzoneSet.StringVal),
+	); err != nil {
+		return fmt.Errorf("failed to insert effect: %w", err)
+	}
+	return nil
+}
+
+// CountActions counts the events that applied effects to the subject, using the subject index.
+func (l *PostgresLogger) CountActions(ctx context.Context, q *ActionHistoryQuery) (int64, error) {
+	var count int64
+	if err := l.pool.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT action_id) FROM osprey_effects
+		WHERE subject = $1 AND created_at >= $2 AND NOT shadow AND error IS NULL AND kind <> 'dry-run'
+			AND ($3 = '' OR $3 = ANY(string_to_array(rules, ',')))
+			AND ($4 = '' OR kind = $4)`,
+		q.Subject, q.Since, q.Rule, q.Kind,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count actions: %w", err)
+	}
+	return count, nil
+}
+
+func nullString(valid bool, s string) *string {
+	if !valid {
+		return nil
+	}
+	return &s
+}
+
+func nullInt64(valid bool, n int64) *int64 {
+	if !valid {
+		return nil
+	}
+	return &n
+}
+
+func (l *PostgresLogger) Close() {
+	l.pool.Close()
+}
@@ -0,0 +1,175 @@
+package effector
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/labstack/echo/v4"
+)
+
+// recentActions keeps the last events the effector handled in memory along with the effects they
+// had, so that on-call can check what happened to an action through the admin API without querying
+// the effect log.
+type recentActions struct {
+	mu sync.Mutex
+	// entries is a ring buffer, with next the slot the next event goes in.
+	entries []*RecentAction
+	next    int
+}
+
+// RecentAction is an event the effector handled and what came of its effects. Effects applied by
+// retries are added to it for as long as it's still in the buffer.
+type RecentAction struct {
+	ActionID   int64          `json:"actionId"`
+	ActionName string         `json:"actionName"`
+	Did        string         `json:"did"`
+	Uri        string         `json:"uri,omitempty"`
+	SendTime   time.Time      `json:"sendTime"`
+	ReceivedAt time.Time      `json:"receivedAt"`
+	Done       bool           `json:"done"`
+	Error      string         `json:"error,omitempty"`
+	Effects    []RecentEffect `json:"effects"`
+}
+
+type RecentEffect struct {
+	Kind         string    `json:"kind"`
+	Subject      string    `json:"subject"`
+	Rules        string    `json:"rules"`
+	Label        string    `json:"label,omitempty"`
+	Tag          string    `json:"tag,omitempty"`
+	Shadow       bool      `json:"shadow,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	OzoneEventID int64     `json:"ozoneEventId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func newRecentActions(size int) *recentActions {
+	return &recentActions{entries: make([]*RecentAction, size)}
+}
+
+// add records that the event is being handled, evicting the oldest event if the buffer is full.
+func (r *recentActions) add(evt *osprey.ResultEvent) {
+	if r == nil {
+		return
+	}
+	a := &RecentAction{
+		ActionID:   evt.ActionId,
+		ActionName: evt.ActionName,
+		Did:        evt.Did,
+		Uri:        evt.Uri,
+		SendTime:   evt.SendTime.AsTime(),
+		ReceivedAt: time.Now(),
+		Effects:    []RecentEffect{},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = a
+	r.next = (r.next + 1) % len(r.entries)
+}
+
+// finish records that the event has been handled, and the error applying its effects if any.
+func (r *recentActions) finish(actionID int64, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a := r.find(actionID); a != nil {
+		a.Done = true
+		if err != nil {
+			a.Error = err.Error()
+		}
+	}
+}
+
+// addEffect adds the logged effect to its action, if the action is still in the buffer.
+func (r *recentActions) addEffect(log *OspreyEffectLog) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.find(log.ActionID)
+	if a == nil {
+		return
+	}
+	a.Effects = append(a.Effects, RecentEffect{
+		Kind:         log.Kind,
+		Subject:      log.Subject,
+		Rules:        log.Rules,
+		Label:        log.Label.StringVal,
+		Tag:          log.Tag.StringVal,
+		Shadow:       log.Shadow,
+		Error:        log.Error.StringVal,
+		OzoneEventID: log.OzoneEventID.Int64,
+		CreatedAt:    log.CreatedAt,
+	})
+}
+
+// find returns the most recent entry for the action, which must be called with the lock held.
+func (r *recentActions) find(actionID int64) *RecentAction {
+	for i := range r.entries {
+		a := r.entries[(r.next-1-i+len(r.entries))%len(r.entries)]
+		if a == nil {
+			return nil
+		}
+		if a.ActionID == actionID {
+			return a
+		}
+	}
+	return nil
+}
+
+// list returns up to limit of the most recent actions matching the filters, newest first. The
+// entries are copied, since handling can still add effects to them.
+func (r *recentActions) list(actionID int64, did string, limit int) []RecentAction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := []RecentAction{}
+	for i := range r.entries {
+		a := r.entries[(r.next-1-i+len(r.entries))%len(r.entries)]
+		if a == nil || len(out) >= limit {
+			break
+		}
+		if (actionID != 0 && a.ActionID != actionID) || (did != "" && a.Did != did) {
+			continue
+		}
+		cp := *a
+		cp.Effects = append([]RecentEffect{}, a.Effects...)
+		out = append(out, cp)
+	}
+	return out
+}
+
+// handleRecentActions lists the most recent events the effector handled, newest first, optionally
+// filtered by action ID or DID.
+func (or *OspreyEffector) handleRecentActions(e echo.Context) error {
+	if or.recentActions == nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "recent actions aren't being kept"})
+	}
+
+	var actionID int64
+	if s := e.QueryParam("action_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid action_id"})
+		}
+		actionID = id
+	}
+
+	limit := 100
+	if s := e.QueryParam("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+		}
+		limit = n
+	}
+
+	return e.JSON(http.StatusOK, or.recentActions.list(actionID, e.QueryParam("did"), limit))
+}
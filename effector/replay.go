@@ -0,0 +1,150 @@
+package effector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/bluesky-social/go-util/pkg/bus/producer"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+type ReplayArgs struct {
+	BigQueryCredentialsJson []byte
+	BigQueryProjectID       string
+	BigQueryDatasetID       string
+
+	BootstrapServers []string
+	InputTopic       string
+
+	// Events logged from Start up to End are replayed.
+	Start time.Time
+	End   time.Time
+	// Rule, when set, only replays events with an effect caused by the rule.
+	Rule string
+	// Limit is the most events that are replayed, or zero for no limit.
+	Limit int
+	// DryRun logs the events that would be replayed without producing them.
+	DryRun bool
+
+	Logger *slog.Logger
+}
+
+// Replay reads events from the BigQuery event log and produces them onto the input topic again, so
+// that the effector handles them as if they'd just arrived. It's meant for recovering from outages,
+// or re-running events after fixing how their effects are applied. Effects that were already taken
+// are still skipped by the action store. It returns how many events were replayed.
+func Replay(ctx context.Context, args *ReplayArgs) (int, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	logger := args.Logger.With("component", "replay")
+
+	if !args.Start.Before(args.End) {
+		return 0, errors.New("replay start must be before its end")
+	}
+	if args.BigQueryCredentialsJson == nil {
+		return 0, errors.New("bigquery credentials are required to replay events")
+	}
+
+	bqc, err := bigquery.NewClient(ctx, args.BigQueryProjectID, option.WithCredentialsJSON(args.BigQueryCredentialsJson))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create bigquery client: %w", err)
+	}
+	defer bqc.Close()
+
+	var p *producer.Producer[*osprey.ResultEvent]
+	if !args.DryRun {
+		p, err = producer.New[*osprey.ResultEvent](ctx, logger, args.BootstrapServers, args.InputTopic)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create input producer: %w", err)
+		}
+		defer p.Close()
+	}
+
+	sql := fmt.Sprintf("SELECT raw FROM `%s.%s.osprey-events` WHERE created_at >= @start AND created_at < @end",
+		args.BigQueryProjectID, args.BigQueryDatasetID)
+	params := []bigquery.QueryParameter{
+		{Name: "start", Value: args.Start},
+		{Name: "end", Value: args.End},
+	}
+	if args.Rule != "" {
+		// Narrows down the scan, events are matched on their rules exactly below.
+		sql += " AND CONTAINS_SUBSTR(raw, @rule)"
+		params = append(params, bigquery.QueryParameter{Name: "rule", Value: args.Rule})
+	}
+	sql += " ORDER BY created_at"
+
+	q := bqc.Query(sql)
+	q.Parameters = params
+
+	it, err := q.Read(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query event log: %w", err)
+	}
+
+	replayed := 0
+	for args.Limit == 0 || replayed < args.Limit {
+		var row struct {
+			Raw string `bigquery:"raw"`
+		}
+		err := it.Next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return replayed, fmt.Errorf("failed to read event log: %w", err)
+		}
+
+		var evt osprey.ResultEvent
+		if err := json.Unmarshal([]byte(row.Raw), &evt); err != nil {
+			logger.Error("failed to unmarshal logged event, skipping", "error", err)
+			continue
+		}
+		if args.Rule != "" && !slices.Contains(eventRules(&evt), args.Rule) {
+			continue
+		}
+
+		if args.DryRun {
+			logger.Info("dry run, not replaying event", "actionId", evt.ActionId, "actionName", evt.ActionName, "did", evt.Did, "uri", evt.Uri)
+		} else if err := p.ProduceSync(ctx, evt.Did, &evt); err != nil {
+			return replayed, fmt.Errorf("failed to produce event %d: %w", evt.ActionId, err)
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+// eventRules returns the rules behind any of the event's effects.
+func eventRules(evt *osprey.ResultEvent) []string {
+	var rules []string
+	rules = appendRules(rules, evt.Labels)
+	rules = appendRules(rules, evt.Tags)
+	rules = appendRules(rules, evt.Takedowns)
+	rules = appendRules(rules, evt.Mutes)
+	rules = appendRules(rules, evt.Diverts)
+	rules = appendRules(rules, evt.Reports)
+	rules = appendRules(rules, evt.Comments)
+	rules = appendRules(rules, evt.Escalations)
+	rules = appendRules(rules, evt.Acknowledgements)
+	rules = appendRules(rules, evt.ResolveAppeals)
+	rules = appendRules(rules, evt.Sets)
+	rules = appendRules(rules, evt.Emails)
+	rules = appendRules(rules, evt.BigqueryFlags)
+	return rules
+}
+
+func appendRules[T ruledEffect](rules []string, effects []T) []string {
+	for _, e := range effects {
+		rules = append(rules, e.GetRules()...)
+	}
+	return rules
+}
@@ -0,0 +1,272 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/bus/consumer"
+	"github.com/bluesky-social/go-util/pkg/bus/producer"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	effectsRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "effects_retried",
+		Namespace: NAMESPACE,
+		Help:      "number of retries of failed effects, by action name and status",
+	}, []string{"action_name", "status"})
+
+	effectsDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "effects_dead_lettered",
+		Namespace: NAMESPACE,
+		Help:      "number of events whose failed effects were dead-lettered after running out of retries, by action name",
+	}, []string{"action_name"})
+)
+
+// retryQueue holds effects that failed to apply. They're produced to a retry topic and consumed
+// back once their backoff has passed, and dead-lettered once they've failed maxAttempts times so
+// that a transient Ozone or PDS outage doesn't drop moderation actions.
+type retryQueue struct {
+	logger *slog.Logger
+
+	producer    *producer.Producer[*osprey.FailedEffects]
+	dlqProducer *producer.Producer[*osprey.FailedEffects]
+	consumer    *consumer.Consumer[*osprey.FailedEffects]
+
+	maxAttempts int32
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	// quit interrupts retries waiting out their backoff when shutting down.
+	quit chan struct{}
+	// handlers tracks the retries being handled, so that shutting down waits for them.
+	handlers sync.WaitGroup
+}
+
+func retryTopic(inputTopic, consumerGroup string) string {
+	return fmt.Sprintf("%s-%s-retry", inputTopic, consumerGroup)
+}
+
+// retryConsumerGroup is the group the retry topic is consumed in. It's kept apart from the input
+// topic's group so that the two are rebalanced separately.
+func retryConsumerGroup(consumerGroup string) string {
+	return consumerGroup + "-retry"
+}
+
+func deadLetterTopic(inputTopic, consumerGroup string) string {
+	return fmt.Sprintf("%s-%s-dlq", inputTopic, consumerGroup)
+}
+
+func (or *OspreyEffector) newRetryQueue(ctx context.Context, args *Args) (*retryQueue, error) {
+	rq := &retryQueue{
+		logger:      args.Logger.With("component", "retry_queue"),
+		maxAttempts: int32(args.RetryMaxAttempts),
+		baseDelay:   args.RetryBaseDelay,
+		maxDelay:    args.RetryMaxDelay,
+		quit:        make(chan struct{}),
+	}
+
+	topic := retryTopic(args.InputTopic, args.ConsumerGroup)
+	p, err := producer.New(ctx, args.Logger, args.BootstrapServers, topic,
+		producer.WithEnsureTopic[*osprey.FailedEffects](true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating retry producer: %w", err)
+	}
+	rq.producer = p
+
+	dlqTopic := deadLetterTopic(args.InputTopic, args.ConsumerGroup)
+	dp, err := producer.New(ctx, args.Logger, args.BootstrapServers, dlqTopic,
+		producer.WithEnsureTopic[*osprey.FailedEffects](true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dead letter producer: %w", err)
+	}
+	rq.dlqProducer = dp
+
+	// Retries wait out their backoff in the handler, so this has to consume in order for the waits
+	// to hold up the records behind them rather than running them all at once.
+	c, err := consumer.New(args.Logger, args.BootstrapServers, topic, retryConsumerGroup(args.ConsumerGroup),
+		consumer.WithOffset[*osprey.FailedEffects](consumer.OffsetStart),
+		consumer.WithInOrderConsumption[*osprey.FailedEffects](),
+		consumer.WithMessageHandler(or.handleRetry),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating retry consumer: %w", err)
+	}
+	rq.consumer = c
+
+	return rq, nil
+}
+
+// backoff is how long to wait before the next attempt after the given number of attempts.
+func (rq *retryQueue) backoff(attempts int32) time.Duration {
+	delay := rq.baseDelay
+	for i := int32(1); i < attempts && delay < rq.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, rq.maxDelay)
+}
+
+// scheduleRetry queues the failed effects for another attempt, or dead-letters them if they've
+// already been attempted as many times as allowed.
+func (or *OspreyEffector) scheduleRetry(failed *osprey.ResultEvent, attempts int32, err error) {
+	if or.retries == nil {
+		return
+	}
+
+	fe := &osprey.FailedEffects{
+		Event:     failed,
+		Attempts:  attempts,
+		LastError: err.Error(),
+	}
+
+	if attempts >= or.retries.maxAttempts {
+		or.deadLetter(fe)
+		return
+	}
+
+	fe.NextAttemptAt = timestamppb.New(time.Now().Add(or.retries.backoff(attempts)))
+
+	// The event is acknowledged once this returns, so keep trying until the effects are safely on
+	// the retry topic rather than dropping them. This holds up the worker, and so the consumer,
+	// while Kafka is unavailable.
+	for delay := time.Second; ; delay = min(delay*2, 30*time.Second) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := or.retries.producer.ProduceSync(ctx, failed.Did, fe)
+		cancel()
+		if err == nil {
+			break
+		}
+		or.retries.logger.Error("failed to queue effects for retry", "actionId", failed.ActionId, "error", err)
+
+		select {
+		case <-time.After(delay):
+		case <-or.retries.quit:
+			return
+		}
+	}
+	or.retries.logger.Info("queued failed effects for retry", "actionId", failed.ActionId, "attempts", attempts, "next_attempt_at", fe.NextAttemptAt.AsTime())
+}
+
+// handleRetry waits out the retry's backoff and then hands it to the workers, returning once it's
+// been applied or queued again, so that its offset is only committed after that and a crash
+// replays it instead of dropping it. Waiting holds up the partition behind it, which only delays
+// retries that are due later anyway, and since retries have a consumer group of their own it
+// doesn't hold up rebalancing of the input topic.
+func (or *OspreyEffector) handleRetry(ctx context.Context, fe *osprey.FailedEffects) error {
+	if fe == nil || fe.Event == nil {
+		return nil
+	}
+
+	or.retries.handlers.Add(1)
+	defer or.retries.handlers.Done()
+
+	if wait := time.Until(fe.NextAttemptAt.AsTime()); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-or.retries.quit:
+			or.requeueRetry(fe)
+			return nil
+		}
+	}
+
+	if err := or.pauseGate.wait(ctx); err != nil {
+		or.requeueRetry(fe)
+		return nil
+	}
+
+	// Retries go through the workers like new events, so that they're applied in order with the
+	// subject's other effects rather than racing them.
+	done, err := or.workers.submit(ctx, eventSubject(fe.Event), func() { or.applyRetry(fe) })
+	if err != nil {
+		or.requeueRetry(fe)
+		return nil
+	}
+	<-done
+
+	return nil
+}
+
+func (or *OspreyEffector) applyRetry(fe *osprey.FailedEffects) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// The allowlist, kill switches, and suppressions may have changed since these were queued.
+	or.dropBlockedEffects(fe.Event)
+	or.dropSuppressedEffects(fe.Event)
+
+	failed, err := or.applyEffects(ctx, fe.Event)
+	if err != nil {
+		effectsRetried.WithLabelValues(fe.Event.ActionName, "error").Inc()
+		or.scheduleRetry(failed, fe.Attempts+1, err)
+		return
+	}
+	effectsRetried.WithLabelValues(fe.Event.ActionName, "ok").Inc()
+}
+
+// requeueRetry puts the retry back so the next consumer picks it up when it can't be handled while
+// shutting down, since the offset is committed either way.
+func (or *OspreyEffector) requeueRetry(fe *osprey.FailedEffects) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := or.retries.producer.ProduceSync(ctx, fe.Event.Did, fe); err != nil {
+		or.retries.logger.Error("failed to requeue retry on shutdown", "actionId", fe.Event.ActionId, "error", err)
+	}
+}
+
+// deadLetter produces the failed effects to the dead letter topic and alerts on them, since they
+// won't be retried again.
+func (or *OspreyEffector) deadLetter(fe *osprey.FailedEffects) {
+	evt := fe.Event
+	effectsDeadLettered.WithLabelValues(evt.ActionName).Inc()
+
+	or.retries.logger.Error("dead-lettering failed effects after running out of retries",
+		"actionId", evt.ActionId,
+		"actionName", evt.ActionName,
+		"did", evt.Did,
+		"uri", evt.Uri,
+		"attempts", fe.Attempts,
+		"error", fe.LastError,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := or.retries.dlqProducer.ProduceSync(ctx, evt.Did, fe); err != nil {
+		or.retries.logger.Error("failed to produce to dead letter topic", "actionId", evt.ActionId, "error", err)
+	}
+
+	subject := evt.Did
+	if evt.Uri != "" {
+		subject = evt.Uri
+	}
+	msg := fmt.Sprintf(`Effects dead-lettered after %d attempts
+Action ID: %d
+Action Name: %s
+Subject: %s
+Last Error: %s`, fe.Attempts, evt.ActionId, evt.ActionName, subject, fe.LastError)
+	for _, a := range or.alerters {
+		if err := a.Alert(ctx, msg); err != nil {
+			or.retries.logger.Error("failed to send dead letter alert", "error", err)
+		}
+	}
+}
+
+// stop interrupts the retries waiting out their backoff, which requeue themselves, and stops
+// consuming retries. The producers stay open for them, and for the workers, until close.
+func (rq *retryQueue) stop() {
+	close(rq.quit)
+	rq.consumer.Close()
+}
+
+func (rq *retryQueue) close() {
+	rq.producer.Close()
+	rq.dlqProducer.Close()
+}
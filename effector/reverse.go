@@ -0,0 +1,185 @@
+package effector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+type ReverseArgs struct {
+	BigQueryCredentialsJson []byte
+	BigQueryProjectID       string
+	BigQueryDatasetID       string
+
+	Ozone *OzoneClientArgs
+
+	// Rule is the rule whose effects are reversed.
+	Rule string
+	// Effects logged from Start up to End are reversed.
+	Start time.Time
+	End   time.Time
+	// Limit is the most effects that are reversed, or zero for no limit.
+	Limit int
+	// DryRun logs the effects that would be reversed without reversing them.
+	DryRun bool
+
+	Logger *slog.Logger
+}
+
+// Reverse undoes the takedowns, labels and tags a rule applied over a time range, for cleaning up
+// after a rule that misfired. The effect log doesn't say whether an effect added or removed
+// something, so each subject is looked up in Ozone and only what's still in place is reversed. That
+// includes effects other rules or moderators applied too, which a dry run shows first. It returns
+// how many effects were reversed.
+func Reverse(ctx context.Context, args *ReverseArgs) (int, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	logger := args.Logger.With("component", "reverse", "rule", args.Rule)
+
+	if args.Rule == "" {
+		return 0, errors.New("a rule is required to reverse effects")
+	}
+	if !args.Start.Before(args.End) {
+		return 0, errors.New("reverse start must be before its end")
+	}
+	if args.BigQueryCredentialsJson == nil {
+		return 0, errors.New("bigquery credentials are required to reverse effects")
+	}
+
+	bqc, err := bigquery.NewClient(ctx, args.BigQueryProjectID, option.WithCredentialsJSON(args.BigQueryCredentialsJson))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create bigquery client: %w", err)
+	}
+	defer bqc.Close()
+
+	args.Ozone.Logger = args.Logger
+	oc, err := NewOzoneClient(ctx, args.Ozone)
+	if err != nil {
+		return 0, fmt.Errorf("could not create ozone client: %w", err)
+	}
+	defer oc.Close()
+
+	sql := fmt.Sprintf("SELECT subject, kind, label, tag FROM `%s.%s.osprey-effects`"+
+		" WHERE created_at >= @start AND created_at < @end AND @rule IN UNNEST(SPLIT(rules, ','))"+
+		" AND NOT shadow AND error IS NULL AND kind IN UNNEST(@kinds)"+
+		" GROUP BY subject, kind, label, tag ORDER BY MIN(created_at)",
+		args.BigQueryProjectID, args.BigQueryDatasetID)
+	q := bqc.Query(sql)
+	q.Parameters = []bigquery.QueryParameter{
+		{Name: "start", Value: args.Start},
+		{Name: "end", Value: args.End},
+		{Name: "rule", Value: args.Rule},
+		{Name: "kinds", Value: []string{EffectTakedown, EffectLabel, EffectTag}},
+	}
+
+	it, err := q.Read(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query effect log: %w", err)
+	}
+
+	meta := ModToolMeta{Rules: args.Rule}
+	comment := fmt.Sprintf("Reversing effects of rule %s from %s to %s",
+		args.Rule, args.Start.Format(time.RFC3339), args.End.Format(time.RFC3339))
+
+	statuses := map[string]*SubjectStatus{}
+	reversed, failed := 0, 0
+	for args.Limit == 0 || reversed < args.Limit {
+		var row struct {
+			Subject string              `bigquery:"subject"`
+			Kind    string              `bigquery:"kind"`
+			Label   bigquery.NullString `bigquery:"label"`
+			Tag     bigquery.NullString `bigquery:"tag"`
+		}
+		err := it.Next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return reversed, fmt.Errorf("failed to read effect log: %w", err)
+		}
+
+		elog := logger.With("subject", row.Subject, "kind", row.Kind, "label", row.Label.StringVal, "tag", row.Tag.StringVal)
+
+		// Messages can't be looked up, and only ever get tags that are harmless to leave.
+		if !strings.HasPrefix(row.Subject, "did:") && !strings.HasPrefix(row.Subject, "at://") {
+			elog.Info("skipping effect on a subject that can't be reversed")
+			continue
+		}
+
+		status, ok := statuses[row.Subject]
+		if !ok {
+			status, err = oc.GetSubjectStatus(ctx, row.Subject)
+			if err != nil {
+				elog.Error("failed to get subject status, skipping", "error", err)
+				failed++
+				continue
+			}
+			statuses[row.Subject] = status
+		}
+
+		isActor := strings.HasPrefix(row.Subject, "did:")
+		var reverse func() error
+		switch row.Kind {
+		case EffectTakedown:
+			if !status.Takendown {
+				continue
+			}
+			reverse = func() error {
+				if isActor {
+					return oc.TakedownActor(ctx, row.Subject, meta, comment, nil, nil, nil, true)
+				}
+				return oc.TakedownRecord(ctx, row.Subject, status.Cid, meta, comment, nil, nil, nil, true)
+			}
+		case EffectLabel:
+			label, ok := AtprotoLabelFromString(row.Label.StringVal)
+			if !ok {
+				elog.Warn("skipping unknown label")
+				continue
+			}
+			if !status.Labels[row.Label.StringVal] {
+				continue
+			}
+			reverse = func() error {
+				if isActor {
+					return oc.LabelActor(ctx, row.Subject, meta, label, comment, nil, nil, true)
+				}
+				return oc.LabelRecord(ctx, row.Subject, status.Cid, meta, label, comment, nil, nil, true)
+			}
+		case EffectTag:
+			if !status.Tags[row.Tag.StringVal] {
+				continue
+			}
+			remove := []string{row.Tag.StringVal}
+			reverse = func() error {
+				if isActor {
+					return oc.TagActor(ctx, row.Subject, meta, nil, remove, &comment)
+				}
+				return oc.TagRecord(ctx, row.Subject, status.Cid, meta, nil, remove, &comment)
+			}
+		}
+
+		if args.DryRun {
+			elog.Info("dry run, not reversing effect")
+		} else if err := reverse(); err != nil {
+			elog.Error("failed to reverse effect", "error", err)
+			failed++
+			continue
+		} else {
+			elog.Info("reversed effect")
+		}
+		reversed++
+	}
+
+	if failed > 0 {
+		return reversed, fmt.Errorf("failed to reverse %d effects", failed)
+	}
+	return reversed, nil
+}
@@ -0,0 +1,237 @@
+package effector
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Rule modes, as used in the rule modes file. A rule can also be given a rollout percentage like
+// "10%", which enforces its effects for that share of accounts and shadows them for the rest.
+const (
+	RuleModeEnforce = "enforce"
+	RuleModeShadow  = "shadow"
+)
+
+var effectsShadowed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "effects_shadowed",
+	Namespace: NAMESPACE,
+	Help:      "number of effects that were logged but not applied because their rules are shadowed, by type and action name",
+}, []string{"type", "action_name"})
+
+// ruleModes holds the percentage of accounts, from 0 to 100, that each rule's effects are
+// enforced for. Rules that aren't listed are always enforced.
+type ruleModes map[string]float64
+
+// loadRuleModes reads a JSON file mapping rule names to a mode, e.g.
+//
+//	{"NewSpamRule": "shadow", "NewSlurRule": "10%", "OldRule": "enforce"}
+func loadRuleModes(path string) (ruleModes, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule modes: %w", err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule modes: %w", err)
+	}
+
+	modes := ruleModes{}
+	for rule, mode := range raw {
+		switch mode {
+		case RuleModeEnforce:
+			modes[rule] = 100
+		case RuleModeShadow:
+			modes[rule] = 0
+		default:
+			pct, err := strconv.ParseFloat(strings.TrimSuffix(mode, "%"), 64)
+			if err != nil || !strings.HasSuffix(mode, "%") || pct < 0 || pct > 100 {
+				return nil, fmt.Errorf("invalid mode %q for rule %s, must be %q, %q, or a percentage like \"10%%\"", mode, rule, RuleModeEnforce, RuleModeShadow)
+			}
+			modes[rule] = pct
+		}
+	}
+	return modes, nil
+}
+
+// enforced reports whether the rule's effects are applied for the account. Sampling is by account
+// rather than at random, so that an account stays on the same side of a rollout across events,
+// retries, and the effects that undo earlier ones.
+func (rm ruleModes) enforced(rule, did string) bool {
+	pct, ok := rm[rule]
+	if !ok || pct >= 100 {
+		return true
+	}
+	if pct <= 0 {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(rule))
+	h.Write([]byte{0})
+	h.Write([]byte(did))
+	return float64(h.Sum32()%10_000) < pct*100
+}
+
+type ruledEffect interface {
+	GetRules() []string
+	GetComment() string
+}
+
+// splitShadowed separates out the effects that none of their rules are enforced for. Effects
+// without any rules are always enforced.
+func splitShadowed[T ruledEffect](rm ruleModes, did string, effects []T) (enforced, shadowed []T) {
+	for _, e := range effects {
+		rules := e.GetRules()
+		enforce := len(rules) == 0
+		for _, rule := range rules {
+			if rm.enforced(rule, did) {
+				enforce = true
+				break
+			}
+		}
+
+		if enforce {
+			enforced = append(enforced, e)
+		} else {
+			shadowed = append(shadowed, e)
+		}
+	}
+	return enforced, shadowed
+}
+
+// splitShadowEffects removes the effects that are shadowed from the event, and returns them in an
+// event of their own. It returns nil when nothing is shadowed.
+func (or *OspreyEffector) splitShadowEffects(evt *osprey.ResultEvent) *osprey.ResultEvent {
+	if or.ruleModes == nil {
+		return nil
+	}
+
+	shadow := &osprey.ResultEvent{
+		SendTime:   evt.SendTime,
+		ActionName: evt.ActionName,
+		ActionId:   evt.ActionId,
+		Did:        evt.Did,
+		Uri:        evt.Uri,
+		Cid:        evt.Cid,
+	}
+
+	evt.Labels, shadow.Labels = splitShadowed(or.ruleModes, evt.Did, evt.Labels)
+	evt.Tags, shadow.Tags = splitShadowed(or.ruleModes, evt.Did, evt.Tags)
+	evt.Takedowns, shadow.Takedowns = splitShadowed(or.ruleModes, evt.Did, evt.Takedowns)
+	evt.Mutes, shadow.Mutes = splitShadowed(or.ruleModes, evt.Did, evt.Mutes)
+	evt.Diverts, shadow.Diverts = splitShadowed(or.ruleModes, evt.Did, evt.Diverts)
+	evt.Reports, shadow.Reports = splitShadowed(or.ruleModes, evt.Did, evt.Reports)
+	evt.Comments, shadow.Comments = splitShadowed(or.ruleModes, evt.Did, evt.Comments)
+	evt.Escalations, shadow.Escalations = splitShadowed(or.ruleModes, evt.Did, evt.Escalations)
+	evt.Acknowledgements, shadow.Acknowledgements = splitShadowed(or.ruleModes, evt.Did, evt.Acknowledgements)
+	evt.ResolveAppeals, shadow.ResolveAppeals = splitShadowed(or.ruleModes, evt.Did, evt.ResolveAppeals)
+	evt.Sets, shadow.Sets = splitShadowed(or.ruleModes, evt.Did, evt.Sets)
+	evt.Emails, shadow.Emails = splitShadowed(or.ruleModes, evt.Did, evt.Emails)
+	evt.BigqueryFlags, shadow.BigqueryFlags = splitShadowed(or.ruleModes, evt.Did, evt.BigqueryFlags)
+
+	if len(shadow.Labels)+len(shadow.Tags)+len(shadow.Takedowns)+len(shadow.Mutes)+len(shadow.Diverts)+
+		len(shadow.Reports)+len(shadow.Comments)+len(shadow.Escalations)+len(shadow.Acknowledgements)+
+		len(shadow.ResolveAppeals)+len(shadow.Sets)+len(shadow.Emails)+len(shadow.BigqueryFlags) == 0 {
+		return nil
+	}
+	return shadow
+}
+
+// logShadowEffects logs the shadowed effects the same way applied ones are, without applying them.
+func (or *OspreyEffector) logShadowEffects(evt *osprey.ResultEvent) {
+	for _, e := range evt.Labels {
+		log := newShadowEffectLog(or, evt, EffectLabel, e)
+		log.Label = bigquery.NullString{StringVal: AtprotoLabelToString(e.Label), Valid: true}
+		or.logEffect(log)
+	}
+	for _, e := range evt.Tags {
+		log := newShadowEffectLog(or, evt, EffectTag, e)
+		log.Tag = bigquery.NullString{StringVal: e.Tag, Valid: true}
+		or.logEffect(log)
+	}
+	for _, e := range evt.Takedowns {
+		or.logEffect(newShadowEffectLog(or, evt, EffectTakedown, e))
+	}
+	for _, e := range evt.Mutes {
+		or.logEffect(newShadowEffectLog(or, evt, EffectMute, e))
+	}
+	for _, e := range evt.Diverts {
+		or.logEffect(newShadowEffectLog(or, evt, EffectDivert, e))
+	}
+	for _, e := range evt.Reports {
+		or.logEffect(newShadowEffectLog(or, evt, EffectReport, e))
+	}
+	for _, e := range evt.Comments {
+		or.logEffect(newShadowEffectLog(or, evt, EffectComment, e))
+	}
+	for _, e := range evt.Escalations {
+		log := newShadowEffectLog(or, evt, EffectEscalation, e)
+		if e.GetQueue() != "" {
+			log.Tag = bigquery.NullString{StringVal: escalationQueueTagPrefix + e.GetQueue(), Valid: true}
+		}
+		or.logEffect(log)
+	}
+	for _, e := range evt.Acknowledgements {
+		or.logEffect(newShadowEffectLog(or, evt, EffectAcknowledgement, e))
+	}
+	for _, e := range evt.ResolveAppeals {
+		or.logEffect(newShadowEffectLog(or, evt, EffectResolveAppeal, e))
+	}
+	for _, e := range evt.Sets {
+		log := newShadowEffectLog(or, evt, EffectSet, e)
+		log.OzoneSet = bigquery.NullString{StringVal: e.Set, Valid: true}
+		or.logEffect(log)
+	}
+	for _, e := range evt.Emails {
+		or.logEffect(newShadowEffectLog(or, evt, EffectEmail, e))
+	}
+	for _, e := range evt.BigqueryFlags {
+		log := newShadowEffectLog(or, evt, EffectBigQueryFlag, e)
+		log.Tag = bigquery.NullString{StringVal: e.Tag, Valid: true}
+		or.logEffect(log)
+	}
+}
+
+// effectSubject is the subject the effect applies to: the record or message for effects on one,
+// and otherwise the account.
+func effectSubject[T any](evt *osprey.ResultEvent, e T) string {
+	if sk, ok := any(e).(subjectEffect); ok {
+		switch sk.GetSubjectKind() {
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD:
+			return evt.Uri
+		case osprey.AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE:
+			return MessageSubject(evt.Message)
+		}
+	}
+	return evt.Did
+}
+
+func newShadowEffectLog[T ruledEffect](or *OspreyEffector, evt *osprey.ResultEvent, kind string, e T) *OspreyEffectLog {
+	effectsShadowed.WithLabelValues(kind, evt.ActionName).Inc()
+
+	subject := effectSubject(evt, e)
+	rules := strings.Join(e.GetRules(), ",")
+	comment := or.effectComment(evt, kind, e.GetRules(), e.GetComment())
+
+	return &OspreyEffectLog{
+		ActionName: evt.ActionName,
+		ActionID:   evt.ActionId,
+		Subject:    subject,
+		Kind:       kind,
+		Comment:    comment,
+		CreatedAt:  time.Now(),
+		Rules:      rules,
+		Shadow:     true,
+	}
+}
@@ -0,0 +1,151 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// ruleGuardInterval is how often rule action rates are checked against their baselines.
+	ruleGuardInterval = time.Minute
+	// ruleGuardRecentMinutes is the window whose rate is compared against the rest of the hour.
+	ruleGuardRecentMinutes = 5
+	// ruleGuardWarmup is how long after starting the guard waits before checking, since until then
+	// there's too little baseline and every rule would look like it had just started acting.
+	ruleGuardWarmup = 20 * time.Minute
+)
+
+var ruleGuardTrips = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "rule_guard_trips",
+	Namespace: NAMESPACE,
+	Help:      "number of times a rule's effects were paused for acting far more than its baseline, by rule",
+}, []string{"rule"})
+
+// ruleGuard pauses the effects of rules whose action rate jumps well above their trailing baseline,
+// so that a bad rule deploy doesn't get to label half the network before anyone notices. Tripping
+// turns on the rule's kill switch, which an operator turns back off through the admin API once
+// they've looked at it.
+type ruleGuard struct {
+	// multiple is how many times its baseline rate a rule has to act at to trip the guard.
+	multiple float64
+	// minEffects is the fewest effects a rule has to cause in the recent window to trip the guard,
+	// so that rules that barely act don't trip it going from one effect to a handful.
+	minEffects int
+	started    time.Time
+
+	mu sync.Mutex
+	// tripped is when the guard last tripped for each rule. A rule isn't tripped again within the
+	// hour, so that it stays resumed when an operator turns its kill switch back off.
+	tripped map[string]time.Time
+}
+
+func newRuleGuard(multiple float64, minEffects int) *ruleGuard {
+	return &ruleGuard{
+		multiple:   multiple,
+		minEffects: minEffects,
+		started:    time.Now(),
+		tripped:    map[string]time.Time{},
+	}
+}
+
+// runRuleGuard checks rule action rates every minute until the context is cancelled.
+func (or *OspreyEffector) runRuleGuard(ctx context.Context) {
+	ticker := time.NewTicker(ruleGuardInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			or.checkRuleRates(ctx, now)
+		}
+	}
+}
+
+// checkRuleRates trips the guard for rules that enforced more than the multiple of their expected
+// effects in the last few minutes, expected being their rate over the rest of the hour.
+func (or *OspreyEffector) checkRuleRates(ctx context.Context, now time.Time) {
+	g := or.ruleGuard
+	uptime := now.Sub(g.started)
+	if uptime < ruleGuardWarmup {
+		return
+	}
+	baselineMinutes := min(int(ruleCountWindow/time.Minute), int(uptime/time.Minute)) - ruleGuardRecentMinutes
+
+	recent, baseline := or.ruleCounts.enforced(ruleGuardRecentMinutes)
+	for rule, n := range recent {
+		if n < g.minEffects {
+			continue
+		}
+		expected := float64(baseline[rule]) * ruleGuardRecentMinutes / float64(baselineMinutes)
+		if float64(n) <= g.multiple*expected {
+			continue
+		}
+		if !g.trip(rule, now) {
+			continue
+		}
+		if !or.killSwitches.setRule(rule, true) {
+			// Already killed by hand.
+			continue
+		}
+
+		ruleGuardTrips.WithLabelValues(rule).Inc()
+		or.logger.Warn("paused rule acting far above its baseline", "rule", rule, "effects", n, "expected", expected)
+
+		msg := fmt.Sprintf(`Paused effects from rule %s
+It caused %d effects in the last %d minutes, against %.1f expected from the last hour
+Turn its kill switch off through the admin API to resume it`, rule, n, ruleGuardRecentMinutes, expected)
+		for _, a := range or.alerters {
+			if err := a.Alert(ctx, msg); err != nil {
+				or.logger.Error("failed to send rule guard alert", "error", err)
+			}
+		}
+	}
+}
+
+// trip reports whether the rule wasn't already tripped within the last hour, recording that it is
+// now.
+func (g *ruleGuard) trip(rule string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if last, ok := g.tripped[rule]; ok && now.Sub(last) < ruleCountWindow {
+		return false
+	}
+	g.tripped[rule] = now
+	return true
+}
+
+// enforced returns the effects each rule enforced in the last recent minutes, including the current
+// one, and in the rest of the hour before that.
+func (c *ruleCounts) enforced(recent int) (map[string]int, map[string]int) {
+	now := time.Now().Unix() / 60
+	oldest := time.Now().Add(-ruleCountWindow).Unix()/60 + 1
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recentCounts := map[string]int{}
+	baselineCounts := map[string]int{}
+	for _, b := range c.buckets {
+		if b.minute < oldest {
+			continue
+		}
+		counts := baselineCounts
+		if b.minute > now-int64(recent) {
+			counts = recentCounts
+		}
+		for key, n := range b.counts {
+			if key.mode == "enforce" {
+				counts[key.rule] += n
+			}
+		}
+	}
+	return recentCounts, baselineCounts
+}
@@ -0,0 +1,62 @@
+package effector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// sentryFlushTimeout is how long Close waits for failures to be sent.
+const sentryFlushTimeout = 5 * time.Second
+
+// SentryErrorSink reports failed effects to Sentry. Failures are grouped by effect kind and the
+// error Ozone responded with, rather than the error message, which names the subject.
+type SentryErrorSink struct {
+	client *sentry.Client
+}
+
+type SentryErrorSinkArgs struct {
+	Dsn         string
+	Environment string
+}
+
+func NewSentryErrorSink(args *SentryErrorSinkArgs) (*SentryErrorSink, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         args.Dsn,
+		Environment: args.Environment,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sentry client: %w", err)
+	}
+	return &SentryErrorSink{client: client}, nil
+}
+
+func (s *SentryErrorSink) Name() string {
+	return "sentry"
+}
+
+func (s *SentryErrorSink) CaptureEffectFailure(ctx context.Context, f *EffectFailure) {
+	ozoneError := ozoneErrorName(f.Err)
+
+	scope := sentry.NewScope()
+	scope.SetLevel(sentry.LevelError)
+	scope.SetTag("effect_kind", f.Kind)
+	scope.SetTag("action_name", f.ActionName)
+	if ozoneError != "" {
+		scope.SetTag("ozone_error", ozoneError)
+	}
+	scope.SetContext("effect", sentry.Context{
+		"action_id": f.ActionID,
+		"subject":   f.Subject,
+		"rules":     f.Rules,
+	})
+	scope.SetFingerprint([]string{"effect-failure", f.Kind, ozoneError})
+
+	s.client.CaptureException(f.Err, &sentry.EventHint{Context: ctx}, scope)
+}
+
+func (s *SentryErrorSink) Close() {
+	s.client.Flush(sentryFlushTimeout)
+}
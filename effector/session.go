@@ -0,0 +1,270 @@
+package effector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var ozoneRelogins = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "ozone_relogins",
+	Namespace: NAMESPACE,
+	Help:      "number of times the Ozone session was started over after it couldn't be refreshed, by status",
+}, []string{"status"})
+
+// Backoff between attempts to start the Ozone session over, when it can't be refreshed and logging
+// in again fails too, so that a bad password doesn't hammer the PDS.
+const (
+	reloginBaseBackoff = 5 * time.Second
+	reloginMaxBackoff  = 5 * time.Minute
+)
+
+// Backends for persisting the Ozone session across restarts.
+const (
+	SessionStoreFile     = "file"
+	SessionStoreMemcache = "memcache"
+	SessionStorePostgres = "postgres"
+)
+
+// StoredSession is an Ozone session persisted across restarts, so that starting up resumes it
+// instead of logging in again. Logins are rate limited by the PDS, which frequent deploys trip.
+type StoredSession struct {
+	// Host and Identifier are what the session was created with. A session stored for another
+	// account or PDS isn't resumed.
+	Host       string `json:"host"`
+	Identifier string `json:"identifier"`
+
+	AccessJwt  string `json:"accessJwt"`
+	RefreshJwt string `json:"refreshJwt"`
+	Handle     string `json:"handle"`
+	Did        string `json:"did"`
+
+	// OAuth is the session when authenticating with OAuth, stored under an identifier of its own.
+	OAuth *oauth.ClientSessionData `json:"oauth,omitempty"`
+}
+
+// SessionStore persists the Ozone session. Refresh tokens are rotated on every refresh, so the
+// session is saved each time it's refreshed.
+type SessionStore interface {
+	// Load returns the stored session for the identifier, or nil if there isn't one.
+	Load(ctx context.Context, identifier string) (*StoredSession, error)
+	Save(ctx context.Context, session *StoredSession) error
+	Close()
+}
+
+// NewSessionStore creates the session store of the given kind, or returns nil if kind is empty.
+func NewSessionStore(ctx context.Context, kind, path string, memcacheServers []string, postgresURL string) (SessionStore, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case SessionStoreFile:
+		if path == "" {
+			return nil, errors.New("must supply a session path to use the file session store")
+		}
+		return NewFileSessionStore(path), nil
+	case SessionStoreMemcache:
+		if len(memcacheServers) == 0 {
+			return nil, errors.New("must supply memcache servers to use the memcache session store")
+		}
+		ms, err := NewMemcacheSessionStore(memcacheServers)
+		if err != nil {
+			return nil, err
+		}
+		return ms, nil
+	case SessionStorePostgres:
+		if postgresURL == "" {
+			return nil, errors.New("must supply a postgres url to use the postgres session store")
+		}
+		ps, err := NewPostgresSessionStore(ctx, postgresURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not create postgres session store: %w", err)
+		}
+		return ps, nil
+	default:
+		return nil, fmt.Errorf("unknown session store %q", kind)
+	}
+}
+
+// FileSessionStore keeps the session in a file, for deployments with a persistent volume.
+type FileSessionStore struct {
+	path string
+}
+
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+func (s *FileSessionStore) Load(ctx context.Context, identifier string) (*StoredSession, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var session StoredSession
+	if err := json.Unmarshal(b, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session file: %w", err)
+	}
+	if session.Identifier != identifier {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+func (s *FileSessionStore) Save(ctx context.Context, session *StoredSession) error {
+	b, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	// Write to a temp file and rename so we never leave a partial session behind. CreateTemp
+	// makes the file readable only by us, which matters since it holds credentials.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp session file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close session file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to rename session file: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSessionStore) Close() {}
+
+// MemcacheSessionStore keeps the session in memcached, shared by every replica. If memcached
+// loses it, the next start logs in again.
+type MemcacheSessionStore struct {
+	client *memcache.Client
+}
+
+func NewMemcacheSessionStore(servers []string) (*MemcacheSessionStore, error) {
+	client := memcache.New(servers...)
+	if err := client.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping memcache servers: %w", err)
+	}
+	return &MemcacheSessionStore{client: client}, nil
+}
+
+func sessionMemcacheKey(identifier string) string {
+	return "osprey-effector-ozone-session-" + identifier
+}
+
+func (s *MemcacheSessionStore) Load(ctx context.Context, identifier string) (*StoredSession, error) {
+	item, err := s.client.Get(sessionMemcacheKey(identifier))
+	if err != nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	var session StoredSession
+	if err := json.Unmarshal(item.Value, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+func (s *MemcacheSessionStore) Save(ctx context.Context, session *StoredSession) error {
+	b, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := s.client.Set(&memcache.Item{Key: sessionMemcacheKey(session.Identifier), Value: b}); err != nil {
+		return fmt.Errorf("failed to set session: %w", err)
+	}
+	return nil
+}
+
+func (s *MemcacheSessionStore) Close() {
+	s.client.Close()
+}
+
+// PostgresSessionStore keeps the session in Postgres, shared by every replica.
+type PostgresSessionStore struct {
+	pool *pgxpool.Pool
+}
+
+const createSessionsTable = `
+CREATE TABLE IF NOT EXISTS effector_ozone_sessions (
+	identifier TEXT PRIMARY KEY,
+	session JSONB NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+
+func NewPostgresSessionStore(ctx context.Context, url string) (*PostgresSessionStore, error) {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+	if _, err := pool.Exec(ctx, createSessionsTable); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to create sessions table: %w", err)
+	}
+	return &PostgresSessionStore{pool: pool}, nil
+}
+
+func (s *PostgresSessionStore) Load(ctx context.Context, identifier string) (*StoredSession, error) {
+	var b []byte
+	err := s.pool.QueryRow(ctx, `SELECT session FROM effector_ozone_sessions WHERE identifier = $1`, identifier).Scan(&b)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to select session: %w", err)
+	}
+
+	var session StoredSession
+	if err := json.Unmarshal(b, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+func (s *PostgresSessionStore) Save(ctx context.Context, session *StoredSession) error {
+	b, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO effector_ozone_sessions (identifier, session)
+		VALUES ($1, $2)
+		ON CONFLICT (identifier) DO UPDATE SET session = EXCLUDED.session, updated_at = now()`,
+		session.Identifier, b,
+	); err != nil {
+		return fmt.Errorf("failed to upsert session: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresSessionStore) Close() {
+	s.pool.Close()
+}
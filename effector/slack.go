@@ -6,25 +6,152 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bluesky-social/indigo/atproto/syntax"
+	"golang.org/x/time/rate"
 )
 
+// SlackLogger posts effects to Slack. Messages are queued and sent at the rate Slack allows, and
+// bursts are collapsed: the first effect of some rules in a batch window is posted right away, and
+// the rest are summarized in one message when the window ends.
 type SlackLogger struct {
+	// webhookUrl receives alerts, and effects that no route matches.
 	webhookUrl string
+	routes     []SlackRoute
+	logger     *slog.Logger
+
+	batchWindow    time.Duration
+	digestInterval time.Duration
+
+	batchMu sync.Mutex
+	batches map[slackBatchKey]*slackBatch
+
+	limiter     *rate.Limiter
+	queue       chan slackQueued
+	quit        chan struct{}
+	flusherDone chan struct{}
+	senderDone  chan struct{}
+	// sendCtx is cancelled when there's no more time to send what's queued on shutdown.
+	sendCtx    context.Context
+	cancelSend context.CancelFunc
+}
+
+type SlackLoggerArgs struct {
+	WebhookURL string
+	Routes     []SlackRoute
+	Logger     *slog.Logger
+
+	// BatchWindow is how long further effects of the same rules are collected into a summary
+	// after one is posted.
+	BatchWindow time.Duration
+	// DigestInterval is how often digest routes are sent their summaries.
+	DigestInterval time.Duration
+}
+
+// SlackRoute sends effects matching any of its rules and any of its kinds to a webhook of its own,
+// e.g. a team's channel. Empty rules or kinds match everything. Digest routes only get periodic
+// summaries, for high-volume rules.
+type SlackRoute struct {
+	Rules      []string `json:"rules"`
+	Kinds      []string `json:"kinds"`
+	WebhookURL string   `json:"webhook_url"`
+	Digest     bool     `json:"digest"`
 }
 
 type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+// slackAttachment wraps the blocks, since only attachments get a colored bar.
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Fields   []slackText    `json:"fields,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
 	Text string `json:"text"`
 }
 
-func NewSlackLogger(webhookUrl string) *SlackLogger {
-	return &SlackLogger{
-		webhookUrl: webhookUrl,
+type slackElement struct {
+	Type  string     `json:"type"`
+	Text  *slackText `json:"text,omitempty"`
+	URL   string     `json:"url,omitempty"`
+	Style string     `json:"style,omitempty"`
+}
+
+// Slack rejects text objects longer than these.
+const (
+	slackMaxTextLength  = 3000
+	slackMaxFieldLength = 2000
+)
+
+func NewSlackLogger(args *SlackLoggerArgs) *SlackLogger {
+	if args.BatchWindow == 0 {
+		args.BatchWindow = time.Minute
+	}
+	if args.DigestInterval == 0 {
+		args.DigestInterval = 15 * time.Minute
+	}
+
+	sendCtx, cancelSend := context.WithCancel(context.Background())
+	l := &SlackLogger{
+		webhookUrl:     args.WebhookURL,
+		routes:         args.Routes,
+		logger:         args.Logger.With("component", "slack_logger"),
+		batchWindow:    args.BatchWindow,
+		digestInterval: args.DigestInterval,
+		batches:        map[slackBatchKey]*slackBatch{},
+		// Slack allows incoming webhooks about one message a second, with short bursts
+		limiter:     rate.NewLimiter(rate.Every(time.Second), 5),
+		queue:       make(chan slackQueued, slackQueueSize),
+		quit:        make(chan struct{}),
+		flusherDone: make(chan struct{}),
+		senderDone:  make(chan struct{}),
+		sendCtx:     sendCtx,
+		cancelSend:  cancelSend,
 	}
+
+	go l.runSender()
+	go l.runFlusher()
+
+	return l
+}
+
+// LoadSlackRoutes reads a JSON file containing a list of SlackRoutes.
+func LoadSlackRoutes(path string) ([]SlackRoute, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slack routes: %w", err)
+	}
+
+	var routes []SlackRoute
+	if err := json.Unmarshal(b, &routes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal slack routes: %w", err)
+	}
+
+	for i, r := range routes {
+		if r.WebhookURL == "" {
+			return nil, fmt.Errorf("slack route %d has no webhook url", i)
+		}
+	}
+	return routes, nil
 }
 
 func (l *SlackLogger) Name() string {
@@ -37,68 +164,145 @@ func (l *SlackLogger) LogEvent(ctx context.Context, log *OspreyEventLog) error {
 }
 
 func (l *SlackLogger) LogEffect(ctx context.Context, log *OspreyEffectLog) error {
-	var bskyUrl string
-	var ozoneUrl string
-
-	if strings.HasPrefix(log.Subject, "did:") {
-		did := log.Subject
-		bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s", did)
-		ozoneUrl = fmt.Sprintf("https://admin.prod.bsky.dev/repositories/%s", did)
-	} else {
-		aturi, err := syntax.ParseATURI(log.Subject)
-		if err != nil {
-			return fmt.Errorf("failed to parse effect subject as aturi: %w", err)
+	// Failures are alerted on once they're dead-lettered, rather than on every attempt.
+	if log.Error.Valid {
+		return nil
+	}
+	for webhookUrl, digest := range l.destinations(log) {
+		if err := l.add(webhookUrl, digest, log); err != nil {
+			return err
 		}
-		did := aturi.Authority().String()
-		collection := aturi.Collection().String()
-		rkey := aturi.RecordKey().String()
+	}
+	return nil
+}
 
-		switch collection {
-		case "app.bsky.feed.post":
-			bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s/post/%s", did, rkey)
-		case "app.bsky.actor.profile":
-			bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s", did)
-		case "app.bsky.graph.list":
-			bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s/list/%s", did, rkey)
+// destinations returns the webhooks of every route the effect matches, or the default webhook if
+// none match, and whether each only gets digests. A webhook that any non-digest route sends the
+// effect to gets it right away.
+func (l *SlackLogger) destinations(log *OspreyEffectLog) map[string]bool {
+	rules := strings.Split(log.Rules, ",")
+
+	dests := map[string]bool{}
+	for _, r := range l.routes {
+		if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, log.Kind) {
+			continue
 		}
-		ozoneUrl = fmt.Sprintf("https://admin.prod.bsky.dev/repositories/%s/%s/%s", did, collection, rkey)
+		if len(r.Rules) > 0 && !slices.ContainsFunc(rules, func(rule string) bool { return slices.Contains(r.Rules, rule) }) {
+			continue
+		}
+		if digest, ok := dests[r.WebhookURL]; !ok || digest {
+			dests[r.WebhookURL] = r.Digest
+		}
+	}
+
+	if len(dests) == 0 && l.webhookUrl != "" {
+		dests[l.webhookUrl] = false
+	}
+	return dests
+}
+
+// slackSeverityColor colors effects by how much they affect the subject.
+func slackSeverityColor(log *OspreyEffectLog) string {
+	if log.Shadow {
+		return "#8b8b8b"
+	}
+	switch log.Kind {
+	case EffectTakedown:
+		return "#d32f2f"
+	case EffectLabel, EffectMute, EffectDivert, EffectEmail:
+		return "#f57c00"
+	case EffectReport, EffectEscalation:
+		return "#fbc02d"
+	default:
+		return "#1185fe"
+	}
+}
+
+func slackEffectMessage(log *OspreyEffectLog) (*slackMessage, error) {
+	bskyUrl, ozoneUrl, err := subjectUrls(log.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	title := fmt.Sprintf("%s by %s", log.Kind, log.ActionName)
+	if log.Shadow {
+		title = "[shadowed, not applied] " + title
 	}
 
-	msg := fmt.Sprintf(`
-Action ID: %d
-Action Name: %s
-Rules: %s
-Created At: %s
-Subject: %s
-Bsky URL: %s
-Ozone URL: %s
-Comment: %s`, log.ActionID, log.ActionName, log.Rules, log.CreatedAt.Format(time.RFC3339Nano), log.Subject, bskyUrl, ozoneUrl, log.Comment)
+	subject := fmt.Sprintf("<%s|%s>", ozoneUrl, log.Subject)
+	if log.Handle != "" {
+		subject = fmt.Sprintf("<%s|@%s>\n`%s`", ozoneUrl, log.Handle, log.Subject)
+	}
 
+	fields := []slackText{
+		{Type: "mrkdwn", Text: "*Subject*\n" + subject},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Rules*\n%s", truncate(log.Rules, slackMaxFieldLength))},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Action ID*\n%d", log.ActionID)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Created At*\n%s", log.CreatedAt.Format(time.RFC3339))},
+	}
 	if log.Label.Valid {
-		msg += fmt.Sprintf("\nLabel: %s", log.Label.StringVal)
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*Label*\n" + log.Label.StringVal})
 	}
 	if log.Tag.Valid {
-		msg += fmt.Sprintf("\nTag: %s", log.Tag.StringVal)
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*Tag*\n" + log.Tag.StringVal})
+	}
+	if log.OzoneSet.Valid {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*Set*\n" + log.OzoneSet.StringVal})
 	}
 	if log.Email.Valid {
-		msg += fmt.Sprintf("\nEmail: %s", log.Label.StringVal)
+		email := log.Email.StringVal
+		if log.EmailLang.Valid {
+			email = fmt.Sprintf("%s (%s)", email, log.EmailLang.StringVal)
+		}
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*Email*\n" + email})
 	}
 
-	return l.log(ctx, msg)
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(title, 150)}},
+		{Type: "section", Fields: fields},
+	}
+	if log.Comment != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("```%s```", truncate(log.Comment, slackMaxTextLength-6))},
+		})
+	}
+
+	buttons := []slackElement{
+		{Type: "button", Text: &slackText{Type: "plain_text", Text: "Open in Ozone"}, URL: ozoneUrl, Style: "primary"},
+	}
+	if bskyUrl != "" {
+		buttons = append(buttons, slackElement{Type: "button", Text: &slackText{Type: "plain_text", Text: "Open in Bluesky"}, URL: bskyUrl})
+	}
+	blocks = append(blocks, slackBlock{Type: "actions", Elements: buttons})
+
+	return &slackMessage{
+		// Shown in notifications, where blocks aren't
+		Text: fmt.Sprintf("%s: %s", title, log.Subject),
+		Attachments: []slackAttachment{{
+			Color:  slackSeverityColor(log),
+			Blocks: blocks,
+		}},
+	}, nil
 }
 
-func (l *SlackLogger) log(ctx context.Context, msg string) error {
+// Alert posts a message that isn't about any one effect, e.g. effects that failed for good, to the
+// default webhook. Alerts skip the queue, so that they aren't stuck behind a burst of effects.
+func (l *SlackLogger) Alert(ctx context.Context, msg string) error {
+	if l.webhookUrl == "" {
+		return nil
+	}
 	// wrap in backticks so it looks nice
-	msg = fmt.Sprintf("```\n%s\n```", msg)
-
-	payload := slackMessage{Text: msg}
+	return l.send(ctx, l.webhookUrl, &slackMessage{Text: fmt.Sprintf("```\n%s\n```", msg)})
+}
 
+func (l *SlackLogger) send(ctx context.Context, webhookUrl string, payload *slackMessage) error {
 	b, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", l.webhookUrl, bytes.NewReader(b))
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookUrl, bytes.NewReader(b))
 	if err != nil {
 		return err
 	}
@@ -112,5 +316,43 @@ func (l *SlackLogger) log(ctx context.Context, msg string) error {
 
 	io.Copy(io.Discard, resp.Body)
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &slackRateLimitedError{retryAfter: time.Duration(max(retryAfter, 1)) * time.Second}
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+
 	return nil
 }
+
+// subjectUrls returns links to the effect's subject in the Bluesky app and in Ozone. The app link
+// is empty for records it can't display.
+func subjectUrls(subject string) (bskyUrl string, ozoneUrl string, err error) {
+	if strings.HasPrefix(subject, "did:") {
+		did := subject
+		bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s", did)
+		ozoneUrl = fmt.Sprintf("https://admin.prod.bsky.dev/repositories/%s", did)
+	} else {
+		aturi, err := syntax.ParseATURI(subject)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse effect subject as aturi: %w", err)
+		}
+		did := aturi.Authority().String()
+		collection := aturi.Collection().String()
+		rkey := aturi.RecordKey().String()
+
+		switch collection {
+		case "app.bsky.feed.post":
+			bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s/post/%s", did, rkey)
+		case "app.bsky.actor.profile":
+			bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s", did)
+		case "app.bsky.graph.list":
+			bskyUrl = fmt.Sprintf("https://bsky.app/profile/%s/list/%s", did, rkey)
+		}
+		ozoneUrl = fmt.Sprintf("https://admin.prod.bsky.dev/repositories/%s/%s/%s", did, collection, rkey)
+	}
+
+	return bskyUrl, ozoneUrl, nil
+}
@@ -0,0 +1,231 @@
+package effector
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var slackMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name:      "slack_messages",
+	Namespace: NAMESPACE,
+	Help:      "number of messages posted to Slack, by status",
+}, []string{"status"})
+
+const (
+	slackQueueSize = 1000
+	// slackSummarySamples is how many of the summarized effects are linked in a summary.
+	slackSummarySamples = 5
+	// slackDrainTimeout is how long queued messages are still sent for when shutting down.
+	slackDrainTimeout = 10 * time.Second
+)
+
+// slackBatchKey groups effects by where they're going and the rules that caused them.
+type slackBatchKey struct {
+	webhookUrl string
+	rules      string
+	digest     bool
+}
+
+// slackBatch collects the effects of some rules until its window ends.
+type slackBatch struct {
+	start   time.Time
+	count   int
+	kinds   map[string]int
+	samples []*OspreyEffectLog
+}
+
+type slackQueued struct {
+	webhookUrl string
+	msg        *slackMessage
+}
+
+type slackRateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *slackRateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited by slack, retry after %s", e.retryAfter)
+}
+
+// add posts the effect right away if it's the first of its rules in the batch window, and
+// otherwise adds it to the batch to be summarized. Digest batches are only ever summarized.
+func (l *SlackLogger) add(webhookUrl string, digest bool, log *OspreyEffectLog) error {
+	key := slackBatchKey{webhookUrl: webhookUrl, rules: log.Rules, digest: digest}
+
+	l.batchMu.Lock()
+	b, ok := l.batches[key]
+	if !ok {
+		b = &slackBatch{start: time.Now(), kinds: map[string]int{}}
+		l.batches[key] = b
+	}
+	postNow := !ok && !digest
+	if !postNow {
+		b.count++
+		b.kinds[log.Kind]++
+		if len(b.samples) < slackSummarySamples {
+			b.samples = append(b.samples, log)
+		}
+	}
+	l.batchMu.Unlock()
+
+	if postNow {
+		msg, err := slackEffectMessage(log)
+		if err != nil {
+			return err
+		}
+		l.enqueue(webhookUrl, msg)
+	}
+	return nil
+}
+
+// enqueue drops the message rather than holding up effects while Slack is backed up.
+func (l *SlackLogger) enqueue(webhookUrl string, msg *slackMessage) {
+	select {
+	case l.queue <- slackQueued{webhookUrl: webhookUrl, msg: msg}:
+	default:
+		slackMessages.WithLabelValues("dropped").Inc()
+		l.logger.Warn("dropped slack message because the queue is full")
+	}
+}
+
+// runFlusher summarizes batches once their windows end.
+func (l *SlackLogger) runFlusher() {
+	defer close(l.flusherDone)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.flush(false)
+		case <-l.quit:
+			l.flush(true)
+			return
+		}
+	}
+}
+
+// flush summarizes the batches whose windows have ended, or all of them.
+func (l *SlackLogger) flush(all bool) {
+	now := time.Now()
+
+	l.batchMu.Lock()
+	ended := map[slackBatchKey]*slackBatch{}
+	for key, b := range l.batches {
+		window := l.batchWindow
+		if key.digest {
+			window = l.digestInterval
+		}
+		if all || now.Sub(b.start) >= window {
+			delete(l.batches, key)
+			if b.count > 0 {
+				ended[key] = b
+			}
+		}
+	}
+	l.batchMu.Unlock()
+
+	for key, b := range ended {
+		l.enqueue(key.webhookUrl, slackSummaryMessage(key, b, now))
+	}
+}
+
+func (l *SlackLogger) runSender() {
+	defer close(l.senderDone)
+
+	for q := range l.queue {
+		if err := l.limiter.Wait(l.sendCtx); err != nil {
+			slackMessages.WithLabelValues("dropped").Inc()
+			continue
+		}
+
+		for attempt := 1; ; attempt++ {
+			ctx, cancel := context.WithTimeout(l.sendCtx, 10*time.Second)
+			err := l.send(ctx, q.webhookUrl, q.msg)
+			cancel()
+
+			var rle *slackRateLimitedError
+			if errors.As(err, &rle) && attempt < 3 {
+				slackMessages.WithLabelValues("rate_limited").Inc()
+				select {
+				case <-time.After(rle.retryAfter):
+					continue
+				case <-l.sendCtx.Done():
+				}
+			}
+			if err != nil {
+				slackMessages.WithLabelValues("error").Inc()
+				l.logger.Error("failed to post slack message", "error", err)
+			} else {
+				slackMessages.WithLabelValues("ok").Inc()
+			}
+			break
+		}
+	}
+}
+
+func slackSummaryMessage(key slackBatchKey, b *slackBatch, now time.Time) *slackMessage {
+	elapsed := now.Sub(b.start).Round(time.Second)
+
+	title := fmt.Sprintf("%d more effects by %s in the last %s", b.count, key.rules, elapsed)
+	if key.digest {
+		title = fmt.Sprintf("Digest: %d effects by %s in the last %s", b.count, key.rules, elapsed)
+	}
+
+	kinds := slices.SortedFunc(maps.Keys(b.kinds), func(a, c string) int {
+		return cmp.Compare(b.kinds[c], b.kinds[a])
+	})
+	var counts []string
+	for _, kind := range kinds {
+		counts = append(counts, fmt.Sprintf("%s: %d", kind, b.kinds[kind]))
+	}
+
+	var samples []string
+	for _, log := range b.samples {
+		sample := log.Subject
+		if _, ozoneUrl, err := subjectUrls(log.Subject); err == nil {
+			sample = fmt.Sprintf("<%s|%s>", ozoneUrl, cmp.Or(log.Handle, log.Subject))
+		}
+		samples = append(samples, fmt.Sprintf("• %s %s", log.Kind, sample))
+	}
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(title, 150)}},
+		{Type: "section", Fields: []slackText{
+			{Type: "mrkdwn", Text: "*Rules*\n" + truncate(key.rules, slackMaxFieldLength)},
+			{Type: "mrkdwn", Text: "*Effects*\n" + strings.Join(counts, "\n")},
+		}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncate("*Examples*\n"+strings.Join(samples, "\n"), slackMaxTextLength)}},
+	}
+
+	return &slackMessage{
+		Text: title,
+		Attachments: []slackAttachment{{
+			Color:  slackSeverityColor(b.samples[0]),
+			Blocks: blocks,
+		}},
+	}
+}
+
+// Close summarizes whatever is batched and sends what's queued, giving up on what's left after a
+// while. Effects mustn't be logged after it's called.
+func (l *SlackLogger) Close() {
+	close(l.quit)
+	<-l.flusherDone
+	close(l.queue)
+
+	timer := time.AfterFunc(slackDrainTimeout, l.cancelSend)
+	defer timer.Stop()
+	<-l.senderDone
+	l.cancelSend()
+}
@@ -0,0 +1,216 @@
+package effector
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	suppressionsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "suppressions_active",
+		Namespace: NAMESPACE,
+		Help:      "number of subjects on the suppression list, including any that have expired but not been pruned",
+	})
+
+	effectsSuppressed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "effects_suppressed",
+		Namespace: NAMESPACE,
+		Help:      "number of effects dropped because their subject is suppressed, by type and action name",
+	}, []string{"type", "action_name"})
+)
+
+// Suppression stops all effects against a subject, as a stopgap for when a rule is wrongly
+// actioning it.
+type Suppression struct {
+	// Subject is a DID, which also covers the account's records and messages, or a record's URI.
+	Subject string `json:"subject"`
+	Reason  string `json:"reason,omitempty"`
+	// ExpiresAt is when the suppression lifts on its own, if ever.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func (s *Suppression) expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// suppressions are the subjects whose effects are dropped. Like kill switches they're set at
+// runtime, and don't survive a restart.
+type suppressions struct {
+	mu       sync.RWMutex
+	subjects map[string]*Suppression
+}
+
+func newSuppressions() *suppressions {
+	return &suppressions{subjects: map[string]*Suppression{}}
+}
+
+func (s *suppressions) empty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subjects) == 0
+}
+
+// suppressed returns the first of the subjects that's suppressed and hasn't expired, if any.
+func (s *suppressions) suppressed(subjects ...string) (string, bool) {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, subject := range subjects {
+		if sup, ok := s.subjects[subject]; ok && !sup.expired(now) {
+			return subject, true
+		}
+	}
+	return "", false
+}
+
+// set adds the suppression, replacing any existing one for the subject.
+func (s *suppressions) set(sup *Suppression) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects[sup.Subject] = sup
+	s.prune()
+}
+
+// remove reports whether the subject was suppressed.
+func (s *suppressions) remove(subject string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.subjects[subject]
+	delete(s.subjects, subject)
+	s.prune()
+	return ok
+}
+
+// list returns the suppressions that haven't expired, by subject.
+func (s *suppressions) list() []*Suppression {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	list := make([]*Suppression, 0, len(s.subjects))
+	for _, subject := range slices.Sorted(maps.Keys(s.subjects)) {
+		list = append(list, s.subjects[subject])
+	}
+	return list
+}
+
+// prune drops expired suppressions. The caller must hold the lock.
+func (s *suppressions) prune() {
+	now := time.Now()
+	maps.DeleteFunc(s.subjects, func(_ string, sup *Suppression) bool {
+		return sup.expired(now)
+	})
+	suppressionsActive.Set(float64(len(s.subjects)))
+}
+
+// dropSuppressedEffects drops the event's effects against suppressed subjects, i.e. effects whose
+// subject is suppressed, or whose subject is a record or message of a suppressed account.
+func (or *OspreyEffector) dropSuppressedEffects(evt *osprey.ResultEvent) {
+	if or.suppressions.empty() {
+		return
+	}
+
+	evt.Labels = suppressEffects(or, evt, EffectLabel, evt.Labels)
+	evt.Tags = suppressEffects(or, evt, EffectTag, evt.Tags)
+	evt.Takedowns = suppressEffects(or, evt, EffectTakedown, evt.Takedowns)
+	evt.Mutes = suppressEffects(or, evt, EffectMute, evt.Mutes)
+	evt.Diverts = suppressEffects(or, evt, EffectDivert, evt.Diverts)
+	evt.Reports = suppressEffects(or, evt, EffectReport, evt.Reports)
+	evt.Comments = suppressEffects(or, evt, EffectComment, evt.Comments)
+	evt.Escalations = suppressEffects(or, evt, EffectEscalation, evt.Escalations)
+	evt.Acknowledgements = suppressEffects(or, evt, EffectAcknowledgement, evt.Acknowledgements)
+	evt.ResolveAppeals = suppressEffects(or, evt, EffectResolveAppeal, evt.ResolveAppeals)
+	evt.Sets = suppressEffects(or, evt, EffectSet, evt.Sets)
+	evt.Emails = suppressEffects(or, evt, EffectEmail, evt.Emails)
+	evt.BigqueryFlags = suppressEffects(or, evt, EffectBigQueryFlag, evt.BigqueryFlags)
+}
+
+func suppressEffects[T ruledEffect](or *OspreyEffector, evt *osprey.ResultEvent, kind string, effects []T) []T {
+	kept := effects[:0]
+	for _, e := range effects {
+		subject := effectSubject(evt, e)
+		if suppressed, ok := or.suppressions.suppressed(subject, evt.Did); ok {
+			or.logger.Warn("dropping effect against a suppressed subject",
+				"kind", kind,
+				"subject", subject,
+				"suppressed", suppressed,
+				"rules", strings.Join(e.GetRules(), ","),
+				"actionId", evt.ActionId,
+				"actionName", evt.ActionName,
+			)
+			effectsSuppressed.WithLabelValues(kind, evt.ActionName).Inc()
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+type suppressionRequest struct {
+	Subject string `json:"subject"`
+	Reason  string `json:"reason"`
+	// ExpiresInHours lifts the suppression after that many hours, if set.
+	ExpiresInHours int64 `json:"expiresInHours"`
+}
+
+func (or *OspreyEffector) handleListSuppressions(e echo.Context) error {
+	return e.JSON(http.StatusOK, or.suppressions.list())
+}
+
+// handleSetSuppression takes the subject in the body rather than the path, since record URIs have
+// slashes in them.
+func (or *OspreyEffector) handleSetSuppression(e echo.Context) error {
+	var req suppressionRequest
+	if err := e.Bind(&req); err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if !validSuppressionSubject(req.Subject) {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "subject must be a did or an at:// uri"})
+	}
+	if req.ExpiresInHours < 0 {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "expiresInHours must not be negative"})
+	}
+
+	sup := &Suppression{
+		Subject:   req.Subject,
+		Reason:    req.Reason,
+		CreatedAt: time.Now(),
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := sup.CreatedAt.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		sup.ExpiresAt = &expiresAt
+	}
+	or.suppressions.set(sup)
+	or.logger.Warn("subject suppressed through the admin api", "subject", sup.Subject, "reason", sup.Reason, "expiresAt", sup.ExpiresAt)
+
+	return or.handleListSuppressions(e)
+}
+
+func (or *OspreyEffector) handleRemoveSuppression(e echo.Context) error {
+	subject := e.QueryParam("subject")
+	if or.suppressions.remove(subject) {
+		or.logger.Warn("subject unsuppressed through the admin api", "subject", subject)
+	}
+	return or.handleListSuppressions(e)
+}
+
+func validSuppressionSubject(subject string) bool {
+	if strings.HasPrefix(subject, "did:") {
+		_, err := syntax.ParseDID(subject)
+		return err == nil
+	}
+	_, err := syntax.ParseATURI(subject)
+	return err == nil
+}
@@ -0,0 +1,58 @@
+package effector
+
+import (
+	"fmt"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// tagExpiredActionName is the action name expired tags are removed under.
+const tagExpiredActionName = "atproto.tag#expired"
+
+// tagExpiryKey is the delayed effects key a tag's removal is scheduled under, so that tagging the
+// subject again pushes the removal back, and removing the tag cancels it.
+func tagExpiryKey(subject, tag string) string {
+	return fmt.Sprintf("tag-expiry|%s|%s", subject, tag)
+}
+
+// trackTagExpiry schedules the removal of a tag that was applied with an expiration, since Ozone
+// tags don't expire, or cancels the removal of a tag that was removed. Removals are delayed effects,
+// so they're applied and logged like any other tag effect of the rules that applied the tag.
+func (or *OspreyEffector) trackTagExpiry(evt *osprey.ResultEvent, subject string, e *osprey.AtprotoTagEffect) {
+	key := tagExpiryKey(subject, e.Tag)
+
+	if e.EffectKind == osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE {
+		if or.delayedEffects.cancel(key) {
+			or.logger.Info("cancelled expiry of removed tag", "subject", subject, "tag", e.Tag)
+		}
+		return
+	}
+	if e.ExpirationInHours == nil || *e.ExpirationInHours <= 0 || !or.ozoneClient.live(evt.Did) {
+		return
+	}
+
+	comment := fmt.Sprintf("Tag expired after %d hours", *e.ExpirationInHours)
+	removal := &osprey.ResultEvent{
+		SendTime:   evt.SendTime,
+		ActionName: tagExpiredActionName,
+		ActionId:   evt.ActionId,
+		Did:        evt.Did,
+		Uri:        evt.Uri,
+		Cid:        evt.Cid,
+		Message:    evt.Message,
+		Tags: []*osprey.AtprotoTagEffect{{
+			EffectKind:  osprey.AtprotoEffectKind_ATPROTO_EFFECT_KIND_REMOVE,
+			SubjectKind: e.SubjectKind,
+			Tag:         e.Tag,
+			Comment:     &comment,
+			Rules:       e.Rules,
+		}},
+	}
+	if err := or.delayedEffects.schedule(evt, &osprey.DelayedEffects{
+		Event:               removal,
+		ExecuteAfterSeconds: *e.ExpirationInHours * 3600,
+		Key:                 key,
+	}); err != nil {
+		or.logger.Error("failed to schedule tag expiry", "subject", subject, "tag", e.Tag, "error", err)
+	}
+}
@@ -0,0 +1,265 @@
+package effector
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// WebhookSignatureHeader holds the hex encoded HMAC-SHA256 of "<timestamp>.<body>", keyed with
+	// the shared secret, prefixed with "sha256=".
+	WebhookSignatureHeader = "X-Osprey-Signature"
+	// WebhookTimestampHeader holds the unix time the batch was signed at, so that receivers can
+	// reject replayed requests.
+	WebhookTimestampHeader = "X-Osprey-Timestamp"
+)
+
+var (
+	webhookBatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "webhook_batches",
+		Namespace: NAMESPACE,
+		Help:      "number of batches posted to the webhook, by status",
+	}, []string{"status"})
+
+	webhookDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "webhook_dropped",
+		Namespace: NAMESPACE,
+		Help:      "number of logs dropped because the webhook queue was full",
+	})
+)
+
+// WebhookLogger posts batches of effect logs, and optionally event logs, to an external HTTPS
+// endpoint so that other systems can follow moderation actions without consuming Kafka. Each
+// request is signed with a shared secret, and retried with backoff when the endpoint fails.
+type WebhookLogger struct {
+	url    string
+	secret []byte
+	client *http.Client
+	logger *slog.Logger
+
+	includeEvents bool
+	batchSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+
+	queue chan webhookItem
+	wg    sync.WaitGroup
+}
+
+type WebhookLoggerArgs struct {
+	URL    string
+	Secret string
+	Logger *slog.Logger
+
+	// IncludeEvents also posts every event, not just the effects taken on them.
+	IncludeEvents bool
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxAttempts   int
+}
+
+type webhookItem struct {
+	event  *OspreyEventLog
+	effect *OspreyEffectLog
+}
+
+// webhookBatch is the body of each request.
+type webhookBatch struct {
+	Events  []*OspreyEventLog  `json:"events"`
+	Effects []*OspreyEffectLog `json:"effects"`
+}
+
+func NewWebhookLogger(args *WebhookLoggerArgs) (*WebhookLogger, error) {
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook url: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("webhook url must use https, got %q", u.Scheme)
+	}
+	if args.Secret == "" {
+		return nil, errors.New("webhook secret is required to sign requests")
+	}
+
+	if args.BatchSize == 0 {
+		args.BatchSize = 50
+	}
+	if args.FlushInterval == 0 {
+		args.FlushInterval = 5 * time.Second
+	}
+	if args.MaxAttempts == 0 {
+		args.MaxAttempts = 5
+	}
+
+	l := &WebhookLogger{
+		url:    args.URL,
+		secret: []byte(args.Secret),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger:        args.Logger.With("component", "webhook_logger"),
+		includeEvents: args.IncludeEvents,
+		batchSize:     args.BatchSize,
+		flushInterval: args.FlushInterval,
+		maxAttempts:   args.MaxAttempts,
+		queue:         make(chan webhookItem, args.BatchSize*10),
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return l, nil
+}
+
+func (l *WebhookLogger) Name() string {
+	return "webhook"
+}
+
+func (l *WebhookLogger) LogEvent(ctx context.Context, log *OspreyEventLog) error {
+	if !l.includeEvents {
+		return nil
+	}
+	l.enqueue(webhookItem{event: log})
+	return nil
+}
+
+func (l *WebhookLogger) LogEffect(ctx context.Context, log *OspreyEffectLog) error {
+	l.enqueue(webhookItem{effect: log})
+	return nil
+}
+
+// enqueue drops the log rather than holding up effects while the endpoint is backed up.
+func (l *WebhookLogger) enqueue(item webhookItem) {
+	select {
+	case l.queue <- item:
+	default:
+		webhookDropped.Inc()
+		l.logger.Warn("dropped webhook log because the queue is full")
+	}
+}
+
+func (l *WebhookLogger) run() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	var batch webhookBatch
+	flush := func() {
+		if len(batch.Events)+len(batch.Effects) == 0 {
+			return
+		}
+		l.post(&batch)
+		batch = webhookBatch{}
+	}
+
+	for {
+		select {
+		case item, ok := <-l.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if item.event != nil {
+				batch.Events = append(batch.Events, item.event)
+			}
+			if item.effect != nil {
+				batch.Effects = append(batch.Effects, item.effect)
+			}
+			if len(batch.Events)+len(batch.Effects) >= l.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// post sends the batch, retrying failed requests with backoff. Batches are dropped once they've
+// failed maxAttempts times.
+func (l *WebhookLogger) post(batch *webhookBatch) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		webhookBatches.WithLabelValues("error").Inc()
+		l.logger.Error("failed to marshal webhook batch", "error", err)
+		return
+	}
+
+	delay := time.Second
+	for attempt := 1; ; attempt++ {
+		retry, err := l.send(body)
+		if err == nil {
+			webhookBatches.WithLabelValues("ok").Inc()
+			return
+		}
+		if !retry || attempt >= l.maxAttempts {
+			webhookBatches.WithLabelValues("error").Inc()
+			l.logger.Error("failed to post webhook batch", "error", err, "attempts", attempt, "events", len(batch.Events), "effects", len(batch.Effects))
+			return
+		}
+
+		l.logger.Warn("failed to post webhook batch, retrying", "error", err, "attempt", attempt, "delay", delay)
+		time.Sleep(delay)
+		delay = min(delay*2, 30*time.Second)
+	}
+}
+
+// send posts the body once, and reports whether a failure is worth retrying.
+func (l *WebhookLogger) send(body []byte) (bool, error) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest("POST", l.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, ts)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+l.sign(ts, body))
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+}
+
+func (l *WebhookLogger) sign(ts string, body []byte) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Close flushes whatever is queued. Logs mustn't be written after it's called.
+func (l *WebhookLogger) Close() {
+	close(l.queue)
+	l.wg.Wait()
+}
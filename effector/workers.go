@@ -0,0 +1,177 @@
+package effector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	eventsQueued = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "events_queued",
+		Namespace: NAMESPACE,
+		Help:      "number of events waiting for or being handled by a worker",
+	})
+
+	eventsWaitingOnSubject = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "events_waiting_on_subject",
+		Namespace: NAMESPACE,
+		Help:      "number of events held back until an earlier event for the same subject has been handled",
+	})
+)
+
+var errPoolClosed = errors.New("worker pool closed")
+
+// workerPool runs jobs on a fixed number of workers. Jobs for the same subject run one at a time in
+// the order they were submitted, so that e.g. a reverse takedown can't race the takedown before it,
+// while jobs for other subjects carry on in parallel on the remaining workers. submit blocks while
+// the pool is full, which holds up the consumer instead of piling up Ozone calls.
+type workerPool struct {
+	ready chan *job
+	// slots bounds the jobs that are waiting or running.
+	slots chan struct{}
+
+	mu sync.Mutex
+	// subjects has an entry for each subject with a job waiting or running, holding the jobs
+	// submitted for it after that one.
+	subjects map[string][]*job
+
+	// inFlight is the number of jobs waiting for or being handled by a worker.
+	inFlight atomic.Int64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+type job struct {
+	subject string
+	run     func()
+	done    chan struct{}
+}
+
+// newWorkerPool starts the workers, with room for queueSize jobs per worker.
+func newWorkerPool(workers, queueSize int) *workerPool {
+	size := workers * queueSize
+	p := &workerPool{
+		ready:    make(chan *job, size),
+		slots:    make(chan struct{}, size),
+		subjects: map[string][]*job{},
+		quit:     make(chan struct{}),
+	}
+	for range workers {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// eventSubject is the key events are ordered by. Effects on an account's records and messages are
+// ordered along with the account's own, since they're looked up and reversed together.
+func eventSubject(evt *osprey.ResultEvent) string {
+	if evt.Did != "" {
+		return evt.Did
+	}
+	if evt.Uri != "" {
+		return evt.Uri
+	}
+	return MessageSubject(evt.Message)
+}
+
+// submit queues the job behind any others for the subject, blocking until there's room. The
+// returned channel is closed once the job has run.
+func (p *workerPool) submit(ctx context.Context, subject string, run func()) (<-chan struct{}, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-p.quit:
+		return nil, errPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// select picks at random when both are ready, so a slot may be taken after the pool closed.
+	select {
+	case <-p.quit:
+		<-p.slots
+		return nil, errPoolClosed
+	default:
+	}
+	eventsQueued.Inc()
+	p.inFlight.Add(1)
+
+	j := &job{subject: subject, run: run, done: make(chan struct{})}
+
+	p.mu.Lock()
+	waiting, busy := p.subjects[subject]
+	if busy {
+		p.subjects[subject] = append(waiting, j)
+		eventsWaitingOnSubject.Inc()
+	} else {
+		p.subjects[subject] = nil
+	}
+	p.mu.Unlock()
+
+	if !busy {
+		// Never blocks, since there's a slot for every job in ready.
+		p.ready <- j
+	}
+	return j.done, nil
+}
+
+func (p *workerPool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case j := <-p.ready:
+			p.runSubject(j)
+		case <-p.quit:
+			// Finish whatever was already queued before stopping.
+			for {
+				select {
+				case j := <-p.ready:
+					p.runSubject(j)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// runSubject runs the job and then the ones that were waiting on it for the same subject, on this
+// worker so that they keep their order.
+func (p *workerPool) runSubject(j *job) {
+	for j != nil {
+		p.run(j)
+
+		p.mu.Lock()
+		waiting := p.subjects[j.subject]
+		if len(waiting) == 0 {
+			delete(p.subjects, j.subject)
+			j = nil
+		} else {
+			p.subjects[j.subject] = waiting[1:]
+			j = waiting[0]
+			eventsWaitingOnSubject.Dec()
+		}
+		p.mu.Unlock()
+	}
+}
+
+func (p *workerPool) run(j *job) {
+	defer func() { <-p.slots }()
+	defer eventsQueued.Dec()
+	defer p.inFlight.Add(-1)
+	defer close(j.done)
+	j.run()
+}
+
+// close stops accepting jobs and waits for the queued ones to run.
+func (p *workerPool) close() {
+	close(p.quit)
+	p.wg.Wait()
+}
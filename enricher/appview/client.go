@@ -114,3 +114,76 @@ func (c *Client) GetProfile(ctx context.Context, did string) ([]byte, *bsky.Acto
 	status = "success"
 	return asBytes, profileView, nil
 }
+
+// GetList fetches a ListView from the Appview. Only the list itself is returned, not its items.
+func (c *Client) GetList(ctx context.Context, uri string) (*bsky.GraphDefs_ListView, error) {
+	ctx, span := tracer.Start(ctx, "AppviewClient.GetList")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("uri", uri))
+
+	if err := c.Limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed to wait on rate limiter: %w", err)
+	}
+	span.AddEvent("rate limit allowed")
+
+	start := time.Now()
+	status := "error"
+	defer func() {
+		duration := time.Since(start)
+		metrics.APIDuration.WithLabelValues(service, status).Observe(duration.Seconds())
+	}()
+
+	out, err := bsky.GraphGetList(ctx, c.xrpcc, "", 1, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+
+	if out == nil || out.List == nil {
+		return nil, fmt.Errorf("list not found (empty response)")
+	}
+
+	status = "success"
+	return out.List, nil
+}
+
+// GetListMembers pages through a list and returns the DIDs of all of its members.
+func (c *Client) GetListMembers(ctx context.Context, uri string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "AppviewClient.GetListMembers")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("uri", uri))
+
+	var members []string
+	cursor := ""
+	for {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("failed to wait on rate limiter: %w", err)
+		}
+
+		start := time.Now()
+		out, err := bsky.GraphGetList(ctx, c.xrpcc, cursor, 100, uri)
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.APIDuration.WithLabelValues(service, status).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list page: %w", err)
+		}
+
+		for _, item := range out.Items {
+			if item.Subject != nil {
+				members = append(members, item.Subject.Did)
+			}
+		}
+
+		if out.Cursor == nil || *out.Cursor == "" || len(out.Items) == 0 {
+			break
+		}
+		cursor = *out.Cursor
+	}
+
+	span.SetAttributes(attribute.Int("members", len(members)))
+	return members, nil
+}
@@ -0,0 +1,257 @@
+package campaign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/puzpuzpuz/xsync/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// bucketsPerWindow is how many time buckets sightings are counted in. Counts are accurate to
+// within one bucket, so a 24h window counts to the hour.
+const bucketsPerWindow = 24
+
+// maxDidsPerHash caps how many distinct DIDs are remembered for a single hash. An image posted by
+// more accounts than this is a campaign either way, so we stop tracking new ones rather than
+// letting one hash grow without bound.
+const maxDidsPerHash = 10_000
+
+// Index keeps a rolling window of recently seen image hashes in memory, with how many times and by
+// how many DIDs each was seen, optionally snapshotting it to disk so that a restart doesn't forget
+// a campaign that's in progress.
+type Index struct {
+	logger           *slog.Logger
+	hashes           *xsync.MapOf[string, *hashState]
+	window           time.Duration
+	bucketSize       time.Duration
+	snapshotPath     string
+	snapshotInterval time.Duration
+}
+
+type IndexArgs struct {
+	Logger *slog.Logger
+	// Window is how far back sightings are counted. Defaults to 24 hours.
+	Window time.Duration
+	// SnapshotPath is the file state is periodically written to and restored from. Optional.
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+}
+
+type hashState struct {
+	mu        sync.Mutex
+	FirstSeen time.Time            `json:"first_seen"`
+	Buckets   []bucket             `json:"buckets"`
+	Dids      map[string]time.Time `json:"dids"` // DID to when it last posted the image
+}
+
+type bucket struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+func NewIndex(args *IndexArgs) (*Index, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	if args.Window <= 0 {
+		args.Window = 24 * time.Hour
+	}
+	if args.SnapshotInterval == 0 {
+		args.SnapshotInterval = time.Minute
+	}
+
+	idx := &Index{
+		logger:           args.Logger,
+		hashes:           xsync.NewMapOf[string, *hashState](),
+		window:           args.Window,
+		bucketSize:       args.Window / bucketsPerWindow,
+		snapshotPath:     args.SnapshotPath,
+		snapshotInterval: args.SnapshotInterval,
+	}
+
+	if idx.snapshotPath != "" {
+		if err := idx.loadSnapshot(); err != nil {
+			return nil, fmt.Errorf("failed to load campaign snapshot: %w", err)
+		}
+	}
+
+	return idx, nil
+}
+
+// Observe records a sighting of the image hash posted by the given DID and returns the sightings
+// of that hash within the window, including this one.
+func (idx *Index) Observe(hash, did string, now time.Time) *osprey.ImageDispatchResults_Sightings {
+	state, _ := idx.hashes.LoadOrCompute(hash, func() *hashState {
+		return &hashState{FirstSeen: now, Dids: map[string]time.Time{}}
+	})
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.prune(now.Add(-idx.window))
+
+	start := now.Truncate(idx.bucketSize)
+	if n := len(state.Buckets); n > 0 && state.Buckets[n-1].Start.Equal(start) {
+		state.Buckets[n-1].Count++
+	} else {
+		state.Buckets = append(state.Buckets, bucket{Start: start, Count: 1})
+	}
+	if _, ok := state.Dids[did]; ok || len(state.Dids) < maxDidsPerHash {
+		state.Dids[did] = now
+	}
+
+	sightings := &osprey.ImageDispatchResults_Sightings{
+		DistinctDids:  int64(len(state.Dids)),
+		FirstSeen:     timestamppb.New(state.FirstSeen),
+		WindowSeconds: int64(idx.window.Seconds()),
+	}
+	for _, b := range state.Buckets {
+		sightings.Count += b.Count
+	}
+	return sightings
+}
+
+// prune drops the buckets and DIDs last seen before cutoff. FirstSeen is kept as long as the hash
+// is still being seen, so a long running campaign reports when it started.
+func (s *hashState) prune(cutoff time.Time) {
+	i := 0
+	for i < len(s.Buckets) && s.Buckets[i].Start.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		s.Buckets = append(s.Buckets[:0], s.Buckets[i:]...)
+	}
+	for did, at := range s.Dids {
+		if at.Before(cutoff) {
+			delete(s.Dids, did)
+		}
+	}
+}
+
+func (s *hashState) empty() bool {
+	return len(s.Buckets) == 0
+}
+
+// Run periodically evicts hashes that haven't been seen within the window and writes snapshots
+// until the context is cancelled, at which point a final snapshot is written.
+func (idx *Index) Run(ctx context.Context) {
+	ticker := time.NewTicker(idx.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := idx.writeSnapshot(); err != nil {
+				idx.logger.Error("failed to write final campaign snapshot", "err", err)
+			}
+			return
+		case <-ticker.C:
+			idx.evictIdle(time.Now())
+			if err := idx.writeSnapshot(); err != nil {
+				idx.logger.Error("failed to write campaign snapshot", "err", err)
+			}
+		}
+	}
+}
+
+func (idx *Index) evictIdle(now time.Time) {
+	cutoff := now.Add(-idx.window)
+	idx.hashes.Range(func(hash string, state *hashState) bool {
+		state.mu.Lock()
+		state.prune(cutoff)
+		empty := state.empty()
+		state.mu.Unlock()
+		if empty {
+			idx.hashes.Delete(hash)
+		}
+		return true
+	})
+}
+
+func (idx *Index) writeSnapshot() error {
+	if idx.snapshotPath == "" {
+		return nil
+	}
+
+	snapshot := make(map[string]*hashState, idx.hashes.Size())
+	idx.hashes.Range(func(hash string, state *hashState) bool {
+		state.mu.Lock()
+		dids := make(map[string]time.Time, len(state.Dids))
+		for did, at := range state.Dids {
+			dids[did] = at
+		}
+		snapshot[hash] = &hashState{
+			FirstSeen: state.FirstSeen,
+			Buckets:   append([]bucket(nil), state.Buckets...),
+			Dids:      dids,
+		}
+		state.mu.Unlock()
+		return true
+	})
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// Write to a temp file and rename so we never leave a partial snapshot behind.
+	tmp, err := os.CreateTemp(filepath.Dir(idx.snapshotPath), filepath.Base(idx.snapshotPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), idx.snapshotPath); err != nil {
+		return fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+
+	idx.logger.Debug("wrote campaign snapshot", "hashes", len(snapshot))
+	return nil
+}
+
+func (idx *Index) loadSnapshot() error {
+	b, err := os.ReadFile(idx.snapshotPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			idx.logger.Info("no campaign snapshot found, starting empty", "path", idx.snapshotPath)
+			return nil
+		}
+		return err
+	}
+
+	snapshot := map[string]*hashState{}
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	cutoff := time.Now().Add(-idx.window)
+	for hash, state := range snapshot {
+		if state.Dids == nil {
+			state.Dids = map[string]time.Time{}
+		}
+		state.prune(cutoff)
+		if !state.empty() {
+			idx.hashes.Store(hash, state)
+		}
+	}
+
+	idx.logger.Info("loaded campaign snapshot", "path", idx.snapshotPath, "hashes", idx.hashes.Size())
+	return nil
+}
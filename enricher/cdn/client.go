@@ -2,9 +2,12 @@ package cdn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/bluesky-social/go-util/pkg/robusthttp"
@@ -18,19 +21,31 @@ import (
 
 const service = "cdn"
 
+// sourcePDS is the metrics source label for blobs fetched from the owning PDS.
+const sourcePDS = "pds"
+
 var tracer = otel.Tracer(service)
 
+// PDSResolver returns the PDS endpoint hosting the given DID's repo.
+type PDSResolver func(ctx context.Context, did string) (string, error)
+
 type Client struct {
-	client  *http.Client
-	host    string
-	limiter *rate.Limiter
-	cache   *lru.LRU[string, []byte]
+	client      *http.Client
+	hosts       []string
+	pdsResolver PDSResolver
+	limiter     *rate.Limiter
+	pdsLimiter  *rate.Limiter
+	cache       *lru.LRU[string, []byte]
 }
 
 type ClientArgs struct {
-	Host      string
-	CacheSize int
-	CacheTTL  time.Duration
+	// Hosts are the CDN hosts to fetch images from, tried in order.
+	Hosts []string
+	// PDSResolver is used to fall back to fetching the blob from the owning PDS when all of the CDN
+	// hosts fail. Optional.
+	PDSResolver PDSResolver
+	CacheSize   int
+	CacheTTL    time.Duration
 }
 
 func NewClient(args *ClientArgs) *Client {
@@ -42,14 +57,18 @@ func NewClient(args *ClientArgs) *Client {
 	c := robusthttp.NewClient()
 
 	return &Client{
-		host:    args.Host,
-		client:  c,
-		limiter: rate.NewLimiter(100, 50),
-		cache:   cache,
+		hosts:       args.Hosts,
+		pdsResolver: args.PDSResolver,
+		client:      c,
+		limiter:     rate.NewLimiter(100, 50),
+		pdsLimiter:  rate.NewLimiter(20, 10),
+		cache:       cache,
 	}
 }
 
-// Scan sends an image to the ToxicCheck service for OCR and returns the recognized text.
+// GetImageBytes fetches a JPEG thumbnail of the image from the first CDN host that returns it. If
+// every host fails, the original blob is fetched from the owning PDS instead, when a PDS resolver
+// is configured.
 func (c *Client) GetImageBytes(ctx context.Context, did, cid string) ([]byte, error) {
 	ctx, span := tracer.Start(ctx, "Cdn.GetImageBytes")
 	defer span.End()
@@ -60,9 +79,10 @@ func (c *Client) GetImageBytes(ctx context.Context, did, cid string) ([]byte, er
 	)
 
 	cacheKey := fmt.Sprintf("%s/%s", did, cid)
-	cached, ok := c.cache.Get(cacheKey)
-	if ok {
-		return cached, nil
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(cacheKey); ok {
+			return cached, nil
+		}
 	}
 
 	if err := c.limiter.Wait(ctx); err != nil {
@@ -70,19 +90,119 @@ func (c *Client) GetImageBytes(ctx context.Context, did, cid string) ([]byte, er
 	}
 	span.AddEvent("rate limit allowed")
 
-	ustr := fmt.Sprintf("%s/img/feed_thumbnail/plain/%s/%s@jpeg", c.host, did, cid)
+	var errs []error
+	for _, host := range c.hosts {
+		ustr := fmt.Sprintf("%s/img/feed_thumbnail/plain/%s/%s@jpeg", host, did, cid)
+		respBytes, err := c.fetch(ctx, sourceLabel(host), ustr, 0)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+			continue
+		}
+
+		if c.cache != nil {
+			c.cache.Add(cacheKey, respBytes)
+		}
+		return respBytes, nil
+	}
+
+	if c.pdsResolver == nil {
+		return nil, errors.Join(errs...)
+	}
+
+	span.AddEvent("falling back to PDS")
+	respBytes, err := c.GetBlob(ctx, did, cid)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", sourcePDS, err))
+		return nil, errors.Join(errs...)
+	}
+
+	if c.cache != nil {
+		c.cache.Add(cacheKey, respBytes)
+	}
+	return respBytes, nil
+}
+
+// GetBlob fetches the original, unprocessed blob from the owning PDS.
+func (c *Client) GetBlob(ctx context.Context, did, cid string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "Cdn.GetBlob")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("did", did),
+		attribute.String("cid", cid),
+	)
+
+	ustr, err := c.blobURL(ctx, did, cid)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetch(ctx, sourcePDS, ustr, 0)
+}
+
+// SniffLen is the number of leading bytes GetBlobHead returns, which is all that content type
+// sniffing looks at.
+const SniffLen = 512
+
+// GetBlobHead fetches the first SniffLen bytes of the original blob from the owning PDS, so its
+// actual content type can be sniffed without downloading the whole thing.
+func (c *Client) GetBlobHead(ctx context.Context, did, cid string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "Cdn.GetBlobHead")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("did", did),
+		attribute.String("cid", cid),
+	)
+
+	ustr, err := c.blobURL(ctx, did, cid)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetch(ctx, sourcePDS, ustr, SniffLen)
+}
+
+// blobURL resolves the owning PDS and returns the getBlob URL for the blob, once the PDS rate
+// limiter allows it.
+func (c *Client) blobURL(ctx context.Context, did, cid string) (string, error) {
+	if c.pdsResolver == nil {
+		return "", fmt.Errorf("no PDS resolver configured")
+	}
+
+	pdsHost, err := c.pdsResolver(ctx, did)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve PDS: %w", err)
+	}
+
+	if err := c.pdsLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("failed to wait on rate limiter: %w", err)
+	}
+
+	q := url.Values{}
+	q.Set("did", did)
+	q.Set("cid", cid)
+	return fmt.Sprintf("%s/xrpc/com.atproto.sync.getBlob?%s", strings.TrimSuffix(pdsHost, "/"), q.Encode()), nil
+}
+
+// fetch GETs the URL and returns the response body. If maxBytes is positive, only that many
+// leading bytes are requested and read.
+func (c *Client) fetch(ctx context.Context, source, ustr string, maxBytes int64) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", ustr, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("User-Agent", "tango-enricher/"+versioninfo.Short())
+	if maxBytes > 0 {
+		// Not every PDS honors range requests, so the body is capped below as well.
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", maxBytes-1))
+	}
 
 	start := time.Now()
 	status := "error"
 	defer func() {
 		duration := time.Since(start)
 		metrics.APIDuration.WithLabelValues(service, status).Observe(duration.Seconds())
+		metrics.ImageFetches.WithLabelValues(source, status).Inc()
 	}()
 
 	res, err := c.client.Do(req)
@@ -90,17 +210,30 @@ func (c *Client) GetImageBytes(ctx context.Context, did, cid string) ([]byte, er
 		return nil, fmt.Errorf("request failed: %v", err)
 	}
 	defer res.Body.Close()
-	respBytes, bodyReadErr := io.ReadAll(res.Body)
+	var body io.Reader = res.Body
+	if maxBytes > 0 {
+		body = io.LimitReader(res.Body, maxBytes)
+	}
+	respBytes, bodyReadErr := io.ReadAll(body)
 
-	if res.StatusCode != 200 {
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		if res.StatusCode == http.StatusNotFound {
+			status = "not_found"
+		}
 		return nil, fmt.Errorf("request failed statusCode=%d", res.StatusCode)
 	}
 	if bodyReadErr != nil {
 		return nil, fmt.Errorf("failed to read resp body: %v", bodyReadErr)
 	}
 
-	c.cache.Add(cacheKey, respBytes)
-
 	status = "ok"
 	return respBytes, nil
 }
+
+// sourceLabel returns the hostname of a CDN host URL for use as a metrics label.
+func sourceLabel(host string) string {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return host
+}
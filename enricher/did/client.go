@@ -8,6 +8,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/bluesky-social/go-util/pkg/robusthttp"
@@ -229,3 +230,57 @@ func (c *Client) GetDIDAuditLog(ctx context.Context, did string) ([]byte, *Audit
 
 	return bytes, &auditLog, nil
 }
+
+// IdentityChurn is derived from the audit log so rules don't have to parse the raw log.
+type IdentityChurn struct {
+	CreatedAt            time.Time
+	Operations           int
+	HandleChangesLast30d int
+	PDSMigrations        int
+	RotationKeyChanges   int
+}
+
+// Churn computes account age and identity change counts from the audit log. Nullified operations
+// are ignored. A handle change, PDS migration or rotation key change is counted whenever an
+// operation differs from the one before it.
+func (l *AuditLog) Churn(now time.Time) *IdentityChurn {
+	churn := &IdentityChurn{}
+	monthAgo := now.AddDate(0, 0, -30)
+
+	var prev *DidLogEntry
+	for i := range l.Entries {
+		entry := &l.Entries[i]
+		if entry.Nullified {
+			continue
+		}
+
+		createdAt, err := syntax.ParseDatetimeLenient(entry.CreatedAt)
+		if err != nil {
+			continue
+		}
+		at := createdAt.Time()
+
+		churn.Operations++
+		if churn.CreatedAt.IsZero() {
+			churn.CreatedAt = at
+		}
+
+		// Legacy "create" operations use a different shape, so we only compare operations of the
+		// same type.
+		op := &entry.Operation
+		if prev != nil && prev.Type == op.Type {
+			if !slices.Equal(prev.AlsoKnownAs, op.AlsoKnownAs) && at.After(monthAgo) {
+				churn.HandleChangesLast30d++
+			}
+			if prev.Services["atproto_pds"].Endpoint != op.Services["atproto_pds"].Endpoint {
+				churn.PDSMigrations++
+			}
+			if !slices.Equal(prev.RotationKeys, op.RotationKeys) {
+				churn.RotationKeyChanges++
+			}
+		}
+		prev = op
+	}
+
+	return churn
+}
@@ -0,0 +1,240 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"google.golang.org/protobuf/proto"
+)
+
+// Topic returns the name of the dead letter queue topic the Bus consumer creates for the given
+// input topic and consumer group.
+func Topic(inputTopic, consumerGroup string) string {
+	return fmt.Sprintf("%s-%s-dlq", inputTopic, consumerGroup)
+}
+
+// Entry is a single record from the dead letter queue. The Bus consumer forwards the original
+// message unchanged, so the handler error that put it there isn't recorded.
+type Entry struct {
+	Partition int32
+	Offset    int64
+	Timestamp time.Time
+	Key       string
+	Value     []byte
+	// Event is nil if the record couldn't be decoded, in which case DecodeErr is set.
+	Event     *osprey.FirehoseEvent
+	DecodeErr error
+}
+
+// Client reads from a dead letter queue topic and requeues entries to the input topic. It reads
+// partitions directly rather than joining a consumer group, so inspecting the queue never moves
+// any committed offsets.
+type Client struct {
+	opts       []kgo.Opt
+	admin      *kgo.Client
+	topic      string
+	inputTopic string
+}
+
+type ClientArgs struct {
+	BootstrapServers []string
+	SASLUsername     string
+	SASLPassword     string
+	InputTopic       string
+	ConsumerGroup    string
+}
+
+func NewClient(args *ClientArgs) (*Client, error) {
+	if len(args.BootstrapServers) == 0 {
+		return nil, fmt.Errorf("at least one bootstrap server must be provided")
+	}
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(args.BootstrapServers...),
+		kgo.ClientID("enricher-dlq"),
+	}
+	if args.SASLUsername != "" && args.SASLPassword != "" {
+		opts = append(opts, kgo.SASL(plain.Auth{
+			User: args.SASLUsername,
+			Pass: args.SASLPassword,
+		}.AsMechanism()))
+	}
+
+	admin, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+
+	return &Client{
+		opts:       opts,
+		admin:      admin,
+		topic:      Topic(args.InputTopic, args.ConsumerGroup),
+		inputTopic: args.InputTopic,
+	}, nil
+}
+
+func (c *Client) Topic() string {
+	return c.topic
+}
+
+func (c *Client) Close() {
+	c.admin.Close()
+}
+
+// List returns up to limit entries currently in the queue, oldest first within each partition.
+// A limit of 0 returns everything.
+func (c *Client) List(ctx context.Context, limit int) ([]*Entry, error) {
+	adm := kadm.NewClient(c.admin)
+
+	starts, err := adm.ListStartOffsets(ctx, c.topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list start offsets: %w", err)
+	}
+	ends, err := adm.ListEndOffsets(ctx, c.topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list end offsets: %w", err)
+	}
+
+	// Read each partition from its start offset up to the end offset as of now.
+	partitions := map[int32]kgo.Offset{}
+	remaining := map[int32]int64{}
+	var listErr error
+	starts.Each(func(o kadm.ListedOffset) {
+		if o.Err != nil {
+			listErr = errors.Join(listErr, fmt.Errorf("partition %d: %w", o.Partition, o.Err))
+			return
+		}
+		end, ok := ends.Lookup(c.topic, o.Partition)
+		if !ok || end.Err != nil || end.Offset <= o.Offset {
+			return
+		}
+		partitions[o.Partition] = kgo.NewOffset().At(o.Offset)
+		remaining[o.Partition] = end.Offset
+	})
+	if listErr != nil {
+		return nil, fmt.Errorf("failed to list offsets: %w", listErr)
+	}
+	if len(partitions) == 0 {
+		return nil, nil
+	}
+
+	entries, err := c.read(ctx, partitions, func(e *Entry) bool {
+		if e.Offset >= remaining[e.Partition]-1 {
+			delete(remaining, e.Partition)
+		}
+		return len(remaining) > 0
+	}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Partition != entries[j].Partition {
+			return entries[i].Partition < entries[j].Partition
+		}
+		return entries[i].Offset < entries[j].Offset
+	})
+	return entries, nil
+}
+
+// Get returns the entry at the given partition and offset.
+func (c *Client) Get(ctx context.Context, partition int32, offset int64) (*Entry, error) {
+	entries, err := c.read(ctx, map[int32]kgo.Offset{partition: kgo.NewOffset().At(offset)}, func(*Entry) bool {
+		return false
+	}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 || entries[0].Offset != offset {
+		return nil, fmt.Errorf("no entry at partition %d offset %d", partition, offset)
+	}
+	return entries[0], nil
+}
+
+// Requeue produces the entries back onto the input topic with their original keys.
+func (c *Client) Requeue(ctx context.Context, entries []*Entry) error {
+	recs := make([]*kgo.Record, 0, len(entries))
+	for _, e := range entries {
+		recs = append(recs, &kgo.Record{
+			Topic: c.inputTopic,
+			Key:   []byte(e.Key),
+			Value: e.Value,
+		})
+	}
+	if err := c.admin.ProduceSync(ctx, recs...).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce to %s: %w", c.inputTopic, err)
+	}
+	return nil
+}
+
+// idleTimeout is how long read waits for more records before assuming there are none left.
+const idleTimeout = 10 * time.Second
+
+// read consumes the given partitions until more returns false, limit entries have been read, no
+// records arrive for idleTimeout, or the context is done.
+func (c *Client) read(ctx context.Context, partitions map[int32]kgo.Offset, more func(*Entry) bool, limit int) ([]*Entry, error) {
+	reader, err := kgo.NewClient(append(slices.Clone(c.opts),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{c.topic: partitions}),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+	)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka reader: %w", err)
+	}
+	defer reader.Close()
+
+	var entries []*Entry
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+		fetches := reader.PollFetches(pollCtx)
+		cancel()
+		if err := ctx.Err(); err != nil {
+			return entries, err
+		}
+		if errors.Is(pollCtx.Err(), context.DeadlineExceeded) {
+			return entries, nil
+		}
+		if errs := fetches.Errors(); len(errs) > 0 {
+			return entries, fmt.Errorf("failed to fetch records: %v", errs)
+		}
+
+		done := false
+		fetches.EachRecord(func(r *kgo.Record) {
+			if done {
+				return
+			}
+			e := decode(r)
+			entries = append(entries, e)
+			if !more(e) || (limit > 0 && len(entries) >= limit) {
+				done = true
+			}
+		})
+		if done {
+			return entries, nil
+		}
+	}
+}
+
+func decode(r *kgo.Record) *Entry {
+	e := &Entry{
+		Partition: r.Partition,
+		Offset:    r.Offset,
+		Timestamp: r.Timestamp,
+		Key:       string(r.Key),
+		Value:     r.Value,
+	}
+	var evt osprey.FirehoseEvent
+	if err := proto.Unmarshal(r.Value, &evt); err != nil {
+		e.DecodeErr = err
+	} else {
+		e.Event = &evt
+	}
+	return e
+}
@@ -0,0 +1,73 @@
+package hive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestScanSendsOnlyImageBytes pins what leaves for Hive to the image itself, since Hive is a third
+// party and record fields and text must never be sent to it.
+func TestScanSendsOnlyImageBytes(t *testing.T) {
+	image := []byte("\x89PNG\r\n\x1a\nnot really a png")
+
+	var parts []string
+	var media []byte
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Errorf("failed to parse content type: %v", err)
+			return
+		}
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Errorf("failed to read part: %v", err)
+				return
+			}
+			parts = append(parts, p.FormName())
+			b, _ := io.ReadAll(p)
+			if p.FormName() == "media" {
+				media = b
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":[{"response":{"output":[{"classes":[{"class":"yes_sexual","score":0.9}]}]}}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("token")
+	c.scanEndpoint = srv.URL
+
+	_, classes, err := c.Scan(context.Background(), image)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if classes["yes_sexual"] != 0.9 {
+		t.Errorf("unexpected classes: %v", classes)
+	}
+
+	if len(parts) != 1 || parts[0] != "media" {
+		t.Errorf("expected only the media part to be sent, got %v", parts)
+	}
+	if !bytes.Equal(media, image) {
+		t.Errorf("expected the media part to be the image bytes, got %q", media)
+	}
+	if query != "" {
+		t.Errorf("expected no query parameters, got %q", query)
+	}
+}
@@ -0,0 +1,208 @@
+package impersonation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"golang.org/x/text/unicode/norm"
+)
+
+const service = "impersonation"
+
+// Fields that are compared against protected accounts.
+const (
+	FieldHandle      = "handle"
+	FieldDisplayName = "display_name"
+)
+
+// ProtectedAccount is an account we want to detect impersonation of.
+type ProtectedAccount struct {
+	Did         string `json:"did"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"display_name,omitempty"`
+
+	normHandle      string
+	normDisplayName string
+}
+
+// Match is a protected account that the checked identity is similar to.
+type Match struct {
+	ProtectedDid    string
+	ProtectedHandle string
+	Field           string
+	Score           float64
+}
+
+type Detector struct {
+	accounts []*ProtectedAccount
+	minScore float64
+	cache    *expirable.LRU[string, []*Match]
+}
+
+type DetectorArgs struct {
+	// AccountsPath is a JSON file containing a list of ProtectedAccounts.
+	AccountsPath string
+	// MinScore is the minimum similarity, from 0 to 1, to report a match.
+	MinScore  float64
+	CacheSize int
+	CacheTTL  time.Duration
+}
+
+func NewDetector(args *DetectorArgs) (*Detector, error) {
+	b, err := os.ReadFile(args.AccountsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protected accounts: %w", err)
+	}
+
+	var accounts []*ProtectedAccount
+	if err := json.Unmarshal(b, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal protected accounts: %w", err)
+	}
+
+	for _, acct := range accounts {
+		acct.normHandle = normalizeHandle(acct.Handle)
+		acct.normDisplayName = normalize(acct.DisplayName)
+	}
+
+	if args.MinScore == 0 {
+		args.MinScore = 0.85
+	}
+	if args.CacheSize == 0 {
+		args.CacheSize = 100_000
+	}
+	if args.CacheTTL == 0 {
+		args.CacheTTL = time.Hour
+	}
+
+	d := &Detector{
+		accounts: accounts,
+		minScore: args.MinScore,
+	}
+	d.cache = expirable.NewLRU(args.CacheSize, func(key string, value []*Match) {
+		metrics.CacheSize.WithLabelValues(service).Set(float64(d.cache.Len()))
+	}, args.CacheTTL)
+
+	return d, nil
+}
+
+// Check compares the handle and display name of an account against all protected accounts. The
+// protected account itself is never reported. Results are cached per identity, since the same
+// account will usually be checked for every record it creates.
+func (d *Detector) Check(did, handle, displayName string) []*Match {
+	key := did + "|" + handle + "|" + displayName
+	if matches, ok := d.cache.Get(key); ok {
+		metrics.CacheResults.WithLabelValues(service, "hit").Inc()
+		return matches
+	}
+	metrics.CacheResults.WithLabelValues(service, "miss").Inc()
+
+	normHandle := normalizeHandle(handle)
+	normDisplayName := normalize(displayName)
+
+	var matches []*Match
+	for _, acct := range d.accounts {
+		if acct.Did == did {
+			continue
+		}
+
+		if normHandle != "" && acct.normHandle != "" {
+			if score := similarity(normHandle, acct.normHandle); score >= d.minScore {
+				matches = append(matches, &Match{ProtectedDid: acct.Did, ProtectedHandle: acct.Handle, Field: FieldHandle, Score: score})
+			}
+		}
+
+		// Display names are compared against both the protected display name and handle, since
+		// impersonators often put the target's handle in their display name.
+		if normDisplayName != "" {
+			score := 0.0
+			if acct.normDisplayName != "" {
+				score = similarity(normDisplayName, acct.normDisplayName)
+			}
+			if acct.normHandle != "" {
+				score = max(score, similarity(normDisplayName, acct.normHandle))
+			}
+			if score >= d.minScore {
+				matches = append(matches, &Match{ProtectedDid: acct.Did, ProtectedHandle: acct.Handle, Field: FieldDisplayName, Score: score})
+			}
+		}
+	}
+
+	d.cache.Add(key, matches)
+	metrics.CacheSize.WithLabelValues(service).Set(float64(d.cache.Len()))
+
+	return matches
+}
+
+// homoglyphs maps characters commonly used to spoof latin letters to the letter they imitate.
+var homoglyphs = map[rune]rune{
+	'0': 'o', '1': 'l', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '|': 'l', '!': 'i',
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'с': 'c', 'т': 't',
+	'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't',
+	'υ': 'u', 'χ': 'x',
+}
+
+// multiGlyphs are letter sequences that render similarly to a single letter.
+var multiGlyphs = strings.NewReplacer("rn", "m", "vv", "w", "cl", "d")
+
+// normalize folds compatibility characters, strips diacritics, maps homoglyphs and drops anything
+// that isn't a letter or digit.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if mapped, ok := homoglyphs[r]; ok {
+			r = mapped
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return multiGlyphs.Replace(b.String())
+}
+
+// normalizeHandle drops the default bsky.social suffix before normalizing, so that e.g.
+// "jay-bsky-team.bsky.social" is compared as "jaybskyteam".
+func normalizeHandle(handle string) string {
+	return normalize(strings.TrimSuffix(strings.ToLower(handle), ".bsky.social"))
+}
+
+// similarity returns 1 minus the edit distance between a and b, scaled by the longer length.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
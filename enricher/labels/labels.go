@@ -0,0 +1,198 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/events"
+	"github.com/bluesky-social/indigo/events/schedulers/sequential"
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	"github.com/gorilla/websocket"
+	"github.com/puzpuzpuz/xsync/v3"
+)
+
+const service = "labels"
+
+// Label is a label currently applied to a subject.
+type Label struct {
+	Uri       string
+	Val       string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+// Cache follows a labeler's label stream and keeps the current set of labels for every subject it
+// has labeled, so enriched events can say what a subject has already been labeled with.
+type Cache struct {
+	logger *slog.Logger
+	host   string
+
+	// labels maps a subject (a DID or record AT-URI) to its current labels, keyed by value.
+	labels *xsync.MapOf[string, map[string]*Label]
+	cursor atomic.Int64
+}
+
+type CacheArgs struct {
+	Logger *slog.Logger
+	// LabelerHost is the labeler service to subscribe to, e.g. https://mod.bsky.app
+	LabelerHost string
+}
+
+func NewCache(args *CacheArgs) (*Cache, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+
+	if _, err := url.Parse(args.LabelerHost); err != nil {
+		return nil, fmt.Errorf("invalid labeler host: %w", err)
+	}
+
+	return &Cache{
+		logger: args.Logger,
+		host:   args.LabelerHost,
+		labels: xsync.NewMapOf[string, map[string]*Label](),
+	}, nil
+}
+
+// Lookup returns the unexpired labels currently applied to any of the given subjects.
+func (c *Cache) Lookup(now time.Time, subjects ...string) []*Label {
+	var out []*Label
+	for _, subject := range subjects {
+		labels, ok := c.labels.Load(subject)
+		if !ok {
+			continue
+		}
+		for _, label := range labels {
+			if label.ExpiresAt != nil && label.ExpiresAt.Before(now) {
+				continue
+			}
+			out = append(out, label)
+		}
+	}
+	return out
+}
+
+// Run subscribes to the label stream until the context is cancelled, reconnecting from the last
+// seen sequence number when the connection drops. The first connection replays the labeler's full
+// history to build up the current state.
+func (c *Cache) Run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := c.subscribe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		c.logger.Error("label stream disconnected, reconnecting", "err", err, "cursor", c.cursor.Load(), "backoff", backoff)
+
+		// Reset the backoff if we managed to stay connected for a while.
+		if time.Since(start) > time.Minute {
+			backoff = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+}
+
+func (c *Cache) subscribe(ctx context.Context) error {
+	u, err := url.Parse(c.host)
+	if err != nil {
+		return fmt.Errorf("failed to parse labeler host: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	u.Path = "/xrpc/com.atproto.label.subscribeLabels"
+	q := u.Query()
+	q.Set("cursor", strconv.FormatInt(c.cursor.Load(), 10))
+	u.RawQuery = q.Encode()
+
+	c.logger.Info("connecting to label stream", "url", u.String())
+	con, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to label stream: %w", err)
+	}
+	defer con.Close()
+
+	// HandleRepoStream doesn't watch the context once it's reading, so close the connection
+	// ourselves on shutdown.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			con.Close()
+		case <-done:
+		}
+	}()
+
+	scheduler := sequential.NewScheduler("enricher_labels", c.handleStreamEvent)
+	return events.HandleRepoStream(ctx, con, scheduler, c.logger)
+}
+
+func (c *Cache) handleStreamEvent(ctx context.Context, xe *events.XRPCStreamEvent) error {
+	switch {
+	case xe.LabelLabels != nil:
+		c.applyLabels(xe.LabelLabels)
+	case xe.LabelInfo != nil:
+		c.logger.Info("label stream info", "name", xe.LabelInfo.Name, "message", xe.LabelInfo.Message)
+	case xe.Error != nil:
+		return fmt.Errorf("label stream error: %s", xe.Error.Message)
+	}
+	return nil
+}
+
+func (c *Cache) applyLabels(evt *comatproto.LabelSubscribeLabels_Labels) {
+	for _, l := range evt.Labels {
+		createdAt, err := syntax.ParseDatetimeLenient(l.Cts)
+		if err != nil {
+			c.logger.Warn("skipping label with invalid cts", "uri", l.Uri, "val", l.Val, "cts", l.Cts)
+			continue
+		}
+
+		label := &Label{
+			Uri:       l.Uri,
+			Val:       l.Val,
+			CreatedAt: createdAt.Time(),
+		}
+		if l.Exp != nil {
+			if exp, err := syntax.ParseDatetimeLenient(*l.Exp); err == nil {
+				expiresAt := exp.Time()
+				label.ExpiresAt = &expiresAt
+			}
+		}
+		negated := l.Neg != nil && *l.Neg
+
+		// Copy on write, so that readers never see a map that's being modified.
+		c.labels.Compute(l.Uri, func(current map[string]*Label, loaded bool) (map[string]*Label, bool) {
+			next := make(map[string]*Label, len(current)+1)
+			for val, existing := range current {
+				next[val] = existing
+			}
+			if negated {
+				delete(next, l.Val)
+			} else {
+				next[l.Val] = label
+			}
+			return next, len(next) == 0
+		})
+	}
+
+	c.cursor.Store(evt.Seq)
+	metrics.CacheSize.WithLabelValues(service).Set(float64(c.labels.Size()))
+}
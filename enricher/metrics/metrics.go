@@ -19,3 +19,96 @@ var CacheSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
 	Name: "enricher_api_cache_size",
 	Help: "Current size of the cache",
 }, []string{"service"})
+
+var OversizedItems = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_oversized_items",
+	Help: "Images and output events that exceeded their size limits, by what was done about it",
+}, []string{"kind", "action"})
+
+var ImagesPerEvent = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "enricher_images_per_event",
+	Help:    "Number of images in each enriched record, by record collection",
+	Buckets: []float64{0, 1, 2, 3, 4, 6, 8, 12, 16},
+}, []string{"collection"})
+
+var BlobSizeBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "enricher_blob_size_bytes",
+	Help:    "Size of each blob referenced by an enriched record as given in the record, by media kind",
+	Buckets: prometheus.ExponentialBuckets(16<<10, 2, 14), // 16 KiB to 128 MiB
+}, []string{"kind"})
+
+var OutputEventSizeBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "enricher_output_event_size_bytes",
+	Help:    "Size of each produced output event, by record collection",
+	Buckets: prometheus.ExponentialBuckets(1<<10, 2, 13), // 1 KiB to 4 MiB
+}, []string{"collection"})
+
+var OutputEventsNearLimit = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_output_events_near_limit",
+	Help: "Produced output events within 20% of the output topic's max message size, by record collection",
+}, []string{"collection"})
+
+var BlobTypeMismatches = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_blob_type_mismatches",
+	Help: "Blobs whose sniffed content is a different kind of media than their declared mimetype",
+}, []string{"declared", "sniffed"})
+
+var PaidAPICalls = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_paid_api_calls",
+	Help: "Calls made to paid third-party APIs by record collection and outcome",
+}, []string{"service", "collection", "outcome"})
+
+var PaidAPIEstimatedCost = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "enricher_paid_api_estimated_cost",
+	Help: "Estimated spend on paid third-party APIs by record collection, based on the configured per-call prices",
+}, []string{"service", "collection"})
+
+var PaidAPIBudgetRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "enricher_paid_api_budget_remaining",
+	Help: "Calls that can be made to a budgeted paid API right now before its budget policy kicks in",
+}, []string{"service"})
+
+var PaidAPIBudgetExhausted = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_paid_api_budget_exhausted",
+	Help: "Calls to a budgeted paid API made while its budget was exhausted, by whether the budget policy still let them through (queue, sample) or skipped them",
+}, []string{"service", "outcome"})
+
+var PrescreenFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_prescreen_failures",
+	Help: "Images whose prescreen call failed, by whether the failure policy sent them to Hive or skipped them",
+}, []string{"outcome"})
+
+var ReportWebhooks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_report_webhooks",
+	Help: "Report intake webhooks received, by status",
+}, []string{"status"})
+
+var ImageFetches = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_image_fetches",
+	Help: "Image fetches by source (CDN host or pds) and status",
+}, []string{"source", "status"})
+
+var DuplicateEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "enricher_duplicate_events_dropped",
+	Help: "Events dropped because the same DID, CID and operation was handled within the dedupe window",
+}, []string{"collection"})
+
+var ProcessorEnabled = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "enricher_processor_enabled",
+	Help: "Whether each configured processor is currently enabled (1) or disabled through the admin API (0)",
+}, []string{"processor"})
+
+var InFlightEvents = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "enricher_in_flight_events",
+	Help: "Events currently being enriched",
+})
+
+var SecondsSinceLastProduce = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "enricher_seconds_since_last_produce",
+	Help: "Time since an enriched event was last successfully produced, as of the last watchdog check",
+})
+
+var Stalled = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "enricher_stalled",
+	Help: "Whether the watchdog considers the enricher stalled (1), having produced nothing within the stall timeout",
+})
@@ -0,0 +1,235 @@
+package pds
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/identity"
+	"github.com/puzpuzpuz/xsync/v3"
+)
+
+// blueskyHostSuffixes are the hosts that Bluesky itself runs PDSes on.
+var blueskyHostSuffixes = []string{
+	"bsky.social",
+	"bsky.network",
+}
+
+// Features describes the PDS an account is hosted on.
+type Features struct {
+	Host           string
+	BlueskyHosted  bool
+	HostFirstSeen  time.Time
+	SuspiciousHost bool
+}
+
+// Reputation tracks when we first saw each PDS host and which hosts are on the suspicious list.
+type Reputation struct {
+	logger           *slog.Logger
+	firstSeen        *xsync.MapOf[string, time.Time]
+	suspicious       map[string]struct{}
+	snapshotPath     string
+	snapshotInterval time.Duration
+}
+
+type ReputationArgs struct {
+	Logger *slog.Logger
+	// SuspiciousHostsPath is a file of newline separated hosts. A host also matches all of its
+	// subdomains. Optional.
+	SuspiciousHostsPath string
+	// SnapshotPath is the file first-seen times are periodically written to and restored from.
+	// Optional.
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+}
+
+func NewReputation(args *ReputationArgs) (*Reputation, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	if args.SnapshotInterval == 0 {
+		args.SnapshotInterval = time.Minute
+	}
+
+	r := &Reputation{
+		logger:           args.Logger,
+		firstSeen:        xsync.NewMapOf[string, time.Time](),
+		suspicious:       map[string]struct{}{},
+		snapshotPath:     args.SnapshotPath,
+		snapshotInterval: args.SnapshotInterval,
+	}
+
+	if args.SuspiciousHostsPath != "" {
+		b, err := os.ReadFile(args.SuspiciousHostsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read suspicious hosts: %w", err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			r.suspicious[normalizeHost(line)] = struct{}{}
+		}
+	}
+
+	if r.snapshotPath != "" {
+		if err := r.loadSnapshot(); err != nil {
+			return nil, fmt.Errorf("failed to load PDS snapshot: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// PDSEndpoint returns the atproto PDS service endpoint from a DID document.
+func PDSEndpoint(doc *identity.DIDDocument) string {
+	for _, svc := range doc.Service {
+		if svc.ID == "#atproto_pds" || strings.HasSuffix(svc.ID, "#atproto_pds") {
+			return svc.ServiceEndpoint
+		}
+	}
+	return ""
+}
+
+// Features returns the reputation features for the PDS hosting the given DID document, or nil if
+// the document has no PDS.
+func (r *Reputation) Features(doc *identity.DIDDocument, now time.Time) *Features {
+	endpoint := PDSEndpoint(doc)
+	if endpoint == "" {
+		return nil
+	}
+
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	host = normalizeHost(host)
+
+	firstSeen, _ := r.firstSeen.LoadOrStore(host, now)
+
+	return &Features{
+		Host:           host,
+		BlueskyHosted:  matchesSuffix(host, blueskyHostSuffixes),
+		HostFirstSeen:  firstSeen,
+		SuspiciousHost: r.isSuspicious(host),
+	}
+}
+
+func (r *Reputation) isSuspicious(host string) bool {
+	for h := host; h != ""; {
+		if _, ok := r.suspicious[h]; ok {
+			return true
+		}
+		_, parent, found := strings.Cut(h, ".")
+		if !found {
+			break
+		}
+		h = parent
+	}
+	return false
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+func matchesSuffix(host string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run periodically writes snapshots until the context is cancelled, at which point a final
+// snapshot is written.
+func (r *Reputation) Run(ctx context.Context) {
+	if r.snapshotPath == "" {
+		return
+	}
+
+	ticker := time.NewTicker(r.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.writeSnapshot(); err != nil {
+				r.logger.Error("failed to write final PDS snapshot", "err", err)
+			}
+			return
+		case <-ticker.C:
+			if err := r.writeSnapshot(); err != nil {
+				r.logger.Error("failed to write PDS snapshot", "err", err)
+			}
+		}
+	}
+}
+
+func (r *Reputation) writeSnapshot() error {
+	snapshot := make(map[string]time.Time, r.firstSeen.Size())
+	r.firstSeen.Range(func(host string, at time.Time) bool {
+		snapshot[host] = at
+		return true
+	})
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// Write to a temp file and rename so we never leave a partial snapshot behind.
+	tmp, err := os.CreateTemp(filepath.Dir(r.snapshotPath), filepath.Base(r.snapshotPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.snapshotPath); err != nil {
+		return fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reputation) loadSnapshot() error {
+	b, err := os.ReadFile(r.snapshotPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			r.logger.Info("no PDS snapshot found, starting empty", "path", r.snapshotPath)
+			return nil
+		}
+		return err
+	}
+
+	snapshot := map[string]time.Time{}
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	for host, at := range snapshot {
+		r.firstSeen.Store(host, at)
+	}
+
+	r.logger.Info("loaded PDS snapshot", "path", r.snapshotPath, "hosts", len(snapshot))
+	return nil
+}
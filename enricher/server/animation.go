@@ -0,0 +1,143 @@
+package enricher
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"math"
+	"strings"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// animation tracks the frames sampled from an animated image. Each frame is processed as its own
+// image under its frame key, and the results are collapsed back into a single result for the
+// original CID once processing is done.
+type animation struct {
+	frameCount int
+	frameKeys  []string
+}
+
+func frameKey(cid string, frame int) string {
+	return fmt.Sprintf("%s#frame%d", cid, frame)
+}
+
+// sampleFrames decodes an animated GIF and returns up to maxSamples evenly spaced frames, encoded
+// as JPEG, along with the total number of frames. The first frame is always included. Frames are
+// composited according to their disposal methods, so each sample is what a viewer would actually
+// see at that point in the animation. Images that aren't animated GIFs return a nil slice.
+func sampleFrames(img []byte, maxSamples int) ([][]byte, int, error) {
+	if maxSamples <= 0 || !bytes.HasPrefix(img, []byte("GIF8")) {
+		return nil, 0, nil
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(img))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode gif: %w", err)
+	}
+	if len(g.Image) <= 1 {
+		return nil, len(g.Image), nil
+	}
+
+	samples := min(maxSamples, len(g.Image))
+	sampled := make(map[int]bool, samples)
+	for i := range samples {
+		sampled[i*len(g.Image)/samples] = true
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	var previous *image.RGBA
+
+	frames := make([][]byte, 0, len(sampled))
+	for i, frame := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Bounds())
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		if sampled[i] {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: 90}); err != nil {
+				return nil, 0, fmt.Errorf("failed to encode frame %d: %w", i, err)
+			}
+			frames = append(frames, buf.Bytes())
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+
+	return frames, len(g.Image), nil
+}
+
+// collapseAnimation replaces the per-frame results of an animated image with the results of its
+// worst-scoring frame, keyed by the original CID.
+func collapseAnimation(imageResults map[string]*osprey.ImageDispatchResults, cid string, anim *animation) {
+	var worst *osprey.ImageDispatchResults
+	worstFrame := 0
+	worstScore := math.Inf(-1)
+	for i, key := range anim.frameKeys {
+		res, ok := imageResults[key]
+		delete(imageResults, key)
+		if !ok {
+			continue
+		}
+		if score := frameRisk(res); worst == nil || score > worstScore {
+			worst, worstFrame, worstScore = res, i, score
+		}
+	}
+	if worst == nil {
+		return
+	}
+
+	worst.Cid = cid
+	worst.Animation = &osprey.ImageDispatchResults_AnimationResults{
+		FrameCount:    int32(anim.frameCount),
+		SampledFrames: int32(len(anim.frameKeys)),
+		WorstFrame:    int32(worstFrame),
+	}
+	imageResults[cid] = worst
+}
+
+// frameRisk ranks a frame's results so the worst frame of an animation can be picked. Hash
+// matches outrank everything, then a prescreen "nsfw" decision, then the highest Hive score for
+// any class that isn't a negative ("no_*" or "*not_*") class.
+func frameRisk(res *osprey.ImageDispatchResults) float64 {
+	score := 0.0
+	if res.Abyss != nil && res.Abyss.GetIsAbuseMatch() {
+		score += 100
+	}
+	if res.Ncii != nil && res.Ncii.GetIsMatch() {
+		score += 100
+	}
+	if res.Flagged != nil && res.Flagged.GetIsMatch() {
+		score += 10
+	}
+	if res.Prescreen != nil && res.Prescreen.GetDecision() == "nsfw" {
+		score += 1
+	}
+	if res.Hive != nil {
+		highest := 0.0
+		for class, classScore := range res.Hive.Classes {
+			if strings.HasPrefix(class, "no_") || strings.Contains(class, "not_") {
+				continue
+			}
+			highest = max(highest, classScore)
+		}
+		score += highest
+	}
+	return score
+}
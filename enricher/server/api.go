@@ -0,0 +1,105 @@
+package enricher
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo-contrib/echoprometheus"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	slogecho "github.com/samber/slog-echo"
+)
+
+// newAPIServer creates the HTTP server for the enricher's webhook and operator endpoints. Routes
+// are registered in addRoutes.
+func newAPIServer(logger *slog.Logger, addr string) (*http.Server, *echo.Echo) {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	e.Use(middleware.Recover())
+	e.Use(middleware.RemoveTrailingSlash())
+	e.Use(echoprometheus.NewMiddleware("enricher"))
+
+	slogEchoCfg := slogecho.Config{
+		DefaultLevel:     slog.LevelInfo,
+		ServerErrorLevel: slog.LevelError,
+		Filters: []slogecho.Filter{
+			func(ctx echo.Context) bool {
+				return ctx.Request().URL.Path != "/_health"
+			},
+		},
+	}
+	e.Use(slogecho.NewWithConfig(logger, slogEchoCfg))
+
+	httpd := &http.Server{
+		Addr:    addr,
+		Handler: e,
+	}
+
+	return httpd, e
+}
+
+func (en *Enricher) addRoutes() {
+	en.echo.GET("/_health", func(e echo.Context) error {
+		return e.String(http.StatusOK, "healthy")
+	})
+
+	g := en.echo.Group("/api")
+	if en.reportWebhookSecret != "" {
+		g.POST("/report", en.handleReportWebhook, bearerAuth(en.reportWebhookSecret))
+	}
+	if en.scanAPIToken != "" {
+		g.POST("/scan", en.handleScan, bearerAuth(en.scanAPIToken))
+	}
+
+	if en.adminAPIToken != "" {
+		admin := g.Group("/admin", bearerAuth(en.adminAPIToken))
+		admin.GET("/processors", en.handleListProcessors)
+		admin.PUT("/processors/:name", en.handleSetProcessor)
+	}
+}
+
+// runAPIServer serves the API until the context is cancelled, then shuts the server down.
+func (en *Enricher) runAPIServer(ctx context.Context) {
+	logger := en.logger.With("component", "api")
+	en.addRoutes()
+
+	go func() {
+		logger.Info("api server listening", "addr", en.httpd.Addr)
+		if err := en.httpd.ListenAndServe(); err != http.ErrServerClosed {
+			logger.Error("failed to start api server", "err", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	logger.Info("shutting down api server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := en.httpd.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to shut down api server", "err", err)
+		return
+	}
+
+	logger.Info("api server shut down")
+}
+
+// bearerAuth rejects requests that don't carry the given token as a bearer token.
+func bearerAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			auth := e.Request().Header.Get(echo.HeaderAuthorization)
+			provided, ok := strings.CutPrefix(auth, "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return e.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+			return next(e)
+		}
+	}
+}
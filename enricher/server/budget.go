@@ -0,0 +1,71 @@
+package enricher
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	"golang.org/x/time/rate"
+)
+
+// Budget policies, deciding what happens to a call when a paid API's budget is exhausted.
+const (
+	// BudgetQueue waits for budget to free up, for as long as the dispatch timeout allows.
+	BudgetQueue = "queue"
+	// BudgetSkip skips the call.
+	BudgetSkip = "skip"
+	// BudgetSample makes the call anyway for a configured fraction of requests and skips the rest.
+	BudgetSample = "sample"
+)
+
+// budget caps calls to a paid API at a daily quota. The quota is spread evenly across the day as a
+// token bucket holding up to an hour's worth of calls, so a burst of traffic can't spend the whole
+// day's budget at once.
+type budget struct {
+	service    string
+	limiter    *rate.Limiter
+	policy     string
+	sampleRate float64
+}
+
+func newBudget(service string, dailyCalls int, policy string, sampleRate float64) *budget {
+	perSecond := rate.Limit(float64(dailyCalls) / (24 * time.Hour).Seconds())
+	burst := max(1, dailyCalls/24)
+	b := &budget{
+		service:    service,
+		limiter:    rate.NewLimiter(perSecond, burst),
+		policy:     policy,
+		sampleRate: sampleRate,
+	}
+	metrics.PaidAPIBudgetRemaining.WithLabelValues(service).Set(float64(burst))
+	return b
+}
+
+// allow reports whether a call may be made, spending budget on it if so. Calls allowed by sampling
+// once the budget is exhausted don't spend anything, since there's nothing left to spend.
+func (b *budget) allow(ctx context.Context) bool {
+	defer func() {
+		metrics.PaidAPIBudgetRemaining.WithLabelValues(b.service).Set(b.limiter.Tokens())
+	}()
+
+	if b.limiter.Allow() {
+		return true
+	}
+
+	allowed := false
+	switch b.policy {
+	case BudgetQueue:
+		// Wait fails straight away if no token will be available before the context deadline.
+		allowed = b.limiter.Wait(ctx) == nil
+	case BudgetSample:
+		allowed = rand.Float64() < b.sampleRate
+	}
+
+	outcome := "skipped"
+	if allowed {
+		outcome = b.policy
+	}
+	metrics.PaidAPIBudgetExhausted.WithLabelValues(b.service, outcome).Inc()
+	return allowed
+}
@@ -0,0 +1,138 @@
+package enricher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/atproto/identity"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+const (
+	labelerCollection     = "app.bsky.labeler.service"
+	feedGenCollection     = "app.bsky.feed.generator"
+	listCollection        = "app.bsky.graph.list"
+	starterPackCollection = "app.bsky.graph.starterpack"
+)
+
+// collectionContext gathers extra context for labeler, feed generator, list and starter pack
+// records. It returns nil for all other collections. Avatar CIDs that need to be fetched in
+// addition to the record's own blobs are returned alongside.
+func (en *Enricher) collectionContext(
+	ctx context.Context,
+	logger *slog.Logger,
+	event *osprey.FirehoseEvent,
+	profile *bsky.ActorDefs_ProfileViewDetailed,
+	doc *identity.DIDDocument,
+) (*osprey.CollectionContext, []string) {
+	collection := event.Commit.Collection
+	logger = logger.With("processor", "collection_context")
+
+	switch collection {
+	case labelerCollection:
+		// Labelers don't have their own avatar, they use the account's profile avatar.
+		cc := &osprey.CollectionContext{}
+		if doc != nil {
+			cc.ServiceEndpoint = serviceEndpoint(doc, "#atproto_labeler")
+		}
+		var extraCids []string
+		if profile != nil && profile.Avatar != nil {
+			if cid := cidFromCdnURL(*profile.Avatar); cid != "" {
+				cc.AvatarCids = []string{cid}
+				extraCids = append(extraCids, cid)
+			}
+		}
+		return cc, extraCids
+
+	case feedGenCollection:
+		var feedGen bsky.FeedGenerator
+		if err := json.Unmarshal(event.Commit.Record, &feedGen); err != nil {
+			logger.Error("failed to unmarshal feed generator record", "err", err)
+			return nil, nil
+		}
+		cc := &osprey.CollectionContext{}
+		if feedGen.Avatar != nil {
+			cc.AvatarCids = []string{feedGen.Avatar.Ref.String()}
+		}
+		if feedGen.Did != "" {
+			cc.ServiceDid = &feedGen.Did
+			if en.didClient != nil {
+				_, serviceDoc, err := en.didClient.GetDIDDoc(ctx, feedGen.Did)
+				if err != nil {
+					logger.Warn("failed to resolve feed generator service DID", "service_did", feedGen.Did, "err", err)
+				} else {
+					cc.ServiceEndpoint = serviceEndpoint(serviceDoc, "#bsky_fg")
+				}
+			}
+		}
+		return cc, nil
+
+	case listCollection:
+		var list bsky.GraphList
+		if err := json.Unmarshal(event.Commit.Record, &list); err != nil {
+			logger.Error("failed to unmarshal list record", "err", err)
+			return nil, nil
+		}
+		uri := fmt.Sprintf("at://%s/%s/%s", event.Did, collection, event.Commit.Rkey)
+		cc := &osprey.CollectionContext{ListUri: &uri}
+		if list.Avatar != nil {
+			cc.AvatarCids = []string{list.Avatar.Ref.String()}
+		}
+		cc.ListItemCount = en.listItemCount(ctx, logger, uri)
+		return cc, nil
+
+	case starterPackCollection:
+		var starterPack bsky.GraphStarterpack
+		if err := json.Unmarshal(event.Commit.Record, &starterPack); err != nil {
+			logger.Error("failed to unmarshal starter pack record", "err", err)
+			return nil, nil
+		}
+		cc := &osprey.CollectionContext{}
+		if starterPack.List != "" {
+			cc.ListUri = &starterPack.List
+			cc.ListItemCount = en.listItemCount(ctx, logger, starterPack.List)
+		}
+		return cc, nil
+	}
+
+	return nil, nil
+}
+
+func (en *Enricher) listItemCount(ctx context.Context, logger *slog.Logger, uri string) *int64 {
+	if en.appviewClient == nil {
+		return nil
+	}
+	// A list that was just created may not have been indexed yet, so failures here are expected.
+	list, err := en.appviewClient.GetList(ctx, uri)
+	if err != nil {
+		logger.Warn("failed to fetch list from AppView", "list_uri", uri, "err", err)
+		return nil
+	}
+	return list.ListItemCount
+}
+
+// serviceEndpoint returns the endpoint of the service with the given fragment ID, or nil.
+func serviceEndpoint(doc *identity.DIDDocument, id string) *string {
+	for _, svc := range doc.Service {
+		if svc.ID == id || strings.HasSuffix(svc.ID, id) {
+			endpoint := svc.ServiceEndpoint
+			return &endpoint
+		}
+	}
+	return nil
+}
+
+// cidFromCdnURL extracts the blob CID from a CDN image URL, which look like
+// https://cdn.bsky.app/img/avatar/plain/<did>/<cid>@jpeg
+func cidFromCdnURL(u string) string {
+	cid, _, _ := strings.Cut(path.Base(u), "@")
+	if cid == "." || cid == "/" {
+		return ""
+	}
+	return cid
+}
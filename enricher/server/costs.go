@@ -0,0 +1,26 @@
+package enricher
+
+import (
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+)
+
+// Services we pay for on a per-call basis. Abyss fronts our PhotoDNA matching, so its calls are
+// what we're billed for PhotoDNA.
+const (
+	paidServiceHive  = "hive"
+	paidServiceAbyss = "abyss"
+)
+
+// recordPaidCall tracks a call to a paid API for cost attribution. Calls that error are still
+// counted towards the estimated cost, since most providers bill for them anyway.
+func (en *Enricher) recordPaidCall(service, collection string, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	metrics.PaidAPICalls.WithLabelValues(service, collection, outcome).Inc()
+
+	if price := en.pricePerCall[service]; price > 0 {
+		metrics.PaidAPIEstimatedCost.WithLabelValues(service, collection).Add(price)
+	}
+}
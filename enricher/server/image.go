@@ -0,0 +1,74 @@
+package enricher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// minDownscaleDimension is the smallest edge we'll shrink an image to before giving up on it.
+// Anything smaller isn't useful to send to a classifier.
+const minDownscaleDimension = 256
+
+var ErrImageTooLarge = errors.New("image exceeds max blob size and could not be downscaled")
+
+// fitImage returns img unchanged if it fits within maxSize bytes. Otherwise it repeatedly halves
+// the image dimensions and re-encodes as JPEG until the result fits. If the image can't be made
+// to fit, ErrImageTooLarge is returned and the caller should skip it.
+func fitImage(img []byte, maxSize int) ([]byte, error) {
+	if maxSize <= 0 || len(img) <= maxSize {
+		return img, nil
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(img))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode oversized image: %w", err)
+	}
+
+	for {
+		bounds := decoded.Bounds()
+		if bounds.Dx()/2 < minDownscaleDimension || bounds.Dy()/2 < minDownscaleDimension {
+			return nil, ErrImageTooLarge
+		}
+		decoded = halveImage(decoded)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, decoded, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, fmt.Errorf("failed to encode downscaled image: %w", err)
+		}
+		if buf.Len() <= maxSize {
+			return buf.Bytes(), nil
+		}
+	}
+}
+
+// halveImage downscales src to half its width and height by averaging each 2x2 block of pixels.
+func halveImage(src image.Image) image.Image {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx()/2, b.Dy()/2))
+	for y := 0; y < dst.Bounds().Dy(); y++ {
+		for x := 0; x < dst.Bounds().Dx(); x++ {
+			var r, g, bl, a uint32
+			for dy := 0; dy < 2; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					pr, pg, pb, pa := src.At(b.Min.X+x*2+dx, b.Min.Y+y*2+dy).RGBA()
+					r += pr
+					g += pg
+					bl += pb
+					a += pa
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / 4 >> 8)
+			dst.Pix[i+1] = uint8(g / 4 >> 8)
+			dst.Pix[i+2] = uint8(bl / 4 >> 8)
+			dst.Pix[i+3] = uint8(a / 4 >> 8)
+		}
+	}
+	return dst
+}
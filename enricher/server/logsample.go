@@ -0,0 +1,35 @@
+package enricher
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+)
+
+// levelHandler drops records below minLevel. Events that aren't sampled for full logging log
+// through it so their warnings and errors still show up.
+type levelHandler struct {
+	slog.Handler
+	minLevel slog.Level
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.minLevel && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), minLevel: h.minLevel}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), minLevel: h.minLevel}
+}
+
+// eventLogger returns the logger for a single event. A sample of events log in full, and the rest
+// only log at the unsampled level and above.
+func (en *Enricher) eventLogger(args ...any) *slog.Logger {
+	if en.eventLogSampleRate >= 1 || rand.Float64() < en.eventLogSampleRate {
+		return en.logger.With(args...)
+	}
+	return slog.New(&levelHandler{Handler: en.logger.Handler(), minLevel: en.unsampledLogLevel}).With(args...)
+}
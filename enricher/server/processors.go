@@ -0,0 +1,88 @@
+package enricher
+
+import (
+	"net/http"
+	"sort"
+	"sync/atomic"
+
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// Processors that can be toggled at runtime through the admin API.
+const (
+	processorOzone        = "ozone"
+	processorAppview      = "appview"
+	processorDid          = "did"
+	processorPrescreen    = "prescreen"
+	processorHive         = "hive"
+	processorAbyss        = "abyss"
+	processorRetinaOcr    = "retina_ocr"
+	processorRetinaHash   = "retina_hash"
+	processorNcii         = "ncii"
+	processorFlaggedImage = "flagged_image"
+)
+
+// registerProcessor makes a configured processor toggleable. Processors start enabled.
+func (en *Enricher) registerProcessor(name string) {
+	enabled := &atomic.Bool{}
+	enabled.Store(true)
+	en.processors[name] = enabled
+	metrics.ProcessorEnabled.WithLabelValues(name).Set(1)
+}
+
+// processorEnabled reports whether the processor is configured and hasn't been disabled.
+func (en *Enricher) processorEnabled(name string) bool {
+	enabled, ok := en.processors[name]
+	return ok && enabled.Load()
+}
+
+type ProcessorStatus struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type SetProcessorRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (en *Enricher) processorStatuses() []ProcessorStatus {
+	statuses := make([]ProcessorStatus, 0, len(en.processors))
+	for name, enabled := range en.processors {
+		statuses = append(statuses, ProcessorStatus{Name: name, Enabled: enabled.Load()})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func (en *Enricher) handleListProcessors(e echo.Context) error {
+	return e.JSON(http.StatusOK, en.processorStatuses())
+}
+
+// handleSetProcessor enables or disables a processor. Disabling prescreen also stops images from
+// going to Hive, since Hive only sees images that prescreen flagged.
+func (en *Enricher) handleSetProcessor(e echo.Context) error {
+	name := e.Param("name")
+	enabled, ok := en.processors[name]
+	if !ok {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "unknown or unconfigured processor"})
+	}
+
+	var req SetProcessorRequest
+	if err := e.Bind(&req); err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "could not bind request"})
+	}
+
+	if enabled.Swap(req.Enabled) != req.Enabled {
+		en.logger.Warn("processor toggled", "processor", name, "enabled", req.Enabled)
+	}
+	value := 0.0
+	if req.Enabled {
+		value = 1
+	}
+	metrics.ProcessorEnabled.WithLabelValues(name).Set(value)
+
+	return e.JSON(http.StatusOK, ProcessorStatus{Name: name, Enabled: req.Enabled})
+}
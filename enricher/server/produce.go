@@ -0,0 +1,101 @@
+package enricher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/bus/kafka"
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/protobuf/proto"
+)
+
+// Header keys set on every produced OspreyInputEvent. These let downstream consumers (and kcat
+// when debugging) filter on the record type without deserializing the full payload.
+const (
+	HeaderCollection = "collection"
+	HeaderOperation  = "operation"
+	HeaderHasImages  = "has_images"
+)
+
+// nearLimitMessageBytes is the produced message size at which an event counts as approaching
+// maxMessageBytes.
+const nearLimitMessageBytes = maxMessageBytes * 4 / 5
+
+// newProducerClient creates the Kafka client used by the output producer. We build it ourselves
+// rather than letting the Bus producer do so, since the Bus producer has no way to attach record
+// headers and we need to produce through the client directly.
+func newProducerClient(args *Args) (*kgo.Client, error) {
+	if len(args.KafkaBootstrapServers) == 0 {
+		return nil, fmt.Errorf("at least one bootstrap server must be provided")
+	}
+
+	// Match the Bus producer's client ID so that the broker host override keeps working.
+	firstBootstrapHost := strings.Split(args.KafkaBootstrapServers[0], ":")[0]
+	clientHostname, err := os.Hostname()
+	if err != nil {
+		clientHostname = "enricher"
+	}
+
+	return kafka.NewKafkaClient(kafka.Config{
+		BootstrapServers: args.KafkaBootstrapServers,
+		ClientID:         fmt.Sprintf("%s;host_override=%s", clientHostname, firstBootstrapHost),
+		Topic:            args.OutputTopic,
+		SASLUsername:     args.SASLUsername,
+		SASLPassword:     args.SASLPassword,
+	}, nil)
+}
+
+// produceEvent asynchronously produces the OspreyInputEvent to the output topic, keyed by DID and
+// tagged with filtering headers derived from the moderation event.
+func (en *Enricher) produceEvent(ctx context.Context, modEvt *osprey.ModerationEnrichedFirehoseRecordEvent, hasImages bool, evt *osprey.OspreyInputEvent) error {
+	payload, err := proto.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal OspreyInputEvent: %w", err)
+	}
+
+	metrics.OutputEventSizeBytes.WithLabelValues(modEvt.Collection).Observe(float64(len(payload)))
+	if len(payload) >= nearLimitMessageBytes {
+		metrics.OutputEventsNearLimit.WithLabelValues(modEvt.Collection).Inc()
+	}
+
+	rec := &kgo.Record{
+		Key:     []byte(modEvt.Did),
+		Value:   payload,
+		Topic:   en.outputTopic,
+		Headers: eventHeaders(modEvt, hasImages),
+	}
+
+	en.producerClient.Produce(ctx, rec, func(r *kgo.Record, err error) {
+		if err != nil {
+			en.logger.Error("failed to async produce record", "key", string(r.Key), "err", err)
+			return
+		}
+		en.lastProduced.Store(time.Now().UnixNano())
+	})
+
+	return nil
+}
+
+func eventHeaders(modEvt *osprey.ModerationEnrichedFirehoseRecordEvent, hasImages bool) []kgo.RecordHeader {
+	operation := ""
+	switch modEvt.Operation {
+	case osprey.CommitOperation_COMMIT_OPERATION_CREATE:
+		operation = "create"
+	case osprey.CommitOperation_COMMIT_OPERATION_UPDATE:
+		operation = "update"
+	case osprey.CommitOperation_COMMIT_OPERATION_DELETE:
+		operation = "delete"
+	}
+
+	return []kgo.RecordHeader{
+		{Key: HeaderCollection, Value: []byte(modEvt.Collection)},
+		{Key: HeaderOperation, Value: []byte(operation)},
+		{Key: HeaderHasImages, Value: []byte(strconv.FormatBool(hasImages))},
+	}
+}
@@ -0,0 +1,156 @@
+package enricher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/labstack/echo/v4"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const reportActionName = "moderation.report#create"
+
+// ReportWebhook is the body accepted by the report intake webhook. It mirrors the shape of an
+// Ozone report event, so Ozone webhooks can be pointed at the endpoint directly. The subject is
+// either a repo ref (did) or a strong ref (uri and cid).
+type ReportWebhook struct {
+	ID         int64  `json:"id"`
+	Source     string `json:"source"`
+	ReasonType string `json:"reasonType"`
+	Reason     string `json:"reason"`
+	Subject    struct {
+		Did string `json:"did"`
+		Uri string `json:"uri"`
+		Cid string `json:"cid"`
+	} `json:"subject"`
+	ReportedBy string `json:"reportedBy"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+func (en *Enricher) handleReportWebhook(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	status := "error"
+	defer func() {
+		metrics.ReportWebhooks.WithLabelValues(status).Inc()
+	}()
+
+	var req ReportWebhook
+	if err := e.Bind(&req); err != nil {
+		status = "invalid"
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "could not bind request"})
+	}
+
+	reportEvt, err := reportWebhookToEvent(&req)
+	if err != nil {
+		status = "invalid"
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ospreyEvt, err := reportToOspreyEvent(reportEvt)
+	if err != nil {
+		en.logger.Error("failed to convert report to OspreyInputEvent", "report_id", req.ID, "err", err)
+		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "could not convert report"})
+	}
+
+	payload, err := proto.Marshal(ospreyEvt)
+	if err != nil {
+		en.logger.Error("failed to marshal OspreyInputEvent", "report_id", req.ID, "err", err)
+		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "could not convert report"})
+	}
+
+	// Produce synchronously so that the sender can retry if the report didn't make it to Kafka.
+	rec := &kgo.Record{
+		Key:   []byte(reportEvt.SubjectDid),
+		Value: payload,
+		Topic: en.outputTopic,
+		Headers: []kgo.RecordHeader{
+			{Key: HeaderCollection, Value: []byte("moderation.report")},
+			{Key: HeaderOperation, Value: []byte("create")},
+			{Key: HeaderHasImages, Value: []byte("false")},
+		},
+	}
+	if err := en.producerClient.ProduceSync(ctx, rec).FirstErr(); err != nil {
+		en.logger.Error("failed to produce report", "report_id", req.ID, "err", err)
+		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "could not produce report"})
+	}
+
+	status = "ok"
+	return e.NoContent(http.StatusAccepted)
+}
+
+func reportWebhookToEvent(req *ReportWebhook) (*osprey.ModerationReportEvent, error) {
+	if req.ReasonType == "" {
+		return nil, fmt.Errorf("missing reasonType")
+	}
+
+	evt := &osprey.ModerationReportEvent{
+		ReportId:   req.ID,
+		Source:     req.Source,
+		ReasonType: req.ReasonType,
+		ReportedBy: req.ReportedBy,
+		CreatedAt:  timestamppb.Now(),
+	}
+	if evt.Source == "" {
+		evt.Source = "ozone"
+	}
+	if req.Reason != "" {
+		evt.Reason = &req.Reason
+	}
+
+	switch {
+	case req.Subject.Uri != "":
+		uri, err := syntax.ParseATURI(req.Subject.Uri)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subject uri: %w", err)
+		}
+		evt.SubjectDid = uri.Authority().String()
+		evt.SubjectUri = &req.Subject.Uri
+		if req.Subject.Cid != "" {
+			evt.SubjectCid = &req.Subject.Cid
+		}
+	case req.Subject.Did != "":
+		if _, err := syntax.ParseDID(req.Subject.Did); err != nil {
+			return nil, fmt.Errorf("invalid subject did: %w", err)
+		}
+		evt.SubjectDid = req.Subject.Did
+	default:
+		return nil, fmt.Errorf("missing subject")
+	}
+
+	if req.CreatedAt != "" {
+		createdAt, err := syntax.ParseDatetimeLenient(req.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid createdAt: %w", err)
+		}
+		evt.CreatedAt = timestamppb.New(createdAt.Time())
+	}
+
+	return evt, nil
+}
+
+func reportToOspreyEvent(event *osprey.ModerationReportEvent) (*osprey.OspreyInputEvent, error) {
+	dataBytes, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ModerationReportEvent: %w", err)
+	}
+
+	return &osprey.OspreyInputEvent{
+		SendTime: timestamppb.Now(),
+		Data: &osprey.OspreyInputEventData{
+			ActionName: reportActionName,
+			ActionId:   time.Now().UnixMicro(),
+			Timestamp:  event.CreatedAt,
+			SecretData: map[string]string{},
+			Encoding:   "UTF8",
+			Data:       dataBytes,
+		},
+	}, nil
+}
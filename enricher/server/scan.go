@@ -0,0 +1,161 @@
+package enricher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/robusthttp"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/bluesky-social/indigo/xrpc"
+	"github.com/bluesky-social/osprey-atproto/enricher/pds"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// scanBlobCollection is the collection reported for on-demand scans of a bare blob, which have no
+// record of their own.
+const scanBlobCollection = "blob"
+
+// ScanRequest asks for an on-demand scan of either a record, by AT-URI, or a single blob, by DID
+// and CID.
+type ScanRequest struct {
+	Uri string `json:"uri"`
+	Did string `json:"did"`
+	Cid string `json:"cid"`
+}
+
+// handleScan runs a record or blob through the full enrichment pipeline and returns the enriched
+// event instead of producing it. Scans don't count towards velocity.
+func (en *Enricher) handleScan(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	var req ScanRequest
+	if err := e.Bind(&req); err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "could not bind request"})
+	}
+
+	var event *osprey.FirehoseEvent
+	var err error
+	switch {
+	case req.Uri != "":
+		event, err = en.scanEventForRecord(ctx, req.Uri)
+	case req.Did != "" && req.Cid != "":
+		event, err = scanEventForBlob(req.Did, req.Cid)
+	default:
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "either uri or did and cid are required"})
+	}
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	logger := en.logger.With("did", event.Did, "collection", event.Commit.Collection, "rkey", event.Commit.Rkey, "scan", true)
+	logger.Info("running on-demand scan")
+
+	modEvt, _, err := en.enrichEvent(ctx, logger, event, false)
+	if err != nil {
+		logger.Error("on-demand scan failed", "err", err)
+		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return e.JSON(http.StatusOK, modEvt)
+}
+
+// scanEventForRecord fetches the record from the author's PDS and wraps it in a create event.
+func (en *Enricher) scanEventForRecord(ctx context.Context, rawURI string) (*osprey.FirehoseEvent, error) {
+	uri, err := syntax.ParseATURI(rawURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uri: %w", err)
+	}
+	if uri.Collection() == "" || uri.RecordKey() == "" {
+		return nil, fmt.Errorf("uri must point to a record")
+	}
+	if en.didClient == nil {
+		return nil, fmt.Errorf("scanning records requires a PLC host to be configured")
+	}
+
+	did := uri.Authority().String()
+	if !uri.Authority().IsDID() {
+		return nil, fmt.Errorf("uri authority must be a DID")
+	}
+
+	_, doc, err := en.didClient.GetDIDDoc(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID: %w", err)
+	}
+	endpoint := pds.PDSEndpoint(doc)
+	if endpoint == "" {
+		return nil, fmt.Errorf("DID has no PDS")
+	}
+
+	httpc := robusthttp.NewClient(robusthttp.WithMaxRetries(1))
+	httpc.Timeout = 10 * time.Second
+	xrpcc := &xrpc.Client{Client: httpc, Host: endpoint}
+
+	var out struct {
+		Cid   *string         `json:"cid"`
+		Value json.RawMessage `json:"value"`
+	}
+	params := map[string]any{
+		"repo":       did,
+		"collection": uri.Collection().String(),
+		"rkey":       uri.RecordKey().String(),
+	}
+	if err := xrpcc.Do(ctx, xrpc.Query, "", "com.atproto.repo.getRecord", params, nil, &out); err != nil {
+		return nil, fmt.Errorf("failed to fetch record: %w", err)
+	}
+
+	event := &osprey.FirehoseEvent{
+		Did:       did,
+		Timestamp: timestamppb.Now(),
+		Kind:      osprey.EventKind_EVENT_KIND_COMMIT,
+		Commit: &osprey.Commit{
+			Operation:  osprey.CommitOperation_COMMIT_OPERATION_CREATE,
+			Collection: uri.Collection().String(),
+			Rkey:       uri.RecordKey().String(),
+			Record:     out.Value,
+		},
+	}
+	if out.Cid != nil {
+		event.Commit.Cid = *out.Cid
+	}
+
+	return event, nil
+}
+
+// scanEventForBlob builds a create event with a synthetic record that references only the given
+// blob, so that it goes through the image processors like any other record's images.
+func scanEventForBlob(did, cid string) (*osprey.FirehoseEvent, error) {
+	if _, err := syntax.ParseDID(did); err != nil {
+		return nil, fmt.Errorf("invalid did: %w", err)
+	}
+	if _, err := syntax.ParseCID(cid); err != nil {
+		return nil, fmt.Errorf("invalid cid: %w", err)
+	}
+
+	record, err := json.Marshal(map[string]any{
+		"blob": map[string]any{
+			"$type":    "blob",
+			"ref":      map[string]string{"$link": cid},
+			"mimeType": "image/*",
+			"size":     0,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal synthetic record: %w", err)
+	}
+
+	return &osprey.FirehoseEvent{
+		Did:       did,
+		Timestamp: timestamppb.Now(),
+		Kind:      osprey.EventKind_EVENT_KIND_COMMIT,
+		Commit: &osprey.Commit{
+			Operation:  osprey.CommitOperation_COMMIT_OPERATION_CREATE,
+			Collection: scanBlobCollection,
+			Record:     record,
+		},
+	}, nil
+}
@@ -6,36 +6,56 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/bluesky-social/go-util/pkg/bus/consumer"
 	"github.com/bluesky-social/go-util/pkg/bus/producer"
+	"github.com/bluesky-social/indigo/api/bsky"
 	"github.com/bluesky-social/indigo/atproto/atdata"
+	"github.com/bluesky-social/indigo/atproto/identity"
 	"github.com/bluesky-social/osprey-atproto/enricher/abyss"
 	"github.com/bluesky-social/osprey-atproto/enricher/appview"
+	"github.com/bluesky-social/osprey-atproto/enricher/campaign"
 	"github.com/bluesky-social/osprey-atproto/enricher/cdn"
 	"github.com/bluesky-social/osprey-atproto/enricher/did"
 	flaggedimage "github.com/bluesky-social/osprey-atproto/enricher/flagged-image"
 	"github.com/bluesky-social/osprey-atproto/enricher/hive"
+	"github.com/bluesky-social/osprey-atproto/enricher/impersonation"
+	"github.com/bluesky-social/osprey-atproto/enricher/labels"
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
 	"github.com/bluesky-social/osprey-atproto/enricher/ncii"
 	"github.com/bluesky-social/osprey-atproto/enricher/ozone"
+	"github.com/bluesky-social/osprey-atproto/enricher/pds"
 	"github.com/bluesky-social/osprey-atproto/enricher/prescreen"
 	retinahash "github.com/bluesky-social/osprey-atproto/enricher/retina-hash"
 	retinaocr "github.com/bluesky-social/osprey-atproto/enricher/retina-ocr"
+	"github.com/bluesky-social/osprey-atproto/enricher/termlist"
+	"github.com/bluesky-social/osprey-atproto/enricher/velocity"
+	"github.com/bluesky-social/osprey-atproto/enricher/watchlist"
 	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/labstack/echo/v4"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 	"github.com/puzpuzpuz/xsync/v3"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Enricher struct {
 	logger             *slog.Logger
 	producer           *producer.Producer[*osprey.OspreyInputEvent]
+	producerClient     *kgo.Client
+	outputTopic        string
 	consumer           *consumer.Consumer[*osprey.FirehoseEvent]
 	cdn                *cdn.Client
 	abyssClient        *abyss.Client
@@ -50,6 +70,54 @@ type Enricher struct {
 	flaggedImageClient *flaggedimage.Client
 
 	milvusClient *milvusclient.Client
+
+	velocityTracker *velocity.Tracker
+	campaignIndex   *campaign.Index
+	termListMatcher *termlist.Matcher
+
+	impersonationDetector *impersonation.Detector
+	pdsReputation         *pds.Reputation
+	watchlist             *watchlist.Watchlist
+	labelCache            *labels.Cache
+
+	httpd               *http.Server
+	echo                *echo.Echo
+	reportWebhookSecret string
+	scanAPIToken        string
+	adminAPIToken       string
+
+	// processors holds the runtime toggle for each configured processor. The map itself is
+	// fixed after New.
+	processors map[string]*atomic.Bool
+
+	// recentEvents holds the DID, CID and operation of recently handled events, so that
+	// re-delivered duplicates can be dropped. Nil when deduplication is disabled.
+	recentEvents *lru.LRU[string, struct{}]
+
+	// inFlight tracks events between being handed to handleEvent and being produced, so they can
+	// be drained on shutdown.
+	inFlight      sync.WaitGroup
+	inFlightCount atomic.Int64
+	drainTimeout  time.Duration
+
+	// lastProduced and lastConsumed are unix nanosecond timestamps of the last successfully
+	// produced event and the last event handed to handleEvent, for the stall watchdog.
+	lastProduced atomic.Int64
+	lastConsumed atomic.Int64
+	stallTimeout time.Duration
+	stallExit    bool
+
+	eventLogSampleRate float64
+	unsampledLogLevel  slog.Level
+
+	maxBlobSize           int
+	animatedFrameSamples  int
+	sniffBlobs            bool
+	maxOutputSize         int
+	pricePerCall          map[string]float64
+	hiveBudget            *budget
+	prescreenQASampleRate float64
+	prescreenFailOpen     bool
 }
 
 type Args struct {
@@ -58,7 +126,7 @@ type Args struct {
 	SASLPassword            string
 	InputTopic              string
 	OutputTopic             string
-	ImageCdnURL             string
+	ImageCdnURLs            []string
 	AbyssURL                string
 	AbyssAdminPassword      string
 	HiveAPIToken            string
@@ -75,24 +143,111 @@ type Args struct {
 	NciiMinDistance         float64
 	FlaggedImageCollection  string
 	FlaggedImageMinDistance float64
+	DedupeWindow            time.Duration
+	DrainTimeout            time.Duration
+	StallTimeout            time.Duration
+	StallExit               bool
+	EventLogSampleRate      float64
+	UnsampledLogLevel       slog.Level
+	MaxBlobSize             int
+	AnimatedFrameSamples    int
+	SniffBlobs              bool
+	MaxOutputSize           int
+	HivePricePerCall        float64
+	HiveDailyBudget         int
+	HiveBudgetPolicy        string
+	HiveBudgetSampleRate    float64
+	AbyssPricePerCall       float64
+	PrescreenQASampleRate   float64
+	PrescreenFailurePolicy  string
+	VelocityEnabled         bool
+	VelocitySnapshotPath    string
+	CampaignIndexEnabled    bool
+	CampaignWindow          time.Duration
+	CampaignSnapshotPath    string
+	TermListSource          string
+	ProtectedAccountsPath   string
+	ImpersonationMinScore   float64
+	SuspiciousHostsPath     string
+	PDSSnapshotPath         string
+	WatchlistURIs           []string
+	LabelerHost             string
+	APIListenAddr           string
+	ReportWebhookSecret     string
+	ScanAPIToken            string
+	AdminAPIToken           string
 	Logger                  *slog.Logger
 }
 
+// Prescreen failure policies, deciding what happens to an image when the prescreen call fails.
+const (
+	// PrescreenFailClosed skips Hive for the image.
+	PrescreenFailClosed = "closed"
+	// PrescreenFailOpen sends the image to Hive anyway.
+	PrescreenFailOpen = "open"
+)
+
+// ConsumerGroup is the Kafka consumer group the enricher consumes the input topic with. The dead
+// letter queue topic name is derived from it.
+const ConsumerGroup = "enricher-consumers"
+
+// maxMessageBytes is the max.message.bytes we configure on the output topic.
+const maxMessageBytes = 5 << 20 // 5 MiB
+
+// DefaultMaxOutputSize leaves some headroom under maxMessageBytes for the record key, headers and
+// batch overhead.
+const DefaultMaxOutputSize = maxMessageBytes - 64<<10
+
 func New(ctx context.Context, args *Args) (*Enricher, error) {
 	if args.Logger == nil {
 		args.Logger = slog.Default()
 	}
 	logger := args.Logger
 
-	if args.ImageCdnURL == "" {
+	if len(args.ImageCdnURLs) == 0 {
 		return nil, fmt.Errorf("missing image CDN url")
 	}
 
+	if args.DrainTimeout <= 0 {
+		args.DrainTimeout = 30 * time.Second
+	}
+
+	switch args.PrescreenFailurePolicy {
+	case "":
+		args.PrescreenFailurePolicy = PrescreenFailClosed
+	case PrescreenFailClosed, PrescreenFailOpen:
+	default:
+		return nil, fmt.Errorf("invalid prescreen failure policy %q, must be %q or %q", args.PrescreenFailurePolicy, PrescreenFailClosed, PrescreenFailOpen)
+	}
+
+	switch args.HiveBudgetPolicy {
+	case "":
+		args.HiveBudgetPolicy = BudgetSkip
+	case BudgetQueue, BudgetSkip, BudgetSample:
+	default:
+		return nil, fmt.Errorf("invalid hive budget policy %q, must be %q, %q or %q", args.HiveBudgetPolicy, BudgetQueue, BudgetSkip, BudgetSample)
+	}
+
+	if args.MaxOutputSize <= 0 || args.MaxOutputSize > maxMessageBytes {
+		args.MaxOutputSize = DefaultMaxOutputSize
+	}
+
 	en := Enricher{
-		logger: args.Logger,
-		cdn: cdn.NewClient(&cdn.ClientArgs{
-			Host: args.ImageCdnURL,
-		}),
+		logger:                args.Logger,
+		drainTimeout:          args.DrainTimeout,
+		stallTimeout:          args.StallTimeout,
+		stallExit:             args.StallExit,
+		eventLogSampleRate:    args.EventLogSampleRate,
+		unsampledLogLevel:     args.UnsampledLogLevel,
+		maxBlobSize:           args.MaxBlobSize,
+		animatedFrameSamples:  args.AnimatedFrameSamples,
+		maxOutputSize:         args.MaxOutputSize,
+		prescreenQASampleRate: args.PrescreenQASampleRate,
+		prescreenFailOpen:     args.PrescreenFailurePolicy == PrescreenFailOpen,
+		pricePerCall: map[string]float64{
+			paidServiceHive:  args.HivePricePerCall,
+			paidServiceAbyss: args.AbyssPricePerCall,
+		},
 	}
 
 	if args.AbyssURL != "" {
@@ -104,6 +259,11 @@ func New(ctx context.Context, args *Args) (*Enricher, error) {
 		hiveClient := hive.NewClient(args.HiveAPIToken)
 		en.hiveClient = hiveClient
 		logger.Info("initialized Hive client")
+
+		if args.HiveDailyBudget > 0 {
+			en.hiveBudget = newBudget(paidServiceHive, args.HiveDailyBudget, args.HiveBudgetPolicy, args.HiveBudgetSampleRate)
+			logger.Info("initialized Hive budget", "daily_calls", args.HiveDailyBudget, "policy", args.HiveBudgetPolicy)
+		}
 	}
 	if args.RetinaOcrURL != "" {
 		client := retinaocr.NewClient(args.RetinaOcrURL)
@@ -143,6 +303,136 @@ func New(ctx context.Context, args *Args) (*Enricher, error) {
 		en.didClient = didClient
 		logger.Info("initialized DID client", "host", args.PLCHost)
 	}
+
+	// Without a DID client there's no way to find the owning PDS, so the CDN hosts are all we have.
+	cdnArgs := &cdn.ClientArgs{Hosts: args.ImageCdnURLs}
+	if en.didClient != nil {
+		cdnArgs.PDSResolver = func(ctx context.Context, did string) (string, error) {
+			_, doc, err := en.didClient.GetDIDDoc(ctx, did)
+			if err != nil {
+				return "", err
+			}
+			endpoint := pds.PDSEndpoint(doc)
+			if endpoint == "" {
+				return "", fmt.Errorf("DID document has no PDS")
+			}
+			return endpoint, nil
+		}
+	}
+	en.cdn = cdn.NewClient(cdnArgs)
+	logger.Info("initialized CDN client", "hosts", args.ImageCdnURLs, "pds_fallback", cdnArgs.PDSResolver != nil)
+
+	if args.SniffBlobs {
+		if cdnArgs.PDSResolver == nil {
+			return nil, fmt.Errorf("blob sniffing requires a PLC host to find each blob's PDS")
+		}
+		en.sniffBlobs = true
+		logger.Info("enabled blob content sniffing")
+	}
+
+	if args.DedupeWindow > 0 {
+		en.recentEvents = lru.NewLRU[string, struct{}](500_000, nil, args.DedupeWindow)
+		logger.Info("initialized duplicate event suppression", "window", args.DedupeWindow)
+	}
+	if args.VelocityEnabled {
+		tracker, err := velocity.NewTracker(&velocity.TrackerArgs{
+			Logger:       logger.With("component", "velocity"),
+			SnapshotPath: args.VelocitySnapshotPath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create velocity tracker: %w", err)
+		}
+		en.velocityTracker = tracker
+		logger.Info("initialized velocity tracker", "snapshot_path", args.VelocitySnapshotPath)
+	}
+	if args.CampaignIndexEnabled {
+		if en.retinaHashClient == nil {
+			return nil, fmt.Errorf("the campaign index requires a Retina Hash URL")
+		}
+		index, err := campaign.NewIndex(&campaign.IndexArgs{
+			Logger:       logger.With("component", "campaign"),
+			Window:       args.CampaignWindow,
+			SnapshotPath: args.CampaignSnapshotPath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create campaign index: %w", err)
+		}
+		en.campaignIndex = index
+		logger.Info("initialized campaign index", "window", args.CampaignWindow, "snapshot_path", args.CampaignSnapshotPath)
+	}
+	if args.TermListSource != "" {
+		matcher, err := termlist.NewMatcher(ctx, &termlist.MatcherArgs{
+			Logger: logger.With("component", "termlist"),
+			Source: args.TermListSource,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create term list matcher: %w", err)
+		}
+		en.termListMatcher = matcher
+		logger.Info("initialized term list matcher", "source", args.TermListSource)
+	}
+	if args.ProtectedAccountsPath != "" {
+		detector, err := impersonation.NewDetector(&impersonation.DetectorArgs{
+			AccountsPath: args.ProtectedAccountsPath,
+			MinScore:     args.ImpersonationMinScore,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create impersonation detector: %w", err)
+		}
+		en.impersonationDetector = detector
+		logger.Info("initialized impersonation detector", "protected_accounts_path", args.ProtectedAccountsPath)
+	}
+	if args.PLCHost != "" {
+		reputation, err := pds.NewReputation(&pds.ReputationArgs{
+			Logger:              logger.With("component", "pds-reputation"),
+			SuspiciousHostsPath: args.SuspiciousHostsPath,
+			SnapshotPath:        args.PDSSnapshotPath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PDS reputation tracker: %w", err)
+		}
+		en.pdsReputation = reputation
+		logger.Info("initialized PDS reputation tracker", "suspicious_hosts_path", args.SuspiciousHostsPath, "snapshot_path", args.PDSSnapshotPath)
+	}
+	if len(args.WatchlistURIs) > 0 {
+		if en.appviewClient == nil {
+			return nil, fmt.Errorf("watchlists require an Appview host")
+		}
+		wl, err := watchlist.NewWatchlist(ctx, &watchlist.WatchlistArgs{
+			Logger: logger.With("component", "watchlist"),
+			Client: en.appviewClient,
+			Lists:  args.WatchlistURIs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create watchlist: %w", err)
+		}
+		en.watchlist = wl
+		logger.Info("initialized watchlist", "lists", args.WatchlistURIs)
+	}
+	if args.LabelerHost != "" {
+		labelCache, err := labels.NewCache(&labels.CacheArgs{
+			Logger:      logger.With("component", "labels"),
+			LabelerHost: args.LabelerHost,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create label cache: %w", err)
+		}
+		en.labelCache = labelCache
+		logger.Info("initialized label cache", "host", args.LabelerHost)
+	}
+	if args.APIListenAddr != "" {
+		en.httpd, en.echo = newAPIServer(logger.With("component", "api"), args.APIListenAddr)
+		en.reportWebhookSecret = args.ReportWebhookSecret
+		if en.reportWebhookSecret == "" {
+			logger.Warn("no report webhook secret set, report intake webhook is disabled")
+		}
+		en.scanAPIToken = args.ScanAPIToken
+		en.adminAPIToken = args.AdminAPIToken
+		if en.scanAPIToken == "" {
+			logger.Warn("no scan API token set, on-demand scan endpoint is disabled")
+		}
+		logger.Info("initialized API server", "addr", args.APIListenAddr)
+	}
 	if args.MilvusHost != "" {
 		client, err := milvusclient.New(ctx, &milvusclient.ClientConfig{
 			Address: args.MilvusHost,
@@ -183,18 +473,44 @@ func New(ctx context.Context, args *Args) (*Enricher, error) {
 		}
 	}
 
+	en.processors = map[string]*atomic.Bool{}
+	for name, configured := range map[string]bool{
+		processorOzone:        en.ozoneClient != nil,
+		processorAppview:      en.appviewClient != nil,
+		processorDid:          en.didClient != nil,
+		processorPrescreen:    en.prescreenClient != nil,
+		processorHive:         en.hiveClient != nil,
+		processorAbyss:        en.abyssClient != nil,
+		processorRetinaOcr:    en.retinaOcrClient != nil,
+		processorRetinaHash:   en.retinaHashClient != nil,
+		processorNcii:         en.nciiClient != nil,
+		processorFlaggedImage: en.flaggedImageClient != nil,
+	} {
+		if configured {
+			en.registerProcessor(name)
+		}
+	}
+
+	producerClient, err := newProducerClient(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer client: %w", err)
+	}
+	en.producerClient = producerClient
+	en.outputTopic = args.OutputTopic
+
 	busProducer, err := producer.New(ctx, logger, args.KafkaBootstrapServers, args.OutputTopic,
+		producer.WithClient[*osprey.OspreyInputEvent](producerClient),
 		producer.WithCredentials[*osprey.OspreyInputEvent](args.SASLUsername, args.SASLPassword),
 		producer.WithEnsureTopic[*osprey.OspreyInputEvent](true),
 		producer.WithTopicPartitions[*osprey.OspreyInputEvent](100),
-		producer.WithMaxMessageBytes[*osprey.OspreyInputEvent](5<<20), // 5 MiB
+		producer.WithMaxMessageBytes[*osprey.OspreyInputEvent](maxMessageBytes),
 	)
 	if err != nil {
 		return nil, errors.Join(err, errors.New("failed to create secure Kafka producer"))
 	}
 	en.producer = busProducer
 
-	busConsumer, err := consumer.New(logger, args.KafkaBootstrapServers, args.InputTopic, "enricher-consumers",
+	busConsumer, err := consumer.New(logger, args.KafkaBootstrapServers, args.InputTopic, ConsumerGroup,
 		consumer.WithOffset[*osprey.FirehoseEvent](consumer.OffsetEnd),
 		consumer.WithMessageHandler(en.handleEvent),
 		consumer.WithDeadLetterQueue[*osprey.FirehoseEvent](),
@@ -217,6 +533,87 @@ func (en *Enricher) Run(ctx context.Context) error {
 	defer en.producer.Close()
 	defer en.consumer.Close()
 
+	if en.velocityTracker != nil {
+		trackerCtx, cancelTracker := context.WithCancel(context.Background())
+		trackerDone := make(chan struct{})
+		go func() {
+			en.velocityTracker.Run(trackerCtx)
+			close(trackerDone)
+		}()
+		// Stop the tracker last so the final snapshot includes everything we processed.
+		defer func() {
+			cancelTracker()
+			<-trackerDone
+		}()
+	}
+
+	if en.campaignIndex != nil {
+		indexCtx, cancelIndex := context.WithCancel(context.Background())
+		indexDone := make(chan struct{})
+		go func() {
+			en.campaignIndex.Run(indexCtx)
+			close(indexDone)
+		}()
+		defer func() {
+			cancelIndex()
+			<-indexDone
+		}()
+	}
+
+	if en.pdsReputation != nil {
+		reputationCtx, cancelReputation := context.WithCancel(context.Background())
+		reputationDone := make(chan struct{})
+		go func() {
+			en.pdsReputation.Run(reputationCtx)
+			close(reputationDone)
+		}()
+		defer func() {
+			cancelReputation()
+			<-reputationDone
+		}()
+	}
+
+	if en.termListMatcher != nil {
+		matcherCtx, cancelMatcher := context.WithCancel(context.Background())
+		defer cancelMatcher()
+		go en.termListMatcher.Run(matcherCtx)
+	}
+
+	if en.watchlist != nil {
+		watchlistCtx, cancelWatchlist := context.WithCancel(context.Background())
+		defer cancelWatchlist()
+		go en.watchlist.Run(watchlistCtx)
+	}
+
+	if en.labelCache != nil {
+		labelsCtx, cancelLabels := context.WithCancel(context.Background())
+		defer cancelLabels()
+		go en.labelCache.Run(labelsCtx)
+	}
+
+	// The API server produces through the same client, so it has to be shut down before the
+	// producer is closed.
+	apiCtx, cancelAPI := context.WithCancel(context.Background())
+	apiDone := make(chan struct{})
+	if en.httpd != nil {
+		go func() {
+			en.runAPIServer(apiCtx)
+			close(apiDone)
+		}()
+	} else {
+		close(apiDone)
+	}
+
+	// Give the watchdog a full stall timeout from startup before it expects any output.
+	stalled := make(chan struct{})
+	if en.stallTimeout > 0 {
+		en.lastProduced.Store(time.Now().UnixNano())
+		en.lastConsumed.Store(time.Now().UnixNano())
+		watchdogCtx, cancelWatchdog := context.WithCancel(context.Background())
+		defer cancelWatchdog()
+		go en.runWatchdog(watchdogCtx, stalled)
+	}
+
 	shutdownConsumer := make(chan struct{})
 	consumerShutdown := make(chan struct{})
 	go func() {
@@ -254,24 +651,43 @@ func (en *Enricher) Run(ctx context.Context) error {
 			en.logger.Info("received OS exit signal", "signal", sig)
 		case <-ctx.Done():
 			en.logger.Info("shutting down on context done")
+		case <-stalled:
+			en.logger.Error("shutting down for restart after stalling")
 		}
 
-		// Wait up to 5 seconds for the Consumer to finish processing.
+		// Closing the Consumer stops fetching and lets the partition consumers finish the records
+		// they've already been handed. Wait for that, and for every in-flight event to be
+		// produced, before closing the producer, up to the drain timeout.
 		close(shutdownConsumer)
+		drained := make(chan struct{})
+		go func() {
+			<-consumerShutdown
+			en.inFlight.Wait()
+			close(drained)
+		}()
 		select {
-		case <-consumerShutdown:
-			en.logger.Info("Consumer finished processing")
-		case <-time.After(5 * time.Second):
-			en.logger.Warn("Consumer did not finish processing in time, forcing shutdown")
+		case <-drained:
+			en.logger.Info("Consumer finished processing and in-flight events drained")
+		case <-time.After(en.drainTimeout):
+			en.logger.Warn("in-flight events did not drain in time, forcing shutdown", "in_flight", en.inFlightCount.Load(), "drain_timeout", en.drainTimeout)
 		}
 
+		cancelAPI()
+		<-apiDone
+
 		// Flush the producer to ensure all messages are sent.
 		en.producer.Close()
 	}()
 
 	<-quit
 	en.logger.Info("graceful shutdown complete")
-	return nil
+
+	select {
+	case <-stalled:
+		return ErrStalled
+	default:
+		return nil
+	}
 }
 
 type GenericResult struct {
@@ -280,7 +696,15 @@ type GenericResult struct {
 	ErrString string
 }
 
-func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent) error {
+func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent) (err error) {
+	en.inFlight.Add(1)
+	metrics.InFlightEvents.Set(float64(en.inFlightCount.Add(1)))
+	en.lastConsumed.Store(time.Now().UnixNano())
+	defer func() {
+		metrics.InFlightEvents.Set(float64(en.inFlightCount.Add(-1)))
+		en.inFlight.Done()
+	}()
+
 	if event.Commit == nil {
 		return nil
 	}
@@ -290,8 +714,65 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 		return nil
 	}
 
-	logger := en.logger.With("did", event.Did, "collection", event.Commit.Collection, "rkey", event.Commit.Rkey, "operation", event.Commit.Operation.String())
+	logger := en.eventLogger("did", event.Did, "collection", event.Commit.Collection, "rkey", event.Commit.Rkey, "operation", event.Commit.Operation.String())
+
+	if en.recentEvents != nil {
+		dedupeKey := fmt.Sprintf("%s|%s|%s", event.Did, event.Commit.Cid, event.Commit.Operation.String())
+		if en.recentEvents.Contains(dedupeKey) {
+			logger.Info("dropping duplicate event")
+			metrics.DuplicateEvents.WithLabelValues(event.Commit.Collection).Inc()
+			return nil
+		}
+		en.recentEvents.Add(dedupeKey, struct{}{})
+		// Let a redelivery through if we fail to handle this one.
+		defer func() {
+			if err != nil {
+				en.recentEvents.Remove(dedupeKey)
+			}
+		}()
+	}
+
+	modEvt, hasImages, err := en.enrichEvent(ctx, logger, event, true)
+	if err != nil {
+		return err
+	}
+
+	outOspreyEvt, err := modResultsToOspreyEvent(modEvt)
+	if err != nil {
+		return fmt.Errorf("failed to create OspreyInputEvent from ModerationEnrichedFirehoseRecordEvent: %w", err)
+	}
+
+	// If the event is too large to produce, drop the raw third-party responses and try again. The
+	// parsed fields (classes, decisions, hashes, etc.) are kept.
+	if size := proto.Size(outOspreyEvt); size > en.maxOutputSize {
+		logger.Warn("output event exceeds max output size, trimming raw results", "size", size, "max_size", en.maxOutputSize)
+		stripRawResults(modEvt.ImageResults)
+
+		outOspreyEvt, err = modResultsToOspreyEvent(modEvt)
+		if err != nil {
+			return fmt.Errorf("failed to create OspreyInputEvent from ModerationEnrichedFirehoseRecordEvent: %w", err)
+		}
+
+		if size := proto.Size(outOspreyEvt); size > en.maxOutputSize {
+			metrics.OversizedItems.WithLabelValues("output", "dropped").Inc()
+			return fmt.Errorf("output event exceeds max output size after trimming raw results: size=%d max_size=%d", size, en.maxOutputSize)
+		}
+		metrics.OversizedItems.WithLabelValues("output", "trimmed").Inc()
+	}
+
+	if err := en.produceEvent(context.Background(), modEvt, hasImages, outOspreyEvt); err != nil {
+		return fmt.Errorf("failed to produce OspreyInputEvent: %w", err)
+	}
+
+	logger.Info("produced OspreyInputEvent")
+
+	return nil
+}
 
+// enrichEvent runs the record through all of the configured enrichers and returns the resulting
+// moderation event, along with whether the record has images. If observe is false the record is not
+// fed into stateful trackers such as velocity, which is what on-demand scans want.
+func (en *Enricher) enrichEvent(ctx context.Context, logger *slog.Logger, event *osprey.FirehoseEvent, observe bool) (*osprey.ModerationEnrichedFirehoseRecordEvent, bool, error) {
 	wg := &sync.WaitGroup{}
 	hiveResults := xsync.NewMapOf[string, *osprey.ImageDispatchResults_HiveResults]()
 	abyssResults := xsync.NewMapOf[string, *osprey.ImageDispatchResults_AbyssResults]()
@@ -300,10 +781,14 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 	prescreenResults := xsync.NewMapOf[string, *osprey.ImageDispatchResults_PrescreenResults]()
 	nciiResults := xsync.NewMapOf[string, *osprey.ImageDispatchResults_NciiResults]()
 	flaggedImageResults := xsync.NewMapOf[string, *osprey.ImageDispatchResults_FlaggedResults]()
+	sightingResults := xsync.NewMapOf[string, *osprey.ImageDispatchResults_Sightings]()
 	var ozoneRepoViewDetail []byte
 	var profileView []byte
+	var profile *bsky.ActorDefs_ProfileViewDetailed
 	var didDoc []byte
+	var identityDoc *identity.DIDDocument
 	var didAuditLog []byte
+	var auditLog *did.AuditLog
 
 	dispatchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -311,7 +796,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 	start := time.Now()
 
 	// Dispatch to Ozone for RepoViewDetail
-	if en.ozoneClient != nil {
+	if en.processorEnabled(processorOzone) {
 		wg.Go(func() {
 			logger := logger.With("processor", "ozone")
 
@@ -333,7 +818,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 	}
 
 	// Dispatch to AppView for ProfileView
-	if en.appviewClient != nil {
+	if en.processorEnabled(processorAppview) {
 		wg.Go(func() {
 			logger := logger.With("processor", "appview")
 
@@ -350,12 +835,13 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 			}
 
 			profileView = respBody
+			profile = prof
 			logger.Info("successfully fetched ProfileView from AppView")
 		})
 	}
 
 	// Dispatch to DID Resolver for DID Doc
-	if en.didClient != nil {
+	if en.processorEnabled(processorDid) {
 		wg.Go(func() {
 			logger := logger.With("processor", "did")
 
@@ -372,6 +858,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 			}
 
 			didDoc = respBody
+			identityDoc = doc
 			logger.Info("successfully fetched DID Document from DID resolver")
 		})
 
@@ -397,6 +884,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 				}
 
 				didAuditLog = respBody
+				auditLog = log
 				logger.Info("successfully fetched DID audit log from DID resolver")
 			})
 		}
@@ -405,35 +893,128 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 	// Download all the images while other things are processing
 	rec, err := atdata.UnmarshalJSON(event.Commit.Record)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal commit record: %w", err)
+		return nil, false, fmt.Errorf("failed to unmarshal commit record: %w", err)
+	}
+
+	recText, err := extractRecordText(event.Commit.Collection, event.Commit.Record)
+	if err != nil {
+		logger.Error("failed to extract record text", "err", err)
+		recText = &recordText{}
 	}
 
 	blobs := atdata.ExtractBlobs(rec)
 	imageCids := []string{}
 	videoCids := []string{}
+	gifCids := map[string]bool{}
+
+	// Declared mimetypes are whatever the client says, so route by the actual content when we can.
+	var sniffed map[string]string
+	var blobTypeMismatches []*osprey.BlobTypeMismatch
+	if en.sniffBlobs && len(blobs) > 0 {
+		sniffed, blobTypeMismatches = en.sniffBlobTypes(ctx, logger, event.Did, blobs)
+	}
 
 	for _, blob := range blobs {
-		mimeType := strings.ToLower(blob.MimeType)
-		if strings.HasPrefix(mimeType, "image/") {
-			imageCids = append(imageCids, blob.Ref.String())
-		} else if strings.HasPrefix(mimeType, "video/") {
-			videoCids = append(videoCids, blob.Ref.String())
+		cid := blob.Ref.String()
+		mimeType, ok := sniffed[cid]
+		if !ok {
+			mimeType = strings.ToLower(blob.MimeType)
+		}
+		kind := mediaKind(mimeType)
+		metrics.BlobSizeBytes.WithLabelValues(kind).Observe(float64(blob.Size))
+		switch kind {
+		case mediaKindImage:
+			imageCids = append(imageCids, cid)
+			if mimeType == "image/gif" {
+				gifCids[cid] = true
+			}
+		case mediaKindVideo:
+			videoCids = append(videoCids, cid)
+		}
+	}
+	// Link card thumbnails are always scanned, even if the blob wasn't picked up above.
+	if card := recText.LinkCard; card != nil && card.ThumbCid != "" {
+		extracted := slices.ContainsFunc(blobs, func(b atdata.Blob) bool { return b.Ref.String() == card.ThumbCid })
+		if !extracted {
+			imageCids = append(imageCids, card.ThumbCid)
+		}
+	}
+	metrics.ImagesPerEvent.WithLabelValues(event.Commit.Collection).Observe(float64(len(imageCids)))
+
+	// Only creates count towards velocity, an edited post isn't a new post.
+	var velocityFeatures *osprey.VelocityFeatures
+	if observe && en.velocityTracker != nil && event.Commit.Operation == osprey.CommitOperation_COMMIT_OPERATION_CREATE {
+		obs, err := velocity.ObservationFromRecord(event.Commit.Collection, event.Commit.Record, len(imageCids))
+		if err != nil {
+			logger.Error("failed to build velocity observation", "err", err)
+		} else {
+			velocityFeatures = en.velocityTracker.Observe(event.Did, time.Now(), obs)
 		}
 	}
 
 	images := xsync.NewMapOf[string, []byte]()
+	animations := xsync.NewMapOf[string, *animation]()
+	storeImage := func(key string, bytes []byte) {
+		if en.maxBlobSize > 0 && len(bytes) > en.maxBlobSize {
+			fitted, err := fitImage(bytes, en.maxBlobSize)
+			if err != nil {
+				logger.Warn("skipping oversized image", "cid", key, "size", len(bytes), "max_size", en.maxBlobSize, "err", err)
+				metrics.OversizedItems.WithLabelValues("image", "skipped").Inc()
+				return
+			}
+			logger.Info("downscaled oversized image", "cid", key, "size", len(bytes), "downscaled_size", len(fitted))
+			metrics.OversizedItems.WithLabelValues("image", "downscaled").Inc()
+			bytes = fitted
+		}
+		images.Store(key, bytes)
+	}
+	// sampleAnimation fetches the original GIF from the PDS, since the CDN only serves a still
+	// thumbnail, and stores each of its sampled frames to be processed separately. It returns false
+	// if the image should be processed as a still image instead.
+	sampleAnimation := func(cid string) bool {
+		blob, err := en.cdn.GetBlob(ctx, event.Did, cid)
+		if err != nil {
+			logger.Warn("failed to fetch gif blob, processing the CDN thumbnail instead", "cid", cid, "err", err)
+			return false
+		}
+		frames, frameCount, err := sampleFrames(blob, en.animatedFrameSamples)
+		if err != nil {
+			logger.Warn("failed to sample animated image frames, processing as a still image", "cid", cid, "err", err)
+			return false
+		}
+		if len(frames) == 0 {
+			return false
+		}
+
+		logger.Info("sampled animated image", "cid", cid, "frame_count", frameCount, "sampled_frames", len(frames))
+		anim := &animation{frameCount: frameCount}
+		for i, frame := range frames {
+			key := frameKey(cid, i)
+			anim.frameKeys = append(anim.frameKeys, key)
+			storeImage(key, frame)
+		}
+		animations.Store(cid, anim)
+		return true
+	}
+	fetchImage := func(cid string) {
+		if gifCids[cid] && en.animatedFrameSamples > 0 && sampleAnimation(cid) {
+			return
+		}
+
+		bytes, err := en.cdn.GetImageBytes(ctx, event.Did, cid)
+		if err != nil {
+			logger.Error("failed to fetch image bytes", "did", event.Did, "cid", cid, "err", err)
+			return
+		}
+		storeImage(cid, bytes)
+	}
 	wg.Go(func() {
 		var imgWg sync.WaitGroup
 		for _, cid := range imageCids {
 			imgWg.Add(1)
 			func(cid string) {
 				defer imgWg.Done()
-				bytes, err := en.cdn.GetImageBytes(ctx, event.Did, cid)
-				if err != nil {
-					logger.Error("failed to fetch image bytes", "did", event.Did, "cid", cid, "err", err)
-					return
-				}
-				images.Store(cid, bytes)
+				fetchImage(cid)
 			}(cid)
 		}
 		imgWg.Wait()
@@ -442,7 +1023,22 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 	// Wait for all of the above to complete
 	wg.Wait()
 
-	// Dispatch images to enabled enrichers.
+	// Labelers, feed generators, lists and starter packs get extra context, which may reference
+	// avatars that aren't part of the record itself.
+	collectionContext, extraImageCids := en.collectionContext(ctx, logger, event, profile, identityDoc)
+	for _, cid := range extraImageCids {
+		_, fetched := images.Load(cid)
+		_, animated := animations.Load(cid)
+		if !fetched && !animated {
+			fetchImage(cid)
+		}
+	}
+
+	// Dispatch images to enabled enrichers. Third-party processors only ever receive the image
+	// bytes (plus the DID and CID for our own services), never record fields or text, so there's
+	// nothing from the record to redact before it leaves. The Hive client's tests hold it to that.
+	// Anything that starts sending record text to an external service needs to strip the fields
+	// that shouldn't go there first.
 	images.Range(func(cid string, img []byte) bool {
 		wg.Add(1)
 		go func(img []byte) {
@@ -450,6 +1046,10 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 
 			// Send to the prescreen service first, if we get back "true", send to Hive
 			if en.prescreenClient != nil {
+				// A disabled prescreen keeps the gate to Hive closed rather than sending it everything.
+				if !en.processorEnabled(processorPrescreen) {
+					return
+				}
 				logger := logger.With("processor", "prescreen", "image_cid", cid)
 				logger.Info("dispatching image to prescreen")
 				decision, res, err := en.prescreenClient.Scan(dispatchCtx, event.Did, img)
@@ -458,25 +1058,50 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 					prescreenResults.Store(cid, &osprey.ImageDispatchResults_PrescreenResults{
 						Error: asProtoErr(err),
 					})
-					return
-				}
-				logger.Info("prescreen scan successful", "decision", decision)
 
-				prescreenResults.Store(cid, &osprey.ImageDispatchResults_PrescreenResults{
-					Raw:      res,
-					Decision: &decision,
-				})
+					// Failing open sends the image to Hive as if prescreen had flagged it.
+					if !en.prescreenFailOpen || !en.processorEnabled(processorHive) {
+						metrics.PrescreenFailures.WithLabelValues("skipped").Inc()
+						return
+					}
+					logger.Warn("prescreen failed open, sending image to Hive")
+					metrics.PrescreenFailures.WithLabelValues("sent_to_hive").Inc()
+				} else {
+					logger.Info("prescreen scan successful", "decision", decision)
 
-				if decision == "sfw" {
-					return
+					// Send a small sample of "sfw" images to Hive anyway so we can measure the
+					// prescreen false-negative rate.
+					qaSampled := decision == "sfw" && en.processorEnabled(processorHive) && rand.Float64() < en.prescreenQASampleRate
+
+					prescreenResults.Store(cid, &osprey.ImageDispatchResults_PrescreenResults{
+						Raw:       res,
+						Decision:  &decision,
+						QaSampled: &qaSampled,
+					})
+
+					if decision == "sfw" && !qaSampled {
+						return
+					}
+					if qaSampled {
+						logger.Info("sampling sfw image for Hive QA")
+					}
 				}
 			}
 
 			// If prescreen flags as NSFW, forward to Hive for more detailed analysis.
-			if en.hiveClient != nil {
+			if en.processorEnabled(processorHive) {
 				logger := logger.With("processor", "hive", "image_cid", cid)
+				if en.hiveBudget != nil && !en.hiveBudget.allow(dispatchCtx) {
+					logger.Warn("hive budget exhausted, skipping image")
+					budgetSkipped := true
+					hiveResults.Store(cid, &osprey.ImageDispatchResults_HiveResults{
+						BudgetSkipped: &budgetSkipped,
+					})
+					return
+				}
 				logger.Info("dispatching image")
 				res, classes, err := en.hiveClient.Scan(dispatchCtx, img)
+				en.recordPaidCall(paidServiceHive, event.Commit.Collection, err)
 				if err != nil {
 					logger.Error("failed to scan image", "err", err)
 					hiveResults.Store(cid, &osprey.ImageDispatchResults_HiveResults{
@@ -492,13 +1117,14 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 			}
 		}(img)
 
-		if en.abyssClient != nil {
+		if en.processorEnabled(processorAbyss) {
 			wg.Add(1)
 			go func(img []byte) {
 				defer wg.Done()
 				logger := logger.With("processor", "abyss", "image_cid", cid)
 				logger.Info("dispatching image")
 				res, isAbuseMatch, err := en.abyssClient.Scan(dispatchCtx, event.Did, img)
+				en.recordPaidCall(paidServiceAbyss, event.Commit.Collection, err)
 				if err != nil {
 					logger.Error("failed to scan image", "err", err)
 					abyssResults.Store(cid, &osprey.ImageDispatchResults_AbyssResults{
@@ -514,7 +1140,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 			}(img)
 		}
 
-		if en.retinaOcrClient != nil {
+		if en.processorEnabled(processorRetinaOcr) {
 			wg.Add(1)
 			go func(img []byte) {
 				defer wg.Done()
@@ -536,7 +1162,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 			}(img)
 		}
 
-		if en.retinaHashClient != nil {
+		if en.processorEnabled(processorRetinaHash) {
 			wg.Add(1)
 			go func(img []byte) {
 				defer wg.Done()
@@ -557,13 +1183,18 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 					QualityTooLow: &resObj.QualityTooLow,
 				})
 
+				// Like velocity, only new records count as sightings.
+				if observe && en.campaignIndex != nil && resObj.Hash != "" && !resObj.QualityTooLow && event.Commit.Operation == osprey.CommitOperation_COMMIT_OPERATION_CREATE {
+					sightingResults.Store(cid, en.campaignIndex.Observe(resObj.Hash, event.Did, time.Now()))
+				}
+
 				// If we got a hash back and the quality was not too low, we want to check for any ncii etc. matches
 				if resObj.Hash != "" && !resObj.QualityTooLow {
 					// Create a waitgroup to process vector lookups
 					var vectorWg sync.WaitGroup
 
 					// Check for ncii matches if there is a client
-					if en.nciiClient != nil {
+					if en.processorEnabled(processorNcii) {
 						vectorWg.Add(1)
 						go func() {
 							defer vectorWg.Done()
@@ -585,7 +1216,7 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 						}()
 					}
 
-					if en.flaggedImageClient != nil {
+					if en.processorEnabled(processorFlaggedImage) {
 						vectorWg.Add(1)
 						go func() {
 							defer vectorWg.Done()
@@ -639,26 +1270,158 @@ func (en *Enricher) handleEvent(ctx context.Context, event *osprey.FirehoseEvent
 		result.Prescreen, _ = prescreenResults.Load(cid)
 		result.Ncii, _ = nciiResults.Load(cid)
 		result.Flagged, _ = flaggedImageResults.Load(cid)
+		result.Sightings, _ = sightingResults.Load(cid)
 		imageResults[cid] = result
 		return true
 	})
 
-	logger.Info("record fully processed", "duration_seconds", time.Since(start).Seconds())
+	animations.Range(func(cid string, anim *animation) bool {
+		collapseAnimation(imageResults, cid, anim)
+		return true
+	})
 
-	modEvt := evtToModerationResults(event, ozoneRepoViewDetail, profileView, didDoc, didAuditLog)
+	if card := recText.LinkCard; card != nil {
+		if res, ok := imageResults[card.ThumbCid]; ok {
+			res.LinkCard = &osprey.ImageDispatchResults_LinkCard{
+				Uri:   card.Uri,
+				Title: card.Title,
+			}
+		}
+	}
 
-	outOspreyEvt, err := modResultsToOspreyEvent(modEvt)
-	if err != nil {
-		return fmt.Errorf("failed to create OspreyInputEvent from ModerationEnrichedFirehoseRecordEvent: %w", err)
+	var termListMatches []*osprey.TermListMatch
+	if en.termListMatcher != nil {
+		texts := &termlist.Texts{
+			Langs: recText.Langs,
+			Fields: map[string][]string{
+				termlist.FieldPostText:    {recText.PostText},
+				termlist.FieldProfileText: {recText.DisplayName, recText.Description},
+				termlist.FieldAltText:     recText.AltTexts,
+			},
+		}
+		if profile != nil {
+			if profile.DisplayName != nil {
+				texts.Fields[termlist.FieldProfileText] = append(texts.Fields[termlist.FieldProfileText], *profile.DisplayName)
+			}
+			if profile.Description != nil {
+				texts.Fields[termlist.FieldProfileText] = append(texts.Fields[termlist.FieldProfileText], *profile.Description)
+			}
+		}
+		retinaOcrResults.Range(func(_ string, res *osprey.ImageDispatchResults_RetinaResults) bool {
+			if res.Text != nil {
+				texts.Fields[termlist.FieldOcrText] = append(texts.Fields[termlist.FieldOcrText], *res.Text)
+			}
+			return true
+		})
+
+		for _, match := range en.termListMatcher.Match(texts) {
+			termListMatches = append(termListMatches, &osprey.TermListMatch{
+				List:     match.List,
+				Language: match.Language,
+				Severity: match.Severity,
+				Fields:   match.Fields,
+			})
+		}
 	}
 
-	if err := en.producer.ProduceAsync(context.Background(), event.Did, outOspreyEvt, nil); err != nil {
-		return fmt.Errorf("failed to produce OspreyInputEvent: %w", err)
+	var impersonationMatches []*osprey.ImpersonationMatch
+	if en.impersonationDetector != nil {
+		handle, displayName := identityForImpersonation(recText, profile, identityDoc)
+		for _, match := range en.impersonationDetector.Check(event.Did, handle, displayName) {
+			impersonationMatches = append(impersonationMatches, &osprey.ImpersonationMatch{
+				ProtectedDid:    match.ProtectedDid,
+				ProtectedHandle: match.ProtectedHandle,
+				Field:           match.Field,
+				Score:           match.Score,
+			})
+		}
 	}
 
-	logger.Info("produced OspreyInputEvent")
+	logger.Info("record fully processed", "duration_seconds", time.Since(start).Seconds())
 
-	return nil
+	modEvt := evtToModerationResults(event, imageResults, ozoneRepoViewDetail, profileView, didDoc, didAuditLog)
+	modEvt.Velocity = velocityFeatures
+	modEvt.TermListMatches = termListMatches
+	modEvt.ImpersonationMatches = impersonationMatches
+	modEvt.CollectionContext = collectionContext
+	modEvt.BlobTypeMismatches = blobTypeMismatches
+	finished := time.Now()
+	modEvt.Pipeline = &osprey.PipelineTimes{
+		EventTimestamp:       event.Timestamp,
+		EnrichmentStartedAt:  timestamppb.New(start),
+		EnrichmentFinishedAt: timestamppb.New(finished),
+	}
+	if event.Timestamp != nil {
+		modEvt.Pipeline.StalenessMs = finished.Sub(event.Timestamp.AsTime()).Milliseconds()
+	}
+	if en.watchlist != nil {
+		modEvt.Watchlists = en.watchlist.Lists(event.Did)
+	}
+	if en.labelCache != nil {
+		uri := fmt.Sprintf("at://%s/%s/%s", event.Did, event.Commit.Collection, event.Commit.Rkey)
+		for _, label := range en.labelCache.Lookup(time.Now(), event.Did, uri) {
+			modEvt.ExistingLabels = append(modEvt.ExistingLabels, &osprey.ExistingLabel{
+				Uri:       label.Uri,
+				Val:       label.Val,
+				CreatedAt: timestamppb.New(label.CreatedAt),
+			})
+		}
+	}
+	if auditLog != nil {
+		modEvt.Identity = auditLogToIdentityFeatures(auditLog, time.Now())
+	}
+	if en.pdsReputation != nil && identityDoc != nil {
+		if features := en.pdsReputation.Features(identityDoc, time.Now()); features != nil {
+			modEvt.Pds = &osprey.PdsFeatures{
+				Host:           features.Host,
+				BlueskyHosted:  features.BlueskyHosted,
+				HostFirstSeen:  timestamppb.New(features.HostFirstSeen),
+				HostAgeSeconds: int64(time.Since(features.HostFirstSeen).Seconds()),
+				SuspiciousHost: features.SuspiciousHost,
+			}
+		}
+	}
+
+	return modEvt, len(imageCids) > 0, nil
+}
+
+// identityForImpersonation picks the most current handle and display name we have for the actor.
+// A profile record being written takes precedence over the AppView's view of the profile.
+func identityForImpersonation(recText *recordText, profile *bsky.ActorDefs_ProfileViewDetailed, doc *identity.DIDDocument) (string, string) {
+	var handle, displayName string
+	if profile != nil {
+		handle = profile.Handle
+		if profile.DisplayName != nil {
+			displayName = *profile.DisplayName
+		}
+	}
+	if doc != nil {
+		for _, aka := range doc.AlsoKnownAs {
+			if h, ok := strings.CutPrefix(aka, "at://"); ok {
+				handle = h
+				break
+			}
+		}
+	}
+	if recText.DisplayName != "" {
+		displayName = recText.DisplayName
+	}
+	return handle, displayName
+}
+
+func auditLogToIdentityFeatures(log *did.AuditLog, now time.Time) *osprey.IdentityFeatures {
+	churn := log.Churn(now)
+	features := &osprey.IdentityFeatures{
+		OperationCount:      int64(churn.Operations),
+		RecentHandleChanges: int64(churn.HandleChangesLast30d),
+		PdsMigrations:       int64(churn.PDSMigrations),
+		RotationKeyChanges:  int64(churn.RotationKeyChanges),
+	}
+	if !churn.CreatedAt.IsZero() {
+		features.AccountCreatedAt = timestamppb.New(churn.CreatedAt)
+		features.AccountAgeSeconds = int64(now.Sub(churn.CreatedAt).Seconds())
+	}
+	return features
 }
 
 func asProtoErr(err error) *string {
@@ -671,6 +1434,7 @@ func asProtoErr(err error) *string {
 
 func evtToModerationResults(
 	event *osprey.FirehoseEvent,
+	imageResults map[string]*osprey.ImageDispatchResults,
 	ozoneRepoViewDetail []byte,
 	profileView []byte,
 	didDoc []byte,
@@ -684,6 +1448,7 @@ func evtToModerationResults(
 		Cid:                 event.Commit.Cid,
 		Operation:           event.Commit.Operation,
 		Record:              event.Commit.Record,
+		ImageResults:        imageResults,
 		OzoneRepoViewDetail: ozoneRepoViewDetail,
 		ProfileView:         profileView,
 		DidDoc:              didDoc,
@@ -691,6 +1456,30 @@ func evtToModerationResults(
 	}
 }
 
+// stripRawResults clears the raw response bodies from each processor's results.
+func stripRawResults(imageResults map[string]*osprey.ImageDispatchResults) {
+	for _, res := range imageResults {
+		if res.Abyss != nil {
+			res.Abyss.Raw = nil
+		}
+		if res.Hive != nil {
+			res.Hive.Raw = nil
+		}
+		if res.Retina != nil {
+			res.Retina.Raw = nil
+		}
+		if res.RetinaHash != nil {
+			res.RetinaHash.Raw = nil
+		}
+		if res.Prescreen != nil {
+			res.Prescreen.Raw = nil
+		}
+		if res.Ncii != nil {
+			res.Ncii.Raw = nil
+		}
+	}
+}
+
 func modResultsToOspreyEvent(
 	event *osprey.ModerationEnrichedFirehoseRecordEvent,
 ) (*osprey.OspreyInputEvent, error) {
@@ -0,0 +1,95 @@
+package enricher
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bluesky-social/indigo/atproto/atdata"
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+)
+
+// Media kinds that blobs are routed by.
+const (
+	mediaKindImage = "image"
+	mediaKindVideo = "video"
+	mediaKindOther = "other"
+)
+
+func mediaKind(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return mediaKindImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return mediaKindVideo
+	default:
+		return mediaKindOther
+	}
+}
+
+// sniffMimeType returns the mimetype of a blob based on its leading bytes. On top of what
+// http.DetectContentType recognizes, ISO base media files are told apart by their major brand,
+// since AVIF and HEIC images share a container format with MP4 and QuickTime video.
+func sniffMimeType(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		switch string(head[8:12]) {
+		case "avif", "avis":
+			return "image/avif"
+		case "heic", "heix", "heim", "heis", "mif1", "msf1":
+			return "image/heic"
+		case "qt  ":
+			return "video/quicktime"
+		default:
+			return "video/mp4"
+		}
+	}
+	mimeType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return mimeType
+}
+
+// sniffBlobTypes fetches the head of each blob from the owning PDS and returns the sniffed
+// mimetype of each one by CID, along with the blobs whose sniffed kind differs from the kind of
+// their declared mimetype. Blobs that couldn't be fetched are left out, and should be routed by
+// their declared mimetype.
+func (en *Enricher) sniffBlobTypes(ctx context.Context, logger *slog.Logger, did string, blobs []atdata.Blob) (map[string]string, []*osprey.BlobTypeMismatch) {
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		sniffed    = make(map[string]string, len(blobs))
+		mismatches []*osprey.BlobTypeMismatch
+	)
+	for _, blob := range blobs {
+		wg.Go(func() {
+			cid := blob.Ref.String()
+			head, err := en.cdn.GetBlobHead(ctx, did, cid)
+			if err != nil {
+				logger.Warn("failed to fetch blob for sniffing, routing by declared mimetype", "cid", cid, "err", err)
+				return
+			}
+			if len(head) == 0 {
+				return
+			}
+
+			declared := strings.ToLower(blob.MimeType)
+			actual := sniffMimeType(head)
+
+			mu.Lock()
+			defer mu.Unlock()
+			sniffed[cid] = actual
+			if declaredKind, actualKind := mediaKind(declared), mediaKind(actual); declaredKind != actualKind {
+				logger.Info("blob content does not match declared mimetype", "cid", cid, "declared", declared, "sniffed", actual)
+				metrics.BlobTypeMismatches.WithLabelValues(declaredKind, actualKind).Inc()
+				mismatches = append(mismatches, &osprey.BlobTypeMismatch{
+					Cid:              cid,
+					DeclaredMimeType: declared,
+					SniffedMimeType:  actual,
+				})
+			}
+		})
+	}
+	wg.Wait()
+	return sniffed, mismatches
+}
@@ -0,0 +1,81 @@
+package enricher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+)
+
+// recordText is the human-written text pulled out of a record, for processors that match on it.
+type recordText struct {
+	PostText    string
+	Langs       []string
+	AltTexts    []string
+	DisplayName string
+	Description string
+	// LinkCard is the post's external link embed, if it has one.
+	LinkCard *linkCard
+}
+
+// linkCard is an app.bsky.embed.external link preview. Scam links are dressed up with misleading
+// thumbnails, so the thumbnail is scanned as an image and its results are tied back to the link.
+type linkCard struct {
+	Uri      string
+	Title    string
+	ThumbCid string
+}
+
+// extractRecordText pulls the text fields we care about out of posts and profiles. Other
+// collections return an empty recordText.
+func extractRecordText(collection string, record []byte) (*recordText, error) {
+	text := &recordText{}
+
+	switch collection {
+	case "app.bsky.feed.post":
+		var post bsky.FeedPost
+		if err := json.Unmarshal(record, &post); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal post record: %w", err)
+		}
+		text.PostText = post.Text
+		text.Langs = post.Langs
+
+		if post.Embed != nil {
+			images := post.Embed.EmbedImages
+			external := post.Embed.EmbedExternal
+			if post.Embed.EmbedRecordWithMedia != nil && post.Embed.EmbedRecordWithMedia.Media != nil {
+				images = post.Embed.EmbedRecordWithMedia.Media.EmbedImages
+				external = post.Embed.EmbedRecordWithMedia.Media.EmbedExternal
+			}
+			if external != nil && external.External != nil {
+				text.LinkCard = &linkCard{
+					Uri:   external.External.Uri,
+					Title: external.External.Title,
+				}
+				if external.External.Thumb != nil {
+					text.LinkCard.ThumbCid = external.External.Thumb.Ref.String()
+				}
+			}
+			if images != nil {
+				for _, img := range images.Images {
+					if img != nil && img.Alt != "" {
+						text.AltTexts = append(text.AltTexts, img.Alt)
+					}
+				}
+			}
+		}
+	case "app.bsky.actor.profile":
+		var profile bsky.ActorProfile
+		if err := json.Unmarshal(record, &profile); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal profile record: %w", err)
+		}
+		if profile.DisplayName != nil {
+			text.DisplayName = *profile.DisplayName
+		}
+		if profile.Description != nil {
+			text.Description = *profile.Description
+		}
+	}
+
+	return text, nil
+}
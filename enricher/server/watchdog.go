@@ -0,0 +1,67 @@
+package enricher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/bluesky-social/osprey-atproto/enricher/metrics"
+)
+
+// ErrStalled is returned from Run when the stall watchdog shut the enricher down so it can be
+// restarted.
+var ErrStalled = errors.New("no events produced within the stall timeout")
+
+// runWatchdog checks that events are still being produced until the context is cancelled. The
+// Kafka client has been seen to wedge without reporting an error, leaving the consumer looking
+// healthy while nothing flows, so the only reliable signal is output stopping. If stall exit is
+// enabled, stalled is closed once the enricher has gone stallTimeout without producing anything.
+func (en *Enricher) runWatchdog(ctx context.Context, stalled chan<- struct{}) {
+	ticker := time.NewTicker(max(en.stallTimeout/4, 10*time.Second))
+	defer ticker.Stop()
+
+	isStalled := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		sinceProduced := now.Sub(time.Unix(0, en.lastProduced.Load()))
+		sinceConsumed := now.Sub(time.Unix(0, en.lastConsumed.Load()))
+		metrics.SecondsSinceLastProduce.Set(sinceProduced.Seconds())
+
+		if sinceProduced < en.stallTimeout {
+			if isStalled {
+				en.logger.Info("events are being produced again, no longer stalled")
+				metrics.Stalled.Set(0)
+				isStalled = false
+			}
+			continue
+		}
+
+		if !isStalled {
+			en.logger.Error("no events produced within the stall timeout",
+				"since_last_produce", sinceProduced,
+				"since_last_consume", sinceConsumed,
+				"in_flight", en.inFlightCount.Load(),
+				"stall_timeout", en.stallTimeout,
+			)
+			metrics.Stalled.Set(1)
+			isStalled = true
+		}
+
+		if en.stallExit {
+			// A wedged client can hang the graceful shutdown too, so make sure we exit regardless.
+			time.AfterFunc(en.drainTimeout+time.Minute, func() {
+				en.logger.Error("graceful shutdown after stall did not complete, exiting")
+				os.Exit(1)
+			})
+			close(stalled)
+			return
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package termlist
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bluesky-social/go-util/pkg/robusthttp"
+	"github.com/carlmjohnson/versioninfo"
+)
+
+// Text fields that lists are matched against.
+const (
+	FieldPostText    = "post_text"
+	FieldProfileText = "profile_text"
+	FieldAltText     = "alt_text"
+	FieldOcrText     = "ocr_text"
+)
+
+// ListFile is the on-disk format for term lists.
+type ListFile struct {
+	Lists []ListDef `json:"lists"`
+}
+
+// ListDef defines a single named list. Keywords are matched case-insensitively on word boundaries,
+// Regexes are used as-is.
+type ListDef struct {
+	Name string `json:"name"`
+	// Language restricts matching of post text to posts tagged with this language. Posts without
+	// language tags and non-post fields are always matched. Empty matches all languages.
+	Language string   `json:"language,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+	Regexes  []string `json:"regexes,omitempty"`
+}
+
+type compiledList struct {
+	def      ListDef
+	patterns []*regexp.Regexp
+}
+
+// Texts are the fields of a single event to match against.
+type Texts struct {
+	Langs  []string
+	Fields map[string][]string
+}
+
+// Match is a list that matched, along with the fields it matched in.
+type Match struct {
+	List     string
+	Language string
+	Severity string
+	Fields   []string
+}
+
+// Matcher holds the currently loaded lists and reloads them when the source changes.
+type Matcher struct {
+	logger         *slog.Logger
+	source         string
+	reloadInterval time.Duration
+	client         *http.Client
+
+	lists    atomic.Pointer[[]*compiledList]
+	lastHash [sha256.Size]byte
+}
+
+type MatcherArgs struct {
+	Logger *slog.Logger
+	// Source is a local file path or an http(s) URL, e.g. an object storage URL.
+	Source         string
+	ReloadInterval time.Duration
+}
+
+func NewMatcher(ctx context.Context, args *MatcherArgs) (*Matcher, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	if args.ReloadInterval == 0 {
+		args.ReloadInterval = time.Minute
+	}
+
+	m := &Matcher{
+		logger:         args.Logger,
+		source:         args.Source,
+		reloadInterval: args.ReloadInterval,
+		client:         robusthttp.NewClient(),
+	}
+
+	if _, err := m.reload(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load term lists: %w", err)
+	}
+
+	return m, nil
+}
+
+// Run polls the source for changes until the context is cancelled. Failed reloads keep the
+// previously loaded lists.
+func (m *Matcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := m.reload(ctx)
+			if err != nil {
+				m.logger.Error("failed to reload term lists", "source", m.source, "err", err)
+				continue
+			}
+			if changed {
+				m.logger.Info("reloaded term lists", "source", m.source, "lists", len(*m.lists.Load()))
+			}
+		}
+	}
+}
+
+func (m *Matcher) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(m.source, "http://") && !strings.HasPrefix(m.source, "https://") {
+		return os.ReadFile(m.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", m.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "tango-enricher/"+versioninfo.Short())
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed statusCode=%d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func (m *Matcher) reload(ctx context.Context) (bool, error) {
+	b, err := m.fetch(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha256.Sum256(b)
+	if m.lists.Load() != nil && bytes.Equal(hash[:], m.lastHash[:]) {
+		return false, nil
+	}
+
+	var file ListFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return false, fmt.Errorf("failed to unmarshal term lists: %w", err)
+	}
+
+	lists := make([]*compiledList, 0, len(file.Lists))
+	for _, def := range file.Lists {
+		list, err := compileList(def)
+		if err != nil {
+			return false, err
+		}
+		lists = append(lists, list)
+	}
+
+	m.lists.Store(&lists)
+	m.lastHash = hash
+	return true, nil
+}
+
+func compileList(def ListDef) (*compiledList, error) {
+	list := &compiledList{def: def}
+
+	if len(def.Keywords) > 0 {
+		quoted := make([]string, len(def.Keywords))
+		for i, kw := range def.Keywords {
+			quoted[i] = regexp.QuoteMeta(kw)
+		}
+		re, err := regexp.Compile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile keywords for list %q: %w", def.Name, err)
+		}
+		list.patterns = append(list.patterns, re)
+	}
+
+	for _, expr := range def.Regexes {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regex %q for list %q: %w", expr, def.Name, err)
+		}
+		list.patterns = append(list.patterns, re)
+	}
+
+	return list, nil
+}
+
+// Match returns every list that matched at least one of the given fields.
+func (m *Matcher) Match(texts *Texts) []*Match {
+	lists := m.lists.Load()
+	if lists == nil {
+		return nil
+	}
+
+	var matches []*Match
+	for _, list := range *lists {
+		var fields []string
+		for field, values := range texts.Fields {
+			if field == FieldPostText && !list.appliesToLangs(texts.Langs) {
+				continue
+			}
+			if list.matchesAny(values) {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		slices.Sort(fields)
+		matches = append(matches, &Match{
+			List:     list.def.Name,
+			Language: list.def.Language,
+			Severity: list.def.Severity,
+			Fields:   fields,
+		})
+	}
+
+	return matches
+}
+
+func (l *compiledList) appliesToLangs(langs []string) bool {
+	if l.def.Language == "" || len(langs) == 0 {
+		return true
+	}
+	for _, lang := range langs {
+		// Match "en" against "en-US" etc.
+		if lang == l.def.Language || strings.HasPrefix(lang, l.def.Language+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *compiledList) matchesAny(values []string) bool {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		for _, re := range l.patterns {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
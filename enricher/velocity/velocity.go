@@ -0,0 +1,339 @@
+package velocity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/bsky"
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"github.com/puzpuzpuz/xsync/v3"
+)
+
+const postCollection = "app.bsky.feed.post"
+
+// maxWindow is the longest window we compute features over. Anything older than this is pruned.
+const maxWindow = time.Hour
+
+// Tracker keeps per-DID sliding window counters in memory, optionally snapshotting them to disk so
+// that a restart doesn't reset everyone's velocity to zero.
+type Tracker struct {
+	logger           *slog.Logger
+	dids             *xsync.MapOf[string, *didState]
+	snapshotPath     string
+	snapshotInterval time.Duration
+}
+
+type TrackerArgs struct {
+	Logger *slog.Logger
+	// SnapshotPath is the file state is periodically written to and restored from. Optional.
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+}
+
+// Observation is what we learned about a single record for the purposes of velocity tracking.
+type Observation struct {
+	IsPost   bool
+	Images   int
+	Mentions []string
+	Text     string
+}
+
+type didState struct {
+	mu sync.Mutex
+	// evicted is set once the state has been removed from the tracker, so an observation that
+	// loaded it just before knows to start over with a fresh one.
+	evicted  bool
+	Posts    []time.Time    `json:"posts"`
+	Images   []countEntry   `json:"images"`
+	Mentions []mentionEntry `json:"mentions"`
+	Texts    []textEntry    `json:"texts"`
+}
+
+type countEntry struct {
+	At    time.Time `json:"at"`
+	Count int       `json:"count"`
+}
+
+type mentionEntry struct {
+	At  time.Time `json:"at"`
+	Did string    `json:"did"`
+}
+
+type textEntry struct {
+	At   time.Time `json:"at"`
+	Hash uint64    `json:"hash"`
+}
+
+func NewTracker(args *TrackerArgs) (*Tracker, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	if args.SnapshotInterval == 0 {
+		args.SnapshotInterval = time.Minute
+	}
+
+	t := &Tracker{
+		logger:           args.Logger,
+		dids:             xsync.NewMapOf[string, *didState](),
+		snapshotPath:     args.SnapshotPath,
+		snapshotInterval: args.SnapshotInterval,
+	}
+
+	if t.snapshotPath != "" {
+		if err := t.loadSnapshot(); err != nil {
+			return nil, fmt.Errorf("failed to load velocity snapshot: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// ObservationFromRecord builds an Observation from a raw JSON record. Only posts contribute text
+// and mentions; image counts are taken from the blobs the caller has already extracted.
+func ObservationFromRecord(collection string, record []byte, images int) (*Observation, error) {
+	obs := &Observation{Images: images}
+	if collection != postCollection {
+		return obs, nil
+	}
+	obs.IsPost = true
+
+	var post bsky.FeedPost
+	if err := json.Unmarshal(record, &post); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal post record: %w", err)
+	}
+	obs.Text = post.Text
+
+	for _, facet := range post.Facets {
+		for _, feature := range facet.Features {
+			if feature.RichtextFacet_Mention != nil {
+				obs.Mentions = append(obs.Mentions, feature.RichtextFacet_Mention.Did)
+			}
+		}
+	}
+
+	return obs, nil
+}
+
+// Observe records the observation for the given DID and returns the resulting features, which
+// include the observation itself.
+func (t *Tracker) Observe(did string, now time.Time, obs *Observation) *osprey.VelocityFeatures {
+	var state *didState
+	for {
+		state, _ = t.dids.LoadOrCompute(did, func() *didState { return &didState{} })
+		state.mu.Lock()
+		if !state.evicted {
+			break
+		}
+		state.mu.Unlock()
+	}
+	defer state.mu.Unlock()
+
+	state.prune(now)
+
+	var textHash uint64
+	if obs.IsPost {
+		state.Posts = append(state.Posts, now)
+		for _, mention := range obs.Mentions {
+			state.Mentions = append(state.Mentions, mentionEntry{At: now, Did: mention})
+		}
+		if text := normalizeText(obs.Text); text != "" {
+			textHash = hashText(text)
+			state.Texts = append(state.Texts, textEntry{At: now, Hash: textHash})
+		}
+	}
+	if obs.Images > 0 {
+		state.Images = append(state.Images, countEntry{At: now, Count: obs.Images})
+	}
+
+	return state.features(now, textHash)
+}
+
+func (s *didState) features(now time.Time, textHash uint64) *osprey.VelocityFeatures {
+	features := &osprey.VelocityFeatures{}
+
+	minuteAgo := now.Add(-time.Minute)
+	for _, at := range s.Posts {
+		features.PostsLastHour++
+		if at.After(minuteAgo) {
+			features.PostsLastMinute++
+		}
+	}
+
+	for _, entry := range s.Images {
+		features.ImagesLastHour += int64(entry.Count)
+	}
+
+	mentioned := make(map[string]struct{}, len(s.Mentions))
+	for _, entry := range s.Mentions {
+		mentioned[entry.Did] = struct{}{}
+	}
+	features.DistinctMentionsLastHour = int64(len(mentioned))
+
+	if textHash != 0 {
+		for _, entry := range s.Texts {
+			if entry.Hash == textHash {
+				features.IdenticalTextPostsLastHour++
+			}
+		}
+	}
+
+	return features
+}
+
+// prune drops all entries that have fallen out of the longest window.
+func (s *didState) prune(now time.Time) {
+	cutoff := now.Add(-maxWindow)
+	s.Posts = pruneBefore(s.Posts, cutoff, func(at time.Time) time.Time { return at })
+	s.Images = pruneBefore(s.Images, cutoff, func(e countEntry) time.Time { return e.At })
+	s.Mentions = pruneBefore(s.Mentions, cutoff, func(e mentionEntry) time.Time { return e.At })
+	s.Texts = pruneBefore(s.Texts, cutoff, func(e textEntry) time.Time { return e.At })
+}
+
+func (s *didState) empty() bool {
+	return len(s.Posts) == 0 && len(s.Images) == 0 && len(s.Mentions) == 0 && len(s.Texts) == 0
+}
+
+// pruneBefore drops the entries older than cutoff. Entries are appended in time order, so we only
+// need to find the first one that's still in the window.
+func pruneBefore[T any](entries []T, cutoff time.Time, at func(T) time.Time) []T {
+	i := 0
+	for i < len(entries) && !at(entries[i]).After(cutoff) {
+		i++
+	}
+	if i == 0 {
+		return entries
+	}
+	return append(entries[:0], entries[i:]...)
+}
+
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+func hashText(text string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	return h.Sum64()
+}
+
+// Run periodically evicts idle DIDs and writes snapshots until the context is cancelled, at which
+// point a final snapshot is written.
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := t.writeSnapshot(); err != nil {
+				t.logger.Error("failed to write final velocity snapshot", "err", err)
+			}
+			return
+		case <-ticker.C:
+			t.evictIdle(time.Now())
+			if err := t.writeSnapshot(); err != nil {
+				t.logger.Error("failed to write velocity snapshot", "err", err)
+			}
+		}
+	}
+}
+
+func (t *Tracker) evictIdle(now time.Time) {
+	t.dids.Range(func(did string, _ *didState) bool {
+		// Check and delete under the map's lock for the DID, so a state that's just been stored
+		// again isn't lost.
+		t.dids.Compute(did, func(state *didState, loaded bool) (*didState, bool) {
+			if !loaded {
+				return nil, true
+			}
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			state.prune(now)
+			state.evicted = state.empty()
+			return state, state.evicted
+		})
+		return true
+	})
+}
+
+func (t *Tracker) writeSnapshot() error {
+	if t.snapshotPath == "" {
+		return nil
+	}
+
+	snapshot := make(map[string]*didState, t.dids.Size())
+	t.dids.Range(func(did string, state *didState) bool {
+		state.mu.Lock()
+		snapshot[did] = &didState{
+			Posts:    append([]time.Time(nil), state.Posts...),
+			Images:   append([]countEntry(nil), state.Images...),
+			Mentions: append([]mentionEntry(nil), state.Mentions...),
+			Texts:    append([]textEntry(nil), state.Texts...),
+		}
+		state.mu.Unlock()
+		return true
+	})
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// Write to a temp file and rename so we never leave a partial snapshot behind.
+	tmp, err := os.CreateTemp(filepath.Dir(t.snapshotPath), filepath.Base(t.snapshotPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), t.snapshotPath); err != nil {
+		return fmt.Errorf("failed to rename snapshot file: %w", err)
+	}
+
+	t.logger.Debug("wrote velocity snapshot", "dids", len(snapshot))
+	return nil
+}
+
+func (t *Tracker) loadSnapshot() error {
+	b, err := os.ReadFile(t.snapshotPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			t.logger.Info("no velocity snapshot found, starting empty", "path", t.snapshotPath)
+			return nil
+		}
+		return err
+	}
+
+	snapshot := map[string]*didState{}
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	now := time.Now()
+	for did, state := range snapshot {
+		state.prune(now)
+		if !state.empty() {
+			t.dids.Store(did, state)
+		}
+	}
+
+	t.logger.Info("loaded velocity snapshot", "path", t.snapshotPath, "dids", t.dids.Size())
+	return nil
+}
@@ -0,0 +1,96 @@
+package watchlist
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/bluesky-social/osprey-atproto/enricher/appview"
+)
+
+// Watchlist keeps the membership of a set of moderation lists in memory so that each event can be
+// checked without a call to the AppView. Membership is refreshed periodically.
+type Watchlist struct {
+	logger          *slog.Logger
+	client          *appview.Client
+	lists           []string
+	refreshInterval time.Duration
+
+	// members maps a DID to the URIs of the lists it's a member of.
+	members atomic.Pointer[map[string][]string]
+}
+
+type WatchlistArgs struct {
+	Logger          *slog.Logger
+	Client          *appview.Client
+	Lists           []string
+	RefreshInterval time.Duration
+}
+
+func NewWatchlist(ctx context.Context, args *WatchlistArgs) (*Watchlist, error) {
+	if args.Logger == nil {
+		args.Logger = slog.Default()
+	}
+	if args.RefreshInterval == 0 {
+		args.RefreshInterval = 5 * time.Minute
+	}
+
+	w := &Watchlist{
+		logger:          args.Logger,
+		client:          args.Client,
+		lists:           args.Lists,
+		refreshInterval: args.RefreshInterval,
+	}
+
+	if err := w.refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load watchlists: %w", err)
+	}
+
+	return w, nil
+}
+
+// Lists returns the URIs of the watchlists the DID is a member of.
+func (w *Watchlist) Lists(did string) []string {
+	members := w.members.Load()
+	if members == nil {
+		return nil
+	}
+	return (*members)[did]
+}
+
+// Run refreshes list membership until the context is cancelled. Failed refreshes keep the
+// previously loaded membership.
+func (w *Watchlist) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.refresh(ctx); err != nil {
+				w.logger.Error("failed to refresh watchlists", "err", err)
+			}
+		}
+	}
+}
+
+func (w *Watchlist) refresh(ctx context.Context) error {
+	members := map[string][]string{}
+	for _, list := range w.lists {
+		dids, err := w.client.GetListMembers(ctx, list)
+		if err != nil {
+			return fmt.Errorf("failed to get members of %s: %w", list, err)
+		}
+		for _, did := range dids {
+			members[did] = append(members[did], list)
+		}
+	}
+
+	w.members.Store(&members)
+	w.logger.Info("refreshed watchlists", "lists", len(w.lists), "members", len(members))
+	return nil
+}
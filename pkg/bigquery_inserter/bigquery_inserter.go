@@ -129,7 +129,9 @@ func (i *BigQueryInserter) Insert(ctx context.Context, e any) error {
 	return nil
 }
 
-func (i *BigQueryInserter) Close(ctx context.Context) error {
+// Flush sends any queued rows without waiting for the batch to fill, for low volume tables whose
+// rows shouldn't sit in the queue indefinitely.
+func (i *BigQueryInserter) Flush(ctx context.Context) {
 	i.insertMu.Lock()
 
 	var toInsert []any
@@ -144,7 +146,10 @@ func (i *BigQueryInserter) Close(ctx context.Context) error {
 	if len(toInsert) > 0 {
 		i.sendStream(ctx, toInsert)
 	}
+}
 
+func (i *BigQueryInserter) Close(ctx context.Context) error {
+	i.Flush(ctx)
 	return nil
 }
 
@@ -1,7 +1,7 @@
 import json
 import platform
 from datetime import datetime
-from typing import Any, List, Optional
+from typing import Any, Dict, List, Optional, Tuple
 
 from confluent_kafka import KafkaError, Producer
 from confluent_kafka.admin import AdminClient, NewTopic
@@ -15,6 +15,12 @@ from rpc.osprey_atproto_pb2 import (
 from rpc.osprey_atproto_pb2 import (
     AtprotoCommentEffect as OutputCommentEffect,
 )
+from rpc.osprey_atproto_pb2 import (
+    AtprotoDivertEffect as OutputDivertEffect,
+)
+from rpc.osprey_atproto_pb2 import (
+    DelayedEffects as OutputDelayedEffects,
+)
 from rpc.osprey_atproto_pb2 import (
     AtprotoEmailEffect as OutputEmailEffect,
 )
@@ -24,26 +30,48 @@ from rpc.osprey_atproto_pb2 import (
 from rpc.osprey_atproto_pb2 import (
     AtprotoLabelEffect as OutputLabelEffect,
 )
+from rpc.osprey_atproto_pb2 import (
+    AtprotoMuteEffect as OutputMuteEffect,
+)
 from rpc.osprey_atproto_pb2 import (
     AtprotoReportEffect as OutputReportEffect,
 )
+from rpc.osprey_atproto_pb2 import (
+    AtprotoResolveAppealEffect as OutputResolveAppealEffect,
+)
+from rpc.osprey_atproto_pb2 import (
+    AtprotoSetEffect as OutputSetEffect,
+)
 from rpc.osprey_atproto_pb2 import (
     AtprotoTagEffect as OutputTagEffect,
 )
 from rpc.osprey_atproto_pb2 import (
     AtprotoTakedownEffect as OutputTakedownEffect,
 )
+from rpc.osprey_atproto_pb2 import (
+    Evidence as OutputEvidence,
+)
 from rpc.osprey_atproto_pb2 import (
     ResultEvent,
 )
 from shared.metrics import worker_metrics
-from udfs.atproto.atproto import GetRecordCIDFromData, GetRecordURIFromData
+from udfs.atproto.atproto import (
+    GetMessageRefFromData,
+    GetRecordCIDFromData,
+    GetRecordURIFromData,
+)
 from udfs.atproto.atproto_acknowledge import AtprotoAcknowledgeEffect
 from udfs.atproto.atproto_comment import AtprotoCommentEffect
+from udfs.atproto.atproto_delay import AtprotoCancelDelayedEffect, AtprotoDelayEffect
+from udfs.atproto.atproto_divert import AtprotoDivertEffect
 from udfs.atproto.atproto_email import AtprotoEmailEffect
 from udfs.atproto.atproto_escalate import AtprotoEscalateEffect
+from udfs.atproto.atproto_evidence import AtprotoEvidenceEffect
 from udfs.atproto.atproto_label import AtprotoLabelEffect, EntityToSubjectKind
+from udfs.atproto.atproto_mute import AtprotoMuteEffect
 from udfs.atproto.atproto_report import AtprotoReportEffect
+from udfs.atproto.atproto_resolve_appeal import AtprotoResolveAppealEffect
+from udfs.atproto.atproto_set import AtprotoSetEffect
 from udfs.atproto.atproto_tag import AtprotoTagEffect
 from udfs.atproto.atproto_takedown import AtprotoTakedownEffect
 
@@ -176,6 +204,10 @@ class KafkaOutputSink(BaseOutputSink):
     def push(self, result: ExecutionResult) -> None:
         data = result.action.data
         did = data["did"]
+        # Actions about DMs have a message rather than a record.
+        uri = GetRecordURIFromData(data) if "rkey" in data else ""
+        cid = GetRecordCIDFromData(data) if "cid" in data else ""
+        message = GetMessageRefFromData(data)
 
         labels: List[OutputLabelEffect] = []
         tags: List[OutputTagEffect] = []
@@ -185,6 +217,13 @@ class KafkaOutputSink(BaseOutputSink):
         emails: List[OutputEmailEffect] = []
         comments: List[OutputCommentEffect] = []
         reports: List[OutputReportEffect] = []
+        mutes: List[OutputMuteEffect] = []
+        diverts: List[OutputDivertEffect] = []
+        resolve_appeals: List[OutputResolveAppealEffect] = []
+        sets: List[OutputSetEffect] = []
+        delays: List[Tuple[AtprotoDelayEffect, List[str]]] = []
+        cancel_delayed: List[str] = []
+        evidence: List[OutputEvidence] = []
 
         if "image_results" in data and data["image_results"] is not None:
             for cid in data["image_results"]:
@@ -265,6 +304,7 @@ class KafkaOutputSink(BaseOutputSink):
                             tag=effect.tag,
                             comment=effect.comment,
                             rules=rule_names,
+                            expiration_in_hours=effect.expiration_in_hours,
                         )
                     )
                 elif isinstance(effect, AtprotoTakedownEffect):
@@ -275,6 +315,30 @@ class KafkaOutputSink(BaseOutputSink):
                             comment=effect.comment,
                             email=effect.email,
                             rules=rule_names,
+                            policies=effect.policies,
+                            duration_in_hours=effect.duration_in_hours,
+                        )
+                    )
+                elif isinstance(effect, AtprotoMuteEffect):
+                    mutes.append(
+                        OutputMuteEffect(
+                            effect_kind=effect.effect_kind,
+                            subject_kind=EntityToSubjectKind(effect.entity),
+                            comment=effect.comment,
+                            duration_in_hours=effect.duration_in_hours,
+                            rules=rule_names,
+                        )
+                    )
+                elif isinstance(effect, AtprotoDivertEffect):
+                    blob_cids = effect.blob_cids
+                    if not blob_cids and data.get("image_results"):
+                        blob_cids = list(data["image_results"].keys())
+                    diverts.append(
+                        OutputDivertEffect(
+                            subject_kind=EntityToSubjectKind(effect.entity),
+                            blob_cids=blob_cids,
+                            comment=effect.comment,
+                            rules=rule_names,
                         )
                     )
                 elif isinstance(effect, AtprotoAcknowledgeEffect):
@@ -291,6 +355,24 @@ class KafkaOutputSink(BaseOutputSink):
                             subject_kind=EntityToSubjectKind(effect.entity),
                             comment=effect.comment,
                             rules=rule_names,
+                            queue=effect.queue,
+                        )
+                    )
+                elif isinstance(effect, AtprotoResolveAppealEffect):
+                    resolve_appeals.append(
+                        OutputResolveAppealEffect(
+                            subject_kind=EntityToSubjectKind(effect.entity),
+                            comment=effect.comment,
+                            rules=rule_names,
+                        )
+                    )
+                elif isinstance(effect, AtprotoSetEffect):
+                    sets.append(
+                        OutputSetEffect(
+                            effect_kind=effect.effect_kind,
+                            set=effect.set,
+                            comment=effect.comment,
+                            rules=rule_names,
                         )
                     )
                 elif isinstance(effect, AtprotoEmailEffect):
@@ -307,6 +389,20 @@ class KafkaOutputSink(BaseOutputSink):
                             rules=rule_names,
                         )
                     )
+                elif isinstance(effect, AtprotoDelayEffect):
+                    delays.append((effect, rule_names))
+                elif isinstance(effect, AtprotoCancelDelayedEffect):
+                    cancel_delayed.append(effect.key)
+                elif isinstance(effect, AtprotoEvidenceEffect):
+                    evidence.append(
+                        OutputEvidence(
+                            kind=effect.kind,
+                            name=effect.name,
+                            value=effect.value,
+                            score=effect.score,
+                            rules=rule_names,
+                        )
+                    )
                 elif isinstance(effect, AtprotoReportEffect):
                     reports.append(
                         OutputReportEffect(
@@ -323,22 +419,65 @@ class KafkaOutputSink(BaseOutputSink):
 
         datab = json.dumps(data).encode("utf-8")
 
+        effect_lists: Dict[str, List[Any]] = {
+            "labels": labels,
+            "tags": tags,
+            "takedowns": takedowns,
+            "emails": emails,
+            "comments": comments,
+            "escalations": escalations,
+            "acknowledgements": acknowledgements,
+            "reports": reports,
+            "mutes": mutes,
+            "diverts": diverts,
+            "resolve_appeals": resolve_appeals,
+            "sets": sets,
+        }
+
+        # Effects of the rules a delay was given for are held back in an event of their own, which
+        # the effector applies once the delay has passed unless it's cancelled first.
+        delayed: List[OutputDelayedEffects] = []
+        for delay, delay_rules in delays:
+            held: Dict[str, List[Any]] = {}
+            for field, effects in effect_lists.items():
+                held[field] = [e for e in effects if set(e.rules) & set(delay_rules)]
+                effect_lists[field] = [
+                    e for e in effects if not set(e.rules) & set(delay_rules)
+                ]
+            if not any(held.values()):
+                continue
+            delayed.append(
+                OutputDelayedEffects(
+                    event=ResultEvent(
+                        send_time=ts,
+                        action_name=result.action.action_name,
+                        action_id=result.action.action_id,
+                        did=did,
+                        uri=uri,
+                        cid=cid,
+                        message=message,
+                        data=datab,
+                        evidence=evidence,
+                        **held,
+                    ),
+                    execute_after_seconds=delay.seconds,
+                    key=delay.key,
+                )
+            )
+
         osprey_result = ResultEvent(
             send_time=ts,
             action_name=result.action.action_name,
             action_id=result.action.action_id,
             did=did,
-            uri=GetRecordURIFromData(result.action.data),
-            cid=GetRecordCIDFromData(result.action.data),
+            uri=uri,
+            cid=cid,
+            message=message,
             data=datab,
-            labels=labels,
-            tags=tags,
-            takedowns=takedowns,
-            emails=emails,
-            comments=comments,
-            escalations=escalations,
-            acknowledgements=acknowledgements,
-            reports=reports,
+            delayed=delayed,
+            cancel_delayed=cancel_delayed,
+            evidence=evidence,
+            **effect_lists,
         )
 
         for attempt in range(self.max_retries):
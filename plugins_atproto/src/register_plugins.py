@@ -14,16 +14,23 @@ from shared.metrics import worker_metrics
 from udfs.atproto.atproto import (
     GetDIDCreatedAt,
     GetHandle,
+    GetMessageEntity,
     GetPDSService,
     GetRecordCID,
     GetRecordURI,
 )
 from udfs.atproto.atproto_acknowledge import AtprotoAcknowledge
 from udfs.atproto.atproto_comment import AtprotoComment
+from udfs.atproto.atproto_delay import CancelDelayedAtprotoEffects, DelayAtprotoEffects
+from udfs.atproto.atproto_divert import AtprotoDivert
 from udfs.atproto.atproto_email import AtprotoSendEmail
 from udfs.atproto.atproto_escalate import AtprotoEscalate
+from udfs.atproto.atproto_evidence import AtprotoEvidence
 from udfs.atproto.atproto_label import AddAtprotoLabel, RemoveAtprotoLabel
+from udfs.atproto.atproto_mute import AddAtprotoMute, RemoveAtprotoMute
 from udfs.atproto.atproto_report import AtprotoReport
+from udfs.atproto.atproto_resolve_appeal import AtprotoResolveAppeal
+from udfs.atproto.atproto_set import AddAtprotoSet, RemoveAtprotoSet
 from udfs.atproto.atproto_tag import AddAtprotoTag, RemoveAtprotoTag
 from udfs.atproto.atproto_takedown import AddAtprotoTakedown, RemoveAtprotoTakedown
 from udfs.atproto.std.did_from_uri import DidFromUri
@@ -81,6 +88,7 @@ def register_udfs():
         DidFromUri,
         GetRecordURI,
         GetRecordCID,
+        GetMessageEntity,
         GetDIDCreatedAt,
         GetPDSService,
         GetHandle,
@@ -98,6 +106,15 @@ def register_udfs():
         RemoveAtprotoTag,
         AddAtprotoTakedown,
         RemoveAtprotoTakedown,
+        AddAtprotoMute,
+        RemoveAtprotoMute,
+        AtprotoDivert,
+        AtprotoResolveAppeal,
+        AddAtprotoSet,
+        RemoveAtprotoSet,
+        DelayAtprotoEffects,
+        CancelDelayedAtprotoEffects,
+        AtprotoEvidence,
     ]
 
 
@@ -15,7 +15,7 @@ _sym_db = _symbol_database.Default()
 from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x14osprey_atproto.proto\x12\x06osprey\x1a\x1fgoogle/protobuf/timestamp.proto\"}\n\x10OspreyInputEvent\x12\x30\n\x04\x64\x61ta\x18\x01 \x01(\x0b\x32\x1c.osprey.OspreyInputEventDataR\x04\x64\x61ta\x12\x37\n\tsend_time\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x08sendTime\"\xcc\x02\n\x14OspreyInputEventData\x12\x1f\n\x0b\x61\x63tion_name\x18\x01 \x01(\tR\nactionName\x12\x1b\n\taction_id\x18\x02 \x01(\x03R\x08\x61\x63tionId\x12\x12\n\x04\x64\x61ta\x18\x03 \x01(\x0cR\x04\x64\x61ta\x12\x38\n\ttimestamp\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12M\n\x0bsecret_data\x18\x05 \x03(\x0b\x32,.osprey.OspreyInputEventData.SecretDataEntryR\nsecretData\x12\x1a\n\x08\x65ncoding\x18\x06 \x01(\tR\x08\x65ncoding\x1a=\n\x0fSecretDataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"\xf3\x02\n\x12\x41tprotoLabelEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12*\n\x05label\x18\x03 \x01(\x0e\x32\x14.osprey.AtprotoLabelR\x05label\x12\x18\n\x07\x63omment\x18\x04 \x01(\tR\x07\x63omment\x12/\n\x05\x65mail\x18\x05 \x01(\x0e\x32\x14.osprey.AtprotoEmailH\x00R\x05\x65mail\x88\x01\x01\x12\x33\n\x13\x65xpiration_in_hours\x18\x06 \x01(\x03H\x01R\x11\x65xpirationInHours\x88\x01\x01\x12\x14\n\x05rules\x18\x07 \x03(\tR\x05rulesB\x08\n\x06_emailB\x16\n\x14_expiration_in_hours\"\xe0\x01\n\x10\x41tprotoTagEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x10\n\x03tag\x18\x03 \x01(\tR\x03tag\x12\x1d\n\x07\x63omment\x18\x04 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x05 \x03(\tR\x05rulesB\n\n\x08_comment\"\xfd\x01\n\x15\x41tprotoTakedownEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x18\n\x07\x63omment\x18\x04 \x01(\tR\x07\x63omment\x12/\n\x05\x65mail\x18\x05 \x01(\x0e\x32\x14.osprey.AtprotoEmailH\x00R\x05\x65mail\x88\x01\x01\x12\x14\n\x05rules\x18\x06 \x03(\tR\x05rulesB\x08\n\x06_email\"\x81\x01\n\x12\x41tprotoEmailEffect\x12*\n\x05\x65mail\x18\x01 \x01(\x0e\x32\x14.osprey.AtprotoEmailR\x05\x65mail\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rulesB\n\n\x08_comment\"\x85\x01\n\x14\x41tprotoCommentEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x18\n\x07\x63omment\x18\x02 \x01(\tR\x07\x63omment\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rules\"\x97\x01\n\x15\x41tprotoEscalateEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rulesB\n\n\x08_comment\"\x9a\x01\n\x18\x41tprotoAcknowledgeEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rulesB\n\n\x08_comment\"\xff\x01\n\x13\x41tprotoReportEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12:\n\x0breport_kind\x18\x02 \x01(\x0e\x32\x19.osprey.AtprotoReportKindR\nreportKind\x12\x18\n\x07\x63omment\x18\x03 \x01(\tR\x07\x63omment\x12*\n\x0epriority_score\x18\x04 \x01(\x03H\x00R\rpriorityScore\x88\x01\x01\x12\x14\n\x05rules\x18\x05 \x03(\tR\x05rulesB\x11\n\x0f_priority_score\"\xa6\x01\n\x12\x42igQueryFlagEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x10\n\x03tag\x18\x02 \x01(\tR\x03tag\x12\x1d\n\x07\x63omment\x18\x03 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x04 \x03(\tR\x05rulesB\n\n\x08_comment\"\xe3\x05\n\x0bResultEvent\x12\x37\n\tsend_time\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x08sendTime\x12\x1f\n\x0b\x61\x63tion_name\x18\x02 \x01(\tR\nactionName\x12\x1b\n\taction_id\x18\x03 \x01(\x03R\x08\x61\x63tionId\x12\x10\n\x03\x64id\x18\x04 \x01(\tR\x03\x64id\x12\x10\n\x03uri\x18\x05 \x01(\tR\x03uri\x12\x10\n\x03\x63id\x18\x06 \x01(\tR\x03\x63id\x12\x12\n\x04\x64\x61ta\x18\x07 \x01(\x0cR\x04\x64\x61ta\x12\x32\n\x06labels\x18\x08 \x03(\x0b\x32\x1a.osprey.AtprotoLabelEffectR\x06labels\x12,\n\x04tags\x18\t \x03(\x0b\x32\x18.osprey.AtprotoTagEffectR\x04tags\x12;\n\ttakedowns\x18\n \x03(\x0b\x32\x1d.osprey.AtprotoTakedownEffectR\ttakedowns\x12\x32\n\x06\x65mails\x18\x0b \x03(\x0b\x32\x1a.osprey.AtprotoEmailEffectR\x06\x65mails\x12\x38\n\x08\x63omments\x18\x0c \x03(\x0b\x32\x1c.osprey.AtprotoCommentEffectR\x08\x63omments\x12?\n\x0b\x65scalations\x18\r \x03(\x0b\x32\x1d.osprey.AtprotoEscalateEffectR\x0b\x65scalations\x12L\n\x10\x61\x63knowledgements\x18\x0e \x03(\x0b\x32 .osprey.AtprotoAcknowledgeEffectR\x10\x61\x63knowledgements\x12\x35\n\x07reports\x18\x0f \x03(\x0b\x32\x1b.osprey.AtprotoReportEffectR\x07reports\x12@\n\rbigqueryFlags\x18\x10 \x03(\x0b\x32\x1a.osprey.BigQueryFlagEffectR\rbigqueryFlags\"\xe0\x01\n\rFirehoseEvent\x12\x10\n\x03\x64id\x18\x01 \x01(\tR\x03\x64id\x12\x38\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12%\n\x04kind\x18\x03 \x01(\x0e\x32\x11.osprey.EventKindR\x04kind\x12&\n\x06\x63ommit\x18\x04 \x01(\x0b\x32\x0e.osprey.CommitR\x06\x63ommit\x12\x18\n\x07\x61\x63\x63ount\x18\x05 \x01(\x0cR\x07\x61\x63\x63ount\x12\x1a\n\x08identity\x18\x06 \x01(\x0cR\x08identity\"\xaf\x01\n\x06\x43ommit\x12\x10\n\x03rev\x18\x01 \x01(\tR\x03rev\x12\x35\n\toperation\x18\x02 \x01(\x0e\x32\x17.osprey.CommitOperationR\toperation\x12\x1e\n\ncollection\x18\x03 \x01(\tR\ncollection\x12\x12\n\x04rkey\x18\x04 \x01(\tR\x04rkey\x12\x16\n\x06record\x18\x05 \x01(\x0cR\x06record\x12\x10\n\x03\x63id\x18\x06 \x01(\tR\x03\x63id\"H\n\x06\x43ursor\x12\x1a\n\x08sequence\x18\x01 \x01(\x03R\x08sequence\x12\"\n\rsaved_on_exit\x18\x02 \x01(\x08R\x0bsavedOnExit\"\xc0\x05\n%ModerationEnrichedFirehoseRecordEvent\x12\x10\n\x03\x64id\x18\x01 \x01(\tR\x03\x64id\x12\x38\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1e\n\ncollection\x18\x03 \x01(\tR\ncollection\x12\x12\n\x04rkey\x18\x04 \x01(\tR\x04rkey\x12\x35\n\toperation\x18\x05 \x01(\x0e\x32\x17.osprey.CommitOperationR\toperation\x12\x16\n\x06record\x18\x06 \x01(\x0cR\x06record\x12\x64\n\rimage_results\x18\x07 \x03(\x0b\x32?.osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntryR\x0cimageResults\x12\x38\n\x16ozone_repo_view_detail\x18\x08 \x01(\x0cH\x00R\x13ozoneRepoViewDetail\x88\x01\x01\x12\x1c\n\x07\x64id_doc\x18\t \x01(\x0cH\x01R\x06\x64idDoc\x88\x01\x01\x12&\n\x0cprofile_view\x18\n \x01(\x0cH\x02R\x0bprofileView\x88\x01\x01\x12\'\n\rdid_audit_log\x18\x0b \x01(\x0cH\x03R\x0b\x64idAuditLog\x88\x01\x01\x12\x10\n\x03\x63id\x18\x0c \x01(\tR\x03\x63id\x1a]\n\x11ImageResultsEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x32\n\x05value\x18\x02 \x01(\x0b\x32\x1c.osprey.ImageDispatchResultsR\x05value:\x02\x38\x01\x42\x19\n\x17_ozone_repo_view_detailB\n\n\x08_did_docB\x0f\n\r_profile_viewB\x10\n\x0e_did_audit_log\"\xee\x0f\n\x14ImageDispatchResults\x12\x10\n\x03\x63id\x18\x01 \x01(\tR\x03\x63id\x12\x44\n\x05\x61\x62yss\x18\x02 \x01(\x0b\x32).osprey.ImageDispatchResults.AbyssResultsH\x00R\x05\x61\x62yss\x88\x01\x01\x12\x41\n\x04hive\x18\x03 \x01(\x0b\x32(.osprey.ImageDispatchResults.HiveResultsH\x01R\x04hive\x88\x01\x01\x12G\n\x06retina\x18\x04 \x01(\x0b\x32*.osprey.ImageDispatchResults.RetinaResultsH\x02R\x06retina\x88\x01\x01\x12P\n\tprescreen\x18\x06 \x01(\x0b\x32-.osprey.ImageDispatchResults.PrescreenResultsH\x03R\tprescreen\x88\x01\x01\x12T\n\x0bretina_hash\x18\x07 \x01(\x0b\x32..osprey.ImageDispatchResults.RetinaHashResultsH\x04R\nretinaHash\x88\x01\x01\x12\x41\n\x04ncii\x18\x08 \x01(\x0b\x32(.osprey.ImageDispatchResults.NciiResultsH\x05R\x04ncii\x88\x01\x01\x12J\n\x07\x66lagged\x18\t \x01(\x0b\x32+.osprey.ImageDispatchResults.FlaggedResultsH\x06R\x07\x66lagged\x88\x01\x01\x1a\x90\x01\n\x0c\x41\x62yssResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12)\n\x0eis_abuse_match\x18\x03 \x01(\x08H\x02R\x0cisAbuseMatch\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x11\n\x0f_is_abuse_match\x1a\xde\x01\n\x0bHiveResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12O\n\x07\x63lasses\x18\x03 \x03(\x0b\x32\x35.osprey.ImageDispatchResults.HiveResults.ClassesEntryR\x07\x63lasses\x1a:\n\x0c\x43lassesEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\x01R\x05value:\x02\x38\x01\x42\x06\n\x04_rawB\x08\n\x06_error\x1au\n\rRetinaResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x17\n\x04text\x18\x03 \x01(\tH\x02R\x04text\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x07\n\x05_text\x1a\xba\x01\n\x11RetinaHashResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x17\n\x04hash\x18\x03 \x01(\tH\x02R\x04hash\x88\x01\x01\x12+\n\x0fquality_too_low\x18\x04 \x01(\x08H\x03R\rqualityTooLow\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x07\n\x05_hashB\x12\n\x10_quality_too_low\x1a\x84\x01\n\x10PrescreenResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x1f\n\x08\x64\x65\x63ision\x18\x03 \x01(\tH\x02R\x08\x64\x65\x63ision\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x0b\n\t_decision\x1a\xa3\x01\n\x0bNciiResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x1e\n\x08is_match\x18\x03 \x01(\x08H\x02R\x07isMatch\x88\x01\x01\x12\x19\n\x05score\x18\x04 \x01(\x01H\x03R\x05score\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x0b\n\t_is_matchB\x08\n\x06_score\x1a\x94\x03\n\x0e\x46laggedResults\x12\x19\n\x05\x65rror\x18\x01 \x01(\tH\x00R\x05\x65rror\x88\x01\x01\x12\x1e\n\x08is_match\x18\x02 \x01(\x08H\x01R\x07isMatch\x88\x01\x01\x12\x1b\n\x06\x61\x63tion\x18\x03 \x01(\tH\x02R\x06\x61\x63tion\x88\x01\x01\x12&\n\x0c\x61\x63tion_level\x18\x04 \x01(\tH\x03R\x0b\x61\x63tionLevel\x88\x01\x01\x12&\n\x0c\x61\x63tion_value\x18\x05 \x01(\tH\x04R\x0b\x61\x63tionValue\x88\x01\x01\x12(\n\ralways_report\x18\x06 \x01(\x08H\x05R\x0c\x61lwaysReport\x88\x01\x01\x12%\n\x0b\x64\x65scription\x18\x07 \x01(\tH\x06R\x0b\x64\x65scription\x88\x01\x01\x12\x19\n\x05score\x18\x08 \x01(\x01H\x07R\x05score\x88\x01\x01\x42\x08\n\x06_errorB\x0b\n\t_is_matchB\t\n\x07_actionB\x0f\n\r_action_levelB\x0f\n\r_action_valueB\x10\n\x0e_always_reportB\x0e\n\x0c_descriptionB\x08\n\x06_scoreB\x08\n\x06_abyssB\x07\n\x05_hiveB\t\n\x07_retinaB\x0c\n\n_prescreenB\x0e\n\x0c_retina_hashB\x07\n\x05_nciiB\n\n\x08_flagged*t\n\x12\x41tprotoSubjectKind\x12\x1d\n\x19\x41TPROTO_SUBJECT_KIND_NONE\x10\x00\x12\x1e\n\x1a\x41TPROTO_SUBJECT_KIND_ACTOR\x10\x01\x12\x1f\n\x1b\x41TPROTO_SUBJECT_KIND_RECORD\x10\x02*\xf6\x01\n\x0c\x41tprotoLabel\x12\x16\n\x12\x41TPROTO_LABEL_NONE\x10\x00\x12\x16\n\x12\x41TPROTO_LABEL_SPAM\x10\x01\x12\x16\n\x12\x41TPROTO_LABEL_RUDE\x10\x02\x12\x16\n\x12\x41TPROTO_LABEL_PORN\x10\x03\x12\x18\n\x14\x41TPROTO_LABEL_SEXUAL\x10\x04\x12\x16\n\x12\x41TPROTO_LABEL_WARN\x10\x05\x12\x16\n\x12\x41TPROTO_LABEL_HIDE\x10\x06\x12\x1e\n\x1a\x41TPROTO_LABEL_NEEDS_REVIEW\x10\x07\x12\x1c\n\x18\x41TPROTO_LABEL_MISLEADING\x10\x08*n\n\x11\x41tprotoEffectKind\x12\x1c\n\x18\x41TPROTO_EFFECT_KIND_NONE\x10\x00\x12\x1b\n\x17\x41TPROTO_EFFECT_KIND_ADD\x10\x01\x12\x1e\n\x1a\x41TPROTO_EFFECT_KIND_REMOVE\x10\x02*\x93\x04\n\x0c\x41tprotoEmail\x12\x16\n\x12\x41TPROTO_EMAIL_NONE\x10\x00\x12\x1c\n\x18\x41TPROTO_EMAIL_SPAM_REPLY\x10\x44\x12 \n\x1b\x41TPROTO_EMAIL_SPAM_TAKEDOWN\x10\x85\x02\x12\x1b\n\x17\x41TPROTO_EMAIL_SPAM_FAKE\x10?\x12\x1d\n\x18\x41TPROTO_EMAIL_SPAM_LABEL\x10\xbe\x02\x12&\n!ATPROTO_EMAIL_SPAM_LABEL_24_HOURS\x10\xae\x02\x12&\n!ATPROTO_EMAIL_SPAM_LABEL_72_HOURS\x10\xb9\x02\x12\x1d\n\x18\x41TPROTO_EMAIL_ID_REQUEST\x10\x99\x02\x12%\n!ATPROTO_EMAIL_IMPERSONATION_LABEL\x10\x1f\x12#\n\x1e\x41TPROTO_EMAIL_AUTOMOD_TAKEDOWN\x10\xa0\x02\x12\x1f\n\x1b\x41TPROTO_EMAIL_REINSTATEMENT\x10<\x12&\n\"ATPROTO_EMAIL_THREAT_POST_TAKEDOWN\x10\x1a\x12\'\n#ATPROTO_EMAIL_PEDO_ACCOUNT_TAKEDOWN\x10+\x12\x1f\n\x1a\x41TPROTO_EMAIL_DMS_DISABLED\x10\xa7\x02\x12!\n\x1d\x41TPROTO_EMAIL_TOXIC_LIST_HIDE\x10\x33*\xf3\x01\n\x11\x41tprotoReportKind\x12\x1c\n\x18\x41TPROTO_REPORT_KIND_NONE\x10\x00\x12\x1c\n\x18\x41TPROTO_REPORT_KIND_SPAM\x10\x01\x12!\n\x1d\x41TPROTO_REPORT_KIND_VIOLATION\x10\x02\x12\"\n\x1e\x41TPROTO_REPORT_KIND_MISLEADING\x10\x03\x12\x1e\n\x1a\x41TPROTO_REPORT_KIND_SEXUAL\x10\x04\x12\x1c\n\x18\x41TPROTO_REPORT_KIND_RUDE\x10\x05\x12\x1d\n\x19\x41TPROTO_REPORT_KIND_OTHER\x10\x06*o\n\tEventKind\x12\x1a\n\x16\x45VENT_KIND_UNSPECIFIED\x10\x00\x12\x15\n\x11\x45VENT_KIND_COMMIT\x10\x01\x12\x16\n\x12\x45VENT_KIND_ACCOUNT\x10\x02\x12\x17\n\x13\x45VENT_KIND_IDENTITY\x10\x03*\x8a\x01\n\x0f\x43ommitOperation\x12 \n\x1c\x43OMMIT_OPERATION_UNSPECIFIED\x10\x00\x12\x1b\n\x17\x43OMMIT_OPERATION_CREATE\x10\x01\x12\x1b\n\x17\x43OMMIT_OPERATION_UPDATE\x10\x02\x12\x1b\n\x17\x43OMMIT_OPERATION_DELETE\x10\x03\x42\x63\n\ncom.ospreyB\x12OspreyAtprotoProtoP\x01Z\t./;osprey\xa2\x02\x03OXX\xaa\x02\x06Osprey\xca\x02\x06Osprey\xe2\x02\x12Osprey\\GPBMetadata\xea\x02\x06Ospreyb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x14osprey_atproto.proto\x12\x06osprey\x1a\x1fgoogle/protobuf/timestamp.proto\"}\n\x10OspreyInputEvent\x12\x30\n\x04\x64\x61ta\x18\x01 \x01(\x0b\x32\x1c.osprey.OspreyInputEventDataR\x04\x64\x61ta\x12\x37\n\tsend_time\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x08sendTime\"\xcc\x02\n\x14OspreyInputEventData\x12\x1f\n\x0b\x61\x63tion_name\x18\x01 \x01(\tR\nactionName\x12\x1b\n\taction_id\x18\x02 \x01(\x03R\x08\x61\x63tionId\x12\x12\n\x04\x64\x61ta\x18\x03 \x01(\x0cR\x04\x64\x61ta\x12\x38\n\ttimestamp\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12M\n\x0bsecret_data\x18\x05 \x03(\x0b\x32,.osprey.OspreyInputEventData.SecretDataEntryR\nsecretData\x12\x1a\n\x08\x65ncoding\x18\x06 \x01(\tR\x08\x65ncoding\x1a=\n\x0fSecretDataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"\xf3\x02\n\x12\x41tprotoLabelEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12*\n\x05label\x18\x03 \x01(\x0e\x32\x14.osprey.AtprotoLabelR\x05label\x12\x18\n\x07\x63omment\x18\x04 \x01(\tR\x07\x63omment\x12/\n\x05\x65mail\x18\x05 \x01(\x0e\x32\x14.osprey.AtprotoEmailH\x00R\x05\x65mail\x88\x01\x01\x12\x33\n\x13\x65xpiration_in_hours\x18\x06 \x01(\x03H\x01R\x11\x65xpirationInHours\x88\x01\x01\x12\x14\n\x05rules\x18\x07 \x03(\tR\x05rulesB\x08\n\x06_emailB\x16\n\x14_expiration_in_hours\"\xad\x02\n\x10\x41tprotoTagEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x10\n\x03tag\x18\x03 \x01(\tR\x03tag\x12\x1d\n\x07\x63omment\x18\x04 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x05 \x03(\tR\x05rules\x12\x33\n\x13\x65xpiration_in_hours\x18\x06 \x01(\x03H\x01R\x11\x65xpirationInHours\x88\x01\x01\x42\n\n\x08_commentB\x16\n\x14_expiration_in_hours\"\xe0\x02\n\x15\x41tprotoTakedownEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x18\n\x07\x63omment\x18\x04 \x01(\tR\x07\x63omment\x12/\n\x05\x65mail\x18\x05 \x01(\x0e\x32\x14.osprey.AtprotoEmailH\x00R\x05\x65mail\x88\x01\x01\x12\x14\n\x05rules\x18\x06 \x03(\tR\x05rules\x12\x1a\n\x08policies\x18\x07 \x03(\tR\x08policies\x12/\n\x11\x64uration_in_hours\x18\x08 \x01(\x03H\x01R\x0f\x64urationInHours\x88\x01\x01\x42\x08\n\x06_emailB\x14\n\x12_duration_in_hours\"\x81\x01\n\x12\x41tprotoEmailEffect\x12*\n\x05\x65mail\x18\x01 \x01(\x0e\x32\x14.osprey.AtprotoEmailR\x05\x65mail\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rulesB\n\n\x08_comment\"\xfb\x01\n\x11\x41tprotoMuteEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12=\n\x0csubject_kind\x18\x02 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1d\n\x07\x63omment\x18\x03 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12*\n\x11\x64uration_in_hours\x18\x04 \x01(\x03R\x0f\x64urationInHours\x12\x14\n\x05rules\x18\x05 \x03(\tR\x05rulesB\n\n\x08_comment\"\xb2\x01\n\x13\x41tprotoDivertEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1b\n\tblob_cids\x18\x02 \x03(\tR\x08\x62lobCids\x12\x1d\n\x07\x63omment\x18\x03 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x04 \x03(\tR\x05rulesB\n\n\x08_comment\"\x85\x01\n\x14\x41tprotoCommentEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x18\n\x07\x63omment\x18\x02 \x01(\tR\x07\x63omment\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rules\"\xbc\x01\n\x15\x41tprotoEscalateEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rules\x12\x19\n\x05queue\x18\x04 \x01(\tH\x01R\x05queue\x88\x01\x01\x42\n\n\x08_commentB\x08\n\x06_queue\"\x9c\x01\n\x1a\x41tprotoResolveAppealEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rulesB\n\n\x08_comment\"\xa1\x01\n\x10\x41tprotoSetEffect\x12:\n\x0b\x65\x66\x66\x65\x63t_kind\x18\x01 \x01(\x0e\x32\x19.osprey.AtprotoEffectKindR\neffectKind\x12\x10\n\x03set\x18\x02 \x01(\tR\x03set\x12\x1d\n\x07\x63omment\x18\x03 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x04 \x03(\tR\x05rulesB\n\n\x08_comment\"\x9a\x01\n\x18\x41tprotoAcknowledgeEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x1d\n\x07\x63omment\x18\x02 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x03 \x03(\tR\x05rulesB\n\n\x08_comment\"\xff\x01\n\x13\x41tprotoReportEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12:\n\x0breport_kind\x18\x02 \x01(\x0e\x32\x19.osprey.AtprotoReportKindR\nreportKind\x12\x18\n\x07\x63omment\x18\x03 \x01(\tR\x07\x63omment\x12*\n\x0epriority_score\x18\x04 \x01(\x03H\x00R\rpriorityScore\x88\x01\x01\x12\x14\n\x05rules\x18\x05 \x03(\tR\x05rulesB\x11\n\x0f_priority_score\"\xa6\x01\n\x12\x42igQueryFlagEffect\x12=\n\x0csubject_kind\x18\x01 \x01(\x0e\x32\x1a.osprey.AtprotoSubjectKindR\x0bsubjectKind\x12\x10\n\x03tag\x18\x02 \x01(\tR\x03tag\x12\x1d\n\x07\x63omment\x18\x03 \x01(\tH\x00R\x07\x63omment\x88\x01\x01\x12\x14\n\x05rules\x18\x04 \x03(\tR\x05rulesB\n\n\x08_comment\"\xff\x08\n\x0bResultEvent\x12\x37\n\tsend_time\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x08sendTime\x12\x1f\n\x0b\x61\x63tion_name\x18\x02 \x01(\tR\nactionName\x12\x1b\n\taction_id\x18\x03 \x01(\x03R\x08\x61\x63tionId\x12\x10\n\x03\x64id\x18\x04 \x01(\tR\x03\x64id\x12\x10\n\x03uri\x18\x05 \x01(\tR\x03uri\x12\x10\n\x03\x63id\x18\x06 \x01(\tR\x03\x63id\x12\x12\n\x04\x64\x61ta\x18\x07 \x01(\x0cR\x04\x64\x61ta\x12\x32\n\x06labels\x18\x08 \x03(\x0b\x32\x1a.osprey.AtprotoLabelEffectR\x06labels\x12,\n\x04tags\x18\t \x03(\x0b\x32\x18.osprey.AtprotoTagEffectR\x04tags\x12;\n\ttakedowns\x18\n \x03(\x0b\x32\x1d.osprey.AtprotoTakedownEffectR\ttakedowns\x12\x32\n\x06\x65mails\x18\x0b \x03(\x0b\x32\x1a.osprey.AtprotoEmailEffectR\x06\x65mails\x12\x38\n\x08\x63omments\x18\x0c \x03(\x0b\x32\x1c.osprey.AtprotoCommentEffectR\x08\x63omments\x12?\n\x0b\x65scalations\x18\r \x03(\x0b\x32\x1d.osprey.AtprotoEscalateEffectR\x0b\x65scalations\x12L\n\x10\x61\x63knowledgements\x18\x0e \x03(\x0b\x32 .osprey.AtprotoAcknowledgeEffectR\x10\x61\x63knowledgements\x12\x35\n\x07reports\x18\x0f \x03(\x0b\x32\x1b.osprey.AtprotoReportEffectR\x07reports\x12@\n\rbigqueryFlags\x18\x10 \x03(\x0b\x32\x1a.osprey.BigQueryFlagEffectR\rbigqueryFlags\x12/\n\x05mutes\x18\x11 \x03(\x0b\x32\x19.osprey.AtprotoMuteEffectR\x05mutes\x12\x35\n\x07\x64iverts\x18\x12 \x03(\x0b\x32\x1b.osprey.AtprotoDivertEffectR\x07\x64iverts\x12K\n\x0fresolve_appeals\x18\x13 \x03(\x0b\x32\".osprey.AtprotoResolveAppealEffectR\x0eresolveAppeals\x12,\n\x04sets\x18\x14 \x03(\x0b\x32\x18.osprey.AtprotoSetEffectR\x04sets\x12\x30\n\x07\x64\x65layed\x18\x15 \x03(\x0b\x32\x16.osprey.DelayedEffectsR\x07\x64\x65layed\x12%\n\x0e\x63\x61ncel_delayed\x18\x16 \x03(\tR\rcancelDelayed\x12\x30\n\x07message\x18\x17 \x01(\x0b\x32\x16.osprey.ChatMessageRefR\x07message\x12,\n\x08\x65vidence\x18\x18 \x03(\x0b\x32\x10.osprey.EvidenceR\x08\x65vidence\"\x83\x01\n\x08\x45vidence\x12\x12\n\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n\x05value\x18\x03 \x01(\tR\x05value\x12\x19\n\x05score\x18\x04 \x01(\x01H\x00R\x05score\x88\x01\x01\x12\x14\n\x05rules\x18\x05 \x03(\tR\x05rulesB\x08\n\x06_score\"\\\n\x0e\x43hatMessageRef\x12\x10\n\x03\x64id\x18\x01 \x01(\tR\x03\x64id\x12\x19\n\x08\x63onvo_id\x18\x02 \x01(\tR\x07\x63onvoId\x12\x1d\n\nmessage_id\x18\x03 \x01(\tR\tmessageId\"\x81\x01\n\x0e\x44\x65layedEffects\x12)\n\x05\x65vent\x18\x01 \x01(\x0b\x32\x13.osprey.ResultEventR\x05\x65vent\x12\x32\n\x15\x65xecute_after_seconds\x18\x02 \x01(\x03R\x13\x65xecuteAfterSeconds\x12\x10\n\x03key\x18\x03 \x01(\tR\x03key\"\xb9\x01\n\rFailedEffects\x12)\n\x05\x65vent\x18\x01 \x01(\x0b\x32\x13.osprey.ResultEventR\x05\x65vent\x12\x1a\n\x08\x61ttempts\x18\x02 \x01(\x05R\x08\x61ttempts\x12\x42\n\x0fnext_attempt_at\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12\x1d\n\nlast_error\x18\x04 \x01(\tR\tlastError\"\xe1\x02\n\rEffectOutcome\x12\x38\n\ttimestamp\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1f\n\x0b\x61\x63tion_name\x18\x02 \x01(\tR\nactionName\x12\x1b\n\taction_id\x18\x03 \x01(\x03R\x08\x61\x63tionId\x12\x12\n\x04kind\x18\x04 \x01(\tR\x04kind\x12\x18\n\x07subject\x18\x05 \x01(\tR\x07subject\x12\x14\n\x05rules\x18\x06 \x03(\tR\x05rules\x12\x18\n\x07success\x18\x07 \x01(\x08R\x07success\x12\x19\n\x05\x65rror\x18\x08 \x01(\tH\x00R\x05\x65rror\x88\x01\x01\x12)\n\x0eozone_event_id\x18\t \x01(\x03H\x01R\x0cozoneEventId\x88\x01\x01\x12\x17\n\x07\x64ry_run\x18\n \x01(\x08R\x06\x64ryRunB\x08\n\x06_errorB\x11\n\x0f_ozone_event_id\"\xe0\x01\n\rFirehoseEvent\x12\x10\n\x03\x64id\x18\x01 \x01(\tR\x03\x64id\x12\x38\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12%\n\x04kind\x18\x03 \x01(\x0e\x32\x11.osprey.EventKindR\x04kind\x12&\n\x06\x63ommit\x18\x04 \x01(\x0b\x32\x0e.osprey.CommitR\x06\x63ommit\x12\x18\n\x07\x61\x63\x63ount\x18\x05 \x01(\x0cR\x07\x61\x63\x63ount\x12\x1a\n\x08identity\x18\x06 \x01(\x0cR\x08identity\"\xaf\x01\n\x06\x43ommit\x12\x10\n\x03rev\x18\x01 \x01(\tR\x03rev\x12\x35\n\toperation\x18\x02 \x01(\x0e\x32\x17.osprey.CommitOperationR\toperation\x12\x1e\n\ncollection\x18\x03 \x01(\tR\ncollection\x12\x12\n\x04rkey\x18\x04 \x01(\tR\x04rkey\x12\x16\n\x06record\x18\x05 \x01(\x0cR\x06record\x12\x10\n\x03\x63id\x18\x06 \x01(\tR\x03\x63id\"H\n\x06\x43ursor\x12\x1a\n\x08sequence\x18\x01 \x01(\x03R\x08sequence\x12\"\n\rsaved_on_exit\x18\x02 \x01(\x08R\x0bsavedOnExit\"\xef\n\n%ModerationEnrichedFirehoseRecordEvent\x12\x10\n\x03\x64id\x18\x01 \x01(\tR\x03\x64id\x12\x38\n\ttimestamp\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1e\n\ncollection\x18\x03 \x01(\tR\ncollection\x12\x12\n\x04rkey\x18\x04 \x01(\tR\x04rkey\x12\x35\n\toperation\x18\x05 \x01(\x0e\x32\x17.osprey.CommitOperationR\toperation\x12\x16\n\x06record\x18\x06 \x01(\x0cR\x06record\x12\x64\n\rimage_results\x18\x07 \x03(\x0b\x32?.osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntryR\x0cimageResults\x12\x38\n\x16ozone_repo_view_detail\x18\x08 \x01(\x0cH\x00R\x13ozoneRepoViewDetail\x88\x01\x01\x12\x1c\n\x07\x64id_doc\x18\t \x01(\x0cH\x01R\x06\x64idDoc\x88\x01\x01\x12&\n\x0cprofile_view\x18\n \x01(\x0cH\x02R\x0bprofileView\x88\x01\x01\x12\'\n\rdid_audit_log\x18\x0b \x01(\x0cH\x03R\x0b\x64idAuditLog\x88\x01\x01\x12\x10\n\x03\x63id\x18\x0c \x01(\tR\x03\x63id\x12\x39\n\x08velocity\x18\r \x01(\x0b\x32\x18.osprey.VelocityFeaturesH\x04R\x08velocity\x88\x01\x01\x12\x41\n\x11term_list_matches\x18\x0e \x03(\x0b\x32\x15.osprey.TermListMatchR\x0ftermListMatches\x12O\n\x15impersonation_matches\x18\x0f \x03(\x0b\x32\x1a.osprey.ImpersonationMatchR\x14impersonationMatches\x12\x39\n\x08identity\x18\x10 \x01(\x0b\x32\x18.osprey.IdentityFeaturesH\x05R\x08identity\x88\x01\x01\x12*\n\x03pds\x18\x11 \x01(\x0b\x32\x13.osprey.PdsFeaturesH\x06R\x03pds\x88\x01\x01\x12M\n\x12\x63ollection_context\x18\x12 \x01(\x0b\x32\x19.osprey.CollectionContextH\x07R\x11\x63ollectionContext\x88\x01\x01\x12\x1e\n\nwatchlists\x18\x13 \x03(\tR\nwatchlists\x12>\n\x0f\x65xisting_labels\x18\x14 \x03(\x0b\x32\x15.osprey.ExistingLabelR\x0e\x65xistingLabels\x12J\n\x14\x62lob_type_mismatches\x18\x15 \x03(\x0b\x32\x18.osprey.BlobTypeMismatchR\x12\x62lobTypeMismatches\x12\x36\n\x08pipeline\x18\x16 \x01(\x0b\x32\x15.osprey.PipelineTimesH\x08R\x08pipeline\x88\x01\x01\x1a]\n\x11ImageResultsEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x32\n\x05value\x18\x02 \x01(\x0b\x32\x1c.osprey.ImageDispatchResultsR\x05value:\x02\x38\x01\x42\x19\n\x17_ozone_repo_view_detailB\n\n\x08_did_docB\x0f\n\r_profile_viewB\x10\n\x0e_did_audit_logB\x0b\n\t_velocityB\x0b\n\t_identityB\x06\n\x04_pdsB\x15\n\x13_collection_contextB\x0b\n\t_pipeline\"\x93\x02\n\x10VelocityFeatures\x12*\n\x11posts_last_minute\x18\x01 \x01(\x03R\x0fpostsLastMinute\x12&\n\x0fposts_last_hour\x18\x02 \x01(\x03R\rpostsLastHour\x12(\n\x10images_last_hour\x18\x03 \x01(\x03R\x0eimagesLastHour\x12=\n\x1b\x64istinct_mentions_last_hour\x18\x04 \x01(\x03R\x18\x64istinctMentionsLastHour\x12\x42\n\x1eidentical_text_posts_last_hour\x18\x05 \x01(\x03R\x1aidenticalTextPostsLastHour\"s\n\rTermListMatch\x12\x12\n\x04list\x18\x01 \x01(\tR\x04list\x12\x1a\n\x08language\x18\x02 \x01(\tR\x08language\x12\x1a\n\x08severity\x18\x03 \x01(\tR\x08severity\x12\x16\n\x06\x66ields\x18\x04 \x03(\tR\x06\x66ields\"\x90\x01\n\x12ImpersonationMatch\x12#\n\rprotected_did\x18\x01 \x01(\tR\x0cprotectedDid\x12)\n\x10protected_handle\x18\x02 \x01(\tR\x0fprotectedHandle\x12\x14\n\x05\x66ield\x18\x03 \x01(\tR\x05\x66ield\x12\x14\n\x05score\x18\x04 \x01(\x01R\x05score\"\xc2\x02\n\x10IdentityFeatures\x12H\n\x12\x61\x63\x63ount_created_at\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x10\x61\x63\x63ountCreatedAt\x12.\n\x13\x61\x63\x63ount_age_seconds\x18\x02 \x01(\x03R\x11\x61\x63\x63ountAgeSeconds\x12\'\n\x0foperation_count\x18\x03 \x01(\x03R\x0eoperationCount\x12\x32\n\x15recent_handle_changes\x18\x04 \x01(\x03R\x13recentHandleChanges\x12%\n\x0epds_migrations\x18\x05 \x01(\x03R\rpdsMigrations\x12\x30\n\x14rotation_key_changes\x18\x06 \x01(\x03R\x12rotationKeyChanges\"\xdf\x01\n\x0bPdsFeatures\x12\x12\n\x04host\x18\x01 \x01(\tR\x04host\x12%\n\x0e\x62luesky_hosted\x18\x02 \x01(\x08R\rblueskyHosted\x12\x42\n\x0fhost_first_seen\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\rhostFirstSeen\x12(\n\x10host_age_seconds\x18\x04 \x01(\x03R\x0ehostAgeSeconds\x12\'\n\x0fsuspicious_host\x18\x05 \x01(\x08R\x0esuspiciousHost\"\x9d\x02\n\x11\x43ollectionContext\x12.\n\x10service_endpoint\x18\x01 \x01(\tH\x00R\x0fserviceEndpoint\x88\x01\x01\x12$\n\x0bservice_did\x18\x02 \x01(\tH\x01R\nserviceDid\x88\x01\x01\x12\x1e\n\x08list_uri\x18\x03 \x01(\tH\x02R\x07listUri\x88\x01\x01\x12+\n\x0flist_item_count\x18\x04 \x01(\x03H\x03R\rlistItemCount\x88\x01\x01\x12\x1f\n\x0b\x61vatar_cids\x18\x05 \x03(\tR\navatarCidsB\x13\n\x11_service_endpointB\x0e\n\x0c_service_didB\x0b\n\t_list_uriB\x12\n\x10_list_item_count\"n\n\rExistingLabel\x12\x10\n\x03uri\x18\x01 \x01(\tR\x03uri\x12\x10\n\x03val\x18\x02 \x01(\tR\x03val\x12\x39\n\ncreated_at\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\tcreatedAt\"\x99\x02\n\rPipelineTimes\x12\x43\n\x0f\x65vent_timestamp\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x0e\x65ventTimestamp\x12N\n\x15\x65nrichment_started_at\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x13\x65nrichmentStartedAt\x12P\n\x16\x65nrichment_finished_at\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\x14\x65nrichmentFinishedAt\x12!\n\x0cstaleness_ms\x18\x04 \x01(\x03R\x0bstalenessMs\"~\n\x10\x42lobTypeMismatch\x12\x10\n\x03\x63id\x18\x01 \x01(\tR\x03\x63id\x12,\n\x12\x64\x65\x63lared_mime_type\x18\x02 \x01(\tR\x10\x64\x65\x63laredMimeType\x12*\n\x11sniffed_mime_type\x18\x03 \x01(\tR\x0fsniffedMimeType\"\xcc\x15\n\x14ImageDispatchResults\x12\x10\n\x03\x63id\x18\x01 \x01(\tR\x03\x63id\x12\x44\n\x05\x61\x62yss\x18\x02 \x01(\x0b\x32).osprey.ImageDispatchResults.AbyssResultsH\x00R\x05\x61\x62yss\x88\x01\x01\x12\x41\n\x04hive\x18\x03 \x01(\x0b\x32(.osprey.ImageDispatchResults.HiveResultsH\x01R\x04hive\x88\x01\x01\x12G\n\x06retina\x18\x04 \x01(\x0b\x32*.osprey.ImageDispatchResults.RetinaResultsH\x02R\x06retina\x88\x01\x01\x12P\n\tprescreen\x18\x06 \x01(\x0b\x32-.osprey.ImageDispatchResults.PrescreenResultsH\x03R\tprescreen\x88\x01\x01\x12T\n\x0bretina_hash\x18\x07 \x01(\x0b\x32..osprey.ImageDispatchResults.RetinaHashResultsH\x04R\nretinaHash\x88\x01\x01\x12\x41\n\x04ncii\x18\x08 \x01(\x0b\x32(.osprey.ImageDispatchResults.NciiResultsH\x05R\x04ncii\x88\x01\x01\x12J\n\x07\x66lagged\x18\t \x01(\x0b\x32+.osprey.ImageDispatchResults.FlaggedResultsH\x06R\x07\x66lagged\x88\x01\x01\x12P\n\tanimation\x18\n \x01(\x0b\x32-.osprey.ImageDispatchResults.AnimationResultsH\x07R\tanimation\x88\x01\x01\x12I\n\tsightings\x18\x0b \x01(\x0b\x32&.osprey.ImageDispatchResults.SightingsH\x08R\tsightings\x88\x01\x01\x12G\n\tlink_card\x18\x0c \x01(\x0b\x32%.osprey.ImageDispatchResults.LinkCardH\tR\x08linkCard\x88\x01\x01\x1a\x90\x01\n\x0c\x41\x62yssResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12)\n\x0eis_abuse_match\x18\x03 \x01(\x08H\x02R\x0cisAbuseMatch\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x11\n\x0f_is_abuse_match\x1a\x9d\x02\n\x0bHiveResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12O\n\x07\x63lasses\x18\x03 \x03(\x0b\x32\x35.osprey.ImageDispatchResults.HiveResults.ClassesEntryR\x07\x63lasses\x12*\n\x0e\x62udget_skipped\x18\x04 \x01(\x08H\x02R\rbudgetSkipped\x88\x01\x01\x1a:\n\x0c\x43lassesEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\x01R\x05value:\x02\x38\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x11\n\x0f_budget_skipped\x1au\n\rRetinaResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x17\n\x04text\x18\x03 \x01(\tH\x02R\x04text\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x07\n\x05_text\x1a\xba\x01\n\x11RetinaHashResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x17\n\x04hash\x18\x03 \x01(\tH\x02R\x04hash\x88\x01\x01\x12+\n\x0fquality_too_low\x18\x04 \x01(\x08H\x03R\rqualityTooLow\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x07\n\x05_hashB\x12\n\x10_quality_too_low\x1a\xb7\x01\n\x10PrescreenResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x1f\n\x08\x64\x65\x63ision\x18\x03 \x01(\tH\x02R\x08\x64\x65\x63ision\x88\x01\x01\x12\"\n\nqa_sampled\x18\x04 \x01(\x08H\x03R\tqaSampled\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x0b\n\t_decisionB\r\n\x0b_qa_sampled\x1a\xa3\x01\n\x0bNciiResults\x12\x15\n\x03raw\x18\x01 \x01(\x0cH\x00R\x03raw\x88\x01\x01\x12\x19\n\x05\x65rror\x18\x02 \x01(\tH\x01R\x05\x65rror\x88\x01\x01\x12\x1e\n\x08is_match\x18\x03 \x01(\x08H\x02R\x07isMatch\x88\x01\x01\x12\x19\n\x05score\x18\x04 \x01(\x01H\x03R\x05score\x88\x01\x01\x42\x06\n\x04_rawB\x08\n\x06_errorB\x0b\n\t_is_matchB\x08\n\x06_score\x1a\x94\x03\n\x0e\x46laggedResults\x12\x19\n\x05\x65rror\x18\x01 \x01(\tH\x00R\x05\x65rror\x88\x01\x01\x12\x1e\n\x08is_match\x18\x02 \x01(\x08H\x01R\x07isMatch\x88\x01\x01\x12\x1b\n\x06\x61\x63tion\x18\x03 \x01(\tH\x02R\x06\x61\x63tion\x88\x01\x01\x12&\n\x0c\x61\x63tion_level\x18\x04 \x01(\tH\x03R\x0b\x61\x63tionLevel\x88\x01\x01\x12&\n\x0c\x61\x63tion_value\x18\x05 \x01(\tH\x04R\x0b\x61\x63tionValue\x88\x01\x01\x12(\n\ralways_report\x18\x06 \x01(\x08H\x05R\x0c\x61lwaysReport\x88\x01\x01\x12%\n\x0b\x64\x65scription\x18\x07 \x01(\tH\x06R\x0b\x64\x65scription\x88\x01\x01\x12\x19\n\x05score\x18\x08 \x01(\x01H\x07R\x05score\x88\x01\x01\x42\x08\n\x06_errorB\x0b\n\t_is_matchB\t\n\x07_actionB\x0f\n\r_action_levelB\x0f\n\r_action_valueB\x10\n\x0e_always_reportB\x0e\n\x0c_descriptionB\x08\n\x06_score\x1a{\n\x10\x41nimationResults\x12\x1f\n\x0b\x66rame_count\x18\x01 \x01(\x05R\nframeCount\x12%\n\x0esampled_frames\x18\x02 \x01(\x05R\rsampledFrames\x12\x1f\n\x0bworst_frame\x18\x03 \x01(\x05R\nworstFrame\x1a\xa8\x01\n\tSightings\x12\x14\n\x05\x63ount\x18\x01 \x01(\x03R\x05\x63ount\x12#\n\rdistinct_dids\x18\x02 \x01(\x03R\x0c\x64istinctDids\x12\x39\n\nfirst_seen\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\tfirstSeen\x12%\n\x0ewindow_seconds\x18\x04 \x01(\x03R\rwindowSeconds\x1a\x32\n\x08LinkCard\x12\x10\n\x03uri\x18\x01 \x01(\tR\x03uri\x12\x14\n\x05title\x18\x02 \x01(\tR\x05titleB\x08\n\x06_abyssB\x07\n\x05_hiveB\t\n\x07_retinaB\x0c\n\n_prescreenB\x0e\n\x0c_retina_hashB\x07\n\x05_nciiB\n\n\x08_flaggedB\x0c\n\n_animationB\x0c\n\n_sightingsB\x0c\n\n_link_card\"\xfe\x02\n\x15ModerationReportEvent\x12\x1b\n\treport_id\x18\x01 \x01(\x03R\x08reportId\x12\x16\n\x06source\x18\x02 \x01(\tR\x06source\x12\x1f\n\x0breason_type\x18\x03 \x01(\tR\nreasonType\x12\x1b\n\x06reason\x18\x04 \x01(\tH\x00R\x06reason\x88\x01\x01\x12\x1f\n\x0bsubject_did\x18\x05 \x01(\tR\nsubjectDid\x12$\n\x0bsubject_uri\x18\x06 \x01(\tH\x01R\nsubjectUri\x88\x01\x01\x12$\n\x0bsubject_cid\x18\x07 \x01(\tH\x02R\nsubjectCid\x88\x01\x01\x12\x1f\n\x0breported_by\x18\x08 \x01(\tR\nreportedBy\x12\x39\n\ncreated_at\x18\t \x01(\x0b\x32\x1a.google.protobuf.TimestampR\tcreatedAtB\t\n\x07_reasonB\x0e\n\x0c_subject_uriB\x0e\n\x0c_subject_cid\"\xf6\x02\n\x11LabelExpiredEvent\x12\x1f\n\x0bsubject_did\x18\x01 \x01(\tR\nsubjectDid\x12$\n\x0bsubject_uri\x18\x02 \x01(\tH\x00R\nsubjectUri\x88\x01\x01\x12\x14\n\x05label\x18\x03 \x01(\tR\x05label\x12\x14\n\x05rules\x18\x04 \x03(\tR\x05rules\x12\x1f\n\x0b\x61\x63tion_name\x18\x05 \x01(\tR\nactionName\x12\x1b\n\taction_id\x18\x06 \x01(\x03R\x08\x61\x63tionId\x12*\n\x11\x64uration_in_hours\x18\x07 \x01(\x03R\x0f\x64urationInHours\x12\x39\n\napplied_at\x18\x08 \x01(\x0b\x32\x1a.google.protobuf.TimestampR\tappliedAt\x12\x39\n\nexpired_at\x18\t \x01(\x0b\x32\x1a.google.protobuf.TimestampR\texpiredAtB\x0e\n\x0c_subject_uri\"\x7f\n\x12RetinaImageRequest\x12\x10\n\x03\x64id\x18\x01 \x01(\tR\x03\x64id\x12\x10\n\x03\x63id\x18\x02 \x01(\tR\x03\x63id\x12*\n\x03ocr\x18\x03 \x01(\x0b\x32\x18.osprey.RetinaOCROptionsR\x03ocr\x12\x19\n\x08\x63\x64n_host\x18\x04 \x01(\tR\x07\x63\x64nHost\"_\n\x10RetinaImageChunk\x12/\n\x03ocr\x18\x01 \x01(\x0b\x32\x18.osprey.RetinaOCROptionsH\x00R\x03ocr\x88\x01\x01\x12\x12\n\x04\x64\x61ta\x18\x02 \x01(\x0cR\x04\x64\x61taB\x06\n\x04_ocr\"H\n\x10RetinaOCROptions\x12\x14\n\x05langs\x18\x01 \x01(\tR\x05langs\x12\x1e\n\npreprocess\x18\x02 \x01(\tR\npreprocess\"+\n\x15RetinaAnalyzeResponse\x12\x12\n\x04text\x18\x01 \x01(\tR\x04text\"\x9f\x01\n\x12RetinaHashResponse\x12\x12\n\x04hash\x18\x01 \x01(\tR\x04hash\x12\x16\n\x06\x62inary\x18\x02 \x01(\tR\x06\x62inary\x12&\n\x0fquality_too_low\x18\x03 \x01(\x08R\rqualityTooLow\x12\x35\n\tchecksums\x18\x04 \x01(\x0b\x32\x17.osprey.RetinaChecksumsR\tchecksums\"O\n\x0fRetinaChecksums\x12\x10\n\x03md5\x18\x01 \x01(\tR\x03md5\x12\x12\n\x04sha1\x18\x02 \x01(\tR\x04sha1\x12\x16\n\x06sha256\x18\x03 \x01(\tR\x06sha256*\x96\x01\n\x12\x41tprotoSubjectKind\x12\x1d\n\x19\x41TPROTO_SUBJECT_KIND_NONE\x10\x00\x12\x1e\n\x1a\x41TPROTO_SUBJECT_KIND_ACTOR\x10\x01\x12\x1f\n\x1b\x41TPROTO_SUBJECT_KIND_RECORD\x10\x02\x12 \n\x1c\x41TPROTO_SUBJECT_KIND_MESSAGE\x10\x03*\xf6\x01\n\x0c\x41tprotoLabel\x12\x16\n\x12\x41TPROTO_LABEL_NONE\x10\x00\x12\x16\n\x12\x41TPROTO_LABEL_SPAM\x10\x01\x12\x16\n\x12\x41TPROTO_LABEL_RUDE\x10\x02\x12\x16\n\x12\x41TPROTO_LABEL_PORN\x10\x03\x12\x18\n\x14\x41TPROTO_LABEL_SEXUAL\x10\x04\x12\x16\n\x12\x41TPROTO_LABEL_WARN\x10\x05\x12\x16\n\x12\x41TPROTO_LABEL_HIDE\x10\x06\x12\x1e\n\x1a\x41TPROTO_LABEL_NEEDS_REVIEW\x10\x07\x12\x1c\n\x18\x41TPROTO_LABEL_MISLEADING\x10\x08*n\n\x11\x41tprotoEffectKind\x12\x1c\n\x18\x41TPROTO_EFFECT_KIND_NONE\x10\x00\x12\x1b\n\x17\x41TPROTO_EFFECT_KIND_ADD\x10\x01\x12\x1e\n\x1a\x41TPROTO_EFFECT_KIND_REMOVE\x10\x02*\x93\x04\n\x0c\x41tprotoEmail\x12\x16\n\x12\x41TPROTO_EMAIL_NONE\x10\x00\x12\x1c\n\x18\x41TPROTO_EMAIL_SPAM_REPLY\x10\x44\x12 \n\x1b\x41TPROTO_EMAIL_SPAM_TAKEDOWN\x10\x85\x02\x12\x1b\n\x17\x41TPROTO_EMAIL_SPAM_FAKE\x10?\x12\x1d\n\x18\x41TPROTO_EMAIL_SPAM_LABEL\x10\xbe\x02\x12&\n!ATPROTO_EMAIL_SPAM_LABEL_24_HOURS\x10\xae\x02\x12&\n!ATPROTO_EMAIL_SPAM_LABEL_72_HOURS\x10\xb9\x02\x12\x1d\n\x18\x41TPROTO_EMAIL_ID_REQUEST\x10\x99\x02\x12%\n!ATPROTO_EMAIL_IMPERSONATION_LABEL\x10\x1f\x12#\n\x1e\x41TPROTO_EMAIL_AUTOMOD_TAKEDOWN\x10\xa0\x02\x12\x1f\n\x1b\x41TPROTO_EMAIL_REINSTATEMENT\x10<\x12&\n\"ATPROTO_EMAIL_THREAT_POST_TAKEDOWN\x10\x1a\x12\'\n#ATPROTO_EMAIL_PEDO_ACCOUNT_TAKEDOWN\x10+\x12\x1f\n\x1a\x41TPROTO_EMAIL_DMS_DISABLED\x10\xa7\x02\x12!\n\x1d\x41TPROTO_EMAIL_TOXIC_LIST_HIDE\x10\x33*\xf3\x01\n\x11\x41tprotoReportKind\x12\x1c\n\x18\x41TPROTO_REPORT_KIND_NONE\x10\x00\x12\x1c\n\x18\x41TPROTO_REPORT_KIND_SPAM\x10\x01\x12!\n\x1d\x41TPROTO_REPORT_KIND_VIOLATION\x10\x02\x12\"\n\x1e\x41TPROTO_REPORT_KIND_MISLEADING\x10\x03\x12\x1e\n\x1a\x41TPROTO_REPORT_KIND_SEXUAL\x10\x04\x12\x1c\n\x18\x41TPROTO_REPORT_KIND_RUDE\x10\x05\x12\x1d\n\x19\x41TPROTO_REPORT_KIND_OTHER\x10\x06*o\n\tEventKind\x12\x1a\n\x16\x45VENT_KIND_UNSPECIFIED\x10\x00\x12\x15\n\x11\x45VENT_KIND_COMMIT\x10\x01\x12\x16\n\x12\x45VENT_KIND_ACCOUNT\x10\x02\x12\x17\n\x13\x45VENT_KIND_IDENTITY\x10\x03*\x8a\x01\n\x0f\x43ommitOperation\x12 \n\x1c\x43OMMIT_OPERATION_UNSPECIFIED\x10\x00\x12\x1b\n\x17\x43OMMIT_OPERATION_CREATE\x10\x01\x12\x1b\n\x17\x43OMMIT_OPERATION_UPDATE\x10\x02\x12\x1b\n\x17\x43OMMIT_OPERATION_DELETE\x10\x03\x32\x9c\x02\n\x06Retina\x12\x44\n\x07\x41nalyze\x12\x1a.osprey.RetinaImageRequest\x1a\x1d.osprey.RetinaAnalyzeResponse\x12H\n\x0b\x41nalyzeBlob\x12\x18.osprey.RetinaImageChunk\x1a\x1d.osprey.RetinaAnalyzeResponse(\x01\x12>\n\x04Hash\x12\x1a.osprey.RetinaImageRequest\x1a\x1a.osprey.RetinaHashResponse\x12\x42\n\x08HashBlob\x12\x18.osprey.RetinaImageChunk\x1a\x1a.osprey.RetinaHashResponse(\x01\x42\x63\n\ncom.ospreyB\x12OspreyAtprotoProtoP\x01Z\t./;osprey\xa2\x02\x03OXX\xaa\x02\x06Osprey\xca\x02\x06Osprey\xe2\x02\x12Osprey\\GPBMetadata\xea\x02\x06Ospreyb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -29,20 +29,20 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT_IMAGERESULTSENTRY']._serialized_options = b'8\001'
   _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS_CLASSESENTRY']._loaded_options = None
   _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS_CLASSESENTRY']._serialized_options = b'8\001'
-  _globals['_ATPROTOSUBJECTKIND']._serialized_start=6351
-  _globals['_ATPROTOSUBJECTKIND']._serialized_end=6467
-  _globals['_ATPROTOLABEL']._serialized_start=6470
-  _globals['_ATPROTOLABEL']._serialized_end=6716
-  _globals['_ATPROTOEFFECTKIND']._serialized_start=6718
-  _globals['_ATPROTOEFFECTKIND']._serialized_end=6828
-  _globals['_ATPROTOEMAIL']._serialized_start=6831
-  _globals['_ATPROTOEMAIL']._serialized_end=7362
-  _globals['_ATPROTOREPORTKIND']._serialized_start=7365
-  _globals['_ATPROTOREPORTKIND']._serialized_end=7608
-  _globals['_EVENTKIND']._serialized_start=7610
-  _globals['_EVENTKIND']._serialized_end=7721
-  _globals['_COMMITOPERATION']._serialized_start=7724
-  _globals['_COMMITOPERATION']._serialized_end=7862
+  _globals['_ATPROTOSUBJECTKIND']._serialized_start=13315
+  _globals['_ATPROTOSUBJECTKIND']._serialized_end=13465
+  _globals['_ATPROTOLABEL']._serialized_start=13468
+  _globals['_ATPROTOLABEL']._serialized_end=13714
+  _globals['_ATPROTOEFFECTKIND']._serialized_start=13716
+  _globals['_ATPROTOEFFECTKIND']._serialized_end=13826
+  _globals['_ATPROTOEMAIL']._serialized_start=13829
+  _globals['_ATPROTOEMAIL']._serialized_end=14360
+  _globals['_ATPROTOREPORTKIND']._serialized_start=14363
+  _globals['_ATPROTOREPORTKIND']._serialized_end=14606
+  _globals['_EVENTKIND']._serialized_start=14608
+  _globals['_EVENTKIND']._serialized_end=14719
+  _globals['_COMMITOPERATION']._serialized_start=14722
+  _globals['_COMMITOPERATION']._serialized_end=14860
   _globals['_OSPREYINPUTEVENT']._serialized_start=65
   _globals['_OSPREYINPUTEVENT']._serialized_end=190
   _globals['_OSPREYINPUTEVENTDATA']._serialized_start=193
@@ -52,49 +52,109 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_ATPROTOLABELEFFECT']._serialized_start=528
   _globals['_ATPROTOLABELEFFECT']._serialized_end=899
   _globals['_ATPROTOTAGEFFECT']._serialized_start=902
-  _globals['_ATPROTOTAGEFFECT']._serialized_end=1126
-  _globals['_ATPROTOTAKEDOWNEFFECT']._serialized_start=1129
-  _globals['_ATPROTOTAKEDOWNEFFECT']._serialized_end=1382
-  _globals['_ATPROTOEMAILEFFECT']._serialized_start=1385
-  _globals['_ATPROTOEMAILEFFECT']._serialized_end=1514
-  _globals['_ATPROTOCOMMENTEFFECT']._serialized_start=1517
-  _globals['_ATPROTOCOMMENTEFFECT']._serialized_end=1650
-  _globals['_ATPROTOESCALATEEFFECT']._serialized_start=1653
-  _globals['_ATPROTOESCALATEEFFECT']._serialized_end=1804
-  _globals['_ATPROTOACKNOWLEDGEEFFECT']._serialized_start=1807
-  _globals['_ATPROTOACKNOWLEDGEEFFECT']._serialized_end=1961
-  _globals['_ATPROTOREPORTEFFECT']._serialized_start=1964
-  _globals['_ATPROTOREPORTEFFECT']._serialized_end=2219
-  _globals['_BIGQUERYFLAGEFFECT']._serialized_start=2222
-  _globals['_BIGQUERYFLAGEFFECT']._serialized_end=2388
-  _globals['_RESULTEVENT']._serialized_start=2391
-  _globals['_RESULTEVENT']._serialized_end=3130
-  _globals['_FIREHOSEEVENT']._serialized_start=3133
-  _globals['_FIREHOSEEVENT']._serialized_end=3357
-  _globals['_COMMIT']._serialized_start=3360
-  _globals['_COMMIT']._serialized_end=3535
-  _globals['_CURSOR']._serialized_start=3537
-  _globals['_CURSOR']._serialized_end=3609
-  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT']._serialized_start=3612
-  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT']._serialized_end=4316
-  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT_IMAGERESULTSENTRY']._serialized_start=4149
-  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT_IMAGERESULTSENTRY']._serialized_end=4242
-  _globals['_IMAGEDISPATCHRESULTS']._serialized_start=4319
-  _globals['_IMAGEDISPATCHRESULTS']._serialized_end=6349
-  _globals['_IMAGEDISPATCHRESULTS_ABYSSRESULTS']._serialized_start=4883
-  _globals['_IMAGEDISPATCHRESULTS_ABYSSRESULTS']._serialized_end=5027
-  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS']._serialized_start=5030
-  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS']._serialized_end=5252
-  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS_CLASSESENTRY']._serialized_start=5176
-  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS_CLASSESENTRY']._serialized_end=5234
-  _globals['_IMAGEDISPATCHRESULTS_RETINARESULTS']._serialized_start=5254
-  _globals['_IMAGEDISPATCHRESULTS_RETINARESULTS']._serialized_end=5371
-  _globals['_IMAGEDISPATCHRESULTS_RETINAHASHRESULTS']._serialized_start=5374
-  _globals['_IMAGEDISPATCHRESULTS_RETINAHASHRESULTS']._serialized_end=5560
-  _globals['_IMAGEDISPATCHRESULTS_PRESCREENRESULTS']._serialized_start=5563
-  _globals['_IMAGEDISPATCHRESULTS_PRESCREENRESULTS']._serialized_end=5695
-  _globals['_IMAGEDISPATCHRESULTS_NCIIRESULTS']._serialized_start=5698
-  _globals['_IMAGEDISPATCHRESULTS_NCIIRESULTS']._serialized_end=5861
-  _globals['_IMAGEDISPATCHRESULTS_FLAGGEDRESULTS']._serialized_start=5864
-  _globals['_IMAGEDISPATCHRESULTS_FLAGGEDRESULTS']._serialized_end=6268
+  _globals['_ATPROTOTAGEFFECT']._serialized_end=1203
+  _globals['_ATPROTOTAKEDOWNEFFECT']._serialized_start=1206
+  _globals['_ATPROTOTAKEDOWNEFFECT']._serialized_end=1558
+  _globals['_ATPROTOEMAILEFFECT']._serialized_start=1561
+  _globals['_ATPROTOEMAILEFFECT']._serialized_end=1690
+  _globals['_ATPROTOMUTEEFFECT']._serialized_start=1693
+  _globals['_ATPROTOMUTEEFFECT']._serialized_end=1944
+  _globals['_ATPROTODIVERTEFFECT']._serialized_start=1947
+  _globals['_ATPROTODIVERTEFFECT']._serialized_end=2125
+  _globals['_ATPROTOCOMMENTEFFECT']._serialized_start=2128
+  _globals['_ATPROTOCOMMENTEFFECT']._serialized_end=2261
+  _globals['_ATPROTOESCALATEEFFECT']._serialized_start=2264
+  _globals['_ATPROTOESCALATEEFFECT']._serialized_end=2452
+  _globals['_ATPROTORESOLVEAPPEALEFFECT']._serialized_start=2455
+  _globals['_ATPROTORESOLVEAPPEALEFFECT']._serialized_end=2611
+  _globals['_ATPROTOSETEFFECT']._serialized_start=2614
+  _globals['_ATPROTOSETEFFECT']._serialized_end=2775
+  _globals['_ATPROTOACKNOWLEDGEEFFECT']._serialized_start=2778
+  _globals['_ATPROTOACKNOWLEDGEEFFECT']._serialized_end=2932
+  _globals['_ATPROTOREPORTEFFECT']._serialized_start=2935
+  _globals['_ATPROTOREPORTEFFECT']._serialized_end=3190
+  _globals['_BIGQUERYFLAGEFFECT']._serialized_start=3193
+  _globals['_BIGQUERYFLAGEFFECT']._serialized_end=3359
+  _globals['_RESULTEVENT']._serialized_start=3362
+  _globals['_RESULTEVENT']._serialized_end=4513
+  _globals['_EVIDENCE']._serialized_start=4516
+  _globals['_EVIDENCE']._serialized_end=4647
+  _globals['_CHATMESSAGEREF']._serialized_start=4649
+  _globals['_CHATMESSAGEREF']._serialized_end=4741
+  _globals['_DELAYEDEFFECTS']._serialized_start=4744
+  _globals['_DELAYEDEFFECTS']._serialized_end=4873
+  _globals['_FAILEDEFFECTS']._serialized_start=4876
+  _globals['_FAILEDEFFECTS']._serialized_end=5061
+  _globals['_EFFECTOUTCOME']._serialized_start=5064
+  _globals['_EFFECTOUTCOME']._serialized_end=5417
+  _globals['_FIREHOSEEVENT']._serialized_start=5420
+  _globals['_FIREHOSEEVENT']._serialized_end=5644
+  _globals['_COMMIT']._serialized_start=5647
+  _globals['_COMMIT']._serialized_end=5822
+  _globals['_CURSOR']._serialized_start=5824
+  _globals['_CURSOR']._serialized_end=5896
+  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT']._serialized_start=5899
+  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT']._serialized_end=7290
+  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT_IMAGERESULTSENTRY']._serialized_start=7053
+  _globals['_MODERATIONENRICHEDFIREHOSERECORDEVENT_IMAGERESULTSENTRY']._serialized_end=7146
+  _globals['_VELOCITYFEATURES']._serialized_start=7293
+  _globals['_VELOCITYFEATURES']._serialized_end=7568
+  _globals['_TERMLISTMATCH']._serialized_start=7570
+  _globals['_TERMLISTMATCH']._serialized_end=7685
+  _globals['_IMPERSONATIONMATCH']._serialized_start=7688
+  _globals['_IMPERSONATIONMATCH']._serialized_end=7832
+  _globals['_IDENTITYFEATURES']._serialized_start=7835
+  _globals['_IDENTITYFEATURES']._serialized_end=8157
+  _globals['_PDSFEATURES']._serialized_start=8160
+  _globals['_PDSFEATURES']._serialized_end=8383
+  _globals['_COLLECTIONCONTEXT']._serialized_start=8386
+  _globals['_COLLECTIONCONTEXT']._serialized_end=8671
+  _globals['_EXISTINGLABEL']._serialized_start=8673
+  _globals['_EXISTINGLABEL']._serialized_end=8783
+  _globals['_PIPELINETIMES']._serialized_start=8786
+  _globals['_PIPELINETIMES']._serialized_end=9067
+  _globals['_BLOBTYPEMISMATCH']._serialized_start=9069
+  _globals['_BLOBTYPEMISMATCH']._serialized_end=9195
+  _globals['_IMAGEDISPATCHRESULTS']._serialized_start=9198
+  _globals['_IMAGEDISPATCHRESULTS']._serialized_end=11962
+  _globals['_IMAGEDISPATCHRESULTS_ABYSSRESULTS']._serialized_start=9992
+  _globals['_IMAGEDISPATCHRESULTS_ABYSSRESULTS']._serialized_end=10136
+  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS']._serialized_start=10139
+  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS']._serialized_end=10424
+  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS_CLASSESENTRY']._serialized_start=10329
+  _globals['_IMAGEDISPATCHRESULTS_HIVERESULTS_CLASSESENTRY']._serialized_end=10387
+  _globals['_IMAGEDISPATCHRESULTS_RETINARESULTS']._serialized_start=10426
+  _globals['_IMAGEDISPATCHRESULTS_RETINARESULTS']._serialized_end=10543
+  _globals['_IMAGEDISPATCHRESULTS_RETINAHASHRESULTS']._serialized_start=10546
+  _globals['_IMAGEDISPATCHRESULTS_RETINAHASHRESULTS']._serialized_end=10732
+  _globals['_IMAGEDISPATCHRESULTS_PRESCREENRESULTS']._serialized_start=10735
+  _globals['_IMAGEDISPATCHRESULTS_PRESCREENRESULTS']._serialized_end=10918
+  _globals['_IMAGEDISPATCHRESULTS_NCIIRESULTS']._serialized_start=10921
+  _globals['_IMAGEDISPATCHRESULTS_NCIIRESULTS']._serialized_end=11084
+  _globals['_IMAGEDISPATCHRESULTS_FLAGGEDRESULTS']._serialized_start=11087
+  _globals['_IMAGEDISPATCHRESULTS_FLAGGEDRESULTS']._serialized_end=11491
+  _globals['_IMAGEDISPATCHRESULTS_ANIMATIONRESULTS']._serialized_start=11493
+  _globals['_IMAGEDISPATCHRESULTS_ANIMATIONRESULTS']._serialized_end=11616
+  _globals['_IMAGEDISPATCHRESULTS_SIGHTINGS']._serialized_start=11619
+  _globals['_IMAGEDISPATCHRESULTS_SIGHTINGS']._serialized_end=11787
+  _globals['_IMAGEDISPATCHRESULTS_LINKCARD']._serialized_start=11789
+  _globals['_IMAGEDISPATCHRESULTS_LINKCARD']._serialized_end=11839
+  _globals['_MODERATIONREPORTEVENT']._serialized_start=11965
+  _globals['_MODERATIONREPORTEVENT']._serialized_end=12347
+  _globals['_LABELEXPIREDEVENT']._serialized_start=12350
+  _globals['_LABELEXPIREDEVENT']._serialized_end=12724
+  _globals['_RETINAIMAGEREQUEST']._serialized_start=12726
+  _globals['_RETINAIMAGEREQUEST']._serialized_end=12853
+  _globals['_RETINAIMAGECHUNK']._serialized_start=12855
+  _globals['_RETINAIMAGECHUNK']._serialized_end=12950
+  _globals['_RETINAOCROPTIONS']._serialized_start=12952
+  _globals['_RETINAOCROPTIONS']._serialized_end=13024
+  _globals['_RETINAANALYZERESPONSE']._serialized_start=13026
+  _globals['_RETINAANALYZERESPONSE']._serialized_end=13069
+  _globals['_RETINAHASHRESPONSE']._serialized_start=13072
+  _globals['_RETINAHASHRESPONSE']._serialized_end=13231
+  _globals['_RETINACHECKSUMS']._serialized_start=13233
+  _globals['_RETINACHECKSUMS']._serialized_end=13312
+  _globals['_RETINA']._serialized_start=14863
+  _globals['_RETINA']._serialized_end=15147
 # @@protoc_insertion_point(module_scope)
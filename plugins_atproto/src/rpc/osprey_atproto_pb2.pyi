@@ -12,6 +12,7 @@ class AtprotoSubjectKind(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
     ATPROTO_SUBJECT_KIND_NONE: _ClassVar[AtprotoSubjectKind]
     ATPROTO_SUBJECT_KIND_ACTOR: _ClassVar[AtprotoSubjectKind]
     ATPROTO_SUBJECT_KIND_RECORD: _ClassVar[AtprotoSubjectKind]
+    ATPROTO_SUBJECT_KIND_MESSAGE: _ClassVar[AtprotoSubjectKind]
 
 class AtprotoLabel(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
     __slots__ = ()
@@ -75,6 +76,7 @@ class CommitOperation(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
 ATPROTO_SUBJECT_KIND_NONE: AtprotoSubjectKind
 ATPROTO_SUBJECT_KIND_ACTOR: AtprotoSubjectKind
 ATPROTO_SUBJECT_KIND_RECORD: AtprotoSubjectKind
+ATPROTO_SUBJECT_KIND_MESSAGE: AtprotoSubjectKind
 ATPROTO_LABEL_NONE: AtprotoLabel
 ATPROTO_LABEL_SPAM: AtprotoLabel
 ATPROTO_LABEL_RUDE: AtprotoLabel
@@ -168,32 +170,38 @@ class AtprotoLabelEffect(_message.Message):
     def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., label: _Optional[_Union[AtprotoLabel, str]] = ..., comment: _Optional[str] = ..., email: _Optional[_Union[AtprotoEmail, str]] = ..., expiration_in_hours: _Optional[int] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
 
 class AtprotoTagEffect(_message.Message):
-    __slots__ = ("effect_kind", "subject_kind", "tag", "comment", "rules")
+    __slots__ = ("effect_kind", "subject_kind", "tag", "comment", "rules", "expiration_in_hours")
     EFFECT_KIND_FIELD_NUMBER: _ClassVar[int]
     SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
     TAG_FIELD_NUMBER: _ClassVar[int]
     COMMENT_FIELD_NUMBER: _ClassVar[int]
     RULES_FIELD_NUMBER: _ClassVar[int]
+    EXPIRATION_IN_HOURS_FIELD_NUMBER: _ClassVar[int]
     effect_kind: AtprotoEffectKind
     subject_kind: AtprotoSubjectKind
     tag: str
     comment: str
     rules: _containers.RepeatedScalarFieldContainer[str]
-    def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., tag: _Optional[str] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
+    expiration_in_hours: int
+    def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., tag: _Optional[str] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ..., expiration_in_hours: _Optional[int] = ...) -> None: ...
 
 class AtprotoTakedownEffect(_message.Message):
-    __slots__ = ("effect_kind", "subject_kind", "comment", "email", "rules")
+    __slots__ = ("effect_kind", "subject_kind", "comment", "email", "rules", "policies", "duration_in_hours")
     EFFECT_KIND_FIELD_NUMBER: _ClassVar[int]
     SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
     COMMENT_FIELD_NUMBER: _ClassVar[int]
     EMAIL_FIELD_NUMBER: _ClassVar[int]
     RULES_FIELD_NUMBER: _ClassVar[int]
+    POLICIES_FIELD_NUMBER: _ClassVar[int]
+    DURATION_IN_HOURS_FIELD_NUMBER: _ClassVar[int]
     effect_kind: AtprotoEffectKind
     subject_kind: AtprotoSubjectKind
     comment: str
     email: AtprotoEmail
     rules: _containers.RepeatedScalarFieldContainer[str]
-    def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., comment: _Optional[str] = ..., email: _Optional[_Union[AtprotoEmail, str]] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
+    policies: _containers.RepeatedScalarFieldContainer[str]
+    duration_in_hours: int
+    def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., comment: _Optional[str] = ..., email: _Optional[_Union[AtprotoEmail, str]] = ..., rules: _Optional[_Iterable[str]] = ..., policies: _Optional[_Iterable[str]] = ..., duration_in_hours: _Optional[int] = ...) -> None: ...
 
 class AtprotoEmailEffect(_message.Message):
     __slots__ = ("email", "comment", "rules")
@@ -205,6 +213,32 @@ class AtprotoEmailEffect(_message.Message):
     rules: _containers.RepeatedScalarFieldContainer[str]
     def __init__(self, email: _Optional[_Union[AtprotoEmail, str]] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
 
+class AtprotoMuteEffect(_message.Message):
+    __slots__ = ("effect_kind", "subject_kind", "comment", "duration_in_hours", "rules")
+    EFFECT_KIND_FIELD_NUMBER: _ClassVar[int]
+    SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
+    COMMENT_FIELD_NUMBER: _ClassVar[int]
+    DURATION_IN_HOURS_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    effect_kind: AtprotoEffectKind
+    subject_kind: AtprotoSubjectKind
+    comment: str
+    duration_in_hours: int
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., comment: _Optional[str] = ..., duration_in_hours: _Optional[int] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class AtprotoDivertEffect(_message.Message):
+    __slots__ = ("subject_kind", "blob_cids", "comment", "rules")
+    SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
+    BLOB_CIDS_FIELD_NUMBER: _ClassVar[int]
+    COMMENT_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    subject_kind: AtprotoSubjectKind
+    blob_cids: _containers.RepeatedScalarFieldContainer[str]
+    comment: str
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., blob_cids: _Optional[_Iterable[str]] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
+
 class AtprotoCommentEffect(_message.Message):
     __slots__ = ("subject_kind", "comment", "rules")
     SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
@@ -216,6 +250,18 @@ class AtprotoCommentEffect(_message.Message):
     def __init__(self, subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
 
 class AtprotoEscalateEffect(_message.Message):
+    __slots__ = ("subject_kind", "comment", "rules", "queue")
+    SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
+    COMMENT_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    QUEUE_FIELD_NUMBER: _ClassVar[int]
+    subject_kind: AtprotoSubjectKind
+    comment: str
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    queue: str
+    def __init__(self, subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ..., queue: _Optional[str] = ...) -> None: ...
+
+class AtprotoResolveAppealEffect(_message.Message):
     __slots__ = ("subject_kind", "comment", "rules")
     SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
     COMMENT_FIELD_NUMBER: _ClassVar[int]
@@ -225,6 +271,18 @@ class AtprotoEscalateEffect(_message.Message):
     rules: _containers.RepeatedScalarFieldContainer[str]
     def __init__(self, subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
 
+class AtprotoSetEffect(_message.Message):
+    __slots__ = ("effect_kind", "set", "comment", "rules")
+    EFFECT_KIND_FIELD_NUMBER: _ClassVar[int]
+    SET_FIELD_NUMBER: _ClassVar[int]
+    COMMENT_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    effect_kind: AtprotoEffectKind
+    set: str
+    comment: str
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, effect_kind: _Optional[_Union[AtprotoEffectKind, str]] = ..., set: _Optional[str] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
+
 class AtprotoAcknowledgeEffect(_message.Message):
     __slots__ = ("subject_kind", "comment", "rules")
     SUBJECT_KIND_FIELD_NUMBER: _ClassVar[int]
@@ -262,7 +320,7 @@ class BigQueryFlagEffect(_message.Message):
     def __init__(self, subject_kind: _Optional[_Union[AtprotoSubjectKind, str]] = ..., tag: _Optional[str] = ..., comment: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
 
 class ResultEvent(_message.Message):
-    __slots__ = ("send_time", "action_name", "action_id", "did", "uri", "cid", "data", "labels", "tags", "takedowns", "emails", "comments", "escalations", "acknowledgements", "reports", "bigqueryFlags")
+    __slots__ = ("send_time", "action_name", "action_id", "did", "uri", "cid", "data", "labels", "tags", "takedowns", "emails", "comments", "escalations", "acknowledgements", "reports", "bigqueryFlags", "mutes", "diverts", "resolve_appeals", "sets", "delayed", "cancel_delayed", "message", "evidence")
     SEND_TIME_FIELD_NUMBER: _ClassVar[int]
     ACTION_NAME_FIELD_NUMBER: _ClassVar[int]
     ACTION_ID_FIELD_NUMBER: _ClassVar[int]
@@ -279,6 +337,14 @@ class ResultEvent(_message.Message):
     ACKNOWLEDGEMENTS_FIELD_NUMBER: _ClassVar[int]
     REPORTS_FIELD_NUMBER: _ClassVar[int]
     BIGQUERYFLAGS_FIELD_NUMBER: _ClassVar[int]
+    MUTES_FIELD_NUMBER: _ClassVar[int]
+    DIVERTS_FIELD_NUMBER: _ClassVar[int]
+    RESOLVE_APPEALS_FIELD_NUMBER: _ClassVar[int]
+    SETS_FIELD_NUMBER: _ClassVar[int]
+    DELAYED_FIELD_NUMBER: _ClassVar[int]
+    CANCEL_DELAYED_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    EVIDENCE_FIELD_NUMBER: _ClassVar[int]
     send_time: _timestamp_pb2.Timestamp
     action_name: str
     action_id: int
@@ -295,7 +361,85 @@ class ResultEvent(_message.Message):
     acknowledgements: _containers.RepeatedCompositeFieldContainer[AtprotoAcknowledgeEffect]
     reports: _containers.RepeatedCompositeFieldContainer[AtprotoReportEffect]
     bigqueryFlags: _containers.RepeatedCompositeFieldContainer[BigQueryFlagEffect]
-    def __init__(self, send_time: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., action_name: _Optional[str] = ..., action_id: _Optional[int] = ..., did: _Optional[str] = ..., uri: _Optional[str] = ..., cid: _Optional[str] = ..., data: _Optional[bytes] = ..., labels: _Optional[_Iterable[_Union[AtprotoLabelEffect, _Mapping]]] = ..., tags: _Optional[_Iterable[_Union[AtprotoTagEffect, _Mapping]]] = ..., takedowns: _Optional[_Iterable[_Union[AtprotoTakedownEffect, _Mapping]]] = ..., emails: _Optional[_Iterable[_Union[AtprotoEmailEffect, _Mapping]]] = ..., comments: _Optional[_Iterable[_Union[AtprotoCommentEffect, _Mapping]]] = ..., escalations: _Optional[_Iterable[_Union[AtprotoEscalateEffect, _Mapping]]] = ..., acknowledgements: _Optional[_Iterable[_Union[AtprotoAcknowledgeEffect, _Mapping]]] = ..., reports: _Optional[_Iterable[_Union[AtprotoReportEffect, _Mapping]]] = ..., bigqueryFlags: _Optional[_Iterable[_Union[BigQueryFlagEffect, _Mapping]]] = ...) -> None: ...
+    mutes: _containers.RepeatedCompositeFieldContainer[AtprotoMuteEffect]
+    diverts: _containers.RepeatedCompositeFieldContainer[AtprotoDivertEffect]
+    resolve_appeals: _containers.RepeatedCompositeFieldContainer[AtprotoResolveAppealEffect]
+    sets: _containers.RepeatedCompositeFieldContainer[AtprotoSetEffect]
+    delayed: _containers.RepeatedCompositeFieldContainer[DelayedEffects]
+    cancel_delayed: _containers.RepeatedScalarFieldContainer[str]
+    message: ChatMessageRef
+    evidence: _containers.RepeatedCompositeFieldContainer[Evidence]
+    def __init__(self, send_time: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., action_name: _Optional[str] = ..., action_id: _Optional[int] = ..., did: _Optional[str] = ..., uri: _Optional[str] = ..., cid: _Optional[str] = ..., data: _Optional[bytes] = ..., labels: _Optional[_Iterable[_Union[AtprotoLabelEffect, _Mapping]]] = ..., tags: _Optional[_Iterable[_Union[AtprotoTagEffect, _Mapping]]] = ..., takedowns: _Optional[_Iterable[_Union[AtprotoTakedownEffect, _Mapping]]] = ..., emails: _Optional[_Iterable[_Union[AtprotoEmailEffect, _Mapping]]] = ..., comments: _Optional[_Iterable[_Union[AtprotoCommentEffect, _Mapping]]] = ..., escalations: _Optional[_Iterable[_Union[AtprotoEscalateEffect, _Mapping]]] = ..., acknowledgements: _Optional[_Iterable[_Union[AtprotoAcknowledgeEffect, _Mapping]]] = ..., reports: _Optional[_Iterable[_Union[AtprotoReportEffect, _Mapping]]] = ..., bigqueryFlags: _Optional[_Iterable[_Union[BigQueryFlagEffect, _Mapping]]] = ..., mutes: _Optional[_Iterable[_Union[AtprotoMuteEffect, _Mapping]]] = ..., diverts: _Optional[_Iterable[_Union[AtprotoDivertEffect, _Mapping]]] = ..., resolve_appeals: _Optional[_Iterable[_Union[AtprotoResolveAppealEffect, _Mapping]]] = ..., sets: _Optional[_Iterable[_Union[AtprotoSetEffect, _Mapping]]] = ..., delayed: _Optional[_Iterable[_Union[DelayedEffects, _Mapping]]] = ..., cancel_delayed: _Optional[_Iterable[str]] = ..., message: _Optional[_Union[ChatMessageRef, _Mapping]] = ..., evidence: _Optional[_Iterable[_Union[Evidence, _Mapping]]] = ...) -> None: ...
+
+class Evidence(_message.Message):
+    __slots__ = ("kind", "name", "value", "score", "rules")
+    KIND_FIELD_NUMBER: _ClassVar[int]
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    VALUE_FIELD_NUMBER: _ClassVar[int]
+    SCORE_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    kind: str
+    name: str
+    value: str
+    score: float
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, kind: _Optional[str] = ..., name: _Optional[str] = ..., value: _Optional[str] = ..., score: _Optional[float] = ..., rules: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class ChatMessageRef(_message.Message):
+    __slots__ = ("did", "convo_id", "message_id")
+    DID_FIELD_NUMBER: _ClassVar[int]
+    CONVO_ID_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_ID_FIELD_NUMBER: _ClassVar[int]
+    did: str
+    convo_id: str
+    message_id: str
+    def __init__(self, did: _Optional[str] = ..., convo_id: _Optional[str] = ..., message_id: _Optional[str] = ...) -> None: ...
+
+class DelayedEffects(_message.Message):
+    __slots__ = ("event", "execute_after_seconds", "key")
+    EVENT_FIELD_NUMBER: _ClassVar[int]
+    EXECUTE_AFTER_SECONDS_FIELD_NUMBER: _ClassVar[int]
+    KEY_FIELD_NUMBER: _ClassVar[int]
+    event: ResultEvent
+    execute_after_seconds: int
+    key: str
+    def __init__(self, event: _Optional[_Union[ResultEvent, _Mapping]] = ..., execute_after_seconds: _Optional[int] = ..., key: _Optional[str] = ...) -> None: ...
+
+class FailedEffects(_message.Message):
+    __slots__ = ("event", "attempts", "next_attempt_at", "last_error")
+    EVENT_FIELD_NUMBER: _ClassVar[int]
+    ATTEMPTS_FIELD_NUMBER: _ClassVar[int]
+    NEXT_ATTEMPT_AT_FIELD_NUMBER: _ClassVar[int]
+    LAST_ERROR_FIELD_NUMBER: _ClassVar[int]
+    event: ResultEvent
+    attempts: int
+    next_attempt_at: _timestamp_pb2.Timestamp
+    last_error: str
+    def __init__(self, event: _Optional[_Union[ResultEvent, _Mapping]] = ..., attempts: _Optional[int] = ..., next_attempt_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., last_error: _Optional[str] = ...) -> None: ...
+
+class EffectOutcome(_message.Message):
+    __slots__ = ("timestamp", "action_name", "action_id", "kind", "subject", "rules", "success", "error", "ozone_event_id", "dry_run")
+    TIMESTAMP_FIELD_NUMBER: _ClassVar[int]
+    ACTION_NAME_FIELD_NUMBER: _ClassVar[int]
+    ACTION_ID_FIELD_NUMBER: _ClassVar[int]
+    KIND_FIELD_NUMBER: _ClassVar[int]
+    SUBJECT_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    SUCCESS_FIELD_NUMBER: _ClassVar[int]
+    ERROR_FIELD_NUMBER: _ClassVar[int]
+    OZONE_EVENT_ID_FIELD_NUMBER: _ClassVar[int]
+    DRY_RUN_FIELD_NUMBER: _ClassVar[int]
+    timestamp: _timestamp_pb2.Timestamp
+    action_name: str
+    action_id: int
+    kind: str
+    subject: str
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    success: bool
+    error: str
+    ozone_event_id: int
+    dry_run: bool
+    def __init__(self, timestamp: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., action_name: _Optional[str] = ..., action_id: _Optional[int] = ..., kind: _Optional[str] = ..., subject: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ..., success: bool = ..., error: _Optional[str] = ..., ozone_event_id: _Optional[int] = ..., dry_run: bool = ...) -> None: ...
 
 class FirehoseEvent(_message.Message):
     __slots__ = ("did", "timestamp", "kind", "commit", "account", "identity")
@@ -338,7 +482,7 @@ class Cursor(_message.Message):
     def __init__(self, sequence: _Optional[int] = ..., saved_on_exit: bool = ...) -> None: ...
 
 class ModerationEnrichedFirehoseRecordEvent(_message.Message):
-    __slots__ = ("did", "timestamp", "collection", "rkey", "operation", "record", "image_results", "ozone_repo_view_detail", "did_doc", "profile_view", "did_audit_log", "cid")
+    __slots__ = ("did", "timestamp", "collection", "rkey", "operation", "record", "image_results", "ozone_repo_view_detail", "did_doc", "profile_view", "did_audit_log", "cid", "velocity", "term_list_matches", "impersonation_matches", "identity", "pds", "collection_context", "watchlists", "existing_labels", "blob_type_mismatches", "pipeline")
     class ImageResultsEntry(_message.Message):
         __slots__ = ("key", "value")
         KEY_FIELD_NUMBER: _ClassVar[int]
@@ -358,6 +502,16 @@ class ModerationEnrichedFirehoseRecordEvent(_message.Message):
     PROFILE_VIEW_FIELD_NUMBER: _ClassVar[int]
     DID_AUDIT_LOG_FIELD_NUMBER: _ClassVar[int]
     CID_FIELD_NUMBER: _ClassVar[int]
+    VELOCITY_FIELD_NUMBER: _ClassVar[int]
+    TERM_LIST_MATCHES_FIELD_NUMBER: _ClassVar[int]
+    IMPERSONATION_MATCHES_FIELD_NUMBER: _ClassVar[int]
+    IDENTITY_FIELD_NUMBER: _ClassVar[int]
+    PDS_FIELD_NUMBER: _ClassVar[int]
+    COLLECTION_CONTEXT_FIELD_NUMBER: _ClassVar[int]
+    WATCHLISTS_FIELD_NUMBER: _ClassVar[int]
+    EXISTING_LABELS_FIELD_NUMBER: _ClassVar[int]
+    BLOB_TYPE_MISMATCHES_FIELD_NUMBER: _ClassVar[int]
+    PIPELINE_FIELD_NUMBER: _ClassVar[int]
     did: str
     timestamp: _timestamp_pb2.Timestamp
     collection: str
@@ -370,10 +524,134 @@ class ModerationEnrichedFirehoseRecordEvent(_message.Message):
     profile_view: bytes
     did_audit_log: bytes
     cid: str
-    def __init__(self, did: _Optional[str] = ..., timestamp: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., collection: _Optional[str] = ..., rkey: _Optional[str] = ..., operation: _Optional[_Union[CommitOperation, str]] = ..., record: _Optional[bytes] = ..., image_results: _Optional[_Mapping[str, ImageDispatchResults]] = ..., ozone_repo_view_detail: _Optional[bytes] = ..., did_doc: _Optional[bytes] = ..., profile_view: _Optional[bytes] = ..., did_audit_log: _Optional[bytes] = ..., cid: _Optional[str] = ...) -> None: ...
+    velocity: VelocityFeatures
+    term_list_matches: _containers.RepeatedCompositeFieldContainer[TermListMatch]
+    impersonation_matches: _containers.RepeatedCompositeFieldContainer[ImpersonationMatch]
+    identity: IdentityFeatures
+    pds: PdsFeatures
+    collection_context: CollectionContext
+    watchlists: _containers.RepeatedScalarFieldContainer[str]
+    existing_labels: _containers.RepeatedCompositeFieldContainer[ExistingLabel]
+    blob_type_mismatches: _containers.RepeatedCompositeFieldContainer[BlobTypeMismatch]
+    pipeline: PipelineTimes
+    def __init__(self, did: _Optional[str] = ..., timestamp: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., collection: _Optional[str] = ..., rkey: _Optional[str] = ..., operation: _Optional[_Union[CommitOperation, str]] = ..., record: _Optional[bytes] = ..., image_results: _Optional[_Mapping[str, ImageDispatchResults]] = ..., ozone_repo_view_detail: _Optional[bytes] = ..., did_doc: _Optional[bytes] = ..., profile_view: _Optional[bytes] = ..., did_audit_log: _Optional[bytes] = ..., cid: _Optional[str] = ..., velocity: _Optional[_Union[VelocityFeatures, _Mapping]] = ..., term_list_matches: _Optional[_Iterable[_Union[TermListMatch, _Mapping]]] = ..., impersonation_matches: _Optional[_Iterable[_Union[ImpersonationMatch, _Mapping]]] = ..., identity: _Optional[_Union[IdentityFeatures, _Mapping]] = ..., pds: _Optional[_Union[PdsFeatures, _Mapping]] = ..., collection_context: _Optional[_Union[CollectionContext, _Mapping]] = ..., watchlists: _Optional[_Iterable[str]] = ..., existing_labels: _Optional[_Iterable[_Union[ExistingLabel, _Mapping]]] = ..., blob_type_mismatches: _Optional[_Iterable[_Union[BlobTypeMismatch, _Mapping]]] = ..., pipeline: _Optional[_Union[PipelineTimes, _Mapping]] = ...) -> None: ...
+
+class VelocityFeatures(_message.Message):
+    __slots__ = ("posts_last_minute", "posts_last_hour", "images_last_hour", "distinct_mentions_last_hour", "identical_text_posts_last_hour")
+    POSTS_LAST_MINUTE_FIELD_NUMBER: _ClassVar[int]
+    POSTS_LAST_HOUR_FIELD_NUMBER: _ClassVar[int]
+    IMAGES_LAST_HOUR_FIELD_NUMBER: _ClassVar[int]
+    DISTINCT_MENTIONS_LAST_HOUR_FIELD_NUMBER: _ClassVar[int]
+    IDENTICAL_TEXT_POSTS_LAST_HOUR_FIELD_NUMBER: _ClassVar[int]
+    posts_last_minute: int
+    posts_last_hour: int
+    images_last_hour: int
+    distinct_mentions_last_hour: int
+    identical_text_posts_last_hour: int
+    def __init__(self, posts_last_minute: _Optional[int] = ..., posts_last_hour: _Optional[int] = ..., images_last_hour: _Optional[int] = ..., distinct_mentions_last_hour: _Optional[int] = ..., identical_text_posts_last_hour: _Optional[int] = ...) -> None: ...
+
+class TermListMatch(_message.Message):
+    __slots__ = ("list", "language", "severity", "fields")
+    LIST_FIELD_NUMBER: _ClassVar[int]
+    LANGUAGE_FIELD_NUMBER: _ClassVar[int]
+    SEVERITY_FIELD_NUMBER: _ClassVar[int]
+    FIELDS_FIELD_NUMBER: _ClassVar[int]
+    list: str
+    language: str
+    severity: str
+    fields: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, list: _Optional[str] = ..., language: _Optional[str] = ..., severity: _Optional[str] = ..., fields: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class ImpersonationMatch(_message.Message):
+    __slots__ = ("protected_did", "protected_handle", "field", "score")
+    PROTECTED_DID_FIELD_NUMBER: _ClassVar[int]
+    PROTECTED_HANDLE_FIELD_NUMBER: _ClassVar[int]
+    FIELD_FIELD_NUMBER: _ClassVar[int]
+    SCORE_FIELD_NUMBER: _ClassVar[int]
+    protected_did: str
+    protected_handle: str
+    field: str
+    score: float
+    def __init__(self, protected_did: _Optional[str] = ..., protected_handle: _Optional[str] = ..., field: _Optional[str] = ..., score: _Optional[float] = ...) -> None: ...
+
+class IdentityFeatures(_message.Message):
+    __slots__ = ("account_created_at", "account_age_seconds", "operation_count", "recent_handle_changes", "pds_migrations", "rotation_key_changes")
+    ACCOUNT_CREATED_AT_FIELD_NUMBER: _ClassVar[int]
+    ACCOUNT_AGE_SECONDS_FIELD_NUMBER: _ClassVar[int]
+    OPERATION_COUNT_FIELD_NUMBER: _ClassVar[int]
+    RECENT_HANDLE_CHANGES_FIELD_NUMBER: _ClassVar[int]
+    PDS_MIGRATIONS_FIELD_NUMBER: _ClassVar[int]
+    ROTATION_KEY_CHANGES_FIELD_NUMBER: _ClassVar[int]
+    account_created_at: _timestamp_pb2.Timestamp
+    account_age_seconds: int
+    operation_count: int
+    recent_handle_changes: int
+    pds_migrations: int
+    rotation_key_changes: int
+    def __init__(self, account_created_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., account_age_seconds: _Optional[int] = ..., operation_count: _Optional[int] = ..., recent_handle_changes: _Optional[int] = ..., pds_migrations: _Optional[int] = ..., rotation_key_changes: _Optional[int] = ...) -> None: ...
+
+class PdsFeatures(_message.Message):
+    __slots__ = ("host", "bluesky_hosted", "host_first_seen", "host_age_seconds", "suspicious_host")
+    HOST_FIELD_NUMBER: _ClassVar[int]
+    BLUESKY_HOSTED_FIELD_NUMBER: _ClassVar[int]
+    HOST_FIRST_SEEN_FIELD_NUMBER: _ClassVar[int]
+    HOST_AGE_SECONDS_FIELD_NUMBER: _ClassVar[int]
+    SUSPICIOUS_HOST_FIELD_NUMBER: _ClassVar[int]
+    host: str
+    bluesky_hosted: bool
+    host_first_seen: _timestamp_pb2.Timestamp
+    host_age_seconds: int
+    suspicious_host: bool
+    def __init__(self, host: _Optional[str] = ..., bluesky_hosted: bool = ..., host_first_seen: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., host_age_seconds: _Optional[int] = ..., suspicious_host: bool = ...) -> None: ...
+
+class CollectionContext(_message.Message):
+    __slots__ = ("service_endpoint", "service_did", "list_uri", "list_item_count", "avatar_cids")
+    SERVICE_ENDPOINT_FIELD_NUMBER: _ClassVar[int]
+    SERVICE_DID_FIELD_NUMBER: _ClassVar[int]
+    LIST_URI_FIELD_NUMBER: _ClassVar[int]
+    LIST_ITEM_COUNT_FIELD_NUMBER: _ClassVar[int]
+    AVATAR_CIDS_FIELD_NUMBER: _ClassVar[int]
+    service_endpoint: str
+    service_did: str
+    list_uri: str
+    list_item_count: int
+    avatar_cids: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, service_endpoint: _Optional[str] = ..., service_did: _Optional[str] = ..., list_uri: _Optional[str] = ..., list_item_count: _Optional[int] = ..., avatar_cids: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class ExistingLabel(_message.Message):
+    __slots__ = ("uri", "val", "created_at")
+    URI_FIELD_NUMBER: _ClassVar[int]
+    VAL_FIELD_NUMBER: _ClassVar[int]
+    CREATED_AT_FIELD_NUMBER: _ClassVar[int]
+    uri: str
+    val: str
+    created_at: _timestamp_pb2.Timestamp
+    def __init__(self, uri: _Optional[str] = ..., val: _Optional[str] = ..., created_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ...) -> None: ...
+
+class PipelineTimes(_message.Message):
+    __slots__ = ("event_timestamp", "enrichment_started_at", "enrichment_finished_at", "staleness_ms")
+    EVENT_TIMESTAMP_FIELD_NUMBER: _ClassVar[int]
+    ENRICHMENT_STARTED_AT_FIELD_NUMBER: _ClassVar[int]
+    ENRICHMENT_FINISHED_AT_FIELD_NUMBER: _ClassVar[int]
+    STALENESS_MS_FIELD_NUMBER: _ClassVar[int]
+    event_timestamp: _timestamp_pb2.Timestamp
+    enrichment_started_at: _timestamp_pb2.Timestamp
+    enrichment_finished_at: _timestamp_pb2.Timestamp
+    staleness_ms: int
+    def __init__(self, event_timestamp: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., enrichment_started_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., enrichment_finished_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., staleness_ms: _Optional[int] = ...) -> None: ...
+
+class BlobTypeMismatch(_message.Message):
+    __slots__ = ("cid", "declared_mime_type", "sniffed_mime_type")
+    CID_FIELD_NUMBER: _ClassVar[int]
+    DECLARED_MIME_TYPE_FIELD_NUMBER: _ClassVar[int]
+    SNIFFED_MIME_TYPE_FIELD_NUMBER: _ClassVar[int]
+    cid: str
+    declared_mime_type: str
+    sniffed_mime_type: str
+    def __init__(self, cid: _Optional[str] = ..., declared_mime_type: _Optional[str] = ..., sniffed_mime_type: _Optional[str] = ...) -> None: ...
 
 class ImageDispatchResults(_message.Message):
-    __slots__ = ("cid", "abyss", "hive", "retina", "prescreen", "retina_hash", "ncii", "flagged")
+    __slots__ = ("cid", "abyss", "hive", "retina", "prescreen", "retina_hash", "ncii", "flagged", "animation", "sightings", "link_card")
     class AbyssResults(_message.Message):
         __slots__ = ("raw", "error", "is_abuse_match")
         RAW_FIELD_NUMBER: _ClassVar[int]
@@ -384,7 +662,7 @@ class ImageDispatchResults(_message.Message):
         is_abuse_match: bool
         def __init__(self, raw: _Optional[bytes] = ..., error: _Optional[str] = ..., is_abuse_match: bool = ...) -> None: ...
     class HiveResults(_message.Message):
-        __slots__ = ("raw", "error", "classes")
+        __slots__ = ("raw", "error", "classes", "budget_skipped")
         class ClassesEntry(_message.Message):
             __slots__ = ("key", "value")
             KEY_FIELD_NUMBER: _ClassVar[int]
@@ -395,10 +673,12 @@ class ImageDispatchResults(_message.Message):
         RAW_FIELD_NUMBER: _ClassVar[int]
         ERROR_FIELD_NUMBER: _ClassVar[int]
         CLASSES_FIELD_NUMBER: _ClassVar[int]
+        BUDGET_SKIPPED_FIELD_NUMBER: _ClassVar[int]
         raw: bytes
         error: str
         classes: _containers.ScalarMap[str, float]
-        def __init__(self, raw: _Optional[bytes] = ..., error: _Optional[str] = ..., classes: _Optional[_Mapping[str, float]] = ...) -> None: ...
+        budget_skipped: bool
+        def __init__(self, raw: _Optional[bytes] = ..., error: _Optional[str] = ..., classes: _Optional[_Mapping[str, float]] = ..., budget_skipped: bool = ...) -> None: ...
     class RetinaResults(_message.Message):
         __slots__ = ("raw", "error", "text")
         RAW_FIELD_NUMBER: _ClassVar[int]
@@ -420,14 +700,16 @@ class ImageDispatchResults(_message.Message):
         quality_too_low: bool
         def __init__(self, raw: _Optional[bytes] = ..., error: _Optional[str] = ..., hash: _Optional[str] = ..., quality_too_low: bool = ...) -> None: ...
     class PrescreenResults(_message.Message):
-        __slots__ = ("raw", "error", "decision")
+        __slots__ = ("raw", "error", "decision", "qa_sampled")
         RAW_FIELD_NUMBER: _ClassVar[int]
         ERROR_FIELD_NUMBER: _ClassVar[int]
         DECISION_FIELD_NUMBER: _ClassVar[int]
+        QA_SAMPLED_FIELD_NUMBER: _ClassVar[int]
         raw: bytes
         error: str
         decision: str
-        def __init__(self, raw: _Optional[bytes] = ..., error: _Optional[str] = ..., decision: _Optional[str] = ...) -> None: ...
+        qa_sampled: bool
+        def __init__(self, raw: _Optional[bytes] = ..., error: _Optional[str] = ..., decision: _Optional[str] = ..., qa_sampled: bool = ...) -> None: ...
     class NciiResults(_message.Message):
         __slots__ = ("raw", "error", "is_match", "score")
         RAW_FIELD_NUMBER: _ClassVar[int]
@@ -458,6 +740,33 @@ class ImageDispatchResults(_message.Message):
         description: str
         score: float
         def __init__(self, error: _Optional[str] = ..., is_match: bool = ..., action: _Optional[str] = ..., action_level: _Optional[str] = ..., action_value: _Optional[str] = ..., always_report: bool = ..., description: _Optional[str] = ..., score: _Optional[float] = ...) -> None: ...
+    class AnimationResults(_message.Message):
+        __slots__ = ("frame_count", "sampled_frames", "worst_frame")
+        FRAME_COUNT_FIELD_NUMBER: _ClassVar[int]
+        SAMPLED_FRAMES_FIELD_NUMBER: _ClassVar[int]
+        WORST_FRAME_FIELD_NUMBER: _ClassVar[int]
+        frame_count: int
+        sampled_frames: int
+        worst_frame: int
+        def __init__(self, frame_count: _Optional[int] = ..., sampled_frames: _Optional[int] = ..., worst_frame: _Optional[int] = ...) -> None: ...
+    class Sightings(_message.Message):
+        __slots__ = ("count", "distinct_dids", "first_seen", "window_seconds")
+        COUNT_FIELD_NUMBER: _ClassVar[int]
+        DISTINCT_DIDS_FIELD_NUMBER: _ClassVar[int]
+        FIRST_SEEN_FIELD_NUMBER: _ClassVar[int]
+        WINDOW_SECONDS_FIELD_NUMBER: _ClassVar[int]
+        count: int
+        distinct_dids: int
+        first_seen: _timestamp_pb2.Timestamp
+        window_seconds: int
+        def __init__(self, count: _Optional[int] = ..., distinct_dids: _Optional[int] = ..., first_seen: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., window_seconds: _Optional[int] = ...) -> None: ...
+    class LinkCard(_message.Message):
+        __slots__ = ("uri", "title")
+        URI_FIELD_NUMBER: _ClassVar[int]
+        TITLE_FIELD_NUMBER: _ClassVar[int]
+        uri: str
+        title: str
+        def __init__(self, uri: _Optional[str] = ..., title: _Optional[str] = ...) -> None: ...
     CID_FIELD_NUMBER: _ClassVar[int]
     ABYSS_FIELD_NUMBER: _ClassVar[int]
     HIVE_FIELD_NUMBER: _ClassVar[int]
@@ -466,6 +775,9 @@ class ImageDispatchResults(_message.Message):
     RETINA_HASH_FIELD_NUMBER: _ClassVar[int]
     NCII_FIELD_NUMBER: _ClassVar[int]
     FLAGGED_FIELD_NUMBER: _ClassVar[int]
+    ANIMATION_FIELD_NUMBER: _ClassVar[int]
+    SIGHTINGS_FIELD_NUMBER: _ClassVar[int]
+    LINK_CARD_FIELD_NUMBER: _ClassVar[int]
     cid: str
     abyss: ImageDispatchResults.AbyssResults
     hive: ImageDispatchResults.HiveResults
@@ -474,4 +786,107 @@ class ImageDispatchResults(_message.Message):
     retina_hash: ImageDispatchResults.RetinaHashResults
     ncii: ImageDispatchResults.NciiResults
     flagged: ImageDispatchResults.FlaggedResults
-    def __init__(self, cid: _Optional[str] = ..., abyss: _Optional[_Union[ImageDispatchResults.AbyssResults, _Mapping]] = ..., hive: _Optional[_Union[ImageDispatchResults.HiveResults, _Mapping]] = ..., retina: _Optional[_Union[ImageDispatchResults.RetinaResults, _Mapping]] = ..., prescreen: _Optional[_Union[ImageDispatchResults.PrescreenResults, _Mapping]] = ..., retina_hash: _Optional[_Union[ImageDispatchResults.RetinaHashResults, _Mapping]] = ..., ncii: _Optional[_Union[ImageDispatchResults.NciiResults, _Mapping]] = ..., flagged: _Optional[_Union[ImageDispatchResults.FlaggedResults, _Mapping]] = ...) -> None: ...
+    animation: ImageDispatchResults.AnimationResults
+    sightings: ImageDispatchResults.Sightings
+    link_card: ImageDispatchResults.LinkCard
+    def __init__(self, cid: _Optional[str] = ..., abyss: _Optional[_Union[ImageDispatchResults.AbyssResults, _Mapping]] = ..., hive: _Optional[_Union[ImageDispatchResults.HiveResults, _Mapping]] = ..., retina: _Optional[_Union[ImageDispatchResults.RetinaResults, _Mapping]] = ..., prescreen: _Optional[_Union[ImageDispatchResults.PrescreenResults, _Mapping]] = ..., retina_hash: _Optional[_Union[ImageDispatchResults.RetinaHashResults, _Mapping]] = ..., ncii: _Optional[_Union[ImageDispatchResults.NciiResults, _Mapping]] = ..., flagged: _Optional[_Union[ImageDispatchResults.FlaggedResults, _Mapping]] = ..., animation: _Optional[_Union[ImageDispatchResults.AnimationResults, _Mapping]] = ..., sightings: _Optional[_Union[ImageDispatchResults.Sightings, _Mapping]] = ..., link_card: _Optional[_Union[ImageDispatchResults.LinkCard, _Mapping]] = ...) -> None: ...
+
+class ModerationReportEvent(_message.Message):
+    __slots__ = ("report_id", "source", "reason_type", "reason", "subject_did", "subject_uri", "subject_cid", "reported_by", "created_at")
+    REPORT_ID_FIELD_NUMBER: _ClassVar[int]
+    SOURCE_FIELD_NUMBER: _ClassVar[int]
+    REASON_TYPE_FIELD_NUMBER: _ClassVar[int]
+    REASON_FIELD_NUMBER: _ClassVar[int]
+    SUBJECT_DID_FIELD_NUMBER: _ClassVar[int]
+    SUBJECT_URI_FIELD_NUMBER: _ClassVar[int]
+    SUBJECT_CID_FIELD_NUMBER: _ClassVar[int]
+    REPORTED_BY_FIELD_NUMBER: _ClassVar[int]
+    CREATED_AT_FIELD_NUMBER: _ClassVar[int]
+    report_id: int
+    source: str
+    reason_type: str
+    reason: str
+    subject_did: str
+    subject_uri: str
+    subject_cid: str
+    reported_by: str
+    created_at: _timestamp_pb2.Timestamp
+    def __init__(self, report_id: _Optional[int] = ..., source: _Optional[str] = ..., reason_type: _Optional[str] = ..., reason: _Optional[str] = ..., subject_did: _Optional[str] = ..., subject_uri: _Optional[str] = ..., subject_cid: _Optional[str] = ..., reported_by: _Optional[str] = ..., created_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ...) -> None: ...
+
+class LabelExpiredEvent(_message.Message):
+    __slots__ = ("subject_did", "subject_uri", "label", "rules", "action_name", "action_id", "duration_in_hours", "applied_at", "expired_at")
+    SUBJECT_DID_FIELD_NUMBER: _ClassVar[int]
+    SUBJECT_URI_FIELD_NUMBER: _ClassVar[int]
+    LABEL_FIELD_NUMBER: _ClassVar[int]
+    RULES_FIELD_NUMBER: _ClassVar[int]
+    ACTION_NAME_FIELD_NUMBER: _ClassVar[int]
+    ACTION_ID_FIELD_NUMBER: _ClassVar[int]
+    DURATION_IN_HOURS_FIELD_NUMBER: _ClassVar[int]
+    APPLIED_AT_FIELD_NUMBER: _ClassVar[int]
+    EXPIRED_AT_FIELD_NUMBER: _ClassVar[int]
+    subject_did: str
+    subject_uri: str
+    label: str
+    rules: _containers.RepeatedScalarFieldContainer[str]
+    action_name: str
+    action_id: int
+    duration_in_hours: int
+    applied_at: _timestamp_pb2.Timestamp
+    expired_at: _timestamp_pb2.Timestamp
+    def __init__(self, subject_did: _Optional[str] = ..., subject_uri: _Optional[str] = ..., label: _Optional[str] = ..., rules: _Optional[_Iterable[str]] = ..., action_name: _Optional[str] = ..., action_id: _Optional[int] = ..., duration_in_hours: _Optional[int] = ..., applied_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ..., expired_at: _Optional[_Union[_timestamp_pb2.Timestamp, _Mapping]] = ...) -> None: ...
+
+class RetinaImageRequest(_message.Message):
+    __slots__ = ("did", "cid", "ocr", "cdn_host")
+    DID_FIELD_NUMBER: _ClassVar[int]
+    CID_FIELD_NUMBER: _ClassVar[int]
+    OCR_FIELD_NUMBER: _ClassVar[int]
+    CDN_HOST_FIELD_NUMBER: _ClassVar[int]
+    did: str
+    cid: str
+    ocr: RetinaOCROptions
+    cdn_host: str
+    def __init__(self, did: _Optional[str] = ..., cid: _Optional[str] = ..., ocr: _Optional[_Union[RetinaOCROptions, _Mapping]] = ..., cdn_host: _Optional[str] = ...) -> None: ...
+
+class RetinaImageChunk(_message.Message):
+    __slots__ = ("ocr", "data")
+    OCR_FIELD_NUMBER: _ClassVar[int]
+    DATA_FIELD_NUMBER: _ClassVar[int]
+    ocr: RetinaOCROptions
+    data: bytes
+    def __init__(self, ocr: _Optional[_Union[RetinaOCROptions, _Mapping]] = ..., data: _Optional[bytes] = ...) -> None: ...
+
+class RetinaOCROptions(_message.Message):
+    __slots__ = ("langs", "preprocess")
+    LANGS_FIELD_NUMBER: _ClassVar[int]
+    PREPROCESS_FIELD_NUMBER: _ClassVar[int]
+    langs: str
+    preprocess: str
+    def __init__(self, langs: _Optional[str] = ..., preprocess: _Optional[str] = ...) -> None: ...
+
+class RetinaAnalyzeResponse(_message.Message):
+    __slots__ = ("text",)
+    TEXT_FIELD_NUMBER: _ClassVar[int]
+    text: str
+    def __init__(self, text: _Optional[str] = ...) -> None: ...
+
+class RetinaHashResponse(_message.Message):
+    __slots__ = ("hash", "binary", "quality_too_low", "checksums")
+    HASH_FIELD_NUMBER: _ClassVar[int]
+    BINARY_FIELD_NUMBER: _ClassVar[int]
+    QUALITY_TOO_LOW_FIELD_NUMBER: _ClassVar[int]
+    CHECKSUMS_FIELD_NUMBER: _ClassVar[int]
+    hash: str
+    binary: str
+    quality_too_low: bool
+    checksums: RetinaChecksums
+    def __init__(self, hash: _Optional[str] = ..., binary: _Optional[str] = ..., quality_too_low: bool = ..., checksums: _Optional[_Union[RetinaChecksums, _Mapping]] = ...) -> None: ...
+
+class RetinaChecksums(_message.Message):
+    __slots__ = ("md5", "sha1", "sha256")
+    MD5_FIELD_NUMBER: _ClassVar[int]
+    SHA1_FIELD_NUMBER: _ClassVar[int]
+    SHA256_FIELD_NUMBER: _ClassVar[int]
+    md5: str
+    sha1: str
+    sha256: str
+    def __init__(self, md5: _Optional[str] = ..., sha1: _Optional[str] = ..., sha256: _Optional[str] = ...) -> None: ...
@@ -2,3 +2,171 @@
 """Client and server classes corresponding to protobuf-defined services."""
 import grpc
 
+import osprey_atproto_pb2 as osprey__atproto__pb2
+
+
+class RetinaStub(object):
+    """Retina's analyze and hash operations, for internal callers. The same operations are served over
+    HTTP for everyone else.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.Analyze = channel.unary_unary(
+                '/osprey.Retina/Analyze',
+                request_serializer=osprey__atproto__pb2.RetinaImageRequest.SerializeToString,
+                response_deserializer=osprey__atproto__pb2.RetinaAnalyzeResponse.FromString,
+                )
+        self.AnalyzeBlob = channel.stream_unary(
+                '/osprey.Retina/AnalyzeBlob',
+                request_serializer=osprey__atproto__pb2.RetinaImageChunk.SerializeToString,
+                response_deserializer=osprey__atproto__pb2.RetinaAnalyzeResponse.FromString,
+                )
+        self.Hash = channel.unary_unary(
+                '/osprey.Retina/Hash',
+                request_serializer=osprey__atproto__pb2.RetinaImageRequest.SerializeToString,
+                response_deserializer=osprey__atproto__pb2.RetinaHashResponse.FromString,
+                )
+        self.HashBlob = channel.stream_unary(
+                '/osprey.Retina/HashBlob',
+                request_serializer=osprey__atproto__pb2.RetinaImageChunk.SerializeToString,
+                response_deserializer=osprey__atproto__pb2.RetinaHashResponse.FromString,
+                )
+
+
+class RetinaServicer(object):
+    """Retina's analyze and hash operations, for internal callers. The same operations are served over
+    HTTP for everyone else.
+    """
+
+    def Analyze(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def AnalyzeBlob(self, request_iterator, context):
+        """The first chunk carries the options, and every chunk part of the image.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Hash(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def HashBlob(self, request_iterator, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_RetinaServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'Analyze': grpc.unary_unary_rpc_method_handler(
+                    servicer.Analyze,
+                    request_deserializer=osprey__atproto__pb2.RetinaImageRequest.FromString,
+                    response_serializer=osprey__atproto__pb2.RetinaAnalyzeResponse.SerializeToString,
+            ),
+            'AnalyzeBlob': grpc.stream_unary_rpc_method_handler(
+                    servicer.AnalyzeBlob,
+                    request_deserializer=osprey__atproto__pb2.RetinaImageChunk.FromString,
+                    response_serializer=osprey__atproto__pb2.RetinaAnalyzeResponse.SerializeToString,
+            ),
+            'Hash': grpc.unary_unary_rpc_method_handler(
+                    servicer.Hash,
+                    request_deserializer=osprey__atproto__pb2.RetinaImageRequest.FromString,
+                    response_serializer=osprey__atproto__pb2.RetinaHashResponse.SerializeToString,
+            ),
+            'HashBlob': grpc.stream_unary_rpc_method_handler(
+                    servicer.HashBlob,
+                    request_deserializer=osprey__atproto__pb2.RetinaImageChunk.FromString,
+                    response_serializer=osprey__atproto__pb2.RetinaHashResponse.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'osprey.Retina', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+
+
+ # This class is part of an EXPERIMENTAL API.
+class Retina(object):
+    """Retina's analyze and hash operations, for internal callers. The same operations are served over
+    HTTP for everyone else.
+    """
+
+    @staticmethod
+    def Analyze(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/osprey.Retina/Analyze',
+            osprey__atproto__pb2.RetinaImageRequest.SerializeToString,
+            osprey__atproto__pb2.RetinaAnalyzeResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def AnalyzeBlob(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_unary(request_iterator, target, '/osprey.Retina/AnalyzeBlob',
+            osprey__atproto__pb2.RetinaImageChunk.SerializeToString,
+            osprey__atproto__pb2.RetinaAnalyzeResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Hash(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/osprey.Retina/Hash',
+            osprey__atproto__pb2.RetinaImageRequest.SerializeToString,
+            osprey__atproto__pb2.RetinaHashResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def HashBlob(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_unary(request_iterator, target, '/osprey.Retina/HashBlob',
+            osprey__atproto__pb2.RetinaImageChunk.SerializeToString,
+            osprey__atproto__pb2.RetinaHashResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -1,10 +1,11 @@
-from typing import Any, Dict
+from typing import Any, Dict, Optional
 
 from osprey.engine.executor.execution_context import ExecutionContext
 from osprey.engine.stdlib.udfs.categories import UdfCategories
 from osprey.engine.udf.arguments import ArgumentsBase
 from osprey.engine.udf.base import UDFBase
 from osprey.worker.lib.osprey_shared.logging import get_logger
+from rpc.osprey_atproto_pb2 import ChatMessageRef
 from udfs.atproto.lib.audit_log import AuditLog
 from udfs.atproto.lib.did_doc import DIDDocument
 
@@ -25,6 +26,19 @@ def GetRecordCIDFromData(data: Dict[str, Any]):
     return f'{data["cid"]}'
 
 
+def GetMessageRefFromData(data: Dict[str, Any]) -> Optional[ChatMessageRef]:
+    """Returns the chat message the action is about, for actions about DMs, which carry its convoId and messageId."""
+    if not data.get('convoId') or not data.get('messageId'):
+        return None
+    return ChatMessageRef(did=data['did'], convo_id=data['convoId'], message_id=data['messageId'])
+
+
+def GetMessageEntityFromData(data: Dict[str, Any]):
+    """Chat messages have no AT-URI, so effects on them take this as their entity, which the effector also logs them
+    under."""
+    return f'chat://{data["did"]}/{data["convoId"]}/{data["messageId"]}'
+
+
 class GetRecordURI(UDFBase[ArgumentsBase, str]):
     category = UdfCategories.STRING
 
@@ -42,6 +56,14 @@ class GetRecordCID(UDFBase[ArgumentsBase, str]):
         return GetRecordCIDFromData(data)
 
 
+class GetMessageEntity(UDFBase[ArgumentsBase, str]):
+    category = UdfCategories.STRING
+
+    def execute(self, execution_context: ExecutionContext, arguments: ArgumentsBase) -> str:
+        data = execution_context.get_data()
+        return GetMessageEntityFromData(data)
+
+
 class GetDIDCreatedAt(UDFBase[ArgumentsBase, str]):
     category = UdfCategories.STRING
 
@@ -0,0 +1,110 @@
+from dataclasses import dataclass
+from typing import List, Optional, Self, cast
+
+from ddtrace.internal.logger import get_logger
+from osprey.engine.executor.custom_extracted_features import CustomExtractedFeature
+from osprey.engine.executor.execution_context import ExecutionContext
+from osprey.engine.language_types.effects import EffectToCustomExtractedFeatureBase
+from osprey.engine.stdlib.udfs.categories import UdfCategories
+from osprey.engine.udf.arguments import ArgumentsBase
+from osprey.engine.udf.base import UDFBase
+from osprey.engine.utils.types import add_slots
+
+logger = get_logger('atproto_delay')
+
+
+class AtprotoDelayArguments(ArgumentsBase):
+    entity: str
+    seconds: int
+    key: Optional[str] = None
+
+
+class AtprotoCancelDelayedArguments(ArgumentsBase):
+    entity: str
+    key: Optional[str] = None
+
+
+@dataclass
+class AtprotoDelayEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a delay effect of a WhenRules(...) invocation. The other effects of the rules it's given for are held
+    back by the effector for the given number of seconds, and only applied if nothing cancels them by key first, e.g.
+    taking a post down unless its author deletes it within ten minutes."""
+
+    entity: str
+    """The entity the delayed effects are on."""
+
+    seconds: int
+    """How long the effects are held back for."""
+
+    key: str
+    """What cancels the effects, which defaults to the entity. Delaying effects again with the same key replaces the
+    ones still waiting."""
+
+    def to_str(self) -> str:
+        return f'{self.key}|{self.seconds}'
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoDelayEffectsExtractedFeature(effects=cast(List[AtprotoDelayEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoDelayEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoDelayEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_delay'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+@dataclass
+class AtprotoCancelDelayedEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a cancellation of a WhenRules(...) invocation, which drops the delayed effects waiting under the key
+    before they're applied, e.g. when the record they were for was deleted."""
+
+    key: str
+    """The key of the delayed effects to cancel, which defaults to the entity."""
+
+    def to_str(self) -> str:
+        return self.key
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoCancelDelayedEffectsExtractedFeature(effects=cast(List[AtprotoCancelDelayedEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoCancelDelayedEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoCancelDelayedEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_cancel_delayed'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+class DelayAtprotoEffects(UDFBase[AtprotoDelayArguments, AtprotoDelayEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(self, execution_context: ExecutionContext, arguments: AtprotoDelayArguments) -> AtprotoDelayEffect:
+        return AtprotoDelayEffect(
+            entity=arguments.entity,
+            seconds=arguments.seconds,
+            key=arguments.key or arguments.entity,
+        )
+
+
+class CancelDelayedAtprotoEffects(UDFBase[AtprotoCancelDelayedArguments, AtprotoCancelDelayedEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(
+        self, execution_context: ExecutionContext, arguments: AtprotoCancelDelayedArguments
+    ) -> AtprotoCancelDelayedEffect:
+        return AtprotoCancelDelayedEffect(key=arguments.key or arguments.entity)
@@ -0,0 +1,64 @@
+from dataclasses import dataclass
+from typing import List, Optional, Self, cast
+
+from ddtrace.internal.logger import get_logger
+from osprey.engine.executor.custom_extracted_features import CustomExtractedFeature
+from osprey.engine.executor.execution_context import ExecutionContext
+from osprey.engine.language_types.effects import EffectToCustomExtractedFeatureBase
+from osprey.engine.stdlib.udfs.categories import UdfCategories
+from osprey.engine.udf.arguments import ArgumentsBase
+from osprey.engine.udf.base import UDFBase
+from osprey.engine.utils.types import add_slots
+
+logger = get_logger('atproto_divert')
+
+
+class AtprotoDivertArguments(ArgumentsBase):
+    entity: str
+    blob_cids: Optional[List[str]] = None
+    comment: Optional[str] = None
+
+
+@dataclass
+class AtprotoDivertEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a divert effect of a WhenRules(...) invocation, which sends a record's blobs to abuse processing."""
+
+    entity: str
+    """The record whose blobs will be diverted. Only records can be diverted."""
+
+    blob_cids: List[str]
+    """The blobs to divert. When empty, every image on the record that was enriched is diverted."""
+
+    comment: Optional[str]
+    """Optional comment that will be included with the divert."""
+
+    def to_str(self) -> str:
+        return f'{self.entity}|{",".join(self.blob_cids)}'
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoDivertEffectsExtractedFeature(effects=cast(List[AtprotoDivertEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoDivertEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoDivertEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_divert'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+class AtprotoDivert(UDFBase[AtprotoDivertArguments, AtprotoDivertEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(self, execution_context: ExecutionContext, arguments: AtprotoDivertArguments) -> AtprotoDivertEffect:
+        return AtprotoDivertEffect(
+            entity=arguments.entity,
+            blob_cids=arguments.blob_cids or [],
+            comment=arguments.comment,
+        )
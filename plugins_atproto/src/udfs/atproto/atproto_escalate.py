@@ -17,6 +17,7 @@ logger = get_logger('atproto_escalate')
 class AtprotoEscalateArguments(ArgumentsBase):
     entity: str
     comment: Optional[str]
+    queue: Optional[str] = None
 
 
 @dataclass
@@ -30,6 +31,9 @@ class AtprotoEscalateEffect(EffectToCustomExtractedFeatureBase[List[str]]):
     comment: Optional[str]
     """Comment to add."""
 
+    queue: Optional[str]
+    """Reviewer queue to route the escalation to, e.g. cse or spam, instead of the general escalation pool."""
+
     def to_str(self) -> str:
         return f'{self.entity}|{self.comment}'
 
@@ -57,6 +61,7 @@ def synthesize_effect(
     return AtprotoEscalateEffect(
         entity=arguments.entity,
         comment=arguments.comment,
+        queue=arguments.queue,
     )
 
 
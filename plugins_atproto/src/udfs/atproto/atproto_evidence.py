@@ -0,0 +1,71 @@
+from dataclasses import dataclass
+from typing import List, Optional, Self, cast
+
+from ddtrace.internal.logger import get_logger
+from osprey.engine.executor.custom_extracted_features import CustomExtractedFeature
+from osprey.engine.executor.execution_context import ExecutionContext
+from osprey.engine.language_types.effects import EffectToCustomExtractedFeatureBase
+from osprey.engine.stdlib.udfs.categories import UdfCategories
+from osprey.engine.udf.arguments import ArgumentsBase
+from osprey.engine.udf.base import UDFBase
+from osprey.engine.utils.types import add_slots
+
+logger = get_logger('atproto_evidence')
+
+
+class AtprotoEvidenceArguments(ArgumentsBase):
+    kind: str
+    name: str = ''
+    value: str = ''
+    score: Optional[float] = None
+
+
+@dataclass
+class AtprotoEvidenceEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a piece of evidence of a WhenRules(...) invocation, such as a classifier score, matched hash or OCR
+    snippet. The effector renders the evidence into a block on the comments of the rules' Ozone events, so that rules
+    don't each format it by hand."""
+
+    kind: str
+    """Where the evidence came from, e.g. hive, hash or ocr."""
+
+    name: str
+    """What was scored or matched, e.g. a classifier class or hash list."""
+
+    value: str
+    """What was found, e.g. the matched hash or the OCR text."""
+
+    score: Optional[float]
+    """The score, for classifiers."""
+
+    def to_str(self) -> str:
+        return f'{self.kind}|{self.name}|{self.value}|{self.score}'
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoEvidenceEffectsExtractedFeature(effects=cast(List[AtprotoEvidenceEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoEvidenceEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoEvidenceEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_evidence'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+class AtprotoEvidence(UDFBase[AtprotoEvidenceArguments, AtprotoEvidenceEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(self, execution_context: ExecutionContext, arguments: AtprotoEvidenceArguments) -> AtprotoEvidenceEffect:
+        return AtprotoEvidenceEffect(
+            kind=arguments.kind,
+            name=arguments.name,
+            value=arguments.value,
+            score=arguments.score,
+        )
@@ -34,6 +34,7 @@ from rpc.osprey_atproto_pb2 import (
     ATPROTO_LABEL_SPAM,
     ATPROTO_LABEL_WARN,
     ATPROTO_SUBJECT_KIND_ACTOR,
+    ATPROTO_SUBJECT_KIND_MESSAGE,
     ATPROTO_SUBJECT_KIND_RECORD,
     AtprotoEffectKind,
     AtprotoEmail,
@@ -58,6 +59,8 @@ def EntityToSubjectKind(entity: str):
         kind = ATPROTO_SUBJECT_KIND_ACTOR
     elif entity.startswith('at://'):
         kind = ATPROTO_SUBJECT_KIND_RECORD
+    elif entity.startswith('chat://'):
+        kind = ATPROTO_SUBJECT_KIND_MESSAGE
     else:
         raise EntityToSubjectKindException()
     return kind
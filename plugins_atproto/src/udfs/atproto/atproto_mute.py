@@ -0,0 +1,89 @@
+from dataclasses import dataclass
+from typing import List, Optional, Self, cast
+
+from ddtrace.internal.logger import get_logger
+from osprey.engine.executor.custom_extracted_features import CustomExtractedFeature
+from osprey.engine.executor.execution_context import ExecutionContext
+from osprey.engine.language_types.effects import EffectToCustomExtractedFeatureBase
+from osprey.engine.stdlib.udfs.categories import UdfCategories
+from osprey.engine.udf.arguments import ArgumentsBase
+from osprey.engine.udf.base import UDFBase
+from osprey.engine.utils.types import add_slots
+from rpc.osprey_atproto_pb2 import ATPROTO_EFFECT_KIND_ADD, ATPROTO_EFFECT_KIND_REMOVE, AtprotoEffectKind
+
+logger = get_logger('atproto_mute')
+
+
+class AddAtprotoMuteArguments(ArgumentsBase):
+    entity: str
+    duration_in_hours: int
+    comment: Optional[str] = None
+
+
+class RemoveAtprotoMuteArguments(ArgumentsBase):
+    entity: str
+    comment: Optional[str] = None
+
+
+@dataclass
+class AtprotoMuteEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a mute effect of a WhenRules(...) invocation. Muting a subject in Ozone hides its incoming reports for
+    a while, a softer step than a takedown."""
+
+    effect_kind: AtprotoEffectKind.ValueType
+    """Whether this mutes or unmutes the entity."""
+
+    entity: str
+    """The entity that will be muted."""
+
+    duration_in_hours: int
+    """How long the entity stays muted. Unused when unmuting."""
+
+    comment: Optional[str]
+    """Optional comment that will be included with the mute."""
+
+    def to_str(self) -> str:
+        return f'{self.entity}|{self.duration_in_hours}'
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoMuteEffectsExtractedFeature(effects=cast(List[AtprotoMuteEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoMuteEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoMuteEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_mute'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+class AddAtprotoMute(UDFBase[AddAtprotoMuteArguments, AtprotoMuteEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(self, execution_context: ExecutionContext, arguments: AddAtprotoMuteArguments) -> AtprotoMuteEffect:
+        return AtprotoMuteEffect(
+            effect_kind=ATPROTO_EFFECT_KIND_ADD,
+            entity=arguments.entity,
+            duration_in_hours=arguments.duration_in_hours,
+            comment=arguments.comment,
+        )
+
+
+class RemoveAtprotoMute(UDFBase[RemoveAtprotoMuteArguments, AtprotoMuteEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(
+        self, execution_context: ExecutionContext, arguments: RemoveAtprotoMuteArguments
+    ) -> AtprotoMuteEffect:
+        return AtprotoMuteEffect(
+            effect_kind=ATPROTO_EFFECT_KIND_REMOVE,
+            entity=arguments.entity,
+            duration_in_hours=0,
+            comment=arguments.comment,
+        )
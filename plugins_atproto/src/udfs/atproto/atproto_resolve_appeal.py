@@ -0,0 +1,62 @@
+from dataclasses import dataclass
+from typing import List, Optional, Self, cast
+
+from ddtrace.internal.logger import get_logger
+from osprey.engine.executor.custom_extracted_features import CustomExtractedFeature
+from osprey.engine.executor.execution_context import ExecutionContext
+from osprey.engine.language_types.effects import EffectToCustomExtractedFeatureBase
+from osprey.engine.stdlib.udfs.categories import UdfCategories
+from osprey.engine.udf.arguments import ArgumentsBase
+from osprey.engine.udf.base import UDFBase
+from osprey.engine.utils.types import add_slots
+
+logger = get_logger('atproto_resolve_appeal')
+
+
+class AtprotoResolveAppealArguments(ArgumentsBase):
+    entity: str
+    comment: Optional[str]
+
+
+@dataclass
+class AtprotoResolveAppealEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a resolve appeal effect of a WhenRules(...) invocation, which closes open appeals on the entity once a
+    given action has finished classification."""
+
+    entity: str
+    """The entity whose appeals will be resolved."""
+
+    comment: Optional[str]
+    """Describes the resolution."""
+
+    def to_str(self) -> str:
+        return f'{self.entity}|{self.comment}'
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoResolveAppealEffectsExtractedFeature(effects=cast(List[AtprotoResolveAppealEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoResolveAppealEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoResolveAppealEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_resolve_appeal'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+class AtprotoResolveAppeal(UDFBase[AtprotoResolveAppealArguments, AtprotoResolveAppealEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(
+        self, execution_context: ExecutionContext, arguments: AtprotoResolveAppealArguments
+    ) -> AtprotoResolveAppealEffect:
+        return AtprotoResolveAppealEffect(
+            entity=arguments.entity,
+            comment=arguments.comment,
+        )
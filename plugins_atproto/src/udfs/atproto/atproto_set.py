@@ -0,0 +1,84 @@
+from dataclasses import dataclass
+from typing import List, Optional, Self, cast
+
+from ddtrace.internal.logger import get_logger
+from osprey.engine.executor.custom_extracted_features import CustomExtractedFeature
+from osprey.engine.executor.execution_context import ExecutionContext
+from osprey.engine.language_types.effects import EffectToCustomExtractedFeatureBase
+from osprey.engine.stdlib.udfs.categories import UdfCategories
+from osprey.engine.udf.arguments import ArgumentsBase
+from osprey.engine.udf.base import UDFBase
+from osprey.engine.utils.types import add_slots
+from rpc.osprey_atproto_pb2 import ATPROTO_EFFECT_KIND_ADD, ATPROTO_EFFECT_KIND_REMOVE, AtprotoEffectKind
+
+logger = get_logger('atproto_set')
+
+
+class AddRemoveAtprotoSetArguments(ArgumentsBase):
+    entity: str
+    set: str
+    comment: Optional[str] = None
+
+
+@dataclass
+class AtprotoSetEffect(EffectToCustomExtractedFeatureBase[List[str]]):
+    """Stores a set effect of a WhenRules(...) invocation, which adds or removes the account's DID from a named Ozone
+    set once a given action has finished classification. Sets hold watchlists and allowlists for other rules or Ozone
+    workflows to consume."""
+
+    effect_kind: AtprotoEffectKind.ValueType
+    """Whether this is an add or remove effect kind."""
+
+    entity: str
+    """The account whose DID will be added to or removed from the set."""
+
+    set: str
+    """The name of the Ozone set, which must already exist."""
+
+    comment: Optional[str]
+    """Optional comment that will be logged with the change."""
+
+    def to_str(self) -> str:
+        return f'{self.entity}|{self.set}'
+
+    @classmethod
+    def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
+        return AtprotoSetEffectsExtractedFeature(effects=cast(List[AtprotoSetEffect], values))
+
+
+@add_slots
+@dataclass
+class AtprotoSetEffectsExtractedFeature(CustomExtractedFeature[List[str]]):
+    effects: List[AtprotoSetEffect]
+
+    @classmethod
+    def feature_name(cls) -> str:
+        return 'atproto_set'
+
+    def get_serializable_feature(self) -> List[str] | None:
+        return [effect.to_str() for effect in self.effects]
+
+
+def synthesize_effect(
+    arguments: AddRemoveAtprotoSetArguments, effect_kind: AtprotoEffectKind.ValueType
+) -> AtprotoSetEffect:
+    return AtprotoSetEffect(
+        effect_kind=effect_kind,
+        entity=arguments.entity,
+        set=arguments.set,
+        comment=arguments.comment,
+    )
+
+
+class AddAtprotoSet(UDFBase[AddRemoveAtprotoSetArguments, AtprotoSetEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(self, execution_context: ExecutionContext, arguments: AddRemoveAtprotoSetArguments) -> AtprotoSetEffect:
+        return synthesize_effect(arguments, ATPROTO_EFFECT_KIND_ADD)
+
+
+class RemoveAtprotoSet(UDFBase[AddRemoveAtprotoSetArguments, AtprotoSetEffect]):
+    category = UdfCategories.ENGINE
+
+    def execute(self, execution_context: ExecutionContext, arguments: AddRemoveAtprotoSetArguments) -> AtprotoSetEffect:
+        return synthesize_effect(arguments, ATPROTO_EFFECT_KIND_REMOVE)
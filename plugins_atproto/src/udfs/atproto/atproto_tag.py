@@ -18,6 +18,7 @@ class AddRemoveAtprotoTagArguments(ArgumentsBase):
     entity: str
     tag: str
     comment: Optional[str] = None
+    expiration_in_hours: Optional[int] = None
 
 
 @dataclass
@@ -37,8 +38,11 @@ class AtprotoTagEffect(EffectToCustomExtractedFeatureBase[List[str]]):
     comment: Optional[str]
     """Optional comment that will be included with the tag."""
 
+    expiration_in_hours: Optional[int] = None
+    """How long until the effector removes the tag again, for tags used as temporary markers."""
+
     def to_str(self) -> str:
-        return f'{self.entity}|{self.tag}'
+        return f'{self.entity}|{self.tag}|{self.expiration_in_hours}'
 
     @classmethod
     def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
@@ -66,6 +70,7 @@ def synthesize_effect(
         entity=arguments.entity,
         tag=arguments.tag,
         comment=arguments.comment,
+        expiration_in_hours=arguments.expiration_in_hours,
     )
 
 
@@ -24,6 +24,8 @@ class AddRemoveAtprotoTakedownArguments(ArgumentsBase):
     entity: str
     comment: str
     email: Optional[str]
+    policies: Optional[List[str]] = None
+    duration_in_hours: Optional[int] = None
 
 
 @dataclass
@@ -42,8 +44,14 @@ class AtprotoTakedownEffect(EffectToCustomExtractedFeatureBase[List[str]]):
     email: Optional[AtprotoEmail.ValueType]
     """The email that will be sent along with the takedown to the user."""
 
+    policies: List[str]
+    """The Ozone policies the takedown was made under."""
+
+    duration_in_hours: Optional[int] = None
+    """How long the takedown lasts. Permanent when unset."""
+
     def to_str(self) -> str:
-        return f'{self.entity}|{self.comment}|{self.email}'
+        return f'{self.entity}|{self.comment}|{self.email}|{",".join(self.policies)}|{self.duration_in_hours}'
 
     @classmethod
     def build_custom_extracted_feature_from_list(cls, values: List[Self]) -> CustomExtractedFeature[List[str]]:
@@ -71,6 +79,8 @@ def synthesize_effect(
         entity=arguments.entity,
         comment=arguments.comment,
         email=StringToAtprotoEmail(arguments.email),
+        policies=arguments.policies or [],
+        duration_in_hours=arguments.duration_in_hours,
     )
 
 
@@ -30,6 +30,8 @@ const (
 	AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE   AtprotoSubjectKind = 0
 	AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_ACTOR  AtprotoSubjectKind = 1
 	AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_RECORD AtprotoSubjectKind = 2
+	// A chat.bsky.convo message, the one in the event's message reference.
+	AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_MESSAGE AtprotoSubjectKind = 3
 )
 
 // Enum value maps for AtprotoSubjectKind.
@@ -38,11 +40,13 @@ var (
 		0: "ATPROTO_SUBJECT_KIND_NONE",
 		1: "ATPROTO_SUBJECT_KIND_ACTOR",
 		2: "ATPROTO_SUBJECT_KIND_RECORD",
+		3: "ATPROTO_SUBJECT_KIND_MESSAGE",
 	}
 	AtprotoSubjectKind_value = map[string]int32{
-		"ATPROTO_SUBJECT_KIND_NONE":   0,
-		"ATPROTO_SUBJECT_KIND_ACTOR":  1,
-		"ATPROTO_SUBJECT_KIND_RECORD": 2,
+		"ATPROTO_SUBJECT_KIND_NONE":    0,
+		"ATPROTO_SUBJECT_KIND_ACTOR":   1,
+		"ATPROTO_SUBJECT_KIND_RECORD":  2,
+		"ATPROTO_SUBJECT_KIND_MESSAGE": 3,
 	}
 )
 
@@ -669,14 +673,16 @@ func (x *AtprotoLabelEffect) GetRules() []string {
 }
 
 type AtprotoTagEffect struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EffectKind    AtprotoEffectKind      `protobuf:"varint,1,opt,name=effect_kind,json=effectKind,proto3,enum=osprey.AtprotoEffectKind" json:"effect_kind,omitempty"`
-	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,2,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
-	Comment       *string                `protobuf:"bytes,4,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
-	Rules         []string               `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	EffectKind  AtprotoEffectKind      `protobuf:"varint,1,opt,name=effect_kind,json=effectKind,proto3,enum=osprey.AtprotoEffectKind" json:"effect_kind,omitempty"`
+	SubjectKind AtprotoSubjectKind     `protobuf:"varint,2,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	Tag         string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	Comment     *string                `protobuf:"bytes,4,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules       []string               `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	// The effector removes the tag again after this long, since Ozone tags don't expire.
+	ExpirationInHours *int64 `protobuf:"varint,6,opt,name=expiration_in_hours,json=expirationInHours,proto3,oneof" json:"expiration_in_hours,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *AtprotoTagEffect) Reset() {
@@ -744,15 +750,24 @@ func (x *AtprotoTagEffect) GetRules() []string {
 	return nil
 }
 
+func (x *AtprotoTagEffect) GetExpirationInHours() int64 {
+	if x != nil && x.ExpirationInHours != nil {
+		return *x.ExpirationInHours
+	}
+	return 0
+}
+
 type AtprotoTakedownEffect struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EffectKind    AtprotoEffectKind      `protobuf:"varint,1,opt,name=effect_kind,json=effectKind,proto3,enum=osprey.AtprotoEffectKind" json:"effect_kind,omitempty"`
-	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,2,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	Comment       string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
-	Email         *AtprotoEmail          `protobuf:"varint,5,opt,name=email,proto3,enum=osprey.AtprotoEmail,oneof" json:"email,omitempty"`
-	Rules         []string               `protobuf:"bytes,6,rep,name=rules,proto3" json:"rules,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EffectKind      AtprotoEffectKind      `protobuf:"varint,1,opt,name=effect_kind,json=effectKind,proto3,enum=osprey.AtprotoEffectKind" json:"effect_kind,omitempty"`
+	SubjectKind     AtprotoSubjectKind     `protobuf:"varint,2,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	Comment         string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	Email           *AtprotoEmail          `protobuf:"varint,5,opt,name=email,proto3,enum=osprey.AtprotoEmail,oneof" json:"email,omitempty"`
+	Rules           []string               `protobuf:"bytes,6,rep,name=rules,proto3" json:"rules,omitempty"`
+	Policies        []string               `protobuf:"bytes,7,rep,name=policies,proto3" json:"policies,omitempty"`                                               // Ozone policy names, for Ozone's policy reporting
+	DurationInHours *int64                 `protobuf:"varint,8,opt,name=duration_in_hours,json=durationInHours,proto3,oneof" json:"duration_in_hours,omitempty"` // makes the takedown temporary
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *AtprotoTakedownEffect) Reset() {
@@ -820,6 +835,20 @@ func (x *AtprotoTakedownEffect) GetRules() []string {
 	return nil
 }
 
+func (x *AtprotoTakedownEffect) GetPolicies() []string {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *AtprotoTakedownEffect) GetDurationInHours() int64 {
+	if x != nil && x.DurationInHours != nil {
+		return *x.DurationInHours
+	}
+	return 0
+}
+
 type AtprotoEmailEffect struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         AtprotoEmail           `protobuf:"varint,1,opt,name=email,proto3,enum=osprey.AtprotoEmail" json:"email,omitempty"`
@@ -880,29 +909,32 @@ func (x *AtprotoEmailEffect) GetRules() []string {
 	return nil
 }
 
-type AtprotoCommentEffect struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	Comment       string                 `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
-	Rules         []string               `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Mutes incoming reports on the subject in Ozone. REMOVE unmutes it.
+type AtprotoMuteEffect struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EffectKind      AtprotoEffectKind      `protobuf:"varint,1,opt,name=effect_kind,json=effectKind,proto3,enum=osprey.AtprotoEffectKind" json:"effect_kind,omitempty"`
+	SubjectKind     AtprotoSubjectKind     `protobuf:"varint,2,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	Comment         *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	DurationInHours int64                  `protobuf:"varint,4,opt,name=duration_in_hours,json=durationInHours,proto3" json:"duration_in_hours,omitempty"`
+	Rules           []string               `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *AtprotoCommentEffect) Reset() {
-	*x = AtprotoCommentEffect{}
+func (x *AtprotoMuteEffect) Reset() {
+	*x = AtprotoMuteEffect{}
 	mi := &file_osprey_atproto_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AtprotoCommentEffect) String() string {
+func (x *AtprotoMuteEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AtprotoCommentEffect) ProtoMessage() {}
+func (*AtprotoMuteEffect) ProtoMessage() {}
 
-func (x *AtprotoCommentEffect) ProtoReflect() protoreflect.Message {
+func (x *AtprotoMuteEffect) ProtoReflect() protoreflect.Message {
 	mi := &file_osprey_atproto_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -914,55 +946,71 @@ func (x *AtprotoCommentEffect) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AtprotoCommentEffect.ProtoReflect.Descriptor instead.
-func (*AtprotoCommentEffect) Descriptor() ([]byte, []int) {
+// Deprecated: Use AtprotoMuteEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoMuteEffect) Descriptor() ([]byte, []int) {
 	return file_osprey_atproto_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *AtprotoCommentEffect) GetSubjectKind() AtprotoSubjectKind {
+func (x *AtprotoMuteEffect) GetEffectKind() AtprotoEffectKind {
+	if x != nil {
+		return x.EffectKind
+	}
+	return AtprotoEffectKind_ATPROTO_EFFECT_KIND_NONE
+}
+
+func (x *AtprotoMuteEffect) GetSubjectKind() AtprotoSubjectKind {
 	if x != nil {
 		return x.SubjectKind
 	}
 	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
 }
 
-func (x *AtprotoCommentEffect) GetComment() string {
-	if x != nil {
-		return x.Comment
+func (x *AtprotoMuteEffect) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
 	}
 	return ""
 }
 
-func (x *AtprotoCommentEffect) GetRules() []string {
+func (x *AtprotoMuteEffect) GetDurationInHours() int64 {
+	if x != nil {
+		return x.DurationInHours
+	}
+	return 0
+}
+
+func (x *AtprotoMuteEffect) GetRules() []string {
 	if x != nil {
 		return x.Rules
 	}
 	return nil
 }
 
-type AtprotoEscalateEffect struct {
+// Diverts the record's blobs to abuse processing in Ozone. Only records can be diverted.
+type AtprotoDivertEffect struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	Comment       *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
-	Rules         []string               `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	BlobCids      []string               `protobuf:"bytes,2,rep,name=blob_cids,json=blobCids,proto3" json:"blob_cids,omitempty"`
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules         []string               `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AtprotoEscalateEffect) Reset() {
-	*x = AtprotoEscalateEffect{}
+func (x *AtprotoDivertEffect) Reset() {
+	*x = AtprotoDivertEffect{}
 	mi := &file_osprey_atproto_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AtprotoEscalateEffect) String() string {
+func (x *AtprotoDivertEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AtprotoEscalateEffect) ProtoMessage() {}
+func (*AtprotoDivertEffect) ProtoMessage() {}
 
-func (x *AtprotoEscalateEffect) ProtoReflect() protoreflect.Message {
+func (x *AtprotoDivertEffect) ProtoReflect() protoreflect.Message {
 	mi := &file_osprey_atproto_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -974,55 +1022,62 @@ func (x *AtprotoEscalateEffect) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AtprotoEscalateEffect.ProtoReflect.Descriptor instead.
-func (*AtprotoEscalateEffect) Descriptor() ([]byte, []int) {
+// Deprecated: Use AtprotoDivertEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoDivertEffect) Descriptor() ([]byte, []int) {
 	return file_osprey_atproto_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *AtprotoEscalateEffect) GetSubjectKind() AtprotoSubjectKind {
+func (x *AtprotoDivertEffect) GetSubjectKind() AtprotoSubjectKind {
 	if x != nil {
 		return x.SubjectKind
 	}
 	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
 }
 
-func (x *AtprotoEscalateEffect) GetComment() string {
+func (x *AtprotoDivertEffect) GetBlobCids() []string {
+	if x != nil {
+		return x.BlobCids
+	}
+	return nil
+}
+
+func (x *AtprotoDivertEffect) GetComment() string {
 	if x != nil && x.Comment != nil {
 		return *x.Comment
 	}
 	return ""
 }
 
-func (x *AtprotoEscalateEffect) GetRules() []string {
+func (x *AtprotoDivertEffect) GetRules() []string {
 	if x != nil {
 		return x.Rules
 	}
 	return nil
 }
 
-type AtprotoAcknowledgeEffect struct {
+type AtprotoCommentEffect struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	Comment       *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Comment       string                 `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
 	Rules         []string               `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AtprotoAcknowledgeEffect) Reset() {
-	*x = AtprotoAcknowledgeEffect{}
+func (x *AtprotoCommentEffect) Reset() {
+	*x = AtprotoCommentEffect{}
 	mi := &file_osprey_atproto_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AtprotoAcknowledgeEffect) String() string {
+func (x *AtprotoCommentEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AtprotoAcknowledgeEffect) ProtoMessage() {}
+func (*AtprotoCommentEffect) ProtoMessage() {}
 
-func (x *AtprotoAcknowledgeEffect) ProtoReflect() protoreflect.Message {
+func (x *AtprotoCommentEffect) ProtoReflect() protoreflect.Message {
 	mi := &file_osprey_atproto_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1034,57 +1089,58 @@ func (x *AtprotoAcknowledgeEffect) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AtprotoAcknowledgeEffect.ProtoReflect.Descriptor instead.
-func (*AtprotoAcknowledgeEffect) Descriptor() ([]byte, []int) {
+// Deprecated: Use AtprotoCommentEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoCommentEffect) Descriptor() ([]byte, []int) {
 	return file_osprey_atproto_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *AtprotoAcknowledgeEffect) GetSubjectKind() AtprotoSubjectKind {
+func (x *AtprotoCommentEffect) GetSubjectKind() AtprotoSubjectKind {
 	if x != nil {
 		return x.SubjectKind
 	}
 	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
 }
 
-func (x *AtprotoAcknowledgeEffect) GetComment() string {
-	if x != nil && x.Comment != nil {
-		return *x.Comment
+func (x *AtprotoCommentEffect) GetComment() string {
+	if x != nil {
+		return x.Comment
 	}
 	return ""
 }
 
-func (x *AtprotoAcknowledgeEffect) GetRules() []string {
+func (x *AtprotoCommentEffect) GetRules() []string {
 	if x != nil {
 		return x.Rules
 	}
 	return nil
 }
 
-type AtprotoReportEffect struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	ReportKind    AtprotoReportKind      `protobuf:"varint,2,opt,name=report_kind,json=reportKind,proto3,enum=osprey.AtprotoReportKind" json:"report_kind,omitempty"`
-	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
-	PriorityScore *int64                 `protobuf:"varint,4,opt,name=priority_score,json=priorityScore,proto3,oneof" json:"priority_score,omitempty"`
-	Rules         []string               `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+type AtprotoEscalateEffect struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	SubjectKind AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	Comment     *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules       []string               `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Reviewer queue to route the escalation to, e.g. "cse" or "spam", by tagging the subject
+	// "queue:<queue>". Escalations without one go to the general escalation pool.
+	Queue         *string `protobuf:"bytes,4,opt,name=queue,proto3,oneof" json:"queue,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AtprotoReportEffect) Reset() {
-	*x = AtprotoReportEffect{}
+func (x *AtprotoEscalateEffect) Reset() {
+	*x = AtprotoEscalateEffect{}
 	mi := &file_osprey_atproto_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AtprotoReportEffect) String() string {
+func (x *AtprotoEscalateEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AtprotoReportEffect) ProtoMessage() {}
+func (*AtprotoEscalateEffect) ProtoMessage() {}
 
-func (x *AtprotoReportEffect) ProtoReflect() protoreflect.Message {
+func (x *AtprotoEscalateEffect) ProtoReflect() protoreflect.Message {
 	mi := &file_osprey_atproto_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1096,70 +1152,63 @@ func (x *AtprotoReportEffect) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AtprotoReportEffect.ProtoReflect.Descriptor instead.
-func (*AtprotoReportEffect) Descriptor() ([]byte, []int) {
+// Deprecated: Use AtprotoEscalateEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoEscalateEffect) Descriptor() ([]byte, []int) {
 	return file_osprey_atproto_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *AtprotoReportEffect) GetSubjectKind() AtprotoSubjectKind {
+func (x *AtprotoEscalateEffect) GetSubjectKind() AtprotoSubjectKind {
 	if x != nil {
 		return x.SubjectKind
 	}
 	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
 }
 
-func (x *AtprotoReportEffect) GetReportKind() AtprotoReportKind {
-	if x != nil {
-		return x.ReportKind
-	}
-	return AtprotoReportKind_ATPROTO_REPORT_KIND_NONE
-}
-
-func (x *AtprotoReportEffect) GetComment() string {
-	if x != nil {
-		return x.Comment
+func (x *AtprotoEscalateEffect) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
 	}
 	return ""
 }
 
-func (x *AtprotoReportEffect) GetPriorityScore() int64 {
-	if x != nil && x.PriorityScore != nil {
-		return *x.PriorityScore
-	}
-	return 0
-}
-
-func (x *AtprotoReportEffect) GetRules() []string {
+func (x *AtprotoEscalateEffect) GetRules() []string {
 	if x != nil {
 		return x.Rules
 	}
 	return nil
 }
 
-type BigQueryFlagEffect struct {
+func (x *AtprotoEscalateEffect) GetQueue() string {
+	if x != nil && x.Queue != nil {
+		return *x.Queue
+	}
+	return ""
+}
+
+// Resolves open appeals on the subject.
+type AtprotoResolveAppealEffect struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
-	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
-	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
-	Rules         []string               `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	Comment       *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules         []string               `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BigQueryFlagEffect) Reset() {
-	*x = BigQueryFlagEffect{}
+func (x *AtprotoResolveAppealEffect) Reset() {
+	*x = AtprotoResolveAppealEffect{}
 	mi := &file_osprey_atproto_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BigQueryFlagEffect) String() string {
+func (x *AtprotoResolveAppealEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BigQueryFlagEffect) ProtoMessage() {}
+func (*AtprotoResolveAppealEffect) ProtoMessage() {}
 
-func (x *BigQueryFlagEffect) ProtoReflect() protoreflect.Message {
+func (x *AtprotoResolveAppealEffect) ProtoReflect() protoreflect.Message {
 	mi := &file_osprey_atproto_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1171,75 +1220,58 @@ func (x *BigQueryFlagEffect) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BigQueryFlagEffect.ProtoReflect.Descriptor instead.
-func (*BigQueryFlagEffect) Descriptor() ([]byte, []int) {
+// Deprecated: Use AtprotoResolveAppealEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoResolveAppealEffect) Descriptor() ([]byte, []int) {
 	return file_osprey_atproto_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *BigQueryFlagEffect) GetSubjectKind() AtprotoSubjectKind {
+func (x *AtprotoResolveAppealEffect) GetSubjectKind() AtprotoSubjectKind {
 	if x != nil {
 		return x.SubjectKind
 	}
 	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
 }
 
-func (x *BigQueryFlagEffect) GetTag() string {
-	if x != nil {
-		return x.Tag
-	}
-	return ""
-}
-
-func (x *BigQueryFlagEffect) GetComment() string {
+func (x *AtprotoResolveAppealEffect) GetComment() string {
 	if x != nil && x.Comment != nil {
 		return *x.Comment
 	}
 	return ""
 }
 
-func (x *BigQueryFlagEffect) GetRules() []string {
+func (x *AtprotoResolveAppealEffect) GetRules() []string {
 	if x != nil {
 		return x.Rules
 	}
 	return nil
 }
 
-type ResultEvent struct {
-	state            protoimpl.MessageState      `protogen:"open.v1"`
-	SendTime         *timestamppb.Timestamp      `protobuf:"bytes,1,opt,name=send_time,json=sendTime,proto3" json:"send_time,omitempty"`
-	ActionName       string                      `protobuf:"bytes,2,opt,name=action_name,json=actionName,proto3" json:"action_name,omitempty"`
-	ActionId         int64                       `protobuf:"varint,3,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
-	Did              string                      `protobuf:"bytes,4,opt,name=did,proto3" json:"did,omitempty"`
-	Uri              string                      `protobuf:"bytes,5,opt,name=uri,proto3" json:"uri,omitempty"`
-	Cid              string                      `protobuf:"bytes,6,opt,name=cid,proto3" json:"cid,omitempty"`
-	Data             []byte                      `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
-	Labels           []*AtprotoLabelEffect       `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty"`
-	Tags             []*AtprotoTagEffect         `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
-	Takedowns        []*AtprotoTakedownEffect    `protobuf:"bytes,10,rep,name=takedowns,proto3" json:"takedowns,omitempty"`
-	Emails           []*AtprotoEmailEffect       `protobuf:"bytes,11,rep,name=emails,proto3" json:"emails,omitempty"`
-	Comments         []*AtprotoCommentEffect     `protobuf:"bytes,12,rep,name=comments,proto3" json:"comments,omitempty"`
-	Escalations      []*AtprotoEscalateEffect    `protobuf:"bytes,13,rep,name=escalations,proto3" json:"escalations,omitempty"`
-	Acknowledgements []*AtprotoAcknowledgeEffect `protobuf:"bytes,14,rep,name=acknowledgements,proto3" json:"acknowledgements,omitempty"`
-	Reports          []*AtprotoReportEffect      `protobuf:"bytes,15,rep,name=reports,proto3" json:"reports,omitempty"`
-	BigqueryFlags    []*BigQueryFlagEffect       `protobuf:"bytes,16,rep,name=bigqueryFlags,proto3" json:"bigqueryFlags,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// Adds the subject's DID to a named Ozone set (tools.ozone.set), for watchlists and allowlists
+// that other rules or Ozone workflows consume. REMOVE deletes it from the set.
+type AtprotoSetEffect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EffectKind    AtprotoEffectKind      `protobuf:"varint,1,opt,name=effect_kind,json=effectKind,proto3,enum=osprey.AtprotoEffectKind" json:"effect_kind,omitempty"`
+	Set           string                 `protobuf:"bytes,2,opt,name=set,proto3" json:"set,omitempty"`
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules         []string               `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResultEvent) Reset() {
-	*x = ResultEvent{}
+func (x *AtprotoSetEffect) Reset() {
+	*x = AtprotoSetEffect{}
 	mi := &file_osprey_atproto_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResultEvent) String() string {
+func (x *AtprotoSetEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResultEvent) ProtoMessage() {}
+func (*AtprotoSetEffect) ProtoMessage() {}
 
-func (x *ResultEvent) ProtoReflect() protoreflect.Message {
+func (x *AtprotoSetEffect) ProtoReflect() protoreflect.Message {
 	mi := &file_osprey_atproto_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1251,150 +1283,2089 @@ func (x *ResultEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResultEvent.ProtoReflect.Descriptor instead.
-func (*ResultEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use AtprotoSetEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoSetEffect) Descriptor() ([]byte, []int) {
 	return file_osprey_atproto_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ResultEvent) GetSendTime() *timestamppb.Timestamp {
+func (x *AtprotoSetEffect) GetEffectKind() AtprotoEffectKind {
 	if x != nil {
-		return x.SendTime
+		return x.EffectKind
 	}
-	return nil
+	return AtprotoEffectKind_ATPROTO_EFFECT_KIND_NONE
 }
 
-func (x *ResultEvent) GetActionName() string {
+func (x *AtprotoSetEffect) GetSet() string {
 	if x != nil {
-		return x.ActionName
+		return x.Set
 	}
 	return ""
 }
 
-func (x *ResultEvent) GetActionId() int64 {
-	if x != nil {
-		return x.ActionId
-	}
-	return 0
-}
-
-func (x *ResultEvent) GetDid() string {
-	if x != nil {
-		return x.Did
+func (x *AtprotoSetEffect) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
 	}
 	return ""
 }
 
-func (x *ResultEvent) GetUri() string {
+func (x *AtprotoSetEffect) GetRules() []string {
 	if x != nil {
-		return x.Uri
+		return x.Rules
 	}
-	return ""
+	return nil
 }
 
-func (x *ResultEvent) GetCid() string {
-	if x != nil {
-		return x.Cid
-	}
-	return ""
+type AtprotoAcknowledgeEffect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	Comment       *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules         []string               `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResultEvent) GetData() []byte {
-	if x != nil {
-		return x.Data
-	}
-	return nil
+func (x *AtprotoAcknowledgeEffect) Reset() {
+	*x = AtprotoAcknowledgeEffect{}
+	mi := &file_osprey_atproto_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResultEvent) GetLabels() []*AtprotoLabelEffect {
-	if x != nil {
-		return x.Labels
-	}
-	return nil
+func (x *AtprotoAcknowledgeEffect) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ResultEvent) GetTags() []*AtprotoTagEffect {
-	if x != nil {
-		return x.Tags
-	}
-	return nil
-}
+func (*AtprotoAcknowledgeEffect) ProtoMessage() {}
+
+func (x *AtprotoAcknowledgeEffect) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AtprotoAcknowledgeEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoAcknowledgeEffect) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AtprotoAcknowledgeEffect) GetSubjectKind() AtprotoSubjectKind {
+	if x != nil {
+		return x.SubjectKind
+	}
+	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
+}
+
+func (x *AtprotoAcknowledgeEffect) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+func (x *AtprotoAcknowledgeEffect) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type AtprotoReportEffect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	ReportKind    AtprotoReportKind      `protobuf:"varint,2,opt,name=report_kind,json=reportKind,proto3,enum=osprey.AtprotoReportKind" json:"report_kind,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	PriorityScore *int64                 `protobuf:"varint,4,opt,name=priority_score,json=priorityScore,proto3,oneof" json:"priority_score,omitempty"`
+	Rules         []string               `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AtprotoReportEffect) Reset() {
+	*x = AtprotoReportEffect{}
+	mi := &file_osprey_atproto_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AtprotoReportEffect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AtprotoReportEffect) ProtoMessage() {}
+
+func (x *AtprotoReportEffect) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AtprotoReportEffect.ProtoReflect.Descriptor instead.
+func (*AtprotoReportEffect) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AtprotoReportEffect) GetSubjectKind() AtprotoSubjectKind {
+	if x != nil {
+		return x.SubjectKind
+	}
+	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
+}
+
+func (x *AtprotoReportEffect) GetReportKind() AtprotoReportKind {
+	if x != nil {
+		return x.ReportKind
+	}
+	return AtprotoReportKind_ATPROTO_REPORT_KIND_NONE
+}
+
+func (x *AtprotoReportEffect) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *AtprotoReportEffect) GetPriorityScore() int64 {
+	if x != nil && x.PriorityScore != nil {
+		return *x.PriorityScore
+	}
+	return 0
+}
+
+func (x *AtprotoReportEffect) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type BigQueryFlagEffect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubjectKind   AtprotoSubjectKind     `protobuf:"varint,1,opt,name=subject_kind,json=subjectKind,proto3,enum=osprey.AtprotoSubjectKind" json:"subject_kind,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Rules         []string               `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BigQueryFlagEffect) Reset() {
+	*x = BigQueryFlagEffect{}
+	mi := &file_osprey_atproto_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BigQueryFlagEffect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BigQueryFlagEffect) ProtoMessage() {}
+
+func (x *BigQueryFlagEffect) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BigQueryFlagEffect.ProtoReflect.Descriptor instead.
+func (*BigQueryFlagEffect) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BigQueryFlagEffect) GetSubjectKind() AtprotoSubjectKind {
+	if x != nil {
+		return x.SubjectKind
+	}
+	return AtprotoSubjectKind_ATPROTO_SUBJECT_KIND_NONE
+}
+
+func (x *BigQueryFlagEffect) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *BigQueryFlagEffect) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+func (x *BigQueryFlagEffect) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ResultEvent struct {
+	state            protoimpl.MessageState        `protogen:"open.v1"`
+	SendTime         *timestamppb.Timestamp        `protobuf:"bytes,1,opt,name=send_time,json=sendTime,proto3" json:"send_time,omitempty"`
+	ActionName       string                        `protobuf:"bytes,2,opt,name=action_name,json=actionName,proto3" json:"action_name,omitempty"`
+	ActionId         int64                         `protobuf:"varint,3,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	Did              string                        `protobuf:"bytes,4,opt,name=did,proto3" json:"did,omitempty"`
+	Uri              string                        `protobuf:"bytes,5,opt,name=uri,proto3" json:"uri,omitempty"`
+	Cid              string                        `protobuf:"bytes,6,opt,name=cid,proto3" json:"cid,omitempty"`
+	Data             []byte                        `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+	Labels           []*AtprotoLabelEffect         `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty"`
+	Tags             []*AtprotoTagEffect           `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	Takedowns        []*AtprotoTakedownEffect      `protobuf:"bytes,10,rep,name=takedowns,proto3" json:"takedowns,omitempty"`
+	Emails           []*AtprotoEmailEffect         `protobuf:"bytes,11,rep,name=emails,proto3" json:"emails,omitempty"`
+	Comments         []*AtprotoCommentEffect       `protobuf:"bytes,12,rep,name=comments,proto3" json:"comments,omitempty"`
+	Escalations      []*AtprotoEscalateEffect      `protobuf:"bytes,13,rep,name=escalations,proto3" json:"escalations,omitempty"`
+	Acknowledgements []*AtprotoAcknowledgeEffect   `protobuf:"bytes,14,rep,name=acknowledgements,proto3" json:"acknowledgements,omitempty"`
+	Reports          []*AtprotoReportEffect        `protobuf:"bytes,15,rep,name=reports,proto3" json:"reports,omitempty"`
+	BigqueryFlags    []*BigQueryFlagEffect         `protobuf:"bytes,16,rep,name=bigqueryFlags,proto3" json:"bigqueryFlags,omitempty"`
+	Mutes            []*AtprotoMuteEffect          `protobuf:"bytes,17,rep,name=mutes,proto3" json:"mutes,omitempty"`
+	Diverts          []*AtprotoDivertEffect        `protobuf:"bytes,18,rep,name=diverts,proto3" json:"diverts,omitempty"`
+	ResolveAppeals   []*AtprotoResolveAppealEffect `protobuf:"bytes,19,rep,name=resolve_appeals,json=resolveAppeals,proto3" json:"resolve_appeals,omitempty"`
+	Sets             []*AtprotoSetEffect           `protobuf:"bytes,20,rep,name=sets,proto3" json:"sets,omitempty"`
+	Delayed          []*DelayedEffects             `protobuf:"bytes,21,rep,name=delayed,proto3" json:"delayed,omitempty"`
+	// Keys of delayed effects to cancel, if they're still waiting.
+	CancelDelayed []string `protobuf:"bytes,22,rep,name=cancel_delayed,json=cancelDelayed,proto3" json:"cancel_delayed,omitempty"`
+	// The chat message effects with the message subject kind are on, for events about DMs.
+	Message       *ChatMessageRef `protobuf:"bytes,23,opt,name=message,proto3" json:"message,omitempty"`
+	Evidence      []*Evidence     `protobuf:"bytes,24,rep,name=evidence,proto3" json:"evidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResultEvent) Reset() {
+	*x = ResultEvent{}
+	mi := &file_osprey_atproto_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultEvent) ProtoMessage() {}
+
+func (x *ResultEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultEvent.ProtoReflect.Descriptor instead.
+func (*ResultEvent) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ResultEvent) GetSendTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SendTime
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetActionName() string {
+	if x != nil {
+		return x.ActionName
+	}
+	return ""
+}
+
+func (x *ResultEvent) GetActionId() int64 {
+	if x != nil {
+		return x.ActionId
+	}
+	return 0
+}
+
+func (x *ResultEvent) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *ResultEvent) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *ResultEvent) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *ResultEvent) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetLabels() []*AtprotoLabelEffect {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetTags() []*AtprotoTagEffect {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
 
 func (x *ResultEvent) GetTakedowns() []*AtprotoTakedownEffect {
 	if x != nil {
-		return x.Takedowns
+		return x.Takedowns
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetEmails() []*AtprotoEmailEffect {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetComments() []*AtprotoCommentEffect {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetEscalations() []*AtprotoEscalateEffect {
+	if x != nil {
+		return x.Escalations
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetAcknowledgements() []*AtprotoAcknowledgeEffect {
+	if x != nil {
+		return x.Acknowledgements
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetReports() []*AtprotoReportEffect {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetBigqueryFlags() []*BigQueryFlagEffect {
+	if x != nil {
+		return x.BigqueryFlags
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetMutes() []*AtprotoMuteEffect {
+	if x != nil {
+		return x.Mutes
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetDiverts() []*AtprotoDivertEffect {
+	if x != nil {
+		return x.Diverts
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetResolveAppeals() []*AtprotoResolveAppealEffect {
+	if x != nil {
+		return x.ResolveAppeals
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetSets() []*AtprotoSetEffect {
+	if x != nil {
+		return x.Sets
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetDelayed() []*DelayedEffects {
+	if x != nil {
+		return x.Delayed
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetCancelDelayed() []string {
+	if x != nil {
+		return x.CancelDelayed
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetMessage() *ChatMessageRef {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ResultEvent) GetEvidence() []*Evidence {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+// Evidence behind a rule's effects, such as a classifier score, matched hash or OCR snippet. The
+// effector renders it into a block on the comments of the rule's Ozone events.
+type Evidence struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Where the evidence came from, e.g. hive, hash or ocr.
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// What was scored or matched, e.g. a classifier class or hash list.
+	Name  string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Score *float64 `protobuf:"fixed64,4,opt,name=score,proto3,oneof" json:"score,omitempty"`
+	// The rules the evidence is for. Evidence without rules is shown on all of the event's effects.
+	Rules         []string `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Evidence) Reset() {
+	*x = Evidence{}
+	mi := &file_osprey_atproto_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Evidence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Evidence) ProtoMessage() {}
+
+func (x *Evidence) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Evidence.ProtoReflect.Descriptor instead.
+func (*Evidence) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Evidence) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Evidence) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Evidence) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Evidence) GetScore() float64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *Evidence) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// A chat.bsky.convo message, sent by did.
+type ChatMessageRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	ConvoId       string                 `protobuf:"bytes,2,opt,name=convo_id,json=convoId,proto3" json:"convo_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessageRef) Reset() {
+	*x = ChatMessageRef{}
+	mi := &file_osprey_atproto_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessageRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessageRef) ProtoMessage() {}
+
+func (x *ChatMessageRef) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessageRef.ProtoReflect.Descriptor instead.
+func (*ChatMessageRef) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChatMessageRef) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *ChatMessageRef) GetConvoId() string {
+	if x != nil {
+		return x.ConvoId
+	}
+	return ""
+}
+
+func (x *ChatMessageRef) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+// Effects the effector holds back until execute_after_seconds have passed, then applies unless an
+// event cancelled them by key in the meantime. Delayed effects with the same key as ones already
+// waiting supersede them.
+type DelayedEffects struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Event               *ResultEvent           `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	ExecuteAfterSeconds int64                  `protobuf:"varint,2,opt,name=execute_after_seconds,json=executeAfterSeconds,proto3" json:"execute_after_seconds,omitempty"`
+	Key                 string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DelayedEffects) Reset() {
+	*x = DelayedEffects{}
+	mi := &file_osprey_atproto_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelayedEffects) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelayedEffects) ProtoMessage() {}
+
+func (x *DelayedEffects) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelayedEffects.ProtoReflect.Descriptor instead.
+func (*DelayedEffects) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DelayedEffects) GetEvent() *ResultEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *DelayedEffects) GetExecuteAfterSeconds() int64 {
+	if x != nil {
+		return x.ExecuteAfterSeconds
+	}
+	return 0
+}
+
+func (x *DelayedEffects) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// Effects the effector failed to apply, produced to its retry topic. The event only holds the
+// effects that failed.
+type FailedEffects struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *ResultEvent           `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Attempts      int32                  `protobuf:"varint,2,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastError     string                 `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailedEffects) Reset() {
+	*x = FailedEffects{}
+	mi := &file_osprey_atproto_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedEffects) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedEffects) ProtoMessage() {}
+
+func (x *FailedEffects) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedEffects.ProtoReflect.Descriptor instead.
+func (*FailedEffects) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FailedEffects) GetEvent() *ResultEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *FailedEffects) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *FailedEffects) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *FailedEffects) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+// The outcome of a moderation event the effector sent to Ozone, produced to its outcomes topic.
+type EffectOutcome struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ActionName    string                 `protobuf:"bytes,2,opt,name=action_name,json=actionName,proto3" json:"action_name,omitempty"`
+	ActionId      int64                  `protobuf:"varint,3,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`       // the kind of Ozone event, e.g. "takedown", "reverse-takedown", "label", "priority-score"
+	Subject       string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"` // DID or AT-URI
+	Rules         []string               `protobuf:"bytes,6,rep,name=rules,proto3" json:"rules,omitempty"`
+	Success       bool                   `protobuf:"varint,7,opt,name=success,proto3" json:"success,omitempty"`
+	Error         *string                `protobuf:"bytes,8,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	OzoneEventId  *int64                 `protobuf:"varint,9,opt,name=ozone_event_id,json=ozoneEventId,proto3,oneof" json:"ozone_event_id,omitempty"` // only set when the event was sent successfully
+	DryRun        bool                   `protobuf:"varint,10,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                          // the event was only logged, since this isn't production
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EffectOutcome) Reset() {
+	*x = EffectOutcome{}
+	mi := &file_osprey_atproto_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EffectOutcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EffectOutcome) ProtoMessage() {}
+
+func (x *EffectOutcome) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EffectOutcome.ProtoReflect.Descriptor instead.
+func (*EffectOutcome) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EffectOutcome) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *EffectOutcome) GetActionName() string {
+	if x != nil {
+		return x.ActionName
+	}
+	return ""
+}
+
+func (x *EffectOutcome) GetActionId() int64 {
+	if x != nil {
+		return x.ActionId
+	}
+	return 0
+}
+
+func (x *EffectOutcome) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *EffectOutcome) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *EffectOutcome) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *EffectOutcome) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *EffectOutcome) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *EffectOutcome) GetOzoneEventId() int64 {
+	if x != nil && x.OzoneEventId != nil {
+		return *x.OzoneEventId
+	}
+	return 0
+}
+
+func (x *EffectOutcome) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type FirehoseEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Kind          EventKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=osprey.EventKind" json:"kind,omitempty"`
+	Commit        *Commit                `protobuf:"bytes,4,opt,name=commit,proto3" json:"commit,omitempty"`
+	Account       []byte                 `protobuf:"bytes,5,opt,name=account,proto3" json:"account,omitempty"`   // comatproto.SyncSubscribeRepos_Account as opaque bytes
+	Identity      []byte                 `protobuf:"bytes,6,opt,name=identity,proto3" json:"identity,omitempty"` // comatproto.SyncSubscribeRepos_Identity as opaque bytes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FirehoseEvent) Reset() {
+	*x = FirehoseEvent{}
+	mi := &file_osprey_atproto_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FirehoseEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirehoseEvent) ProtoMessage() {}
+
+func (x *FirehoseEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirehoseEvent.ProtoReflect.Descriptor instead.
+func (*FirehoseEvent) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FirehoseEvent) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *FirehoseEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *FirehoseEvent) GetKind() EventKind {
+	if x != nil {
+		return x.Kind
+	}
+	return EventKind_EVENT_KIND_UNSPECIFIED
+}
+
+func (x *FirehoseEvent) GetCommit() *Commit {
+	if x != nil {
+		return x.Commit
+	}
+	return nil
+}
+
+func (x *FirehoseEvent) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *FirehoseEvent) GetIdentity() []byte {
+	if x != nil {
+		return x.Identity
+	}
+	return nil
+}
+
+type Commit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rev           string                 `protobuf:"bytes,1,opt,name=rev,proto3" json:"rev,omitempty"`
+	Operation     CommitOperation        `protobuf:"varint,2,opt,name=operation,proto3,enum=osprey.CommitOperation" json:"operation,omitempty"`
+	Collection    string                 `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
+	Rkey          string                 `protobuf:"bytes,4,opt,name=rkey,proto3" json:"rkey,omitempty"`
+	Record        []byte                 `protobuf:"bytes,5,opt,name=record,proto3" json:"record,omitempty"` // json.RawMessage as opaque bytes
+	Cid           string                 `protobuf:"bytes,6,opt,name=cid,proto3" json:"cid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Commit) Reset() {
+	*x = Commit{}
+	mi := &file_osprey_atproto_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Commit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Commit) ProtoMessage() {}
+
+func (x *Commit) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Commit.ProtoReflect.Descriptor instead.
+func (*Commit) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Commit) GetRev() string {
+	if x != nil {
+		return x.Rev
+	}
+	return ""
+}
+
+func (x *Commit) GetOperation() CommitOperation {
+	if x != nil {
+		return x.Operation
+	}
+	return CommitOperation_COMMIT_OPERATION_UNSPECIFIED
+}
+
+func (x *Commit) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *Commit) GetRkey() string {
+	if x != nil {
+		return x.Rkey
+	}
+	return ""
+}
+
+func (x *Commit) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *Commit) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+type Cursor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	SavedOnExit   bool                   `protobuf:"varint,2,opt,name=saved_on_exit,json=savedOnExit,proto3" json:"saved_on_exit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cursor) Reset() {
+	*x = Cursor{}
+	mi := &file_osprey_atproto_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cursor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cursor) ProtoMessage() {}
+
+func (x *Cursor) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cursor.ProtoReflect.Descriptor instead.
+func (*Cursor) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Cursor) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Cursor) GetSavedOnExit() bool {
+	if x != nil {
+		return x.SavedOnExit
+	}
+	return false
+}
+
+type ModerationEnrichedFirehoseRecordEvent struct {
+	state                protoimpl.MessageState           `protogen:"open.v1"`
+	Did                  string                           `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	Timestamp            *timestamppb.Timestamp           `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Collection           string                           `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
+	Rkey                 string                           `protobuf:"bytes,4,opt,name=rkey,proto3" json:"rkey,omitempty"`
+	Operation            CommitOperation                  `protobuf:"varint,5,opt,name=operation,proto3,enum=osprey.CommitOperation" json:"operation,omitempty"`
+	Record               []byte                           `protobuf:"bytes,6,opt,name=record,proto3" json:"record,omitempty"`                                                                                                           // json.RawMessage as opaque bytes
+	ImageResults         map[string]*ImageDispatchResults `protobuf:"bytes,7,rep,name=image_results,json=imageResults,proto3" json:"image_results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // map of image_cid to ImageDispatchResults
+	OzoneRepoViewDetail  []byte                           `protobuf:"bytes,8,opt,name=ozone_repo_view_detail,json=ozoneRepoViewDetail,proto3,oneof" json:"ozone_repo_view_detail,omitempty"`                                            // JSON encoded repoViewDetail from Ozone for the actor
+	DidDoc               []byte                           `protobuf:"bytes,9,opt,name=did_doc,json=didDoc,proto3,oneof" json:"did_doc,omitempty"`                                                                                       // JSON encoded DID Document
+	ProfileView          []byte                           `protobuf:"bytes,10,opt,name=profile_view,json=profileView,proto3,oneof" json:"profile_view,omitempty"`                                                                       // JSON encoded ProfileViewDetailed from AppView
+	DidAuditLog          []byte                           `protobuf:"bytes,11,opt,name=did_audit_log,json=didAuditLog,proto3,oneof" json:"did_audit_log,omitempty"`                                                                     // JSON encoded DID audit log
+	Cid                  string                           `protobuf:"bytes,12,opt,name=cid,proto3" json:"cid,omitempty"`
+	Velocity             *VelocityFeatures                `protobuf:"bytes,13,opt,name=velocity,proto3,oneof" json:"velocity,omitempty"`                                               // per-DID sliding window counters, including this record
+	TermListMatches      []*TermListMatch                 `protobuf:"bytes,14,rep,name=term_list_matches,json=termListMatches,proto3" json:"term_list_matches,omitempty"`              // keyword/regex lists that matched the record's text
+	ImpersonationMatches []*ImpersonationMatch            `protobuf:"bytes,15,rep,name=impersonation_matches,json=impersonationMatches,proto3" json:"impersonation_matches,omitempty"` // protected accounts the handle or display name resembles
+	Identity             *IdentityFeatures                `protobuf:"bytes,16,opt,name=identity,proto3,oneof" json:"identity,omitempty"`                                               // derived from the DID audit log
+	Pds                  *PdsFeatures                     `protobuf:"bytes,17,opt,name=pds,proto3,oneof" json:"pds,omitempty"`                                                         // reputation of the PDS from the DID document
+	CollectionContext    *CollectionContext               `protobuf:"bytes,18,opt,name=collection_context,json=collectionContext,proto3,oneof" json:"collection_context,omitempty"`    // labeler, feed generator, list and starter pack records only
+	Watchlists           []string                         `protobuf:"bytes,19,rep,name=watchlists,proto3" json:"watchlists,omitempty"`                                                 // URIs of the configured moderation lists the author is a member of
+	ExistingLabels       []*ExistingLabel                 `protobuf:"bytes,20,rep,name=existing_labels,json=existingLabels,proto3" json:"existing_labels,omitempty"`                   // labels our labeler has already applied to the account or record
+	BlobTypeMismatches   []*BlobTypeMismatch              `protobuf:"bytes,21,rep,name=blob_type_mismatches,json=blobTypeMismatches,proto3" json:"blob_type_mismatches,omitempty"`     // blobs whose content isn't the kind of media they were declared as
+	Pipeline             *PipelineTimes                   `protobuf:"bytes,22,opt,name=pipeline,proto3,oneof" json:"pipeline,omitempty"`                                               // when the enricher worked on the event, for computing staleness
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) Reset() {
+	*x = ModerationEnrichedFirehoseRecordEvent{}
+	mi := &file_osprey_atproto_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationEnrichedFirehoseRecordEvent) ProtoMessage() {}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationEnrichedFirehoseRecordEvent.ProtoReflect.Descriptor instead.
+func (*ModerationEnrichedFirehoseRecordEvent) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetRkey() string {
+	if x != nil {
+		return x.Rkey
+	}
+	return ""
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetOperation() CommitOperation {
+	if x != nil {
+		return x.Operation
+	}
+	return CommitOperation_COMMIT_OPERATION_UNSPECIFIED
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetImageResults() map[string]*ImageDispatchResults {
+	if x != nil {
+		return x.ImageResults
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetOzoneRepoViewDetail() []byte {
+	if x != nil {
+		return x.OzoneRepoViewDetail
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetDidDoc() []byte {
+	if x != nil {
+		return x.DidDoc
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetProfileView() []byte {
+	if x != nil {
+		return x.ProfileView
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetDidAuditLog() []byte {
+	if x != nil {
+		return x.DidAuditLog
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetVelocity() *VelocityFeatures {
+	if x != nil {
+		return x.Velocity
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetTermListMatches() []*TermListMatch {
+	if x != nil {
+		return x.TermListMatches
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetImpersonationMatches() []*ImpersonationMatch {
+	if x != nil {
+		return x.ImpersonationMatches
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetIdentity() *IdentityFeatures {
+	if x != nil {
+		return x.Identity
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetPds() *PdsFeatures {
+	if x != nil {
+		return x.Pds
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetCollectionContext() *CollectionContext {
+	if x != nil {
+		return x.CollectionContext
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetWatchlists() []string {
+	if x != nil {
+		return x.Watchlists
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetExistingLabels() []*ExistingLabel {
+	if x != nil {
+		return x.ExistingLabels
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetBlobTypeMismatches() []*BlobTypeMismatch {
+	if x != nil {
+		return x.BlobTypeMismatches
+	}
+	return nil
+}
+
+func (x *ModerationEnrichedFirehoseRecordEvent) GetPipeline() *PipelineTimes {
+	if x != nil {
+		return x.Pipeline
+	}
+	return nil
+}
+
+type VelocityFeatures struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	PostsLastMinute            int64                  `protobuf:"varint,1,opt,name=posts_last_minute,json=postsLastMinute,proto3" json:"posts_last_minute,omitempty"`
+	PostsLastHour              int64                  `protobuf:"varint,2,opt,name=posts_last_hour,json=postsLastHour,proto3" json:"posts_last_hour,omitempty"`
+	ImagesLastHour             int64                  `protobuf:"varint,3,opt,name=images_last_hour,json=imagesLastHour,proto3" json:"images_last_hour,omitempty"`
+	DistinctMentionsLastHour   int64                  `protobuf:"varint,4,opt,name=distinct_mentions_last_hour,json=distinctMentionsLastHour,proto3" json:"distinct_mentions_last_hour,omitempty"`         // distinct DIDs mentioned across posts
+	IdenticalTextPostsLastHour int64                  `protobuf:"varint,5,opt,name=identical_text_posts_last_hour,json=identicalTextPostsLastHour,proto3" json:"identical_text_posts_last_hour,omitempty"` // posts with the same normalized text as this one
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *VelocityFeatures) Reset() {
+	*x = VelocityFeatures{}
+	mi := &file_osprey_atproto_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VelocityFeatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VelocityFeatures) ProtoMessage() {}
+
+func (x *VelocityFeatures) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VelocityFeatures.ProtoReflect.Descriptor instead.
+func (*VelocityFeatures) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *VelocityFeatures) GetPostsLastMinute() int64 {
+	if x != nil {
+		return x.PostsLastMinute
+	}
+	return 0
+}
+
+func (x *VelocityFeatures) GetPostsLastHour() int64 {
+	if x != nil {
+		return x.PostsLastHour
+	}
+	return 0
+}
+
+func (x *VelocityFeatures) GetImagesLastHour() int64 {
+	if x != nil {
+		return x.ImagesLastHour
+	}
+	return 0
+}
+
+func (x *VelocityFeatures) GetDistinctMentionsLastHour() int64 {
+	if x != nil {
+		return x.DistinctMentionsLastHour
+	}
+	return 0
+}
+
+func (x *VelocityFeatures) GetIdenticalTextPostsLastHour() int64 {
+	if x != nil {
+		return x.IdenticalTextPostsLastHour
+	}
+	return 0
+}
+
+type TermListMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          string                 `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Severity      string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
+	Fields        []string               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"` // "post_text", "profile_text", "alt_text", "ocr_text"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TermListMatch) Reset() {
+	*x = TermListMatch{}
+	mi := &file_osprey_atproto_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TermListMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TermListMatch) ProtoMessage() {}
+
+func (x *TermListMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TermListMatch.ProtoReflect.Descriptor instead.
+func (*TermListMatch) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TermListMatch) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *TermListMatch) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *TermListMatch) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *TermListMatch) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ImpersonationMatch struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProtectedDid    string                 `protobuf:"bytes,1,opt,name=protected_did,json=protectedDid,proto3" json:"protected_did,omitempty"`
+	ProtectedHandle string                 `protobuf:"bytes,2,opt,name=protected_handle,json=protectedHandle,proto3" json:"protected_handle,omitempty"`
+	Field           string                 `protobuf:"bytes,3,opt,name=field,proto3" json:"field,omitempty"`   // "handle", "display_name"
+	Score           float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"` // similarity from 0 to 1 after homoglyph normalization
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ImpersonationMatch) Reset() {
+	*x = ImpersonationMatch{}
+	mi := &file_osprey_atproto_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImpersonationMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImpersonationMatch) ProtoMessage() {}
+
+func (x *ImpersonationMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImpersonationMatch.ProtoReflect.Descriptor instead.
+func (*ImpersonationMatch) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ImpersonationMatch) GetProtectedDid() string {
+	if x != nil {
+		return x.ProtectedDid
+	}
+	return ""
+}
+
+func (x *ImpersonationMatch) GetProtectedHandle() string {
+	if x != nil {
+		return x.ProtectedHandle
+	}
+	return ""
+}
+
+func (x *ImpersonationMatch) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ImpersonationMatch) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type IdentityFeatures struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	AccountCreatedAt    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=account_created_at,json=accountCreatedAt,proto3" json:"account_created_at,omitempty"` // time of the first PLC operation
+	AccountAgeSeconds   int64                  `protobuf:"varint,2,opt,name=account_age_seconds,json=accountAgeSeconds,proto3" json:"account_age_seconds,omitempty"`
+	OperationCount      int64                  `protobuf:"varint,3,opt,name=operation_count,json=operationCount,proto3" json:"operation_count,omitempty"`
+	RecentHandleChanges int64                  `protobuf:"varint,4,opt,name=recent_handle_changes,json=recentHandleChanges,proto3" json:"recent_handle_changes,omitempty"` // in the last 30 days
+	PdsMigrations       int64                  `protobuf:"varint,5,opt,name=pds_migrations,json=pdsMigrations,proto3" json:"pds_migrations,omitempty"`
+	RotationKeyChanges  int64                  `protobuf:"varint,6,opt,name=rotation_key_changes,json=rotationKeyChanges,proto3" json:"rotation_key_changes,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *IdentityFeatures) Reset() {
+	*x = IdentityFeatures{}
+	mi := &file_osprey_atproto_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdentityFeatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdentityFeatures) ProtoMessage() {}
+
+func (x *IdentityFeatures) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdentityFeatures.ProtoReflect.Descriptor instead.
+func (*IdentityFeatures) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *IdentityFeatures) GetAccountCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccountCreatedAt
+	}
+	return nil
+}
+
+func (x *IdentityFeatures) GetAccountAgeSeconds() int64 {
+	if x != nil {
+		return x.AccountAgeSeconds
+	}
+	return 0
+}
+
+func (x *IdentityFeatures) GetOperationCount() int64 {
+	if x != nil {
+		return x.OperationCount
+	}
+	return 0
+}
+
+func (x *IdentityFeatures) GetRecentHandleChanges() int64 {
+	if x != nil {
+		return x.RecentHandleChanges
+	}
+	return 0
+}
+
+func (x *IdentityFeatures) GetPdsMigrations() int64 {
+	if x != nil {
+		return x.PdsMigrations
+	}
+	return 0
+}
+
+func (x *IdentityFeatures) GetRotationKeyChanges() int64 {
+	if x != nil {
+		return x.RotationKeyChanges
+	}
+	return 0
+}
+
+type PdsFeatures struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Host           string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`                                         // normalized PDS hostname
+	BlueskyHosted  bool                   `protobuf:"varint,2,opt,name=bluesky_hosted,json=blueskyHosted,proto3" json:"bluesky_hosted,omitempty"` // false for self-hosted and third-party PDSes
+	HostFirstSeen  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=host_first_seen,json=hostFirstSeen,proto3" json:"host_first_seen,omitempty"`
+	HostAgeSeconds int64                  `protobuf:"varint,4,opt,name=host_age_seconds,json=hostAgeSeconds,proto3" json:"host_age_seconds,omitempty"` // time since we first saw an account on this host
+	SuspiciousHost bool                   `protobuf:"varint,5,opt,name=suspicious_host,json=suspiciousHost,proto3" json:"suspicious_host,omitempty"`   // host is on the configured suspicious-host list
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PdsFeatures) Reset() {
+	*x = PdsFeatures{}
+	mi := &file_osprey_atproto_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PdsFeatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PdsFeatures) ProtoMessage() {}
+
+func (x *PdsFeatures) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PdsFeatures.ProtoReflect.Descriptor instead.
+func (*PdsFeatures) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PdsFeatures) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *PdsFeatures) GetBlueskyHosted() bool {
+	if x != nil {
+		return x.BlueskyHosted
+	}
+	return false
+}
+
+func (x *PdsFeatures) GetHostFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HostFirstSeen
+	}
+	return nil
+}
+
+func (x *PdsFeatures) GetHostAgeSeconds() int64 {
+	if x != nil {
+		return x.HostAgeSeconds
+	}
+	return 0
+}
+
+func (x *PdsFeatures) GetSuspiciousHost() bool {
+	if x != nil {
+		return x.SuspiciousHost
+	}
+	return false
+}
+
+type CollectionContext struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ServiceEndpoint *string                `protobuf:"bytes,1,opt,name=service_endpoint,json=serviceEndpoint,proto3,oneof" json:"service_endpoint,omitempty"` // labeler or feed generator service endpoint
+	ServiceDid      *string                `protobuf:"bytes,2,opt,name=service_did,json=serviceDid,proto3,oneof" json:"service_did,omitempty"`                // feed generator service DID
+	ListUri         *string                `protobuf:"bytes,3,opt,name=list_uri,json=listUri,proto3,oneof" json:"list_uri,omitempty"`                         // the list itself, or the list a starter pack references
+	ListItemCount   *int64                 `protobuf:"varint,4,opt,name=list_item_count,json=listItemCount,proto3,oneof" json:"list_item_count,omitempty"`
+	AvatarCids      []string               `protobuf:"bytes,5,rep,name=avatar_cids,json=avatarCids,proto3" json:"avatar_cids,omitempty"` // avatar images, results are in image_results
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CollectionContext) Reset() {
+	*x = CollectionContext{}
+	mi := &file_osprey_atproto_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionContext) ProtoMessage() {}
+
+func (x *CollectionContext) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionContext.ProtoReflect.Descriptor instead.
+func (*CollectionContext) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CollectionContext) GetServiceEndpoint() string {
+	if x != nil && x.ServiceEndpoint != nil {
+		return *x.ServiceEndpoint
+	}
+	return ""
+}
+
+func (x *CollectionContext) GetServiceDid() string {
+	if x != nil && x.ServiceDid != nil {
+		return *x.ServiceDid
+	}
+	return ""
+}
+
+func (x *CollectionContext) GetListUri() string {
+	if x != nil && x.ListUri != nil {
+		return *x.ListUri
+	}
+	return ""
+}
+
+func (x *CollectionContext) GetListItemCount() int64 {
+	if x != nil && x.ListItemCount != nil {
+		return *x.ListItemCount
+	}
+	return 0
+}
+
+func (x *CollectionContext) GetAvatarCids() []string {
+	if x != nil {
+		return x.AvatarCids
+	}
+	return nil
+}
+
+type ExistingLabel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uri           string                 `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"` // the account DID or record AT-URI the label is applied to
+	Val           string                 `protobuf:"bytes,2,opt,name=val,proto3" json:"val,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExistingLabel) Reset() {
+	*x = ExistingLabel{}
+	mi := &file_osprey_atproto_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExistingLabel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExistingLabel) ProtoMessage() {}
+
+func (x *ExistingLabel) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExistingLabel.ProtoReflect.Descriptor instead.
+func (*ExistingLabel) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ExistingLabel) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *ExistingLabel) GetVal() string {
+	if x != nil {
+		return x.Val
+	}
+	return ""
+}
+
+func (x *ExistingLabel) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type PipelineTimes struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	EventTimestamp       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=event_timestamp,json=eventTimestamp,proto3" json:"event_timestamp,omitempty"` // from the firehose, same as the event's timestamp
+	EnrichmentStartedAt  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=enrichment_started_at,json=enrichmentStartedAt,proto3" json:"enrichment_started_at,omitempty"`
+	EnrichmentFinishedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=enrichment_finished_at,json=enrichmentFinishedAt,proto3" json:"enrichment_finished_at,omitempty"`
+	StalenessMs          int64                  `protobuf:"varint,4,opt,name=staleness_ms,json=stalenessMs,proto3" json:"staleness_ms,omitempty"` // from event_timestamp to enrichment_finished_at
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PipelineTimes) Reset() {
+	*x = PipelineTimes{}
+	mi := &file_osprey_atproto_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineTimes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineTimes) ProtoMessage() {}
+
+func (x *PipelineTimes) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineTimes.ProtoReflect.Descriptor instead.
+func (*PipelineTimes) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PipelineTimes) GetEventTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTimestamp
+	}
+	return nil
+}
+
+func (x *PipelineTimes) GetEnrichmentStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EnrichmentStartedAt
+	}
+	return nil
+}
+
+func (x *PipelineTimes) GetEnrichmentFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EnrichmentFinishedAt
+	}
+	return nil
+}
+
+func (x *PipelineTimes) GetStalenessMs() int64 {
+	if x != nil {
+		return x.StalenessMs
+	}
+	return 0
+}
+
+type BlobTypeMismatch struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Cid              string                 `protobuf:"bytes,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	DeclaredMimeType string                 `protobuf:"bytes,2,opt,name=declared_mime_type,json=declaredMimeType,proto3" json:"declared_mime_type,omitempty"` // from the record
+	SniffedMimeType  string                 `protobuf:"bytes,3,opt,name=sniffed_mime_type,json=sniffedMimeType,proto3" json:"sniffed_mime_type,omitempty"`    // from the blob's leading bytes
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BlobTypeMismatch) Reset() {
+	*x = BlobTypeMismatch{}
+	mi := &file_osprey_atproto_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobTypeMismatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobTypeMismatch) ProtoMessage() {}
+
+func (x *BlobTypeMismatch) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobTypeMismatch.ProtoReflect.Descriptor instead.
+func (*BlobTypeMismatch) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BlobTypeMismatch) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *BlobTypeMismatch) GetDeclaredMimeType() string {
+	if x != nil {
+		return x.DeclaredMimeType
+	}
+	return ""
+}
+
+func (x *BlobTypeMismatch) GetSniffedMimeType() string {
+	if x != nil {
+		return x.SniffedMimeType
+	}
+	return ""
+}
+
+type ImageDispatchResults struct {
+	state         protoimpl.MessageState                  `protogen:"open.v1"`
+	Cid           string                                  `protobuf:"bytes,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	Abyss         *ImageDispatchResults_AbyssResults      `protobuf:"bytes,2,opt,name=abyss,proto3,oneof" json:"abyss,omitempty"`
+	Hive          *ImageDispatchResults_HiveResults       `protobuf:"bytes,3,opt,name=hive,proto3,oneof" json:"hive,omitempty"`
+	Retina        *ImageDispatchResults_RetinaResults     `protobuf:"bytes,4,opt,name=retina,proto3,oneof" json:"retina,omitempty"`
+	Prescreen     *ImageDispatchResults_PrescreenResults  `protobuf:"bytes,6,opt,name=prescreen,proto3,oneof" json:"prescreen,omitempty"`
+	RetinaHash    *ImageDispatchResults_RetinaHashResults `protobuf:"bytes,7,opt,name=retina_hash,json=retinaHash,proto3,oneof" json:"retina_hash,omitempty"`
+	Ncii          *ImageDispatchResults_NciiResults       `protobuf:"bytes,8,opt,name=ncii,proto3,oneof" json:"ncii,omitempty"`
+	Flagged       *ImageDispatchResults_FlaggedResults    `protobuf:"bytes,9,opt,name=flagged,proto3,oneof" json:"flagged,omitempty"`
+	Animation     *ImageDispatchResults_AnimationResults  `protobuf:"bytes,10,opt,name=animation,proto3,oneof" json:"animation,omitempty"`
+	Sightings     *ImageDispatchResults_Sightings         `protobuf:"bytes,11,opt,name=sightings,proto3,oneof" json:"sightings,omitempty"`
+	LinkCard      *ImageDispatchResults_LinkCard          `protobuf:"bytes,12,opt,name=link_card,json=linkCard,proto3,oneof" json:"link_card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDispatchResults) Reset() {
+	*x = ImageDispatchResults{}
+	mi := &file_osprey_atproto_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDispatchResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDispatchResults) ProtoMessage() {}
+
+func (x *ImageDispatchResults) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDispatchResults.ProtoReflect.Descriptor instead.
+func (*ImageDispatchResults) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ImageDispatchResults) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *ImageDispatchResults) GetAbyss() *ImageDispatchResults_AbyssResults {
+	if x != nil {
+		return x.Abyss
+	}
+	return nil
+}
+
+func (x *ImageDispatchResults) GetHive() *ImageDispatchResults_HiveResults {
+	if x != nil {
+		return x.Hive
+	}
+	return nil
+}
+
+func (x *ImageDispatchResults) GetRetina() *ImageDispatchResults_RetinaResults {
+	if x != nil {
+		return x.Retina
 	}
 	return nil
 }
 
-func (x *ResultEvent) GetEmails() []*AtprotoEmailEffect {
+func (x *ImageDispatchResults) GetPrescreen() *ImageDispatchResults_PrescreenResults {
 	if x != nil {
-		return x.Emails
+		return x.Prescreen
 	}
 	return nil
 }
 
-func (x *ResultEvent) GetComments() []*AtprotoCommentEffect {
+func (x *ImageDispatchResults) GetRetinaHash() *ImageDispatchResults_RetinaHashResults {
 	if x != nil {
-		return x.Comments
+		return x.RetinaHash
 	}
 	return nil
 }
 
-func (x *ResultEvent) GetEscalations() []*AtprotoEscalateEffect {
+func (x *ImageDispatchResults) GetNcii() *ImageDispatchResults_NciiResults {
 	if x != nil {
-		return x.Escalations
+		return x.Ncii
 	}
 	return nil
 }
 
-func (x *ResultEvent) GetAcknowledgements() []*AtprotoAcknowledgeEffect {
+func (x *ImageDispatchResults) GetFlagged() *ImageDispatchResults_FlaggedResults {
 	if x != nil {
-		return x.Acknowledgements
+		return x.Flagged
 	}
 	return nil
 }
 
-func (x *ResultEvent) GetReports() []*AtprotoReportEffect {
+func (x *ImageDispatchResults) GetAnimation() *ImageDispatchResults_AnimationResults {
 	if x != nil {
-		return x.Reports
+		return x.Animation
 	}
 	return nil
 }
 
-func (x *ResultEvent) GetBigqueryFlags() []*BigQueryFlagEffect {
+func (x *ImageDispatchResults) GetSightings() *ImageDispatchResults_Sightings {
 	if x != nil {
-		return x.BigqueryFlags
+		return x.Sightings
 	}
 	return nil
 }
 
-type FirehoseEvent struct {
+func (x *ImageDispatchResults) GetLinkCard() *ImageDispatchResults_LinkCard {
+	if x != nil {
+		return x.LinkCard
+	}
+	return nil
+}
+
+// A moderation report received through the report intake webhook, produced with the action name
+// "moderation.report#create".
+type ModerationReportEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Kind          EventKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=osprey.EventKind" json:"kind,omitempty"`
-	Commit        *Commit                `protobuf:"bytes,4,opt,name=commit,proto3" json:"commit,omitempty"`
-	Account       []byte                 `protobuf:"bytes,5,opt,name=account,proto3" json:"account,omitempty"`   // comatproto.SyncSubscribeRepos_Account as opaque bytes
-	Identity      []byte                 `protobuf:"bytes,6,opt,name=identity,proto3" json:"identity,omitempty"` // comatproto.SyncSubscribeRepos_Identity as opaque bytes
+	ReportId      int64                  `protobuf:"varint,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`                           // e.g. "ozone"
+	ReasonType    string                 `protobuf:"bytes,3,opt,name=reason_type,json=reasonType,proto3" json:"reason_type,omitempty"` // e.g. "com.atproto.moderation.defs#reasonSpam"
+	Reason        *string                `protobuf:"bytes,4,opt,name=reason,proto3,oneof" json:"reason,omitempty"`
+	SubjectDid    string                 `protobuf:"bytes,5,opt,name=subject_did,json=subjectDid,proto3" json:"subject_did,omitempty"`
+	SubjectUri    *string                `protobuf:"bytes,6,opt,name=subject_uri,json=subjectUri,proto3,oneof" json:"subject_uri,omitempty"` // set when the report is about a record
+	SubjectCid    *string                `protobuf:"bytes,7,opt,name=subject_cid,json=subjectCid,proto3,oneof" json:"subject_cid,omitempty"`
+	ReportedBy    string                 `protobuf:"bytes,8,opt,name=reported_by,json=reportedBy,proto3" json:"reported_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FirehoseEvent) Reset() {
-	*x = FirehoseEvent{}
-	mi := &file_osprey_atproto_proto_msgTypes[12]
+func (x *ModerationReportEvent) Reset() {
+	*x = ModerationReportEvent{}
+	mi := &file_osprey_atproto_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FirehoseEvent) String() string {
+func (x *ModerationReportEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FirehoseEvent) ProtoMessage() {}
+func (*ModerationReportEvent) ProtoMessage() {}
 
-func (x *FirehoseEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[12]
+func (x *ModerationReportEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1405,80 +3376,106 @@ func (x *FirehoseEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FirehoseEvent.ProtoReflect.Descriptor instead.
-func (*FirehoseEvent) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ModerationReportEvent.ProtoReflect.Descriptor instead.
+func (*ModerationReportEvent) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *FirehoseEvent) GetDid() string {
+func (x *ModerationReportEvent) GetReportId() int64 {
 	if x != nil {
-		return x.Did
+		return x.ReportId
 	}
-	return ""
+	return 0
 }
 
-func (x *FirehoseEvent) GetTimestamp() *timestamppb.Timestamp {
+func (x *ModerationReportEvent) GetSource() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Source
 	}
-	return nil
+	return ""
 }
 
-func (x *FirehoseEvent) GetKind() EventKind {
+func (x *ModerationReportEvent) GetReasonType() string {
 	if x != nil {
-		return x.Kind
+		return x.ReasonType
 	}
-	return EventKind_EVENT_KIND_UNSPECIFIED
+	return ""
 }
 
-func (x *FirehoseEvent) GetCommit() *Commit {
-	if x != nil {
-		return x.Commit
+func (x *ModerationReportEvent) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
 	}
-	return nil
+	return ""
 }
 
-func (x *FirehoseEvent) GetAccount() []byte {
+func (x *ModerationReportEvent) GetSubjectDid() string {
 	if x != nil {
-		return x.Account
+		return x.SubjectDid
 	}
-	return nil
+	return ""
 }
 
-func (x *FirehoseEvent) GetIdentity() []byte {
+func (x *ModerationReportEvent) GetSubjectUri() string {
+	if x != nil && x.SubjectUri != nil {
+		return *x.SubjectUri
+	}
+	return ""
+}
+
+func (x *ModerationReportEvent) GetSubjectCid() string {
+	if x != nil && x.SubjectCid != nil {
+		return *x.SubjectCid
+	}
+	return ""
+}
+
+func (x *ModerationReportEvent) GetReportedBy() string {
 	if x != nil {
-		return x.Identity
+		return x.ReportedBy
 	}
-	return nil
+	return ""
 }
 
-type Commit struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Rev           string                 `protobuf:"bytes,1,opt,name=rev,proto3" json:"rev,omitempty"`
-	Operation     CommitOperation        `protobuf:"varint,2,opt,name=operation,proto3,enum=osprey.CommitOperation" json:"operation,omitempty"`
-	Collection    string                 `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
-	Rkey          string                 `protobuf:"bytes,4,opt,name=rkey,proto3" json:"rkey,omitempty"`
-	Record        []byte                 `protobuf:"bytes,5,opt,name=record,proto3" json:"record,omitempty"` // json.RawMessage as opaque bytes
-	Cid           string                 `protobuf:"bytes,6,opt,name=cid,proto3" json:"cid,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ModerationReportEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
 }
 
-func (x *Commit) Reset() {
-	*x = Commit{}
-	mi := &file_osprey_atproto_proto_msgTypes[13]
+// A temporary label the effector applied that has since expired, produced with the action name
+// "atproto.label#expired" so that rules can step up if the behavior continued.
+type LabelExpiredEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SubjectDid      string                 `protobuf:"bytes,1,opt,name=subject_did,json=subjectDid,proto3" json:"subject_did,omitempty"`
+	SubjectUri      *string                `protobuf:"bytes,2,opt,name=subject_uri,json=subjectUri,proto3,oneof" json:"subject_uri,omitempty"` // set when the label was on a record
+	Label           string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Rules           []string               `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`                             // the rules that applied the label
+	ActionName      string                 `protobuf:"bytes,5,opt,name=action_name,json=actionName,proto3" json:"action_name,omitempty"` // of the event the label was applied for
+	ActionId        int64                  `protobuf:"varint,6,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	DurationInHours int64                  `protobuf:"varint,7,opt,name=duration_in_hours,json=durationInHours,proto3" json:"duration_in_hours,omitempty"`
+	AppliedAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`
+	ExpiredAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LabelExpiredEvent) Reset() {
+	*x = LabelExpiredEvent{}
+	mi := &file_osprey_atproto_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Commit) String() string {
+func (x *LabelExpiredEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Commit) ProtoMessage() {}
+func (*LabelExpiredEvent) ProtoMessage() {}
 
-func (x *Commit) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[13]
+func (x *LabelExpiredEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1489,76 +3486,100 @@ func (x *Commit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Commit.ProtoReflect.Descriptor instead.
-func (*Commit) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use LabelExpiredEvent.ProtoReflect.Descriptor instead.
+func (*LabelExpiredEvent) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *Commit) GetRev() string {
+func (x *LabelExpiredEvent) GetSubjectDid() string {
 	if x != nil {
-		return x.Rev
+		return x.SubjectDid
 	}
 	return ""
 }
 
-func (x *Commit) GetOperation() CommitOperation {
-	if x != nil {
-		return x.Operation
+func (x *LabelExpiredEvent) GetSubjectUri() string {
+	if x != nil && x.SubjectUri != nil {
+		return *x.SubjectUri
 	}
-	return CommitOperation_COMMIT_OPERATION_UNSPECIFIED
+	return ""
 }
 
-func (x *Commit) GetCollection() string {
+func (x *LabelExpiredEvent) GetLabel() string {
 	if x != nil {
-		return x.Collection
+		return x.Label
 	}
 	return ""
 }
 
-func (x *Commit) GetRkey() string {
+func (x *LabelExpiredEvent) GetRules() []string {
 	if x != nil {
-		return x.Rkey
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *LabelExpiredEvent) GetActionName() string {
+	if x != nil {
+		return x.ActionName
 	}
 	return ""
 }
 
-func (x *Commit) GetRecord() []byte {
+func (x *LabelExpiredEvent) GetActionId() int64 {
 	if x != nil {
-		return x.Record
+		return x.ActionId
+	}
+	return 0
+}
+
+func (x *LabelExpiredEvent) GetDurationInHours() int64 {
+	if x != nil {
+		return x.DurationInHours
+	}
+	return 0
+}
+
+func (x *LabelExpiredEvent) GetAppliedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AppliedAt
 	}
 	return nil
 }
 
-func (x *Commit) GetCid() string {
+func (x *LabelExpiredEvent) GetExpiredAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Cid
+		return x.ExpiredAt
 	}
-	return ""
+	return nil
 }
 
-type Cursor struct {
+// An image fetched from the CDN by its DID and CID.
+type RetinaImageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sequence      int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
-	SavedOnExit   bool                   `protobuf:"varint,2,opt,name=saved_on_exit,json=savedOnExit,proto3" json:"saved_on_exit,omitempty"`
+	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	Cid           string                 `protobuf:"bytes,2,opt,name=cid,proto3" json:"cid,omitempty"`
+	Ocr           *RetinaOCROptions      `protobuf:"bytes,3,opt,name=ocr,proto3" json:"ocr,omitempty"`
+	CdnHost       string                 `protobuf:"bytes,4,opt,name=cdn_host,json=cdnHost,proto3" json:"cdn_host,omitempty"` // one of the server's allowed CDN hosts, if not its default
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Cursor) Reset() {
-	*x = Cursor{}
-	mi := &file_osprey_atproto_proto_msgTypes[14]
+func (x *RetinaImageRequest) Reset() {
+	*x = RetinaImageRequest{}
+	mi := &file_osprey_atproto_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Cursor) String() string {
+func (x *RetinaImageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Cursor) ProtoMessage() {}
+func (*RetinaImageRequest) ProtoMessage() {}
 
-func (x *Cursor) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[14]
+func (x *RetinaImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1569,58 +3590,62 @@ func (x *Cursor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Cursor.ProtoReflect.Descriptor instead.
-func (*Cursor) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use RetinaImageRequest.ProtoReflect.Descriptor instead.
+func (*RetinaImageRequest) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *Cursor) GetSequence() int64 {
+func (x *RetinaImageRequest) GetDid() string {
 	if x != nil {
-		return x.Sequence
+		return x.Did
 	}
-	return 0
+	return ""
 }
 
-func (x *Cursor) GetSavedOnExit() bool {
+func (x *RetinaImageRequest) GetCid() string {
 	if x != nil {
-		return x.SavedOnExit
+		return x.Cid
 	}
-	return false
+	return ""
 }
 
-type ModerationEnrichedFirehoseRecordEvent struct {
-	state               protoimpl.MessageState           `protogen:"open.v1"`
-	Did                 string                           `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
-	Timestamp           *timestamppb.Timestamp           `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Collection          string                           `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
-	Rkey                string                           `protobuf:"bytes,4,opt,name=rkey,proto3" json:"rkey,omitempty"`
-	Operation           CommitOperation                  `protobuf:"varint,5,opt,name=operation,proto3,enum=osprey.CommitOperation" json:"operation,omitempty"`
-	Record              []byte                           `protobuf:"bytes,6,opt,name=record,proto3" json:"record,omitempty"`                                                                                                           // json.RawMessage as opaque bytes
-	ImageResults        map[string]*ImageDispatchResults `protobuf:"bytes,7,rep,name=image_results,json=imageResults,proto3" json:"image_results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // map of image_cid to ImageDispatchResults
-	OzoneRepoViewDetail []byte                           `protobuf:"bytes,8,opt,name=ozone_repo_view_detail,json=ozoneRepoViewDetail,proto3,oneof" json:"ozone_repo_view_detail,omitempty"`                                            // JSON encoded repoViewDetail from Ozone for the actor
-	DidDoc              []byte                           `protobuf:"bytes,9,opt,name=did_doc,json=didDoc,proto3,oneof" json:"did_doc,omitempty"`                                                                                       // JSON encoded DID Document
-	ProfileView         []byte                           `protobuf:"bytes,10,opt,name=profile_view,json=profileView,proto3,oneof" json:"profile_view,omitempty"`                                                                       // JSON encoded ProfileViewDetailed from AppView
-	DidAuditLog         []byte                           `protobuf:"bytes,11,opt,name=did_audit_log,json=didAuditLog,proto3,oneof" json:"did_audit_log,omitempty"`                                                                     // JSON encoded DID audit log
-	Cid                 string                           `protobuf:"bytes,12,opt,name=cid,proto3" json:"cid,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+func (x *RetinaImageRequest) GetOcr() *RetinaOCROptions {
+	if x != nil {
+		return x.Ocr
+	}
+	return nil
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) Reset() {
-	*x = ModerationEnrichedFirehoseRecordEvent{}
-	mi := &file_osprey_atproto_proto_msgTypes[15]
+func (x *RetinaImageRequest) GetCdnHost() string {
+	if x != nil {
+		return x.CdnHost
+	}
+	return ""
+}
+
+type RetinaImageChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ocr           *RetinaOCROptions      `protobuf:"bytes,1,opt,name=ocr,proto3,oneof" json:"ocr,omitempty"` // only read from the first chunk
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetinaImageChunk) Reset() {
+	*x = RetinaImageChunk{}
+	mi := &file_osprey_atproto_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) String() string {
+func (x *RetinaImageChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ModerationEnrichedFirehoseRecordEvent) ProtoMessage() {}
+func (*RetinaImageChunk) ProtoMessage() {}
 
-func (x *ModerationEnrichedFirehoseRecordEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[15]
+func (x *RetinaImageChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1631,124 +3656,146 @@ func (x *ModerationEnrichedFirehoseRecordEvent) ProtoReflect() protoreflect.Mess
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ModerationEnrichedFirehoseRecordEvent.ProtoReflect.Descriptor instead.
-func (*ModerationEnrichedFirehoseRecordEvent) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use RetinaImageChunk.ProtoReflect.Descriptor instead.
+func (*RetinaImageChunk) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetDid() string {
+func (x *RetinaImageChunk) GetOcr() *RetinaOCROptions {
 	if x != nil {
-		return x.Did
+		return x.Ocr
 	}
-	return ""
+	return nil
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetTimestamp() *timestamppb.Timestamp {
+func (x *RetinaImageChunk) GetData() []byte {
 	if x != nil {
-		return x.Timestamp
+		return x.Data
 	}
 	return nil
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetCollection() string {
-	if x != nil {
-		return x.Collection
-	}
-	return ""
+type RetinaOCROptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Langs         string                 `protobuf:"bytes,1,opt,name=langs,proto3" json:"langs,omitempty"`           // e.g. "jpn+eng", or the server's default when empty
+	Preprocess    string                 `protobuf:"bytes,2,opt,name=preprocess,proto3" json:"preprocess,omitempty"` // comma separated steps, "none", or the server's default when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetRkey() string {
-	if x != nil {
-		return x.Rkey
-	}
-	return ""
+func (x *RetinaOCROptions) Reset() {
+	*x = RetinaOCROptions{}
+	mi := &file_osprey_atproto_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetOperation() CommitOperation {
-	if x != nil {
-		return x.Operation
-	}
-	return CommitOperation_COMMIT_OPERATION_UNSPECIFIED
+func (x *RetinaOCROptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetRecord() []byte {
+func (*RetinaOCROptions) ProtoMessage() {}
+
+func (x *RetinaOCROptions) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[39]
 	if x != nil {
-		return x.Record
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetImageResults() map[string]*ImageDispatchResults {
-	if x != nil {
-		return x.ImageResults
-	}
-	return nil
+// Deprecated: Use RetinaOCROptions.ProtoReflect.Descriptor instead.
+func (*RetinaOCROptions) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetOzoneRepoViewDetail() []byte {
+func (x *RetinaOCROptions) GetLangs() string {
 	if x != nil {
-		return x.OzoneRepoViewDetail
+		return x.Langs
 	}
-	return nil
+	return ""
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetDidDoc() []byte {
+func (x *RetinaOCROptions) GetPreprocess() string {
 	if x != nil {
-		return x.DidDoc
+		return x.Preprocess
 	}
-	return nil
+	return ""
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetProfileView() []byte {
+type RetinaAnalyzeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetinaAnalyzeResponse) Reset() {
+	*x = RetinaAnalyzeResponse{}
+	mi := &file_osprey_atproto_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetinaAnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetinaAnalyzeResponse) ProtoMessage() {}
+
+func (x *RetinaAnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[40]
 	if x != nil {
-		return x.ProfileView
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetDidAuditLog() []byte {
-	if x != nil {
-		return x.DidAuditLog
-	}
-	return nil
+// Deprecated: Use RetinaAnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*RetinaAnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *ModerationEnrichedFirehoseRecordEvent) GetCid() string {
+func (x *RetinaAnalyzeResponse) GetText() string {
 	if x != nil {
-		return x.Cid
+		return x.Text
 	}
 	return ""
 }
 
-type ImageDispatchResults struct {
-	state         protoimpl.MessageState                  `protogen:"open.v1"`
-	Cid           string                                  `protobuf:"bytes,1,opt,name=cid,proto3" json:"cid,omitempty"`
-	Abyss         *ImageDispatchResults_AbyssResults      `protobuf:"bytes,2,opt,name=abyss,proto3,oneof" json:"abyss,omitempty"`
-	Hive          *ImageDispatchResults_HiveResults       `protobuf:"bytes,3,opt,name=hive,proto3,oneof" json:"hive,omitempty"`
-	Retina        *ImageDispatchResults_RetinaResults     `protobuf:"bytes,4,opt,name=retina,proto3,oneof" json:"retina,omitempty"`
-	Prescreen     *ImageDispatchResults_PrescreenResults  `protobuf:"bytes,6,opt,name=prescreen,proto3,oneof" json:"prescreen,omitempty"`
-	RetinaHash    *ImageDispatchResults_RetinaHashResults `protobuf:"bytes,7,opt,name=retina_hash,json=retinaHash,proto3,oneof" json:"retina_hash,omitempty"`
-	Ncii          *ImageDispatchResults_NciiResults       `protobuf:"bytes,8,opt,name=ncii,proto3,oneof" json:"ncii,omitempty"`
-	Flagged       *ImageDispatchResults_FlaggedResults    `protobuf:"bytes,9,opt,name=flagged,proto3,oneof" json:"flagged,omitempty"`
+type RetinaHashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Binary        string                 `protobuf:"bytes,2,opt,name=binary,proto3" json:"binary,omitempty"`
+	QualityTooLow bool                   `protobuf:"varint,3,opt,name=quality_too_low,json=qualityTooLow,proto3" json:"quality_too_low,omitempty"`
+	Checksums     *RetinaChecksums       `protobuf:"bytes,4,opt,name=checksums,proto3" json:"checksums,omitempty"` // only set by HashBlob
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ImageDispatchResults) Reset() {
-	*x = ImageDispatchResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[16]
+func (x *RetinaHashResponse) Reset() {
+	*x = RetinaHashResponse{}
+	mi := &file_osprey_atproto_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ImageDispatchResults) String() string {
+func (x *RetinaHashResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImageDispatchResults) ProtoMessage() {}
+func (*RetinaHashResponse) ProtoMessage() {}
 
-func (x *ImageDispatchResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[16]
+func (x *RetinaHashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1759,65 +3806,97 @@ func (x *ImageDispatchResults) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImageDispatchResults.ProtoReflect.Descriptor instead.
-func (*ImageDispatchResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use RetinaHashResponse.ProtoReflect.Descriptor instead.
+func (*RetinaHashResponse) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *ImageDispatchResults) GetCid() string {
+func (x *RetinaHashResponse) GetHash() string {
 	if x != nil {
-		return x.Cid
+		return x.Hash
 	}
 	return ""
 }
 
-func (x *ImageDispatchResults) GetAbyss() *ImageDispatchResults_AbyssResults {
+func (x *RetinaHashResponse) GetBinary() string {
 	if x != nil {
-		return x.Abyss
+		return x.Binary
 	}
-	return nil
+	return ""
 }
 
-func (x *ImageDispatchResults) GetHive() *ImageDispatchResults_HiveResults {
+func (x *RetinaHashResponse) GetQualityTooLow() bool {
 	if x != nil {
-		return x.Hive
+		return x.QualityTooLow
 	}
-	return nil
+	return false
 }
 
-func (x *ImageDispatchResults) GetRetina() *ImageDispatchResults_RetinaResults {
+func (x *RetinaHashResponse) GetChecksums() *RetinaChecksums {
 	if x != nil {
-		return x.Retina
+		return x.Checksums
 	}
 	return nil
 }
 
-func (x *ImageDispatchResults) GetPrescreen() *ImageDispatchResults_PrescreenResults {
+type RetinaChecksums struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Md5           string                 `protobuf:"bytes,1,opt,name=md5,proto3" json:"md5,omitempty"`
+	Sha1          string                 `protobuf:"bytes,2,opt,name=sha1,proto3" json:"sha1,omitempty"`
+	Sha256        string                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetinaChecksums) Reset() {
+	*x = RetinaChecksums{}
+	mi := &file_osprey_atproto_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetinaChecksums) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetinaChecksums) ProtoMessage() {}
+
+func (x *RetinaChecksums) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[42]
 	if x != nil {
-		return x.Prescreen
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ImageDispatchResults) GetRetinaHash() *ImageDispatchResults_RetinaHashResults {
+// Deprecated: Use RetinaChecksums.ProtoReflect.Descriptor instead.
+func (*RetinaChecksums) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RetinaChecksums) GetMd5() string {
 	if x != nil {
-		return x.RetinaHash
+		return x.Md5
 	}
-	return nil
+	return ""
 }
 
-func (x *ImageDispatchResults) GetNcii() *ImageDispatchResults_NciiResults {
+func (x *RetinaChecksums) GetSha1() string {
 	if x != nil {
-		return x.Ncii
+		return x.Sha1
 	}
-	return nil
+	return ""
 }
 
-func (x *ImageDispatchResults) GetFlagged() *ImageDispatchResults_FlaggedResults {
+func (x *RetinaChecksums) GetSha256() string {
 	if x != nil {
-		return x.Flagged
+		return x.Sha256
 	}
-	return nil
+	return ""
 }
 
 type ImageDispatchResults_AbyssResults struct {
@@ -1831,7 +3910,7 @@ type ImageDispatchResults_AbyssResults struct {
 
 func (x *ImageDispatchResults_AbyssResults) Reset() {
 	*x = ImageDispatchResults_AbyssResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[19]
+	mi := &file_osprey_atproto_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1843,7 +3922,7 @@ func (x *ImageDispatchResults_AbyssResults) String() string {
 func (*ImageDispatchResults_AbyssResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_AbyssResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[19]
+	mi := &file_osprey_atproto_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1856,7 +3935,7 @@ func (x *ImageDispatchResults_AbyssResults) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ImageDispatchResults_AbyssResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_AbyssResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 0}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 0}
 }
 
 func (x *ImageDispatchResults_AbyssResults) GetRaw() []byte {
@@ -1885,13 +3964,14 @@ type ImageDispatchResults_HiveResults struct {
 	Raw           []byte                 `protobuf:"bytes,1,opt,name=raw,proto3,oneof" json:"raw,omitempty"`
 	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
 	Classes       map[string]float64     `protobuf:"bytes,3,rep,name=classes,proto3" json:"classes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // map of class name to confidence score
+	BudgetSkipped *bool                  `protobuf:"varint,4,opt,name=budget_skipped,json=budgetSkipped,proto3,oneof" json:"budget_skipped,omitempty"`                                     // not sent to Hive because the daily budget was exhausted
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ImageDispatchResults_HiveResults) Reset() {
 	*x = ImageDispatchResults_HiveResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[20]
+	mi := &file_osprey_atproto_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1903,7 +3983,7 @@ func (x *ImageDispatchResults_HiveResults) String() string {
 func (*ImageDispatchResults_HiveResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_HiveResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[20]
+	mi := &file_osprey_atproto_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1916,7 +3996,7 @@ func (x *ImageDispatchResults_HiveResults) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageDispatchResults_HiveResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_HiveResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 1}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 1}
 }
 
 func (x *ImageDispatchResults_HiveResults) GetRaw() []byte {
@@ -1940,6 +4020,13 @@ func (x *ImageDispatchResults_HiveResults) GetClasses() map[string]float64 {
 	return nil
 }
 
+func (x *ImageDispatchResults_HiveResults) GetBudgetSkipped() bool {
+	if x != nil && x.BudgetSkipped != nil {
+		return *x.BudgetSkipped
+	}
+	return false
+}
+
 type ImageDispatchResults_RetinaResults struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Raw           []byte                 `protobuf:"bytes,1,opt,name=raw,proto3,oneof" json:"raw,omitempty"`
@@ -1951,7 +4038,7 @@ type ImageDispatchResults_RetinaResults struct {
 
 func (x *ImageDispatchResults_RetinaResults) Reset() {
 	*x = ImageDispatchResults_RetinaResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[21]
+	mi := &file_osprey_atproto_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1963,7 +4050,7 @@ func (x *ImageDispatchResults_RetinaResults) String() string {
 func (*ImageDispatchResults_RetinaResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_RetinaResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[21]
+	mi := &file_osprey_atproto_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1976,7 +4063,7 @@ func (x *ImageDispatchResults_RetinaResults) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ImageDispatchResults_RetinaResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_RetinaResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 2}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 2}
 }
 
 func (x *ImageDispatchResults_RetinaResults) GetRaw() []byte {
@@ -2012,7 +4099,7 @@ type ImageDispatchResults_RetinaHashResults struct {
 
 func (x *ImageDispatchResults_RetinaHashResults) Reset() {
 	*x = ImageDispatchResults_RetinaHashResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[22]
+	mi := &file_osprey_atproto_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2024,7 +4111,7 @@ func (x *ImageDispatchResults_RetinaHashResults) String() string {
 func (*ImageDispatchResults_RetinaHashResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_RetinaHashResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[22]
+	mi := &file_osprey_atproto_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2037,7 +4124,7 @@ func (x *ImageDispatchResults_RetinaHashResults) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use ImageDispatchResults_RetinaHashResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_RetinaHashResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 3}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 3}
 }
 
 func (x *ImageDispatchResults_RetinaHashResults) GetRaw() []byte {
@@ -2072,14 +4159,15 @@ type ImageDispatchResults_PrescreenResults struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Raw           []byte                 `protobuf:"bytes,1,opt,name=raw,proto3,oneof" json:"raw,omitempty"`
 	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
-	Decision      *string                `protobuf:"bytes,3,opt,name=decision,proto3,oneof" json:"decision,omitempty"` // "nsfw", "sfw"
+	Decision      *string                `protobuf:"bytes,3,opt,name=decision,proto3,oneof" json:"decision,omitempty"`                     // "nsfw", "sfw"
+	QaSampled     *bool                  `protobuf:"varint,4,opt,name=qa_sampled,json=qaSampled,proto3,oneof" json:"qa_sampled,omitempty"` // "sfw" image that was sent to Hive anyway for QA sampling
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ImageDispatchResults_PrescreenResults) Reset() {
 	*x = ImageDispatchResults_PrescreenResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[23]
+	mi := &file_osprey_atproto_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2091,7 +4179,7 @@ func (x *ImageDispatchResults_PrescreenResults) String() string {
 func (*ImageDispatchResults_PrescreenResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_PrescreenResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[23]
+	mi := &file_osprey_atproto_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2104,7 +4192,7 @@ func (x *ImageDispatchResults_PrescreenResults) ProtoReflect() protoreflect.Mess
 
 // Deprecated: Use ImageDispatchResults_PrescreenResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_PrescreenResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 4}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 4}
 }
 
 func (x *ImageDispatchResults_PrescreenResults) GetRaw() []byte {
@@ -2128,6 +4216,13 @@ func (x *ImageDispatchResults_PrescreenResults) GetDecision() string {
 	return ""
 }
 
+func (x *ImageDispatchResults_PrescreenResults) GetQaSampled() bool {
+	if x != nil && x.QaSampled != nil {
+		return *x.QaSampled
+	}
+	return false
+}
+
 type ImageDispatchResults_NciiResults struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Raw           []byte                 `protobuf:"bytes,1,opt,name=raw,proto3,oneof" json:"raw,omitempty"`
@@ -2140,7 +4235,7 @@ type ImageDispatchResults_NciiResults struct {
 
 func (x *ImageDispatchResults_NciiResults) Reset() {
 	*x = ImageDispatchResults_NciiResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[24]
+	mi := &file_osprey_atproto_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2152,7 +4247,7 @@ func (x *ImageDispatchResults_NciiResults) String() string {
 func (*ImageDispatchResults_NciiResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_NciiResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[24]
+	mi := &file_osprey_atproto_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2165,7 +4260,7 @@ func (x *ImageDispatchResults_NciiResults) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageDispatchResults_NciiResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_NciiResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 5}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 5}
 }
 
 func (x *ImageDispatchResults_NciiResults) GetRaw() []byte {
@@ -2212,7 +4307,7 @@ type ImageDispatchResults_FlaggedResults struct {
 
 func (x *ImageDispatchResults_FlaggedResults) Reset() {
 	*x = ImageDispatchResults_FlaggedResults{}
-	mi := &file_osprey_atproto_proto_msgTypes[25]
+	mi := &file_osprey_atproto_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2224,7 +4319,7 @@ func (x *ImageDispatchResults_FlaggedResults) String() string {
 func (*ImageDispatchResults_FlaggedResults) ProtoMessage() {}
 
 func (x *ImageDispatchResults_FlaggedResults) ProtoReflect() protoreflect.Message {
-	mi := &file_osprey_atproto_proto_msgTypes[25]
+	mi := &file_osprey_atproto_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2237,7 +4332,7 @@ func (x *ImageDispatchResults_FlaggedResults) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use ImageDispatchResults_FlaggedResults.ProtoReflect.Descriptor instead.
 func (*ImageDispatchResults_FlaggedResults) Descriptor() ([]byte, []int) {
-	return file_osprey_atproto_proto_rawDescGZIP(), []int{16, 6}
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 6}
 }
 
 func (x *ImageDispatchResults_FlaggedResults) GetError() string {
@@ -2296,6 +4391,191 @@ func (x *ImageDispatchResults_FlaggedResults) GetScore() float64 {
 	return 0
 }
 
+// Set for animated images. Each sampled frame is processed separately and the results of the
+// worst-scoring frame are reported.
+type ImageDispatchResults_AnimationResults struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FrameCount    int32                  `protobuf:"varint,1,opt,name=frame_count,json=frameCount,proto3" json:"frame_count,omitempty"`
+	SampledFrames int32                  `protobuf:"varint,2,opt,name=sampled_frames,json=sampledFrames,proto3" json:"sampled_frames,omitempty"`
+	WorstFrame    int32                  `protobuf:"varint,3,opt,name=worst_frame,json=worstFrame,proto3" json:"worst_frame,omitempty"` // index into the sampled frames
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDispatchResults_AnimationResults) Reset() {
+	*x = ImageDispatchResults_AnimationResults{}
+	mi := &file_osprey_atproto_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDispatchResults_AnimationResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDispatchResults_AnimationResults) ProtoMessage() {}
+
+func (x *ImageDispatchResults_AnimationResults) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDispatchResults_AnimationResults.ProtoReflect.Descriptor instead.
+func (*ImageDispatchResults_AnimationResults) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 7}
+}
+
+func (x *ImageDispatchResults_AnimationResults) GetFrameCount() int32 {
+	if x != nil {
+		return x.FrameCount
+	}
+	return 0
+}
+
+func (x *ImageDispatchResults_AnimationResults) GetSampledFrames() int32 {
+	if x != nil {
+		return x.SampledFrames
+	}
+	return 0
+}
+
+func (x *ImageDispatchResults_AnimationResults) GetWorstFrame() int32 {
+	if x != nil {
+		return x.WorstFrame
+	}
+	return 0
+}
+
+// How often the image's PDQ hash has been seen recently, including this time. Only set for newly
+// created records when the campaign index is enabled.
+type ImageDispatchResults_Sightings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	DistinctDids  int64                  `protobuf:"varint,2,opt,name=distinct_dids,json=distinctDids,proto3" json:"distinct_dids,omitempty"`
+	FirstSeen     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`              // first sighting of the hash that's still being tracked
+	WindowSeconds int64                  `protobuf:"varint,4,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // how far back count and distinct_dids go
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDispatchResults_Sightings) Reset() {
+	*x = ImageDispatchResults_Sightings{}
+	mi := &file_osprey_atproto_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDispatchResults_Sightings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDispatchResults_Sightings) ProtoMessage() {}
+
+func (x *ImageDispatchResults_Sightings) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDispatchResults_Sightings.ProtoReflect.Descriptor instead.
+func (*ImageDispatchResults_Sightings) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 8}
+}
+
+func (x *ImageDispatchResults_Sightings) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ImageDispatchResults_Sightings) GetDistinctDids() int64 {
+	if x != nil {
+		return x.DistinctDids
+	}
+	return 0
+}
+
+func (x *ImageDispatchResults_Sightings) GetFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return nil
+}
+
+func (x *ImageDispatchResults_Sightings) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// Set when the image is the thumbnail of a post's external link card.
+type ImageDispatchResults_LinkCard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uri           string                 `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageDispatchResults_LinkCard) Reset() {
+	*x = ImageDispatchResults_LinkCard{}
+	mi := &file_osprey_atproto_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageDispatchResults_LinkCard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageDispatchResults_LinkCard) ProtoMessage() {}
+
+func (x *ImageDispatchResults_LinkCard) ProtoReflect() protoreflect.Message {
+	mi := &file_osprey_atproto_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageDispatchResults_LinkCard.ProtoReflect.Descriptor instead.
+func (*ImageDispatchResults_LinkCard) Descriptor() ([]byte, []int) {
+	return file_osprey_atproto_proto_rawDescGZIP(), []int{34, 9}
+}
+
+func (x *ImageDispatchResults_LinkCard) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *ImageDispatchResults_LinkCard) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
 var File_osprey_atproto_proto protoreflect.FileDescriptor
 
 const file_osprey_atproto_proto_rawDesc = "" +
@@ -2326,39 +4606,76 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\x13expiration_in_hours\x18\x06 \x01(\x03H\x01R\x11expirationInHours\x88\x01\x01\x12\x14\n" +
 	"\x05rules\x18\a \x03(\tR\x05rulesB\b\n" +
 	"\x06_emailB\x16\n" +
-	"\x14_expiration_in_hours\"\xe0\x01\n" +
+	"\x14_expiration_in_hours\"\xad\x02\n" +
 	"\x10AtprotoTagEffect\x12:\n" +
 	"\veffect_kind\x18\x01 \x01(\x0e2\x19.osprey.AtprotoEffectKindR\n" +
 	"effectKind\x12=\n" +
 	"\fsubject_kind\x18\x02 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x10\n" +
 	"\x03tag\x18\x03 \x01(\tR\x03tag\x12\x1d\n" +
 	"\acomment\x18\x04 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
-	"\x05rules\x18\x05 \x03(\tR\x05rulesB\n" +
+	"\x05rules\x18\x05 \x03(\tR\x05rules\x123\n" +
+	"\x13expiration_in_hours\x18\x06 \x01(\x03H\x01R\x11expirationInHours\x88\x01\x01B\n" +
 	"\n" +
-	"\b_comment\"\xfd\x01\n" +
+	"\b_commentB\x16\n" +
+	"\x14_expiration_in_hours\"\xe0\x02\n" +
 	"\x15AtprotoTakedownEffect\x12:\n" +
 	"\veffect_kind\x18\x01 \x01(\x0e2\x19.osprey.AtprotoEffectKindR\n" +
 	"effectKind\x12=\n" +
 	"\fsubject_kind\x18\x02 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x18\n" +
 	"\acomment\x18\x04 \x01(\tR\acomment\x12/\n" +
 	"\x05email\x18\x05 \x01(\x0e2\x14.osprey.AtprotoEmailH\x00R\x05email\x88\x01\x01\x12\x14\n" +
-	"\x05rules\x18\x06 \x03(\tR\x05rulesB\b\n" +
-	"\x06_email\"\x81\x01\n" +
+	"\x05rules\x18\x06 \x03(\tR\x05rules\x12\x1a\n" +
+	"\bpolicies\x18\a \x03(\tR\bpolicies\x12/\n" +
+	"\x11duration_in_hours\x18\b \x01(\x03H\x01R\x0fdurationInHours\x88\x01\x01B\b\n" +
+	"\x06_emailB\x14\n" +
+	"\x12_duration_in_hours\"\x81\x01\n" +
 	"\x12AtprotoEmailEffect\x12*\n" +
 	"\x05email\x18\x01 \x01(\x0e2\x14.osprey.AtprotoEmailR\x05email\x12\x1d\n" +
 	"\acomment\x18\x02 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
 	"\x05rules\x18\x03 \x03(\tR\x05rulesB\n" +
 	"\n" +
+	"\b_comment\"\xfb\x01\n" +
+	"\x11AtprotoMuteEffect\x12:\n" +
+	"\veffect_kind\x18\x01 \x01(\x0e2\x19.osprey.AtprotoEffectKindR\n" +
+	"effectKind\x12=\n" +
+	"\fsubject_kind\x18\x02 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01\x12*\n" +
+	"\x11duration_in_hours\x18\x04 \x01(\x03R\x0fdurationInHours\x12\x14\n" +
+	"\x05rules\x18\x05 \x03(\tR\x05rulesB\n" +
+	"\n" +
+	"\b_comment\"\xb2\x01\n" +
+	"\x13AtprotoDivertEffect\x12=\n" +
+	"\fsubject_kind\x18\x01 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x1b\n" +
+	"\tblob_cids\x18\x02 \x03(\tR\bblobCids\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
+	"\x05rules\x18\x04 \x03(\tR\x05rulesB\n" +
+	"\n" +
 	"\b_comment\"\x85\x01\n" +
 	"\x14AtprotoCommentEffect\x12=\n" +
 	"\fsubject_kind\x18\x01 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x18\n" +
 	"\acomment\x18\x02 \x01(\tR\acomment\x12\x14\n" +
-	"\x05rules\x18\x03 \x03(\tR\x05rules\"\x97\x01\n" +
+	"\x05rules\x18\x03 \x03(\tR\x05rules\"\xbc\x01\n" +
 	"\x15AtprotoEscalateEffect\x12=\n" +
 	"\fsubject_kind\x18\x01 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x1d\n" +
 	"\acomment\x18\x02 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
+	"\x05rules\x18\x03 \x03(\tR\x05rules\x12\x19\n" +
+	"\x05queue\x18\x04 \x01(\tH\x01R\x05queue\x88\x01\x01B\n" +
+	"\n" +
+	"\b_commentB\b\n" +
+	"\x06_queue\"\x9c\x01\n" +
+	"\x1aAtprotoResolveAppealEffect\x12=\n" +
+	"\fsubject_kind\x18\x01 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x1d\n" +
+	"\acomment\x18\x02 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
 	"\x05rules\x18\x03 \x03(\tR\x05rulesB\n" +
 	"\n" +
+	"\b_comment\"\xa1\x01\n" +
+	"\x10AtprotoSetEffect\x12:\n" +
+	"\veffect_kind\x18\x01 \x01(\x0e2\x19.osprey.AtprotoEffectKindR\n" +
+	"effectKind\x12\x10\n" +
+	"\x03set\x18\x02 \x01(\tR\x03set\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
+	"\x05rules\x18\x04 \x03(\tR\x05rulesB\n" +
+	"\n" +
 	"\b_comment\"\x9a\x01\n" +
 	"\x18AtprotoAcknowledgeEffect\x12=\n" +
 	"\fsubject_kind\x18\x01 \x01(\x0e2\x1a.osprey.AtprotoSubjectKindR\vsubjectKind\x12\x1d\n" +
@@ -2380,7 +4697,7 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01\x12\x14\n" +
 	"\x05rules\x18\x04 \x03(\tR\x05rulesB\n" +
 	"\n" +
-	"\b_comment\"\xe3\x05\n" +
+	"\b_comment\"\xff\b\n" +
 	"\vResultEvent\x127\n" +
 	"\tsend_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\bsendTime\x12\x1f\n" +
 	"\vaction_name\x18\x02 \x01(\tR\n" +
@@ -2399,7 +4716,52 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\vescalations\x18\r \x03(\v2\x1d.osprey.AtprotoEscalateEffectR\vescalations\x12L\n" +
 	"\x10acknowledgements\x18\x0e \x03(\v2 .osprey.AtprotoAcknowledgeEffectR\x10acknowledgements\x125\n" +
 	"\areports\x18\x0f \x03(\v2\x1b.osprey.AtprotoReportEffectR\areports\x12@\n" +
-	"\rbigqueryFlags\x18\x10 \x03(\v2\x1a.osprey.BigQueryFlagEffectR\rbigqueryFlags\"\xe0\x01\n" +
+	"\rbigqueryFlags\x18\x10 \x03(\v2\x1a.osprey.BigQueryFlagEffectR\rbigqueryFlags\x12/\n" +
+	"\x05mutes\x18\x11 \x03(\v2\x19.osprey.AtprotoMuteEffectR\x05mutes\x125\n" +
+	"\adiverts\x18\x12 \x03(\v2\x1b.osprey.AtprotoDivertEffectR\adiverts\x12K\n" +
+	"\x0fresolve_appeals\x18\x13 \x03(\v2\".osprey.AtprotoResolveAppealEffectR\x0eresolveAppeals\x12,\n" +
+	"\x04sets\x18\x14 \x03(\v2\x18.osprey.AtprotoSetEffectR\x04sets\x120\n" +
+	"\adelayed\x18\x15 \x03(\v2\x16.osprey.DelayedEffectsR\adelayed\x12%\n" +
+	"\x0ecancel_delayed\x18\x16 \x03(\tR\rcancelDelayed\x120\n" +
+	"\amessage\x18\x17 \x01(\v2\x16.osprey.ChatMessageRefR\amessage\x12,\n" +
+	"\bevidence\x18\x18 \x03(\v2\x10.osprey.EvidenceR\bevidence\"\x83\x01\n" +
+	"\bEvidence\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x19\n" +
+	"\x05score\x18\x04 \x01(\x01H\x00R\x05score\x88\x01\x01\x12\x14\n" +
+	"\x05rules\x18\x05 \x03(\tR\x05rulesB\b\n" +
+	"\x06_score\"\\\n" +
+	"\x0eChatMessageRef\x12\x10\n" +
+	"\x03did\x18\x01 \x01(\tR\x03did\x12\x19\n" +
+	"\bconvo_id\x18\x02 \x01(\tR\aconvoId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\"\x81\x01\n" +
+	"\x0eDelayedEffects\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.osprey.ResultEventR\x05event\x122\n" +
+	"\x15execute_after_seconds\x18\x02 \x01(\x03R\x13executeAfterSeconds\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\"\xb9\x01\n" +
+	"\rFailedEffects\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.osprey.ResultEventR\x05event\x12\x1a\n" +
+	"\battempts\x18\x02 \x01(\x05R\battempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\"\xe1\x02\n" +
+	"\rEffectOutcome\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1f\n" +
+	"\vaction_name\x18\x02 \x01(\tR\n" +
+	"actionName\x12\x1b\n" +
+	"\taction_id\x18\x03 \x01(\x03R\bactionId\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12\x14\n" +
+	"\x05rules\x18\x06 \x03(\tR\x05rules\x12\x18\n" +
+	"\asuccess\x18\a \x01(\bR\asuccess\x12\x19\n" +
+	"\x05error\x18\b \x01(\tH\x00R\x05error\x88\x01\x01\x12)\n" +
+	"\x0eozone_event_id\x18\t \x01(\x03H\x01R\fozoneEventId\x88\x01\x01\x12\x17\n" +
+	"\adry_run\x18\n" +
+	" \x01(\bR\x06dryRunB\b\n" +
+	"\x06_errorB\x11\n" +
+	"\x0f_ozone_event_id\"\xe0\x01\n" +
 	"\rFirehoseEvent\x12\x10\n" +
 	"\x03did\x18\x01 \x01(\tR\x03did\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12%\n" +
@@ -2418,7 +4780,8 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\x03cid\x18\x06 \x01(\tR\x03cid\"H\n" +
 	"\x06Cursor\x12\x1a\n" +
 	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\"\n" +
-	"\rsaved_on_exit\x18\x02 \x01(\bR\vsavedOnExit\"\xc0\x05\n" +
+	"\rsaved_on_exit\x18\x02 \x01(\bR\vsavedOnExit\"\xef\n" +
+	"\n" +
 	"%ModerationEnrichedFirehoseRecordEvent\x12\x10\n" +
 	"\x03did\x18\x01 \x01(\tR\x03did\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1e\n" +
@@ -2434,7 +4797,19 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\fprofile_view\x18\n" +
 	" \x01(\fH\x02R\vprofileView\x88\x01\x01\x12'\n" +
 	"\rdid_audit_log\x18\v \x01(\fH\x03R\vdidAuditLog\x88\x01\x01\x12\x10\n" +
-	"\x03cid\x18\f \x01(\tR\x03cid\x1a]\n" +
+	"\x03cid\x18\f \x01(\tR\x03cid\x129\n" +
+	"\bvelocity\x18\r \x01(\v2\x18.osprey.VelocityFeaturesH\x04R\bvelocity\x88\x01\x01\x12A\n" +
+	"\x11term_list_matches\x18\x0e \x03(\v2\x15.osprey.TermListMatchR\x0ftermListMatches\x12O\n" +
+	"\x15impersonation_matches\x18\x0f \x03(\v2\x1a.osprey.ImpersonationMatchR\x14impersonationMatches\x129\n" +
+	"\bidentity\x18\x10 \x01(\v2\x18.osprey.IdentityFeaturesH\x05R\bidentity\x88\x01\x01\x12*\n" +
+	"\x03pds\x18\x11 \x01(\v2\x13.osprey.PdsFeaturesH\x06R\x03pds\x88\x01\x01\x12M\n" +
+	"\x12collection_context\x18\x12 \x01(\v2\x19.osprey.CollectionContextH\aR\x11collectionContext\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"watchlists\x18\x13 \x03(\tR\n" +
+	"watchlists\x12>\n" +
+	"\x0fexisting_labels\x18\x14 \x03(\v2\x15.osprey.ExistingLabelR\x0eexistingLabels\x12J\n" +
+	"\x14blob_type_mismatches\x18\x15 \x03(\v2\x18.osprey.BlobTypeMismatchR\x12blobTypeMismatches\x126\n" +
+	"\bpipeline\x18\x16 \x01(\v2\x15.osprey.PipelineTimesH\bR\bpipeline\x88\x01\x01\x1a]\n" +
 	"\x11ImageResultsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
 	"\x05value\x18\x02 \x01(\v2\x1c.osprey.ImageDispatchResultsR\x05value:\x028\x01B\x19\n" +
@@ -2442,7 +4817,67 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\n" +
 	"\b_did_docB\x0f\n" +
 	"\r_profile_viewB\x10\n" +
-	"\x0e_did_audit_log\"\xee\x0f\n" +
+	"\x0e_did_audit_logB\v\n" +
+	"\t_velocityB\v\n" +
+	"\t_identityB\x06\n" +
+	"\x04_pdsB\x15\n" +
+	"\x13_collection_contextB\v\n" +
+	"\t_pipeline\"\x93\x02\n" +
+	"\x10VelocityFeatures\x12*\n" +
+	"\x11posts_last_minute\x18\x01 \x01(\x03R\x0fpostsLastMinute\x12&\n" +
+	"\x0fposts_last_hour\x18\x02 \x01(\x03R\rpostsLastHour\x12(\n" +
+	"\x10images_last_hour\x18\x03 \x01(\x03R\x0eimagesLastHour\x12=\n" +
+	"\x1bdistinct_mentions_last_hour\x18\x04 \x01(\x03R\x18distinctMentionsLastHour\x12B\n" +
+	"\x1eidentical_text_posts_last_hour\x18\x05 \x01(\x03R\x1aidenticalTextPostsLastHour\"s\n" +
+	"\rTermListMatch\x12\x12\n" +
+	"\x04list\x18\x01 \x01(\tR\x04list\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1a\n" +
+	"\bseverity\x18\x03 \x01(\tR\bseverity\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\"\x90\x01\n" +
+	"\x12ImpersonationMatch\x12#\n" +
+	"\rprotected_did\x18\x01 \x01(\tR\fprotectedDid\x12)\n" +
+	"\x10protected_handle\x18\x02 \x01(\tR\x0fprotectedHandle\x12\x14\n" +
+	"\x05field\x18\x03 \x01(\tR\x05field\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"\xc2\x02\n" +
+	"\x10IdentityFeatures\x12H\n" +
+	"\x12account_created_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x10accountCreatedAt\x12.\n" +
+	"\x13account_age_seconds\x18\x02 \x01(\x03R\x11accountAgeSeconds\x12'\n" +
+	"\x0foperation_count\x18\x03 \x01(\x03R\x0eoperationCount\x122\n" +
+	"\x15recent_handle_changes\x18\x04 \x01(\x03R\x13recentHandleChanges\x12%\n" +
+	"\x0epds_migrations\x18\x05 \x01(\x03R\rpdsMigrations\x120\n" +
+	"\x14rotation_key_changes\x18\x06 \x01(\x03R\x12rotationKeyChanges\"\xdf\x01\n" +
+	"\vPdsFeatures\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12%\n" +
+	"\x0ebluesky_hosted\x18\x02 \x01(\bR\rblueskyHosted\x12B\n" +
+	"\x0fhost_first_seen\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\rhostFirstSeen\x12(\n" +
+	"\x10host_age_seconds\x18\x04 \x01(\x03R\x0ehostAgeSeconds\x12'\n" +
+	"\x0fsuspicious_host\x18\x05 \x01(\bR\x0esuspiciousHost\"\x9d\x02\n" +
+	"\x11CollectionContext\x12.\n" +
+	"\x10service_endpoint\x18\x01 \x01(\tH\x00R\x0fserviceEndpoint\x88\x01\x01\x12$\n" +
+	"\vservice_did\x18\x02 \x01(\tH\x01R\n" +
+	"serviceDid\x88\x01\x01\x12\x1e\n" +
+	"\blist_uri\x18\x03 \x01(\tH\x02R\alistUri\x88\x01\x01\x12+\n" +
+	"\x0flist_item_count\x18\x04 \x01(\x03H\x03R\rlistItemCount\x88\x01\x01\x12\x1f\n" +
+	"\vavatar_cids\x18\x05 \x03(\tR\n" +
+	"avatarCidsB\x13\n" +
+	"\x11_service_endpointB\x0e\n" +
+	"\f_service_didB\v\n" +
+	"\t_list_uriB\x12\n" +
+	"\x10_list_item_count\"n\n" +
+	"\rExistingLabel\x12\x10\n" +
+	"\x03uri\x18\x01 \x01(\tR\x03uri\x12\x10\n" +
+	"\x03val\x18\x02 \x01(\tR\x03val\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x99\x02\n" +
+	"\rPipelineTimes\x12C\n" +
+	"\x0fevent_timestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x0eeventTimestamp\x12N\n" +
+	"\x15enrichment_started_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x13enrichmentStartedAt\x12P\n" +
+	"\x16enrichment_finished_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x14enrichmentFinishedAt\x12!\n" +
+	"\fstaleness_ms\x18\x04 \x01(\x03R\vstalenessMs\"~\n" +
+	"\x10BlobTypeMismatch\x12\x10\n" +
+	"\x03cid\x18\x01 \x01(\tR\x03cid\x12,\n" +
+	"\x12declared_mime_type\x18\x02 \x01(\tR\x10declaredMimeType\x12*\n" +
+	"\x11sniffed_mime_type\x18\x03 \x01(\tR\x0fsniffedMimeType\"\xcc\x15\n" +
 	"\x14ImageDispatchResults\x12\x10\n" +
 	"\x03cid\x18\x01 \x01(\tR\x03cid\x12D\n" +
 	"\x05abyss\x18\x02 \x01(\v2).osprey.ImageDispatchResults.AbyssResultsH\x00R\x05abyss\x88\x01\x01\x12A\n" +
@@ -2452,23 +4887,29 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\vretina_hash\x18\a \x01(\v2..osprey.ImageDispatchResults.RetinaHashResultsH\x04R\n" +
 	"retinaHash\x88\x01\x01\x12A\n" +
 	"\x04ncii\x18\b \x01(\v2(.osprey.ImageDispatchResults.NciiResultsH\x05R\x04ncii\x88\x01\x01\x12J\n" +
-	"\aflagged\x18\t \x01(\v2+.osprey.ImageDispatchResults.FlaggedResultsH\x06R\aflagged\x88\x01\x01\x1a\x90\x01\n" +
+	"\aflagged\x18\t \x01(\v2+.osprey.ImageDispatchResults.FlaggedResultsH\x06R\aflagged\x88\x01\x01\x12P\n" +
+	"\tanimation\x18\n" +
+	" \x01(\v2-.osprey.ImageDispatchResults.AnimationResultsH\aR\tanimation\x88\x01\x01\x12I\n" +
+	"\tsightings\x18\v \x01(\v2&.osprey.ImageDispatchResults.SightingsH\bR\tsightings\x88\x01\x01\x12G\n" +
+	"\tlink_card\x18\f \x01(\v2%.osprey.ImageDispatchResults.LinkCardH\tR\blinkCard\x88\x01\x01\x1a\x90\x01\n" +
 	"\fAbyssResults\x12\x15\n" +
 	"\x03raw\x18\x01 \x01(\fH\x00R\x03raw\x88\x01\x01\x12\x19\n" +
 	"\x05error\x18\x02 \x01(\tH\x01R\x05error\x88\x01\x01\x12)\n" +
 	"\x0eis_abuse_match\x18\x03 \x01(\bH\x02R\fisAbuseMatch\x88\x01\x01B\x06\n" +
 	"\x04_rawB\b\n" +
 	"\x06_errorB\x11\n" +
-	"\x0f_is_abuse_match\x1a\xde\x01\n" +
+	"\x0f_is_abuse_match\x1a\x9d\x02\n" +
 	"\vHiveResults\x12\x15\n" +
 	"\x03raw\x18\x01 \x01(\fH\x00R\x03raw\x88\x01\x01\x12\x19\n" +
 	"\x05error\x18\x02 \x01(\tH\x01R\x05error\x88\x01\x01\x12O\n" +
-	"\aclasses\x18\x03 \x03(\v25.osprey.ImageDispatchResults.HiveResults.ClassesEntryR\aclasses\x1a:\n" +
+	"\aclasses\x18\x03 \x03(\v25.osprey.ImageDispatchResults.HiveResults.ClassesEntryR\aclasses\x12*\n" +
+	"\x0ebudget_skipped\x18\x04 \x01(\bH\x02R\rbudgetSkipped\x88\x01\x01\x1a:\n" +
 	"\fClassesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01B\x06\n" +
 	"\x04_rawB\b\n" +
-	"\x06_error\x1au\n" +
+	"\x06_errorB\x11\n" +
+	"\x0f_budget_skipped\x1au\n" +
 	"\rRetinaResults\x12\x15\n" +
 	"\x03raw\x18\x01 \x01(\fH\x00R\x03raw\x88\x01\x01\x12\x19\n" +
 	"\x05error\x18\x02 \x01(\tH\x01R\x05error\x88\x01\x01\x12\x17\n" +
@@ -2484,14 +4925,17 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\x04_rawB\b\n" +
 	"\x06_errorB\a\n" +
 	"\x05_hashB\x12\n" +
-	"\x10_quality_too_low\x1a\x84\x01\n" +
+	"\x10_quality_too_low\x1a\xb7\x01\n" +
 	"\x10PrescreenResults\x12\x15\n" +
 	"\x03raw\x18\x01 \x01(\fH\x00R\x03raw\x88\x01\x01\x12\x19\n" +
 	"\x05error\x18\x02 \x01(\tH\x01R\x05error\x88\x01\x01\x12\x1f\n" +
-	"\bdecision\x18\x03 \x01(\tH\x02R\bdecision\x88\x01\x01B\x06\n" +
+	"\bdecision\x18\x03 \x01(\tH\x02R\bdecision\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"qa_sampled\x18\x04 \x01(\bH\x03R\tqaSampled\x88\x01\x01B\x06\n" +
 	"\x04_rawB\b\n" +
 	"\x06_errorB\v\n" +
-	"\t_decision\x1a\xa3\x01\n" +
+	"\t_decisionB\r\n" +
+	"\v_qa_sampled\x1a\xa3\x01\n" +
 	"\vNciiResults\x12\x15\n" +
 	"\x03raw\x18\x01 \x01(\fH\x00R\x03raw\x88\x01\x01\x12\x19\n" +
 	"\x05error\x18\x02 \x01(\tH\x01R\x05error\x88\x01\x01\x12\x1e\n" +
@@ -2517,7 +4961,22 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\r_action_valueB\x10\n" +
 	"\x0e_always_reportB\x0e\n" +
 	"\f_descriptionB\b\n" +
-	"\x06_scoreB\b\n" +
+	"\x06_score\x1a{\n" +
+	"\x10AnimationResults\x12\x1f\n" +
+	"\vframe_count\x18\x01 \x01(\x05R\n" +
+	"frameCount\x12%\n" +
+	"\x0esampled_frames\x18\x02 \x01(\x05R\rsampledFrames\x12\x1f\n" +
+	"\vworst_frame\x18\x03 \x01(\x05R\n" +
+	"worstFrame\x1a\xa8\x01\n" +
+	"\tSightings\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12#\n" +
+	"\rdistinct_dids\x18\x02 \x01(\x03R\fdistinctDids\x129\n" +
+	"\n" +
+	"first_seen\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tfirstSeen\x12%\n" +
+	"\x0ewindow_seconds\x18\x04 \x01(\x03R\rwindowSeconds\x1a2\n" +
+	"\bLinkCard\x12\x10\n" +
+	"\x03uri\x18\x01 \x01(\tR\x03uri\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05titleB\b\n" +
 	"\x06_abyssB\a\n" +
 	"\x05_hiveB\t\n" +
 	"\a_retinaB\f\n" +
@@ -2526,11 +4985,78 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\f_retina_hashB\a\n" +
 	"\x05_nciiB\n" +
 	"\n" +
-	"\b_flagged*t\n" +
+	"\b_flaggedB\f\n" +
+	"\n" +
+	"_animationB\f\n" +
+	"\n" +
+	"_sightingsB\f\n" +
+	"\n" +
+	"_link_card\"\xfe\x02\n" +
+	"\x15ModerationReportEvent\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\x03R\breportId\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x1f\n" +
+	"\vreason_type\x18\x03 \x01(\tR\n" +
+	"reasonType\x12\x1b\n" +
+	"\x06reason\x18\x04 \x01(\tH\x00R\x06reason\x88\x01\x01\x12\x1f\n" +
+	"\vsubject_did\x18\x05 \x01(\tR\n" +
+	"subjectDid\x12$\n" +
+	"\vsubject_uri\x18\x06 \x01(\tH\x01R\n" +
+	"subjectUri\x88\x01\x01\x12$\n" +
+	"\vsubject_cid\x18\a \x01(\tH\x02R\n" +
+	"subjectCid\x88\x01\x01\x12\x1f\n" +
+	"\vreported_by\x18\b \x01(\tR\n" +
+	"reportedBy\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\t\n" +
+	"\a_reasonB\x0e\n" +
+	"\f_subject_uriB\x0e\n" +
+	"\f_subject_cid\"\xf6\x02\n" +
+	"\x11LabelExpiredEvent\x12\x1f\n" +
+	"\vsubject_did\x18\x01 \x01(\tR\n" +
+	"subjectDid\x12$\n" +
+	"\vsubject_uri\x18\x02 \x01(\tH\x00R\n" +
+	"subjectUri\x88\x01\x01\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x14\n" +
+	"\x05rules\x18\x04 \x03(\tR\x05rules\x12\x1f\n" +
+	"\vaction_name\x18\x05 \x01(\tR\n" +
+	"actionName\x12\x1b\n" +
+	"\taction_id\x18\x06 \x01(\x03R\bactionId\x12*\n" +
+	"\x11duration_in_hours\x18\a \x01(\x03R\x0fdurationInHours\x129\n" +
+	"\n" +
+	"applied_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tappliedAt\x129\n" +
+	"\n" +
+	"expired_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\texpiredAtB\x0e\n" +
+	"\f_subject_uri\"\x7f\n" +
+	"\x12RetinaImageRequest\x12\x10\n" +
+	"\x03did\x18\x01 \x01(\tR\x03did\x12\x10\n" +
+	"\x03cid\x18\x02 \x01(\tR\x03cid\x12*\n" +
+	"\x03ocr\x18\x03 \x01(\v2\x18.osprey.RetinaOCROptionsR\x03ocr\x12\x19\n" +
+	"\bcdn_host\x18\x04 \x01(\tR\acdnHost\"_\n" +
+	"\x10RetinaImageChunk\x12/\n" +
+	"\x03ocr\x18\x01 \x01(\v2\x18.osprey.RetinaOCROptionsH\x00R\x03ocr\x88\x01\x01\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04dataB\x06\n" +
+	"\x04_ocr\"H\n" +
+	"\x10RetinaOCROptions\x12\x14\n" +
+	"\x05langs\x18\x01 \x01(\tR\x05langs\x12\x1e\n" +
+	"\n" +
+	"preprocess\x18\x02 \x01(\tR\n" +
+	"preprocess\"+\n" +
+	"\x15RetinaAnalyzeResponse\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"\x9f\x01\n" +
+	"\x12RetinaHashResponse\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x16\n" +
+	"\x06binary\x18\x02 \x01(\tR\x06binary\x12&\n" +
+	"\x0fquality_too_low\x18\x03 \x01(\bR\rqualityTooLow\x125\n" +
+	"\tchecksums\x18\x04 \x01(\v2\x17.osprey.RetinaChecksumsR\tchecksums\"O\n" +
+	"\x0fRetinaChecksums\x12\x10\n" +
+	"\x03md5\x18\x01 \x01(\tR\x03md5\x12\x12\n" +
+	"\x04sha1\x18\x02 \x01(\tR\x04sha1\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256*\x96\x01\n" +
 	"\x12AtprotoSubjectKind\x12\x1d\n" +
 	"\x19ATPROTO_SUBJECT_KIND_NONE\x10\x00\x12\x1e\n" +
 	"\x1aATPROTO_SUBJECT_KIND_ACTOR\x10\x01\x12\x1f\n" +
-	"\x1bATPROTO_SUBJECT_KIND_RECORD\x10\x02*\xf6\x01\n" +
+	"\x1bATPROTO_SUBJECT_KIND_RECORD\x10\x02\x12 \n" +
+	"\x1cATPROTO_SUBJECT_KIND_MESSAGE\x10\x03*\xf6\x01\n" +
 	"\fAtprotoLabel\x12\x16\n" +
 	"\x12ATPROTO_LABEL_NONE\x10\x00\x12\x16\n" +
 	"\x12ATPROTO_LABEL_SPAM\x10\x01\x12\x16\n" +
@@ -2578,7 +5104,12 @@ const file_osprey_atproto_proto_rawDesc = "" +
 	"\x1cCOMMIT_OPERATION_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17COMMIT_OPERATION_CREATE\x10\x01\x12\x1b\n" +
 	"\x17COMMIT_OPERATION_UPDATE\x10\x02\x12\x1b\n" +
-	"\x17COMMIT_OPERATION_DELETE\x10\x03Bc\n" +
+	"\x17COMMIT_OPERATION_DELETE\x10\x032\x9c\x02\n" +
+	"\x06Retina\x12D\n" +
+	"\aAnalyze\x12\x1a.osprey.RetinaImageRequest\x1a\x1d.osprey.RetinaAnalyzeResponse\x12H\n" +
+	"\vAnalyzeBlob\x12\x18.osprey.RetinaImageChunk\x1a\x1d.osprey.RetinaAnalyzeResponse(\x01\x12>\n" +
+	"\x04Hash\x12\x1a.osprey.RetinaImageRequest\x1a\x1a.osprey.RetinaHashResponse\x12B\n" +
+	"\bHashBlob\x12\x18.osprey.RetinaImageChunk\x1a\x1a.osprey.RetinaHashResponse(\x01Bc\n" +
 	"\n" +
 	"com.ospreyB\x12OspreyAtprotoProtoP\x01Z\t./;osprey\xa2\x02\x03OXX\xaa\x02\x06Osprey\xca\x02\x06Osprey\xe2\x02\x12Osprey\\GPBMetadata\xea\x02\x06Ospreyb\x06proto3"
 
@@ -2595,7 +5126,7 @@ func file_osprey_atproto_proto_rawDescGZIP() []byte {
 }
 
 var file_osprey_atproto_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
-var file_osprey_atproto_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_osprey_atproto_proto_msgTypes = make([]protoimpl.MessageInfo, 56)
 var file_osprey_atproto_proto_goTypes = []any{
 	(AtprotoSubjectKind)(0),                        // 0: osprey.AtprotoSubjectKind
 	(AtprotoLabel)(0),                              // 1: osprey.AtprotoLabel
@@ -2610,34 +5141,63 @@ var file_osprey_atproto_proto_goTypes = []any{
 	(*AtprotoTagEffect)(nil),                       // 10: osprey.AtprotoTagEffect
 	(*AtprotoTakedownEffect)(nil),                  // 11: osprey.AtprotoTakedownEffect
 	(*AtprotoEmailEffect)(nil),                     // 12: osprey.AtprotoEmailEffect
-	(*AtprotoCommentEffect)(nil),                   // 13: osprey.AtprotoCommentEffect
-	(*AtprotoEscalateEffect)(nil),                  // 14: osprey.AtprotoEscalateEffect
-	(*AtprotoAcknowledgeEffect)(nil),               // 15: osprey.AtprotoAcknowledgeEffect
-	(*AtprotoReportEffect)(nil),                    // 16: osprey.AtprotoReportEffect
-	(*BigQueryFlagEffect)(nil),                     // 17: osprey.BigQueryFlagEffect
-	(*ResultEvent)(nil),                            // 18: osprey.ResultEvent
-	(*FirehoseEvent)(nil),                          // 19: osprey.FirehoseEvent
-	(*Commit)(nil),                                 // 20: osprey.Commit
-	(*Cursor)(nil),                                 // 21: osprey.Cursor
-	(*ModerationEnrichedFirehoseRecordEvent)(nil),  // 22: osprey.ModerationEnrichedFirehoseRecordEvent
-	(*ImageDispatchResults)(nil),                   // 23: osprey.ImageDispatchResults
-	nil,                                            // 24: osprey.OspreyInputEventData.SecretDataEntry
-	nil,                                            // 25: osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntry
-	(*ImageDispatchResults_AbyssResults)(nil),      // 26: osprey.ImageDispatchResults.AbyssResults
-	(*ImageDispatchResults_HiveResults)(nil),       // 27: osprey.ImageDispatchResults.HiveResults
-	(*ImageDispatchResults_RetinaResults)(nil),     // 28: osprey.ImageDispatchResults.RetinaResults
-	(*ImageDispatchResults_RetinaHashResults)(nil), // 29: osprey.ImageDispatchResults.RetinaHashResults
-	(*ImageDispatchResults_PrescreenResults)(nil),  // 30: osprey.ImageDispatchResults.PrescreenResults
-	(*ImageDispatchResults_NciiResults)(nil),       // 31: osprey.ImageDispatchResults.NciiResults
-	(*ImageDispatchResults_FlaggedResults)(nil),    // 32: osprey.ImageDispatchResults.FlaggedResults
-	nil,                           // 33: osprey.ImageDispatchResults.HiveResults.ClassesEntry
-	(*timestamppb.Timestamp)(nil), // 34: google.protobuf.Timestamp
+	(*AtprotoMuteEffect)(nil),                      // 13: osprey.AtprotoMuteEffect
+	(*AtprotoDivertEffect)(nil),                    // 14: osprey.AtprotoDivertEffect
+	(*AtprotoCommentEffect)(nil),                   // 15: osprey.AtprotoCommentEffect
+	(*AtprotoEscalateEffect)(nil),                  // 16: osprey.AtprotoEscalateEffect
+	(*AtprotoResolveAppealEffect)(nil),             // 17: osprey.AtprotoResolveAppealEffect
+	(*AtprotoSetEffect)(nil),                       // 18: osprey.AtprotoSetEffect
+	(*AtprotoAcknowledgeEffect)(nil),               // 19: osprey.AtprotoAcknowledgeEffect
+	(*AtprotoReportEffect)(nil),                    // 20: osprey.AtprotoReportEffect
+	(*BigQueryFlagEffect)(nil),                     // 21: osprey.BigQueryFlagEffect
+	(*ResultEvent)(nil),                            // 22: osprey.ResultEvent
+	(*Evidence)(nil),                               // 23: osprey.Evidence
+	(*ChatMessageRef)(nil),                         // 24: osprey.ChatMessageRef
+	(*DelayedEffects)(nil),                         // 25: osprey.DelayedEffects
+	(*FailedEffects)(nil),                          // 26: osprey.FailedEffects
+	(*EffectOutcome)(nil),                          // 27: osprey.EffectOutcome
+	(*FirehoseEvent)(nil),                          // 28: osprey.FirehoseEvent
+	(*Commit)(nil),                                 // 29: osprey.Commit
+	(*Cursor)(nil),                                 // 30: osprey.Cursor
+	(*ModerationEnrichedFirehoseRecordEvent)(nil),  // 31: osprey.ModerationEnrichedFirehoseRecordEvent
+	(*VelocityFeatures)(nil),                       // 32: osprey.VelocityFeatures
+	(*TermListMatch)(nil),                          // 33: osprey.TermListMatch
+	(*ImpersonationMatch)(nil),                     // 34: osprey.ImpersonationMatch
+	(*IdentityFeatures)(nil),                       // 35: osprey.IdentityFeatures
+	(*PdsFeatures)(nil),                            // 36: osprey.PdsFeatures
+	(*CollectionContext)(nil),                      // 37: osprey.CollectionContext
+	(*ExistingLabel)(nil),                          // 38: osprey.ExistingLabel
+	(*PipelineTimes)(nil),                          // 39: osprey.PipelineTimes
+	(*BlobTypeMismatch)(nil),                       // 40: osprey.BlobTypeMismatch
+	(*ImageDispatchResults)(nil),                   // 41: osprey.ImageDispatchResults
+	(*ModerationReportEvent)(nil),                  // 42: osprey.ModerationReportEvent
+	(*LabelExpiredEvent)(nil),                      // 43: osprey.LabelExpiredEvent
+	(*RetinaImageRequest)(nil),                     // 44: osprey.RetinaImageRequest
+	(*RetinaImageChunk)(nil),                       // 45: osprey.RetinaImageChunk
+	(*RetinaOCROptions)(nil),                       // 46: osprey.RetinaOCROptions
+	(*RetinaAnalyzeResponse)(nil),                  // 47: osprey.RetinaAnalyzeResponse
+	(*RetinaHashResponse)(nil),                     // 48: osprey.RetinaHashResponse
+	(*RetinaChecksums)(nil),                        // 49: osprey.RetinaChecksums
+	nil,                                            // 50: osprey.OspreyInputEventData.SecretDataEntry
+	nil,                                            // 51: osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntry
+	(*ImageDispatchResults_AbyssResults)(nil),      // 52: osprey.ImageDispatchResults.AbyssResults
+	(*ImageDispatchResults_HiveResults)(nil),       // 53: osprey.ImageDispatchResults.HiveResults
+	(*ImageDispatchResults_RetinaResults)(nil),     // 54: osprey.ImageDispatchResults.RetinaResults
+	(*ImageDispatchResults_RetinaHashResults)(nil), // 55: osprey.ImageDispatchResults.RetinaHashResults
+	(*ImageDispatchResults_PrescreenResults)(nil),  // 56: osprey.ImageDispatchResults.PrescreenResults
+	(*ImageDispatchResults_NciiResults)(nil),       // 57: osprey.ImageDispatchResults.NciiResults
+	(*ImageDispatchResults_FlaggedResults)(nil),    // 58: osprey.ImageDispatchResults.FlaggedResults
+	(*ImageDispatchResults_AnimationResults)(nil),  // 59: osprey.ImageDispatchResults.AnimationResults
+	(*ImageDispatchResults_Sightings)(nil),         // 60: osprey.ImageDispatchResults.Sightings
+	(*ImageDispatchResults_LinkCard)(nil),          // 61: osprey.ImageDispatchResults.LinkCard
+	nil,                                            // 62: osprey.ImageDispatchResults.HiveResults.ClassesEntry
+	(*timestamppb.Timestamp)(nil),                  // 63: google.protobuf.Timestamp
 }
 var file_osprey_atproto_proto_depIdxs = []int32{
 	8,  // 0: osprey.OspreyInputEvent.data:type_name -> osprey.OspreyInputEventData
-	34, // 1: osprey.OspreyInputEvent.send_time:type_name -> google.protobuf.Timestamp
-	34, // 2: osprey.OspreyInputEventData.timestamp:type_name -> google.protobuf.Timestamp
-	24, // 3: osprey.OspreyInputEventData.secret_data:type_name -> osprey.OspreyInputEventData.SecretDataEntry
+	63, // 1: osprey.OspreyInputEvent.send_time:type_name -> google.protobuf.Timestamp
+	63, // 2: osprey.OspreyInputEventData.timestamp:type_name -> google.protobuf.Timestamp
+	50, // 3: osprey.OspreyInputEventData.secret_data:type_name -> osprey.OspreyInputEventData.SecretDataEntry
 	2,  // 4: osprey.AtprotoLabelEffect.effect_kind:type_name -> osprey.AtprotoEffectKind
 	0,  // 5: osprey.AtprotoLabelEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
 	1,  // 6: osprey.AtprotoLabelEffect.label:type_name -> osprey.AtprotoLabel
@@ -2648,43 +5208,92 @@ var file_osprey_atproto_proto_depIdxs = []int32{
 	0,  // 11: osprey.AtprotoTakedownEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
 	3,  // 12: osprey.AtprotoTakedownEffect.email:type_name -> osprey.AtprotoEmail
 	3,  // 13: osprey.AtprotoEmailEffect.email:type_name -> osprey.AtprotoEmail
-	0,  // 14: osprey.AtprotoCommentEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
-	0,  // 15: osprey.AtprotoEscalateEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
-	0,  // 16: osprey.AtprotoAcknowledgeEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
-	0,  // 17: osprey.AtprotoReportEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
-	4,  // 18: osprey.AtprotoReportEffect.report_kind:type_name -> osprey.AtprotoReportKind
-	0,  // 19: osprey.BigQueryFlagEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
-	34, // 20: osprey.ResultEvent.send_time:type_name -> google.protobuf.Timestamp
-	9,  // 21: osprey.ResultEvent.labels:type_name -> osprey.AtprotoLabelEffect
-	10, // 22: osprey.ResultEvent.tags:type_name -> osprey.AtprotoTagEffect
-	11, // 23: osprey.ResultEvent.takedowns:type_name -> osprey.AtprotoTakedownEffect
-	12, // 24: osprey.ResultEvent.emails:type_name -> osprey.AtprotoEmailEffect
-	13, // 25: osprey.ResultEvent.comments:type_name -> osprey.AtprotoCommentEffect
-	14, // 26: osprey.ResultEvent.escalations:type_name -> osprey.AtprotoEscalateEffect
-	15, // 27: osprey.ResultEvent.acknowledgements:type_name -> osprey.AtprotoAcknowledgeEffect
-	16, // 28: osprey.ResultEvent.reports:type_name -> osprey.AtprotoReportEffect
-	17, // 29: osprey.ResultEvent.bigqueryFlags:type_name -> osprey.BigQueryFlagEffect
-	34, // 30: osprey.FirehoseEvent.timestamp:type_name -> google.protobuf.Timestamp
-	5,  // 31: osprey.FirehoseEvent.kind:type_name -> osprey.EventKind
-	20, // 32: osprey.FirehoseEvent.commit:type_name -> osprey.Commit
-	6,  // 33: osprey.Commit.operation:type_name -> osprey.CommitOperation
-	34, // 34: osprey.ModerationEnrichedFirehoseRecordEvent.timestamp:type_name -> google.protobuf.Timestamp
-	6,  // 35: osprey.ModerationEnrichedFirehoseRecordEvent.operation:type_name -> osprey.CommitOperation
-	25, // 36: osprey.ModerationEnrichedFirehoseRecordEvent.image_results:type_name -> osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntry
-	26, // 37: osprey.ImageDispatchResults.abyss:type_name -> osprey.ImageDispatchResults.AbyssResults
-	27, // 38: osprey.ImageDispatchResults.hive:type_name -> osprey.ImageDispatchResults.HiveResults
-	28, // 39: osprey.ImageDispatchResults.retina:type_name -> osprey.ImageDispatchResults.RetinaResults
-	30, // 40: osprey.ImageDispatchResults.prescreen:type_name -> osprey.ImageDispatchResults.PrescreenResults
-	29, // 41: osprey.ImageDispatchResults.retina_hash:type_name -> osprey.ImageDispatchResults.RetinaHashResults
-	31, // 42: osprey.ImageDispatchResults.ncii:type_name -> osprey.ImageDispatchResults.NciiResults
-	32, // 43: osprey.ImageDispatchResults.flagged:type_name -> osprey.ImageDispatchResults.FlaggedResults
-	23, // 44: osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntry.value:type_name -> osprey.ImageDispatchResults
-	33, // 45: osprey.ImageDispatchResults.HiveResults.classes:type_name -> osprey.ImageDispatchResults.HiveResults.ClassesEntry
-	46, // [46:46] is the sub-list for method output_type
-	46, // [46:46] is the sub-list for method input_type
-	46, // [46:46] is the sub-list for extension type_name
-	46, // [46:46] is the sub-list for extension extendee
-	0,  // [0:46] is the sub-list for field type_name
+	2,  // 14: osprey.AtprotoMuteEffect.effect_kind:type_name -> osprey.AtprotoEffectKind
+	0,  // 15: osprey.AtprotoMuteEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	0,  // 16: osprey.AtprotoDivertEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	0,  // 17: osprey.AtprotoCommentEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	0,  // 18: osprey.AtprotoEscalateEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	0,  // 19: osprey.AtprotoResolveAppealEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	2,  // 20: osprey.AtprotoSetEffect.effect_kind:type_name -> osprey.AtprotoEffectKind
+	0,  // 21: osprey.AtprotoAcknowledgeEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	0,  // 22: osprey.AtprotoReportEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	4,  // 23: osprey.AtprotoReportEffect.report_kind:type_name -> osprey.AtprotoReportKind
+	0,  // 24: osprey.BigQueryFlagEffect.subject_kind:type_name -> osprey.AtprotoSubjectKind
+	63, // 25: osprey.ResultEvent.send_time:type_name -> google.protobuf.Timestamp
+	9,  // 26: osprey.ResultEvent.labels:type_name -> osprey.AtprotoLabelEffect
+	10, // 27: osprey.ResultEvent.tags:type_name -> osprey.AtprotoTagEffect
+	11, // 28: osprey.ResultEvent.takedowns:type_name -> osprey.AtprotoTakedownEffect
+	12, // 29: osprey.ResultEvent.emails:type_name -> osprey.AtprotoEmailEffect
+	15, // 30: osprey.ResultEvent.comments:type_name -> osprey.AtprotoCommentEffect
+	16, // 31: osprey.ResultEvent.escalations:type_name -> osprey.AtprotoEscalateEffect
+	19, // 32: osprey.ResultEvent.acknowledgements:type_name -> osprey.AtprotoAcknowledgeEffect
+	20, // 33: osprey.ResultEvent.reports:type_name -> osprey.AtprotoReportEffect
+	21, // 34: osprey.ResultEvent.bigqueryFlags:type_name -> osprey.BigQueryFlagEffect
+	13, // 35: osprey.ResultEvent.mutes:type_name -> osprey.AtprotoMuteEffect
+	14, // 36: osprey.ResultEvent.diverts:type_name -> osprey.AtprotoDivertEffect
+	17, // 37: osprey.ResultEvent.resolve_appeals:type_name -> osprey.AtprotoResolveAppealEffect
+	18, // 38: osprey.ResultEvent.sets:type_name -> osprey.AtprotoSetEffect
+	25, // 39: osprey.ResultEvent.delayed:type_name -> osprey.DelayedEffects
+	24, // 40: osprey.ResultEvent.message:type_name -> osprey.ChatMessageRef
+	23, // 41: osprey.ResultEvent.evidence:type_name -> osprey.Evidence
+	22, // 42: osprey.DelayedEffects.event:type_name -> osprey.ResultEvent
+	22, // 43: osprey.FailedEffects.event:type_name -> osprey.ResultEvent
+	63, // 44: osprey.FailedEffects.next_attempt_at:type_name -> google.protobuf.Timestamp
+	63, // 45: osprey.EffectOutcome.timestamp:type_name -> google.protobuf.Timestamp
+	63, // 46: osprey.FirehoseEvent.timestamp:type_name -> google.protobuf.Timestamp
+	5,  // 47: osprey.FirehoseEvent.kind:type_name -> osprey.EventKind
+	29, // 48: osprey.FirehoseEvent.commit:type_name -> osprey.Commit
+	6,  // 49: osprey.Commit.operation:type_name -> osprey.CommitOperation
+	63, // 50: osprey.ModerationEnrichedFirehoseRecordEvent.timestamp:type_name -> google.protobuf.Timestamp
+	6,  // 51: osprey.ModerationEnrichedFirehoseRecordEvent.operation:type_name -> osprey.CommitOperation
+	51, // 52: osprey.ModerationEnrichedFirehoseRecordEvent.image_results:type_name -> osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntry
+	32, // 53: osprey.ModerationEnrichedFirehoseRecordEvent.velocity:type_name -> osprey.VelocityFeatures
+	33, // 54: osprey.ModerationEnrichedFirehoseRecordEvent.term_list_matches:type_name -> osprey.TermListMatch
+	34, // 55: osprey.ModerationEnrichedFirehoseRecordEvent.impersonation_matches:type_name -> osprey.ImpersonationMatch
+	35, // 56: osprey.ModerationEnrichedFirehoseRecordEvent.identity:type_name -> osprey.IdentityFeatures
+	36, // 57: osprey.ModerationEnrichedFirehoseRecordEvent.pds:type_name -> osprey.PdsFeatures
+	37, // 58: osprey.ModerationEnrichedFirehoseRecordEvent.collection_context:type_name -> osprey.CollectionContext
+	38, // 59: osprey.ModerationEnrichedFirehoseRecordEvent.existing_labels:type_name -> osprey.ExistingLabel
+	40, // 60: osprey.ModerationEnrichedFirehoseRecordEvent.blob_type_mismatches:type_name -> osprey.BlobTypeMismatch
+	39, // 61: osprey.ModerationEnrichedFirehoseRecordEvent.pipeline:type_name -> osprey.PipelineTimes
+	63, // 62: osprey.IdentityFeatures.account_created_at:type_name -> google.protobuf.Timestamp
+	63, // 63: osprey.PdsFeatures.host_first_seen:type_name -> google.protobuf.Timestamp
+	63, // 64: osprey.ExistingLabel.created_at:type_name -> google.protobuf.Timestamp
+	63, // 65: osprey.PipelineTimes.event_timestamp:type_name -> google.protobuf.Timestamp
+	63, // 66: osprey.PipelineTimes.enrichment_started_at:type_name -> google.protobuf.Timestamp
+	63, // 67: osprey.PipelineTimes.enrichment_finished_at:type_name -> google.protobuf.Timestamp
+	52, // 68: osprey.ImageDispatchResults.abyss:type_name -> osprey.ImageDispatchResults.AbyssResults
+	53, // 69: osprey.ImageDispatchResults.hive:type_name -> osprey.ImageDispatchResults.HiveResults
+	54, // 70: osprey.ImageDispatchResults.retina:type_name -> osprey.ImageDispatchResults.RetinaResults
+	56, // 71: osprey.ImageDispatchResults.prescreen:type_name -> osprey.ImageDispatchResults.PrescreenResults
+	55, // 72: osprey.ImageDispatchResults.retina_hash:type_name -> osprey.ImageDispatchResults.RetinaHashResults
+	57, // 73: osprey.ImageDispatchResults.ncii:type_name -> osprey.ImageDispatchResults.NciiResults
+	58, // 74: osprey.ImageDispatchResults.flagged:type_name -> osprey.ImageDispatchResults.FlaggedResults
+	59, // 75: osprey.ImageDispatchResults.animation:type_name -> osprey.ImageDispatchResults.AnimationResults
+	60, // 76: osprey.ImageDispatchResults.sightings:type_name -> osprey.ImageDispatchResults.Sightings
+	61, // 77: osprey.ImageDispatchResults.link_card:type_name -> osprey.ImageDispatchResults.LinkCard
+	63, // 78: osprey.ModerationReportEvent.created_at:type_name -> google.protobuf.Timestamp
+	63, // 79: osprey.LabelExpiredEvent.applied_at:type_name -> google.protobuf.Timestamp
+	63, // 80: osprey.LabelExpiredEvent.expired_at:type_name -> google.protobuf.Timestamp
+	46, // 81: osprey.RetinaImageRequest.ocr:type_name -> osprey.RetinaOCROptions
+	46, // 82: osprey.RetinaImageChunk.ocr:type_name -> osprey.RetinaOCROptions
+	49, // 83: osprey.RetinaHashResponse.checksums:type_name -> osprey.RetinaChecksums
+	41, // 84: osprey.ModerationEnrichedFirehoseRecordEvent.ImageResultsEntry.value:type_name -> osprey.ImageDispatchResults
+	62, // 85: osprey.ImageDispatchResults.HiveResults.classes:type_name -> osprey.ImageDispatchResults.HiveResults.ClassesEntry
+	63, // 86: osprey.ImageDispatchResults.Sightings.first_seen:type_name -> google.protobuf.Timestamp
+	44, // 87: osprey.Retina.Analyze:input_type -> osprey.RetinaImageRequest
+	45, // 88: osprey.Retina.AnalyzeBlob:input_type -> osprey.RetinaImageChunk
+	44, // 89: osprey.Retina.Hash:input_type -> osprey.RetinaImageRequest
+	45, // 90: osprey.Retina.HashBlob:input_type -> osprey.RetinaImageChunk
+	47, // 91: osprey.Retina.Analyze:output_type -> osprey.RetinaAnalyzeResponse
+	47, // 92: osprey.Retina.AnalyzeBlob:output_type -> osprey.RetinaAnalyzeResponse
+	48, // 93: osprey.Retina.Hash:output_type -> osprey.RetinaHashResponse
+	48, // 94: osprey.Retina.HashBlob:output_type -> osprey.RetinaHashResponse
+	91, // [91:95] is the sub-list for method output_type
+	87, // [87:91] is the sub-list for method input_type
+	87, // [87:87] is the sub-list for extension type_name
+	87, // [87:87] is the sub-list for extension extendee
+	0,  // [0:87] is the sub-list for field type_name
 }
 
 func init() { file_osprey_atproto_proto_init() }
@@ -2696,28 +5305,38 @@ func file_osprey_atproto_proto_init() {
 	file_osprey_atproto_proto_msgTypes[3].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[4].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[5].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[6].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[7].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[8].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[9].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[10].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[15].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[11].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[12].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[13].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[14].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[16].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[19].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[20].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[21].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[22].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[23].OneofWrappers = []any{}
 	file_osprey_atproto_proto_msgTypes[24].OneofWrappers = []any{}
-	file_osprey_atproto_proto_msgTypes[25].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[30].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[34].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[35].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[36].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[38].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[45].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[46].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[47].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[48].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[49].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[50].OneofWrappers = []any{}
+	file_osprey_atproto_proto_msgTypes[51].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_osprey_atproto_proto_rawDesc), len(file_osprey_atproto_proto_rawDesc)),
 			NumEnums:      7,
-			NumMessages:   27,
+			NumMessages:   56,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_osprey_atproto_proto_goTypes,
 		DependencyIndexes: file_osprey_atproto_proto_depIdxs,
@@ -0,0 +1,54 @@
+package retina
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIKey is a key clients authenticate to the API with. Its name identifies it in metrics and logs
+// without exposing the key.
+type APIKey struct {
+	Name string
+	Key  string
+}
+
+// ParseAPIKeys parses keys given as name:key, or as just the key, in which case it's named after its
+// position.
+func ParseAPIKeys(keys []string) ([]APIKey, error) {
+	var parsed []APIKey
+	for i, k := range keys {
+		name, key, ok := strings.Cut(k, ":")
+		if !ok {
+			name, key = fmt.Sprintf("key%d", i), k
+		}
+		if key == "" {
+			return nil, fmt.Errorf("api key %q is empty", name)
+		}
+		parsed = append(parsed, APIKey{Name: name, Key: key})
+	}
+	return parsed, nil
+}
+
+// apiKeyAuth rejects requests that don't carry one of the API keys as a bearer token, and counts
+// requests by the key they used.
+func (r *Retina) apiKeyAuth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			auth := e.Request().Header.Get(echo.HeaderAuthorization)
+			if provided, ok := strings.CutPrefix(auth, "Bearer "); ok {
+				for _, k := range r.apiKeys {
+					if subtle.ConstantTimeCompare([]byte(provided), []byte(k.Key)) == 1 {
+						apiRequests.WithLabelValues(k.Name, "authorized").Inc()
+						return next(e)
+					}
+				}
+			}
+			apiRequests.WithLabelValues("", "unauthorized").Inc()
+			return e.JSON(http.StatusUnauthorized, makeErrorJson("unauthorized"))
+		}
+	}
+}
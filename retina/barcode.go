@@ -0,0 +1,97 @@
+package retina
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/aztec"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	multiqrcode "github.com/makiuchi-d/gozxing/multi/qrcode"
+	"github.com/makiuchi-d/gozxing/oned"
+)
+
+type Barcode struct {
+	// Format is the kind of barcode, e.g. QR_CODE or EAN_13.
+	Format string `json:"format"`
+	Text   string `json:"text"`
+}
+
+type BarcodeResult struct {
+	Barcodes []Barcode `json:"barcodes"`
+}
+
+var barcodeHints = map[gozxing.DecodeHintType]any{
+	gozxing.DecodeHintType_TRY_HARDER: true,
+}
+
+// barcodeReaders are the readers of everything but QR codes, which are read separately since an
+// image can have several of them. Each of these finds at most one barcode.
+func barcodeReaders() []gozxing.Reader {
+	return []gozxing.Reader{
+		datamatrix.NewDataMatrixReader(),
+		aztec.NewAztecReader(),
+		oned.NewMultiFormatUPCEANReader(barcodeHints),
+		oned.NewCode128Reader(),
+		oned.NewCode39Reader(),
+		oned.NewCode93Reader(),
+		oned.NewCodaBarReader(),
+		oned.NewITFReader(),
+	}
+}
+
+// DecodeBarcodes finds and decodes the QR codes and barcodes in the image. Images are also read
+// inverted if nothing's found, since light codes on dark backgrounds are common in memes.
+func DecodeBarcodes(img image.Image) ([]Barcode, error) {
+	src := gozxing.NewLuminanceSourceFromImage(img)
+
+	barcodes, err := decodeBarcodes(src)
+	if err != nil || len(barcodes) > 0 {
+		return barcodes, err
+	}
+	return decodeBarcodes(gozxing.NewInvertedLuminanceSource(src))
+}
+
+func decodeBarcodes(src gozxing.LuminanceSource) ([]Barcode, error) {
+	bmp, err := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to binarize image: %w", err)
+	}
+
+	var barcodes []Barcode
+	seen := map[Barcode]bool{}
+	add := func(res *gozxing.Result) {
+		b := Barcode{Format: res.GetBarcodeFormat().String(), Text: res.GetText()}
+		if !seen[b] {
+			seen[b] = true
+			barcodes = append(barcodes, b)
+		}
+	}
+
+	results, err := multiqrcode.NewQRCodeMultiReader().DecodeMultiple(bmp, barcodeHints)
+	if err != nil && !isReaderException(err) {
+		return nil, fmt.Errorf("failed to decode qr codes: %w", err)
+	}
+	for _, res := range results {
+		add(res)
+	}
+
+	for _, reader := range barcodeReaders() {
+		res, err := reader.Decode(bmp, barcodeHints)
+		if err != nil {
+			// Barcodes that are found but fail their checksum or can't be decoded are skipped like
+			// ones that aren't there, so that one bad barcode doesn't hide the others.
+			continue
+		}
+		add(res)
+	}
+
+	return barcodes, nil
+}
+
+// isReaderException reports whether the error is just that no readable barcode was found.
+func isReaderException(err error) bool {
+	var readerErr gozxing.ReaderException
+	return errors.As(err, &readerErr)
+}
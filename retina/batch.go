@@ -0,0 +1,249 @@
+package retina
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxBatchSize is the most images a batch request can include.
+const maxBatchSize = 20
+
+type BatchRequest struct {
+	Images []ImageRequest `json:"images"`
+	// Langs are the OCR languages, Preprocess the preprocessing steps, and CdnHost the CDN of
+	// images that don't set their own, overriding the query parameters.
+	Langs      string `json:"langs,omitempty"`
+	Preprocess string `json:"preprocess,omitempty"`
+	CdnHost    string `json:"cdnHost,omitempty"`
+}
+
+// BatchItemResult is the OCR and PDQ result of one image in a batch. Images are identified by their
+// DID and CID, or the file name they were uploaded under.
+type BatchItemResult struct {
+	Did  string `json:"did,omitempty"`
+	Cid  string `json:"cid,omitempty"`
+	Name string `json:"name,omitempty"`
+
+	Text *string    `json:"text,omitempty"`
+	Pdq  *PdqResult `json:"pdq,omitempty"`
+	// Checksums are only set for uploaded images.
+	Checksums *Checksums `json:"checksums,omitempty"`
+	// Error is set if either the OCR or the hash of the image failed, and explains which.
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Results []BatchItemResult `json:"results"`
+}
+
+type batchItem struct {
+	result *BatchItemResult
+	// image is the uploaded image, or nil if it's to be downloaded from cdnHost.
+	image   []byte
+	cdnHost string
+	langs   []string
+	steps   []string
+}
+
+// handleBatch runs OCR and PDQ hashing on several images in one request, given either as DID and
+// CID pairs in a JSON body, or as the files of a multipart form. Results are in the order of the
+// request, and an image failing doesn't fail the others.
+func (r *Retina) handleBatch(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	start := time.Now()
+
+	status := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(status, "batch").Inc()
+		requestTimeHist.WithLabelValues(status, "batch").Observe(float64(time.Since(start).Seconds()))
+	}()
+
+	items, err := r.batchItems(e)
+	if err != nil {
+		return e.JSON(batchItemsStatus(err), makeErrorJson(err.Error()))
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.processBatchItem(ctx, item)
+		}()
+	}
+	wg.Wait()
+
+	res := BatchResult{Results: make([]BatchItemResult, len(items))}
+	for i, item := range items {
+		res.Results[i] = *item.result
+	}
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, res)
+}
+
+// batchItemsStatus is the status code a request whose items couldn't be read is rejected with.
+func batchItemsStatus(err error) int {
+	if errors.Is(err, ErrImageTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func (r *Retina) batchItems(e echo.Context) ([]*batchItem, error) {
+	var items []*batchItem
+
+	queryLangs, err := r.parseOCRLanguages(e.QueryParam("langs"))
+	if err != nil {
+		return nil, err
+	}
+	querySteps, err := r.parsePreprocessSteps(e.QueryParam("preprocess"))
+	if err != nil {
+		return nil, err
+	}
+
+	queryCdnHost := e.QueryParam("cdnHost")
+
+	if strings.HasPrefix(e.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		form, err := e.MultipartForm()
+		if err != nil {
+			return nil, errors.New("could not parse multipart form")
+		}
+		// Forms don't keep the order of their fields, so files are in the order of their field names.
+		for _, field := range slices.Sorted(maps.Keys(form.File)) {
+			for _, fh := range form.File[field] {
+				f, err := fh.Open()
+				if err != nil {
+					return nil, fmt.Errorf("could not open file %s", fh.Filename)
+				}
+				b, err := io.ReadAll(io.LimitReader(f, r.limits.MaxUploadSize+1))
+				f.Close()
+				if err != nil {
+					return nil, fmt.Errorf("could not read file %s", fh.Filename)
+				}
+				if int64(len(b)) > r.limits.MaxUploadSize {
+					return nil, fmt.Errorf("%w: file %s is over the limit of %d bytes", ErrImageTooLarge, fh.Filename, r.limits.MaxUploadSize)
+				}
+				items = append(items, &batchItem{result: &BatchItemResult{Name: fh.Filename}, image: b, langs: queryLangs, steps: querySteps})
+			}
+		}
+	} else {
+		var req BatchRequest
+		if err := e.Bind(&req); err != nil {
+			return nil, errors.New("could not bind request")
+		}
+		for _, img := range req.Images {
+			langs := queryLangs
+			if l := cmp.Or(img.Langs, req.Langs); l != "" {
+				if langs, err = r.parseOCRLanguages(l); err != nil {
+					return nil, err
+				}
+			}
+			steps := querySteps
+			if p := cmp.Or(img.Preprocess, req.Preprocess); p != "" {
+				if steps, err = r.parsePreprocessSteps(p); err != nil {
+					return nil, err
+				}
+			}
+			cdnHost, err := r.cdnHost(cmp.Or(img.CdnHost, req.CdnHost, queryCdnHost))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, &batchItem{result: &BatchItemResult{Did: img.Did, Cid: img.Cid}, langs: langs, steps: steps, cdnHost: cdnHost})
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("no images in request")
+	}
+	if len(items) > maxBatchSize {
+		return nil, fmt.Errorf("too many images in request, the most is %d", maxBatchSize)
+	}
+	return items, nil
+}
+
+func (r *Retina) processBatchItem(ctx context.Context, item *batchItem) {
+	res := item.result
+
+	if text, ok := r.cachedText(res.Cid, item.langs, item.steps); ok {
+		res.Text = &text
+	}
+	if pdq, ok := r.cachedHash(res.Cid); ok {
+		res.Pdq = pdq
+	}
+	if res.Text != nil && res.Pdq != nil {
+		return
+	}
+
+	b := item.image
+	if b != nil {
+		res.Checksums = GetChecksums(b)
+	} else {
+		var err error
+		cdnUrl := makeCdnUrl(item.cdnHost, res.Did, res.Cid)
+		b, err = r.downloadImage(ctx, cdnUrl)
+		if err != nil {
+			if errors.Is(err, ErrImageNotFound) {
+				res.Error = "image not found"
+				return
+			}
+			if errors.Is(err, ErrImageTooLarge) {
+				res.Error = err.Error()
+				return
+			}
+			r.logger.Error("error downloading image", "url", cdnUrl, "error", err)
+			res.Error = "could not download image"
+			return
+		}
+	}
+
+	var errs []string
+
+	if res.Text == nil {
+		if tb, err := r.prepareForOCR(b, item.steps); errors.Is(err, ErrImageTooLarge) {
+			errs = append(errs, err.Error())
+		} else if err != nil {
+			errs = append(errs, "could not decode image")
+		} else if text, err := r.getImageTextStream(ctx, bytes.NewReader(tb), item.langs); err != nil {
+			r.logger.Error("error getting text from image", "error", err)
+			errs = append(errs, "could not get text from image")
+		} else {
+			res.Text = &text
+			r.cacheText(res.Cid, item.langs, item.steps, text)
+		}
+	}
+
+	if res.Pdq == nil {
+		hashRes, err := r.GetImageHash(ctx, b)
+		switch {
+		case errors.Is(err, ErrQualityTooLow):
+			res.Pdq = &PdqResult{QualityTooLow: true}
+			r.cacheHash(res.Cid, res.Pdq)
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("error getting image hash: %v", err))
+		default:
+			if binary, err := strToBinary(hashRes); err != nil {
+				errs = append(errs, "unable to convert pdq hash to binary")
+			} else {
+				res.Pdq = &PdqResult{Hash: &hashRes, Binary: &binary}
+				r.cacheHash(res.Cid, res.Pdq)
+			}
+		}
+	}
+
+	res.Error = strings.Join(errs, "; ")
+}
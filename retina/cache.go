@@ -0,0 +1,170 @@
+package retina
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	lru "github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+// CacheArgs configure caching OCR and PDQ results by the CID of the image, so that images that
+// are scanned over and over, like viral ones, are only run through tesseract and PDQ once.
+type CacheArgs struct {
+	// Size is how many results are kept in memory. Results aren't cached in memory if it's 0.
+	Size int
+	TTL  time.Duration
+	// MemcacheServers share results between replicas, if set.
+	MemcacheServers []string
+}
+
+// resultCache looks results up in memory, then in memcached. A nil resultCache caches nothing.
+type resultCache struct {
+	lru      *lru.LRU[string, []byte]
+	memcache *memcache.Client
+	ttl      time.Duration
+}
+
+func newResultCache(args *CacheArgs) (*resultCache, error) {
+	if args.Size <= 0 && len(args.MemcacheServers) == 0 {
+		return nil, nil
+	}
+
+	c := &resultCache{ttl: args.TTL}
+	if args.Size > 0 {
+		c.lru = lru.NewLRU[string, []byte](args.Size, nil, args.TTL)
+	}
+	if len(args.MemcacheServers) > 0 {
+		c.memcache = memcache.New(args.MemcacheServers...)
+		if err := c.memcache.Ping(); err != nil {
+			return nil, fmt.Errorf("failed to ping memcache servers: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *resultCache) get(kind, key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	if c.lru != nil {
+		if v, ok := c.lru.Get(key); ok {
+			cacheLookups.WithLabelValues(kind, "memory").Inc()
+			return v, true
+		}
+	}
+	if c.memcache != nil {
+		item, err := c.memcache.Get(memcacheResultKey(key))
+		if err == nil {
+			cacheLookups.WithLabelValues(kind, "memcache").Inc()
+			if c.lru != nil {
+				c.lru.Add(key, item.Value)
+			}
+			return item.Value, true
+		}
+		if !errors.Is(err, memcache.ErrCacheMiss) {
+			cacheLookups.WithLabelValues(kind, "error").Inc()
+			return nil, false
+		}
+	}
+	cacheLookups.WithLabelValues(kind, "miss").Inc()
+	return nil, false
+}
+
+func (c *resultCache) set(key string, v []byte) {
+	if c == nil {
+		return
+	}
+
+	if c.lru != nil {
+		c.lru.Add(key, v)
+	}
+	if c.memcache != nil {
+		// Failing to cache only costs the next lookup a scan.
+		_ = c.memcache.Set(&memcache.Item{
+			Key:        memcacheResultKey(key),
+			Value:      v,
+			Expiration: int32(c.ttl.Seconds()),
+		})
+	}
+}
+
+func memcacheResultKey(key string) string {
+	return "retina-" + key
+}
+
+// OCR results depend on the languages and preprocessing as well as the image.
+func ocrCacheKey(cid string, langs, steps []string) string {
+	return fmt.Sprintf("ocr:%s:%s:%s", cid, strings.Join(langs, "+"), strings.Join(steps, ","))
+}
+
+func pdqCacheKey(cid string) string {
+	return "pdq:" + cid
+}
+
+// blobCacheCid returns the CID to cache the results of an uploaded blob under, which is the CID the
+// caller gave only if it's the CID of the blob. Otherwise it's empty, so that the results aren't
+// cached and a wrong CID can't stand in for the image it names.
+func blobCacheCid(claimed string, b []byte) string {
+	if claimed == "" {
+		return ""
+	}
+	c, err := cid.Decode(claimed)
+	if err != nil || c.Prefix().MhType != multihash.SHA2_256 {
+		return ""
+	}
+	sum, err := c.Prefix().Sum(b)
+	if err != nil || !sum.Equals(c) {
+		return ""
+	}
+	return claimed
+}
+
+// cachedText returns the text of the image with the CID, if it's cached.
+func (r *Retina) cachedText(cid string, langs, steps []string) (string, bool) {
+	if cid == "" {
+		return "", false
+	}
+	v, ok := r.cache.get("ocr", ocrCacheKey(cid, langs, steps))
+	return string(v), ok
+}
+
+func (r *Retina) cacheText(cid string, langs, steps []string, text string) {
+	if cid == "" {
+		return
+	}
+	r.cache.set(ocrCacheKey(cid, langs, steps), []byte(text))
+}
+
+// cachedHash returns the PDQ result of the image with the CID, if it's cached.
+func (r *Retina) cachedHash(cid string) (*PdqResult, bool) {
+	if cid == "" {
+		return nil, false
+	}
+	v, ok := r.cache.get("pdq", pdqCacheKey(cid))
+	if !ok {
+		return nil, false
+	}
+	var res PdqResult
+	if err := json.Unmarshal(v, &res); err != nil {
+		return nil, false
+	}
+	return &res, true
+}
+
+func (r *Retina) cacheHash(cid string, res *PdqResult) {
+	if cid == "" {
+		return
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	r.cache.set(pdqCacheKey(cid), b)
+}
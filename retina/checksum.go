@@ -0,0 +1,52 @@
+package retina
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Checksums are the exact hashes of a blob, as industry hash lists use, in hex.
+type Checksums struct {
+	MD5    string `json:"md5"`
+	SHA1   string `json:"sha1"`
+	SHA256 string `json:"sha256"`
+}
+
+// GetChecksums computes the checksums of b.
+func GetChecksums(b []byte) *Checksums {
+	m, s1, s256 := md5.New(), sha1.New(), sha256.New()
+	_, _ = io.MultiWriter(m, s1, s256).Write(b)
+	return &Checksums{
+		MD5:    hex.EncodeToString(m.Sum(nil)),
+		SHA1:   hex.EncodeToString(s1.Sum(nil)),
+		SHA256: hex.EncodeToString(s256.Sum(nil)),
+	}
+}
+
+// handleChecksum returns the checksums of a blob given either as the request body, or as a DID and
+// CID in a JSON body, which is fetched from the owner's PDS so that it's the original blob.
+func (r *Retina) handleChecksum(e echo.Context) error {
+	start := time.Now()
+
+	status := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(status, "checksum").Inc()
+		requestTimeHist.WithLabelValues(status, "checksum").Observe(float64(time.Since(start).Seconds()))
+	}()
+
+	b, code, err := r.requestBlob(e)
+	if err != nil {
+		return e.JSON(code, makeErrorJson(err.Error()))
+	}
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, GetChecksums(b))
+}
@@ -0,0 +1,161 @@
+package retina
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"slices"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	ClassifierLayoutNCHW = "nchw"
+	ClassifierLayoutNHWC = "nhwc"
+)
+
+var ErrClassifierDisabled = errors.New("no classifier model is configured")
+
+// ClassifierArgs configure the optional ONNX image classifier, e.g. an NSFW model. How images are
+// fed to the model depends on how it was trained, so it's all configurable.
+type ClassifierArgs struct {
+	// ModelPath is the ONNX model, which enables the classifier if set.
+	ModelPath string
+	// LibraryPath is the onnxruntime shared library, or onnxruntime.so if unset.
+	LibraryPath string
+	// Labels are the model's classes, in the order of its outputs.
+	Labels []string
+	// InputSize is the width and height images are resized to.
+	InputSize int
+	// Layout is how the model takes its input, ClassifierLayoutNCHW or ClassifierLayoutNHWC.
+	Layout string
+	// Mean and Std normalize each RGB channel, after it's scaled to [0, 1].
+	Mean []float64
+	Std  []float64
+	// Softmax turns the outputs into probabilities, for models that output logits.
+	Softmax bool
+	// MaxConcurrent is how many images can be classified at once.
+	MaxConcurrent int64
+}
+
+// ClassScore and ClassifyResult match the responses of the prescreen service, so that they can be
+// used in its place.
+type ClassScore struct {
+	Class string  `json:"class"`
+	Score float64 `json:"score"`
+}
+
+type ClassifyResult struct {
+	// Result is the class with the highest score.
+	Result      string       `json:"result"`
+	ClassScores []ClassScore `json:"class_scores"`
+}
+
+// validate fills in the defaults, and checks the rest.
+func (a *ClassifierArgs) validate() error {
+	if len(a.Labels) == 0 {
+		return errors.New("the classifier needs the labels of its model's classes")
+	}
+	if a.InputSize <= 0 {
+		a.InputSize = 224
+	}
+	if a.Layout == "" {
+		a.Layout = ClassifierLayoutNCHW
+	}
+	if a.Layout != ClassifierLayoutNCHW && a.Layout != ClassifierLayoutNHWC {
+		return fmt.Errorf("unknown classifier input layout %q", a.Layout)
+	}
+	if a.Mean == nil {
+		a.Mean = []float64{0, 0, 0}
+	}
+	if a.Std == nil {
+		a.Std = []float64{1, 1, 1}
+	}
+	if len(a.Mean) != 3 || len(a.Std) != 3 || slices.Contains(a.Std, 0) {
+		return errors.New("the classifier needs a mean and a non-zero std for each of the 3 channels")
+	}
+	if a.MaxConcurrent <= 0 {
+		a.MaxConcurrent = 2
+	}
+	return nil
+}
+
+// Classify runs the classifier model on the image.
+func (r *Retina) Classify(ctx context.Context, b []byte) (*ClassifyResult, error) {
+	if r.classifier == nil {
+		return nil, ErrClassifierDisabled
+	}
+
+	if err := r.checkImageSize(b); err != nil {
+		return nil, err
+	}
+	img, err := decodeImage(b)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := r.classifier.run(ctx, classifierInput(img, r.classifierArgs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run classifier: %w", err)
+	}
+	return classifierResult(outputs, r.classifierArgs)
+}
+
+// classifierInput resizes the image to the model's input size, and lays its normalized channels
+// out as the model takes them.
+func classifierInput(img image.Image, args *ClassifierArgs) []float32 {
+	size := args.InputSize
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.BiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	data := make([]float32, 3*size*size)
+	for y := range size {
+		for x := range size {
+			px := dst.Pix[y*dst.Stride+x*4:]
+			for c := range 3 {
+				v := float32((float64(px[c])/255 - args.Mean[c]) / args.Std[c])
+				if args.Layout == ClassifierLayoutNHWC {
+					data[(y*size+x)*3+c] = v
+				} else {
+					data[c*size*size+y*size+x] = v
+				}
+			}
+		}
+	}
+	return data
+}
+
+func classifierResult(outputs []float32, args *ClassifierArgs) (*ClassifyResult, error) {
+	if len(outputs) != len(args.Labels) {
+		return nil, fmt.Errorf("model has %d outputs, but %d labels are configured", len(outputs), len(args.Labels))
+	}
+
+	scores := make([]float64, len(outputs))
+	for i, v := range outputs {
+		scores[i] = float64(v)
+	}
+	if args.Softmax {
+		top := slices.Max(scores)
+		var sum float64
+		for i, v := range scores {
+			scores[i] = math.Exp(v - top)
+			sum += scores[i]
+		}
+		for i := range scores {
+			scores[i] /= sum
+		}
+	}
+
+	res := &ClassifyResult{ClassScores: make([]ClassScore, len(scores))}
+	best := 0
+	for i, score := range scores {
+		res.ClassScores[i] = ClassScore{Class: args.Labels[i], Score: score}
+		if score > scores[best] {
+			best = i
+		}
+	}
+	res.Result = args.Labels[best]
+	return res, nil
+}
@@ -0,0 +1,89 @@
+//go:build onnx
+
+package retina
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	ort "github.com/yalue/onnxruntime_go"
+	"golang.org/x/sync/semaphore"
+)
+
+// onnxClassifier runs the classifier model with onnxruntime, whose sessions can be run
+// concurrently.
+type onnxClassifier struct {
+	session *ort.DynamicAdvancedSession
+	shape   ort.Shape
+	sem     *semaphore.Weighted
+}
+
+func newOnnxClassifier(args *ClassifierArgs) (*onnxClassifier, error) {
+	if args.LibraryPath != "" {
+		ort.SetSharedLibraryPath(args.LibraryPath)
+	}
+	if err := ort.InitializeEnvironment(); err != nil {
+		return nil, fmt.Errorf("failed to initialize onnxruntime: %w", err)
+	}
+
+	inputs, outputs, err := ort.GetInputOutputInfo(args.ModelPath)
+	if err != nil {
+		ort.DestroyEnvironment()
+		return nil, fmt.Errorf("failed to read classifier model: %w", err)
+	}
+	if len(inputs) != 1 || len(outputs) == 0 {
+		ort.DestroyEnvironment()
+		return nil, fmt.Errorf("classifier model should have 1 input, but has %d", len(inputs))
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(args.ModelPath, []string{inputs[0].Name}, []string{outputs[0].Name}, nil)
+	if err != nil {
+		ort.DestroyEnvironment()
+		return nil, fmt.Errorf("failed to load classifier model: %w", err)
+	}
+
+	size := int64(args.InputSize)
+	shape := ort.NewShape(1, 3, size, size)
+	if args.Layout == ClassifierLayoutNHWC {
+		shape = ort.NewShape(1, size, size, 3)
+	}
+
+	return &onnxClassifier{
+		session: session,
+		shape:   shape,
+		sem:     semaphore.NewWeighted(args.MaxConcurrent),
+	}, nil
+}
+
+func (c *onnxClassifier) run(ctx context.Context, input []float32) ([]float32, error) {
+	if err := c.sem.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("error acquiring semaphore lock: %w", err)
+	}
+	defer c.sem.Release(1)
+
+	in, err := ort.NewTensor(c.shape, input)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Destroy()
+
+	// The output is allocated by onnxruntime, since its shape depends on the model.
+	outputs := []ort.Value{nil}
+	if err := c.session.Run([]ort.Value{in}, outputs); err != nil {
+		return nil, err
+	}
+	defer outputs[0].Destroy()
+
+	out, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, errors.New("classifier model's output isn't float32")
+	}
+	return slices.Clone(out.GetData()), nil
+}
+
+func (c *onnxClassifier) close() {
+	c.session.Destroy()
+	ort.DestroyEnvironment()
+}
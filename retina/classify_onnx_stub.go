@@ -0,0 +1,23 @@
+//go:build !onnx
+
+package retina
+
+import (
+	"context"
+	"errors"
+)
+
+var errOnnxNotBuilt = errors.New("retina was built without the onnx build tag, so it can't run a classifier model")
+
+// onnxClassifier is only available with the onnx build tag, which needs onnxruntime.
+type onnxClassifier struct{}
+
+func newOnnxClassifier(args *ClassifierArgs) (*onnxClassifier, error) {
+	return nil, errOnnxNotBuilt
+}
+
+func (c *onnxClassifier) run(ctx context.Context, input []float32) ([]float32, error) {
+	return nil, errOnnxNotBuilt
+}
+
+func (c *onnxClassifier) close() {}
@@ -0,0 +1,56 @@
+package retina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+
+	_ "image/gif"
+	_ "image/jpeg"
+
+	_ "github.com/gen2brain/avif"
+	_ "golang.org/x/image/webp"
+)
+
+var ErrDecodeImage = errors.New("failed to decode image")
+
+// toolFormats are the formats tesseract and the pdq photo hasher are given images in as is. Images
+// in any other supported format are transcoded to PNG in memory first.
+var toolFormats = map[string]bool{
+	"jpeg": true,
+	"png":  true,
+}
+
+// decodeImage decodes an image in any of the supported formats.
+func decodeImage(b []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecodeImage, err)
+	}
+	return img, nil
+}
+
+// toToolFormat returns the image as is if it's already a JPEG or PNG, and otherwise transcodes it
+// to PNG, so that tesseract and the pdq photo hasher don't depend on how they were built to read
+// it.
+func toToolFormat(b []byte) ([]byte, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	if toolFormats[format] {
+		return b, nil
+	}
+
+	img, err := decodeImage(b)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to transcode %s image to png: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
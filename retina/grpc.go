@@ -0,0 +1,316 @@
+package retina
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer serves the same analyze and hash operations as the HTTP API.
+type grpcServer struct {
+	osprey.UnimplementedRetinaServer
+	r *Retina
+}
+
+// grpcMessageOverhead is room for the fields sent along with an image in a message, on top of the
+// max upload size.
+const grpcMessageOverhead = 64 << 10
+
+func (r *Retina) newGrpcServer() *grpc.Server {
+	// Messages are capped like HTTP request bodies, rather than at gRPC's default.
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(r.limits.MaxUploadSize) + grpcMessageOverhead),
+	}
+	if len(r.apiKeys) > 0 {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				if err := r.checkGrpcAPIKey(ctx); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := r.checkGrpcAPIKey(ss.Context()); err != nil {
+					return err
+				}
+				return handler(srv, ss)
+			}),
+		)
+	}
+
+	s := grpc.NewServer(opts...)
+	osprey.RegisterRetinaServer(s, &grpcServer{r: r})
+	return s
+}
+
+// checkGrpcAPIKey checks the authorization metadata like apiKeyAuth checks the header.
+func (r *Retina) checkGrpcAPIKey(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		provided, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok {
+			continue
+		}
+		for _, k := range r.apiKeys {
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(k.Key)) == 1 {
+				apiRequests.WithLabelValues(k.Name, "authorized").Inc()
+				return nil
+			}
+		}
+	}
+	apiRequests.WithLabelValues("", "unauthorized").Inc()
+	return status.Error(codes.Unauthenticated, "unauthorized")
+}
+
+func (r *Retina) runGrpcServer(ctx context.Context, s *grpc.Server, addr string) {
+	log := r.logger.With("component", "retina_grpc")
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Error("failed to listen for grpc", "addr", addr, "error", err)
+		return
+	}
+
+	go func() {
+		log.Info("retina grpc server listening", "addr", addr)
+		if err := s.Serve(lis); err != nil {
+			log.Error("failed to serve grpc", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	log.Info("shutting down retina grpc server")
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		s.Stop()
+	}
+	log.Info("retina grpc server shut down")
+}
+
+func (g *grpcServer) Analyze(ctx context.Context, req *osprey.RetinaImageRequest) (*osprey.RetinaAnalyzeResponse, error) {
+	start := time.Now()
+	st := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(st, "ocr-grpc").Inc()
+		requestTimeHist.WithLabelValues(st, "ocr-grpc").Observe(time.Since(start).Seconds())
+	}()
+
+	langs, steps, err := g.ocrOptions(req.Ocr)
+	if err != nil {
+		return nil, err
+	}
+	if text, ok := g.r.cachedText(req.Cid, langs, steps); ok {
+		st = "ok"
+		return &osprey.RetinaAnalyzeResponse{Text: text}, nil
+	}
+
+	b, err := g.download(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := g.analyze(ctx, b, langs, steps)
+	if err != nil {
+		return nil, err
+	}
+	g.r.cacheText(req.Cid, langs, steps, res.Text)
+	st = "ok"
+	return res, nil
+}
+
+func (g *grpcServer) AnalyzeBlob(stream osprey.Retina_AnalyzeBlobServer) error {
+	start := time.Now()
+	st := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(st, "ocr-blob-grpc").Inc()
+		requestTimeHist.WithLabelValues(st, "ocr-blob-grpc").Observe(time.Since(start).Seconds())
+	}()
+
+	b, opts, err := receiveBlob(stream, g.r.limits.MaxUploadSize)
+	if err != nil {
+		return err
+	}
+	langs, steps, err := g.ocrOptions(opts)
+	if err != nil {
+		return err
+	}
+	res, err := g.analyze(stream.Context(), b, langs, steps)
+	if err != nil {
+		return err
+	}
+	st = "ok"
+	return stream.SendAndClose(res)
+}
+
+func (g *grpcServer) Hash(ctx context.Context, req *osprey.RetinaImageRequest) (*osprey.RetinaHashResponse, error) {
+	start := time.Now()
+	st := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(st, "pdq-grpc").Inc()
+		requestTimeHist.WithLabelValues(st, "pdq-grpc").Observe(time.Since(start).Seconds())
+	}()
+
+	if pdq, ok := g.r.cachedHash(req.Cid); ok {
+		st = "ok"
+		return hashResponse(pdq), nil
+	}
+
+	b, err := g.download(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := g.hash(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	pdq := &PdqResult{QualityTooLow: true}
+	if !res.QualityTooLow {
+		pdq = &PdqResult{Hash: &res.Hash, Binary: &res.Binary}
+	}
+	g.r.cacheHash(req.Cid, pdq)
+	st = "ok"
+	return res, nil
+}
+
+func (g *grpcServer) HashBlob(stream osprey.Retina_HashBlobServer) error {
+	start := time.Now()
+	st := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(st, "pdq-grpc").Inc()
+		requestTimeHist.WithLabelValues(st, "pdq-grpc").Observe(time.Since(start).Seconds())
+	}()
+
+	b, _, err := receiveBlob(stream, g.r.limits.MaxUploadSize)
+	if err != nil {
+		return err
+	}
+	res, err := g.hash(stream.Context(), b)
+	if err != nil {
+		return err
+	}
+	checksums := GetChecksums(b)
+	res.Checksums = &osprey.RetinaChecksums{Md5: checksums.MD5, Sha1: checksums.SHA1, Sha256: checksums.SHA256}
+	st = "ok"
+	return stream.SendAndClose(res)
+}
+
+func (g *grpcServer) download(ctx context.Context, req *osprey.RetinaImageRequest) ([]byte, error) {
+	if req.Did == "" || req.Cid == "" {
+		return nil, status.Error(codes.InvalidArgument, "did and cid are required")
+	}
+	cdnHost, err := g.r.cdnHost(req.CdnHost)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	cdnUrl := makeCdnUrl(cdnHost, req.Did, req.Cid)
+	b, err := g.r.downloadImage(ctx, cdnUrl)
+	if err != nil {
+		if errors.Is(err, ErrImageNotFound) {
+			return nil, status.Error(codes.NotFound, "image not found")
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		g.r.logger.Error("error downloading image", "url", cdnUrl, "error", err)
+		return nil, status.Error(codes.Unavailable, "could not download image")
+	}
+	return b, nil
+}
+
+func (g *grpcServer) ocrOptions(opts *osprey.RetinaOCROptions) ([]string, []string, error) {
+	langs, err := g.r.parseOCRLanguages(opts.GetLangs())
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	steps, err := g.r.parsePreprocessSteps(opts.GetPreprocess())
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return langs, steps, nil
+}
+
+func (g *grpcServer) analyze(ctx context.Context, b []byte, langs, steps []string) (*osprey.RetinaAnalyzeResponse, error) {
+	b, err := g.r.prepareForOCR(b, steps)
+	if err != nil {
+		if errors.Is(err, ErrImageTooLarge) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, "could not decode image")
+	}
+	text, err := g.r.getImageTextStream(ctx, bytes.NewReader(b), langs)
+	if err != nil {
+		g.r.logger.Error("error getting text from image", "error", err)
+		return nil, status.Error(codes.Internal, "could not get text from image")
+	}
+	return &osprey.RetinaAnalyzeResponse{Text: text}, nil
+}
+
+func (g *grpcServer) hash(ctx context.Context, b []byte) (*osprey.RetinaHashResponse, error) {
+	hashRes, err := g.r.GetImageHash(ctx, b)
+	if err != nil {
+		if errors.Is(err, ErrQualityTooLow) {
+			return &osprey.RetinaHashResponse{QualityTooLow: true}, nil
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "error getting image hash: %v", err)
+	}
+	binary, err := strToBinary(hashRes)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "unable to convert pdq hash to binary")
+	}
+	return &osprey.RetinaHashResponse{Hash: hashRes, Binary: binary}, nil
+}
+
+func hashResponse(pdq *PdqResult) *osprey.RetinaHashResponse {
+	if pdq.QualityTooLow || pdq.Hash == nil || pdq.Binary == nil {
+		return &osprey.RetinaHashResponse{QualityTooLow: true}
+	}
+	return &osprey.RetinaHashResponse{Hash: *pdq.Hash, Binary: *pdq.Binary}
+}
+
+// receiveBlob reads the image streamed in chunks, and the options sent with the first one.
+func receiveBlob(stream interface {
+	Recv() (*osprey.RetinaImageChunk, error)
+}, maxSize int64) ([]byte, *osprey.RetinaOCROptions, error) {
+	var buf bytes.Buffer
+	var opts *osprey.RetinaOCROptions
+	for first := true; ; first = false {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if first {
+			opts = chunk.Ocr
+		}
+		if int64(buf.Len()+len(chunk.Data)) > maxSize {
+			return nil, nil, status.Error(codes.ResourceExhausted, "image is too large")
+		}
+		buf.Write(chunk.Data)
+	}
+	if buf.Len() == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "no image data")
+	}
+	return buf.Bytes(), opts, nil
+}
@@ -0,0 +1,77 @@
+package retina
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	osprey "github.com/bluesky-social/osprey-atproto/proto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGrpcClient serves retina's gRPC API in memory, with the given max upload size.
+func newTestGrpcClient(t *testing.T, maxUploadSize int64) osprey.RetinaClient {
+	t.Helper()
+
+	r := &Retina{
+		logger: slog.Default(),
+		limits: &LimitArgs{MaxUploadSize: maxUploadSize},
+	}
+	r.limits.setDefaults()
+
+	lis := bufconn.Listen(1 << 20)
+	s := r.newGrpcServer()
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return osprey.NewRetinaClient(conn)
+}
+
+func TestGrpcRejectsOversizedMessages(t *testing.T) {
+	client := newTestGrpcClient(t, 1<<10)
+
+	// Larger than the upload size and the room for other fields, so it's rejected before the
+	// handler sees it, rather than failing on the missing DID.
+	_, err := client.Hash(context.Background(), &osprey.RetinaImageRequest{
+		Cid: strings.Repeat("a", 1<<10+grpcMessageOverhead+1),
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+}
+
+func TestGrpcRejectsOversizedBlobs(t *testing.T) {
+	client := newTestGrpcClient(t, 1<<10)
+
+	stream, err := client.HashBlob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each chunk fits in a message, but together they're over the upload size.
+	for range 2 {
+		if err := stream.Send(&osprey.RetinaImageChunk{Data: make([]byte, 600)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+}
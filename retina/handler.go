@@ -2,11 +2,15 @@ package retina
 
 import (
 	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -16,6 +20,14 @@ import (
 type ImageRequest struct {
 	Did string `json:"did"`
 	Cid string `json:"cid"`
+	// Langs are the OCR languages, e.g. jpn+eng, overriding the langs query parameter.
+	Langs string `json:"langs,omitempty"`
+	// Preprocess are the comma separated preprocessing steps run before OCR, overriding the
+	// preprocess query parameter.
+	Preprocess string `json:"preprocess,omitempty"`
+	// CdnHost is the CDN the image is fetched from, overriding the cdnHost query parameter. It has
+	// to be one of the allowed CDN hosts.
+	CdnHost string `json:"cdnHost,omitempty"`
 }
 
 type AnalyzeResult struct {
@@ -27,6 +39,9 @@ type PdqResult struct {
 	Hash          *string `json:"hash,omitempty"`
 	Binary        *string `json:"binary,omitempty"`
 	QualityTooLow bool    `json:"qualityTooLow"`
+	// Checksums are only set for blobs in the request, since images fetched from the CDN aren't
+	// the original blobs.
+	Checksums *Checksums `json:"checksums,omitempty"`
 }
 
 func makeErrorJson(error string) AnalyzeResult {
@@ -51,23 +66,54 @@ func (r *Retina) handleAnalyze(e echo.Context) error {
 		return e.JSON(http.StatusBadRequest, makeErrorJson("could not bind request"))
 	}
 
-	cdnUrl := makeCdnUrl(req.Did, req.Cid)
+	langs, err := r.parseOCRLanguages(cmp.Or(req.Langs, e.QueryParam("langs")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+	}
+	steps, err := r.parsePreprocessSteps(cmp.Or(req.Preprocess, e.QueryParam("preprocess")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+	}
+
+	cdnHost, err := r.cdnHost(cmp.Or(req.CdnHost, e.QueryParam("cdnHost")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+	}
+
+	if text, ok := r.cachedText(req.Cid, langs, steps); ok {
+		status = "ok"
+		return e.JSON(http.StatusOK, AnalyzeResult{Text: text})
+	}
+
+	cdnUrl := makeCdnUrl(cdnHost, req.Did, req.Cid)
 	imageBytes, err := r.downloadImage(ctx, cdnUrl)
 	if err != nil {
 		if errors.Is(err, ErrImageNotFound) {
 			return e.JSON(http.StatusBadRequest, makeErrorJson("image not found"))
 		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+		}
 
 		r.logger.Error("error downloading image", "url", cdnUrl, "error", err)
 		// not really an internal error?
 		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not download image"))
 	}
 
-	imageText, err := r.getImageTextStream(ctx, bytes.NewReader(imageBytes))
+	imageBytes, err = r.prepareForOCR(imageBytes, steps)
+	if err != nil {
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+		}
+		return e.JSON(http.StatusUnsupportedMediaType, makeErrorJson("could not decode image"))
+	}
+
+	imageText, err := r.getImageTextStream(ctx, bytes.NewReader(imageBytes), langs)
 	if err != nil {
 		r.logger.Error("error getting text from image", "error", err)
 		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not get text from image"))
 	}
+	r.cacheText(req.Cid, langs, steps, imageText)
 
 	status = "ok"
 
@@ -101,12 +147,44 @@ func (r *Retina) handleAnalyzeBlob(e echo.Context) error {
 		return e.JSON(http.StatusUnsupportedMediaType, makeErrorJson("unsupported media type"))
 	}
 
-	imageText, err := r.getImageTextStream(ctx, req.Body)
+	langs, err := r.parseOCRLanguages(e.QueryParam("langs"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+	}
+	steps, err := r.parsePreprocessSteps(e.QueryParam("preprocess"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+	}
+
+	cid := e.QueryParam("cid")
+	if text, ok := r.cachedText(cid, langs, steps); ok {
+		req.Body.Close()
+		status = "ok"
+		return e.JSON(http.StatusOK, AnalyzeResult{Text: text})
+	}
+
+	b, err := io.ReadAll(req.Body)
 	req.Body.Close()
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error reading image bytes from request: %v", err)))
+	}
+
+	cacheCid := blobCacheCid(cid, b)
+
+	b, err = r.prepareForOCR(b, steps)
+	if err != nil {
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+		}
+		return e.JSON(http.StatusUnsupportedMediaType, makeErrorJson("could not decode image"))
+	}
+
+	imageText, err := r.getImageTextStream(ctx, bytes.NewReader(b), langs)
 	if err != nil {
 		r.logger.Error("error getting text from request body stream", "error", err)
 		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not get text from image"))
 	}
+	r.cacheText(cacheCid, langs, steps, imageText)
 
 	status = "ok"
 
@@ -131,33 +209,39 @@ func (r *Retina) handlePdq(e echo.Context) error {
 		return e.JSON(http.StatusBadRequest, makeErrorJson("could not bind request"))
 	}
 
-	cdnUrl := makeCdnUrl(req.Did, req.Cid)
+	cdnHost, err := r.cdnHost(cmp.Or(req.CdnHost, e.QueryParam("cdnHost")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+	}
+
+	if res, ok := r.cachedHash(req.Cid); ok {
+		status = "ok"
+		return e.JSON(http.StatusOK, res)
+	}
+
+	cdnUrl := makeCdnUrl(cdnHost, req.Did, req.Cid)
 	imageBytes, err := r.downloadImage(ctx, cdnUrl)
 	if err != nil {
 		if errors.Is(err, ErrImageNotFound) {
 			return e.JSON(http.StatusBadRequest, makeErrorJson("image not found"))
 		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+		}
 
 		r.logger.Error("error downloading image", "url", cdnUrl, "error", err)
 		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not download image"))
 	}
 
-	filePath, err := saveBytes(imageBytes)
-	if err != nil {
-		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not save image bytes to disk"))
-	}
-	defer func() {
-		if err := os.Remove(filePath); err != nil {
-			r.logger.Error("unable to delete image file", "error", err)
-		}
-	}()
-
-	hashRes, err := r.GetImageHash(ctx, filePath)
+	hashRes, err := r.GetImageHash(ctx, imageBytes)
 	if err != nil {
 		if errors.Is(err, ErrQualityTooLow) {
-			return e.JSON(http.StatusOK, PdqResult{
-				QualityTooLow: true,
-			})
+			res := PdqResult{QualityTooLow: true}
+			r.cacheHash(req.Cid, &res)
+			return e.JSON(http.StatusOK, res)
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
 		}
 		return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error getting image hash: %v", err)))
 	}
@@ -167,13 +251,16 @@ func (r *Retina) handlePdq(e echo.Context) error {
 		return e.JSON(http.StatusInternalServerError, makeErrorJson("unable to convert pdq hash to binary"))
 	}
 
-	status = "ok"
-
-	return e.JSON(http.StatusOK, PdqResult{
+	res := PdqResult{
 		Hash:          &hashRes,
 		Binary:        &binary,
 		QualityTooLow: false,
-	})
+	}
+	r.cacheHash(req.Cid, &res)
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, res)
 }
 
 func (r *Retina) handlePdqBlob(e echo.Context) error {
@@ -192,27 +279,112 @@ func (r *Retina) handlePdqBlob(e echo.Context) error {
 	if err != nil {
 		return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error reading image bytes from request: %v", err)))
 	}
+	checksums := GetChecksums(b)
+
+	cid := e.QueryParam("cid")
+	if res, ok := r.cachedHash(cid); ok {
+		res.Checksums = checksums
+		status = "ok"
+		return e.JSON(http.StatusOK, res)
+	}
+
+	hashRes, err := r.GetImageHash(ctx, b)
+	if err != nil {
+		if errors.Is(err, ErrQualityTooLow) {
+			res := PdqResult{QualityTooLow: true}
+			r.cacheHash(blobCacheCid(cid, b), &res)
+			res.Checksums = checksums
+			return e.JSON(http.StatusOK, res)
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+		}
+		return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error getting image hash: %v", err)))
+	}
 
-	filePath, err := saveBytes(b)
+	binary, err := strToBinary(hashRes)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not save image bytes to disk"))
+		return e.JSON(http.StatusInternalServerError, makeErrorJson("unable to convert pdq hash to binary"))
+	}
+
+	res := PdqResult{
+		Hash:          &hashRes,
+		Binary:        &binary,
+		QualityTooLow: false,
 	}
+	r.cacheHash(blobCacheCid(cid, b), &res)
+	res.Checksums = checksums
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, res)
+}
+
+type VideoPdqResult struct {
+	// Hash is the video level hash, made up of the bits set in most of the frames' hashes.
+	Hash          *string          `json:"hash,omitempty"`
+	Binary        *string          `json:"binary,omitempty"`
+	Frames        []VideoFrameHash `json:"frames,omitempty"`
+	QualityTooLow bool             `json:"qualityTooLow"`
+	// Checksums are only set for videos in the request body.
+	Checksums *Checksums `json:"checksums,omitempty"`
+}
+
+// handleVideoPdq hashes a video given either as the request body, or as a DID and CID in a JSON body,
+// which is fetched from the video CDN.
+func (r *Retina) handleVideoPdq(e echo.Context) error {
+	req := e.Request()
+	ctx := req.Context()
+
+	start := time.Now()
+
+	status := "error"
 	defer func() {
-		if err := os.Remove(filePath); err != nil {
-			r.logger.Error("unable to delete image file", "error", err)
-		}
+		imagesProcessed.WithLabelValues(status, "pdq-video").Inc()
+		requestTimeHist.WithLabelValues(status, "pdq-video").Observe(float64(time.Since(start).Seconds()))
 	}()
 
-	hashRes, err := r.GetImageHash(ctx, filePath)
+	var input string
+	var checksums *Checksums
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		var imgReq ImageRequest
+		if err := e.Bind(&imgReq); err != nil || imgReq.Did == "" || imgReq.Cid == "" {
+			return e.JSON(http.StatusBadRequest, makeErrorJson("could not bind request"))
+		}
+		input = makeVideoPlaylistUrl(imgReq.Did, imgReq.Cid)
+	} else {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error reading video bytes from request: %v", err)))
+		}
+		checksums = GetChecksums(b)
+		filePath, err := saveVideo(b)
+		if err != nil {
+			return e.JSON(http.StatusInternalServerError, makeErrorJson("could not save video bytes to disk"))
+		}
+		defer func() {
+			if err := os.Remove(filePath); err != nil {
+				r.logger.Error("unable to delete video file", "error", err)
+			}
+		}()
+		input = filePath
+	}
+
+	frames, err := r.GetVideoHashes(ctx, input)
 	if err != nil {
-		if errors.Is(err, ErrQualityTooLow) {
-			return e.JSON(http.StatusOK, PdqResult{
+		if errors.Is(err, ErrNoVideoFrames) {
+			return e.JSON(http.StatusOK, VideoPdqResult{
 				QualityTooLow: true,
+				Checksums:     checksums,
 			})
 		}
-		return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error getting image hash: %v", err)))
+		return e.JSON(http.StatusInternalServerError, makeErrorJson(fmt.Sprintf("error getting video hashes: %v", err)))
 	}
 
+	hashRes, err := videoHash(frames)
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, makeErrorJson("unable to combine frame hashes"))
+	}
 	binary, err := strToBinary(hashRes)
 	if err != nil {
 		return e.JSON(http.StatusInternalServerError, makeErrorJson("unable to convert pdq hash to binary"))
@@ -220,15 +392,195 @@ func (r *Retina) handlePdqBlob(e echo.Context) error {
 
 	status = "ok"
 
-	return e.JSON(http.StatusOK, PdqResult{
-		Hash:          &hashRes,
-		Binary:        &binary,
-		QualityTooLow: false,
+	return e.JSON(http.StatusOK, VideoPdqResult{
+		Hash:      &hashRes,
+		Binary:    &binary,
+		Frames:    frames,
+		Checksums: checksums,
 	})
 }
 
 var SupportedMimeTypes = map[string]bool{
 	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+	"image/avif": true,
+}
+
+// handleBarcodes decodes the QR codes and barcodes in an image given either as the request body, or
+// as a DID and CID in a JSON body, which is fetched from the CDN.
+func (r *Retina) handleBarcodes(e echo.Context) error {
+	start := time.Now()
+
+	status := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(status, "barcode").Inc()
+		requestTimeHist.WithLabelValues(status, "barcode").Observe(float64(time.Since(start).Seconds()))
+	}()
+
+	b, code, err := r.requestImage(e)
+	if err != nil {
+		return e.JSON(code, makeErrorJson(err.Error()))
+	}
+
+	if err := r.checkImageSize(b); errors.Is(err, ErrImageTooLarge) {
+		return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+	}
+	img, err := decodeImage(b)
+	if err != nil {
+		return e.JSON(http.StatusUnsupportedMediaType, makeErrorJson("could not decode image"))
+	}
+
+	barcodes, err := DecodeBarcodes(img)
+	if err != nil {
+		r.logger.Error("error decoding barcodes", "error", err)
+		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not decode barcodes"))
+	}
+	barcodesFound.Add(float64(len(barcodes)))
+	if barcodes == nil {
+		barcodes = []Barcode{}
+	}
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, BarcodeResult{Barcodes: barcodes})
+}
+
+// handleClassify runs the classifier model on an image given either as the request body, or as a
+// DID and CID in a JSON body, which is fetched from the CDN.
+func (r *Retina) handleClassify(e echo.Context) error {
+	ctx := e.Request().Context()
+
+	start := time.Now()
+
+	status := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(status, "classify").Inc()
+		requestTimeHist.WithLabelValues(status, "classify").Observe(float64(time.Since(start).Seconds()))
+	}()
+
+	if r.classifier == nil {
+		return e.JSON(http.StatusNotImplemented, makeErrorJson(ErrClassifierDisabled.Error()))
+	}
+
+	b, code, err := r.requestImage(e)
+	if err != nil {
+		return e.JSON(code, makeErrorJson(err.Error()))
+	}
+
+	res, err := r.Classify(ctx, b)
+	if err != nil {
+		if errors.Is(err, ErrDecodeImage) {
+			return e.JSON(http.StatusUnsupportedMediaType, makeErrorJson("could not decode image"))
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return e.JSON(http.StatusRequestEntityTooLarge, makeErrorJson(err.Error()))
+		}
+		r.logger.Error("error classifying image", "error", err)
+		return e.JSON(http.StatusInternalServerError, makeErrorJson("could not classify image"))
+	}
+	imagesClassified.WithLabelValues(res.Result).Inc()
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, res)
+}
+
+// requestImage reads the image given either as the request body, or as a DID and CID in a JSON
+// body, which is fetched from the CDN. If it can't, it returns the status to respond with.
+func (r *Retina) requestImage(e echo.Context) ([]byte, int, error) {
+	req := e.Request()
+
+	if !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if !supportedMimeType(req.Header.Get(echo.HeaderContentType)) {
+			return nil, http.StatusUnsupportedMediaType, errors.New("unsupported media type")
+		}
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("error reading image bytes from request: %v", err)
+		}
+		return b, http.StatusOK, nil
+	}
+
+	var imgReq ImageRequest
+	if err := e.Bind(&imgReq); err != nil || imgReq.Did == "" || imgReq.Cid == "" {
+		return nil, http.StatusBadRequest, errors.New("could not bind request")
+	}
+	cdnHost, err := r.cdnHost(cmp.Or(imgReq.CdnHost, e.QueryParam("cdnHost")))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	cdnUrl := makeCdnUrl(cdnHost, imgReq.Did, imgReq.Cid)
+	b, err := r.downloadImage(req.Context(), cdnUrl)
+	if err != nil {
+		if errors.Is(err, ErrImageNotFound) {
+			return nil, http.StatusBadRequest, errors.New("image not found")
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, err
+		}
+		r.logger.Error("error downloading image", "url", cdnUrl, "error", err)
+		return nil, http.StatusInternalServerError, errors.New("could not download image")
+	}
+	return b, http.StatusOK, nil
+}
+
+// requestBlob reads the blob given either as the request body, or as a DID and CID in a JSON body,
+// which is fetched from the owner's PDS. If it can't, it returns the status to respond with.
+func (r *Retina) requestBlob(e echo.Context) ([]byte, int, error) {
+	req := e.Request()
+
+	if !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("error reading blob bytes from request: %v", err)
+		}
+		return b, http.StatusOK, nil
+	}
+
+	var imgReq ImageRequest
+	if err := e.Bind(&imgReq); err != nil || imgReq.Did == "" || imgReq.Cid == "" {
+		return nil, http.StatusBadRequest, errors.New("could not bind request")
+	}
+	b, err := r.downloadBlob(req.Context(), imgReq.Did, imgReq.Cid)
+	if err != nil {
+		if errors.Is(err, ErrImageNotFound) {
+			return nil, http.StatusBadRequest, errors.New("blob not found")
+		}
+		if errors.Is(err, ErrImageTooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, err
+		}
+		r.logger.Error("error downloading blob", "did", imgReq.Did, "cid", imgReq.Cid, "error", err)
+		return nil, http.StatusInternalServerError, errors.New("could not download blob")
+	}
+	return b, http.StatusOK, nil
+}
+
+// handleMetadata reads the metadata of an image given either as the request body, or as a DID and
+// CID in a JSON body, which is fetched from the owner's PDS.
+func (r *Retina) handleMetadata(e echo.Context) error {
+	start := time.Now()
+
+	status := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(status, "metadata").Inc()
+		requestTimeHist.WithLabelValues(status, "metadata").Observe(float64(time.Since(start).Seconds()))
+	}()
+
+	b, code, err := r.requestBlob(e)
+	if err != nil {
+		return e.JSON(code, makeErrorJson(err.Error()))
+	}
+
+	md, err := GetImageMetadata(b)
+	if err != nil {
+		return e.JSON(http.StatusUnsupportedMediaType, makeErrorJson("could not decode image"))
+	}
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, md)
 }
 
 func supportedMimeType(contentType string) bool {
@@ -238,3 +590,13 @@ func supportedMimeType(contentType string) bool {
 	_, ok := SupportedMimeTypes[contentType]
 	return ok
 }
+
+type SupportedTypesResult struct {
+	MimeTypes []string `json:"mimeTypes"`
+}
+
+func (r *Retina) handleSupportedTypes(e echo.Context) error {
+	return e.JSON(http.StatusOK, SupportedTypesResult{
+		MimeTypes: slices.Sorted(maps.Keys(SupportedMimeTypes)),
+	})
+}
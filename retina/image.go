@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"time"
 )
 
 var (
 	ErrImageNotFound      = errors.New("image not found")
 	ErrImageBadStatusCode = errors.New("received bad status code when downloading image")
+	ErrCdnHostNotAllowed  = errors.New("cdn host is not allowed")
 )
 
+const DefaultCdnHost = "https://cdn.bsky.app"
+
 func (r *Retina) downloadImage(ctx context.Context, imageUrl string) ([]byte, error) {
 	start := time.Now()
 	status := "error"
@@ -46,10 +52,16 @@ func (r *Retina) downloadImage(ctx context.Context, imageUrl string) ([]byte, er
 		return nil, fmt.Errorf("%w: status code was %d", ErrImageBadStatusCode, resp.StatusCode)
 	}
 
-	b, err := io.ReadAll(resp.Body)
+	if resp.ContentLength > r.limits.MaxUploadSize {
+		return nil, fmt.Errorf("%w: download is %d bytes, the most is %d", ErrImageTooLarge, resp.ContentLength, r.limits.MaxUploadSize)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, r.limits.MaxUploadSize+1))
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(b)) > r.limits.MaxUploadSize {
+		return nil, fmt.Errorf("%w: download is over %d bytes", ErrImageTooLarge, r.limits.MaxUploadSize)
+	}
 
 	status = "ok"
 	return b, nil
@@ -69,6 +81,28 @@ func saveBytes(bytes []byte) (string, error) {
 	return file.Name(), nil
 }
 
-func makeCdnUrl(did, cid string) string {
-	return fmt.Sprintf("https://cdn.bsky.app/img/feed_thumbnail/plain/%s/%s@jpeg", did, cid)
+func makeCdnUrl(host, did, cid string) string {
+	return fmt.Sprintf("%s/img/feed_thumbnail/plain/%s/%s@jpeg", host, did, cid)
+}
+
+// cdnHost returns the CDN host a request's images are fetched from. Requests can only pick one
+// of the allowed hosts, so that they can't point retina at anything else.
+func (r *Retina) cdnHost(override string) (string, error) {
+	override = strings.TrimSuffix(override, "/")
+	if override == "" || override == r.defaultCdnHost {
+		return r.defaultCdnHost, nil
+	}
+	if !slices.Contains(r.allowedCdnHosts, override) {
+		return "", fmt.Errorf("%w: %s", ErrCdnHostNotAllowed, override)
+	}
+	return override, nil
+}
+
+// parseCdnHost checks that a configured CDN host is a base URL images can be fetched from.
+func parseCdnHost(host string) (string, error) {
+	u, err := url.Parse(host)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid cdn host %q, expected a url like %s", host, DefaultCdnHost)
+	}
+	return strings.TrimSuffix(host, "/"), nil
 }
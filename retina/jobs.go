@@ -0,0 +1,253 @@
+package retina
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var ErrCallbackHostNotAllowed = errors.New("callback host is not allowed")
+
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+)
+
+const (
+	// jobRetention is how long finished jobs can be fetched for.
+	jobRetention = time.Hour
+	// maxJobs caps the jobs kept at once, finished or not.
+	maxJobs = 10_000
+
+	callbackAttempts = 3
+
+	// jobShutdownGrace is how long running jobs get to finish on shutdown before they're cancelled.
+	jobShutdownGrace = 30 * time.Second
+)
+
+// Job is a batch of images analyzed and hashed in the background, for callers that can't wait on
+// OCR. Its results are sent to its callback URL when it's done, and can be fetched until
+// jobRetention after.
+type Job struct {
+	ID          string            `json:"id"`
+	Status      string            `json:"status"`
+	Results     []BatchItemResult `json:"results,omitempty"`
+	CallbackURL string            `json:"callbackUrl,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	FinishedAt  *time.Time        `json:"finishedAt,omitempty"`
+}
+
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: map[string]*Job{}}
+}
+
+// add reports whether there was room for the job, after dropping finished jobs past retention.
+func (s *jobStore) add(job *Job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, j := range s.jobs {
+		if j.FinishedAt != nil && now.Sub(*j.FinishedAt) > jobRetention {
+			delete(s.jobs, id)
+		}
+	}
+	if len(s.jobs) >= maxJobs {
+		return false
+	}
+	s.jobs[job.ID] = job
+	jobsActive.Set(float64(len(s.jobs)))
+	return true
+}
+
+// get returns a copy of the job, so that it can be serialized while the job carries on.
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *j, true
+}
+
+func (s *jobStore) update(id string, f func(*Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if j, ok := s.jobs[id]; ok {
+		f(j)
+	}
+}
+
+// handleCreateJob takes the same requests as handleBatch, but runs them in the background and
+// responds with the job's ID straight away.
+func (r *Retina) handleCreateJob(e echo.Context) error {
+	callbackURL := e.QueryParam("callbackUrl")
+	if callbackURL != "" {
+		if err := r.checkCallbackURL(callbackURL); err != nil {
+			return e.JSON(http.StatusBadRequest, makeErrorJson(err.Error()))
+		}
+	}
+
+	items, err := r.batchItems(e)
+	if err != nil {
+		return e.JSON(batchItemsStatus(err), makeErrorJson(err.Error()))
+	}
+
+	job := &Job{
+		ID:          uuid.NewString(),
+		Status:      JobPending,
+		CallbackURL: callbackURL,
+		CreatedAt:   time.Now(),
+	}
+	if !r.jobs.add(job) {
+		return e.JSON(http.StatusServiceUnavailable, makeErrorJson("too many jobs, try again later"))
+	}
+
+	r.jobsWg.Add(1)
+	go func() {
+		defer r.jobsWg.Done()
+		r.runJob(job.ID, items)
+	}()
+
+	return e.JSON(http.StatusAccepted, map[string]string{"id": job.ID})
+}
+
+// checkCallbackURL checks that a job's callback goes to one of the allowed hosts, so that jobs
+// can't point retina at anything else.
+func (r *Retina) checkCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("callbackUrl must be an http or https url")
+	}
+	if !slices.Contains(r.allowedCallbackHosts, u.Host) {
+		return fmt.Errorf("%w: %s", ErrCallbackHostNotAllowed, u.Host)
+	}
+	return nil
+}
+
+func (r *Retina) handleGetJob(e echo.Context) error {
+	job, ok := r.jobs.get(e.Param("id"))
+	if !ok {
+		return e.JSON(http.StatusNotFound, makeErrorJson("job not found"))
+	}
+	return e.JSON(http.StatusOK, job)
+}
+
+func (r *Retina) runJob(id string, items []*batchItem) {
+	start := time.Now()
+	r.jobs.update(id, func(j *Job) { j.Status = JobRunning })
+
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.processBatchItem(r.jobsCtx, item)
+		}()
+	}
+	wg.Wait()
+
+	results := make([]BatchItemResult, len(items))
+	for i, item := range items {
+		results[i] = *item.result
+	}
+
+	var job Job
+	r.jobs.update(id, func(j *Job) {
+		now := time.Now()
+		j.Status = JobDone
+		j.Results = results
+		j.FinishedAt = &now
+		job = *j
+	})
+	jobTimeHist.Observe(time.Since(start).Seconds())
+
+	if job.CallbackURL != "" {
+		r.sendJobCallback(&job)
+	}
+}
+
+// sendJobCallback posts the finished job to its callback URL, retrying a few times since the
+// results are otherwise only there to be polled for.
+func (r *Retina) sendJobCallback(job *Job) {
+	body, err := json.Marshal(job)
+	if err != nil {
+		r.logger.Error("failed to marshal job for callback", "id", job.ID, "error", err)
+		return
+	}
+
+	status := "error"
+	defer func() {
+		jobCallbacks.WithLabelValues(status).Inc()
+	}()
+
+	for attempt := 1; attempt <= callbackAttempts; attempt++ {
+		err := r.postJobCallback(job, body)
+		if err == nil {
+			status = "ok"
+			return
+		}
+		r.logger.Warn("job callback failed", "id", job.ID, "attempt", attempt, "error", err)
+
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-r.jobsCtx.Done():
+			return
+		}
+	}
+	r.logger.Error("giving up on job callback", "id", job.ID, "url", job.CallbackURL)
+}
+
+func (r *Retina) postJobCallback(job *Job, body []byte) error {
+	req, err := http.NewRequestWithContext(r.jobsCtx, http.MethodPost, job.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Retina-Job-Id", job.ID)
+
+	resp, err := r.callbackClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// waitForJobs gives the running jobs a chance to finish and send their callbacks, since they're
+// lost on shutdown, before cancelling them.
+func (r *Retina) waitForJobs() {
+	done := make(chan struct{})
+	go func() {
+		r.jobsWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(jobShutdownGrace):
+		r.logger.Warn("cancelling unfinished jobs")
+	}
+	r.cancelJobs()
+	<-done
+}
@@ -0,0 +1,86 @@
+package retina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DefaultMaxUploadSize     = 100 << 20
+	DefaultMaxImageDimension = 16384
+	DefaultMaxImagePixels    = 50_000_000
+)
+
+var ErrImageTooLarge = errors.New("image is too large")
+
+// LimitArgs cap how much retina reads and decodes for a request, so that one crafted image can't
+// run it out of memory.
+type LimitArgs struct {
+	// MaxUploadSize is the most bytes a request body, or an image downloaded for one, can be.
+	MaxUploadSize int64
+	// MaxImageDimension is the most pixels wide or tall an image can be.
+	MaxImageDimension int
+	// MaxImagePixels is the most pixels an image can have in all.
+	MaxImagePixels int64
+}
+
+func (a *LimitArgs) setDefaults() {
+	if a.MaxUploadSize <= 0 {
+		a.MaxUploadSize = DefaultMaxUploadSize
+	}
+	if a.MaxImageDimension <= 0 {
+		a.MaxImageDimension = DefaultMaxImageDimension
+	}
+	if a.MaxImagePixels <= 0 {
+		a.MaxImagePixels = DefaultMaxImagePixels
+	}
+}
+
+// checkImageSize checks the dimensions in the image's header against the limits. Decoding
+// allocates all of an image's pixels up front, so a small image that claims to be huge has to be
+// rejected before it's decoded.
+func (r *Retina) checkImageSize(b []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodeImage, err)
+	}
+	if cfg.Width > r.limits.MaxImageDimension || cfg.Height > r.limits.MaxImageDimension {
+		return fmt.Errorf("%w: %dx%d is over the limit of %d pixels wide or tall", ErrImageTooLarge, cfg.Width, cfg.Height, r.limits.MaxImageDimension)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > r.limits.MaxImagePixels {
+		return fmt.Errorf("%w: %dx%d is over the limit of %d pixels", ErrImageTooLarge, cfg.Width, cfg.Height, r.limits.MaxImagePixels)
+	}
+	return nil
+}
+
+// bodyLimit reads request bodies up to the max upload size before handlers do, and responds with
+// a 413 to larger ones, so that none of the handlers have to.
+func (r *Retina) bodyLimit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			req := e.Request()
+			tooLarge := makeErrorJson(fmt.Sprintf("request body is too large, the most is %d bytes", r.limits.MaxUploadSize))
+			if req.ContentLength > r.limits.MaxUploadSize {
+				return e.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+			}
+
+			b, err := io.ReadAll(http.MaxBytesReader(e.Response(), req.Body, r.limits.MaxUploadSize))
+			req.Body.Close()
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					return e.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+				}
+				return e.JSON(http.StatusBadRequest, makeErrorJson(fmt.Sprintf("error reading request body: %v", err)))
+			}
+			req.Body = io.NopCloser(bytes.NewReader(b))
+			return next(e)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package retina
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/rwcarlsen/goexif/exif"
+	"github.com/rwcarlsen/goexif/tiff"
+)
+
+// maxXMPSize caps how much a compressed XMP packet is inflated to.
+const maxXMPSize = 1 << 20
+
+var (
+	jpegExifHeader = []byte("Exif\x00\x00")
+	jpegXMPHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	jpegICCHeader  = []byte("ICC_PROFILE\x00")
+)
+
+// ImageMetadata is what an image says about where it came from, for provenance analysis.
+type ImageMetadata struct {
+	Format        string        `json:"format"`
+	Width         int           `json:"width"`
+	Height        int           `json:"height"`
+	Exif          *ExifMetadata `json:"exif,omitempty"`
+	HasICCProfile bool          `json:"hasIccProfile"`
+	// XMP is the embedded XMP packet, as is.
+	XMP string `json:"xmp,omitempty"`
+}
+
+type ExifMetadata struct {
+	// CaptureTime is when the photo was taken, or else last changed, as written in the EXIF, which
+	// has no time zone.
+	CaptureTime string       `json:"captureTime,omitempty"`
+	GPS         *GPSLocation `json:"gps,omitempty"`
+	Software    string       `json:"software,omitempty"`
+	CameraMake  string       `json:"cameraMake,omitempty"`
+	CameraModel string       `json:"cameraModel,omitempty"`
+	// Fields are all of the EXIF fields that could be read, by name.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Error is set if the EXIF couldn't be read, or only partly.
+	Error string `json:"error,omitempty"`
+}
+
+type GPSLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// rawMetadata are the metadata blocks found in an image's container.
+type rawMetadata struct {
+	exif []byte
+	icc  []byte
+	xmp  []byte
+}
+
+// GetImageMetadata reads the EXIF, ICC profile and XMP embedded in a JPEG, PNG or WebP image. Other
+// formats only get their dimensions.
+func GetImageMetadata(b []byte) (*ImageMetadata, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	md := &ImageMetadata{Format: format, Width: cfg.Width, Height: cfg.Height}
+
+	var raw rawMetadata
+	switch format {
+	case "jpeg":
+		raw = jpegMetadata(b)
+	case "png":
+		raw = pngMetadata(b)
+	case "webp":
+		raw = webpMetadata(b)
+	}
+
+	if raw.exif != nil {
+		md.Exif = parseExif(raw.exif)
+	}
+	md.HasICCProfile = len(raw.icc) > 0
+	md.XMP = string(raw.xmp)
+	return md, nil
+}
+
+func parseExif(b []byte) *ExifMetadata {
+	em := &ExifMetadata{}
+
+	x, err := exif.Decode(bytes.NewReader(b))
+	if err != nil {
+		em.Error = err.Error()
+	}
+	if x == nil {
+		return em
+	}
+
+	em.Fields = map[string]string{}
+	_ = x.Walk(exifWalker(func(name exif.FieldName, tag *tiff.Tag) {
+		switch name {
+		// Maker notes are large, proprietary binary blobs, and the pointers are only offsets.
+		case exif.MakerNote, exif.ExifIFDPointer, exif.GPSInfoIFDPointer, exif.InteroperabilityIFDPointer:
+			return
+		}
+		em.Fields[string(name)] = exifTagString(tag)
+	}))
+
+	for _, name := range []exif.FieldName{exif.DateTimeOriginal, exif.DateTime} {
+		if v := em.Fields[string(name)]; v != "" {
+			em.CaptureTime = v
+			break
+		}
+	}
+	em.Software = em.Fields[string(exif.Software)]
+	em.CameraMake = em.Fields[string(exif.Make)]
+	em.CameraModel = em.Fields[string(exif.Model)]
+	if lat, long, err := x.LatLong(); err == nil {
+		em.GPS = &GPSLocation{Latitude: lat, Longitude: long}
+	}
+	return em
+}
+
+type exifWalker func(exif.FieldName, *tiff.Tag)
+
+func (w exifWalker) Walk(name exif.FieldName, tag *tiff.Tag) error {
+	w(name, tag)
+	return nil
+}
+
+func exifTagString(tag *tiff.Tag) string {
+	if tag.Format() == tiff.StringVal {
+		if s, err := tag.StringVal(); err == nil {
+			return strings.TrimRight(s, "\x00 ")
+		}
+	}
+	return tag.String()
+}
+
+// jpegMetadata reads the APP segments before the image data.
+func jpegMetadata(b []byte) rawMetadata {
+	var raw rawMetadata
+	for i := 2; i+4 <= len(b); {
+		if b[i] != 0xff {
+			return raw
+		}
+		marker := b[i+1]
+		switch {
+		case marker == 0xff:
+			// Padding before a marker.
+			i++
+			continue
+		case marker == 0x01 || marker == 0xd8 || (marker >= 0xd0 && marker <= 0xd7):
+			// Markers without a length.
+			i += 2
+			continue
+		case marker == 0xda || marker == 0xd9:
+			// The metadata all comes before the start of scan.
+			return raw
+		}
+
+		n := int(binary.BigEndian.Uint16(b[i+2:]))
+		if n < 2 || i+2+n > len(b) {
+			return raw
+		}
+		seg := b[i+4 : i+2+n]
+		switch {
+		case marker == 0xe1 && bytes.HasPrefix(seg, jpegExifHeader) && raw.exif == nil:
+			raw.exif = seg
+		case marker == 0xe1 && bytes.HasPrefix(seg, jpegXMPHeader):
+			raw.xmp = seg[len(jpegXMPHeader):]
+		case marker == 0xe2 && bytes.HasPrefix(seg, jpegICCHeader) && len(seg) > len(jpegICCHeader)+2:
+			// Profiles can be split across segments, after a sequence number and count.
+			raw.icc = append(raw.icc, seg[len(jpegICCHeader)+2:]...)
+		}
+		i += 2 + n
+	}
+	return raw
+}
+
+// pngMetadata reads the eXIf, iCCP and XMP iTXt chunks.
+func pngMetadata(b []byte) rawMetadata {
+	var raw rawMetadata
+	for i := 8; i+12 <= len(b); {
+		n := int(binary.BigEndian.Uint32(b[i:]))
+		if n < 0 || n > len(b)-i-12 {
+			return raw
+		}
+		data := b[i+8 : i+8+n]
+		switch string(b[i+4 : i+8]) {
+		case "eXIf":
+			raw.exif = data
+		case "iCCP":
+			raw.icc = data
+		case "iTXt":
+			if xmp := pngXMP(data); xmp != nil {
+				raw.xmp = xmp
+			}
+		case "IEND":
+			return raw
+		}
+		i += 12 + n
+	}
+	return raw
+}
+
+// pngXMP returns the text of an iTXt chunk if it's the XMP packet.
+func pngXMP(data []byte) []byte {
+	keyword, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok || string(keyword) != "XML:com.adobe.xmp" || len(rest) < 2 {
+		return nil
+	}
+	compressed := rest[0] == 1
+	// Skip the compression method, language tag and translated keyword.
+	_, rest, ok = bytes.Cut(rest[2:], []byte{0})
+	if !ok {
+		return nil
+	}
+	_, text, ok := bytes.Cut(rest, []byte{0})
+	if !ok {
+		return nil
+	}
+	if !compressed {
+		return text
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(text))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+	inflated, err := io.ReadAll(io.LimitReader(zr, maxXMPSize))
+	if err != nil {
+		return nil
+	}
+	return inflated
+}
+
+// webpMetadata reads the EXIF, ICCP and XMP chunks of the RIFF container.
+func webpMetadata(b []byte) rawMetadata {
+	var raw rawMetadata
+	if len(b) < 12 || string(b[:4]) != "RIFF" || string(b[8:12]) != "WEBP" {
+		return raw
+	}
+	for i := 12; i+8 <= len(b); {
+		n := int(binary.LittleEndian.Uint32(b[i+4:]))
+		if n < 0 || n > len(b)-i-8 {
+			return raw
+		}
+		data := b[i+8 : i+8+n]
+		switch string(b[i : i+4]) {
+		case "EXIF":
+			raw.exif = data
+		case "ICCP":
+			raw.icc = data
+		case "XMP ":
+			raw.xmp = data
+		}
+		// Chunks are padded to an even size.
+		i += 8 + n + n%2
+	}
+	return raw
+}
+
+// downloadBlob fetches the original blob from the PDS of the DID, since the CDN's images are
+// re-encoded without their metadata.
+func (r *Retina) downloadBlob(ctx context.Context, did, cid string) ([]byte, error) {
+	parsed, err := syntax.ParseDID(did)
+	if err != nil {
+		return nil, fmt.Errorf("invalid did: %w", err)
+	}
+	ident, err := r.directory.LookupDID(ctx, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve did: %w", err)
+	}
+	pds := ident.PDSEndpoint()
+	if pds == "" {
+		return nil, fmt.Errorf("did document has no pds")
+	}
+
+	q := url.Values{}
+	q.Set("did", did)
+	q.Set("cid", cid)
+	return r.downloadImage(ctx, fmt.Sprintf("%s/xrpc/com.atproto.sync.getBlob?%s", strings.TrimSuffix(pds, "/"), q.Encode()))
+}
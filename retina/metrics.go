@@ -25,4 +25,42 @@ var (
 		Help:    "histogram of pdq hashing times",
 		Buckets: prometheus.ExponentialBucketsRange(0.01, 60, 20),
 	})
+	videoHashHist = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "retina_video_hash_time",
+		Help:    "histogram of video hashing times, including extracting frames",
+		Buckets: prometheus.ExponentialBucketsRange(0.1, 300, 20),
+	}, []string{"status"})
+	apiRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retina_api_requests",
+		Help: "total number of api requests by the name of the api key they used and whether they were authorized",
+	}, []string{"key", "status"})
+	videoFramesHashed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "retina_video_frames_hashed",
+		Help: "total number of video frames hashed",
+	})
+	jobsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "retina_jobs",
+		Help: "number of async jobs being run or kept for their results",
+	})
+	jobTimeHist = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "retina_job_time",
+		Help:    "histogram of async job run times",
+		Buckets: prometheus.ExponentialBucketsRange(0.01, 300, 20),
+	})
+	barcodesFound = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "retina_barcodes_found",
+		Help: "total number of qr codes and barcodes decoded from images",
+	})
+	imagesClassified = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retina_images_classified",
+		Help: "total number of images classified by the class they scored highest for",
+	}, []string{"class"})
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retina_cache_lookups",
+		Help: "total number of result cache lookups by kind of result and where they were found, if anywhere",
+	}, []string{"kind", "result"})
+	jobCallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retina_job_callbacks",
+		Help: "total number of async job callbacks by whether they were delivered",
+	}, []string{"status"})
 )
@@ -3,19 +3,59 @@ package retina
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
-func (r *Retina) getImageTextStream(ctx context.Context, img io.Reader) (string, error) {
+// OCRArgs configure tesseract, whether it's run in process or exec'd.
+type OCRArgs struct {
+	// InProcess runs tesseract through libtesseract, with a client per concurrent OCR, instead of
+	// exec'ing it per image. Retina must be built with the tesseract build tag.
+	InProcess bool
+	// Languages are the tesseract languages to recognize when a request doesn't ask for any, e.g.
+	// eng. Tesseract's default is used when empty.
+	Languages []string
+	// PageSegMode is tesseract's page segmentation mode, or tesseract's default when zero.
+	PageSegMode int
+	// DPI is the resolution tesseract assumes images are at, or tesseract's guess when zero.
+	DPI int
+	// Preprocess are the preprocessing steps run on images before OCR when a request doesn't ask
+	// for any, see PreprocessSteps.
+	Preprocess []string
+}
+
+var ErrUnsupportedLanguage = errors.New("ocr language is not installed")
+
+// getImageTextStream runs OCR on the image in the languages, or the default languages if none are
+// given.
+func (r *Retina) getImageTextStream(ctx context.Context, img io.Reader, langs []string) (string, error) {
+	if len(langs) == 0 {
+		langs = r.ocrArgs.Languages
+	}
+
 	if err := r.ocrSemaphore.Acquire(ctx, 1); err != nil {
 		return "", fmt.Errorf("error acquiring semaphore lock: %w", err)
 	}
 	defer r.ocrSemaphore.Release(1)
 
-	cmd := exec.CommandContext(ctx, "tesseract", "stdin", "stdout")
+	if r.tesseract != nil {
+		b, err := io.ReadAll(img)
+		if err != nil {
+			return "", fmt.Errorf("error reading image: %w", err)
+		}
+		text, err := r.tesseract.text(ctx, b, langs)
+		if err != nil {
+			r.logger.Error("error running tesseract in process", "error", err)
+			return "", err
+		}
+		return strings.TrimSpace(text), nil
+	}
+
+	cmd := exec.CommandContext(ctx, "tesseract", append([]string{"stdin", "stdout"}, r.ocrArgs.execArgs(langs)...)...)
 	cmd.Stdin = img
 
 	out := &bytes.Buffer{}
@@ -32,3 +72,64 @@ func (r *Retina) getImageTextStream(ctx context.Context, img io.Reader) (string,
 
 	return strings.TrimSpace(out.String()), nil
 }
+
+// execArgs are the tesseract command's flags for the args and languages.
+func (a *OCRArgs) execArgs(langs []string) []string {
+	var args []string
+	if len(langs) > 0 {
+		args = append(args, "-l", strings.Join(langs, "+"))
+	}
+	if a.PageSegMode != 0 {
+		args = append(args, "--psm", strconv.Itoa(a.PageSegMode))
+	}
+	if a.DPI != 0 {
+		args = append(args, "--dpi", strconv.Itoa(a.DPI))
+	}
+	return args
+}
+
+// parseOCRLanguages parses languages as tesseract takes them, e.g. jpn+eng, and checks that they're
+// installed. Commas are accepted as well as pluses, and no languages means the default ones.
+func (r *Retina) parseOCRLanguages(langs string) ([]string, error) {
+	var parsed []string
+	for _, lang := range strings.FieldsFunc(langs, func(c rune) bool { return c == '+' || c == ',' }) {
+		lang = strings.TrimSpace(lang)
+		if lang == "" {
+			continue
+		}
+		if r.installedLanguages != nil && !r.installedLanguages[lang] {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+		}
+		parsed = append(parsed, lang)
+	}
+	return parsed, nil
+}
+
+// listInstalledLanguages returns the language packs tesseract has installed.
+func (r *Retina) listInstalledLanguages(ctx context.Context) (map[string]bool, error) {
+	var langs []string
+	if r.tesseract != nil {
+		var err error
+		if langs, err = r.tesseract.languages(); err != nil {
+			return nil, err
+		}
+	} else {
+		out, err := exec.CommandContext(ctx, "tesseract", "--list-langs").Output()
+		if err != nil {
+			return nil, fmt.Errorf("error listing tesseract languages: %w", err)
+		}
+		// The first line says where the languages were found.
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		for _, line := range lines[1:] {
+			langs = append(langs, strings.TrimSpace(line))
+		}
+	}
+
+	installed := map[string]bool{}
+	for _, lang := range langs {
+		if lang != "" {
+			installed[lang] = true
+		}
+	}
+	return installed, nil
+}
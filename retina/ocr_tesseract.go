@@ -0,0 +1,101 @@
+//go:build tesseract
+
+package retina
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+// tesseractPool holds a libtesseract client for each OCR that can run at once, since a client
+// can't be used concurrently and is expensive to set up per image.
+type tesseractPool struct {
+	clients chan *tesseractClient
+}
+
+// tesseractClient remembers the languages its client was last set to, since changing them
+// reinitializes tesseract.
+type tesseractClient struct {
+	*gosseract.Client
+	langs string
+}
+
+func newTesseractPool(args *OCRArgs, size int64) (*tesseractPool, error) {
+	p := &tesseractPool{clients: make(chan *tesseractClient, size)}
+	for range size {
+		client := &tesseractClient{Client: gosseract.NewClient()}
+		if err := configureTesseract(client, args); err != nil {
+			client.Close()
+			p.close()
+			return nil, err
+		}
+		p.clients <- client
+	}
+	return p, nil
+}
+
+func configureTesseract(client *tesseractClient, args *OCRArgs) error {
+	if err := client.setLanguages(args.Languages); err != nil {
+		return err
+	}
+	if args.PageSegMode != 0 {
+		if err := client.SetPageSegMode(gosseract.PageSegMode(args.PageSegMode)); err != nil {
+			return fmt.Errorf("error setting tesseract page segmentation mode: %w", err)
+		}
+	}
+	if args.DPI != 0 {
+		if err := client.SetVariable("user_defined_dpi", strconv.Itoa(args.DPI)); err != nil {
+			return fmt.Errorf("error setting tesseract dpi: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *tesseractClient) setLanguages(langs []string) error {
+	joined := strings.Join(langs, "+")
+	if len(langs) == 0 || joined == c.langs {
+		return nil
+	}
+	if err := c.SetLanguage(langs...); err != nil {
+		return fmt.Errorf("error setting tesseract languages: %w", err)
+	}
+	c.langs = joined
+	return nil
+}
+
+func (p *tesseractPool) text(ctx context.Context, img []byte, langs []string) (string, error) {
+	var client *tesseractClient
+	select {
+	case client = <-p.clients:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { p.clients <- client }()
+
+	if err := client.setLanguages(langs); err != nil {
+		return "", err
+	}
+	if err := client.SetImageFromBytes(img); err != nil {
+		return "", fmt.Errorf("error setting tesseract image: %w", err)
+	}
+	return client.Text()
+}
+
+func (p *tesseractPool) languages() ([]string, error) {
+	return gosseract.GetAvailableLanguages()
+}
+
+func (p *tesseractPool) close() {
+	for {
+		select {
+		case client := <-p.clients:
+			client.Close()
+		default:
+			return
+		}
+	}
+}
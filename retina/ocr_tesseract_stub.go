@@ -0,0 +1,27 @@
+//go:build !tesseract
+
+package retina
+
+import (
+	"context"
+	"errors"
+)
+
+var errTesseractNotBuilt = errors.New("retina was built without the tesseract build tag, so ocr can't run in process")
+
+// tesseractPool is only available with the tesseract build tag, which needs libtesseract.
+type tesseractPool struct{}
+
+func newTesseractPool(args *OCRArgs, size int64) (*tesseractPool, error) {
+	return nil, errTesseractNotBuilt
+}
+
+func (p *tesseractPool) text(ctx context.Context, img []byte, langs []string) (string, error) {
+	return "", errTesseractNotBuilt
+}
+
+func (p *tesseractPool) languages() ([]string, error) {
+	return nil, errTesseractNotBuilt
+}
+
+func (p *tesseractPool) close() {}
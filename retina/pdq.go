@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -19,7 +20,9 @@ var (
 	ErrQualityTooLow    = errors.New("pdq hash quality was too low")
 )
 
-func (r *Retina) GetImageHash(ctx context.Context, filepath string) (string, error) {
+// GetImageHash returns the image's PDQ hash, hashing it in process unless retina was configured to
+// exec the pdq-photo-hasher binary.
+func (r *Retina) GetImageHash(ctx context.Context, imageBytes []byte) (string, error) {
 	start := time.Now()
 	status := "error"
 	defer func() {
@@ -28,39 +31,83 @@ func (r *Retina) GetImageHash(ctx context.Context, filepath string) (string, err
 		}
 	}()
 
-	cmd := exec.CommandContext(ctx, r.pdqPath, filepath)
+	if err := r.checkImageSize(imageBytes); err != nil {
+		return "", err
+	}
+
+	var hash string
+	var quality int
+	var err error
+	if r.pdqExec {
+		hash, quality, err = r.execImageHash(ctx, imageBytes)
+	} else {
+		hash, quality, err = inProcessImageHash(imageBytes)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if quality < 50 { // recommended minimum quality of pdq hashes
+		status = "quality_too_low"
+		return "", fmt.Errorf("%w. quality was %d", ErrQualityTooLow, quality)
+	}
+
+	status = "ok"
+
+	return hash, nil
+}
+
+func inProcessImageHash(imageBytes []byte) (string, int, error) {
+	img, err := decodeImage(imageBytes)
+	if err != nil {
+		return "", 0, err
+	}
+	return pdqHashImage(img)
+}
+
+// execImageHash hashes the image with pdq-photo-hasher, which reads it from a temp file.
+func (r *Retina) execImageHash(ctx context.Context, imageBytes []byte) (string, int, error) {
+	imageBytes, err := toToolFormat(imageBytes)
+	if err != nil {
+		return "", 0, err
+	}
+
+	filePath, err := saveBytes(imageBytes)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not save image bytes to disk: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(filePath); err != nil {
+			r.logger.Error("unable to delete image file", "error", err)
+		}
+	}()
+
+	cmd := exec.CommandContext(ctx, r.pdqPath, filePath)
 
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
 	if err := cmd.Run(); err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	output := stdout.String()
 	if len(output) == 0 {
-		return "", ErrEmptyPdqResponse
+		return "", 0, ErrEmptyPdqResponse
 	}
 
 	respParts := strings.Split(output, ",")
 	if len(respParts) != 3 {
-		return "", ErrBadPdqResponse
+		return "", 0, ErrBadPdqResponse
 	}
 
 	qualitystr := respParts[1]
 	quality, err := strconv.ParseInt(qualitystr, 10, 64)
 	if err != nil {
-		return "", ErrInvalidPdqFloat
+		return "", 0, ErrInvalidPdqFloat
 	}
 
-	if quality < 50 { // recommended minimum quality of pdq hashes
-		status = "quality_too_low"
-		return "", fmt.Errorf("%w. quality was %d", ErrQualityTooLow, quality)
-	}
-
-	status = "ok"
-
-	return respParts[0], nil
+	return respParts[0], int(quality), nil
 }
 
 func strToBinary(input string) (string, error) {
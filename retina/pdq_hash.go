@@ -0,0 +1,191 @@
+package retina
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+	"strings"
+)
+
+// A port of the reference PDQ implementation from facebook/ThreatExchange (pdq/cpp/hashing), so
+// that images can be hashed without shelling out to pdq-photo-hasher. It hashes the image at its
+// full resolution like the photo hasher does, and produces the same hashes up to float rounding.
+
+const (
+	pdqLumaFromR = 0.299
+	pdqLumaFromG = 0.587
+	pdqLumaFromB = 0.114
+
+	pdqJaroszWindowDim = 64
+	pdqJaroszReps      = 2
+	pdqDctDim          = 16
+)
+
+// pdqDctMatrix is the 16x64 DCT matrix, skipping the DC row.
+var pdqDctMatrix = func() [pdqDctDim][pdqJaroszWindowDim]float32 {
+	var d [pdqDctDim][pdqJaroszWindowDim]float32
+	scale := math.Sqrt(2.0 / pdqJaroszWindowDim)
+	for i := range pdqDctDim {
+		for j := range pdqJaroszWindowDim {
+			d[i][j] = float32(scale * math.Cos((math.Pi/2/pdqJaroszWindowDim)*float64(i+1)*float64(2*j+1)))
+		}
+	}
+	return d
+}()
+
+// pdqHashImage returns the image's PDQ hash as hex, and its quality from 0 to 100.
+func pdqHashImage(img image.Image) (string, int, error) {
+	bounds := img.Bounds()
+	numRows, numCols := bounds.Dy(), bounds.Dx()
+	if numRows == 0 || numCols == 0 {
+		return "", 0, fmt.Errorf("image has no pixels")
+	}
+
+	buf1 := make([]float32, numRows*numCols)
+	buf2 := make([]float32, numRows*numCols)
+	for y := range numRows {
+		for x := range numCols {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			buf1[y*numCols+x] = pdqLumaFromR*float32(r>>8) + pdqLumaFromG*float32(g>>8) + pdqLumaFromB*float32(b>>8)
+		}
+	}
+
+	windowAlongRows := jaroszWindowSize(numCols)
+	windowAlongCols := jaroszWindowSize(numRows)
+	for range pdqJaroszReps {
+		for y := range numRows {
+			boxFilter(buf1[y*numCols:], buf2[y*numCols:], numCols, 1, windowAlongRows)
+		}
+		for x := range numCols {
+			boxFilter(buf2[x:], buf1[x:], numRows, numCols, windowAlongCols)
+		}
+	}
+
+	var decimated [pdqJaroszWindowDim][pdqJaroszWindowDim]float32
+	for i := range pdqJaroszWindowDim {
+		row := int((float64(i) + 0.5) * float64(numRows) / pdqJaroszWindowDim)
+		for j := range pdqJaroszWindowDim {
+			col := int((float64(j) + 0.5) * float64(numCols) / pdqJaroszWindowDim)
+			decimated[i][j] = buf1[row*numCols+col]
+		}
+	}
+
+	quality := pdqQuality(&decimated)
+	dct := pdqDct(&decimated)
+
+	values := make([]float32, 0, pdqDctDim*pdqDctDim)
+	for i := range pdqDctDim {
+		values = append(values, dct[i][:]...)
+	}
+	slices.Sort(values)
+	median := values[len(values)/2-1]
+
+	// Bit j of word i is set if that coefficient is above the median, and the words are written
+	// from the last to the first.
+	var words [pdqDctDim]uint16
+	for i := range pdqDctDim {
+		for j := range pdqDctDim {
+			if dct[i][j] > median {
+				words[i] |= 1 << j
+			}
+		}
+	}
+	var sb strings.Builder
+	for i := pdqDctDim - 1; i >= 0; i-- {
+		fmt.Fprintf(&sb, "%04x", words[i])
+	}
+
+	return sb.String(), quality, nil
+}
+
+// jaroszWindowSize is the box filter window for downsampling a dimension to 64.
+func jaroszWindowSize(dim int) int {
+	return (dim + 2*pdqJaroszWindowDim - 1) / (2 * pdqJaroszWindowDim)
+}
+
+// boxFilter is a one-dimensional box filter of n values, stride apart, with a window that shrinks at
+// the edges.
+func boxFilter(in, out []float32, n, stride, window int) {
+	half := (window + 2) / 2
+	phase1 := half - 1
+	phase2 := window - half + 1
+	phase3 := n - window
+	phase4 := half - 1
+
+	var li, ri, oi int
+	var sum float32
+	var size int
+	for range phase1 {
+		sum += in[ri]
+		size++
+		ri += stride
+	}
+	for range phase2 {
+		sum += in[ri]
+		size++
+		out[oi] = sum / float32(size)
+		ri += stride
+		oi += stride
+	}
+	for range phase3 {
+		sum += in[ri]
+		sum -= in[li]
+		out[oi] = sum / float32(size)
+		li += stride
+		ri += stride
+		oi += stride
+	}
+	for range phase4 {
+		sum -= in[li]
+		size--
+		out[oi] = sum / float32(size)
+		li += stride
+		oi += stride
+	}
+}
+
+// pdqQuality scores how much detail there is in the downsampled image, since hashes of flat images
+// match too much to be useful.
+func pdqQuality(buf *[pdqJaroszWindowDim][pdqJaroszWindowDim]float32) int {
+	gradientSum := 0
+	for i := range pdqJaroszWindowDim - 1 {
+		for j := range pdqJaroszWindowDim {
+			d := (buf[i][j] - buf[i+1][j]) * 100 / 255
+			gradientSum += int(math.Abs(float64(d)))
+		}
+	}
+	for i := range pdqJaroszWindowDim {
+		for j := range pdqJaroszWindowDim - 1 {
+			d := (buf[i][j] - buf[i][j+1]) * 100 / 255
+			gradientSum += int(math.Abs(float64(d)))
+		}
+	}
+	return min(gradientSum/90, 100)
+}
+
+// pdqDct computes D * A * Dᵀ for the 64x64 image A.
+func pdqDct(buf *[pdqJaroszWindowDim][pdqJaroszWindowDim]float32) [pdqDctDim][pdqDctDim]float32 {
+	var t [pdqDctDim][pdqJaroszWindowDim]float32
+	for i := range pdqDctDim {
+		for j := range pdqJaroszWindowDim {
+			var sum float32
+			for k := range pdqJaroszWindowDim {
+				sum += pdqDctMatrix[i][k] * buf[k][j]
+			}
+			t[i][j] = sum
+		}
+	}
+
+	var out [pdqDctDim][pdqDctDim]float32
+	for i := range pdqDctDim {
+		for j := range pdqDctDim {
+			var sum float32
+			for k := range pdqJaroszWindowDim {
+				sum += t[i][k] * pdqDctMatrix[j][k]
+			}
+			out[i][j] = sum
+		}
+	}
+	return out
+}
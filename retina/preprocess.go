@@ -0,0 +1,219 @@
+package retina
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Preprocessing steps that can be run on images before OCR. Whatever order they're asked for in,
+// they're run in the order of PreprocessSteps.
+const (
+	PreprocessUpscale   = "upscale"
+	PreprocessGrayscale = "grayscale"
+	PreprocessDeskew    = "deskew"
+	PreprocessThreshold = "threshold"
+
+	// preprocessNone asks for no steps, overriding the server's defaults.
+	preprocessNone = "none"
+)
+
+var PreprocessSteps = []string{PreprocessUpscale, PreprocessGrayscale, PreprocessDeskew, PreprocessThreshold}
+
+var ErrUnknownPreprocessStep = errors.New("unknown preprocessing step")
+
+const (
+	// upscaleTarget is the size the longest side of small images is scaled up to, at most
+	// upscaleMaxFactor times, since tesseract misses text that's only a few pixels tall.
+	upscaleTarget    = 1600
+	upscaleMaxFactor = 3.0
+
+	// thresholdSensitivity is how much darker than its neighborhood a pixel has to be to be
+	// considered text, and thresholdWindowDivisor the size of the neighborhood relative to the
+	// image's width.
+	thresholdSensitivity   = 0.15
+	thresholdWindowDivisor = 16
+
+	// deskewMaxDegrees is the furthest an image is rotated to straighten it.
+	deskewMaxDegrees = 10.0
+	deskewStep       = 0.5
+)
+
+// parsePreprocessSteps parses comma separated preprocessing steps, returning the server's defaults
+// if there are none, and no steps for "none".
+func (r *Retina) parsePreprocessSteps(steps string) ([]string, error) {
+	if strings.TrimSpace(steps) == "" {
+		return r.ocrArgs.Preprocess, nil
+	}
+
+	var parsed []string
+	for _, step := range strings.Split(steps, ",") {
+		step = strings.TrimSpace(step)
+		switch {
+		case step == preprocessNone:
+			return nil, nil
+		case !slices.Contains(PreprocessSteps, step):
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPreprocessStep, step)
+		}
+		parsed = append(parsed, step)
+	}
+	return parsed, nil
+}
+
+// prepareForOCR runs the preprocessing steps on the image and returns it as a PNG, or just makes
+// sure it's in a format tesseract reads if there are no steps.
+func (r *Retina) prepareForOCR(b []byte, steps []string) ([]byte, error) {
+	if err := r.checkImageSize(b); err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return toToolFormat(b)
+	}
+
+	img, err := decodeImage(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range PreprocessSteps {
+		if !slices.Contains(steps, step) {
+			continue
+		}
+		switch step {
+		case PreprocessUpscale:
+			img = upscale(img)
+		case PreprocessGrayscale:
+			img = grayscale(img)
+		case PreprocessDeskew:
+			img = deskew(grayscale(img))
+		case PreprocessThreshold:
+			img = threshold(grayscale(img))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode preprocessed image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// upscale scales small images up so that their longest side is upscaleTarget.
+func upscale(img image.Image) image.Image {
+	b := img.Bounds()
+	longest := max(b.Dx(), b.Dy())
+	if longest == 0 || longest >= upscaleTarget {
+		return img
+	}
+
+	factor := min(float64(upscaleTarget)/float64(longest), upscaleMaxFactor)
+	dst := image.NewRGBA(image.Rect(0, 0, int(float64(b.Dx())*factor), int(float64(b.Dy())*factor)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func grayscale(img image.Image) *image.Gray {
+	if g, ok := img.(*image.Gray); ok {
+		return g
+	}
+	b := img.Bounds()
+	g := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(g, g.Bounds(), img, b.Min, draw.Src)
+	return g
+}
+
+// threshold binarizes the image by comparing each pixel to the mean of its neighborhood, which
+// copes with text on gradients and busy backgrounds better than a global threshold.
+func threshold(g *image.Gray) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	half := max(w/thresholdWindowDivisor, 2) / 2
+
+	// integral[y+1][x+1] is the sum of the pixels above and to the left of (x, y), inclusive.
+	integral := make([]int64, (w+1)*(h+1))
+	for y := range h {
+		var rowSum int64
+		for x := range w {
+			rowSum += int64(g.Pix[y*g.Stride+x])
+			integral[(y+1)*(w+1)+x+1] = integral[y*(w+1)+x+1] + rowSum
+		}
+	}
+
+	out := image.NewGray(g.Rect)
+	for y := range h {
+		y0, y1 := max(y-half, 0), min(y+half, h-1)
+		for x := range w {
+			x0, x1 := max(x-half, 0), min(x+half, w-1)
+			count := int64((x1 - x0 + 1) * (y1 - y0 + 1))
+			sum := integral[(y1+1)*(w+1)+x1+1] - integral[y0*(w+1)+x1+1] - integral[(y1+1)*(w+1)+x0] + integral[y0*(w+1)+x0]
+
+			v := color.Gray{Y: 255}
+			if float64(int64(g.Pix[y*g.Stride+x])*count) <= float64(sum)*(1-thresholdSensitivity) {
+				v.Y = 0
+			}
+			out.Pix[y*out.Stride+x] = v.Y
+		}
+	}
+	return out
+}
+
+// deskew rotates the image by the angle, within deskewMaxDegrees, that lines its dark pixels up
+// into the sharpest rows, which is the angle that straightens lines of text.
+func deskew(g *image.Gray) image.Image {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+
+	// Every other pixel is plenty to find the angle by.
+	var dark []image.Point
+	for y := 0; y < h; y += 2 {
+		for x := 0; x < w; x += 2 {
+			if g.Pix[y*g.Stride+x] < 128 {
+				dark = append(dark, image.Point{X: x, Y: y})
+			}
+		}
+	}
+	if len(dark) == 0 {
+		return g
+	}
+
+	bestAngle, bestScore := 0.0, -1.0
+	rows := make([]float64, 2*(w+h))
+	for angle := -deskewMaxDegrees; angle <= deskewMaxDegrees; angle += deskewStep {
+		sin, cos := math.Sincos(angle * math.Pi / 180)
+		clear(rows)
+		for _, p := range dark {
+			row := int(float64(p.Y)*cos-float64(p.X)*sin) + w
+			if row >= 0 && row < len(rows) {
+				rows[row]++
+			}
+		}
+		var score float64
+		for i := 1; i < len(rows); i++ {
+			d := rows[i] - rows[i-1]
+			score += d * d
+		}
+		if score > bestScore {
+			bestAngle, bestScore = angle, score
+		}
+	}
+	if bestAngle == 0 {
+		return g
+	}
+
+	// Rotate about the center by the angle, filling the corners with white.
+	sin, cos := math.Sincos(bestAngle * math.Pi / 180)
+	cx, cy := float64(w)/2, float64(h)/2
+	out := image.NewGray(g.Rect)
+	draw.Draw(out, out.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.BiLinear.Transform(out, [6]float64{
+		cos, sin, cx - cos*cx - sin*cy,
+		-sin, cos, cy + sin*cx - cos*cy,
+	}, g, g.Bounds(), draw.Over, nil)
+	return out
+}
@@ -1,17 +1,22 @@
 package retina
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	_ "net/http/pprof"
 
+	"github.com/bluesky-social/indigo/atproto/identity"
 	"github.com/bluesky-social/indigo/util"
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
@@ -32,7 +37,41 @@ type Retina struct {
 	logger            *slog.Logger
 	downloadSemaphore *semaphore.Weighted
 	ocrSemaphore      *semaphore.Weighted
+	videoSemaphore    *semaphore.Weighted
 	pdqPath           string
+	pdqExec           bool
+	ocrArgs           *OCRArgs
+	videoArgs         *VideoArgs
+	// tesseract runs OCR in process, if enabled.
+	tesseract *tesseractPool
+	// apiKeys are the keys the API accepts, or empty if it doesn't check.
+	apiKeys []APIKey
+	// grpcAddr is where the gRPC API listens, if it's enabled.
+	grpcAddr string
+	// installedLanguages are the OCR languages requests can ask for, or nil if they're unknown.
+	installedLanguages map[string]bool
+	// defaultCdnHost is where images are fetched from, unless a request picks one of the
+	// allowedCdnHosts.
+	defaultCdnHost  string
+	allowedCdnHosts []string
+	// allowedCallbackHosts are the hosts job callbacks can be sent to, and callbackClient sends them
+	// without following redirects, so that a callback can't be bounced anywhere else.
+	allowedCallbackHosts []string
+	callbackClient       *http.Client
+	// directory resolves the PDS that original blobs are fetched from.
+	directory identity.Directory
+	// classifier runs the classifier model, if one is configured.
+	classifier     *onnxClassifier
+	classifierArgs *ClassifierArgs
+	// cache keeps OCR and PDQ results by image CID, if enabled.
+	cache  *resultCache
+	limits *LimitArgs
+
+	jobs *jobStore
+	// jobsCtx is cancelled to stop the jobs that are still running on shutdown.
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+	jobsWg     sync.WaitGroup
 }
 
 type Args struct {
@@ -42,6 +81,24 @@ type Args struct {
 	Logger                *slog.Logger
 	MaxConcurrentOCRExecs int64
 	PdqPath               string
+	// PdqExec hashes images by exec'ing the pdq-photo-hasher at PdqPath instead of in process.
+	PdqExec bool
+	OCR     OCRArgs
+	Video   VideoArgs
+	// APIKeys are the keys the API accepts as bearer tokens. Without any, the API is open.
+	APIKeys []APIKey
+	// GRPCListenAddr serves the API over gRPC as well, if set.
+	GRPCListenAddr string
+	Cache          CacheArgs
+	// CdnHost is the CDN images are fetched from, DefaultCdnHost if unset.
+	CdnHost string
+	// AllowedCdnHosts are the other CDN hosts requests can ask for images to be fetched from.
+	AllowedCdnHosts []string
+	// AllowedCallbackHosts are the hosts, with their port if it isn't the default, that jobs can
+	// send their results to. Jobs can't ask for callbacks without any.
+	AllowedCallbackHosts []string
+	Classifier           ClassifierArgs
+	Limits               LimitArgs
 }
 
 func New(args *Args) (*Retina, error) {
@@ -59,6 +116,13 @@ func New(args *Args) (*Retina, error) {
 	client := util.RobustHTTPClient()
 	client.Timeout = 5 * time.Second
 
+	callbackClient := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
 	e := echo.New()
 
 	e.Use(middleware.Recover())
@@ -95,7 +159,61 @@ func New(args *Args) (*Retina, error) {
 	downloadSem := semaphore.NewWeighted(args.MaxConcurrentOCRExecs * 4)
 	ocrSem := semaphore.NewWeighted(args.MaxConcurrentOCRExecs)
 
-	return &Retina{
+	if args.Video.FfmpegPath == "" {
+		args.Video.FfmpegPath = "ffmpeg"
+	}
+	if args.Video.FrameInterval <= 0 {
+		args.Video.FrameInterval = time.Second
+	}
+	if args.Video.MaxFrames <= 0 {
+		args.Video.MaxFrames = 60
+	}
+	if args.Video.MaxConcurrent <= 0 {
+		args.Video.MaxConcurrent = 2
+	}
+	videoSem := semaphore.NewWeighted(args.Video.MaxConcurrent)
+
+	args.Limits.setDefaults()
+
+	var tesseract *tesseractPool
+	if args.OCR.InProcess {
+		var err error
+		tesseract, err = newTesseractPool(&args.OCR, args.MaxConcurrentOCRExecs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up in process ocr: %w", err)
+		}
+	}
+
+	defaultCdnHost, err := parseCdnHost(cmp.Or(args.CdnHost, DefaultCdnHost))
+	if err != nil {
+		return nil, err
+	}
+	var allowedCdnHosts []string
+	for _, host := range args.AllowedCdnHosts {
+		host, err := parseCdnHost(host)
+		if err != nil {
+			return nil, err
+		}
+		allowedCdnHosts = append(allowedCdnHosts, host)
+	}
+
+	var classifier *onnxClassifier
+	if args.Classifier.ModelPath != "" {
+		if err := args.Classifier.validate(); err != nil {
+			return nil, fmt.Errorf("invalid classifier: %w", err)
+		}
+		classifier, err = newOnnxClassifier(&args.Classifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up classifier: %w", err)
+		}
+	}
+
+	cache, err := newResultCache(&args.Cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up result cache: %w", err)
+	}
+
+	r := &Retina{
 		httpd:             httpd,
 		metricsHttpd:      metricsHttpd,
 		client:            client,
@@ -103,8 +221,50 @@ func New(args *Args) (*Retina, error) {
 		logger:            args.Logger,
 		downloadSemaphore: downloadSem,
 		ocrSemaphore:      ocrSem,
+		videoSemaphore:    videoSem,
 		pdqPath:           args.PdqPath,
-	}, nil
+		pdqExec:           args.PdqExec,
+		ocrArgs:           &args.OCR,
+		videoArgs:         &args.Video,
+		tesseract:         tesseract,
+		apiKeys:           args.APIKeys,
+		grpcAddr:          args.GRPCListenAddr,
+		jobs:              newJobStore(),
+		cache:             cache,
+		limits:            &args.Limits,
+		classifier:        classifier,
+		classifierArgs:    &args.Classifier,
+		directory:         identity.DefaultDirectory(),
+		defaultCdnHost:    defaultCdnHost,
+		allowedCdnHosts:   allowedCdnHosts,
+
+		allowedCallbackHosts: args.AllowedCallbackHosts,
+		callbackClient:       callbackClient,
+	}
+	r.jobsCtx, r.cancelJobs = context.WithCancel(context.Background())
+
+	if len(args.APIKeys) == 0 {
+		args.Logger.Warn("no api keys configured, the api is open to anyone who can reach it")
+	}
+
+	for _, step := range args.OCR.Preprocess {
+		if !slices.Contains(PreprocessSteps, step) {
+			return nil, fmt.Errorf("invalid default preprocessing: %w: %s", ErrUnknownPreprocessStep, step)
+		}
+	}
+
+	// Without the installed languages, requested ones are passed to tesseract unchecked.
+	installed, err := r.listInstalledLanguages(context.Background())
+	if err != nil {
+		args.Logger.Warn("could not list installed ocr languages, requested languages won't be validated", "error", err)
+	} else {
+		r.installedLanguages = installed
+		if _, err := r.parseOCRLanguages(strings.Join(args.OCR.Languages, "+")); err != nil {
+			return nil, fmt.Errorf("invalid default ocr languages: %w", err)
+		}
+	}
+
+	return r, nil
 }
 
 func (r *Retina) Run(ctx context.Context) error {
@@ -119,6 +279,7 @@ func (r *Retina) Run(ctx context.Context) error {
 	wg := sync.WaitGroup{}
 
 	shutdownEcho := make(chan struct{})
+	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		log := r.logger.With("component", "retina_echo")
@@ -144,6 +305,17 @@ func (r *Retina) Run(ctx context.Context) error {
 		log.Info("retina api server shut down")
 	}()
 
+	grpcCtx, stopGrpc := context.WithCancel(ctx)
+	defer stopGrpc()
+	if r.grpcAddr != "" {
+		grpcServer := r.newGrpcServer()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.runGrpcServer(grpcCtx, grpcServer, r.grpcAddr)
+		}()
+	}
+
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
@@ -152,8 +324,18 @@ func (r *Retina) Run(ctx context.Context) error {
 	r.logger.Info("shutting down on signal")
 
 	close(shutdownEcho)
+	stopGrpc()
 	wg.Wait()
 
+	r.waitForJobs()
+
+	if r.tesseract != nil {
+		r.tesseract.close()
+	}
+	if r.classifier != nil {
+		r.classifier.close()
+	}
+
 	r.logger.Info("shut down successfuly")
 
 	return nil
@@ -165,8 +347,22 @@ func (r *Retina) addRoutes() {
 	})
 
 	g := r.echo.Group("/api")
+	if len(r.apiKeys) > 0 {
+		g.Use(r.apiKeyAuth())
+	}
+	g.Use(r.bodyLimit())
 	g.POST("/analyze", r.handleAnalyze)
 	g.POST("/analyze_blob", r.handleAnalyzeBlob)
 	g.POST("/hash", r.handlePdq)
 	g.POST("/hash_blob", r.handlePdqBlob)
+	g.POST("/scan", r.handleScan)
+	g.GET("/supported_types", r.handleSupportedTypes)
+	g.POST("/hash_video", r.handleVideoPdq)
+	g.POST("/batch", r.handleBatch)
+	g.POST("/metadata", r.handleMetadata)
+	g.POST("/checksum", r.handleChecksum)
+	g.POST("/qr", r.handleBarcodes)
+	g.POST("/classify", r.handleClassify)
+	g.POST("/jobs", r.handleCreateJob)
+	g.GET("/jobs/:id", r.handleGetJob)
 }
@@ -0,0 +1,157 @@
+package retina
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ScanResult is the OCR text and PDQ hash of one image.
+type ScanResult struct {
+	Text string     `json:"text"`
+	Pdq  *PdqResult `json:"pdq,omitempty"`
+	// Checksums are only set for images in the request body.
+	Checksums *Checksums `json:"checksums,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// handleScan extracts the text from and hashes an image given either as the request body, with its
+// DID and CID in the query parameters, or as a DID and CID in a JSON body, which is fetched from
+// the CDN. The image is only read or downloaded once for both.
+func (r *Retina) handleScan(e echo.Context) error {
+	req := e.Request()
+	ctx, span := tracer.Start(req.Context(), "handleScan")
+	defer span.End()
+
+	start := time.Now()
+
+	status := "error"
+	defer func() {
+		imagesProcessed.WithLabelValues(status, "scan").Inc()
+		requestTimeHist.WithLabelValues(status, "scan").Observe(float64(time.Since(start).Seconds()))
+	}()
+
+	fromBody := !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	imgReq := ImageRequest{Did: e.QueryParam("did"), Cid: e.QueryParam("cid")}
+	if fromBody {
+		if !supportedMimeType(req.Header.Get(echo.HeaderContentType)) {
+			return e.JSON(http.StatusUnsupportedMediaType, ScanResult{Error: "unsupported media type"})
+		}
+	} else if err := e.Bind(&imgReq); err != nil || imgReq.Did == "" || imgReq.Cid == "" {
+		return e.JSON(http.StatusBadRequest, ScanResult{Error: "could not bind request"})
+	}
+
+	span.SetAttributes(
+		attribute.String("did", imgReq.Did),
+		attribute.String("cid", imgReq.Cid),
+	)
+
+	langs, err := r.parseOCRLanguages(cmp.Or(imgReq.Langs, e.QueryParam("langs")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, ScanResult{Error: err.Error()})
+	}
+	steps, err := r.parsePreprocessSteps(cmp.Or(imgReq.Preprocess, e.QueryParam("preprocess")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, ScanResult{Error: err.Error()})
+	}
+	cdnHost, err := r.cdnHost(cmp.Or(imgReq.CdnHost, e.QueryParam("cdnHost")))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, ScanResult{Error: err.Error()})
+	}
+
+	var res ScanResult
+	text, textCached := r.cachedText(imgReq.Cid, langs, steps)
+	res.Text = text
+	res.Pdq, _ = r.cachedHash(imgReq.Cid)
+
+	var b []byte
+	if fromBody {
+		b, err = io.ReadAll(req.Body)
+		if err != nil {
+			return e.JSON(http.StatusInternalServerError, ScanResult{Error: fmt.Sprintf("error reading image bytes from request: %v", err)})
+		}
+		res.Checksums = GetChecksums(b)
+	}
+	if textCached && res.Pdq != nil {
+		status = "ok"
+		return e.JSON(http.StatusOK, res)
+	}
+
+	// Results of uploaded images are only cached if they're the image the CID names.
+	cacheCid := imgReq.Cid
+	if fromBody {
+		cacheCid = blobCacheCid(imgReq.Cid, b)
+	}
+
+	if !fromBody {
+		cdnUrl := makeCdnUrl(cdnHost, imgReq.Did, imgReq.Cid)
+		b, err = r.downloadImage(ctx, cdnUrl)
+		if err != nil {
+			if errors.Is(err, ErrImageNotFound) {
+				return e.JSON(http.StatusBadRequest, ScanResult{Error: "image not found"})
+			}
+			if errors.Is(err, ErrImageTooLarge) {
+				return e.JSON(http.StatusRequestEntityTooLarge, ScanResult{Error: err.Error()})
+			}
+			r.logger.Error("error downloading image", "url", cdnUrl, "error", err)
+			return e.JSON(http.StatusInternalServerError, ScanResult{Error: "could not download image"})
+		}
+	}
+
+	if !textCached {
+		tb, err := r.prepareForOCR(b, steps)
+		if err != nil {
+			if errors.Is(err, ErrImageTooLarge) {
+				return e.JSON(http.StatusRequestEntityTooLarge, ScanResult{Error: err.Error()})
+			}
+			return e.JSON(http.StatusUnsupportedMediaType, ScanResult{Error: "could not decode image"})
+		}
+		res.Text, err = r.getImageTextStream(ctx, bytes.NewReader(tb), langs)
+		if err != nil {
+			r.logger.Error("error getting text from image", "error", err)
+			return e.JSON(http.StatusInternalServerError, ScanResult{Error: "could not get text from image"})
+		}
+		r.cacheText(cacheCid, langs, steps, res.Text)
+	}
+
+	if res.Pdq == nil {
+		res.Pdq, err = r.pdqResult(ctx, b)
+		if err != nil {
+			if errors.Is(err, ErrImageTooLarge) {
+				return e.JSON(http.StatusRequestEntityTooLarge, ScanResult{Error: err.Error()})
+			}
+			return e.JSON(http.StatusInternalServerError, ScanResult{Error: err.Error()})
+		}
+		r.cacheHash(cacheCid, res.Pdq)
+	}
+
+	status = "ok"
+
+	return e.JSON(http.StatusOK, res)
+}
+
+// pdqResult hashes the image, with images whose quality is too low to hash as a result rather than
+// an error.
+func (r *Retina) pdqResult(ctx context.Context, b []byte) (*PdqResult, error) {
+	hashRes, err := r.GetImageHash(ctx, b)
+	if err != nil {
+		if errors.Is(err, ErrQualityTooLow) {
+			return &PdqResult{QualityTooLow: true}, nil
+		}
+		return nil, fmt.Errorf("error getting image hash: %w", err)
+	}
+	binary, err := strToBinary(hashRes)
+	if err != nil {
+		return nil, errors.New("unable to convert pdq hash to binary")
+	}
+	return &PdqResult{Hash: &hashRes, Binary: &binary}, nil
+}
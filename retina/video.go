@@ -0,0 +1,161 @@
+package retina
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+var ErrNoVideoFrames = errors.New("no frames could be hashed from the video")
+
+// VideoArgs configure how videos are sampled for hashing.
+type VideoArgs struct {
+	// FfmpegPath is the ffmpeg binary frames are extracted with.
+	FfmpegPath string
+	// FrameInterval is how far apart the sampled frames are.
+	FrameInterval time.Duration
+	// MaxFrames caps how many frames are hashed, so that long videos don't hold up the others.
+	MaxFrames int
+	// MaxConcurrent is how many videos can be hashed at once.
+	MaxConcurrent int64
+}
+
+type VideoFrameHash struct {
+	// Offset is roughly where in the video the frame was sampled from, in seconds.
+	Offset  float64 `json:"offset"`
+	Hash    string  `json:"hash"`
+	Quality int     `json:"quality"`
+}
+
+// GetVideoHashes samples frames from the video at the path or URL, and returns the PDQ hash of each
+// frame that's of high enough quality. Frames are always hashed in process.
+func (r *Retina) GetVideoHashes(ctx context.Context, input string) ([]VideoFrameHash, error) {
+	if err := r.videoSemaphore.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("error acquiring semaphore lock: %w", err)
+	}
+	defer r.videoSemaphore.Release(1)
+
+	start := time.Now()
+	status := "error"
+	defer func() {
+		videoHashHist.WithLabelValues(status).Observe(time.Since(start).Seconds())
+	}()
+
+	interval := r.videoArgs.FrameInterval.Seconds()
+	cmd := exec.CommandContext(ctx, r.videoArgs.FfmpegPath,
+		"-nostdin",
+		"-loglevel", "error",
+		"-i", input,
+		"-vf", "fps=1/"+strconv.FormatFloat(interval, 'f', -1, 64),
+		"-frames:v", strconv.Itoa(r.videoArgs.MaxFrames),
+		"-f", "image2pipe",
+		"-c:v", "png",
+		"pipe:1",
+	)
+	errOut := &bytes.Buffer{}
+	cmd.Stderr = errOut
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting ffmpeg: %w", err)
+	}
+
+	// ffmpeg writes the frames back to back, and the png decoder stops at the end of each one.
+	var frames []VideoFrameHash
+	br := bufio.NewReader(stdout)
+	for i := 0; ; i++ {
+		if _, err := br.Peek(1); err != nil {
+			break
+		}
+		img, err := png.Decode(br)
+		if err != nil {
+			_, _ = io.Copy(io.Discard, br)
+			_ = cmd.Wait()
+			return nil, fmt.Errorf("error decoding frame %d: %w", i, err)
+		}
+
+		hash, quality, err := pdqHashImage(img)
+		if err != nil {
+			continue
+		}
+		videoFramesHashed.Inc()
+		if quality < 50 { // recommended minimum quality of pdq hashes
+			continue
+		}
+		frames = append(frames, VideoFrameHash{
+			Offset:  float64(i) * interval,
+			Hash:    hash,
+			Quality: quality,
+		})
+	}
+
+	if err := cmd.Wait(); err != nil {
+		r.logger.Error("error running ffmpeg", "error", err, "stderr", errOut.String())
+		return nil, err
+	}
+	if len(frames) == 0 {
+		status = "no_frames"
+		return nil, ErrNoVideoFrames
+	}
+
+	status = "ok"
+	return frames, nil
+}
+
+// videoHash is the video level hash of its frames: each bit is set if it's set in most of them, so
+// that it stays close to the hashes of the video's typical frames.
+func videoHash(frames []VideoFrameHash) (string, error) {
+	counts := make([]int, 256)
+	for _, f := range frames {
+		b, err := strToBinary(f.Hash)
+		if err != nil {
+			return "", err
+		}
+		for i, c := range b {
+			if c == '1' {
+				counts[i]++
+			}
+		}
+	}
+
+	out := make([]byte, 32)
+	for i, n := range counts {
+		if 2*n > len(frames) {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return fmt.Sprintf("%x", out), nil
+}
+
+// saveVideo writes the video to a temp file, since mp4s that have their index at the end can't be
+// read by ffmpeg from a pipe.
+func saveVideo(b []byte) (string, error) {
+	file, err := os.CreateTemp("", "*.video")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(b); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+func makeVideoPlaylistUrl(did, cid string) string {
+	return fmt.Sprintf("https://video.bsky.app/watch/%s/%s/playlist.m3u8", url.PathEscape(did), url.PathEscape(cid))
+}